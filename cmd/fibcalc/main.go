@@ -4,10 +4,12 @@ package main
 
 import (
 	"context"
+	"errors"
 	"io"
 	"os"
 
 	"github.com/agbru/fibcalc/internal/app"
+	apperrors "github.com/agbru/fibcalc/internal/errors"
 )
 
 // exitVersion is the sentinel exit code returned when --version is handled.
@@ -23,20 +25,27 @@ func main() {
 }
 
 // run contains the core logic extracted from main for testability.
-// It returns an exit code: 0 for success, positive for errors,
-// or exitVersion (-1) when --version was handled (no os.Exit needed).
+// It returns an exit code: 0 for success, positive for errors (4 for
+// invalid flags or configuration), or exitVersion (-1) when --version was
+// handled (no os.Exit needed).
 func run(args []string, stdout, stderr io.Writer) int {
 	if app.HasVersionFlag(args[1:]) {
 		app.PrintVersion(stdout)
 		return exitVersion
 	}
+	if code, ok := app.RunCommand(args, stdout, stderr); ok {
+		return code
+	}
 
 	application, err := app.New(args, stderr)
 	if err != nil {
 		if app.IsHelpError(err) {
 			return 0
 		}
-		return 1
+		if errors.As(err, new(apperrors.ConfigError)) {
+			return apperrors.ExitErrorConfig
+		}
+		return apperrors.ExitErrorGeneric
 	}
 
 	return application.Run(context.Background(), stdout)
@@ -2,12 +2,20 @@ package main
 
 import (
 	"bytes"
+	"os"
 	"os/exec"
 	"runtime"
 	"strings"
 	"testing"
 )
 
+// TestMain keeps the runs of these tests out of the user's history
+// database.
+func TestMain(m *testing.M) {
+	os.Setenv("FIBCALC_HISTORY", "false")
+	os.Exit(m.Run())
+}
+
 // --- Unit tests calling run() directly (instrumented for coverage) ---
 
 func TestRun_Version(t *testing.T) {
@@ -82,8 +90,8 @@ func TestRun_InvalidFlag(t *testing.T) {
 	var stdout, stderr bytes.Buffer
 	code := run([]string{"fibcalc", "--invalid-flag-xyz"}, &stdout, &stderr)
 
-	if code != 1 {
-		t.Errorf("Expected exit code 1, got %d", code)
+	if code != 4 {
+		t.Errorf("Expected exit code 4, got %d", code)
 	}
 }
 
@@ -232,11 +240,11 @@ func TestMain_ExitCodes(t *testing.T) {
 		}
 	})
 
-	t.Run("invalid flag exits 1", func(t *testing.T) {
+	t.Run("invalid flag exits 4", func(t *testing.T) {
 		t.Parallel()
 		_, code := runBinary(t, bin, "--invalid-flag-xyz")
-		if code != 1 {
-			t.Errorf("Expected exit code 1, got %d", code)
+		if code != 4 {
+			t.Errorf("Expected exit code 4, got %d", code)
 		}
 	})
 
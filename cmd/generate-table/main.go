@@ -0,0 +1,54 @@
+// Package main provides a standalone tool that generates the table of
+// F(0)..F(MaxTableN) embedded in the fibonacci package (fibtable.bin).
+//
+// The format is the magic "FIBT", the uvarint count of values, then for
+// each value the uvarint length of its big-endian magnitude followed by the
+// magnitude itself.
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// tableMagic starts the table file.
+const tableMagic = "FIBT"
+
+// maxN is the index of the last value in the table. It must match
+// fibonacci.MaxTableN.
+const maxN = 1000
+
+// main writes the table to the output file.
+func main() {
+	out := flag.String("out", "internal/fibonacci/fibtable.bin", "Output file for the table")
+	flag.Parse()
+
+	data := encodeTable(maxN)
+	if err := os.WriteFile(*out, data, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing table: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Successfully generated F(0)..F(%d) (%d bytes) at %s\n", maxN, len(data), *out)
+}
+
+// encodeTable returns the encoded table of F(0)..F(n), computed by
+// iterative addition.
+func encodeTable(n uint64) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(tableMagic)
+	buf.Write(binary.AppendUvarint(nil, n+1))
+
+	a, b := big.NewInt(0), big.NewInt(1)
+	for i := uint64(0); i <= n; i++ {
+		mag := a.Bytes()
+		buf.Write(binary.AppendUvarint(nil, uint64(len(mag))))
+		buf.Write(mag)
+		a.Add(a, b)
+		a, b = b, a
+	}
+	return buf.Bytes()
+}
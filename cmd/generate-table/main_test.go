@@ -0,0 +1,29 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestEmbeddedTableIsUpToDate verifies that the table embedded in the
+// fibonacci package is the one this tool generates.
+func TestEmbeddedTableIsUpToDate(t *testing.T) {
+	embedded, err := os.ReadFile(filepath.Join("..", "..", "internal", "fibonacci", "fibtable.bin"))
+	if err != nil {
+		t.Fatalf("cannot read the embedded table: %v", err)
+	}
+	if !bytes.Equal(embedded, encodeTable(maxN)) {
+		t.Error("internal/fibonacci/fibtable.bin is stale; run 'go generate ./internal/fibonacci'")
+	}
+}
+
+// TestEncodeTable checks the encoding of a short table.
+func TestEncodeTable(t *testing.T) {
+	// F(0..3) = 0, 1, 1, 2
+	want := []byte("FIBT\x04\x00\x01\x01\x01\x01\x01\x02")
+	if got := encodeTable(3); !bytes.Equal(got, want) {
+		t.Errorf("encodeTable(3) = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,7 @@
+// Package fib is the one-call API of fibcalc for programs that just want
+// F(n): N picks the algorithm, the thresholds and the options the command
+// line would, and returns the number; WithProgress follows a long
+// calculation through a callback. The calculators, their options and
+// the orchestration of several of them stay in the internal packages
+// behind the fibcalc command.
+package fib
@@ -0,0 +1,36 @@
+package fib_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/agbru/fibcalc/fib"
+)
+
+// ExampleN computes a Fibonacci number with the defaults of this machine.
+func ExampleN() {
+	f, _, err := fib.N(context.Background(), 90)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Println(f)
+	// Output:
+	// 2880067194370816120
+}
+
+// ExampleWithProgress reports the progress of a calculation.
+func ExampleWithProgress() {
+	var last fib.Progress
+	f, _, err := fib.N(context.Background(), 1_000_000, fib.WithProgress(func(p fib.Progress) {
+		// Print p.Fraction to show a progress bar; reports are rate limited.
+		last = p
+	}))
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Printf("F(1,000,000) has %d bits; done: %.0f%%\n", f.BitLen(), 100*last.Fraction)
+	// Output:
+	// F(1,000,000) has 694241 bits; done: 100%
+}
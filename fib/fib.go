@@ -0,0 +1,114 @@
+package fib
+
+import (
+	"context"
+	"math/big"
+	"runtime"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/calibration"
+	"github.com/agbru/fibcalc/internal/config"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+)
+
+// Stats describes how N computed its result.
+type Stats struct {
+	// Algorithm is the name of the calculator chosen, such as "fast".
+	Algorithm string
+	// Rationale explains the choice in one line.
+	Rationale string
+	// Duration is the time the calculation took.
+	Duration time.Duration
+	// Calibrated reports whether the thresholds come from the calibration
+	// profile written by `fibcalc --calibrate`, rather than from estimates
+	// for the hardware.
+	Calibrated bool
+	// ParallelThreshold, FFTThreshold and StrassenThreshold are the
+	// thresholds used, in bits.
+	ParallelThreshold int
+	FFTThreshold      int
+	StrassenThreshold int
+}
+
+// observable is implemented by the calculators of the factory, which
+// report progress to observers.
+type observable interface {
+	CalculateWithObservers(ctx context.Context, subject *fibonacci.ProgressSubject, calcIndex int, n uint64, opts fibonacci.Options) (*big.Int, error)
+}
+
+// N computes the n-th Fibonacci number, F(0) = 0, F(1) = 1.
+//
+// The thresholds come from the calibration profile of this machine
+// (~/.fibcalc_calibration.json) when one is valid, else from estimates for
+// the hardware, and the algorithm is the one the cost model expects to be
+// fastest, as with `fibcalc --algo auto`. Diagnostics, such as the task
+// distribution and the calibration trials, go at debug level to the zerolog
+// logger attached to ctx with zerolog.Logger.WithContext, if any. N is safe
+// for concurrent use.
+//
+// Parameters:
+//   - ctx: Cancels the calculation.
+//   - n: The index of the Fibonacci number.
+//   - opts: Options, such as WithProgress.
+//
+// Returns:
+//   - *big.Int: F(n).
+//   - Stats: The algorithm, thresholds and duration.
+//   - error: The context's error if it ends the calculation first.
+func N(ctx context.Context, n uint64, opts ...Option) (*big.Int, Stats, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	var stats Stats
+	profile, loaded := calibration.LoadOrCreateProfile("")
+	if loaded {
+		stats.Calibrated = true
+		stats.ParallelThreshold = profile.OptimalParallelThreshold
+		stats.FFTThreshold = profile.OptimalFFTThreshold
+		stats.StrassenThreshold = profile.OptimalStrassenThreshold
+	} else {
+		profile = nil // a fresh profile, not a calibration
+		stats.ParallelThreshold = config.EstimateOptimalParallelThreshold()
+		stats.FFTThreshold = config.EstimateOptimalFFTThreshold()
+		stats.StrassenThreshold = config.EstimateOptimalStrassenThreshold()
+	}
+
+	factory := fibonacci.NewDefaultFactory()
+	// The profile is read once: the crossover comes from the one loaded
+	crossover, _ := calibration.ProfileFFTCrossover(profile)
+	model := fibonacci.CostModel{
+		FFTCrossoverBits:  crossover,
+		FFTThreshold:      stats.FFTThreshold,
+		ParallelThreshold: stats.ParallelThreshold,
+		StrassenThreshold: stats.StrassenThreshold,
+		NumCPU:            runtime.NumCPU(),
+	}
+	sel := model.Select(n, factory.List())
+	stats.Algorithm, stats.Rationale = sel.Name, sel.Rationale
+	calc, err := factory.Get(sel.Name)
+	if err != nil {
+		return nil, stats, err
+	}
+
+	calcOpts := fibonacci.Options{
+		ParallelThreshold: stats.ParallelThreshold,
+		FFTThreshold:      stats.FFTThreshold,
+		StrassenThreshold: stats.StrassenThreshold,
+	}
+	start := time.Now()
+	var result *big.Int
+	if c, ok := calc.(observable); ok && o.progress != nil {
+		// The calculator rate limits the updates to its observers
+		subject := fibonacci.NewProgressSubject()
+		subject.Register(progressObserver{fn: o.progress, start: start})
+		result, err = c.CalculateWithObservers(ctx, subject, 0, n, calcOpts)
+	} else {
+		result, err = calc.Calculate(ctx, nil, 0, n, calcOpts)
+	}
+	stats.Duration = time.Since(start)
+	if err != nil {
+		return nil, stats, err
+	}
+	return result, stats, nil
+}
@@ -0,0 +1,85 @@
+package fib
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+)
+
+func TestN(t *testing.T) {
+	t.Parallel()
+	f100, _ := new(big.Int).SetString("354224848179261915075", 10)
+	tests := []struct {
+		n    uint64
+		want *big.Int
+	}{
+		{0, big.NewInt(0)},
+		{1, big.NewInt(1)},
+		{2, big.NewInt(1)},
+		{10, big.NewInt(55)},
+		{100, f100},
+	}
+	for _, tt := range tests {
+		got, stats, err := N(context.Background(), tt.n)
+		if err != nil {
+			t.Fatalf("N(%d) error: %v", tt.n, err)
+		}
+		if got.Cmp(tt.want) != 0 {
+			t.Errorf("N(%d) = %s, want %s", tt.n, got, tt.want)
+		}
+		if stats.Algorithm == "" || stats.Rationale == "" {
+			t.Errorf("N(%d) stats lack the algorithm: %+v", tt.n, stats)
+		}
+	}
+
+	// A large index agrees with the identity F(2k) = F(k)(2F(k+1) - F(k))
+	const k = 500_000
+	fk, _, err := N(context.Background(), k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fk1, _, _ := N(context.Background(), k+1)
+	f2k, stats, _ := N(context.Background(), 2*k)
+	want := new(big.Int).Lsh(fk1, 1)
+	want.Sub(want, fk).Mul(want, fk)
+	if f2k.Cmp(want) != 0 {
+		t.Errorf("N(%d) with %s disagrees with the doubling identity", 2*k, stats.Algorithm)
+	}
+}
+
+func TestNCanceled(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := N(ctx, 10_000_000); !errors.Is(err, context.Canceled) {
+		t.Errorf("N() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestNWithProgress(t *testing.T) {
+	t.Parallel()
+	for _, n := range []uint64{10, 2_000_000} {
+		var mu sync.Mutex
+		var reports []Progress
+		_, _, err := N(context.Background(), n, WithProgress(func(p Progress) {
+			mu.Lock()
+			defer mu.Unlock()
+			reports = append(reports, p)
+		}))
+		if err != nil {
+			t.Fatalf("N(%d) error: %v", n, err)
+		}
+		mu.Lock()
+		if len(reports) == 0 || reports[len(reports)-1].Fraction != 1 {
+			t.Errorf("N(%d) reports = %+v, want them to end with 1", n, reports)
+		}
+		for i := 1; i < len(reports); i++ {
+			if reports[i].Fraction < reports[i-1].Fraction || reports[i].Elapsed < reports[i-1].Elapsed {
+				t.Errorf("N(%d) report %d = %+v went back from %+v", n, i, reports[i], reports[i-1])
+			}
+		}
+		mu.Unlock()
+	}
+}
@@ -0,0 +1,49 @@
+package fib
+
+import (
+	"time"
+)
+
+// Progress is a progress report of N.
+type Progress struct {
+	// Fraction is the part of the calculation done, from 0 to 1.
+	Fraction float64
+	// Elapsed is the time since the calculation started.
+	Elapsed time.Duration
+}
+
+// Option configures N.
+type Option func(*options)
+
+// options are the settings of a call to N.
+type options struct {
+	progress func(Progress)
+}
+
+// WithProgress makes N report its progress to fn. Reports are rate limited
+// to about ten a second: fast steps are coalesced, and long steps are
+// interpolated so that the fraction keeps moving. Fractions never decrease,
+// and a successful calculation ends with a report of 1. fn is called from
+// the goroutines of the calculation, one call at a time, and should return
+// quickly.
+//
+// Parameters:
+//   - fn: The function receiving the reports.
+//
+// Returns:
+//   - Option: The option for N.
+func WithProgress(fn func(Progress)) Option {
+	return func(o *options) { o.progress = fn }
+}
+
+// progressObserver forwards the updates of a calculation to a WithProgress
+// function, adding the elapsed time.
+type progressObserver struct {
+	fn    func(Progress)
+	start time.Time
+}
+
+// Update implements fibonacci.ProgressObserver.
+func (o progressObserver) Update(_ int, fraction float64) {
+	o.fn(Progress{Fraction: min(fraction, 1), Elapsed: time.Since(o.start)})
+}
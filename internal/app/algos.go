@@ -0,0 +1,108 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+)
+
+// runAlgos lists the registered algorithms with the metadata each
+// calculator declares: as a table, as JSON with --json, or with --markdown
+// as the table of docs/algorithms/COMPARISON.md.
+func runAlgos(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("algos", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	asJSON := fs.Bool("json", false, "Print the algorithms as JSON.")
+	asMarkdown := fs.Bool("markdown", false, "Print the algorithms as a Markdown table, for the documentation.")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return apperrors.ExitSuccess
+		}
+		return apperrors.ExitErrorConfig
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "Error: unexpected argument %q.\n", fs.Arg(0))
+		return apperrors.ExitErrorConfig
+	}
+	if *asJSON && *asMarkdown {
+		fmt.Fprintln(stderr, "Error: --json and --markdown are mutually exclusive.")
+		return apperrors.ExitErrorConfig
+	}
+
+	infos := fibonacci.Algorithms(fibonacci.NewDefaultFactory())
+	switch {
+	case *asJSON:
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(infos); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return apperrors.ExitErrorGeneric
+		}
+	case *asMarkdown:
+		writeAlgorithmsMarkdown(stdout, infos)
+	default:
+		writeAlgorithms(stdout, infos)
+	}
+	return apperrors.ExitSuccess
+}
+
+// writeAlgorithms prints one block per algorithm: a summary line, then its
+// display name, aliases, memory profile and notes.
+func writeAlgorithms(w io.Writer, infos []fibonacci.AlgorithmInfo) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tPARALLEL\tRECOMMENDED\tCOMPLEXITY")
+	for _, info := range infos {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", info.Name, yesNo(info.Parallel), info.RangeString(), orDash(info.Complexity))
+	}
+	_ = tw.Flush()
+
+	for _, info := range infos {
+		fmt.Fprintf(w, "\n%s: %s\n", info.Name, info.DisplayName)
+		if len(info.Aliases) > 0 {
+			fmt.Fprintf(w, "  Aliases: %s\n", strings.Join(info.Aliases, ", "))
+		}
+		if info.Memory != "" {
+			fmt.Fprintf(w, "  Memory:  %s\n", info.Memory)
+		}
+		if info.Notes != "" {
+			fmt.Fprintf(w, "  %s\n", info.Notes)
+		}
+	}
+}
+
+// writeAlgorithmsMarkdown prints the algorithms as a Markdown table.
+func writeAlgorithmsMarkdown(w io.Writer, infos []fibonacci.AlgorithmInfo) {
+	fmt.Fprintln(w, "| Registry Name | Name() Output | Complexity | Memory | Parallel | Recommended | Notes |")
+	fmt.Fprintln(w, "|---------------|---------------|------------|--------|----------|-------------|-------|")
+	for _, info := range infos {
+		name := "`\"" + info.Name + "\"`"
+		for _, alias := range info.Aliases {
+			name += ", `\"" + alias + "\"`"
+		}
+		fmt.Fprintf(w, "| %s | %s | %s | %s | %s | %s | %s |\n", name, info.DisplayName,
+			orDash(info.Complexity), orDash(info.Memory), yesNo(info.Parallel), info.RangeString(), orDash(info.Notes))
+	}
+}
+
+// yesNo renders a boolean for the algorithm listings.
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// orDash returns s, or "-" if it is empty.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
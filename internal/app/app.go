@@ -2,20 +2,33 @@ package app
 
 import (
 	"context"
+	"crypto/ed25519"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
+	"math/rand/v2"
+	"os"
 	"os/signal"
+	"path/filepath"
+	"sync"
 	"syscall"
+	"time"
 
+	"github.com/agbru/fibcalc/internal/audit"
 	"github.com/agbru/fibcalc/internal/bigfft"
 	"github.com/agbru/fibcalc/internal/calibration"
 	"github.com/agbru/fibcalc/internal/cli"
 	"github.com/agbru/fibcalc/internal/config"
 	apperrors "github.com/agbru/fibcalc/internal/errors"
 	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/fsguard"
+	"github.com/agbru/fibcalc/internal/history"
+	"github.com/agbru/fibcalc/internal/metrics"
 	"github.com/agbru/fibcalc/internal/orchestration"
+	"github.com/agbru/fibcalc/internal/provenance"
+	"github.com/agbru/fibcalc/internal/push"
+	"github.com/agbru/fibcalc/internal/sysmon"
 	"github.com/agbru/fibcalc/internal/tui"
 	"github.com/agbru/fibcalc/internal/ui"
 	"github.com/rs/zerolog"
@@ -26,6 +39,35 @@ type Application struct {
 	Config    config.AppConfig
 	Factory   fibonacci.CalculatorFactory
 	ErrWriter io.Writer
+	// Args holds the command-line arguments (without the program name),
+	// recorded verbatim in the audit log.
+	Args []string
+	// Runtime is the presentation snapshot of the run (theme, console),
+	// built once by Run from Config and passed to the interfaces.
+	Runtime config.RuntimeConfig
+	// In is where answers to prompts are read from (os.Stdin if nil).
+	In io.Reader
+
+	// outcome is the result reported by the last calculation, if any.
+	outcome *orchestration.CalculationResult
+	// results are all the results of the last calculation, with their
+	// error kinds, for the audit log.
+	results []orchestration.CalculationResult
+	// fallbacks are the registered names of the algorithms of the last
+	// calculation that failed and were replaced by --fallback, in order.
+	fallbacks []string
+	// usage is the resource usage of the last calculation; nil where the
+	// platform does not report it.
+	usage *sysmon.Usage
+	// pusher sends metrics to Config.MetricsPush; nil when it is unset.
+	pusher *push.Pusher
+	// signingKey signs the result file; nil when Config.SignKey is unset.
+	signingKey ed25519.PrivateKey
+	// profileOnce guards the loading of profile, on first use.
+	profileOnce sync.Once
+	// profile is the calibration profile, nil if there is none valid for
+	// this machine.
+	profile *calibration.CalibrationProfile
 }
 
 // AppOption configures an Application during construction.
@@ -37,6 +79,10 @@ func WithFactory(f fibonacci.CalculatorFactory) AppOption {
 }
 
 // New creates a new Application instance by parsing command-line arguments.
+// It does little more than the parsing, so that small calculations run from
+// scripts start fast: the calibration profile is only read once Run
+// dispatches to a mode that calculates (see resolveThresholds), and the
+// factory only creates the calculators that are run.
 func New(args []string, errWriter io.Writer, opts ...AppOption) (*Application, error) {
 	app := &Application{ErrWriter: errWriter}
 	for _, opt := range opts {
@@ -47,7 +93,7 @@ func New(args []string, errWriter io.Writer, opts ...AppOption) (*Application, e
 	}
 
 	factory := app.Factory
-	availableAlgos := factory.List()
+	availableAlgos := fibonacci.AcceptedNames(factory)
 
 	programName := "fibcalc"
 	var cmdArgs []string
@@ -61,13 +107,27 @@ func New(args []string, errWriter io.Writer, opts ...AppOption) (*Application, e
 		return nil, err
 	}
 
-	if cfgWithProfile, loaded := calibration.LoadCachedCalibration(cfg, cfg.CalibrationProfile); loaded {
-		cfg = cfgWithProfile
-	} else {
-		cfg = config.ApplyAdaptiveThresholds(cfg)
+	if notice, deprecated := fibonacci.DeprecationNotice(factory, cfg.Algo); deprecated {
+		fmt.Fprintf(errWriter, "Warning: algorithm '%s' is deprecated: %s\n", cfg.Algo, notice)
+	}
+
+	// Fix the seed now so that it can be reported and recorded
+	for cfg.Seed == 0 {
+		cfg.Seed = rand.Int64()
+	}
+
+	// Load the signing key now so that a bad key fails before the run
+	if cfg.SignKey != "" {
+		key, err := provenance.LoadPrivateKey(cfg.SignKey)
+		if err != nil {
+			fmt.Fprintln(errWriter, "Configuration error:", err)
+			return nil, apperrors.NewConfigError("%v", err)
+		}
+		app.signingKey = key
 	}
 
 	app.Config = cfg
+	app.Args = cmdArgs
 	return app, nil
 }
 
@@ -76,24 +136,100 @@ func (a *Application) Run(ctx context.Context, out io.Writer) int {
 	if a.Config.Completion != "" {
 		return a.runCompletion(out)
 	}
+	if a.Config.HelpFull {
+		return a.runFullHelp(out)
+	}
+	if a.Config.MachineSchema {
+		return a.runMachineSchema(out)
+	}
+	if a.Config.Indicators == metrics.SelectList {
+		return a.runIndicatorList(out)
+	}
 
+	if a.Config.NoWrite {
+		defer fsguard.Deny()()
+	}
+	if a.Config.MetricsPush != "" {
+		a.pusher = a.newPusher()
+	}
+	if a.Config.Telemetry {
+		a.optInTelemetry()
+	}
+
+	start := time.Now()
+	exitCode := a.dispatch(ctx, out)
+	if a.Config.AuditLog != "" {
+		a.writeAuditRecord(start, exitCode)
+	}
+	if a.Config.History {
+		a.writeHistory(start)
+	}
+	if a.pusher != nil {
+		a.pushRunMetrics()
+	}
+	if a.Config.GHASummary {
+		a.writeGHASummary()
+	}
+	if a.Config.ExecOnComplete != "" {
+		a.execOnComplete(ctx, start, exitCode)
+	}
+	a.submitTelemetry()
+	return exitCode
+}
+
+// dispatch runs the mode selected by the configuration.
+func (a *Application) dispatch(ctx context.Context, out io.Writer) int {
 	zerolog.SetGlobalLevel(zerolog.InfoLevel)
-	ui.InitTheme(false)
+	a.Runtime = config.NewRuntimeConfig(a.Config, ui.InitConsole(a.Config.ASCII))
+	a.Runtime.Install()
+	console := a.Runtime.Console()
+	out = ui.NewSafeWriter(out)
+	a.ErrWriter = ui.NewSafeWriter(a.ErrWriter)
+	// With --machine, stdout only receives the result document
+	stdout := out
+	if a.Config.Machine {
+		out = a.ErrWriter
+	}
+
+	a.applyPriority()
 
 	// Initialize global concurrency limits
 	fibonacci.InitTaskSemaphore(a.Config.MaxGoroutines)
 	bigfft.InitFFTSemaphore(a.Config.MaxGoroutines)
+	if a.Config.Paranoid {
+		bigfft.SetParanoid(true)
+	}
 
 	if a.Config.Calibrate {
 		return a.runCalibration(ctx, out)
 	}
 
+	a.Config = a.resolveThresholds()
 	a.Config = a.runAutoCalibrationIfEnabled(ctx, out)
 
+	if a.Config.Algo == config.AutoAlgo {
+		a.resolveAutoAlgorithm(out)
+	}
+
 	if a.Config.TUI {
-		return a.runTUI(ctx, out)
+		if console.VT {
+			return a.runTUI(ctx, out)
+		}
+		fmt.Fprintln(a.ErrWriter, "Warning: this console cannot display the TUI (no ANSI support); using CLI output.")
 	}
 
+	if a.Config.Explain {
+		return a.runExplain(ctx, out)
+	}
+	if a.Config.Watch {
+		return a.runWatch(ctx, out)
+	}
+	if a.Config.NSeries != "" {
+		return a.runSeries(ctx, out, stdout)
+	}
+	if a.Config.Machine {
+		return a.runMachine(ctx, stdout)
+	}
 	return a.runCalculate(ctx, out)
 }
 
@@ -109,7 +245,36 @@ func (a *Application) runCompletion(out io.Writer) int {
 
 // runCalibration runs the full calibration mode.
 func (a *Application) runCalibration(ctx context.Context, out io.Writer) int {
-	return calibration.RunCalibration(ctx, out, a.Factory.GetAll(), cli.DisplayProgress, cli.CLIColorProvider{})
+	return calibration.RunCalibrationWithOptions(ctx, out, a.Factory.GetAll(), calibration.CalibrationOptions{
+		ProfilePath: a.Config.CalibrationProfile,
+		SaveProfile: !a.Config.ProfileReadOnly,
+		Seed:        a.Config.Seed,
+	}, cli.DisplayProgress, cli.CLIColorProvider{})
+}
+
+// calibrationProfile returns the calibration profile of --calibration-profile,
+// read on first use only.
+//
+// Returns:
+//   - *calibration.CalibrationProfile: The profile, nil if there is none
+//     valid for this machine.
+func (a *Application) calibrationProfile() *calibration.CalibrationProfile {
+	a.profileOnce.Do(func() {
+		if profile, loaded := calibration.LoadOrCreateProfile(a.Config.CalibrationProfile); loaded && profile.IsValid() {
+			a.profile = profile
+		}
+	})
+	return a.profile
+}
+
+// resolveThresholds returns the configuration with the thresholds of the
+// calibration profile or, without one, the hardware estimates for those
+// left at 0.
+func (a *Application) resolveThresholds() config.AppConfig {
+	if profile := a.calibrationProfile(); profile != nil {
+		return calibration.ApplyProfile(a.Config, profile)
+	}
+	return config.ApplyAdaptiveThresholds(a.Config)
 }
 
 // runAutoCalibrationIfEnabled runs auto-calibration if enabled.
@@ -123,14 +288,86 @@ func (a *Application) runAutoCalibrationIfEnabled(ctx context.Context, out io.Wr
 }
 
 // runTUI launches the interactive TUI dashboard.
-func (a *Application) runTUI(ctx context.Context, _ io.Writer) int {
-	ctx, cancelTimeout := context.WithTimeout(ctx, a.Config.Timeout)
+func (a *Application) runTUI(ctx context.Context, out io.Writer) int {
+	a.applyTimeoutFactor(ctx, out)
+	// The dashboard may offer to extend the timeout
+	ctx, cancelTimeout := orchestration.WithExtendableTimeout(ctx, a.Config.Timeout)
 	defer cancelTimeout()
 	ctx, stopSignals := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
 	defer stopSignals()
 
+	// The dashboard replaces the CLI output, which --log-file keeps
+	var log io.Writer
+	if a.Config.LogFile != "" {
+		f, err := fsguard.OpenFile(filepath.Clean(a.Config.LogFile), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+		if err != nil {
+			fmt.Fprintf(a.ErrWriter, "Error opening log file: %v\n", err)
+			return apperrors.ExitErrorConfig
+		}
+		defer f.Close()
+		log = f
+	}
+
 	calculatorsToRun := orchestration.GetCalculatorsToRun(a.Config.Algo, a.Factory)
-	return tui.Run(ctx, calculatorsToRun, a.Config, Version)
+	return tui.Run(ctx, calculatorsToRun, a.Config, a.Runtime, Version, log)
+}
+
+// writeAuditRecord appends a record of this invocation to the audit log.
+// Failures are reported on ErrWriter but never change the exit code.
+func (a *Application) writeAuditRecord(start time.Time, exitCode int) {
+	rec := audit.Record{
+		Timestamp: start.UTC(),
+		Version:   Version,
+		Args:      a.Args,
+		Config:    a.Config,
+		ExitCode:  exitCode,
+	}
+	rec.SetDuration(time.Since(start))
+	if a.outcome != nil {
+		rec.Algorithm = a.outcome.Name
+		rec.SetResult(a.outcome.Result)
+		rec.SetDuration(a.outcome.Duration)
+	}
+	for _, res := range a.results {
+		rec.AddResult(res.Name, res.Duration, res.Result, res.ErrorKind(), res.Err)
+	}
+	logger := audit.NewLogger(a.Config.AuditLog, audit.DefaultMaxSize, audit.DefaultMaxBackups)
+	if err := logger.Append(rec); err != nil {
+		fmt.Fprintf(a.ErrWriter, "Warning: %v\n", err)
+	}
+}
+
+// writeHistory records the results of the last calculation in the history
+// database, one entry per algorithm. Failures are reported on ErrWriter
+// but never change the exit code.
+func (a *Application) writeHistory(start time.Time) {
+	if len(a.results) == 0 {
+		return
+	}
+	// Results carry display names; the history stores registered names
+	keys := a.algorithmKeys()
+	entries := make([]history.Entry, 0, len(a.results))
+	for _, res := range a.results {
+		e := history.Entry{
+			Timestamp:  start.UTC(),
+			Version:    Version,
+			N:          a.Config.N,
+			Algorithm:  keys[res.Name],
+			Name:       res.Name,
+			DurationNs: res.Duration.Nanoseconds(),
+			ErrorKind:  res.ErrorKind(),
+		}
+		if e.Algorithm == "" {
+			e.Algorithm = res.Name
+		}
+		if res.Result != nil {
+			e.ResultBits = res.Result.BitLen()
+		}
+		entries = append(entries, e)
+	}
+	if err := history.Append(historyPath(a.Config.HistoryFile), entries); err != nil {
+		fmt.Fprintf(a.ErrWriter, "Warning: %v\n", err)
+	}
 }
 
 // IsHelpError checks if the error is a help flag error (--help was used).
@@ -5,22 +5,38 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/agbru/fibcalc/internal/audit"
 	"github.com/agbru/fibcalc/internal/calibration"
 	"github.com/agbru/fibcalc/internal/cli"
 	"github.com/agbru/fibcalc/internal/config"
 	apperrors "github.com/agbru/fibcalc/internal/errors"
 	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/format"
+	"github.com/agbru/fibcalc/internal/fsguard"
+	"github.com/agbru/fibcalc/internal/history"
 	"github.com/agbru/fibcalc/internal/orchestration"
 	"github.com/agbru/fibcalc/internal/testutil"
 )
 
+// TestMain keeps the tests that parse real command lines from recording
+// their runs in the user's history database.
+func TestMain(m *testing.M) {
+	os.Setenv(config.EnvPrefix+"HISTORY", "false")
+	os.Exit(m.Run())
+}
+
 // Helper to create a test factory with mocked calculator
 func createMockFactory(result *big.Int, err error) *fibonacci.TestFactory {
 	mockCalc := &fibonacci.MockCalculator{
@@ -306,6 +322,30 @@ func TestRunCompletion(t *testing.T) {
 	}
 }
 
+// TestRunIndicatorList tests that --indicators list prints the indicators
+// instead of calculating.
+func TestRunIndicatorList(t *testing.T) {
+	t.Parallel()
+	var outBuf bytes.Buffer
+	app := &Application{
+		Config:    config.AppConfig{Indicators: "list", N: 10},
+		Factory:   fibonacci.GlobalFactory(),
+		ErrWriter: &bytes.Buffer{},
+	}
+
+	if exitCode := app.Run(context.Background(), &outBuf); exitCode != apperrors.ExitSuccess {
+		t.Errorf("Expected exit code %d, got %d", apperrors.ExitSuccess, exitCode)
+	}
+	for _, want := range []string{"bit-throughput", "digital-root", "perf", "math"} {
+		if !strings.Contains(outBuf.String(), want) {
+			t.Errorf("Output should contain %q. Got:\n%s", want, outBuf.String())
+		}
+	}
+	if strings.Contains(outBuf.String(), "F(10)") {
+		t.Errorf("Output should not contain a result. Got:\n%s", outBuf.String())
+	}
+}
+
 // TestRunCompletionInvalid tests invalid completion shell.
 func TestRunCompletionInvalid(t *testing.T) {
 	t.Parallel()
@@ -486,6 +526,16 @@ func TestAnalyzeResultsWithOutputVariety(t *testing.T) {
 		}
 	})
 
+	t.Run("Quiet Mode With Duration", func(t *testing.T) {
+		t.Parallel()
+		var outBuf bytes.Buffer
+		outputCfg := cli.OutputConfig{Quiet: true, QuietDuration: format.DurationMilliseconds, DurationPrecision: 3}
+		app.analyzeResultsWithOutput(results, outputCfg, &outBuf)
+		if got := outBuf.String(); got != "55\t1.000\n" {
+			t.Errorf("Expected the value and the duration in ms, got %q", got)
+		}
+	})
+
 	t.Run("No Success Results", func(t *testing.T) {
 		t.Parallel()
 		var outBuf bytes.Buffer
@@ -750,6 +800,149 @@ func TestWithFactory(t *testing.T) {
 	})
 }
 
+// TestNewAlgorithmAliases verifies that factory aliases pass validation and
+// that deprecated names produce a warning.
+func TestNewAlgorithmAliases(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Alias is accepted", func(t *testing.T) {
+		t.Parallel()
+		var errBuf bytes.Buffer
+		app, err := New([]string{"fibcalc", "-n", "10", "-algo", "fd"}, &errBuf)
+		if err != nil {
+			t.Fatalf("New() returned unexpected error: %v", err)
+		}
+		calcs := orchestration.GetCalculatorsToRun(app.Config.Algo, app.Factory)
+		if len(calcs) != 1 {
+			t.Fatalf("expected 1 calculator for alias, got %d", len(calcs))
+		}
+		if errBuf.Len() != 0 {
+			t.Errorf("unexpected warning: %q", errBuf.String())
+		}
+	})
+
+	t.Run("Deprecated name warns", func(t *testing.T) {
+		t.Parallel()
+		var errBuf bytes.Buffer
+		factory := fibonacci.NewDefaultFactory()
+		factory.Deprecate("mat", "use 'matrix' instead")
+		if _, err := New([]string{"fibcalc", "-n", "10", "-algo", "mat"}, &errBuf, WithFactory(factory)); err != nil {
+			t.Fatalf("New() returned unexpected error: %v", err)
+		}
+		if !strings.Contains(errBuf.String(), "deprecated: use 'matrix' instead") {
+			t.Errorf("expected deprecation warning, got %q", errBuf.String())
+		}
+	})
+}
+
+// TestNewSeed verifies that an explicit seed is kept and that a fresh,
+// non-zero seed is chosen otherwise so it can be recorded.
+func TestNewSeed(t *testing.T) {
+	t.Parallel()
+	app, err := New([]string{"fibcalc", "-n", "10", "--seed", "99"}, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("New() returned unexpected error: %v", err)
+	}
+	if app.Config.Seed != 99 {
+		t.Errorf("Expected Seed=99, got %d", app.Config.Seed)
+	}
+
+	app, err = New([]string{"fibcalc", "-n", "10"}, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("New() returned unexpected error: %v", err)
+	}
+	if app.Config.Seed == 0 {
+		t.Error("Expected a non-zero seed to be chosen")
+	}
+}
+
+// TestRunAutoAlgorithm verifies that --algo auto runs a single calculator
+// chosen by the cost model and explains the choice.
+func TestRunAutoAlgorithm(t *testing.T) {
+	t.Parallel()
+	var outBuf bytes.Buffer
+	app := &Application{
+		Config: config.AppConfig{
+			N:                  10,
+			Algo:               config.AutoAlgo,
+			Timeout:            1 * time.Minute,
+			Threshold:          fibonacci.DefaultParallelThreshold,
+			FFTThreshold:       20000,
+			CalibrationProfile: filepath.Join(t.TempDir(), "missing.json"),
+			ShowValue:          true,
+		},
+		Factory:   createMockFactory(big.NewInt(55), nil),
+		ErrWriter: &bytes.Buffer{},
+	}
+
+	if code := app.Run(context.Background(), &outBuf); code != apperrors.ExitSuccess {
+		t.Fatalf("Expected exit code %d, got %d", apperrors.ExitSuccess, code)
+	}
+	if app.Config.Algo != "fast" {
+		t.Errorf("Expected auto to resolve to 'fast' for small n, got %q", app.Config.Algo)
+	}
+	output := testutil.StripAnsiCodes(outBuf.String())
+	if !strings.Contains(output, "Auto-selected algorithm: fast") {
+		t.Errorf("Output should announce the selection. Output:\n%s", output)
+	}
+	if !strings.Contains(output, "crossover from hardware estimate") {
+		t.Errorf("Output should explain the crossover source. Output:\n%s", output)
+	}
+}
+
+// TestRunSoftRealtime verifies that --soft-realtime reports the GC pauses
+// of the run. It changes the global GC and yield settings, so it does not
+// run in parallel.
+func TestRunSoftRealtime(t *testing.T) {
+	var outBuf bytes.Buffer
+	app := &Application{
+		Config: config.AppConfig{
+			N:            100_000,
+			Algo:         "fast",
+			Timeout:      1 * time.Minute,
+			GCControl:    "auto",
+			SoftRealtime: true,
+		},
+		Factory:   fibonacci.NewDefaultFactory(),
+		ErrWriter: &bytes.Buffer{},
+	}
+	if code := app.Run(context.Background(), &outBuf); code != apperrors.ExitSuccess {
+		t.Fatalf("Expected exit code %d, got %d", apperrors.ExitSuccess, code)
+	}
+	output := testutil.StripAnsiCodes(outBuf.String())
+	if !strings.Contains(output, "Soft real-time:") || !strings.Contains(output, "(target 50ms)") {
+		t.Errorf("Output should report the GC pauses against the target. Output:\n%s", output)
+	}
+}
+
+// TestRunAutoAlgorithmRecommendedRange verifies that "auto" only chooses
+// among the calculators recommended for n, and names the others.
+func TestRunAutoAlgorithmRecommendedRange(t *testing.T) {
+	t.Parallel()
+	var outBuf bytes.Buffer
+	app := &Application{
+		Config: config.AppConfig{
+			N:                  1000,
+			Algo:               config.AutoAlgo,
+			Timeout:            1 * time.Minute,
+			CalibrationProfile: filepath.Join(t.TempDir(), "missing.json"),
+		},
+		Factory:   fibonacci.NewDefaultFactory(),
+		ErrWriter: &bytes.Buffer{},
+	}
+
+	if code := app.Run(context.Background(), &outBuf); code != apperrors.ExitSuccess {
+		t.Fatalf("Expected exit code %d, got %d", apperrors.ExitSuccess, code)
+	}
+	output := testutil.StripAnsiCodes(outBuf.String())
+	if !strings.Contains(output, "Outside their recommended range of n: fft, lowmem") {
+		t.Errorf("Output should name the calculators not recommended for n=1000. Output:\n%s", output)
+	}
+	if strings.Contains(output, "fft 1.") {
+		t.Errorf("fft should not be costed for n=1000. Output:\n%s", output)
+	}
+}
+
 // TestApplyAdaptiveThresholdsZeroValues tests that zero-value thresholds
 // trigger the adaptive estimation paths.
 func TestApplyAdaptiveThresholdsZeroValues(t *testing.T) {
@@ -1272,3 +1465,540 @@ func TestRunCalculateCalculatorError(t *testing.T) {
 		t.Error("Expected non-success exit code for calculator error")
 	}
 }
+
+// TestRunWritesHistory verifies that every algorithm's run is recorded in
+// the history database under its registered name.
+func TestRunWritesHistory(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	app := &Application{
+		Config: config.AppConfig{
+			N:           10,
+			Algo:        "all",
+			Timeout:     1 * time.Minute,
+			Quiet:       true,
+			History:     true,
+			HistoryFile: path,
+		},
+		Factory:   createMockFactory(big.NewInt(55), nil),
+		ErrWriter: &bytes.Buffer{},
+	}
+	if exitCode := app.Run(context.Background(), &bytes.Buffer{}); exitCode != apperrors.ExitSuccess {
+		t.Fatalf("Expected exit code %d, got %d", apperrors.ExitSuccess, exitCode)
+	}
+
+	entries, err := history.Load(path)
+	if err != nil {
+		t.Fatalf("Failed to load history: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 history entries, got %d", len(entries))
+	}
+	for _, e := range entries {
+		if e.N != 10 || e.ResultBits != 6 || e.Version != Version || e.Algorithm == "" {
+			t.Errorf("Unexpected history entry: %+v", e)
+		}
+	}
+}
+
+// TestRunPushesMetrics verifies that a summary of every algorithm's run is
+// pushed to --metrics-push, and that a rejected push only warns.
+func TestRunPushesMetrics(t *testing.T) {
+	t.Parallel()
+	var (
+		mu     sync.Mutex
+		bodies []string
+		status = http.StatusNoContent
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		bodies = append(bodies, string(body))
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	newApp := func(errOut *bytes.Buffer) *Application {
+		return &Application{
+			Config: config.AppConfig{
+				N:                   10,
+				Algo:                "all",
+				Timeout:             1 * time.Minute,
+				Quiet:               true,
+				MetricsPush:         srv.URL,
+				MetricsPushInterval: time.Hour,
+			},
+			Factory:   createMockFactory(big.NewInt(55), nil),
+			ErrWriter: errOut,
+		}
+	}
+
+	var errOut bytes.Buffer
+	if exitCode := newApp(&errOut).Run(context.Background(), &bytes.Buffer{}); exitCode != apperrors.ExitSuccess {
+		t.Fatalf("Expected exit code %d, got %d", apperrors.ExitSuccess, exitCode)
+	}
+	if len(bodies) != 1 || strings.Count(bodies[0], "fibcalc_run,") != 3 {
+		t.Fatalf("Expected one push of 3 run samples, got %q", bodies)
+	}
+	if !strings.Contains(bodies[0], "n=10,status=ok,version=") || !strings.Contains(bodies[0], "result_bits=6") {
+		t.Errorf("Unexpected run samples: %s", bodies[0])
+	}
+	if errOut.Len() != 0 {
+		t.Errorf("Unexpected warning: %s", errOut.String())
+	}
+
+	status = http.StatusUnauthorized
+	errOut.Reset()
+	if exitCode := newApp(&errOut).Run(context.Background(), &bytes.Buffer{}); exitCode != apperrors.ExitSuccess {
+		t.Fatalf("A failed push changed the exit code to %d", exitCode)
+	}
+	if !strings.Contains(errOut.String(), "Warning: metrics push: 401") {
+		t.Errorf("Expected a push warning, got %q", errOut.String())
+	}
+}
+
+// TestRunTelemetry verifies that --telemetry records the consent, that
+// every later run is reported until `fibcalc telemetry off`, and that the
+// reports carry no result.
+func TestRunTelemetry(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		bodies []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		bodies = append(bodies, string(body))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+	statePath := filepath.Join(t.TempDir(), "telemetry.json")
+	t.Setenv(telemetryFileEnv, statePath)
+
+	run := func(optIn bool) string {
+		var errOut bytes.Buffer
+		app := &Application{
+			Config: config.AppConfig{
+				N:                 10,
+				Algo:              "all",
+				Timeout:           1 * time.Minute,
+				Quiet:             true,
+				Telemetry:         optIn,
+				TelemetryEndpoint: srv.URL,
+				FFTThreshold:      500000,
+			},
+			Factory:   createMockFactory(big.NewInt(55), nil),
+			ErrWriter: &errOut,
+		}
+		if exitCode := app.Run(context.Background(), &bytes.Buffer{}); exitCode != apperrors.ExitSuccess {
+			t.Fatalf("Expected exit code %d, got %d", apperrors.ExitSuccess, exitCode)
+		}
+		return errOut.String()
+	}
+
+	if errOut := run(true); !strings.Contains(errOut, "Telemetry enabled") || !strings.Contains(errOut, "fibcalc telemetry off") {
+		t.Errorf("Expected the opt-in notice, got %q", errOut)
+	}
+	if errOut := run(false); errOut != "" {
+		t.Errorf("Unexpected output after the opt-in: %q", errOut)
+	}
+	if len(bodies) != 2 || strings.Count(bodies[1], `"hardware"`) != 3 || !strings.Contains(bodies[1], `"fft_threshold":500000`) {
+		t.Fatalf("Expected two reports of 3 records, got %q", bodies)
+	}
+	if strings.Contains(bodies[0], "result") {
+		t.Errorf("A report carries the result: %s", bodies[0])
+	}
+
+	var stdout bytes.Buffer
+	if code, _ := RunCommand([]string{"fibcalc", "telemetry", "status"}, &stdout, &bytes.Buffer{}); code != apperrors.ExitSuccess || !strings.Contains(stdout.String(), srv.URL) {
+		t.Errorf("telemetry status = %d with:\n%s", code, stdout.String())
+	}
+	if code, _ := RunCommand([]string{"fibcalc", "telemetry", "off"}, &bytes.Buffer{}, &bytes.Buffer{}); code != apperrors.ExitSuccess {
+		t.Fatalf("telemetry off = %d", code)
+	}
+	run(false)
+	if len(bodies) != 2 {
+		t.Errorf("A report was sent after telemetry off: %q", bodies[2:])
+	}
+	stdout.Reset()
+	RunCommand([]string{"fibcalc", "telemetry"}, &stdout, &bytes.Buffer{})
+	if !strings.Contains(stdout.String(), "disabled") {
+		t.Errorf("telemetry status after off:\n%s", stdout.String())
+	}
+	if code, _ := RunCommand([]string{"fibcalc", "telemetry", "on"}, &bytes.Buffer{}, &bytes.Buffer{}); code != apperrors.ExitErrorConfig {
+		t.Errorf("telemetry on = %d, want %d", code, apperrors.ExitErrorConfig)
+	}
+}
+
+// TestRunGHASummary verifies that --gha-summary appends a results table to
+// the GitHub Actions job summary on each run.
+func TestRunGHASummary(t *testing.T) {
+	summaryPath := filepath.Join(t.TempDir(), "step_summary.md")
+	t.Setenv(ghaSummaryEnv, summaryPath)
+
+	for range 2 {
+		app := &Application{
+			Config: config.AppConfig{
+				N:          10,
+				Algo:       "all",
+				Timeout:    1 * time.Minute,
+				Quiet:      true,
+				GHASummary: true,
+			},
+			Factory:   createMockFactory(big.NewInt(55), nil),
+			ErrWriter: &bytes.Buffer{},
+		}
+		if exitCode := app.Run(context.Background(), &bytes.Buffer{}); exitCode != apperrors.ExitSuccess {
+			t.Fatalf("Expected exit code %d, got %d", apperrors.ExitSuccess, exitCode)
+		}
+	}
+
+	data, err := os.ReadFile(summaryPath)
+	if err != nil {
+		t.Fatalf("Failed to read the job summary: %v", err)
+	}
+	if got := string(data); strings.Count(got, "### fibcalc: F(10)") != 2 || strings.Count(got, "✅ Success |") != 6 {
+		t.Errorf("Expected two tables of 3 successful runs, got:\n%s", got)
+	}
+}
+
+func TestRunExplain(t *testing.T) {
+	t.Parallel()
+	for algo, want := range map[string][]string{
+		"fast":   {"--- Fast doubling: F(10) ---", "Result: F(10) = 55"},
+		"all":    {"--- Fast doubling: F(10) ---", "--- Matrix exponentiation: F(10) ---", "Q^9"},
+		"matrix": {"--- Matrix exponentiation: F(10) ---", "Result: F(10) = 55"},
+	} {
+		var out bytes.Buffer
+		app := &Application{
+			Config: config.AppConfig{
+				N:       10,
+				Algo:    algo,
+				Timeout: 1 * time.Minute,
+				Explain: true,
+			},
+			Factory:   fibonacci.NewDefaultFactory(),
+			ErrWriter: &bytes.Buffer{},
+		}
+		if exitCode := app.Run(context.Background(), &out); exitCode != apperrors.ExitSuccess {
+			t.Fatalf("%s: exit code %d, want %d", algo, exitCode, apperrors.ExitSuccess)
+		}
+		for _, w := range want {
+			if !strings.Contains(out.String(), w) {
+				t.Errorf("%s: output does not contain %q:\n%s", algo, w, out.String())
+			}
+		}
+		if algo == "matrix" && strings.Contains(out.String(), "Fast doubling") {
+			t.Errorf("matrix: unexpected fast doubling trace:\n%s", out.String())
+		}
+	}
+}
+
+func TestRunRuntimeTrace(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "trace.out")
+	var out bytes.Buffer
+	app := &Application{
+		Config: config.AppConfig{
+			N:            1_000_000,
+			Algo:         "fast",
+			Timeout:      1 * time.Minute,
+			RuntimeTrace: path + ":1m",
+		},
+		Factory:   fibonacci.NewDefaultFactory(),
+		ErrWriter: &bytes.Buffer{},
+	}
+	if exitCode := app.Run(context.Background(), &out); exitCode != apperrors.ExitSuccess {
+		t.Fatalf("exit code %d, want %d", exitCode, apperrors.ExitSuccess)
+	}
+	// The whole run fits in the window
+	if !strings.Contains(out.String(), "written to "+path+".") || !strings.Contains(out.String(), "go tool trace "+path) {
+		t.Errorf("output does not report the trace:\n%s", out.String())
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+		t.Errorf("trace file not written: %v", err)
+	}
+}
+
+func TestRunExecOnComplete(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("the test command uses sh")
+	}
+	dir := t.TempDir()
+	hook := `printf '%s %s %s %s' "$FIB_N" "$FIB_DIGITS" "$FIB_OUTPUT_FILE" "$FIB_EXIT" > ` + filepath.Join(dir, "env")
+	output := filepath.Join(dir, "f100.txt")
+
+	tests := []struct {
+		name    string
+		factory fibonacci.CalculatorFactory
+		want    string
+	}{
+		{"success", fibonacci.NewDefaultFactory(), "100 21 " + output + " 0"},
+		{"failure", createMockFactory(nil, fmt.Errorf("boom")), fmt.Sprintf("100  %s %d", output, apperrors.ExitErrorGeneric)},
+	}
+	for _, tt := range tests {
+		errOut := &bytes.Buffer{}
+		app := &Application{
+			Config: config.AppConfig{
+				N:              100,
+				Algo:           "fast",
+				Timeout:        1 * time.Minute,
+				OutputFile:     output,
+				NoTable:        true,
+				ExecOnComplete: hook + "; echo hook ran; exit 3",
+			},
+			Factory:   tt.factory,
+			ErrWriter: errOut,
+		}
+		app.Run(context.Background(), &bytes.Buffer{})
+		got, err := os.ReadFile(filepath.Join(dir, "env"))
+		if err != nil || string(got) != tt.want {
+			t.Errorf("%s: hook environment = %q, %v, want %q", tt.name, got, err, tt.want)
+		}
+		// The hook's output and failure go to stderr
+		if !strings.Contains(errOut.String(), "hook ran") || !strings.Contains(errOut.String(), "--exec-on-complete command failed: exit status 3") {
+			t.Errorf("%s: stderr does not hold the hook output and failure:\n%s", tt.name, errOut.String())
+		}
+	}
+}
+
+func TestRunOEIS(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, "# A000045\n0 0\n1 1\n2 1\n10 55\n")
+	}))
+	defer srv.Close()
+	oldURL := oeisBFileURL
+	oeisBFileURL = srv.URL
+	defer func() { oeisBFileURL = oldURL }()
+	t.Setenv(oeisFileEnv, filepath.Join(t.TempDir(), "b000045.txt"))
+
+	tests := []struct {
+		name     string
+		n        uint64
+		value    int64
+		wantCode int
+		want     string
+	}{
+		{"match", 10, 55, apperrors.ExitSuccess, "F(10) matches a(10) of the b-file."},
+		{"mismatch", 10, 56, apperrors.ExitErrorVerification, "F(10) differs from a(10)"},
+		{"mismatch report", 10, 56, apperrors.ExitErrorVerification, "Write a bug report to "},
+		{"beyond the b-file", 20, 6765, apperrors.ExitSuccess, "the b-file lists a(0)..a(10), not F(20); not checked."},
+	}
+	for _, tt := range tests {
+		var out, errOut bytes.Buffer
+		app := &Application{
+			Config: config.AppConfig{
+				N:       tt.n,
+				Algo:    "fast",
+				Timeout: 1 * time.Minute,
+				OEIS:    true,
+			},
+			Factory:   createMockFactory(big.NewInt(tt.value), nil),
+			ErrWriter: &errOut,
+			In:        strings.NewReader("n\n"),
+		}
+		if exitCode := app.Run(context.Background(), &out); exitCode != tt.wantCode {
+			t.Errorf("%s: exit code %d, want %d", tt.name, exitCode, tt.wantCode)
+		}
+		if got := out.String() + errOut.String(); !strings.Contains(got, tt.want) {
+			t.Errorf("%s: output does not contain %q:\n%s", tt.name, tt.want, got)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("b-file downloaded %d times, want once", requests)
+	}
+
+	// No b-file: not cached, and the download fails
+	missing := httptest.NewServer(http.NotFoundHandler())
+	defer missing.Close()
+	oeisBFileURL = missing.URL
+	t.Setenv(oeisFileEnv, filepath.Join(t.TempDir(), "b000045.txt"))
+	var errOut bytes.Buffer
+	app := &Application{
+		Config:    config.AppConfig{N: 10, Algo: "fast", Timeout: time.Minute, OEIS: true},
+		Factory:   createMockFactory(big.NewInt(55), nil),
+		ErrWriter: &errOut,
+	}
+	if exitCode := app.Run(context.Background(), &bytes.Buffer{}); exitCode != apperrors.ExitErrorNoReference {
+		t.Errorf("without a b-file: exit code %d, want %d:\n%s", exitCode, apperrors.ExitErrorNoReference, errOut.String())
+	}
+}
+
+// TestRunWritesAuditLog verifies that an audit record is appended per run.
+func TestRunWritesAuditLog(t *testing.T) {
+	t.Parallel()
+	auditPath := filepath.Join(t.TempDir(), "audit.jsonl")
+	factory := createMockFactory(big.NewInt(55), nil)
+
+	for _, quiet := range []bool{false, true} {
+		app := &Application{
+			Config: config.AppConfig{
+				N:        10,
+				Algo:     "fast",
+				Timeout:  1 * time.Minute,
+				Quiet:    quiet,
+				AuditLog: auditPath,
+			},
+			Factory:   factory,
+			ErrWriter: &bytes.Buffer{},
+			Args:      []string{"-n", "10", "--audit-log", auditPath},
+		}
+		if exitCode := app.Run(context.Background(), &bytes.Buffer{}); exitCode != apperrors.ExitSuccess {
+			t.Fatalf("Expected exit code %d, got %d", apperrors.ExitSuccess, exitCode)
+		}
+	}
+
+	data, err := os.ReadFile(auditPath)
+	if err != nil {
+		t.Fatalf("Failed to read audit log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 audit records, got %d", len(lines))
+	}
+	var rec audit.Record
+	if err := json.Unmarshal([]byte(lines[0]), &rec); err != nil {
+		t.Fatalf("Invalid audit record: %v", err)
+	}
+	if rec.ExitCode != apperrors.ExitSuccess || rec.Config.N != 10 || len(rec.Args) != 4 {
+		t.Errorf("Unexpected audit record: %+v", rec)
+	}
+	if rec.ResultSHA256 != audit.HashResult(big.NewInt(55)) {
+		t.Errorf("ResultSHA256 = %q, want hash of 55", rec.ResultSHA256)
+	}
+}
+
+func TestApplyTimeoutFactor(t *testing.T) {
+	t.Parallel()
+	newApp := func(factor float64, err error) (*Application, *bytes.Buffer) {
+		var errOut bytes.Buffer
+		return &Application{
+			Config: config.AppConfig{
+				N:             1000,
+				Algo:          "all",
+				Timeout:       time.Minute,
+				TimeoutFactor: factor,
+			},
+			Factory:   createMockFactory(big.NewInt(55), err),
+			ErrWriter: &errOut,
+		}, &errOut
+	}
+
+	t.Run("Unset factor keeps the timeout", func(t *testing.T) {
+		t.Parallel()
+		app, _ := newApp(0, nil)
+		app.applyTimeoutFactor(context.Background(), &bytes.Buffer{})
+		if app.Config.Timeout != time.Minute {
+			t.Errorf("Timeout = %s, want 1m0s", app.Config.Timeout)
+		}
+	})
+
+	t.Run("Fast predictions get the minimum timeout", func(t *testing.T) {
+		t.Parallel()
+		app, _ := newApp(2, nil)
+		var out bytes.Buffer
+		app.applyTimeoutFactor(context.Background(), &out)
+		if app.Config.Timeout != minFactorTimeout {
+			t.Errorf("Timeout = %s, want %s", app.Config.Timeout, minFactorTimeout)
+		}
+		if !strings.Contains(out.String(), "Timeout: ") || !strings.Contains(out.String(), "2x the predicted") {
+			t.Errorf("Unexpected output: %q", out.String())
+		}
+	})
+
+	t.Run("Failed prediction keeps the timeout", func(t *testing.T) {
+		t.Parallel()
+		app, errOut := newApp(2, fmt.Errorf("boom"))
+		app.applyTimeoutFactor(context.Background(), &bytes.Buffer{})
+		if app.Config.Timeout != time.Minute {
+			t.Errorf("Timeout = %s, want 1m0s", app.Config.Timeout)
+		}
+		if !strings.Contains(errOut.String(), "cannot predict the duration") {
+			t.Errorf("Expected a warning, got %q", errOut.String())
+		}
+	})
+}
+
+// TestRunNoWrite checks that --no-write leaves no file behind, even for the
+// features that were not turned off by the configuration, and that the
+// low-memory algorithm still runs. Writes are denied process-wide, so it
+// does not run in parallel.
+func TestRunNoWrite(t *testing.T) {
+	dir := t.TempDir()
+	app := &Application{
+		Config: config.AppConfig{
+			N:           1000,
+			Algo:        "all",
+			Timeout:     1 * time.Minute,
+			NoTable:     true,
+			NoWrite:     true,
+			History:     true,
+			HistoryFile: filepath.Join(dir, "history.jsonl"),
+			AuditLog:    filepath.Join(dir, "audit.jsonl"),
+		},
+		Factory:   fibonacci.NewDefaultFactory(),
+		ErrWriter: &bytes.Buffer{},
+	}
+	if code := app.Run(context.Background(), &bytes.Buffer{}); code != apperrors.ExitSuccess {
+		t.Errorf("exit code = %d, want %d; stderr:\n%s", code, apperrors.ExitSuccess, app.ErrWriter)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("--no-write run wrote %d files in %s", len(entries), dir)
+	}
+	if fsguard.Denied() {
+		t.Error("writes still denied after Run returned")
+	}
+}
+
+// TestResolveThresholdsLazily checks that New does not read the calibration
+// profile, and that it is read once, on first use.
+func TestResolveThresholdsLazily(t *testing.T) {
+	t.Parallel()
+	profilePath := filepath.Join(t.TempDir(), "calibration.json")
+	profile := calibration.NewProfile()
+	profile.OptimalParallelThreshold = 8192
+	profile.OptimalFFTThreshold = 600000
+	profile.OptimalStrassenThreshold = 4096
+	if err := profile.SaveProfile(profilePath); err != nil {
+		t.Fatalf("SaveProfile: %v", err)
+	}
+
+	app, err := New([]string{"fibcalc", "-n", "20", "--calibration-profile", profilePath}, io.Discard)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if app.Config.Threshold != 0 || app.Config.FFTThreshold != 0 {
+		t.Errorf("New resolved the thresholds: %d, %d", app.Config.Threshold, app.Config.FFTThreshold)
+	}
+
+	cfg := app.resolveThresholds()
+	if cfg.Threshold != 8192 || cfg.FFTThreshold != 600000 || cfg.StrassenThreshold != 4096 {
+		t.Errorf("thresholds = %d, %d, %d, want those of the profile", cfg.Threshold, cfg.FFTThreshold, cfg.StrassenThreshold)
+	}
+	if err := os.Remove(profilePath); err != nil {
+		t.Fatal(err)
+	}
+	if crossover, source := calibration.ProfileFFTCrossover(app.calibrationProfile()); crossover != 600000 {
+		t.Errorf("cost model crossover = %d from %s, want the profile read before", crossover, source)
+	}
+}
+
+// BenchmarkStartup measures the setup of a small calculation run from a
+// script, which dominates its duration.
+func BenchmarkStartup(b *testing.B) {
+	args := []string{"fibcalc", "-n", "20", "--quiet", "--history=false"}
+	for b.Loop() {
+		app, err := New(args, io.Discard)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if code := app.Run(context.Background(), io.Discard); code != apperrors.ExitSuccess {
+			b.Fatalf("exit code %d", code)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package app
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/agbru/fibcalc/internal/bugreport"
+	"github.com/agbru/fibcalc/internal/orchestration"
+	"golang.org/x/term"
+)
+
+// openURL opens u in the user's browser. It is a variable so that tests
+// can replace it.
+var openURL = func(u string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", u)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", u)
+	default:
+		cmd = exec.Command("xdg-open", u)
+	}
+	return cmd.Start()
+}
+
+// offerBugReport is called when the algorithms disagree, or when a result
+// fails its verification against the OEIS b-file (--oeis) or an external
+// program (fibcalc crosscheck); results then end with the reference value.
+// It offers to write a bug report (see package bugreport) to the current
+// directory, then to open the issue tracker. Without a terminal to answer
+// on, it only points to the issue tracker. Prompts go to ErrWriter so that
+// they stay out of redirected output.
+func (a *Application) offerBugReport(results []orchestration.CalculationResult) {
+	in := a.In
+	if in == nil {
+		in = os.Stdin
+	}
+	if !canPrompt(in) {
+		fmt.Fprintf(a.ErrWriter, "\nPlease report this mismatch at %s\n", bugreport.IssueURL)
+		return
+	}
+
+	report := bugreport.New(a.Config, a.Args, bugreport.Build{Version: Version, Commit: Commit, BuildDate: BuildDate}, results)
+	answers := bufio.NewReader(in)
+	path := bugreport.DefaultFileName(report.Created)
+	if !confirm(answers, a.ErrWriter, fmt.Sprintf("\nWrite a bug report to %s? [Y/n] ", path), true) {
+		return
+	}
+	if err := report.WriteFile(path); err != nil {
+		fmt.Fprintf(a.ErrWriter, "Error: %v\n", err)
+		return
+	}
+	fmt.Fprintf(a.ErrWriter, "Bug report written to %s.\n", path)
+
+	link := report.IssueLink()
+	if confirm(answers, a.ErrWriter, "Open the issue tracker in your browser? [y/N] ", false) {
+		err := openURL(link)
+		if err == nil {
+			fmt.Fprintf(a.ErrWriter, "Paste the content of %s into the issue.\n", path)
+			return
+		}
+		fmt.Fprintf(a.ErrWriter, "Cannot open a browser: %v\n", err)
+	}
+	fmt.Fprintf(a.ErrWriter, "File an issue at %s and paste the content of %s into it.\n", link, path)
+}
+
+// canPrompt reports whether answers can be read from in: it is an
+// interactive terminal, or a reader that is not a file (supplied by the
+// caller, e.g. in tests). Piped standard input is never read.
+func canPrompt(in io.Reader) bool {
+	f, ok := in.(*os.File)
+	return !ok || term.IsTerminal(int(f.Fd()))
+}
+
+// confirm prints question and reads a yes/no answer from answers. An empty
+// answer, or the end of the input, selects def.
+func confirm(answers *bufio.Reader, out io.Writer, question string, def bool) bool {
+	fmt.Fprint(out, question)
+	line, err := answers.ReadString('\n')
+	if err != nil && line == "" {
+		fmt.Fprintln(out)
+		return def
+	}
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true
+	case "n", "no":
+		return false
+	}
+	return def
+}
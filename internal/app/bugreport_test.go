@@ -0,0 +1,103 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/config"
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+)
+
+// mismatchApp returns an application whose algorithms disagree, reading
+// prompt answers from answers.
+func mismatchApp(answers string, errOut *bytes.Buffer) *Application {
+	return &Application{
+		Config: config.AppConfig{
+			N:                  1000,
+			Algo:               "all",
+			Timeout:            time.Minute,
+			CompareMode:        "sequential",
+			FailMode:           "mismatch",
+			CalibrationProfile: "none.json",
+		},
+		Factory:   fibonacci.NewDefaultFactory(),
+		ErrWriter: errOut,
+		Args:      []string{"-n", "1000", "--fail-mode", "mismatch"},
+		In:        strings.NewReader(answers),
+	}
+}
+
+// TestOfferBugReport verifies the prompts shown when the algorithms
+// disagree. It changes the working directory, so it does not run in
+// parallel.
+func TestOfferBugReport(t *testing.T) {
+	var opened []string
+	saved := openURL
+	openURL = func(u string) error {
+		opened = append(opened, u)
+		return nil
+	}
+	t.Cleanup(func() { openURL = saved })
+
+	t.Run("write and open", func(t *testing.T) {
+		t.Chdir(t.TempDir())
+		opened = nil
+		var errOut bytes.Buffer
+		if code := mismatchApp("\ny\n", &errOut).Run(context.Background(), &bytes.Buffer{}); code != apperrors.ExitErrorMismatch {
+			t.Fatalf("exit code = %d, want %d", code, apperrors.ExitErrorMismatch)
+		}
+		files, _ := filepath.Glob("fibcalc-bugreport-*.md")
+		if len(files) != 1 {
+			t.Fatalf("expected one report, found %v\n%s", files, errOut.String())
+		}
+		data, err := os.ReadFile(files[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), "## Divergence analysis") {
+			t.Errorf("report lacks the divergence analysis:\n%s", data)
+		}
+		if len(opened) != 1 || !strings.Contains(opened[0], "issues/new") {
+			t.Errorf("opened = %v, want the issue tracker", opened)
+		}
+	})
+
+	t.Run("declined", func(t *testing.T) {
+		t.Chdir(t.TempDir())
+		opened = nil
+		var errOut bytes.Buffer
+		mismatchApp("n\n", &errOut).Run(context.Background(), &bytes.Buffer{})
+		if files, _ := filepath.Glob("fibcalc-bugreport-*.md"); len(files) != 0 {
+			t.Errorf("no report should be written, found %v", files)
+		}
+		if len(opened) != 0 {
+			t.Errorf("nothing should be opened, got %v", opened)
+		}
+	})
+
+	t.Run("no terminal", func(t *testing.T) {
+		t.Chdir(t.TempDir())
+		var errOut bytes.Buffer
+		app := mismatchApp("", &errOut)
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer r.Close()
+		defer w.Close()
+		app.In = r
+		app.Run(context.Background(), &bytes.Buffer{})
+		if files, _ := filepath.Glob("fibcalc-bugreport-*.md"); len(files) != 0 {
+			t.Errorf("no report should be written, found %v", files)
+		}
+		if !strings.Contains(errOut.String(), "Please report this mismatch at") {
+			t.Errorf("expected a pointer to the issue tracker, got:\n%s", errOut.String())
+		}
+	})
+}
@@ -7,19 +7,62 @@ import (
 	"math/big"
 	"os"
 	"os/signal"
+	"slices"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/agbru/fibcalc/internal/cli"
+	"github.com/agbru/fibcalc/internal/config"
 	apperrors "github.com/agbru/fibcalc/internal/errors"
 	"github.com/agbru/fibcalc/internal/fibonacci"
 	"github.com/agbru/fibcalc/internal/fibonacci/memory"
+	"github.com/agbru/fibcalc/internal/format"
+	"github.com/agbru/fibcalc/internal/metrics"
 	"github.com/agbru/fibcalc/internal/orchestration"
+	"github.com/agbru/fibcalc/internal/perfevent"
+	"github.com/agbru/fibcalc/internal/progress"
+	"github.com/agbru/fibcalc/internal/sysmon"
 	"github.com/agbru/fibcalc/internal/ui"
 )
 
+// exitProcess ends the process when the watchdog finds a calculation that
+// cannot be aborted; tests replace it.
+var exitProcess = os.Exit
+
+// resolveAutoAlgorithm replaces the "auto" algorithm with the calculator the
+// cost model expects to be fastest for the configured n, using the FFT
+// crossover from the calibration profile when one is available. Only the
+// calculators whose metadata recommends them for n are candidates, unless
+// none is. The choice and its rationale are printed unless quiet mode is
+// enabled.
+func (a *Application) resolveAutoAlgorithm(out io.Writer) {
+	model, source := a.costModel()
+	candidates := fibonacci.RecommendedFor(a.Factory, a.Config.N)
+	if len(candidates) == 0 {
+		candidates = a.Factory.List()
+	}
+	sel := model.Select(a.Config.N, candidates)
+	if sel.Name == "" {
+		return
+	}
+	if !a.Config.Quiet && !a.Config.TUI {
+		fmt.Fprintf(out, "Auto-selected algorithm: %s\n", ui.ColorGreen()+sel.Name+ui.ColorReset())
+		fmt.Fprintf(out, "  Rationale: %s; crossover from %s.\n", sel.Rationale, source)
+		if excluded := slices.DeleteFunc(a.Factory.List(), func(name string) bool {
+			return slices.Contains(candidates, name)
+		}); len(excluded) > 0 {
+			fmt.Fprintf(out, "  Outside their recommended range of n: %s (see fibcalc algos).\n", strings.Join(excluded, ", "))
+		}
+	}
+	a.Config.Algo = sel.Name
+}
+
 // runCalculate orchestrates the execution of the CLI calculation command.
 func (a *Application) runCalculate(ctx context.Context, out io.Writer) int {
+	// Measured around the whole run, fallbacks included
+	defer a.measureUsage(out)()
+
 	// Partial computation mode: last K digits only
 	if a.Config.LastDigits > 0 {
 		return a.runLastDigits(ctx, out)
@@ -32,19 +75,41 @@ func (a *Application) runCalculate(ctx context.Context, out io.Writer) int {
 		}
 	}
 
-	// Setup lifecycle (timeout + signals)
-	ctx, cancelTimeout := context.WithTimeout(ctx, a.Config.Timeout)
+	// Setup lifecycle (timeout + signals). The timeout is paused during the
+	// --warmup runs.
+	a.applyTimeoutFactor(ctx, out)
+	ctx, cancelTimeout := orchestration.WithExtendableTimeout(ctx, a.Config.Timeout)
 	defer cancelTimeout()
 	ctx, stopSignals := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
 	defer stopSignals()
 
 	// Get calculators to run
 	calculatorsToRun := orchestration.GetCalculatorsToRun(a.Config.Algo, a.Factory)
+	compareMode, err := orchestration.ParseCompareMode(a.Config.CompareMode)
+	if err != nil {
+		fmt.Fprintf(a.ErrWriter, "Configuration error: %v\n", err)
+		return apperrors.ExitErrorConfig
+	}
+
+	// Hardware counters are process-wide, so they imply sequential runs
+	perfCounters := a.Config.PerfCounters
+	if perfCounters {
+		if err := perfevent.Available(); err != nil {
+			fmt.Fprintf(a.ErrWriter, "Warning: hardware counters unavailable, continuing without them: %v\n", err)
+			perfCounters = false
+		} else {
+			compareMode = orchestration.CompareSequential
+		}
+	}
+	// So does the energy indicator, which measures the whole package
+	if a.Config.Energy {
+		compareMode = orchestration.CompareSequential
+	}
 
 	// Skip verbose output in quiet mode
 	if !a.Config.Quiet {
 		cli.PrintExecutionConfig(a.Config, out)
-		cli.PrintExecutionMode(calculatorsToRun, out)
+		cli.PrintExecutionMode(calculatorsToRun, compareMode, out)
 	}
 
 	// Choose progress reporter based on quiet mode
@@ -54,26 +119,127 @@ func (a *Application) runCalculate(ctx context.Context, out io.Writer) int {
 		progressOut = io.Discard
 		progressReporter = orchestration.NullProgressReporter{}
 	} else {
-		progressReporter = cli.CLIProgressReporter{}
+		progressReporter = cli.CLIProgressReporter{RefreshRate: a.Config.ProgressRefresh}
 	}
 
 	// Execute calculations
 	opts := fibonacci.Options{
-		ParallelThreshold: a.Config.Threshold,
-		FFTThreshold:      a.Config.FFTThreshold,
-		StrassenThreshold: a.Config.StrassenThreshold,
+		ParallelThreshold:   a.Config.Threshold,
+		FFTThreshold:        a.Config.FFTThreshold,
+		StrassenThreshold:   a.Config.StrassenThreshold,
+		GCMode:              a.Config.GCControl,
+		SoftRealtime:        a.Config.SoftRealtime,
+		DisableTables:       a.Config.NoTable,
+		SpillThresholdBytes: a.Config.SpillThresholdBytes(),
+		SpillDir:            a.Config.SpillDir,
+	}
+	execOpts := orchestration.ExecutionOptions{
+		Mode:            compareMode,
+		PerfCounters:    perfCounters,
+		Energy:          a.Config.Energy,
+		TDP:             float64(a.Config.TDP),
+		Failure:         fibonacci.NewFailureInjection(a.Config.FailMode, a.Config.FailAfter),
+		Warmup:          a.Config.Warmup,
+		ProgressPolicy:  progress.Policy(a.Config.ProgressPolicy),
+		ProgressTimeout: a.Config.ProgressTimeout,
+	}
+	if a.Config.AdaptiveParallelism {
+		execOpts.Contention = sysmon.NewContentionMonitor()
+	}
+	if a.Config.MemoryPressure > 0 {
+		execOpts.MemoryPressure = memory.NewPressureMonitor(a.Config.MemoryPressure)
+		execOpts.MemoryPressure.OnPressure(func(p memory.Pressure) { cli.PrintMemoryPressure(p, a.ErrWriter) })
+	}
+	if a.Config.StallFactor > 0 {
+		execOpts.Watchdog = orchestration.NewWatchdog(orchestration.WatchdogOptions{
+			Factor: a.Config.StallFactor,
+			Abort:  a.Config.StallAbort,
+			Out:    a.ErrWriter,
+			// A calculation stuck past its context checks cannot return
+			OnStuck: func() { exitProcess(apperrors.ExitErrorStalled) },
+		})
+	}
+	if a.Config.RuntimeTrace != "" {
+		// Validated with the configuration
+		path, window, _ := config.ParseRuntimeTrace(a.Config.RuntimeTrace)
+		execOpts.RuntimeTrace = orchestration.NewRuntimeTrace(orchestration.RuntimeTraceOptions{Path: path, Window: window})
+	}
+	var measureGC func() memory.GCStats
+	if a.Config.SoftRealtime {
+		measureGC = memory.MeasureGC()
+	}
+	a.fallbacks = nil
+	results := a.runWithFallback(calculatorsToRun, func(calcs []fibonacci.Calculator) []orchestration.CalculationResult {
+		reporter := progressReporter
+		if a.pusher != nil {
+			reporter = a.progressPusher(reporter, calcs)
+		}
+		return orchestration.ExecuteCalculationsWithOptions(ctx, calcs, a.Config.N, opts, execOpts, reporter, progressOut)
+	})
+	if execOpts.RuntimeTrace != nil {
+		a.reportRuntimeTrace(execOpts.RuntimeTrace, out)
+	}
+	if measureGC != nil && !a.Config.Quiet {
+		printSoftRealtime(out, measureGC())
+	}
+	if a.Config.Verbose {
+		// Lost progress updates explain a display that stalled
+		if s := progress.TotalStats(); s.Dropped+s.Coalesced > 0 {
+			fmt.Fprintf(out, "Progress updates: %d sent, %d dropped, %d coalesced (policy %s)\n",
+				s.Sent, s.Dropped, s.Coalesced, execOpts.ProgressPolicy)
+		}
+		if m := execOpts.Contention; m != nil {
+			fmt.Fprintf(out, "Adaptive parallelism: %d switches between parallel and sequential multiplications\n", m.Switches())
+		}
 	}
-	results := orchestration.ExecuteCalculations(ctx, calculatorsToRun, a.Config.N, opts, progressReporter, progressOut)
 
 	// Build output config for the CLI options
 	outputCfg := cli.OutputConfig{
-		OutputFile: a.Config.OutputFile,
-		Quiet:      a.Config.Quiet,
-		Verbose:    a.Config.Verbose,
-		ShowValue:  a.Config.ShowValue,
+		OutputFile:        a.Config.OutputFile,
+		Quiet:             a.Config.Quiet,
+		QuietDuration:     format.DurationUnit(a.Config.QuietDuration),
+		DurationPrecision: a.Config.DurationPrecision,
+		Verbose:           a.Config.Verbose,
+		ShowValue:         a.Config.ShowValue,
+		Seed:              a.Config.Seed,
+		Truncation:        cli.TruncationFromConfig(a.Config),
+		Indicators:        a.indicators(),
+		SigningKey:        a.signingKey,
+		Version:           Version,
 	}
 
-	return a.analyzeResultsWithOutput(results, outputCfg, out)
+	exitCode := a.analyzeResultsWithOutput(results, outputCfg, out)
+	if len(a.fallbacks) > 0 && exitCode == apperrors.ExitSuccess && !a.Config.Quiet && a.outcome != nil {
+		name := a.algorithmKeys()[a.outcome.Name]
+		if name == "" {
+			name = a.outcome.Name
+		}
+		fmt.Fprintf(out, "%sNote: computed by %s after falling back from %s.%s\n",
+			ui.ColorYellow(), name, strings.Join(a.fallbacks, ", "), ui.ColorReset())
+	}
+	if exitCode == apperrors.ExitErrorMismatch {
+		a.offerBugReport(results)
+	}
+	if a.Config.OEIS && exitCode == apperrors.ExitSuccess && a.outcome != nil {
+		report := out
+		if a.Config.Quiet {
+			report = nil
+		}
+		exitCode = a.checkOEIS(ctx, a.outcome, report)
+	}
+	return exitCode
+}
+
+// printSoftRealtime reports the GC pauses of a --soft-realtime run against
+// config.SoftRealtimeTarget.
+func printSoftRealtime(out io.Writer, stats memory.GCStats) {
+	pause := time.Duration(stats.MaxPauseNs)
+	color := ui.ColorGreen()
+	if pause > config.SoftRealtimeTarget {
+		color = ui.ColorYellow()
+	}
+	fmt.Fprintf(out, "Soft real-time: %d GC cycles, longest pause %s%s%s (target %s)\n",
+		stats.NumGC, color, format.FormatExecutionDuration(pause), ui.ColorReset(), format.FormatExecutionDuration(config.SoftRealtimeTarget))
 }
 
 // validateMemoryBudget checks if the estimated memory usage fits within the configured limit.
@@ -111,42 +277,47 @@ func (a *Application) runLastDigits(ctx context.Context, out io.Writer) int {
 	k := a.Config.LastDigits
 	n := a.Config.N
 
-	// Compute modulus = 10^k
-	mod := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(k)), nil)
-
 	if !a.Config.Quiet {
 		fmt.Fprintf(out, "Computing last %d digits of F(%d)...\n", k, n)
 	}
 
 	start := time.Now()
-	result, err := fibonacci.FastDoublingMod(n, mod)
+	digits, err := fibonacci.LastDigits(ctx, n, k)
 	elapsed := time.Since(start)
 
 	if err != nil {
-		fmt.Fprintf(a.ErrWriter, "Error: %v\n", err)
-		return apperrors.ExitErrorGeneric
+		return apperrors.HandleCalculationError(err, elapsed, a.ErrWriter, nil)
 	}
-
-	// Format with leading zeros to exactly k digits
-	format := fmt.Sprintf("%%0%ds", k)
-	digits := fmt.Sprintf(format, result.String())
+	result, _ := new(big.Int).SetString(digits, 10)
+	a.outcome = &orchestration.CalculationResult{Name: "last-digits", Result: result, Duration: elapsed}
 
 	if a.Config.Quiet {
-		fmt.Fprintln(out, digits)
+		quietCfg := cli.OutputConfig{QuietDuration: format.DurationUnit(a.Config.QuietDuration), DurationPrecision: a.Config.DurationPrecision}
+		fmt.Fprintln(out, cli.FormatQuietLine(digits, elapsed, quietCfg))
 	} else {
-		fmt.Fprintf(out, "Last %d digits of F(%d): %s\n", k, n, digits)
-		fmt.Fprintf(out, "Computed in %s\n", elapsed.Round(time.Millisecond))
+		fmt.Fprintf(out, "Last %d digits of F(%d) (partial output, F(n) mod 10^%d): %s\n", k, n, k, digits)
+		fmt.Fprintf(out, "Computed in %s\n", format.FormatExecutionDuration(elapsed))
 	}
 
 	return apperrors.ExitSuccess
 }
 
 func (a *Application) analyzeResultsWithOutput(results []orchestration.CalculationResult, outputCfg cli.OutputConfig, out io.Writer) int {
+	// Shared: the analysis sorts results and marks mismatches in place.
+	a.results = results
 	bestResult := findBestResult(results)
+	if bestResult != nil {
+		// Copy: AnalyzeComparisonResults sorts results in place.
+		outcome := *bestResult
+		a.outcome = &outcome
+	}
 
 	// Handle quiet mode for single result
 	if outputCfg.Quiet && bestResult != nil {
-		cli.DisplayQuietResult(out, bestResult.Result, a.Config.N, bestResult.Duration)
+		if err := cli.WriteQuietResult(out, bestResult.Result, bestResult.Duration, outputCfg); err != nil {
+			fmt.Fprintf(a.ErrWriter, "Error writing the result: %v\n", err)
+			return apperrors.ExitErrorGeneric
+		}
 
 		// Save to file if requested
 		if err := a.saveResultIfNeeded(bestResult, outputCfg); err != nil {
@@ -163,7 +334,8 @@ func (a *Application) analyzeResultsWithOutput(results []orchestration.Calculati
 		Details:   a.Config.Details,
 		ShowValue: a.Config.ShowValue,
 	}
-	exitCode := orchestration.AnalyzeComparisonResults(results, presOpts, cli.CLIResultPresenter{}, cli.CLIResultPresenter{}, out)
+	presenter := cli.CLIResultPresenter{Truncation: outputCfg.Truncation, Indicators: outputCfg.Indicators}
+	exitCode := orchestration.AnalyzeComparisonResults(results, presOpts, presenter, presenter, out)
 
 	// Handle file output for non-quiet mode
 	if bestResult != nil && exitCode == apperrors.ExitSuccess {
@@ -202,3 +374,14 @@ func (a *Application) saveResultIfNeeded(res *orchestration.CalculationResult, c
 	}
 	return nil
 }
+
+// indicators returns the indicators selected by --indicators. The
+// selection was validated with the configuration; all indicators are shown
+// if it no longer resolves.
+func (a *Application) indicators() []metrics.Indicator {
+	selected, err := metrics.DefaultRegistry.Select(a.Config.Indicators)
+	if err != nil {
+		return nil
+	}
+	return selected
+}
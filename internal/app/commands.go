@@ -0,0 +1,296 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/capabilities"
+	"github.com/agbru/fibcalc/internal/cli"
+	"github.com/agbru/fibcalc/internal/config"
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/format"
+	"github.com/agbru/fibcalc/internal/fsguard"
+	"github.com/agbru/fibcalc/internal/metrics"
+)
+
+// commandHandler runs a subcommand with its arguments (after the name) and
+// returns the exit code.
+type commandHandler func(args []string, stdout, stderr io.Writer) int
+
+// commands maps the subcommands listed in config.Commands to their handlers.
+var commands = map[string]commandHandler{
+	"install-manpages": runInstallManPages,
+	"algos":            runAlgos,
+	"env":              runEnv,
+	"digits":           runDigits,
+	"history":          runHistory,
+	"verify-signature": runVerifySignature,
+	"capabilities":     runCapabilities,
+	"telemetry":        runTelemetry,
+	"crosscheck":       runCrosscheck,
+	"plot":             runPlot,
+}
+
+// RunCommand runs the subcommand named by args[1], if there is one.
+// Subcommands are recognized before flag parsing, so they take their own
+// flags.
+//
+// Parameters:
+//   - args: The full command line, program name included.
+//   - stdout: The writer for regular output.
+//   - stderr: The writer for errors.
+//
+// Returns:
+//   - int: The exit code of the subcommand.
+//   - bool: True if args named a subcommand, false otherwise.
+func RunCommand(args []string, stdout, stderr io.Writer) (int, bool) {
+	if len(args) < 2 {
+		return 0, false
+	}
+	handler, ok := commands[args[1]]
+	if !ok {
+		return 0, false
+	}
+	return handler(args[2:], stdout, stderr), true
+}
+
+// manPageName is the file name of the installed man page.
+const manPageName = "fibcalc.1"
+
+// runInstallManPages writes the fibcalc(1) man page to a man1 directory.
+func runInstallManPages(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("install-manpages", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	dir := fs.String("dir", defaultManDir(), "Directory to install the section 1 man page in.")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return apperrors.ExitSuccess
+		}
+		return apperrors.ExitErrorConfig
+	}
+	if *dir == "" {
+		fmt.Fprintln(stderr, "Error: no man page directory; use --dir.")
+		return apperrors.ExitErrorConfig
+	}
+
+	if err := fsguard.MkdirAll(*dir, 0o755); err != nil {
+		fmt.Fprintf(stderr, "Error creating %s: %v\n", *dir, err)
+		return apperrors.ExitErrorGeneric
+	}
+	path := filepath.Join(*dir, manPageName)
+	f, err := fsguard.Create(path)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error creating %s: %v\n", path, err)
+		return apperrors.ExitErrorGeneric
+	}
+	err = config.WriteManPage(f, Version, fibonacci.AcceptedNames(fibonacci.NewDefaultFactory()))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "Error writing %s: %v\n", path, err)
+		return apperrors.ExitErrorGeneric
+	}
+	fmt.Fprintf(stdout, "Installed %s\n", path)
+	return apperrors.ExitSuccess
+}
+
+// defaultManDir returns the man1 directory to install into: the system-wide
+// one when running as root, the user's one otherwise.
+func defaultManDir() string {
+	if os.Geteuid() == 0 {
+		return "/usr/local/share/man/man1"
+	}
+	if dataHome := os.Getenv("XDG_DATA_HOME"); dataHome != "" {
+		return filepath.Join(dataHome, "man", "man1")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".local", "share", "man", "man1")
+}
+
+// runEnv lists the FIBCALC_* variables and their status. args are the
+// flags the variables would be combined with.
+func runEnv(args []string, stdout, stderr io.Writer) int {
+	statuses, err := config.CheckEnv(args, fibonacci.AcceptedNames(fibonacci.NewDefaultFactory()))
+	if err != nil {
+		fmt.Fprintln(stderr, "Configuration error:", err)
+		return apperrors.ExitErrorConfig
+	}
+	if !config.WriteEnvReport(stdout, statuses) {
+		return apperrors.ExitErrorConfig
+	}
+	return apperrors.ExitSuccess
+}
+
+// runCapabilities reports the optional subsystems compiled into the
+// binary and available on this machine, as a table or as JSON.
+func runCapabilities(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("capabilities", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	asJSON := fs.Bool("json", false, "Print the report as JSON.")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return apperrors.ExitSuccess
+		}
+		return apperrors.ExitErrorConfig
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "Error: unexpected argument %q.\n", fs.Arg(0))
+		return apperrors.ExitErrorConfig
+	}
+
+	report := capabilities.Detect(Version, fibonacci.NewDefaultFactory())
+	if *asJSON {
+		if err := capabilities.WriteJSON(stdout, report); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return apperrors.ExitErrorGeneric
+		}
+		return apperrors.ExitSuccess
+	}
+	capabilities.Write(stdout, report)
+	return apperrors.ExitSuccess
+}
+
+// defaultDigitsWindow is the number of digits `fibcalc digits` prints
+// without --last.
+const defaultDigitsWindow = 20
+
+// runDigits prints the last K decimal digits of F(N), computed with modular
+// arithmetic, or with --first the first K, computed with interval
+// arithmetic. Both are labeled as partial output. N is given with -n or as
+// the only argument.
+func runDigits(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("digits", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	n := fs.Uint64("n", 0, "Index N of the Fibonacci number (or give it as the argument).")
+	last := fs.Int("last", defaultDigitsWindow, "Number of trailing decimal digits to print.")
+	first := fs.Int("first", 0, "Print the first K decimal digits instead, only those that are certain.")
+	timeout := fs.Duration("timeout", config.DefaultTimeout, "Maximum execution time.")
+	quiet := fs.Bool("quiet", false, "Print only the digits.")
+	fs.BoolVar(quiet, "q", false, "Alias for --quiet.")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return apperrors.ExitSuccess
+		}
+		return apperrors.ExitErrorConfig
+	}
+	switch {
+	case fs.NArg() > 1:
+		fmt.Fprintln(stderr, "Error: digits takes a single N.")
+		return apperrors.ExitErrorConfig
+	case fs.NArg() == 1:
+		v, err := strconv.ParseUint(fs.Arg(0), 10, 64)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: invalid N %q.\n", fs.Arg(0))
+			return apperrors.ExitErrorConfig
+		}
+		*n = v
+	}
+	if *last <= 0 {
+		fmt.Fprintf(stderr, "Error: --last must be positive, got %d.\n", *last)
+		return apperrors.ExitErrorConfig
+	}
+	lastSet := false
+	fs.Visit(func(f *flag.Flag) { lastSet = lastSet || f.Name == "last" })
+	switch {
+	case *first < 0:
+		fmt.Fprintf(stderr, "Error: --first must be positive, got %d.\n", *first)
+		return apperrors.ExitErrorConfig
+	case *first > 0 && lastSet:
+		fmt.Fprintln(stderr, "Error: --first and --last cannot be used together.")
+		return apperrors.ExitErrorConfig
+	case *first > 0 && *n > fibonacci.MaxLeadingDigitsN:
+		fmt.Fprintf(stderr, "Error: --first supports N up to %d.\n", fibonacci.MaxLeadingDigitsN)
+		return apperrors.ExitErrorConfig
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if *first > 0 {
+		return printLeadingDigits(ctx, *n, *first, *quiet, stdout, stderr)
+	}
+	start := time.Now()
+	digits, err := fibonacci.LastDigits(ctx, *n, *last)
+	elapsed := time.Since(start)
+	if err != nil {
+		return apperrors.HandleCalculationError(err, elapsed, stderr, nil)
+	}
+
+	if *quiet {
+		fmt.Fprintln(stdout, digits)
+		return apperrors.ExitSuccess
+	}
+	total := fibonacci.DecimalDigits(*n)
+	if total <= uint64(*last) {
+		// The window covers the whole value: print it without the padding.
+		value := strings.TrimLeft(digits, "0")
+		if value == "" {
+			value = "0"
+		}
+		fmt.Fprintf(stdout, "F(%d) = %s\n", *n, value)
+		fmt.Fprintf(stdout, "Complete value (%d digits), computed in %s.\n", len(value), format.FormatExecutionDuration(elapsed))
+		return apperrors.ExitSuccess
+	}
+	fmt.Fprintf(stdout, "F(%d) = ...%s\n", *n, digits)
+	fmt.Fprintf(stdout, "Partial output: the last %d of %s digits (F(%d) mod 10^%d), computed in %s.\n",
+		*last, format.FormatNumberString(strconv.FormatUint(total, 10)), *n, *last, format.FormatExecutionDuration(elapsed))
+	return apperrors.ExitSuccess
+}
+
+// printLeadingDigits prints the certain leading digits of F(n), at most k,
+// for runDigits. Digits that the error bounds do not settle are withheld
+// and counted.
+func printLeadingDigits(ctx context.Context, n uint64, k int, quiet bool, stdout, stderr io.Writer) int {
+	start := time.Now()
+	digits, total, err := fibonacci.LeadingDigits(ctx, n, k)
+	elapsed := time.Since(start)
+	if err != nil {
+		return apperrors.HandleCalculationError(err, elapsed, stderr, nil)
+	}
+
+	if quiet {
+		fmt.Fprintln(stdout, digits)
+		return apperrors.ExitSuccess
+	}
+	if uint64(len(digits)) == total {
+		fmt.Fprintf(stdout, "F(%d) = %s\n", n, digits)
+		fmt.Fprintf(stdout, "Complete value (%d digits), computed in %s.\n", total, format.FormatExecutionDuration(elapsed))
+		return apperrors.ExitSuccess
+	}
+	fmt.Fprintf(stdout, "F(%d) = %s...\n", n, digits)
+	fmt.Fprintf(stdout, "Partial output: the first %d of %s digits, certain from interval bounds on φ^%d/√5, computed in %s.\n",
+		len(digits), format.FormatNumberString(strconv.FormatUint(total, 10)), n, format.FormatExecutionDuration(elapsed))
+	if len(digits) < k {
+		fmt.Fprintf(stdout, "The other %d requested digits are not certain at the working precision and are withheld.\n", k-len(digits))
+	}
+	return apperrors.ExitSuccess
+}
+
+// runIndicatorList prints the registered indicators for --indicators list.
+func (a *Application) runIndicatorList(out io.Writer) int {
+	cli.DisplayIndicatorList(out, metrics.DefaultRegistry.Indicators())
+	return apperrors.ExitSuccess
+}
+
+// runFullHelp prints the --help-full output.
+func (a *Application) runFullHelp(out io.Writer) int {
+	config.WriteFullHelp(out, "fibcalc", fibonacci.AcceptedNames(a.Factory))
+	return apperrors.ExitSuccess
+}
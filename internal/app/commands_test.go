@@ -0,0 +1,366 @@
+package app
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/bugreport"
+	"github.com/agbru/fibcalc/internal/cli"
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/history"
+)
+
+func TestRunCommand(t *testing.T) {
+	t.Parallel()
+
+	t.Run("not a command", func(t *testing.T) {
+		t.Parallel()
+		for _, args := range [][]string{{"fibcalc"}, {"fibcalc", "-n", "10"}, {"fibcalc", "unknown"}} {
+			if _, ok := RunCommand(args, &bytes.Buffer{}, &bytes.Buffer{}); ok {
+				t.Errorf("RunCommand(%v) handled a non-command", args)
+			}
+		}
+	})
+
+	t.Run("install-manpages", func(t *testing.T) {
+		t.Parallel()
+		dir := filepath.Join(t.TempDir(), "man1")
+		var stdout, stderr bytes.Buffer
+		code, ok := RunCommand([]string{"fibcalc", "install-manpages", "--dir", dir}, &stdout, &stderr)
+		if !ok || code != apperrors.ExitSuccess {
+			t.Fatalf("RunCommand = (%d, %v), want (0, true); stderr: %s", code, ok, stderr.String())
+		}
+		data, err := os.ReadFile(filepath.Join(dir, manPageName))
+		if err != nil {
+			t.Fatalf("man page not installed: %v", err)
+		}
+		if !strings.HasPrefix(string(data), ".TH FIBCALC 1") {
+			t.Errorf("installed file is not a man page:\n%.80s", data)
+		}
+		if !strings.Contains(stdout.String(), "Installed") {
+			t.Errorf("stdout = %q, want an installation notice", stdout.String())
+		}
+	})
+
+	t.Run("install-manpages bad flag", func(t *testing.T) {
+		t.Parallel()
+		code, ok := RunCommand([]string{"fibcalc", "install-manpages", "--bogus"}, &bytes.Buffer{}, &bytes.Buffer{})
+		if !ok || code != apperrors.ExitErrorConfig {
+			t.Errorf("RunCommand = (%d, %v), want (%d, true)", code, ok, apperrors.ExitErrorConfig)
+		}
+	})
+}
+
+func TestRunFullHelp(t *testing.T) {
+	t.Parallel()
+	var errBuf, out bytes.Buffer
+	app, err := New([]string{"fibcalc", "--help-full"}, &errBuf)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if code := app.Run(t.Context(), &out); code != apperrors.ExitSuccess {
+		t.Fatalf("Run = %d, want 0", code)
+	}
+	if !strings.Contains(out.String(), "Exit codes:") {
+		t.Errorf("--help-full output lacks the exit codes:\n%s", out.String())
+	}
+}
+
+// TestMachineSchemaUpToDate checks that docs/machine-schema.json is the
+// output of `fibcalc --machine-schema`.
+func TestMachineSchemaUpToDate(t *testing.T) {
+	t.Parallel()
+	data, err := os.ReadFile(filepath.Join("..", "..", "docs", "machine-schema.json"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	var errBuf, out bytes.Buffer
+	app, err := New([]string{"fibcalc", "--machine-schema"}, &errBuf)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if code := app.Run(t.Context(), &out); code != apperrors.ExitSuccess {
+		t.Fatalf("Run = %d, want 0", code)
+	}
+	if out.String() != string(data) {
+		t.Errorf("docs/machine-schema.json is out of date; replace it with the output of fibcalc --machine-schema:\n%s", out.String())
+	}
+}
+
+func TestRunEnv(t *testing.T) {
+	t.Setenv("FIBCALC_N", "100")
+	var stdout bytes.Buffer
+	code, ok := RunCommand([]string{"fibcalc", "env", "-n", "5"}, &stdout, &bytes.Buffer{})
+	if !ok || code != apperrors.ExitSuccess {
+		t.Fatalf("RunCommand = (%d, %v), want (0, true)", code, ok)
+	}
+	if !strings.Contains(stdout.String(), "ok (overridden by flag)") {
+		t.Errorf("FIBCALC_N should be reported as overridden by -n:\n%s", stdout.String())
+	}
+
+	t.Setenv("FIBCALC_ALGO", "nope")
+	if code, _ := RunCommand([]string{"fibcalc", "env"}, &bytes.Buffer{}, &bytes.Buffer{}); code != apperrors.ExitErrorConfig {
+		t.Errorf("RunCommand with an invalid variable = %d, want %d", code, apperrors.ExitErrorConfig)
+	}
+}
+
+func TestRunHistory(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	now := time.Now().UTC()
+	err := history.Append(path, []history.Entry{
+		{Timestamp: now.Add(-10 * 24 * time.Hour), Version: "v1", N: 1000, Algorithm: "fft", DurationNs: int64(3 * time.Millisecond)},
+		{Timestamp: now, Version: "v2", N: 2000, Algorithm: "fft", DurationNs: int64(2 * time.Millisecond)},
+		{Timestamp: now, Version: "v2", N: 2000, Algorithm: "fast", DurationNs: int64(time.Millisecond)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout bytes.Buffer
+	code, ok := RunCommand([]string{"fibcalc", "history", "--file", path, "--since", "7d", "--algo", "fft"}, &stdout, &bytes.Buffer{})
+	if !ok || code != apperrors.ExitSuccess {
+		t.Fatalf("RunCommand = (%d, %v), want (0, true)", code, ok)
+	}
+	out := stdout.String()
+	if !strings.Contains(out, "2,000") || strings.Contains(out, "1,000") || strings.Contains(out, "fast") {
+		t.Errorf("history should list only the recent fft run:\n%s", out)
+	}
+	if !strings.Contains(out, "1 of 3 recorded runs") {
+		t.Errorf("history should count the runs:\n%s", out)
+	}
+
+	stdout.Reset()
+	code, _ = RunCommand([]string{"fibcalc", "history", "--file", path, "--sort", "duration", "--json"}, &stdout, &bytes.Buffer{})
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if code != apperrors.ExitSuccess || len(lines) != 3 || !strings.Contains(lines[0], `"algorithm":"fast"`) {
+		t.Errorf("--sort duration --json = %d with:\n%s", code, stdout.String())
+	}
+
+	if code, _ := RunCommand([]string{"fibcalc", "history", "--file", path, "--sort", "speed"}, &bytes.Buffer{}, &bytes.Buffer{}); code != apperrors.ExitErrorConfig {
+		t.Errorf("RunCommand with an unknown sort = %d, want %d", code, apperrors.ExitErrorConfig)
+	}
+}
+
+func TestRunDigits(t *testing.T) {
+	t.Parallel()
+
+	var stdout bytes.Buffer
+	code, ok := RunCommand([]string{"fibcalc", "digits", "--last", "5", "-q", "100"}, &stdout, &bytes.Buffer{})
+	if !ok || code != apperrors.ExitSuccess || stdout.String() != "15075\n" {
+		t.Fatalf("RunCommand = (%d, %v) with %q, want (0, true) with \"15075\\n\"", code, ok, stdout.String())
+	}
+
+	stdout.Reset()
+	if code, _ := RunCommand([]string{"fibcalc", "digits", "-n", "1000000000000"}, &stdout, &bytes.Buffer{}); code != apperrors.ExitSuccess {
+		t.Fatalf("RunCommand for a large N = %d, want 0", code)
+	}
+	if !strings.Contains(stdout.String(), "Partial output: the last 20 of") {
+		t.Errorf("output should be labeled as partial:\n%s", stdout.String())
+	}
+
+	stdout.Reset()
+	RunCommand([]string{"fibcalc", "digits", "10"}, &stdout, &bytes.Buffer{})
+	if !strings.Contains(stdout.String(), "F(10) = 55\n") || !strings.Contains(stdout.String(), "Complete value") {
+		t.Errorf("a value shorter than the window should be printed in full:\n%s", stdout.String())
+	}
+
+	stdout.Reset()
+	RunCommand([]string{"fibcalc", "digits", "--first", "12", "-q", "100"}, &stdout, &bytes.Buffer{})
+	if stdout.String() != "354224848179\n" {
+		t.Errorf("digits --first 12 100 = %q, want \"354224848179\\n\"", stdout.String())
+	}
+
+	for _, args := range [][]string{{"x"}, {"1", "2"}, {"--last", "0", "10"}, {"--first", "-1", "10"}, {"--first", "5", "--last", "5", "10"}, {"--first", "5", "9000000000"}} {
+		code, _ := RunCommand(append([]string{"fibcalc", "digits"}, args...), &bytes.Buffer{}, &bytes.Buffer{})
+		if code != apperrors.ExitErrorConfig {
+			t.Errorf("digits %v = %d, want %d", args, code, apperrors.ExitErrorConfig)
+		}
+	}
+}
+
+func TestRunVerifySignature(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	_, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "result.txt")
+	cfg := cli.OutputConfig{OutputFile: path, SigningKey: key, Version: "v1.2.3"}
+	if err := cli.WriteResultToFile(big.NewInt(55), 10, time.Millisecond, "fast", cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout bytes.Buffer
+	code, ok := RunCommand([]string{"fibcalc", "verify-signature", path}, &stdout, &bytes.Buffer{})
+	if !ok || code != apperrors.ExitSuccess || !strings.Contains(stdout.String(), "OK: F(10) by fast, fibcalc v1.2.3") {
+		t.Fatalf("RunCommand = (%d, %v) with:\n%s", code, ok, stdout.String())
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, bytes.Replace(data, []byte("\n55\n"), []byte("\n56\n"), 1), 0600); err != nil {
+		t.Fatal(err)
+	}
+	stdout.Reset()
+	code, _ = RunCommand([]string{"fibcalc", "verify-signature", path}, &stdout, &bytes.Buffer{})
+	if code != apperrors.ExitErrorMismatch || !strings.Contains(stdout.String(), "FAILED") {
+		t.Errorf("verify-signature of a modified value = %d with:\n%s", code, stdout.String())
+	}
+
+	if code, _ := RunCommand([]string{"fibcalc", "verify-signature"}, &bytes.Buffer{}, &bytes.Buffer{}); code != apperrors.ExitErrorConfig {
+		t.Errorf("verify-signature without files = %d, want %d", code, apperrors.ExitErrorConfig)
+	}
+}
+
+func TestRunCapabilities(t *testing.T) {
+	t.Parallel()
+	var stdout bytes.Buffer
+	code, ok := RunCommand([]string{"fibcalc", "capabilities"}, &stdout, &bytes.Buffer{})
+	if !ok || code != apperrors.ExitSuccess || !strings.Contains(stdout.String(), "mmap") {
+		t.Fatalf("RunCommand = (%d, %v) with:\n%s", code, ok, stdout.String())
+	}
+
+	stdout.Reset()
+	code, _ = RunCommand([]string{"fibcalc", "capabilities", "--json"}, &stdout, &bytes.Buffer{})
+	if code != apperrors.ExitSuccess || !strings.Contains(stdout.String(), `"name": "simd"`) {
+		t.Errorf("capabilities --json = %d with:\n%s", code, stdout.String())
+	}
+}
+
+func TestRunAlgos(t *testing.T) {
+	t.Parallel()
+	var stdout bytes.Buffer
+	code, ok := RunCommand([]string{"fibcalc", "algos"}, &stdout, &bytes.Buffer{})
+	if !ok || code != apperrors.ExitSuccess || !strings.Contains(stdout.String(), "n ≥ 1,000,000,000") {
+		t.Fatalf("RunCommand = (%d, %v) with:\n%s", code, ok, stdout.String())
+	}
+
+	stdout.Reset()
+	code, _ = RunCommand([]string{"fibcalc", "algos", "--json"}, &stdout, &bytes.Buffer{})
+	var infos []fibonacci.AlgorithmInfo
+	if err := json.Unmarshal(stdout.Bytes(), &infos); code != apperrors.ExitSuccess || err != nil {
+		t.Fatalf("algos --json = %d (%v) with:\n%s", code, err, stdout.String())
+	}
+	if len(infos) != len(fibonacci.NewDefaultFactory().List()) || infos[0].Name != "fast" || !infos[0].Parallel {
+		t.Errorf("algos --json = %+v, want every registered calculator, fast first", infos)
+	}
+
+	code, _ = RunCommand([]string{"fibcalc", "algos", "--json", "--markdown"}, &bytes.Buffer{}, &bytes.Buffer{})
+	if code != apperrors.ExitErrorConfig {
+		t.Errorf("algos --json --markdown = %d, want %d", code, apperrors.ExitErrorConfig)
+	}
+}
+
+// TestAlgorithmsDocUpToDate checks that the table of COMPARISON.md is the
+// output of `fibcalc algos --markdown`.
+func TestAlgorithmsDocUpToDate(t *testing.T) {
+	t.Parallel()
+	data, err := os.ReadFile(filepath.Join("..", "..", "docs", "algorithms", "COMPARISON.md"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	var stdout bytes.Buffer
+	if code, _ := RunCommand([]string{"fibcalc", "algos", "--markdown"}, &stdout, &bytes.Buffer{}); code != apperrors.ExitSuccess {
+		t.Fatalf("algos --markdown = %d", code)
+	}
+	if !strings.Contains(string(data), stdout.String()) {
+		t.Errorf("docs/algorithms/COMPARISON.md is out of date; replace its table with the output of fibcalc algos --markdown:\n%s", stdout.String())
+	}
+}
+
+func TestRunCrosscheck(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("the external commands use sh")
+	}
+	var stdout bytes.Buffer
+	code, ok := RunCommand([]string{"fibcalc", "crosscheck", "-n", "1e2", "--external", "echo 'F({n}) = 354,224,848,179,261,915,075'"}, &stdout, &bytes.Buffer{})
+	if !ok || code != apperrors.ExitSuccess || !strings.Contains(stdout.String(), "OK: the values match") {
+		t.Fatalf("RunCommand = (%d, %v) with:\n%s", code, ok, stdout.String())
+	}
+
+	stdout.Reset()
+	var stderr bytes.Buffer
+	code, _ = RunCommand([]string{"fibcalc", "crosscheck", "-n", "100", "--external", "echo 354224848179261915975 # F({n})"}, &stdout, &stderr)
+	if code != apperrors.ExitErrorVerification || !strings.Contains(stdout.String(), "MISMATCH") || !strings.Contains(stdout.String(), "    19\n") {
+		t.Errorf("crosscheck of a wrong value = %d with:\n%s", code, stdout.String())
+	}
+	if !canPrompt(os.Stdin) && !strings.Contains(stderr.String(), "Please report this mismatch at "+bugreport.IssueURL) {
+		t.Errorf("crosscheck of a wrong value does not point to the issue tracker:\n%s", stderr.String())
+	}
+
+	for _, args := range [][]string{
+		{"-n", "100"},
+		{"-n", "1.5", "--external", "echo 1"},
+		{"-n", "100", "--external", "echo 1", "--algo", "nope"},
+	} {
+		if code, _ := RunCommand(append([]string{"fibcalc", "crosscheck"}, args...), &bytes.Buffer{}, &bytes.Buffer{}); code != apperrors.ExitErrorConfig {
+			t.Errorf("crosscheck %v = %d, want %d", args, code, apperrors.ExitErrorConfig)
+		}
+	}
+	if code, _ := RunCommand([]string{"fibcalc", "crosscheck", "-n", "10", "--external", "exit 1"}, &bytes.Buffer{}, &bytes.Buffer{}); code != apperrors.ExitErrorNoReference {
+		t.Errorf("crosscheck with a failing command = %d, want %d", code, apperrors.ExitErrorNoReference)
+	}
+}
+
+func TestRunPlot(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	bench := filepath.Join(dir, "bench.json")
+	var docs bytes.Buffer
+	for _, n := range []uint64{1000, 10000, 100000} {
+		doc := cli.MachineResult{N: n, Runs: []cli.MachineRun{
+			{Algorithm: "fast", DurationNs: int64(n) * 20},
+			{Algorithm: "fft", DurationNs: int64(n) * 100 / int64(n/1000)},
+			{Algorithm: "matrix", ErrorKind: apperrors.ErrorKindTimeout, Error: "timeout"},
+		}}
+		if err := cli.DisplayMachineResult(&docs, doc); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(bench, docs.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout bytes.Buffer
+	code, ok := RunCommand([]string{"fibcalc", "plot", bench, "--height", "8"}, &stdout, &bytes.Buffer{})
+	out := stdout.String()
+	if !ok || code != apperrors.ExitSuccess || !strings.Contains(out, "2 algorithm(s) at 3 index(es)") || strings.Contains(out, "matrix") {
+		t.Fatalf("RunCommand = (%d, %v) with:\n%s", code, ok, out)
+	}
+	if !strings.Contains(out, "overtakes fast between n = 1e3 and 1e4") {
+		t.Errorf("plot does not report the crossover:\n%s", out)
+	}
+
+	svg := filepath.Join(dir, "bench.svg")
+	stdout.Reset()
+	if code, _ := RunCommand([]string{"fibcalc", "plot", "--svg", svg, bench}, &stdout, &bytes.Buffer{}); code != apperrors.ExitSuccess {
+		t.Fatalf("plot --svg = %d with:\n%s", code, stdout.String())
+	}
+	if data, err := os.ReadFile(svg); err != nil || !strings.HasPrefix(string(data), "<svg ") {
+		t.Errorf("plot --svg wrote %q, %v", data, err)
+	}
+
+	empty := filepath.Join(dir, "empty.json")
+	if err := os.WriteFile(empty, []byte("{\"n\": 10, \"runs\": []}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{{}, {bench, bench}, {filepath.Join(dir, "missing.json")}, {empty}} {
+		if code, _ := RunCommand(append([]string{"fibcalc", "plot"}, args...), &bytes.Buffer{}, &bytes.Buffer{}); code != apperrors.ExitErrorConfig {
+			t.Errorf("plot %v = %d, want %d", args, code, apperrors.ExitErrorConfig)
+		}
+	}
+}
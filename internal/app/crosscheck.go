@@ -0,0 +1,151 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/config"
+	"github.com/agbru/fibcalc/internal/crosscheck"
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/format"
+	"github.com/agbru/fibcalc/internal/orchestration"
+)
+
+// maxReportedRanges bounds the runs of differing digits crosscheck lists.
+const maxReportedRanges = 10
+
+// mismatchContext is the number of digits shown on each side of the first
+// difference.
+const mismatchContext = 10
+
+// runCrosscheck runs an external program for F(n) and compares its output
+// with fibcalc's result, reporting where they differ. It exits with
+// ExitErrorVerification if they do, and with ExitErrorNoReference if the
+// program gives no value.
+func runCrosscheck(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("crosscheck", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	nSpec := fs.String("n", "", "Index N, as an integer, a power (10^6) or an exponent (1e6).")
+	external := fs.String("external", "", "Command printing F(N), run by the shell; {n} is replaced by N, else N is appended.")
+	algo := fs.String("algo", "fast", "Algorithm computing the reference value.")
+	timeout := fs.Duration("timeout", config.DefaultTimeout, "Maximum time of each calculation.")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return apperrors.ExitSuccess
+		}
+		return apperrors.ExitErrorConfig
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "Error: unexpected argument %q.\n", fs.Arg(0))
+		return apperrors.ExitErrorConfig
+	}
+	if *nSpec == "" || *external == "" {
+		fmt.Fprintln(stderr, "Error: crosscheck needs -n and --external.")
+		return apperrors.ExitErrorConfig
+	}
+	n, err := config.ParseIndex(*nSpec)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: -n: %v.\n", err)
+		return apperrors.ExitErrorConfig
+	}
+	calc, err := fibonacci.NewDefaultFactory().Get(*algo)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: --algo: %v\n", err)
+		return apperrors.ExitErrorConfig
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	calcCtx, cancel := context.WithTimeout(ctx, *timeout)
+	start := time.Now()
+	result, err := calc.Calculate(calcCtx, nil, 0, n, fibonacci.Options{})
+	refElapsed := time.Since(start)
+	cancel()
+	if err != nil {
+		return apperrors.HandleCalculationError(err, refElapsed, stderr, nil)
+	}
+	ref := result.String()
+	index := format.FormatNumberString(strconv.FormatUint(n, 10))
+	fmt.Fprintf(stdout, "fibcalc:  F(%s) by %s, %s digits in %s\n", index, calc.Name(),
+		format.FormatNumberString(strconv.Itoa(len(ref))), format.FormatExecutionDuration(refElapsed))
+
+	extCtx, cancel := context.WithTimeout(ctx, *timeout)
+	defer cancel()
+	start = time.Now()
+	ext, err := crosscheck.Run(extCtx, *external, n, stderr)
+	extElapsed := time.Since(start)
+	if err != nil {
+		if extCtx.Err() != nil {
+			return apperrors.HandleCalculationError(err, extElapsed, stderr, nil)
+		}
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return apperrors.ExitErrorNoReference
+	}
+	fmt.Fprintf(stdout, "external: %s, %s digits in %s\n", crosscheck.Expand(*external, n),
+		format.FormatNumberString(strconv.Itoa(len(ext))), format.FormatExecutionDuration(extElapsed))
+
+	c := crosscheck.Compare(ref, ext)
+	if c.Equal {
+		fmt.Fprintln(stdout, "OK: the values match.")
+		return apperrors.ExitSuccess
+	}
+	writeMismatch(stdout, ref, ext, c)
+	if value, ok := new(big.Int).SetString(ext, 10); ok {
+		a := &Application{
+			Config:    config.AppConfig{N: n, Algo: *algo, Timeout: *timeout},
+			Args:      append([]string{"crosscheck"}, args...),
+			ErrWriter: stderr,
+		}
+		a.offerBugReport([]orchestration.CalculationResult{
+			{Name: calc.Name(), Result: result, Duration: refElapsed},
+			{Name: "external", Result: value, Duration: extElapsed},
+		})
+	}
+	return apperrors.ExitErrorVerification
+}
+
+// writeMismatch reports where the reference and the external value differ.
+func writeMismatch(w io.Writer, ref, ext string, c crosscheck.Comparison) {
+	fmt.Fprintln(w, "MISMATCH: the values differ.")
+	if c.Digits != c.ExternalDigits {
+		fmt.Fprintf(w, "  Lengths: %s digits (fibcalc) vs %s (external)\n",
+			format.FormatNumberString(strconv.Itoa(c.Digits)), format.FormatNumberString(strconv.Itoa(c.ExternalDigits)))
+	}
+	fmt.Fprintf(w, "  Common leading digits: %s, common trailing digits: %s\n",
+		format.FormatNumberString(strconv.Itoa(c.Prefix)), format.FormatNumberString(strconv.Itoa(c.Suffix)))
+	if len(c.Ranges) > 0 {
+		fmt.Fprintf(w, "  Differing digits: %s in %d range(s), by position from the most significant digit:\n",
+			format.FormatNumberString(strconv.Itoa(c.Differing)), len(c.Ranges))
+		for _, r := range c.Ranges[:min(len(c.Ranges), maxReportedRanges)] {
+			if r.First == r.Last {
+				fmt.Fprintf(w, "    %d\n", r.First)
+			} else {
+				fmt.Fprintf(w, "    %d-%d\n", r.First, r.Last)
+			}
+		}
+		if len(c.Ranges) > maxReportedRanges {
+			fmt.Fprintf(w, "    ... and %d more\n", len(c.Ranges)-maxReportedRanges)
+		}
+	}
+
+	// The digits around the first difference
+	lo := max(0, c.Prefix-mismatchContext)
+	excerpt := func(s string) string {
+		hi := min(len(s), c.Prefix+mismatchContext+1)
+		if lo >= hi {
+			return "(end)"
+		}
+		return s[lo:hi]
+	}
+	fmt.Fprintf(w, "  From digit %d:\n    fibcalc:  %s\n    external: %s\n", lo+1, excerpt(ref), excerpt(ext))
+}
@@ -0,0 +1,45 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/agbru/fibcalc/internal/cli"
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/ui"
+)
+
+// runExplain computes F(n) with fast doubling, and with matrix
+// exponentiation for --algo matrix or all, printing each step (--explain).
+func (a *Application) runExplain(ctx context.Context, out io.Writer) int {
+	type trace struct {
+		algo    string
+		explain func(io.Writer, uint64) fibonacci.StepHook
+	}
+	traces := []trace{{"fast", cli.ExplainDoubling}}
+	switch a.Config.Algo {
+	case "matrix":
+		traces = []trace{{"matrix", cli.ExplainMatrix}}
+	case "all":
+		traces = append(traces, trace{"matrix", cli.ExplainMatrix})
+	}
+
+	n := a.Config.N
+	for _, t := range traces {
+		calc, err := a.Factory.Get(t.algo)
+		if err != nil {
+			fmt.Fprintf(a.ErrWriter, "Error: %v\n", err)
+			return apperrors.ExitErrorConfig
+		}
+		// The tables would skip the steps
+		opts := fibonacci.Options{DisableTables: true, OnStep: t.explain(out, n)}
+		result, err := calc.Calculate(ctx, nil, 0, n, opts)
+		if err != nil {
+			return apperrors.HandleCalculationError(err, 0, a.ErrWriter, cli.CLIColorProvider{})
+		}
+		fmt.Fprintf(out, "%sResult: F(%d) = %s%s\n", ui.ColorBold(), n, result, ui.ColorReset())
+	}
+	return apperrors.ExitSuccess
+}
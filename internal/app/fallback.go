@@ -0,0 +1,94 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/agbru/fibcalc/internal/config"
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/orchestration"
+	"github.com/agbru/fibcalc/internal/ui"
+)
+
+// retriable reports whether another algorithm may succeed where res
+// failed. A timeout or a cancellation would end the next attempt too.
+func retriable(res orchestration.CalculationResult) bool {
+	switch res.ErrorKind() {
+	case apperrors.ErrorKindNone, apperrors.ErrorKindTimeout, apperrors.ErrorKindCanceled:
+		return false
+	}
+	return true
+}
+
+// fallbackOrder returns the algorithms --fallback tries, by registered
+// name, without the one that ran first.
+//
+// Parameters:
+//   - first: The registered name of the algorithm that failed.
+//
+// Returns:
+//   - []string: The algorithms to try, in order.
+func (a *Application) fallbackOrder(first string) []string {
+	order := a.Config.FallbackOrder()
+	if a.Config.Fallback == config.FallbackAuto {
+		model, _ := a.costModel()
+		costs := model.Select(a.Config.N, a.Factory.List()).Costs
+		for name := range costs {
+			order = append(order, name)
+		}
+		sort.Slice(order, func(i, j int) bool {
+			if costs[order[i]] != costs[order[j]] {
+				return costs[order[i]] < costs[order[j]]
+			}
+			return order[i] < order[j]
+		})
+	}
+	return slices.DeleteFunc(order, func(name string) bool { return name == first })
+}
+
+// runWithFallback runs the calculator and, with --fallback, the next
+// algorithms of the order while the previous one failed in a way another
+// algorithm may avoid. Each fallback is announced on ErrWriter and
+// recorded in a.fallbacks.
+//
+// Parameters:
+//   - calculators: The calculators of the run.
+//   - execute: Runs calculators and returns their results.
+//
+// Returns:
+//   - []orchestration.CalculationResult: The results of every attempt, the
+//     failures included, so that the results table shows them.
+func (a *Application) runWithFallback(calculators []fibonacci.Calculator, execute func([]fibonacci.Calculator) []orchestration.CalculationResult) []orchestration.CalculationResult {
+	results := execute(calculators)
+	if a.Config.Fallback == "" || len(results) != 1 || !retriable(results[0]) {
+		return results
+	}
+
+	keys := a.algorithmKeys()
+	key := func(res orchestration.CalculationResult) string {
+		if k := keys[res.Name]; k != "" {
+			return k
+		}
+		return res.Name
+	}
+	failed := results[0]
+	for _, name := range a.fallbackOrder(key(failed)) {
+		calc, err := a.Factory.Get(name)
+		if err != nil {
+			continue
+		}
+		a.fallbacks = append(a.fallbacks, key(failed))
+		// The error itself is shown in the results table
+		fmt.Fprintf(a.ErrWriter, "%sWarning: %s failed (%s); falling back to %s.%s\n",
+			ui.ColorYellow(), key(failed), failed.ErrorKind(), name, ui.ColorReset())
+		attempt := execute([]fibonacci.Calculator{calc})
+		results = append(results, attempt...)
+		if len(attempt) != 1 || !retriable(attempt[0]) {
+			break
+		}
+		failed = attempt[0]
+	}
+	return results
+}
@@ -0,0 +1,102 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/config"
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/fibtest"
+	"github.com/agbru/fibcalc/internal/testutil"
+)
+
+// fallbackApp returns an application running "fast", which fails with
+// fastErr, with --fallback set to order.
+func fallbackApp(fastErr error, order string) (*Application, *fibtest.Calculator, *bytes.Buffer) {
+	matrix := fibtest.Fixed("Matrix", nil)
+	var errBuf bytes.Buffer
+	app := &Application{
+		Config: config.AppConfig{
+			N:        20,
+			Algo:     "fast",
+			Fallback: order,
+			Timeout:  time.Minute,
+		},
+		Factory: fibonacci.NewTestFactory(map[string]fibonacci.Calculator{
+			"fast":   fibtest.Failing("Fast", fastErr, 0),
+			"matrix": matrix,
+			"fft":    fibtest.Failing("FFT", errors.New("unreachable"), 0),
+		}),
+		ErrWriter: &errBuf,
+	}
+	return app, matrix, &errBuf
+}
+
+func TestRunWithFallback(t *testing.T) {
+	t.Parallel()
+	app, matrix, errBuf := fallbackApp(errors.New("boom"), "matrix,fft")
+	var out bytes.Buffer
+
+	if code := app.runCalculate(context.Background(), &out); code != apperrors.ExitSuccess {
+		t.Fatalf("runCalculate() = %d, want %d:\n%s", code, apperrors.ExitSuccess, out.String())
+	}
+	if matrix.Calls() != 1 {
+		t.Errorf("matrix ran %d times, want 1", matrix.Calls())
+	}
+	if !slices.Equal(app.fallbacks, []string{"fast"}) {
+		t.Errorf("fallbacks = %v, want [fast]", app.fallbacks)
+	}
+	if len(app.results) != 2 || app.outcome == nil || app.outcome.Name != "Matrix" {
+		t.Errorf("expected the failure and the fallback result, got %+v", app.results)
+	}
+	if got := errBuf.String(); !strings.Contains(got, "fast failed (internal); falling back to matrix") {
+		t.Errorf("fallback not announced on stderr:\n%s", got)
+	}
+	if got := testutil.StripAnsiCodes(out.String()); !strings.Contains(got, "computed by matrix after falling back from fast") {
+		t.Errorf("fallback not noted with the result:\n%s", got)
+	}
+
+	doc := app.machineResult(apperrors.ExitSuccess)
+	// The value is streamed from Result when the document is written
+	if doc.Result == nil || doc.Result.String() != "6765" || !slices.Equal(doc.Fallbacks, []string{"fast"}) {
+		t.Errorf("machine document = %+v, want F(20) with fallbacks [fast]", doc)
+	}
+	// One run per algorithm tried, the failed one included
+	if len(doc.Runs) != 2 || doc.Runs[0].Error != "" || doc.Runs[0].ResultBits != 13 || doc.Runs[0].ResultDigits != 4 ||
+		doc.Runs[1].Error == "" || doc.Runs[1].ResultDigits != 0 {
+		t.Errorf("machine document runs = %+v, want F(20) from matrix, then the failure of fast", doc.Runs)
+	}
+}
+
+func TestRunWithFallback_NotOnTimeout(t *testing.T) {
+	t.Parallel()
+	app, matrix, _ := fallbackApp(context.DeadlineExceeded, "matrix")
+
+	if code := app.runCalculate(context.Background(), &bytes.Buffer{}); code == apperrors.ExitSuccess {
+		t.Error("runCalculate() succeeded, want the timeout")
+	}
+	if matrix.Calls() != 0 || len(app.fallbacks) != 0 {
+		t.Errorf("a timeout should not fall back (matrix ran %d times)", matrix.Calls())
+	}
+}
+
+func TestFallbackOrder(t *testing.T) {
+	t.Parallel()
+	app, _, _ := fallbackApp(nil, "matrix, fast ,fft")
+	if got := app.fallbackOrder("fast"); !slices.Equal(got, []string{"matrix", "fft"}) {
+		t.Errorf("fallbackOrder() = %v, want [matrix fft]", got)
+	}
+
+	app.Config.Fallback = config.FallbackAuto
+	app.Config.N = 10_000_000
+	got := app.fallbackOrder("fast")
+	if len(got) != 2 || slices.Contains(got, "fast") {
+		t.Errorf("fallbackOrder(auto) = %v, want the two other modeled algorithms", got)
+	}
+}
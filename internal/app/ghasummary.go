@@ -0,0 +1,35 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/agbru/fibcalc/internal/cli"
+	"github.com/agbru/fibcalc/internal/fsguard"
+)
+
+// ghaSummaryEnv names the job summary file of a GitHub Actions step.
+const ghaSummaryEnv = "GITHUB_STEP_SUMMARY"
+
+// writeGHASummary appends the results of the last calculation to the
+// GitHub Actions job summary, if fibcalc runs in a step. Failures are
+// reported on ErrWriter but never change the exit code.
+func (a *Application) writeGHASummary() {
+	path := os.Getenv(ghaSummaryEnv)
+	if path == "" || len(a.results) == 0 {
+		return
+	}
+	f, err := fsguard.OpenFile(filepath.Clean(path), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		fmt.Fprintf(a.ErrWriter, "Warning: failed to open the job summary: %v\n", err)
+		return
+	}
+	err = cli.WriteGHASummary(f, cli.GHASummary{N: a.Config.N, Version: Version, Results: a.results})
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		fmt.Fprintf(a.ErrWriter, "Warning: failed to write the job summary: %v\n", err)
+	}
+}
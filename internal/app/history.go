@@ -0,0 +1,143 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/config"
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/format"
+	"github.com/agbru/fibcalc/internal/history"
+)
+
+// defaultHistoryLimit is the number of runs `fibcalc history` lists
+// without --limit.
+const defaultHistoryLimit = 20
+
+// runHistory lists past runs from the history database, filtered and
+// sorted by its flags, as a table or as JSON Lines.
+func runHistory(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	since := fs.String("since", "", "Only runs at most this old (e.g. 36h, 7d, 2w).")
+	algo := fs.String("algo", "", "Only runs of this algorithm.")
+	n := fs.Uint64("n", 0, "Only runs for this index.")
+	sortBy := fs.String("sort", history.SortTime, "Order: "+strings.Join(history.SortOrders, ", ")+".")
+	limit := fs.Int("limit", defaultHistoryLimit, "Maximum number of runs listed (0 for all).")
+	failed := fs.Bool("failed", false, "Include the failed runs.")
+	file := fs.String("file", historyPath(os.Getenv(config.EnvPrefix+"HISTORY_FILE")), "History database path.")
+	asJSON := fs.Bool("json", false, "Print the runs as JSON Lines.")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return apperrors.ExitSuccess
+		}
+		return apperrors.ExitErrorConfig
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "Error: unexpected argument %q.\n", fs.Arg(0))
+		return apperrors.ExitErrorConfig
+	}
+
+	q := history.Query{N: *n, Sort: *sortBy, Limit: *limit, Failed: *failed}
+	if *since != "" {
+		age, err := history.ParseAge(*since)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: --since: %v.\n", err)
+			return apperrors.ExitErrorConfig
+		}
+		q.Since = age
+	}
+	if *algo != "" {
+		// Aliases are accepted; unknown names may be removed calculators
+		q.Algorithm = *algo
+		if canonical, err := fibonacci.NewDefaultFactory().Resolve(*algo); err == nil {
+			q.Algorithm = canonical
+		}
+	}
+
+	entries, err := history.Load(*file)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return apperrors.ExitErrorGeneric
+	}
+	selected, err := q.Apply(entries, time.Now())
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: --sort: %v.\n", err)
+		return apperrors.ExitErrorConfig
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		for _, e := range selected {
+			if err := enc.Encode(e); err != nil {
+				fmt.Fprintf(stderr, "Error: %v\n", err)
+				return apperrors.ExitErrorGeneric
+			}
+		}
+		return apperrors.ExitSuccess
+	}
+	if len(selected) == 0 {
+		fmt.Fprintf(stdout, "No matching runs in %s.\n", *file)
+		return apperrors.ExitSuccess
+	}
+	writeHistoryTable(stdout, selected)
+	fmt.Fprintf(stdout, "\n%d of %d recorded runs.\n", len(selected), len(entries))
+	return apperrors.ExitSuccess
+}
+
+// historyPath returns the history database to use: path, or
+// history.DefaultPath() when it is empty.
+func historyPath(path string) string {
+	if path == "" {
+		return history.DefaultPath()
+	}
+	return path
+}
+
+// writeHistoryTable prints history entries as an aligned table.
+func writeHistoryTable(w io.Writer, entries []history.Entry) {
+	header := []string{"When", "Version", "N", "Algorithm", "Duration", "Status"}
+	rows := [][]string{header}
+	for _, e := range entries {
+		status := "ok"
+		if e.ErrorKind != apperrors.ErrorKindNone {
+			status = e.ErrorKind.String()
+		}
+		rows = append(rows, []string{
+			e.Timestamp.Local().Format("2006-01-02 15:04"),
+			e.Version,
+			format.FormatNumberString(strconv.FormatUint(e.N, 10)),
+			e.Algorithm,
+			format.FormatExecutionDuration(e.Duration()),
+			status,
+		})
+	}
+	widths := make([]int, len(header))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len(cell))
+		}
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			if i == len(row)-1 {
+				fmt.Fprintln(w, cell)
+				continue
+			}
+			// N is right-aligned, the other columns left-aligned
+			if i == 2 {
+				fmt.Fprintf(w, "%*s   ", widths[i], cell)
+			} else {
+				fmt.Fprintf(w, "%-*s   ", widths[i], cell)
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/fibonacci"
+)
+
+// completionEnv returns the variables describing the run to the
+// --exec-on-complete command:
+//
+//	FIB_N            the index
+//	FIB_DURATION_MS  the duration of the calculation reported, in whole
+//	                 milliseconds (of the whole run if none was)
+//	FIB_DIGITS       the number of decimal digits of F(n), empty if the
+//	                 calculation failed
+//	FIB_OUTPUT_FILE  the --output file, empty if none
+//	FIB_EXIT         the exit code of fibcalc
+func (a *Application) completionEnv(start time.Time, exitCode int) []string {
+	duration := time.Since(start)
+	digits := ""
+	if a.outcome != nil {
+		duration = a.outcome.Duration
+		if a.outcome.Err == nil && a.outcome.Result != nil {
+			digits = strconv.FormatUint(fibonacci.DecimalDigits(a.Config.N), 10)
+		}
+	}
+	return []string{
+		"FIB_N=" + strconv.FormatUint(a.Config.N, 10),
+		"FIB_DURATION_MS=" + strconv.FormatInt(duration.Milliseconds(), 10),
+		"FIB_DIGITS=" + digits,
+		"FIB_OUTPUT_FILE=" + a.Config.OutputFile,
+		"FIB_EXIT=" + strconv.Itoa(exitCode),
+	}
+}
+
+// execOnComplete runs the --exec-on-complete command with the shell (sh,
+// or cmd on Windows), its environment extended with completionEnv, once
+// the run is over, whether it succeeded or not. Its output goes to
+// ErrWriter, to keep stdout for the results. It still runs after an
+// interruption. Failures are reported on ErrWriter but never change the
+// exit code.
+func (a *Application) execOnComplete(ctx context.Context, start time.Time, exitCode int) {
+	ctx = context.WithoutCancel(ctx)
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", a.Config.ExecOnComplete)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", a.Config.ExecOnComplete)
+	}
+	cmd.Env = append(os.Environ(), a.completionEnv(start, exitCode)...)
+	cmd.Stdout = a.ErrWriter
+	cmd.Stderr = a.ErrWriter
+	if err := cmd.Run(); err != nil {
+		fmt.Fprintf(a.ErrWriter, "Warning: --exec-on-complete command failed: %v\n", err)
+	}
+}
@@ -0,0 +1,96 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/agbru/fibcalc/internal/audit"
+	"github.com/agbru/fibcalc/internal/cli"
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+)
+
+// runMachine runs the calculation with --machine: the usual output goes to
+// stderr, and stdout receives only the JSON result document.
+//
+// Parameters:
+//   - ctx: The context of the run.
+//   - stdout: The writer of the document.
+//
+// Returns:
+//   - int: The exit code of the calculation.
+func (a *Application) runMachine(ctx context.Context, stdout io.Writer) int {
+	exitCode := a.runCalculate(ctx, a.ErrWriter)
+	if err := cli.DisplayMachineResult(stdout, a.machineResult(exitCode)); err != nil {
+		fmt.Fprintf(a.ErrWriter, "Error writing the result document: %v\n", err)
+		if exitCode == apperrors.ExitSuccess {
+			return apperrors.ExitErrorGeneric
+		}
+	}
+	return exitCode
+}
+
+// runMachineSchema prints the --machine-schema output.
+func (a *Application) runMachineSchema(out io.Writer) int {
+	if _, err := out.Write(cli.MachineSchema()); err != nil {
+		fmt.Fprintf(a.ErrWriter, "Error writing the schema: %v\n", err)
+		return apperrors.ExitErrorGeneric
+	}
+	return apperrors.ExitSuccess
+}
+
+// machineResult builds the --machine document of the run that ended with
+// exitCode.
+func (a *Application) machineResult(exitCode int) cli.MachineResult {
+	doc := cli.MachineResult{Version: Version, N: a.Config.N, ExitCode: exitCode, Fallbacks: a.fallbacks}
+	if a.outcome != nil && a.outcome.Result != nil {
+		doc.Algorithm = a.outcome.Name
+		doc.ResultSHA256 = audit.HashResult(a.outcome.Result)
+		if k := a.Config.LastDigits; k > 0 {
+			doc.LastDigits = k
+			doc.Value = fmt.Sprintf("%0*s", k, a.outcome.Result.String())
+		} else {
+			// Streamed by DisplayMachineResult
+			doc.Result = a.outcome.Result
+		}
+	}
+
+	if u := a.usage; u != nil {
+		doc.Resources = &cli.MachineResources{
+			UserCPUNs:                  u.UserCPU.Nanoseconds(),
+			SystemCPUNs:                u.SystemCPU.Nanoseconds(),
+			MaxRSSBytes:                u.MaxRSS,
+			VoluntaryContextSwitches:   u.VoluntaryCtxSwitches,
+			InvoluntaryContextSwitches: u.InvoluntaryCtxSwitches,
+		}
+	}
+
+	// Results carry display names; the document also gives registered names
+	keys := a.algorithmKeys()
+	// The runs usually agree: count the digits of each value once
+	digits := make(map[string]int)
+	for _, res := range a.results {
+		run := cli.MachineRun{
+			Algorithm:  keys[res.Name],
+			Name:       res.Name,
+			DurationNs: res.Duration.Nanoseconds(),
+			ErrorKind:  res.ErrorKind(),
+		}
+		if run.Algorithm == "" {
+			run.Algorithm = res.Name
+		}
+		if res.Result != nil {
+			run.ResultBits = res.Result.BitLen()
+			run.ResultSHA256 = audit.HashResult(res.Result)
+			if _, ok := digits[run.ResultSHA256]; !ok {
+				digits[run.ResultSHA256] = cli.DecimalDigits(res.Result)
+			}
+			run.ResultDigits = digits[run.ResultSHA256]
+		}
+		if res.Err != nil {
+			run.Error = res.Err.Error()
+		}
+		doc.Runs = append(doc.Runs, run)
+	}
+	return doc
+}
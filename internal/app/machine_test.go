@@ -0,0 +1,88 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/audit"
+	"github.com/agbru/fibcalc/internal/cli"
+	"github.com/agbru/fibcalc/internal/config"
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/sysmon"
+	"github.com/agbru/fibcalc/internal/testutil"
+)
+
+func TestRunMachine(t *testing.T) {
+	t.Parallel()
+	var stdout, stderr bytes.Buffer
+	app := &Application{
+		Config: config.AppConfig{
+			N:       10,
+			Algo:    "all",
+			Timeout: time.Minute,
+			Machine: true,
+		},
+		Factory:   createMockFactory(big.NewInt(55), nil),
+		ErrWriter: &stderr,
+	}
+
+	if code := app.Run(context.Background(), &stdout); code != apperrors.ExitSuccess {
+		t.Fatalf("Run() = %d, want %d; stderr:\n%s", code, apperrors.ExitSuccess, stderr.String())
+	}
+
+	// stdout holds the document alone, on one line
+	if lines := strings.Count(stdout.String(), "\n"); lines != 1 {
+		t.Fatalf("stdout has %d lines, want the document only:\n%s", lines, stdout.String())
+	}
+	var doc cli.MachineResult
+	if err := json.Unmarshal(stdout.Bytes(), &doc); err != nil {
+		t.Fatalf("stdout is not a JSON document: %v\n%s", err, stdout.String())
+	}
+	if doc.N != 10 || doc.Value != "55" || doc.ExitCode != 0 || len(doc.Runs) != 3 || doc.ResultSHA256 != audit.HashResult(big.NewInt(55)) {
+		t.Errorf("document = %+v, want F(10) = 55 from 3 runs", doc)
+	}
+	for _, run := range doc.Runs {
+		if run.Algorithm == "" || run.ResultBits != 6 || run.Error != "" || run.ResultSHA256 != doc.ResultSHA256 {
+			t.Errorf("run = %+v, want a successful 6-bit result", run)
+		}
+	}
+	if _, err := sysmon.ReadUsage(); err == nil && (doc.Resources == nil || doc.Resources.MaxRSSBytes == 0) {
+		t.Errorf("resources = %+v, want the resource usage of the run", doc.Resources)
+	}
+
+	if got := testutil.StripAnsiCodes(stderr.String()); !strings.Contains(got, "Comparison Summary") {
+		t.Errorf("stderr should hold the human-readable output:\n%s", got)
+	}
+}
+
+func TestRunMachine_LastDigits(t *testing.T) {
+	t.Parallel()
+	var stdout bytes.Buffer
+	app := &Application{
+		Config: config.AppConfig{
+			N:          15,
+			LastDigits: 5,
+			Timeout:    time.Minute,
+			Machine:    true,
+		},
+		Factory:   createMockFactory(nil, nil),
+		ErrWriter: &bytes.Buffer{},
+	}
+
+	if code := app.Run(context.Background(), &stdout); code != apperrors.ExitSuccess {
+		t.Fatalf("Run() = %d, want %d", code, apperrors.ExitSuccess)
+	}
+	var doc cli.MachineResult
+	if err := json.Unmarshal(stdout.Bytes(), &doc); err != nil {
+		t.Fatalf("stdout is not a JSON document: %v\n%s", err, stdout.String())
+	}
+	// F(15) = 610, zero-padded to the 5 digits requested
+	if doc.Value != "00610" || doc.LastDigits != 5 {
+		t.Errorf("document = %+v, want value 00610 with last_digits 5", doc)
+	}
+}
@@ -0,0 +1,81 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/config"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/orchestration"
+	"github.com/agbru/fibcalc/internal/push"
+)
+
+// metricsPushTokenEnv holds the API token of the metrics backend. It is
+// read from the environment only, so that it never appears in the process
+// arguments or in the configuration recorded by the audit log.
+const metricsPushTokenEnv = config.EnvPrefix + "METRICS_PUSH_TOKEN"
+
+// newPusher creates the pusher for Config.MetricsPush.
+func (a *Application) newPusher() *push.Pusher {
+	format, _ := push.ParseFormat(a.Config.MetricsPushFormat) // validated by ParseConfig
+	return push.New(a.Config.MetricsPush, format, os.Getenv(metricsPushTokenEnv))
+}
+
+// progressPusher wraps inner so that live gauges of the calculators are
+// pushed while it displays the progress.
+func (a *Application) progressPusher(inner orchestration.ProgressReporter, calculators []fibonacci.Calculator) orchestration.ProgressReporter {
+	keys := a.algorithmKeys()
+	names := make([]string, len(calculators))
+	for i, calc := range calculators {
+		if names[i] = keys[calc.Name()]; names[i] == "" {
+			names[i] = calc.Name()
+		}
+	}
+	return &push.Reporter{
+		Inner:      inner,
+		Pusher:     a.pusher,
+		Interval:   a.Config.MetricsPushInterval,
+		Algorithms: names,
+		N:          a.Config.N,
+	}
+}
+
+// pushRunMetrics pushes the summary of the last calculation, then reports
+// the first push failure of the run on ErrWriter. Failures never change
+// the exit code.
+func (a *Application) pushRunMetrics() {
+	a.pushResults()
+	if err := a.pusher.Err(); err != nil {
+		fmt.Fprintf(a.ErrWriter, "Warning: %v\n", err)
+	}
+}
+
+// pushResults pushes the summary of the last calculation, if any. The
+// pusher keeps the first failure for pushRunMetrics to report.
+func (a *Application) pushResults() {
+	if len(a.results) == 0 {
+		return
+	}
+	keys := a.algorithmKeys()
+	names := make([]string, len(a.results))
+	for i, res := range a.results {
+		names[i] = keys[res.Name]
+	}
+	samples := push.RunSamples(a.results, names, a.Config.N, Version, time.Now())
+	_ = a.pusher.Push(context.Background(), samples)
+}
+
+// algorithmKeys maps the display names of the factory's calculators, which
+// results carry, to their registered names.
+func (a *Application) algorithmKeys() map[string]string {
+	keys := make(map[string]string)
+	if a.Factory == nil {
+		return keys
+	}
+	for key, calc := range a.Factory.GetAll() {
+		keys[calc.Name()] = key
+	}
+	return keys
+}
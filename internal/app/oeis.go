@@ -0,0 +1,75 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+
+	"github.com/agbru/fibcalc/internal/config"
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/oeis"
+	"github.com/agbru/fibcalc/internal/orchestration"
+	"github.com/agbru/fibcalc/internal/ui"
+)
+
+// oeisFileEnv overrides the cached b-file. It is read from the environment
+// only, like the other state files.
+const oeisFileEnv = config.EnvPrefix + "OEIS_FILE"
+
+// oeisBFileURL is the b-file downloaded by --oeis; tests point it to a
+// local server.
+var oeisBFileURL = oeis.BFileURL
+
+// oeisPath returns the cached b-file.
+func oeisPath() string {
+	if path := os.Getenv(oeisFileEnv); path != "" {
+		return path
+	}
+	return oeis.DefaultPath()
+}
+
+// checkOEIS compares the result with the term of the OEIS b-file (--oeis).
+// An index the b-file does not list is reported but leaves the exit code
+// alone.
+//
+// Parameters:
+//   - ctx: The context of the b-file download.
+//   - res: The result to check.
+//   - out: The writer of the report, nil in quiet mode.
+//
+// Returns:
+//   - int: ExitErrorVerification if the values differ, ExitErrorNoReference
+//     if the b-file cannot be obtained, else ExitSuccess.
+func (a *Application) checkOEIS(ctx context.Context, res *orchestration.CalculationResult, out io.Writer) int {
+	if out == nil {
+		out = io.Discard
+	}
+	b, downloaded, err := oeis.Open(ctx, oeisBFileURL, oeisPath())
+	if err != nil {
+		fmt.Fprintf(a.ErrWriter, "Error: --oeis: %v\n", err)
+		return apperrors.ExitErrorNoReference
+	}
+	if downloaded {
+		fmt.Fprintf(out, "OEIS %s: b-file downloaded to %s (%d terms).\n", oeis.SequenceID, oeisPath(), b.Len())
+	}
+
+	n := a.Config.N
+	term, ok := b.Term(n)
+	if !ok {
+		fmt.Fprintf(out, "OEIS %s: the b-file lists a(%d)..a(%d), not F(%d); not checked.\n", oeis.SequenceID, b.First, b.Last, n)
+		return apperrors.ExitSuccess
+	}
+	if term != res.Result.String() {
+		fmt.Fprintf(a.ErrWriter, "%sOEIS %s: F(%d) differs from a(%d) in the b-file %s.%s\n",
+			ui.ColorRed(), oeis.SequenceID, n, n, oeisPath(), ui.ColorReset())
+		if value, ok := new(big.Int).SetString(term, 10); ok {
+			a.offerBugReport([]orchestration.CalculationResult{*res, {Name: "OEIS " + oeis.SequenceID + " b-file", Result: value}})
+		}
+		return apperrors.ExitErrorVerification
+	}
+	fmt.Fprintf(out, "%sOEIS %s: F(%d) matches a(%d) of the b-file.%s See %s\n",
+		ui.ColorGreen(), oeis.SequenceID, n, n, ui.ColorReset(), oeis.URL)
+	return apperrors.ExitSuccess
+}
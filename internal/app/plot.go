@@ -0,0 +1,173 @@
+package app
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/cli"
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/fsguard"
+	"github.com/agbru/fibcalc/internal/plot"
+	"github.com/agbru/fibcalc/internal/ui"
+)
+
+// Default size of the text chart of plot, in characters.
+const (
+	defaultPlotWidth  = 64
+	defaultPlotHeight = 16
+)
+
+// plotTitle is the title of the charts.
+const plotTitle = "fibcalc: duration vs n"
+
+// runPlot charts the durations of the --machine documents of a file against
+// n, one curve per algorithm, as text or with --svg as an SVG file, and
+// lists the crossovers. The file may come before or after the flags; "-"
+// reads stdin.
+func runPlot(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("plot", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	svgPath := fs.String("svg", "", "Write the chart to this SVG file instead of printing it.")
+	width := fs.Int("width", defaultPlotWidth, "Width of the text chart, in characters.")
+	height := fs.Int("height", defaultPlotHeight, "Height of the text chart, in lines.")
+	var files []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return apperrors.ExitSuccess
+			}
+			return apperrors.ExitErrorConfig
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		files = append(files, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(files) != 1 {
+		fmt.Fprintln(stderr, "Error: plot takes a single file of --machine documents (- for stdin).")
+		return apperrors.ExitErrorConfig
+	}
+
+	in := io.Reader(os.Stdin)
+	if files[0] != "-" {
+		f, err := os.Open(files[0])
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return apperrors.ExitErrorConfig
+		}
+		defer f.Close()
+		in = f
+	}
+	series, indexes, err := loadPlotSeries(in)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %s: %v\n", files[0], err)
+		return apperrors.ExitErrorConfig
+	}
+
+	if *svgPath != "" {
+		if err := writeSVGFile(*svgPath, series); err != nil {
+			fmt.Fprintf(stderr, "Error: --svg: %v\n", err)
+			return apperrors.ExitErrorGeneric
+		}
+		fmt.Fprintf(stdout, "Chart of %d algorithm(s) at %d index(es) written to %s.\n", len(series), indexes, *svgPath)
+	} else {
+		fmt.Fprintf(stdout, "%s%s (%d algorithm(s) at %d index(es))%s\n", ui.ColorBold(), plotTitle, len(series), indexes, ui.ColorReset())
+		if err := plot.WriteASCII(stdout, series, *width, *height); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return apperrors.ExitErrorGeneric
+		}
+	}
+	printCrossovers(stdout, plot.Crossovers(series))
+	return apperrors.ExitSuccess
+}
+
+// loadPlotSeries reads a stream of --machine documents, such as the output
+// of --n-series with --machine, into a series per algorithm, in the order
+// they first appear. Failed runs are left out, and an algorithm timed
+// several times at an index keeps its best duration.
+//
+// Parameters:
+//   - r: The documents, as JSON values one after the other.
+//
+// Returns:
+//   - []plot.Series: The series, whose points are by increasing n.
+//   - int: The number of distinct indexes.
+//   - error: An error if a document is malformed or none has a timing.
+func loadPlotSeries(r io.Reader) ([]plot.Series, int, error) {
+	var names []string
+	best := make(map[string]map[uint64]plot.Point)
+	indexes := make(map[uint64]bool)
+	dec := json.NewDecoder(r)
+	for count := 1; ; count++ {
+		var doc cli.MachineResult
+		if err := dec.Decode(&doc); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, 0, fmt.Errorf("document %d: %w", count, err)
+		}
+		for _, run := range doc.Runs {
+			if run.ErrorKind != apperrors.ErrorKindNone || run.Error != "" || run.DurationNs <= 0 {
+				continue
+			}
+			name := run.Algorithm
+			if name == "" {
+				name = run.Name
+			}
+			if best[name] == nil {
+				best[name] = make(map[uint64]plot.Point)
+				names = append(names, name)
+			}
+			p := plot.Point{N: doc.N, Duration: time.Duration(run.DurationNs)}
+			if prev, ok := best[name][doc.N]; !ok || p.Duration < prev.Duration {
+				best[name][doc.N] = p
+			}
+			indexes[doc.N] = true
+		}
+	}
+	if len(names) == 0 {
+		return nil, 0, errors.New("no successful run to plot")
+	}
+
+	series := make([]plot.Series, len(names))
+	for i, name := range names {
+		points := slices.Collect(maps.Values(best[name]))
+		slices.SortFunc(points, func(a, b plot.Point) int { return cmp.Compare(a.N, b.N) })
+		series[i] = plot.Series{Name: name, Points: points}
+	}
+	return series, len(indexes), nil
+}
+
+// writeSVGFile writes the SVG chart of the series to path.
+func writeSVGFile(path string, series []plot.Series) error {
+	f, err := fsguard.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := plot.WriteSVG(f, series, plotTitle); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// printCrossovers lists where the algorithms swap order.
+func printCrossovers(w io.Writer, crossovers []plot.Crossover) {
+	if len(crossovers) == 0 {
+		fmt.Fprintln(w, "No crossover: the algorithms keep the same order at every index.")
+		return
+	}
+	fmt.Fprintln(w, "Crossovers:")
+	for _, c := range crossovers {
+		fmt.Fprintf(w, "  %s%s%s overtakes %s between n = %s and %s (about n = %s)\n", ui.ColorGreen(), c.Faster, ui.ColorReset(),
+			c.Slower, plot.FormatN(float64(c.Low)), plot.FormatN(float64(c.High)), plot.FormatN(c.N))
+	}
+}
@@ -0,0 +1,52 @@
+package app
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/agbru/fibcalc/internal/parallel"
+	"github.com/agbru/fibcalc/internal/priority"
+)
+
+// setNice and setIOPriority change the process priority. They are
+// variables so that tests can replace them.
+var (
+	setNice       = priority.SetNice
+	setIOPriority = priority.SetIOPriority
+)
+
+// applyPriority lowers the scheduling priority as configured by --nice,
+// --ionice and --background, before any worker starts. Background mode
+// fills in the settings that were not given and caps GOMAXPROCS to half
+// the processors. A priority that cannot be set is only a warning: the
+// calculation runs anyway.
+func (a *Application) applyPriority() {
+	cfg := &a.Config
+	if cfg.Background {
+		workers := priority.BackgroundWorkers(runtime.NumCPU())
+		if cfg.Nice == 0 {
+			cfg.Nice = priority.BackgroundNice
+		}
+		if cfg.IONice == "" {
+			cfg.IONice = priority.BackgroundIO.String()
+		}
+		if cfg.MaxGoroutines == 0 {
+			cfg.MaxGoroutines = workers
+		}
+		if runtime.GOMAXPROCS(0) > workers {
+			runtime.GOMAXPROCS(workers)
+			parallel.RefreshProcessors()
+		}
+	}
+
+	if cfg.Nice != 0 {
+		if err := setNice(cfg.Nice); err != nil {
+			fmt.Fprintf(a.ErrWriter, "Warning: cannot set nice %d: %v\n", cfg.Nice, err)
+		}
+	}
+	// The value was checked by Config.Validate.
+	io, _ := priority.ParseIOPriority(cfg.IONice)
+	if err := setIOPriority(io); err != nil {
+		fmt.Fprintf(a.ErrWriter, "Warning: cannot set I/O priority %s: %v\n", cfg.IONice, err)
+	}
+}
@@ -0,0 +1,83 @@
+package app
+
+import (
+	"bytes"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/agbru/fibcalc/internal/config"
+	"github.com/agbru/fibcalc/internal/parallel"
+	"github.com/agbru/fibcalc/internal/priority"
+)
+
+// TestApplyPriority replaces the priority setters and changes GOMAXPROCS,
+// so it does not run in parallel.
+func TestApplyPriority(t *testing.T) {
+	var nices []int
+	var ios []priority.IOPriority
+	failIO := false
+	savedNice, savedIO := setNice, setIOPriority
+	setNice = func(n int) error {
+		nices = append(nices, n)
+		return nil
+	}
+	setIOPriority = func(p priority.IOPriority) error {
+		ios = append(ios, p)
+		if failIO && p.Class != priority.IOClassNone {
+			return errors.New("not supported")
+		}
+		return nil
+	}
+	procs := runtime.GOMAXPROCS(0)
+	t.Cleanup(func() {
+		setNice, setIOPriority = savedNice, savedIO
+		runtime.GOMAXPROCS(procs)
+		parallel.RefreshProcessors()
+	})
+
+	t.Run("background", func(t *testing.T) {
+		nices, ios = nil, nil
+		a := &Application{Config: config.AppConfig{Background: true}, ErrWriter: &bytes.Buffer{}}
+		a.applyPriority()
+		workers := priority.BackgroundWorkers(runtime.NumCPU())
+		if a.Config.Nice != priority.BackgroundNice || a.Config.IONice != "idle" || a.Config.MaxGoroutines != workers {
+			t.Errorf("config = nice %d, ionice %q, max goroutines %d; want %d, idle, %d",
+				a.Config.Nice, a.Config.IONice, a.Config.MaxGoroutines, priority.BackgroundNice, workers)
+		}
+		if len(nices) != 1 || nices[0] != priority.BackgroundNice || len(ios) != 1 || ios[0] != priority.BackgroundIO {
+			t.Errorf("setters called with %v and %v", nices, ios)
+		}
+		if got := runtime.GOMAXPROCS(0); got > workers {
+			t.Errorf("GOMAXPROCS = %d, want at most %d", got, workers)
+		}
+	})
+
+	t.Run("explicit settings win", func(t *testing.T) {
+		nices, ios = nil, nil
+		a := &Application{Config: config.AppConfig{Background: true, Nice: 5, IONice: "best-effort:6", MaxGoroutines: 3}, ErrWriter: &bytes.Buffer{}}
+		a.applyPriority()
+		if nices[0] != 5 || ios[0] != (priority.IOPriority{Class: priority.IOClassBestEffort, Level: 6}) || a.Config.MaxGoroutines != 3 {
+			t.Errorf("setters called with %v and %v, max goroutines %d", nices, ios, a.Config.MaxGoroutines)
+		}
+	})
+
+	t.Run("unchanged by default", func(t *testing.T) {
+		nices, ios = nil, nil
+		(&Application{ErrWriter: &bytes.Buffer{}}).applyPriority()
+		if len(nices) != 0 || len(ios) != 1 || ios[0].Class != priority.IOClassNone {
+			t.Errorf("setters called with %v and %v", nices, ios)
+		}
+	})
+
+	t.Run("failure is a warning", func(t *testing.T) {
+		failIO = true
+		defer func() { failIO = false }()
+		var errOut bytes.Buffer
+		(&Application{Config: config.AppConfig{IONice: "idle"}, ErrWriter: &errOut}).applyPriority()
+		if !strings.Contains(errOut.String(), "Warning: cannot set I/O priority idle: not supported") {
+			t.Errorf("unexpected warning %q", errOut.String())
+		}
+	})
+}
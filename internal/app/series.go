@@ -0,0 +1,185 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"os/signal"
+	"slices"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/cli"
+	"github.com/agbru/fibcalc/internal/config"
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/format"
+	"github.com/agbru/fibcalc/internal/ui"
+)
+
+// seriesPoint is the outcome of one index of an --n-series.
+type seriesPoint struct {
+	n uint64
+	// durations are the durations of the successful runs, by registered
+	// algorithm name.
+	durations map[string]time.Duration
+}
+
+// runSeries runs the calculation for each index of --n-series, then prints
+// the timings of every algorithm by index and how they scale with n. Each
+// index is recorded in the history, pushed, added to the job summary and
+// submitted to telemetry like a single run, and with --machine its result
+// document is written to stdout as it completes. The series stops at the first index that does
+// not succeed.
+//
+// Parameters:
+//   - ctx: The context of the series.
+//   - out: The writer of the human-readable output.
+//   - stdout: The writer of the --machine documents.
+//
+// Returns:
+//   - int: The exit code of the last index run.
+func (a *Application) runSeries(ctx context.Context, out, stdout io.Writer) int {
+	series, err := config.ParseNSeries(a.Config.NSeries)
+	if err != nil { // validated by ParseConfig
+		fmt.Fprintf(a.ErrWriter, "Configuration error: %v\n", err)
+		return apperrors.ExitErrorConfig
+	}
+	ctx, stopSignals := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
+
+	keys := a.algorithmKeys()
+	exitCode := apperrors.ExitSuccess
+	var points []seriesPoint
+	for i, n := range series {
+		fmt.Fprintf(out, "\n%s=== n-series %d/%d: n = %s ===%s\n", ui.ColorBold(), i+1, len(series),
+			format.FormatNumberString(strconv.FormatUint(n, 10)), ui.ColorReset())
+		a.Config.N = n
+		a.results, a.outcome = nil, nil
+		start := time.Now()
+		exitCode = a.runCalculate(ctx, out)
+		if a.Config.Machine {
+			if err := cli.DisplayMachineResult(stdout, a.machineResult(exitCode)); err != nil {
+				fmt.Fprintf(a.ErrWriter, "Error writing the result document: %v\n", err)
+			}
+		}
+		if a.Config.History {
+			a.writeHistory(start)
+		}
+		if a.pusher != nil {
+			a.pushResults()
+		}
+		if a.Config.GHASummary {
+			a.writeGHASummary()
+		}
+		a.submitTelemetry()
+
+		point := seriesPoint{n: n, durations: make(map[string]time.Duration)}
+		for name, d := range resultDurations(a.results) {
+			if key := keys[name]; key != "" {
+				name = key
+			}
+			point.durations[name] = d
+		}
+		points = append(points, point)
+		if exitCode != apperrors.ExitSuccess {
+			if i < len(series)-1 {
+				fmt.Fprintf(out, "\n%sStopping the series: n = %d did not succeed.%s\n", ui.ColorYellow(), n, ui.ColorReset())
+			}
+			break
+		}
+	}
+	// Every index is already recorded; Run must not record the last again
+	a.results, a.outcome = nil, nil
+
+	printSeriesSummary(out, points)
+	return exitCode
+}
+
+// printSeriesSummary writes the timings of the series as a table, one row
+// per index and one column per algorithm, followed by the exponent k of
+// the fitted time ∝ n^k of each algorithm.
+//
+// Parameters:
+//   - out: The writer.
+//   - points: The indexes run, in order.
+func printSeriesSummary(out io.Writer, points []seriesPoint) {
+	algos := make(map[string]bool)
+	for _, p := range points {
+		for name := range p.durations {
+			algos[name] = true
+		}
+	}
+	names := slices.Sorted(maps.Keys(algos))
+	if len(names) == 0 {
+		return
+	}
+
+	fmt.Fprintf(out, "\n--- n-series summary ---\n")
+	fmt.Fprintf(out, "  %-16s", "n")
+	for _, name := range names {
+		fmt.Fprintf(out, " %14s", name)
+	}
+	fmt.Fprintln(out)
+	for _, p := range points {
+		fmt.Fprintf(out, "  %-16s", format.FormatNumberString(strconv.FormatUint(p.n, 10)))
+		for _, name := range names {
+			cell := "-"
+			if d, ok := p.durations[name]; ok {
+				cell = format.FormatExecutionDuration(d)
+			}
+			fmt.Fprintf(out, " %14s", cell)
+		}
+		fmt.Fprintln(out)
+	}
+	fmt.Fprintf(out, "  %-16s", "scaling")
+	for _, name := range names {
+		cell := "-"
+		if k, ok := scalingExponent(points, name); ok {
+			cell = fmt.Sprintf("n^%.2f", k)
+		}
+		fmt.Fprintf(out, " %s%14s%s", ui.ColorCyan(), cell, ui.ColorReset())
+	}
+	fmt.Fprintln(out)
+}
+
+// scalingExponent fits time = c·n^k by least squares on the logarithms of
+// the successful runs of the algorithm.
+//
+// Parameters:
+//   - points: The indexes run.
+//   - name: The algorithm.
+//
+// Returns:
+//   - float64: The exponent k.
+//   - bool: false if fewer than two distinct indexes have a timing.
+func scalingExponent(points []seriesPoint, name string) (float64, bool) {
+	var xs, ys []float64
+	for _, p := range points {
+		if d, ok := p.durations[name]; ok && d > 0 && p.n > 0 {
+			xs = append(xs, math.Log(float64(p.n)))
+			ys = append(ys, math.Log(float64(d)))
+		}
+	}
+	if len(xs) < 2 {
+		return 0, false
+	}
+	var meanX, meanY float64
+	for i := range xs {
+		meanX += xs[i]
+		meanY += ys[i]
+	}
+	meanX /= float64(len(xs))
+	meanY /= float64(len(ys))
+	var cov, varX float64
+	for i := range xs {
+		cov += (xs[i] - meanX) * (ys[i] - meanY)
+		varX += (xs[i] - meanX) * (xs[i] - meanX)
+	}
+	if varX == 0 {
+		return 0, false
+	}
+	return cov / varX, true
+}
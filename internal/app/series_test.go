@@ -0,0 +1,104 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/cli"
+	"github.com/agbru/fibcalc/internal/config"
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/fibtest"
+	"github.com/agbru/fibcalc/internal/testutil"
+)
+
+func TestScalingExponent(t *testing.T) {
+	t.Parallel()
+	var points []seriesPoint
+	for _, n := range []uint64{1000, 2000, 4000, 8000} {
+		// time ∝ n²
+		d := time.Duration(n*n) * time.Nanosecond
+		points = append(points, seriesPoint{n: n, durations: map[string]time.Duration{"fast": d}})
+	}
+	if k, ok := scalingExponent(points, "fast"); !ok || math.Abs(k-2) > 1e-9 {
+		t.Errorf("scalingExponent() = %v, %v; want 2, true", k, ok)
+	}
+	if _, ok := scalingExponent(points[:1], "fast"); ok {
+		t.Error("scalingExponent() of a single point should not fit")
+	}
+	if _, ok := scalingExponent(points, "matrix"); ok {
+		t.Error("scalingExponent() of an algorithm without timings should not fit")
+	}
+}
+
+func TestRunSeries(t *testing.T) {
+	t.Parallel()
+	var stdout, stderr bytes.Buffer
+	app := &Application{
+		Config: config.AppConfig{
+			NSeries: "10..30 step +10",
+			Algo:    "all",
+			Timeout: time.Minute,
+			Machine: true,
+		},
+		Factory: fibonacci.NewTestFactory(map[string]fibonacci.Calculator{
+			"fast":   fibtest.Fixed("Fast", nil),
+			"matrix": fibtest.Fixed("Matrix", nil),
+		}),
+		ErrWriter: &stderr,
+	}
+
+	if code := app.Run(context.Background(), &stdout); code != apperrors.ExitSuccess {
+		t.Fatalf("Run() = %d, want %d; stderr:\n%s", code, apperrors.ExitSuccess, stderr.String())
+	}
+
+	// One document per index, with the true F(n)
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("stdout has %d documents, want 3:\n%s", len(lines), stdout.String())
+	}
+	for i, line := range lines {
+		var doc cli.MachineResult
+		if err := json.Unmarshal([]byte(line), &doc); err != nil {
+			t.Fatalf("document %d: %v", i, err)
+		}
+		n := uint64(10 * (i + 1))
+		if doc.N != n || doc.Value != fibtest.Reference(n).String() || len(doc.Runs) != 2 {
+			t.Errorf("document %d = %+v, want F(%d) from 2 runs", i, doc, n)
+		}
+	}
+
+	got := testutil.StripAnsiCodes(stderr.String())
+	for _, want := range []string{"n-series 3/3: n = 30", "--- n-series summary ---", "fast", "matrix", "scaling"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("stderr does not contain %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestRunSeries_StopsOnFailure(t *testing.T) {
+	t.Parallel()
+	var out bytes.Buffer
+	app := &Application{
+		Config: config.AppConfig{
+			NSeries: "10..30 step +10",
+			Algo:    "fast",
+			Timeout: time.Minute,
+		},
+		Factory:   createMockFactory(nil, context.DeadlineExceeded),
+		ErrWriter: &bytes.Buffer{},
+	}
+
+	if code := app.Run(context.Background(), &out); code == apperrors.ExitSuccess {
+		t.Fatal("Run() succeeded, want the failure of the first index")
+	}
+	got := testutil.StripAnsiCodes(out.String())
+	if !strings.Contains(got, "Stopping the series") || strings.Contains(got, "n-series 2/3") {
+		t.Errorf("expected the series to stop after the first index:\n%s", got)
+	}
+}
@@ -0,0 +1,58 @@
+package app
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/provenance"
+)
+
+// runVerifySignature checks the signatures of result files and reports
+// written with --sign-key. It exits with ExitErrorMismatch if a file does
+// not verify.
+func runVerifySignature(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("verify-signature", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	keyPath := fs.String("key", "", "Only accept signatures by this PEM ed25519 public (or private) key.")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return apperrors.ExitSuccess
+		}
+		return apperrors.ExitErrorConfig
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(stderr, "Error: verify-signature takes the files to verify.")
+		return apperrors.ExitErrorConfig
+	}
+	var trusted ed25519.PublicKey
+	if *keyPath != "" {
+		key, err := provenance.LoadPublicKey(*keyPath)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: --key: %v.\n", err)
+			return apperrors.ExitErrorConfig
+		}
+		trusted = key
+	}
+
+	code := apperrors.ExitSuccess
+	for _, path := range fs.Args() {
+		v, err := provenance.VerifyFile(path, trusted)
+		if err != nil {
+			fmt.Fprintf(stdout, "%s: FAILED: %v\n", path, err)
+			code = apperrors.ExitErrorMismatch
+			continue
+		}
+		fmt.Fprintf(stdout, "%s: OK: F(%d) by %s, fibcalc %s, signed by %s\n", path, v.N, v.Algorithm, v.Version, v.Fingerprint())
+		if !v.ValueChecked {
+			fmt.Fprintf(stdout, "  the file holds no value: only its metadata is covered (SHA-256 %s)\n", v.ResultSHA256)
+		}
+	}
+	if trusted == nil && code == apperrors.ExitSuccess {
+		fmt.Fprintln(stdout, "The signer's key was not checked; use --key to require a known key.")
+	}
+	return code
+}
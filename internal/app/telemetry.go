@@ -0,0 +1,142 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/config"
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/telemetry"
+	"github.com/agbru/fibcalc/internal/ui"
+)
+
+// telemetryFileEnv overrides the telemetry state file. It is read from the
+// environment only, like the state it points to is only changed by
+// --telemetry and `fibcalc telemetry off`.
+const telemetryFileEnv = config.EnvPrefix + "TELEMETRY_FILE"
+
+// telemetryPath returns the telemetry state file.
+func telemetryPath() string {
+	if path := os.Getenv(telemetryFileEnv); path != "" {
+		return path
+	}
+	return telemetry.DefaultPath()
+}
+
+// optInTelemetry records the consent given by --telemetry and, the first
+// time or when the endpoint changes, says what is sent where and how to
+// stop it. Failures are reported on ErrWriter but never change the exit
+// code.
+func (a *Application) optInTelemetry() {
+	path := telemetryPath()
+	state, err := telemetry.Load(path)
+	if err != nil {
+		fmt.Fprintf(a.ErrWriter, "Warning: %v\n", err)
+	}
+	if state.Enabled && state.Endpoint == a.Config.TelemetryEndpoint {
+		return
+	}
+	state = telemetry.State{Enabled: true, Endpoint: a.Config.TelemetryEndpoint, Since: time.Now().UTC()}
+	if err := telemetry.Save(path, state); err != nil {
+		fmt.Fprintf(a.ErrWriter, "Warning: %v\n", err)
+		return
+	}
+	fmt.Fprintf(a.ErrWriter, "%sTelemetry enabled: anonymous performance reports of successful runs (hardware hash, n, algorithm, duration, thresholds) will be sent to %s. Run 'fibcalc telemetry off' to stop.%s\n",
+		ui.ColorCyan(), state.Endpoint, ui.ColorReset())
+}
+
+// submitTelemetry sends the successful results of the last calculation to
+// the telemetry endpoint, if the user opted in. Failures are reported on
+// ErrWriter but never change the exit code.
+func (a *Application) submitTelemetry() {
+	if len(a.results) == 0 {
+		return
+	}
+	state, err := telemetry.Load(telemetryPath())
+	if err != nil {
+		fmt.Fprintf(a.ErrWriter, "Warning: %v\n", err)
+		return
+	}
+	if !state.Enabled || state.Endpoint == "" {
+		return
+	}
+
+	keys := a.algorithmKeys()
+	var records []telemetry.Record
+	for _, res := range a.results {
+		if res.Err != nil {
+			continue
+		}
+		rec := telemetry.NewRecord()
+		rec.Version = Version
+		rec.N = a.Config.N
+		if rec.Algorithm = keys[res.Name]; rec.Algorithm == "" {
+			rec.Algorithm = res.Name
+		}
+		rec.DurationNs = res.Duration.Nanoseconds()
+		rec.ParallelThreshold = a.Config.Threshold
+		rec.FFTThreshold = a.Config.FFTThreshold
+		rec.StrassenThreshold = a.Config.StrassenThreshold
+		records = append(records, rec)
+	}
+	if err := telemetry.Submit(context.Background(), state.Endpoint, records); err != nil {
+		fmt.Fprintf(a.ErrWriter, "Warning: %v\n", err)
+	}
+}
+
+// runTelemetry shows the telemetry consent (status) or withdraws it (off).
+func runTelemetry(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("telemetry", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	file := fs.String("file", telemetryPath(), "Telemetry state path.")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return apperrors.ExitSuccess
+		}
+		return apperrors.ExitErrorConfig
+	}
+	action := "status"
+	switch fs.NArg() {
+	case 0:
+	case 1:
+		action = fs.Arg(0)
+	default:
+		fmt.Fprintf(stderr, "Error: unexpected argument %q.\n", fs.Arg(1))
+		return apperrors.ExitErrorConfig
+	}
+
+	state, err := telemetry.Load(*file)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return apperrors.ExitErrorGeneric
+	}
+	switch action {
+	case "status":
+		if !state.Enabled {
+			fmt.Fprintln(stdout, "Telemetry: disabled (opt in with --telemetry --telemetry-endpoint URL)")
+			return apperrors.ExitSuccess
+		}
+		fmt.Fprintf(stdout, "Telemetry: enabled since %s\n", state.Since.Local().Format(time.DateTime))
+		fmt.Fprintf(stdout, "Endpoint:  %s\n", state.Endpoint)
+		fmt.Fprintf(stdout, "Hardware:  %s (hash sent with each report)\n", telemetry.Fingerprint())
+		return apperrors.ExitSuccess
+	case "off":
+		if !state.Enabled {
+			fmt.Fprintln(stdout, "Telemetry is already disabled.")
+			return apperrors.ExitSuccess
+		}
+		if err := telemetry.Save(*file, telemetry.State{}); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return apperrors.ExitErrorGeneric
+		}
+		fmt.Fprintln(stdout, "Telemetry disabled: no more reports will be sent.")
+		return apperrors.ExitSuccess
+	}
+	fmt.Fprintf(stderr, "Error: unknown telemetry action %q (expected status or off).\n", action)
+	return apperrors.ExitErrorConfig
+}
@@ -0,0 +1,105 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/calibration"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/format"
+	"github.com/agbru/fibcalc/internal/orchestration"
+	"github.com/agbru/fibcalc/internal/ui"
+)
+
+// minFactorTimeout is the smallest timeout --timeout-factor sets: below it,
+// scheduling noise and output dominate the predicted duration.
+const minFactorTimeout = 5 * time.Second
+
+// costModel returns the cost model of this machine, with the FFT crossover
+// from the calibration profile when one is available.
+//
+// Returns:
+//   - fibonacci.CostModel: The model.
+//   - string: Where the FFT crossover comes from.
+func (a *Application) costModel() (fibonacci.CostModel, string) {
+	crossover, source := calibration.ProfileFFTCrossover(a.calibrationProfile())
+	return fibonacci.CostModel{
+		FFTCrossoverBits:  crossover,
+		FFTThreshold:      a.Config.FFTThreshold,
+		ParallelThreshold: a.Config.Threshold,
+		StrassenThreshold: a.Config.StrassenThreshold,
+		NumCPU:            runtime.NumCPU(),
+	}, source
+}
+
+// applyTimeoutFactor replaces the configured timeout with --timeout-factor
+// times the predicted duration of the calculation, when the factor is set.
+// The prediction is printed unless quiet mode or the TUI is enabled; if it
+// fails, --timeout is kept.
+func (a *Application) applyTimeoutFactor(ctx context.Context, out io.Writer) {
+	if a.Config.TimeoutFactor <= 0 {
+		return
+	}
+	predicted, err := a.predictDuration(ctx)
+	if err != nil {
+		fmt.Fprintf(a.ErrWriter, "Warning: cannot predict the duration, keeping the %s timeout: %v\n", a.Config.Timeout, err)
+		return
+	}
+	timeout := max(time.Duration(a.Config.TimeoutFactor*float64(predicted)), minFactorTimeout).Round(time.Second)
+	a.Config.Timeout = timeout
+	if !a.Config.Quiet && !a.Config.TUI {
+		fmt.Fprintf(out, "Timeout: %s (%gx the predicted %s)\n",
+			ui.ColorYellow()+format.FormatExecutionDuration(timeout)+ui.ColorReset(), a.Config.TimeoutFactor, format.FormatExecutionDuration(predicted))
+	}
+}
+
+// predictDuration estimates how long the configured calculation takes.
+// Each calculator computes F(orchestration.WarmupIndex(n)) twice, the
+// first run absorbing cold caches, and the cost model scales the faster
+// run to n. Sequential comparisons take the sum of the predictions, the
+// others as long as the slowest calculator.
+func (a *Application) predictDuration(ctx context.Context) (time.Duration, error) {
+	calculators := orchestration.GetCalculatorsToRun(a.Config.Algo, a.Factory)
+	mode, err := orchestration.ParseCompareMode(a.Config.CompareMode)
+	if err != nil {
+		mode = orchestration.CompareParallel
+	}
+	// Hardware counters and the energy indicator run calculators in turn
+	if a.Config.PerfCounters || a.Config.Energy {
+		mode = orchestration.CompareSequential
+	}
+
+	model, _ := a.costModel()
+	keys := a.algorithmKeys()
+	opts := fibonacci.Options{
+		ParallelThreshold: a.Config.Threshold,
+		FFTThreshold:      a.Config.FFTThreshold,
+		StrassenThreshold: a.Config.StrassenThreshold,
+		DisableTables:     a.Config.NoTable,
+	}
+	probe := orchestration.WarmupIndex(a.Config.N)
+
+	var total, slowest time.Duration
+	for _, calc := range calculators {
+		fastest := time.Duration(-1)
+		for range 2 {
+			start := time.Now()
+			if _, err := calc.Calculate(ctx, nil, 0, probe, opts); err != nil {
+				return 0, fmt.Errorf("%s: %w", calc.Name(), err)
+			}
+			if took := time.Since(start); fastest < 0 || took < fastest {
+				fastest = took
+			}
+		}
+		predicted := model.Extrapolate(keys[calc.Name()], probe, fastest, a.Config.N)
+		total += predicted
+		slowest = max(slowest, predicted)
+	}
+	if mode == orchestration.CompareSequential {
+		return total, nil
+	}
+	return slowest, nil
+}
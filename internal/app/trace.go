@@ -0,0 +1,38 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/config"
+	"github.com/agbru/fibcalc/internal/orchestration"
+	"github.com/agbru/fibcalc/internal/ui"
+)
+
+// reportRuntimeTrace stops the --runtime-trace capture and tells what it
+// holds and how to open it; a failure is a warning.
+func (a *Application) reportRuntimeTrace(rt *orchestration.RuntimeTrace, out io.Writer) {
+	capture, err := rt.Close()
+	if err != nil {
+		fmt.Fprintf(a.ErrWriter, "Warning: --runtime-trace: %v\n", err)
+		return
+	}
+	if a.Config.Quiet {
+		return
+	}
+	if capture.Name == "" {
+		fmt.Fprintln(out, "Runtime trace: none written, the calculation ended before its heaviest steps could be selected.")
+		return
+	}
+	path, _, _ := config.ParseRuntimeTrace(a.Config.RuntimeTrace)
+	fmt.Fprintf(out, "Runtime trace: %s of %s from %.1f%% progress", capture.Duration.Round(time.Millisecond), capture.Name, 100*capture.Progress)
+	switch {
+	case capture.Steps == 1:
+		fmt.Fprintf(out, ", 1 step at bit %d of %d", capture.LastBit, capture.Bits)
+	case capture.Steps > 1:
+		fmt.Fprintf(out, ", %d steps from bit %d to %d of %d", capture.Steps, capture.FirstBit, capture.LastBit, capture.Bits)
+	}
+	fmt.Fprintf(out, ", written to %s.\n", path)
+	fmt.Fprintf(out, "  Open it with: %sgo tool trace %s%s\n", ui.ColorBold(), path, ui.ColorReset())
+}
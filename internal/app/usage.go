@@ -0,0 +1,38 @@
+package app
+
+import (
+	"io"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/cli"
+	"github.com/agbru/fibcalc/internal/sysmon"
+)
+
+// measureUsage starts measuring the resource usage of a calculation. The
+// function it returns ends the measurement: it records the usage in
+// a.usage, for the --machine document, and shows it with --details.
+//
+// Parameters:
+//   - out: The writer of the --details summary.
+//
+// Returns:
+//   - func(): Ends the measurement; call it when the calculation is done.
+func (a *Application) measureUsage(out io.Writer) func() {
+	a.usage = nil
+	start := time.Now()
+	before, err := sysmon.ReadUsage()
+	if err != nil {
+		return func() {}
+	}
+	return func() {
+		after, err := sysmon.ReadUsage()
+		if err != nil {
+			return
+		}
+		usage := after.Sub(before)
+		a.usage = &usage
+		if a.Config.Details && !a.Config.Quiet {
+			cli.DisplayResourceUsage(usage, time.Since(start), out)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/calibration"
+	"github.com/agbru/fibcalc/internal/format"
+	"github.com/agbru/fibcalc/internal/orchestration"
+	"github.com/agbru/fibcalc/internal/ui"
+)
+
+// watchInterval is how often --watch checks the calibration profile.
+const watchInterval = 500 * time.Millisecond
+
+// fileStamp identifies a version of a watched file. A missing file has the
+// zero stamp.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// statFile returns the stamp of the file at path.
+func statFile(path string) fileStamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{modTime: info.ModTime(), size: info.Size()}
+}
+
+// waitForChange polls the file at path until its stamp differs from since.
+//
+// Parameters:
+//   - ctx: Stops the wait when canceled.
+//   - path: The watched file.
+//   - since: The stamp of the version already used.
+//   - interval: The polling period.
+//
+// Returns:
+//   - error: nil once the file changed, or ctx.Err().
+func waitForChange(ctx context.Context, path string, since fileStamp, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		if statFile(path) != since {
+			return nil
+		}
+	}
+}
+
+// runWatch runs the calculation, then runs it again each time the
+// calibration profile changes, with the thresholds it now holds, showing
+// how the timings moved. It stops on SIGINT or SIGTERM.
+//
+// Returns:
+//   - int: The exit code of the last run.
+func (a *Application) runWatch(ctx context.Context, out io.Writer) int {
+	ctx, stopSignals := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
+
+	path := a.Config.CalibrationProfile
+	if path == "" {
+		path = calibration.GetDefaultProfilePath()
+	}
+	path = filepath.Clean(path)
+
+	var previous map[string]time.Duration
+	for {
+		stamp := statFile(path)
+		a.results = nil
+		exitCode := a.runCalculate(ctx, out)
+		current := resultDurations(a.results)
+		if previous != nil {
+			printTimingDiff(out, previous, current)
+		}
+		previous = current
+
+		fmt.Fprintf(out, "\nWatching %s%s%s for changes (Ctrl+C to stop)...\n", ui.ColorCyan(), path, ui.ColorReset())
+		if err := waitForChange(ctx, path, stamp, watchInterval); err != nil {
+			return exitCode
+		}
+		if updated, ok := calibration.LoadCachedCalibration(a.Config, path); ok {
+			a.Config = updated
+		}
+		fmt.Fprintf(out, "\n--- Profile changed: parallelism=%s%d%s bits, FFT=%s%d%s bits, Strassen=%s%d%s bits ---\n",
+			ui.ColorYellow(), a.Config.Threshold, ui.ColorReset(),
+			ui.ColorYellow(), a.Config.FFTThreshold, ui.ColorReset(),
+			ui.ColorYellow(), a.Config.StrassenThreshold, ui.ColorReset())
+	}
+}
+
+// resultDurations returns the durations of the successful results by
+// algorithm.
+func resultDurations(results []orchestration.CalculationResult) map[string]time.Duration {
+	durations := make(map[string]time.Duration, len(results))
+	for _, r := range results {
+		if r.Err == nil {
+			durations[r.Name] = r.Duration
+		}
+	}
+	return durations
+}
+
+// printTimingDiff writes the timing of each algorithm of the current run
+// next to its timing in the previous run.
+//
+// Parameters:
+//   - out: The writer.
+//   - previous: The durations of the previous run, by algorithm.
+//   - current: The durations of the current run, by algorithm.
+func printTimingDiff(out io.Writer, previous, current map[string]time.Duration) {
+	fmt.Fprintf(out, "\n--- Timings vs previous run ---\n")
+	for _, name := range slices.Sorted(maps.Keys(current)) {
+		d := current[name]
+		before, ok := previous[name]
+		if !ok || before <= 0 {
+			fmt.Fprintf(out, "  %-20s %s (new)\n", name, format.FormatExecutionDuration(d))
+			continue
+		}
+		change := (float64(d) - float64(before)) / float64(before) * 100
+		color := ui.ColorGreen()
+		if change > 0 {
+			color = ui.ColorRed()
+		}
+		fmt.Fprintf(out, "  %-20s %s (%s%+.1f%%%s, was %s)\n", name, format.FormatExecutionDuration(d),
+			color, change, ui.ColorReset(), format.FormatExecutionDuration(before))
+	}
+}
@@ -0,0 +1,116 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/config"
+)
+
+// lockedBuffer is a bytes.Buffer safe for a writer and a concurrent reader.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestWaitForChange(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "profile.json")
+	stamp := statFile(path)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := waitForChange(ctx, path, stamp, 5*time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("waitForChange on an unchanged file = %v, want the context error", err)
+	}
+
+	if err := os.WriteFile(path, []byte("{}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := waitForChange(ctx, path, stamp, 5*time.Millisecond); err != nil {
+		t.Errorf("waitForChange on a created file = %v, want nil", err)
+	}
+}
+
+func TestPrintTimingDiff(t *testing.T) {
+	t.Parallel()
+	var out bytes.Buffer
+	printTimingDiff(&out,
+		map[string]time.Duration{"Fast": 2 * time.Second},
+		map[string]time.Duration{"Fast": time.Second, "Matrix": 3 * time.Second})
+
+	got := out.String()
+	for _, want := range []string{"Fast", "-50.0%", "was 2s", "Matrix", "(new)"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestRunWatch(t *testing.T) {
+	t.Parallel()
+	profile := filepath.Join(t.TempDir(), "profile.json")
+	app := &Application{
+		Config: config.AppConfig{
+			N:                  1000,
+			Algo:               "all",
+			Timeout:            time.Minute,
+			CalibrationProfile: profile,
+			Watch:              true,
+		},
+		Factory:   createMockFactory(big.NewInt(55), nil),
+		ErrWriter: &bytes.Buffer{},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var out lockedBuffer
+	done := make(chan int)
+	go func() { done <- app.runWatch(ctx, &out) }()
+
+	waitFor := func(s string, count int) {
+		t.Helper()
+		deadline := time.Now().Add(10 * time.Second)
+		for strings.Count(out.String(), s) < count {
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %q:\n%s", s, out.String())
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	waitFor("Watching", 1)
+	if err := os.WriteFile(profile, []byte("{}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	waitFor("Watching", 2)
+	cancel()
+
+	if code := <-done; code != 0 {
+		t.Errorf("runWatch() = %d, want 0", code)
+	}
+	if got := out.String(); !strings.Contains(got, "Profile changed") || !strings.Contains(got, "Timings vs previous run") {
+		t.Errorf("expected a second run compared with the first:\n%s", got)
+	}
+}
@@ -0,0 +1,223 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"math/big"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/config"
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/fsguard"
+)
+
+// Rotation defaults.
+const (
+	// DefaultMaxSize is the size in bytes after which the log is rotated.
+	DefaultMaxSize int64 = 10 << 20 // 10 MiB
+	// DefaultMaxBackups is the number of rotated files kept (path.1 … path.N).
+	DefaultMaxBackups = 5
+)
+
+// Record is a single audit log entry describing one invocation.
+type Record struct {
+	Timestamp    time.Time        `json:"timestamp"`
+	Version      string           `json:"version"`
+	Args         []string         `json:"args"`
+	Config       config.AppConfig `json:"config"`
+	Algorithm    string           `json:"algorithm,omitempty"`
+	ResultSHA256 string           `json:"result_sha256,omitempty"`
+	ResultBits   int              `json:"result_bits,omitempty"`
+	DurationNs   int64            `json:"duration_ns"`
+	Duration     string           `json:"duration"`
+	ExitCode     int              `json:"exit_code"`
+	// Results lists every algorithm of a comparison with its outcome.
+	Results []AlgorithmResult `json:"results,omitempty"`
+}
+
+// AlgorithmResult is the outcome of one algorithm in a Record.
+type AlgorithmResult struct {
+	Algorithm    string `json:"algorithm"`
+	DurationNs   int64  `json:"duration_ns"`
+	ResultSHA256 string `json:"result_sha256,omitempty"`
+	// ErrorKind categorizes a failure or a mismatching value ("timeout",
+	// "canceled", "oom", "internal", "mismatch"); omitted on success.
+	ErrorKind apperrors.ErrorKind `json:"error_kind,omitempty"`
+	Error     string              `json:"error,omitempty"`
+}
+
+// SetDuration records d in both machine- and human-readable form.
+func (r *Record) SetDuration(d time.Duration) {
+	r.DurationNs = d.Nanoseconds()
+	r.Duration = d.String()
+}
+
+// SetResult records the digest and size of a computed value.
+// A nil result leaves the result fields empty.
+func (r *Record) SetResult(result *big.Int) {
+	if result == nil {
+		return
+	}
+	r.ResultSHA256 = HashResult(result)
+	r.ResultBits = result.BitLen()
+}
+
+// AddResult appends the outcome of one algorithm to Results.
+//
+// Parameters:
+//   - name: The algorithm.
+//   - d: Its duration.
+//   - result: Its value, nil if it failed.
+//   - kind: The category of its failure, apperrors.ErrorKindNone on success.
+//   - err: Its error, if any.
+func (r *Record) AddResult(name string, d time.Duration, result *big.Int, kind apperrors.ErrorKind, err error) {
+	entry := AlgorithmResult{Algorithm: name, DurationNs: d.Nanoseconds(), ErrorKind: kind}
+	if result != nil {
+		entry.ResultSHA256 = HashResult(result)
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	r.Results = append(r.Results, entry)
+}
+
+// digestChunkSize is the size of the chunks HashResult feeds the hash.
+const digestChunkSize = 64 << 10
+
+// HashResult returns the hex-encoded SHA-256 digest of the big-endian
+// magnitude of x (as returned by x.Bytes()), prefixed with "-" for
+// negative values. It is independent of the decimal representation, and
+// the magnitude is streamed to the hash in chunks rather than copied
+// whole, so it is cheap even for huge results.
+func HashResult(x *big.Int) string {
+	h := sha256.New()
+	hashMagnitude(h, x)
+	digest := hex.EncodeToString(h.Sum(nil))
+	if x.Sign() < 0 {
+		return "-" + digest
+	}
+	return digest
+}
+
+// hashMagnitude writes the big-endian magnitude of x to h, without leading
+// zero bytes, from the most significant word down.
+func hashMagnitude(h hash.Hash, x *big.Int) {
+	words := x.Bits()
+	buf := make([]byte, 0, digestChunkSize)
+	var word [8]byte
+	for i := len(words) - 1; i >= 0; i-- {
+		b := word[:bits.UintSize/8]
+		if bits.UintSize == 64 {
+			binary.BigEndian.PutUint64(b, uint64(words[i]))
+		} else {
+			binary.BigEndian.PutUint32(b, uint32(words[i]))
+		}
+		if i == len(words)-1 {
+			// The most significant word is not zero
+			for b[0] == 0 {
+				b = b[1:]
+			}
+		}
+		buf = append(buf, b...)
+		if len(buf) > digestChunkSize-len(word) {
+			h.Write(buf)
+			buf = buf[:0]
+		}
+	}
+	h.Write(buf)
+}
+
+// Logger appends records to a JSON Lines file, rotating it once it grows
+// beyond MaxSize. Logger is safe for concurrent use within a process.
+type Logger struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	mu         sync.Mutex
+}
+
+// NewLogger creates a logger writing to path. Non-positive maxSize or
+// maxBackups fall back to DefaultMaxSize and DefaultMaxBackups.
+//
+// Parameters:
+//   - path: The audit log file path.
+//   - maxSize: Rotation threshold in bytes.
+//   - maxBackups: Number of rotated files to keep.
+//
+// Returns:
+//   - *Logger: A new audit logger.
+func NewLogger(path string, maxSize int64, maxBackups int) *Logger {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	if maxBackups <= 0 {
+		maxBackups = DefaultMaxBackups
+	}
+	return &Logger{path: filepath.Clean(path), maxSize: maxSize, maxBackups: maxBackups}
+}
+
+// Append serializes rec as a single JSON line and appends it to the log,
+// rotating the file first if the new line would exceed the size limit.
+//
+// Returns:
+//   - error: An error if the record cannot be encoded or written.
+func (l *Logger) Append(rec Record) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit record: %w", err)
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if dir := filepath.Dir(l.path); dir != "" && dir != "." {
+		if err := fsguard.MkdirAll(dir, 0750); err != nil {
+			return fmt.Errorf("failed to create audit log directory %q: %w", dir, err)
+		}
+	}
+
+	if info, err := os.Stat(l.path); err == nil && info.Size() > 0 && info.Size()+int64(len(line)) > l.maxSize {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+
+	file, err := fsguard.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log %q: %w", l.path, err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(line); err != nil {
+		return fmt.Errorf("failed to write audit log %q: %w", l.path, err)
+	}
+	return nil
+}
+
+// rotate shifts path.N-1 → path.N … path → path.1, dropping the oldest file.
+func (l *Logger) rotate() error {
+	oldest := fmt.Sprintf("%s.%d", l.path, l.maxBackups)
+	if err := fsguard.Remove(oldest); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove old audit log %q: %w", oldest, err)
+	}
+	for i := l.maxBackups - 1; i >= 1; i-- {
+		src := fmt.Sprintf("%s.%d", l.path, i)
+		dst := fmt.Sprintf("%s.%d", l.path, i+1)
+		if err := fsguard.Rename(src, dst); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate audit log %q: %w", src, err)
+		}
+	}
+	if err := fsguard.Rename(l.path, l.path+".1"); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to rotate audit log %q: %w", l.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package audit
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/config"
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/fsguard"
+)
+
+func TestHashResult(t *testing.T) {
+	t.Parallel()
+	a := HashResult(big.NewInt(55))
+	b := HashResult(big.NewInt(55))
+	if a != b {
+		t.Errorf("hash should be deterministic: %s != %s", a, b)
+	}
+	if len(a) != 64 {
+		t.Errorf("expected 64 hex chars, got %d", len(a))
+	}
+	if HashResult(big.NewInt(89)) == a {
+		t.Error("different values should hash differently")
+	}
+	if neg := HashResult(big.NewInt(-55)); neg != "-"+a {
+		t.Errorf("negative hash = %s, want -%s", neg, a)
+	}
+}
+
+func TestHashResultMatchesBytes(t *testing.T) {
+	t.Parallel()
+	large := new(big.Int).Lsh(big.NewInt(0x1234567), 3*digestChunkSize*8+5)
+	large.Add(large, big.NewInt(99))
+	for _, x := range []*big.Int{big.NewInt(0), big.NewInt(1), big.NewInt(255), big.NewInt(256), new(big.Int).Lsh(big.NewInt(1), 64), large} {
+		sum := sha256.Sum256(x.Bytes())
+		if got, want := HashResult(x), hex.EncodeToString(sum[:]); got != want {
+			t.Errorf("HashResult() of a %d-bit value = %s, want the digest of Bytes() %s", x.BitLen(), got, want)
+		}
+	}
+}
+
+func TestLoggerAppend(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "nested", "audit.jsonl")
+	logger := NewLogger(path, 0, 0)
+
+	for i := 0; i < 3; i++ {
+		rec := Record{
+			Timestamp: time.Now(),
+			Args:      []string{"-n", "10"},
+			Config:    config.AppConfig{N: 10, Algo: "fast"},
+			Algorithm: "fast",
+			ExitCode:  i,
+		}
+		rec.SetResult(big.NewInt(55))
+		rec.SetDuration(1500 * time.Microsecond)
+		rec.AddResult("fast", time.Millisecond, big.NewInt(55), apperrors.ErrorKindNone, nil)
+		rec.AddResult("matrix", time.Second, nil, apperrors.ErrorKindTimeout, errors.New("deadline"))
+		if err := logger.Append(rec); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer f.Close()
+
+	lines := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatalf("line %d is not valid JSON: %v", lines, err)
+		}
+		if rec.ExitCode != lines {
+			t.Errorf("line %d: exit code %d", lines, rec.ExitCode)
+		}
+		if rec.Config.N != 10 || rec.ResultSHA256 == "" || rec.DurationNs != 1500000 {
+			t.Errorf("line %d: unexpected record %+v", lines, rec)
+		}
+		if len(rec.Results) != 2 || rec.Results[0].ErrorKind != apperrors.ErrorKindNone || rec.Results[0].ResultSHA256 == "" ||
+			rec.Results[1].ErrorKind != apperrors.ErrorKindTimeout || rec.Results[1].Error != "deadline" {
+			t.Errorf("line %d: unexpected record %+v", lines, rec)
+		}
+		lines++
+	}
+	if lines != 3 {
+		t.Errorf("expected 3 records, got %d", lines)
+	}
+}
+
+func TestLoggerRotation(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.jsonl")
+	// Room for three records, whatever the size of the configuration
+	line, err := json.Marshal(Record{Args: []string{strings.Repeat("x", 50)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	maxSize := int64(3 * (len(line) + 1)) // with the newlines
+	logger := NewLogger(path, maxSize, 2)
+
+	for i := 0; i < 10; i++ {
+		if err := logger.Append(Record{Args: []string{strings.Repeat("x", 50)}, ExitCode: i}); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	for _, name := range []string{"audit.jsonl", "audit.jsonl.1", "audit.jsonl.2"} {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("expected %s to exist: %v", name, err)
+		}
+		if info.Size() > maxSize {
+			t.Errorf("%s exceeds max size: %d bytes", name, info.Size())
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "audit.jsonl.3")); !os.IsNotExist(err) {
+		t.Error("expected at most 2 backups")
+	}
+}
+
+// TestLoggerRotationDenied checks that rotation, which deletes the oldest
+// log, goes through fsguard. Not parallel: it denies writes for the whole
+// process.
+func TestLoggerRotationDenied(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.jsonl")
+	names := []string{path, path + ".1", path + ".2"}
+	for _, name := range names {
+		if err := os.WriteFile(name, []byte("{}\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer fsguard.Deny()()
+
+	if err := NewLogger(path, 1, 2).rotate(); !errors.Is(err, fsguard.ErrDenied) {
+		t.Errorf("rotate with writes denied = %v, want ErrDenied", err)
+	}
+	for _, name := range names {
+		if _, err := os.Stat(name); err != nil {
+			t.Errorf("rotate with writes denied removed %s: %v", filepath.Base(name), err)
+		}
+	}
+}
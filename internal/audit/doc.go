@@ -0,0 +1,6 @@
+// Package audit provides an append-only, size-rotated log of fibcalc
+// invocations. Each run is recorded as a single JSON line containing the
+// command-line arguments, the resolved configuration, a digest of the result,
+// the duration and the exit code, so that results produced as part of
+// research workflows can be traced back to the exact invocation.
+package audit
@@ -100,6 +100,16 @@ func (b *BumpAllocatorAdapter) AllocFermatSlice(K, n int) ([]fermat, []big.Word,
 	return f, w, func() {} // no-op cleanup
 }
 
+// bumpOrPool returns the allocator of ba, or the pool allocator if ba is
+// nil, so that the WithBump variants of the transforms and products accept a
+// nil bump allocator.
+func bumpOrPool(ba *BumpAllocator) TempAllocator {
+	if ba == nil {
+		return defaultPoolAllocator
+	}
+	return NewBumpAllocatorAdapter(ba)
+}
+
 // defaultPoolAllocator is a shared instance of PoolAllocator.
 var defaultPoolAllocator = &PoolAllocator{}
 
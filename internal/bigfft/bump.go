@@ -27,6 +27,9 @@ import (
 type BumpAllocator struct {
 	buffer []big.Word
 	offset int
+	// spilled is true for a buffer mapped from a file by Spill, which also
+	// holds the transform outputs.
+	spilled bool
 }
 
 // bumpAllocatorPool pools BumpAllocator instances for reuse.
@@ -54,6 +54,20 @@ func Mul(x, y *big.Int) (res *big.Int, err error) {
 // MulTo computes the product x*y and stores the result in z.
 // It can be used instead of the Mul method of *big.Int from math/big package.
 func MulTo(z, x, y *big.Int) (res *big.Int, err error) {
+	return MulToWithCache(z, x, y, GetTransformCache())
+}
+
+// MulToWithCache is like MulTo but uses the given transform cache instead of
+// the global one, so that concurrent calculations can use independent cache
+// settings. A nil cache disables transform caching.
+func MulToWithCache(z, x, y *big.Int, cache *TransformCache) (res *big.Int, err error) {
+	return MulToWithSpill(z, x, y, cache, nil)
+}
+
+// MulToWithSpill is like MulToWithCache but maps the transform buffers of
+// the multiplication from temporary files when they exceed the threshold of
+// spill (see Spill). A nil spill keeps them on the heap.
+func MulToWithSpill(z, x, y *big.Int, cache *TransformCache, spill *Spill) (res *big.Int, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			err = fmt.Errorf("panic in bigfft.MulTo: %v\nStack: %s", r, debug.Stack())
@@ -64,7 +78,7 @@ func MulTo(z, x, y *big.Int) (res *big.Int, err error) {
 	if xwords > fftThreshold && ywords > fftThreshold {
 		var xb, yb nat = x.Bits(), y.Bits()
 		// Reuse z's existing buffer if available
-		zb, err := fftmulTo(z.Bits(), xb, yb)
+		zb, err := fftmulToCache(z.Bits(), xb, yb, cache, spill)
 		if err != nil {
 			return nil, err
 		}
@@ -93,7 +107,21 @@ func Sqr(x *big.Int) (res *big.Int, err error) {
 	return new(big.Int).Mul(x, x), nil
 }
 
+// SqrTo computes x*x and stores the result in z, reusing its buffer.
 func SqrTo(z, x *big.Int) (res *big.Int, err error) {
+	return SqrToWithCache(z, x, GetTransformCache())
+}
+
+// SqrToWithCache is like SqrTo but uses the given transform cache instead of
+// the global one. A nil cache disables transform caching.
+func SqrToWithCache(z, x *big.Int, cache *TransformCache) (res *big.Int, err error) {
+	return SqrToWithSpill(z, x, cache, nil)
+}
+
+// SqrToWithSpill is like SqrToWithCache but maps the transform buffers of
+// the squaring from temporary files when they exceed the threshold of spill
+// (see Spill). A nil spill keeps them on the heap.
+func SqrToWithSpill(z, x *big.Int, cache *TransformCache, spill *Spill) (res *big.Int, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			err = fmt.Errorf("panic in bigfft.SqrTo: %v\nStack: %s", r, debug.Stack())
@@ -102,7 +130,7 @@ func SqrTo(z, x *big.Int) (res *big.Int, err error) {
 	xwords := len(x.Bits())
 	if xwords > fftThreshold {
 		var xb nat = x.Bits()
-		zb, err := fftsqrTo(z.Bits(), xb)
+		zb, err := fftsqrToCache(z.Bits(), xb, cache, spill)
 		if err != nil {
 			return nil, err
 		}
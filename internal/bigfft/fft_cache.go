@@ -87,9 +87,17 @@ func NewTransformCache(config TransformCacheConfig) *TransformCache {
 }
 
 // SetCacheLogger configures the logger for the global FFT transform cache.
+// It is safe to call while the cache is in use.
 func SetCacheLogger(l zerolog.Logger) {
-	cache := GetTransformCache()
-	cache.logger = l
+	GetTransformCache().SetLogger(l)
+}
+
+// SetLogger configures the logger of the cache statistics. It is safe to
+// call while the cache is in use.
+func (tc *TransformCache) SetLogger(l zerolog.Logger) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.logger = l
 }
 
 // globalTransformCache is the package-level transform cache.
@@ -106,6 +114,10 @@ func GetTransformCache() *TransformCache {
 
 // SetTransformCacheConfig updates the global cache configuration.
 // This should be called before any FFT operations for consistent behavior.
+// It is safe to call while the cache is in use, but it affects every user
+// of the global cache: callers that need their own settings (such as
+// concurrent calculations with different options) should create a cache
+// with NewTransformCache and pass it explicitly instead.
 func SetTransformCacheConfig(config TransformCacheConfig) {
 	cache := GetTransformCache()
 	cache.mu.Lock()
@@ -166,7 +178,7 @@ func computeKey(data nat, k uint, n int) uint64 {
 // be modified. PolValues.Mul() and PolValues.Sqr() are safe as they create new
 // result values without mutating the receiver.
 func (tc *TransformCache) Get(data nat, k uint, n int) (PolValues, bool) {
-	if !tc.config.Enabled || len(data)*_W < tc.config.MinBitLen {
+	if !tc.acceptsLen(len(data) * _W) {
 		return PolValues{}, false
 	}
 
@@ -224,8 +236,9 @@ func (tc *TransformCache) logPeriodicStats() {
 	tc.mu.RLock()
 	size := tc.lru.Len()
 	currentBytes := tc.currentBytes
+	logger := tc.logger
 	tc.mu.RUnlock()
-	tc.logger.Debug().
+	logger.Debug().
 		Uint64("hits", hits).
 		Uint64("misses", misses).
 		Float64("hit_rate", hitRate).
@@ -237,7 +250,7 @@ func (tc *TransformCache) logPeriodicStats() {
 
 // Put stores a transform result in the cache.
 func (tc *TransformCache) Put(data nat, pv PolValues) {
-	if !tc.config.Enabled || len(data)*_W < tc.config.MinBitLen {
+	if !tc.acceptsLen(len(data) * _W) {
 		return
 	}
 
@@ -350,6 +363,18 @@ func (tc *TransformCache) Clear() {
 	tc.evictions.Store(0)
 }
 
+// Disable drops the cached entries and stops caching, keeping the
+// statistics. It gives the memory of the cache back under memory pressure.
+func (tc *TransformCache) Disable() {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	tc.config.Enabled = false
+	tc.entries = make(map[uint64]*list.Element)
+	tc.lru.Init()
+	tc.currentBytes = 0
+}
+
 // ─────────────────────────────────────────────────────────────────────────────
 // Cached Transform Functions
 // ─────────────────────────────────────────────────────────────────────────────
@@ -363,44 +388,49 @@ func polyBitLen(p *Poly) int {
 	return totalWords * _W
 }
 
+// accepts reports whether a polynomial is worth caching under the cache's
+// current configuration. A nil cache accepts nothing.
+func (tc *TransformCache) accepts(p *Poly) bool {
+	if tc == nil {
+		return false
+	}
+	return tc.acceptsLen(polyBitLen(p))
+}
+
+// acceptsLen reports whether a value of bitLen bits is worth caching under
+// the cache's current configuration.
+func (tc *TransformCache) acceptsLen(bitLen int) bool {
+	tc.mu.RLock()
+	enabled, minBitLen := tc.config.Enabled, tc.config.MinBitLen
+	tc.mu.RUnlock()
+	return enabled && bitLen >= minBitLen
+}
+
 // TransformCached is like Transform but uses the global cache.
 // If the transform result is cached, it returns the cached value.
 // Otherwise, it computes the transform and caches the result.
 func (p *Poly) TransformCached(n int) (PolValues, error) {
-	cache := GetTransformCache()
-
-	// Check if caching is applicable
-	if !cache.config.Enabled || polyBitLen(p) < cache.config.MinBitLen {
-		return p.Transform(n)
-	}
-
-	// Compute key directly from polynomial coefficients (no intermediate allocation)
-	key := computePolyKey(p, p.K, n)
-
-	// Try cache lookup
-	if cached, found := cache.getByKey(key); found {
-		return cached, nil
-	}
-
-	// Compute transform
-	pv, err := p.Transform(n)
-	if err != nil {
-		return PolValues{}, err
-	}
-
-	// Cache the result
-	cache.putByKey(key, pv)
-
-	return pv, nil
+	return p.transformCachedIn(GetTransformCache(), n, nil)
 }
 
 // TransformCachedWithBump is like TransformWithBump but uses the global cache.
 func (p *Poly) TransformCachedWithBump(n int, ba *BumpAllocator) (PolValues, error) {
-	cache := GetTransformCache()
+	return p.transformCachedIn(GetTransformCache(), n, ba)
+}
+
+// transformCachedIn transforms p using the given cache (nil disables
+// caching) and, if ba is non-nil, the bump allocator for temporaries.
+func (p *Poly) transformCachedIn(cache *TransformCache, n int, ba *BumpAllocator) (PolValues, error) {
+	transform := func() (PolValues, error) {
+		if ba != nil {
+			return p.TransformWithBump(n, ba)
+		}
+		return p.Transform(n)
+	}
 
 	// Check if caching is applicable
-	if !cache.config.Enabled || polyBitLen(p) < cache.config.MinBitLen {
-		return p.TransformWithBump(n, ba)
+	if !cache.accepts(p) {
+		return transform()
 	}
 
 	// Compute key directly from polynomial coefficients (no intermediate allocation)
@@ -408,11 +438,13 @@ func (p *Poly) TransformCachedWithBump(n int, ba *BumpAllocator) (PolValues, err
 
 	// Try cache lookup
 	if cached, found := cache.getByKey(key); found {
+		// The residues come from p, so a corrupted entry is caught
+		cached.check = newParanoidCheck(p)
 		return cached, nil
 	}
 
 	// Compute transform
-	pv, err := p.TransformWithBump(n, ba)
+	pv, err := transform()
 	if err != nil {
 		return PolValues{}, err
 	}
@@ -449,13 +481,19 @@ func (p *Poly) MulCached(q *Poly) (Poly, error) {
 
 // MulCachedWithBump multiplies p and q using cached transforms and bump allocator.
 func (p *Poly) MulCachedWithBump(q *Poly, ba *BumpAllocator) (Poly, error) {
+	return p.mulCachedIn(GetTransformCache(), q, ba)
+}
+
+// mulCachedIn multiplies p and q using the given transform cache (nil
+// disables caching) and bump allocator.
+func (p *Poly) mulCachedIn(cache *TransformCache, q *Poly, ba *BumpAllocator) (Poly, error) {
 	n := valueSize(p.K, p.M, 2)
 
-	pv, err := p.TransformCachedWithBump(n, ba)
+	pv, err := p.transformCachedIn(cache, n, ba)
 	if err != nil {
 		return Poly{}, err
 	}
-	qv, err := q.TransformCachedWithBump(n, ba)
+	qv, err := q.transformCachedIn(cache, n, ba)
 	if err != nil {
 		return Poly{}, err
 	}
@@ -493,9 +531,15 @@ func (p *Poly) SqrCached() (Poly, error) {
 
 // SqrCachedWithBump computes p*p using cached transform and bump allocator.
 func (p *Poly) SqrCachedWithBump(ba *BumpAllocator) (Poly, error) {
+	return p.sqrCachedIn(GetTransformCache(), ba)
+}
+
+// sqrCachedIn computes p*p using the given transform cache (nil disables
+// caching) and bump allocator.
+func (p *Poly) sqrCachedIn(cache *TransformCache, ba *BumpAllocator) (Poly, error) {
 	n := valueSize(p.K, p.M, 2)
 
-	pv, err := p.TransformCachedWithBump(n, ba)
+	pv, err := p.transformCachedIn(cache, n, ba)
 	if err != nil {
 		return Poly{}, err
 	}
@@ -4,6 +4,8 @@ import (
 	"math/big"
 	"sync"
 	"testing"
+
+	"github.com/rs/zerolog"
 )
 
 // ─────────────────────────────────────────────────────────────────────────────
@@ -358,6 +360,32 @@ func TestTransformCacheClear(t *testing.T) {
 	}
 }
 
+func TestTransformCacheDisable(t *testing.T) {
+	t.Parallel()
+	cache := NewTransformCache(TransformCacheConfig{MaxEntries: 10, MinBitLen: 64, Enabled: true})
+
+	testData := make(nat, 10)
+	for i := range testData {
+		testData[i] = big.Word(i + 1)
+	}
+	cache.Put(testData, PolValues{K: 4, N: 100, Values: []fermat{{1, 2, 3}}})
+	cache.Get(testData, 4, 100)
+
+	cache.Disable()
+
+	stats := cache.Stats()
+	if stats.Size != 0 || stats.Bytes != 0 {
+		t.Errorf("expected an empty cache after Disable, got %d entries of %d bytes", stats.Size, stats.Bytes)
+	}
+	if stats.Hits != 1 {
+		t.Errorf("expected Disable to keep the statistics, got %d hits", stats.Hits)
+	}
+	cache.Put(testData, PolValues{K: 4, N: 100, Values: []fermat{{1, 2, 3}}})
+	if stats := cache.Stats(); stats.Size != 0 {
+		t.Errorf("expected Put to be ignored after Disable, got %d entries", stats.Size)
+	}
+}
+
 func TestTransformCacheConcurrency(t *testing.T) {
 	t.Parallel()
 	config := TransformCacheConfig{
@@ -578,3 +606,26 @@ func BenchmarkCachePut(b *testing.B) {
 		cache.Put(testData, mockValues)
 	}
 }
+
+// TestSetCacheLoggerConcurrent verifies that the global cache logger can be
+// replaced while the cache is in use (run with -race).
+func TestSetCacheLoggerConcurrent(t *testing.T) {
+	t.Parallel()
+	cache := GetTransformCache()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for range cacheLogInterval {
+			cache.logPeriodicStats()
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for range 100 {
+			SetCacheLogger(zerolog.Nop())
+		}
+	}()
+	wg.Wait()
+}
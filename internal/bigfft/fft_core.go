@@ -4,12 +4,13 @@ package bigfft
 // of src, a length 1<<k vector of numbers modulo b^n+1
 // where b = 1<<_W.
 func fourier(dst []fermat, src []fermat, backward bool, n int, k uint) error {
-	return fourierWithState(dst, src, backward, n, k, nil)
+	return fourierWithState(dst, src, backward, n, k, nil, nil)
 }
 
 // fourierWithState performs the Fourier transform with optional pre-allocated state.
-// If state is nil, temporary buffers are allocated from the pool.
-func fourierWithState(dst []fermat, src []fermat, backward bool, n int, k uint, state *fftState) error {
+// If state is nil, temporary buffers are allocated from the pool. The work
+// is counted on meter, which may be nil.
+func fourierWithState(dst []fermat, src []fermat, backward bool, n int, k uint, state *fftState, meter *WorkMeter) error {
 	// Use pooled state if not provided
 	var tmp, tmp2 fermat
 	if state != nil {
@@ -23,18 +24,19 @@ func fourierWithState(dst []fermat, src []fermat, backward bool, n int, k uint,
 	}
 
 	// Call the recursive FFT function
-	return fourierRecursive(dst, src, backward, n, k, k, 0, tmp, tmp2)
+	return fourierRecursiveUnified(dst, src, backward, n, k, k, 0, tmp, tmp2, GetPoolAllocator(), meter)
 }
 
 // fourierWithBump performs the Fourier transform using a bump allocator for
 // temporary buffers. This provides better cache locality than fourierWithState.
-func fourierWithBump(dst []fermat, src []fermat, backward bool, n int, k uint, ba *BumpAllocator) error {
+// The work is counted on meter, which may be nil.
+func fourierWithBump(dst []fermat, src []fermat, backward bool, n int, k uint, ba *BumpAllocator, meter *WorkMeter) error {
 	tmp := ba.AllocFermat(n)
 	tmp2 := ba.AllocFermat(n)
 
 	// Use the unified recursive function with bump allocator adapter
 	alloc := NewBumpAllocatorAdapter(ba)
-	return fourierRecursiveUnified(dst, src, backward, n, k, k, 0, tmp, tmp2, alloc)
+	return fourierRecursiveUnified(dst, src, backward, n, k, k, 0, tmp, tmp2, alloc, meter)
 }
 
 func fftmul(x, y nat) (nat, error) {
@@ -52,18 +54,28 @@ func fftmul(x, y nat) (nat, error) {
 // are cached and reused for repeated multiplications of the same values,
 // providing 15-30% speedup in iterative algorithms like Fibonacci.
 func fftmulTo(dst, x, y nat) (nat, error) {
+	return fftmulToCache(dst, x, y, GetTransformCache(), nil)
+}
+
+// fftmulToCache is fftmulTo with an explicit transform cache (nil disables
+// caching) and spill (nil keeps every buffer on the heap).
+func fftmulToCache(dst, x, y nat, cache *TransformCache, spill *Spill) (nat, error) {
 	k, m := fftSize(x, y)
 
-	// Estimate and acquire bump allocator for temporary allocations
+	// Estimate and acquire bump allocator for temporary allocations; the
+	// outputs are the values of x and y, their product and its inverse
 	wordLen := len(x) + len(y)
-	ba := AcquireBumpAllocator(EstimateBumpCapacity(wordLen))
-	defer ReleaseBumpAllocator(ba)
+	ba, release, err := spill.acquireBump(EstimateBumpCapacity(wordLen), 4*transformWords(k, m))
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 
 	xp := polyFromNat(x, k, m)
 	yp := polyFromNat(y, k, m)
 
 	// Use cached multiplication when cache is enabled
-	rp, err := xp.MulCachedWithBump(&yp, ba)
+	rp, err := xp.mulCachedIn(cache, &yp, ba)
 	if err != nil {
 		return nil, err
 	}
@@ -85,17 +97,27 @@ func fftsqr(x nat) (nat, error) {
 // are cached and reused for repeated squaring of the same values,
 // providing significant speedup in iterative algorithms like Fibonacci.
 func fftsqrTo(dst, x nat) (nat, error) {
+	return fftsqrToCache(dst, x, GetTransformCache(), nil)
+}
+
+// fftsqrToCache is fftsqrTo with an explicit transform cache (nil disables
+// caching) and spill (nil keeps every buffer on the heap).
+func fftsqrToCache(dst, x nat, cache *TransformCache, spill *Spill) (nat, error) {
 	k, m := fftSizeSqr(x)
 
-	// Estimate and acquire bump allocator for temporary allocations
+	// Estimate and acquire bump allocator for temporary allocations; the
+	// outputs are the values of x, their square and its inverse
 	wordLen := 2 * len(x)
-	ba := AcquireBumpAllocator(EstimateBumpCapacity(wordLen))
-	defer ReleaseBumpAllocator(ba)
+	ba, release, err := spill.acquireBump(EstimateBumpCapacity(wordLen), 3*transformWords(k, m))
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 
 	xp := polyFromNat(x, k, m)
 
 	// Use cached squaring when cache is enabled
-	rp, err := xp.SqrCachedWithBump(ba)
+	rp, err := xp.sqrCachedIn(cache, ba)
 	if err != nil {
 		return nil, err
 	}
@@ -2,6 +2,8 @@ package bigfft
 
 import (
 	"math/big"
+
+	"github.com/agbru/fibcalc/internal/parallel"
 )
 
 // Poly represents an integer via a polynomial in Z[x]/(x^K+1)
@@ -12,6 +14,10 @@ type Poly struct {
 	K uint  // K is such that 1<<K is the FFT length.
 	M int   // the M such that P(b^M) is the original number.
 	A []nat // a slice of at most 1<<K M-word coefficients.
+
+	// Meter, if set, counts the work of the transforms of p and of the
+	// values derived from it.
+	Meter *WorkMeter
 }
 
 // polyFromNat slices the number x into a Polynomial
@@ -105,7 +111,7 @@ func (p *Poly) Mul(q *Poly) (Poly, error) {
 // MulWithBump multiplies p and q using a bump allocator for temporary allocations.
 // This provides better cache locality and reduces GC pressure.
 func (p *Poly) MulWithBump(q *Poly, ba *BumpAllocator) (Poly, error) {
-	return p.mul(q, NewBumpAllocatorAdapter(ba))
+	return p.mul(q, bumpOrPool(ba))
 }
 
 func (p *Poly) mul(q *Poly, alloc TempAllocator) (Poly, error) {
@@ -140,6 +146,13 @@ type PolValues struct {
 	K      uint     // K is such that 1<<K is the FFT length.
 	N      int      // the length of coefficients, n*_W a multiple of K/4.
 	Values []fermat // a slice of 1<<K (n+1)-word values
+
+	// Meter, if set, counts the work of the operations on the values and
+	// of the values derived from them.
+	Meter *WorkMeter
+
+	// check holds the operand residues of the paranoid mode, nil otherwise.
+	check *paranoidCheck
 }
 
 // Transform evaluates p at θ^i for i = 0...K-1, where
@@ -152,7 +165,7 @@ func (p *Poly) Transform(n int) (PolValues, error) {
 // for temporary allocations. This provides better cache locality and reduces
 // GC pressure compared to Transform().
 func (p *Poly) TransformWithBump(n int, ba *BumpAllocator) (PolValues, error) {
-	return p.transform(n, NewBumpAllocatorAdapter(ba))
+	return p.transform(n, bumpOrPool(ba))
 }
 
 func (p *Poly) transform(n int, alloc TempAllocator) (PolValues, error) {
@@ -165,7 +178,7 @@ func (p *Poly) transform(n int, alloc TempAllocator) (PolValues, error) {
 	defer cleanup()
 
 	// Use pooled allocation for output buffers (contiguous backing array)
-	valbits := acquireWordSliceUnsafe(wordCount)
+	valbits := allocOutput(alloc, wordCount)
 	values := acquireFermatSlice(K)
 
 	for i := 0; i < K; i++ {
@@ -182,16 +195,21 @@ func (p *Poly) transform(n int, alloc TempAllocator) (PolValues, error) {
 	}
 
 	if ba != nil {
-		if err := fourierWithBump(values, input, false, n, k, ba); err != nil {
+		if err := fourierWithBump(values, input, false, n, k, ba, p.Meter); err != nil {
 			return PolValues{}, err
 		}
 	} else {
-		if err := fourier(values, input, false, n, k); err != nil {
+		if err := fourierWithState(values, input, false, n, k, nil, p.Meter); err != nil {
 			return PolValues{}, err
 		}
 	}
 
-	return PolValues{k, n, values}, nil
+	if paranoid.Load() {
+		if err := checkNormalized(values, n, "forward transform"); err != nil {
+			return PolValues{}, err
+		}
+	}
+	return PolValues{K: k, N: n, Values: values, Meter: p.Meter, check: newParanoidCheck(p)}, nil
 }
 
 // InvTransform reconstructs p (modulo X^K - 1) from its
@@ -203,7 +221,7 @@ func (v *PolValues) InvTransform() (Poly, error) {
 // InvTransformWithBump reconstructs p (modulo X^K - 1) from its values,
 // using a bump allocator for temporary allocations.
 func (v *PolValues) InvTransformWithBump(ba *BumpAllocator) (Poly, error) {
-	return v.invTransform(NewBumpAllocatorAdapter(ba))
+	return v.invTransform(bumpOrPool(ba))
 }
 
 func (v *PolValues) invTransform(alloc TempAllocator) (Poly, error) {
@@ -213,7 +231,7 @@ func (v *PolValues) invTransform(alloc TempAllocator) (Poly, error) {
 
 	// Perform an inverse Fourier transform to recover p.
 	// Use pooled allocation for output buffers (contiguous backing array)
-	pbits := acquireWordSliceUnsafe(wordCount)
+	pbits := allocOutput(alloc, wordCount)
 	p := acquireFermatSlice(K)
 	for i := 0; i < K; i++ {
 		p[i] = fermat(pbits[i*(n+1) : (i+1)*(n+1)])
@@ -226,11 +244,11 @@ func (v *PolValues) invTransform(alloc TempAllocator) (Poly, error) {
 	}
 
 	if ba != nil {
-		if err := fourierWithBump(p, v.Values, true, n, k, ba); err != nil {
+		if err := fourierWithBump(p, v.Values, true, n, k, ba, v.Meter); err != nil {
 			return Poly{}, err
 		}
 	} else {
-		if err := fourier(p, v.Values, true, n, k); err != nil {
+		if err := fourierWithState(p, v.Values, true, n, k, nil, v.Meter); err != nil {
 			return Poly{}, err
 		}
 	}
@@ -248,7 +266,15 @@ func (v *PolValues) invTransform(alloc TempAllocator) (Poly, error) {
 		a[i] = nat(p[i])
 	}
 
-	return Poly{K: k, M: 0, A: a}, nil
+	if paranoid.Load() {
+		if err := checkNormalized(p, n, "inverse transform"); err != nil {
+			return Poly{}, err
+		}
+		if err := v.check.checkProduct(p, k); err != nil {
+			return Poly{}, err
+		}
+	}
+	return Poly{K: k, M: 0, A: a, Meter: v.Meter}, nil
 }
 
 // NTransform evaluates p at θω^i for i = 0...K-1, where
@@ -287,7 +313,7 @@ func (p *Poly) NTransform(n int) PolValues {
 		values[i] = fermat(valbits[i*(n+1) : (i+1)*(n+1)])
 	}
 	fourier(values, twisted, false, n, k)
-	return PolValues{k, n, values}
+	return PolValues{K: k, N: n, Values: values}
 }
 
 // InvNTransform reconstructs a polynomial from its values at
@@ -325,32 +351,41 @@ func (p *PolValues) Mul(q *PolValues) (PolValues, error) {
 // MulWithBump returns the pointwise product of p and q, using a bump allocator
 // for temporary buffers.
 func (p *PolValues) MulWithBump(q *PolValues, ba *BumpAllocator) (PolValues, error) {
-	return p.mul(q, NewBumpAllocatorAdapter(ba))
+	return p.mul(q, bumpOrPool(ba))
 }
 
 func (p *PolValues) mul(q *PolValues, alloc TempAllocator) (PolValues, error) {
 	n := p.N
 	K := len(p.Values)
 	var r PolValues
-	r.K, r.N = p.K, p.N
+	r.K, r.N, r.Meter = p.K, p.N, p.Meter
+	r.check = p.check.product(q.check)
 
 	// Use pooled allocation for returned data (contiguous backing array)
 	r.Values = acquireFermatSlice(K)
 	wordCount := K * (n + 1)
-	bits := acquireWordSliceUnsafe(wordCount)
+	bits := allocOutput(alloc, wordCount)
 
 	// Use allocator for temporary multiplication result
 	// The temporary buffer needs to be 8*n (or 8*n - 1 if optimized)
 	// We use 8*n to be safe and consistent with previous code
 	buf, cleanup := alloc.AllocFermatTemp(8 * n)
 	defer cleanup()
+	cost := pointwiseCost(n)
 
 	for i := 0; i < K; i++ {
 		r.Values[i] = bits[i*(n+1) : (i+1)*(n+1)]
 		z := buf.Mul(p.Values[i], q.Values[i])
 		copy(r.Values[i], z)
+		p.Meter.add(cost)
+		parallel.MaybeYield()
 	}
 
+	if paranoid.Load() {
+		if err := checkNormalized(r.Values, n, "pointwise product"); err != nil {
+			return PolValues{}, err
+		}
+	}
 	return r, nil
 }
 
@@ -363,31 +398,40 @@ func (p *PolValues) Sqr() (PolValues, error) {
 // SqrWithBump returns the pointwise square of p, using a bump allocator
 // for temporary buffers.
 func (p *PolValues) SqrWithBump(ba *BumpAllocator) (PolValues, error) {
-	return p.sqr(NewBumpAllocatorAdapter(ba))
+	return p.sqr(bumpOrPool(ba))
 }
 
 func (p *PolValues) sqr(alloc TempAllocator) (PolValues, error) {
 	n := p.N
 	K := len(p.Values)
 	var r PolValues
-	r.K, r.N = p.K, p.N
+	r.K, r.N, r.Meter = p.K, p.N, p.Meter
+	r.check = p.check.product(p.check)
 
 	// Use pooled allocation for returned data (contiguous backing array)
 	r.Values = acquireFermatSlice(K)
 	wordCount := K * (n + 1)
-	bits := acquireWordSliceUnsafe(wordCount)
+	bits := allocOutput(alloc, wordCount)
 
 	// Use allocator for temporary multiplication result
 	buf, cleanup := alloc.AllocFermatTemp(8 * n)
 	defer cleanup()
+	cost := pointwiseCost(n)
 
 	for i := 0; i < K; i++ {
 		r.Values[i] = bits[i*(n+1) : (i+1)*(n+1)]
 		// Square: use specialized squaring
 		z := buf.Sqr(p.Values[i])
 		copy(r.Values[i], z)
+		p.Meter.add(cost)
+		parallel.MaybeYield()
 	}
 
+	if paranoid.Load() {
+		if err := checkNormalized(r.Values, n, "pointwise square"); err != nil {
+			return PolValues{}, err
+		}
+	}
 	return r, nil
 }
 
@@ -412,5 +456,6 @@ func (p *PolValues) Clone() PolValues {
 		K:      p.K,
 		N:      p.N,
 		Values: values,
+		check:  p.check,
 	}
 }
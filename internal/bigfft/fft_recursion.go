@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"runtime"
 	"sync"
+
+	"github.com/agbru/fibcalc/internal/parallel"
 )
 
 // concurrencySemaphore is a buffered channel used to limit the number of
@@ -85,7 +87,8 @@ func GetFFTParallelismConfig() FFTParallelismConfig {
 //   - depth: current recursion depth
 //   - tmp, tmp2: temporary buffers for this goroutine
 //   - alloc: allocator for creating new temp buffers in parallel goroutines
-func fourierRecursiveUnified(dst, src []fermat, backward bool, n int, k, size, depth uint, tmp, tmp2 fermat, alloc TempAllocator) error {
+//   - meter: counts the butterflies done (may be nil)
+func fourierRecursiveUnified(dst, src []fermat, backward bool, n int, k, size, depth uint, tmp, tmp2 fermat, alloc TempAllocator, meter *WorkMeter) error {
 	idxShift := k - size
 	ω2shift := (4 * n * _W) >> size
 	if backward {
@@ -105,6 +108,7 @@ func fourierRecursiveUnified(dst, src []fermat, backward bool, n int, k, size, d
 	case 1:
 		dst[0].Add(src[0], src[1<<idxShift])
 		dst[1].Sub(src[0], src[1<<idxShift])
+		meter.add(butterflyWords * uint64(n+1))
 		return nil
 	}
 
@@ -134,11 +138,11 @@ func fourierRecursiveUnified(dst, src []fermat, backward bool, n int, k, size, d
 				defer cleanup1()
 				defer cleanup2()
 
-				errAsync = fourierRecursiveUnified(dst2, src[1<<idxShift:], backward, n, k, size-1, depth+1, t1, t2, alloc)
+				errAsync = fourierRecursiveUnified(dst2, src[1<<idxShift:], backward, n, k, size-1, depth+1, t1, t2, alloc, meter)
 			}()
 
 			// Run first half in current thread with current temps
-			errSync := fourierRecursiveUnified(dst1, src, backward, n, k, size-1, depth+1, tmp, tmp2, alloc)
+			errSync := fourierRecursiveUnified(dst1, src, backward, n, k, size-1, depth+1, tmp, tmp2, alloc, meter)
 
 			wg.Wait()
 			if errAsync != nil {
@@ -147,35 +151,39 @@ func fourierRecursiveUnified(dst, src []fermat, backward bool, n int, k, size, d
 			if errSync != nil {
 				return errSync
 			}
-			return executeReconstruction(dst1, dst2, ω2shift, tmp, tmp2)
+			return executeReconstruction(dst1, dst2, ω2shift, tmp, tmp2, meter)
 		default:
 			// Fallthrough to sequential
 		}
 	}
 
 	// Recursive calls (Sequential)
-	if err := fourierRecursiveUnified(dst1, src, backward, n, k, size-1, depth+1, tmp, tmp2, alloc); err != nil {
+	if err := fourierRecursiveUnified(dst1, src, backward, n, k, size-1, depth+1, tmp, tmp2, alloc, meter); err != nil {
 		return err
 	}
-	if err := fourierRecursiveUnified(dst2, src[1<<idxShift:], backward, n, k, size-1, depth+1, tmp, tmp2, alloc); err != nil {
+	if err := fourierRecursiveUnified(dst2, src[1<<idxShift:], backward, n, k, size-1, depth+1, tmp, tmp2, alloc, meter); err != nil {
 		return err
 	}
-	return executeReconstruction(dst1, dst2, ω2shift, tmp, tmp2)
+	return executeReconstruction(dst1, dst2, ω2shift, tmp, tmp2, meter)
 }
 
 // executeReconstruction applies the butterfly reconstruction step, combining
 // the two halves of the FFT transform using the twiddle factor shift.
-func executeReconstruction(dst1, dst2 []fermat, ω2shift int, tmp, tmp2 fermat) error {
+func executeReconstruction(dst1, dst2 []fermat, ω2shift int, tmp, tmp2 fermat, meter *WorkMeter) error {
 	for i := range dst1 {
 		tmp.ShiftHalf(dst2[i], i*ω2shift, tmp2)
 		dst2[i].Sub(dst1[i], tmp)
 		dst1[i].Add(dst1[i], tmp)
 	}
+	if len(dst1) > 0 {
+		meter.add(uint64(len(dst1)) * butterflyWords * uint64(len(dst1[0])))
+	}
+	parallel.MaybeYield()
 	return nil
 }
 
 // fourierRecursive is a convenience wrapper that uses pool allocation.
 // Kept for backward compatibility.
 func fourierRecursive(dst, src []fermat, backward bool, n int, k, size, depth uint, tmp, tmp2 fermat) error {
-	return fourierRecursiveUnified(dst, src, backward, n, k, size, depth, tmp, tmp2, GetPoolAllocator())
+	return fourierRecursiveUnified(dst, src, backward, n, k, size, depth, tmp, tmp2, GetPoolAllocator(), nil)
 }
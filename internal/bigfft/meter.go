@@ -0,0 +1,83 @@
+// This file provides WorkMeter, which measures the progress of FFT
+// operations while they run.
+
+package bigfft
+
+import (
+	"math"
+	"sync/atomic"
+)
+
+// butterflyWords is the number of word operations counted for one butterfly
+// of a transform (a shift, a subtraction and an addition of n+1 words, per
+// word of coefficient).
+const butterflyWords = 3
+
+// pointwiseExponent models the cost of a pointwise product of two
+// coefficients, which math/big multiplies with Karatsuba.
+const pointwiseExponent = 1.585
+
+// WorkMeter counts the work done by the transforms and pointwise products of
+// the Poly and PolValues values it is attached to (through their Meter
+// field). Work is measured in word operations, so that a caller who knows
+// the size of an operation can turn the count into a fraction with
+// TransformWork and PointwiseWork.
+//
+// A nil *WorkMeter counts nothing. A WorkMeter is safe for concurrent use,
+// including by the parallel branches of a transform.
+type WorkMeter struct {
+	done atomic.Uint64
+}
+
+// Done returns the work counted so far.
+//
+// Returns:
+//   - uint64: The number of word operations done.
+func (m *WorkMeter) Done() uint64 {
+	if m == nil {
+		return 0
+	}
+	return m.done.Load()
+}
+
+// add counts w word operations.
+func (m *WorkMeter) add(w uint64) {
+	if m != nil {
+		m.done.Add(w)
+	}
+}
+
+// TransformWork returns the work counted for one forward or inverse
+// transform of 1<<k values of n+1 words.
+//
+// Parameters:
+//   - k: The log2 of the FFT length.
+//   - n: The coefficient length in words (as passed to Transform).
+//
+// Returns:
+//   - uint64: The number of word operations.
+func TransformWork(k uint, n int) uint64 {
+	if k == 0 {
+		return 0
+	}
+	return (uint64(k) << (k - 1)) * butterflyWords * uint64(n+1)
+}
+
+// PointwiseWork returns the work counted for a pointwise product or square
+// of 1<<k values of n+1 words.
+//
+// Parameters:
+//   - k: The log2 of the FFT length.
+//   - n: The coefficient length in words (as passed to Transform).
+//
+// Returns:
+//   - uint64: The number of word operations.
+func PointwiseWork(k uint, n int) uint64 {
+	return (uint64(1) << k) * pointwiseCost(n)
+}
+
+// pointwiseCost returns the work counted for the product of two values of
+// n+1 words.
+func pointwiseCost(n int) uint64 {
+	return uint64(math.Pow(float64(n+1), pointwiseExponent))
+}
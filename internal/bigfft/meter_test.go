@@ -0,0 +1,60 @@
+package bigfft
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestWorkMeter_CountsPlannedWork(t *testing.T) {
+	t.Parallel()
+	x := new(big.Int).Lsh(big.NewInt(1), 200_000)
+	x.Sub(x, big.NewInt(1))
+	k, m := GetFFTParams(2*len(x.Bits()) + 2)
+	n := ValueSize(k, m, 2)
+
+	meter := new(WorkMeter)
+	p := PolyFromInt(x, k, m)
+	p.Meter = meter
+
+	v, err := p.Transform(n)
+	if err != nil {
+		t.Fatalf("Transform: %v", err)
+	}
+	want := TransformWork(k, n)
+	if got := meter.Done(); got != want {
+		t.Fatalf("after Transform, Done = %d, want %d", got, want)
+	}
+
+	sq, err := v.Sqr()
+	if err != nil {
+		t.Fatalf("Sqr: %v", err)
+	}
+	want += PointwiseWork(k, n)
+	if got := meter.Done(); got != want {
+		t.Fatalf("after Sqr, Done = %d, want %d", got, want)
+	}
+
+	r, err := sq.InvTransform()
+	if err != nil {
+		t.Fatalf("InvTransform: %v", err)
+	}
+	want += TransformWork(k, n)
+	if got := meter.Done(); got != want {
+		t.Fatalf("after InvTransform, Done = %d, want %d", got, want)
+	}
+
+	// The meter must not change the result.
+	r.M = m
+	if got := r.IntToBigInt(new(big.Int)); got.Cmp(new(big.Int).Mul(x, x)) != 0 {
+		t.Error("metered square differs from x*x")
+	}
+}
+
+func TestWorkMeter_Nil(t *testing.T) {
+	t.Parallel()
+	var m *WorkMeter
+	m.add(10)
+	if m.Done() != 0 {
+		t.Error("a nil meter should count nothing")
+	}
+}
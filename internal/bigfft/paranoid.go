@@ -0,0 +1,157 @@
+package bigfft
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"math/rand/v2"
+	"sync/atomic"
+)
+
+// ErrInvariant reports that a check of the paranoid mode failed: an FFT
+// multiplication produced a corrupted intermediate value.
+var ErrInvariant = errors.New("bigfft: invariant violated")
+
+// paranoidModulus is the prime 2^61-1. Paranoid checks compare values
+// modulo this prime, which costs one pass over the operands.
+const paranoidModulus = 1<<61 - 1
+
+// paranoidSamples is the number of product coefficients compared against
+// the slow reference after each inverse transform.
+const paranoidSamples = 16
+
+// paranoid enables the invariant checks (see SetParanoid).
+var paranoid atomic.Bool
+
+// SetParanoid enables or disables the paranoid mode. In paranoid mode,
+// every transform stage validates the Fermat-ring invariants of its values
+// (normalization, length), and every inverse transform checks the carry
+// bound of the product coefficients and compares a random sample of them
+// against a slow reference computed from the operands, modulo a prime.
+// A failed check makes the multiplication return an error wrapping
+// ErrInvariant instead of a silently wrong result.
+//
+// The checks make a few linear passes over the operands and coefficients of
+// each multiplication, a small cost next to the transforms; they are meant
+// for debugging and for validating long runs on suspect hardware.
+//
+// Parameters:
+//   - on: Whether to run the checks.
+func SetParanoid(on bool) {
+	paranoid.Store(on)
+}
+
+// Paranoid reports whether the paranoid mode is enabled.
+func Paranoid() bool {
+	return paranoid.Load()
+}
+
+// paranoidCheck carries what the checks of the values derived from a
+// transform need: the residues of the operands' coefficients.
+type paranoidCheck struct {
+	m    int      // words per operand coefficient
+	a, b []uint64 // coefficient residues of the operands; b is nil before a product
+}
+
+// newParanoidCheck returns the check state of the transform of p, or nil
+// outside the paranoid mode.
+func newParanoidCheck(p *Poly) *paranoidCheck {
+	if !paranoid.Load() {
+		return nil
+	}
+	a := make([]uint64, len(p.A))
+	for i, c := range p.A {
+		a[i] = natResidue(c)
+	}
+	return &paranoidCheck{m: p.M, a: a}
+}
+
+// product returns the check state of the pointwise product of the values
+// checked by c and d.
+func (c *paranoidCheck) product(d *paranoidCheck) *paranoidCheck {
+	if c == nil || d == nil {
+		return nil
+	}
+	return &paranoidCheck{m: c.m, a: c.a, b: d.a}
+}
+
+// checkNormalized validates the values of a transform stage: each one has
+// n+1 words and its last word is zero or one.
+func checkNormalized(values []fermat, n int, stage string) error {
+	for i, v := range values {
+		if len(v) != n+1 {
+			return fmt.Errorf("%w: %s: value %d has %d words, want %d", ErrInvariant, stage, i, len(v), n+1)
+		}
+		if v[n] > 1 {
+			return fmt.Errorf("%w: %s: value %d is not normalized (last word %d)", ErrInvariant, stage, i, v[n])
+		}
+	}
+	return nil
+}
+
+// checkProduct validates the coefficients of the cyclic product recovered
+// by an inverse transform of k: each one is below 2^(2·m·_W+k), the bound
+// of a sum of 2^k products of m-word coefficients, and sampled ones equal
+// the reference computed from the operands' residues.
+func (c *paranoidCheck) checkProduct(coeffs []fermat, k uint) error {
+	if c == nil || c.b == nil {
+		return nil
+	}
+	bound := 2*c.m*_W + int(k)
+	for i, v := range coeffs {
+		if l := natBitLen(nat(v)); l > bound {
+			return fmt.Errorf("%w: inverse transform: coefficient %d has %d bits, bound %d", ErrInvariant, i, l, bound)
+		}
+	}
+	K := len(coeffs)
+	for range paranoidSamples {
+		i := rand.IntN(K)
+		if got, want := natResidue(nat(coeffs[i])), c.reference(i, K); got != want {
+			return fmt.Errorf("%w: inverse transform: coefficient %d differs from the reference", ErrInvariant, i)
+		}
+	}
+	return nil
+}
+
+// reference returns the residue of coefficient i of the cyclic product of
+// length K: the sum of a[j]·b[(i-j) mod K].
+func (c *paranoidCheck) reference(i, K int) uint64 {
+	var sum uint64
+	for j, a := range c.a {
+		idx := ((i-j)%K + K) % K
+		if idx < len(c.b) {
+			sum = (sum + mulMod(a, c.b[idx])) % paranoidModulus
+		}
+	}
+	return sum
+}
+
+// natResidue returns x modulo paranoidModulus.
+func natResidue(x nat) uint64 {
+	var r uint64
+	for i := len(x) - 1; i >= 0; i-- {
+		// r·2^_W + x[i] as a 128-bit value (hi, lo)
+		hi, lo := r, uint64(x[i])
+		if _W == 32 {
+			hi, lo = r>>32, r<<32|uint64(x[i])
+		}
+		r = bits.Rem64(hi, lo, paranoidModulus)
+	}
+	return r
+}
+
+// mulMod returns a·b modulo paranoidModulus.
+func mulMod(a, b uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	return bits.Rem64(hi, lo, paranoidModulus)
+}
+
+// natBitLen returns the number of significant bits of x.
+func natBitLen(x nat) int {
+	for i := len(x) - 1; i >= 0; i-- {
+		if x[i] != 0 {
+			return i*_W + bits.Len(uint(x[i]))
+		}
+	}
+	return 0
+}
@@ -0,0 +1,11 @@
+//go:build bigfft_paranoid
+
+package bigfft
+
+// Builds with the bigfft_paranoid tag start in paranoid mode, so that the
+// whole test suite can run with the invariant checks:
+//
+//	go test -tags bigfft_paranoid ./...
+func init() {
+	SetParanoid(true)
+}
@@ -0,0 +1,98 @@
+package bigfft
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// enableParanoid turns the paranoid mode on for the duration of the test.
+func enableParanoid(t *testing.T) {
+	t.Helper()
+	SetParanoid(true)
+	t.Cleanup(func() { SetParanoid(false) })
+}
+
+func TestNatResidue(t *testing.T) {
+	t.Parallel()
+	modulus := new(big.Int).SetUint64(paranoidModulus)
+	for _, words := range []int{0, 1, 2, 7, 100} {
+		x := randomNat(t, words)
+		want := new(big.Int).Mod(new(big.Int).SetBits(x), modulus).Uint64()
+		if got := natResidue(x); got != want {
+			t.Errorf("natResidue(%d words) = %d, want %d", words, got, want)
+		}
+	}
+}
+
+func TestParanoidMulSqr(t *testing.T) {
+	enableParanoid(t)
+	x := new(big.Int).SetBits(randomNat(t, 5000))
+	y := new(big.Int).SetBits(randomNat(t, 4000))
+
+	got, err := Mul(x, y)
+	if err != nil {
+		t.Fatalf("Mul failed in paranoid mode: %v", err)
+	}
+	if got.Cmp(new(big.Int).Mul(x, y)) != 0 {
+		t.Error("Mul result differs from math/big")
+	}
+	got, err = Sqr(x)
+	if err != nil {
+		t.Fatalf("Sqr failed in paranoid mode: %v", err)
+	}
+	if got.Cmp(new(big.Int).Mul(x, x)) != 0 {
+		t.Error("Sqr result differs from math/big")
+	}
+}
+
+func TestParanoidDetectsCorruption(t *testing.T) {
+	enableParanoid(t)
+	k, m := uint(6), 4
+	n := valueSize(k, m, 2)
+	p := polyFromNat(randomNat(t, 100), k, m)
+	q := polyFromNat(randomNat(t, 100), k, m)
+
+	pv, err := p.Transform(n)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+	qv, err := q.Transform(n)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+	rv, err := pv.Mul(&qv)
+	if err != nil {
+		t.Fatalf("Mul failed: %v", err)
+	}
+	if _, err := rv.InvTransform(); err != nil {
+		t.Fatalf("InvTransform failed on a sound product: %v", err)
+	}
+
+	// A flipped bit in one value spreads to every coefficient
+	rv.Values[3][0] ^= 1 << 7
+	if _, err := rv.InvTransform(); !errors.Is(err, ErrInvariant) {
+		t.Errorf("InvTransform after a bit flip: err = %v, want ErrInvariant", err)
+	}
+
+	// An unnormalized value is caught by the stage check
+	rv.Values[3][0] ^= 1 << 7
+	rv.Values[5][n] = 2
+	if _, err := rv.InvTransform(); !errors.Is(err, ErrInvariant) {
+		t.Errorf("InvTransform of unnormalized values: err = %v, want ErrInvariant", err)
+	}
+}
+
+// randomNat returns a random nat of the given length.
+func randomNat(t *testing.T, words int) nat {
+	t.Helper()
+	if words == 0 {
+		return nil
+	}
+	buf := make([]byte, words*_W/8)
+	if _, err := rand.Read(buf); err != nil {
+		t.Fatal(err)
+	}
+	return nat(new(big.Int).SetBytes(buf).Bits())
+}
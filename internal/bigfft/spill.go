@@ -0,0 +1,137 @@
+// This file provides Spill, which moves large transform buffers from the
+// heap to memory-mapped temporary files.
+
+package bigfft
+
+import (
+	"fmt"
+	"math/big"
+	"sync/atomic"
+
+	"github.com/agbru/fibcalc/internal/fibonacci/memory"
+)
+
+// Spill places the transform buffers of FFT multiplications above a size
+// threshold in memory-mapped temporary files instead of the heap. The
+// operating system pages them in and out as the transforms walk through
+// them, so a calculation can run slightly beyond the available RAM, at the
+// cost of disk I/O on every transform that no longer fits. Below the
+// threshold, buffers come from the usual pools.
+//
+// A nil *Spill spills nothing. A Spill is safe for concurrent use.
+type Spill struct {
+	thresholdBytes int64
+	dir            string
+
+	buffers atomic.Int64
+	bytes   atomic.Int64
+}
+
+// SpillStats summarizes the buffers a Spill moved to disk.
+type SpillStats struct {
+	// Buffers is the number of workspaces mapped from files.
+	Buffers int64 `json:"buffers"`
+	// Bytes is the total size of the transform buffers placed in them.
+	Bytes int64 `json:"bytes"`
+}
+
+// NewSpill returns a Spill of the buffers larger than thresholdBytes.
+//
+// Parameters:
+//   - thresholdBytes: The workspace size above which buffers are mapped
+//     from files; 0 or less disables spilling (NewSpill returns nil).
+//   - dir: The directory of the temporary files; os.TempDir() if empty.
+//
+// Returns:
+//   - *Spill: The spill, or nil if disabled.
+func NewSpill(thresholdBytes int64, dir string) *Spill {
+	if thresholdBytes <= 0 {
+		return nil
+	}
+	return &Spill{thresholdBytes: thresholdBytes, dir: dir}
+}
+
+// Stats returns the buffers spilled so far.
+//
+// Returns:
+//   - SpillStats: The counts; zero for a nil Spill.
+func (s *Spill) Stats() SpillStats {
+	if s == nil {
+		return SpillStats{}
+	}
+	return SpillStats{Buffers: s.buffers.Load(), Bytes: s.bytes.Load()}
+}
+
+// Workspace returns a bump allocator mapped from a temporary file for a
+// sequence of transforms, if their buffers exceed the threshold. Pass it to
+// the WithBump variants of the transforms and products: they then allocate
+// both their temporaries and their outputs in the file. The outputs must
+// not be used after release.
+//
+// Parameters:
+//   - temps: The words of temporaries the transforms need.
+//   - outputs: The words of the values they produce.
+//
+// Returns:
+//   - *BumpAllocator: The mapped allocator, or nil to use the pools (the
+//     WithBump variants accept nil).
+//   - func(): Releases the workspace and deletes its file.
+//   - error: An error if the file could not be created or mapped.
+func (s *Spill) Workspace(temps, outputs int) (*BumpAllocator, func(), error) {
+	if !s.spills(temps + outputs) {
+		return nil, func() {}, nil
+	}
+	return s.mapBump(temps + outputs)
+}
+
+// spills reports whether a workspace of the given words exceeds the
+// threshold.
+func (s *Spill) spills(words int) bool {
+	return s != nil && int64(words)*int64(_W/8) > s.thresholdBytes
+}
+
+// mapBump returns a bump allocator on a mapped file of the given words and
+// the function that unmaps it, recording the words used on release.
+func (s *Spill) mapBump(words int) (*BumpAllocator, func(), error) {
+	m, err := memory.NewMappedWords(s.dir, words)
+	if err != nil {
+		return nil, nil, fmt.Errorf("bigfft: spilling %d bytes of transform buffers: %w", int64(words)*int64(_W/8), err)
+	}
+	ba := &BumpAllocator{buffer: m.Words, spilled: true}
+	s.buffers.Add(1)
+	release := func() {
+		s.bytes.Add(int64(ba.Used()) * int64(_W/8))
+		m.Close()
+	}
+	return ba, release, nil
+}
+
+// acquireBump returns the bump allocator of a multiplication and the
+// function that releases it. temps is the capacity its temporaries need,
+// outputs the size of the transform outputs (values, products and inverse
+// transform) in words. If both together exceed the threshold, the allocator
+// is mapped from a file holding the outputs as well (see allocOutput);
+// otherwise it comes from the pool, sized for the temporaries.
+func (s *Spill) acquireBump(temps, outputs int) (*BumpAllocator, func(), error) {
+	if !s.spills(temps + outputs) {
+		ba := AcquireBumpAllocator(temps)
+		return ba, func() { ReleaseBumpAllocator(ba) }, nil
+	}
+	return s.mapBump(temps + outputs)
+}
+
+// transformWords returns the size in words of the values of a transform of
+// length 1<<k on coefficients of m words.
+func transformWords(k uint, m int) int {
+	return (valueSize(k, m, 2) + 1) << k
+}
+
+// allocOutput allocates the backing words of a transform output: from the
+// bump allocator when it is mapped from a file, from the pool otherwise.
+// The words are not cleared.
+func allocOutput(alloc TempAllocator, words int) []big.Word {
+	if a, ok := alloc.(*BumpAllocatorAdapter); ok && a.ba.spilled {
+		return a.ba.AllocUnsafe(words)
+	}
+	return acquireWordSliceUnsafe(words)
+}
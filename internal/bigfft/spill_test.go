@@ -0,0 +1,92 @@
+package bigfft
+
+import (
+	"math/big"
+	"os"
+	"testing"
+)
+
+func TestSpillMulSqr(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	spill := NewSpill(1, dir)
+	x := new(big.Int).SetBits(randomNat(t, 5000))
+	y := new(big.Int).SetBits(randomNat(t, 4000))
+
+	got, err := MulToWithSpill(new(big.Int), x, y, nil, spill)
+	if err != nil {
+		t.Fatalf("MulToWithSpill: %v", err)
+	}
+	if got.Cmp(new(big.Int).Mul(x, y)) != 0 {
+		t.Error("spilled product differs from math/big")
+	}
+	got, err = SqrToWithSpill(new(big.Int), x, nil, spill)
+	if err != nil {
+		t.Fatalf("SqrToWithSpill: %v", err)
+	}
+	if got.Cmp(new(big.Int).Mul(x, x)) != 0 {
+		t.Error("spilled square differs from math/big")
+	}
+
+	stats := spill.Stats()
+	if stats.Buffers != 2 || stats.Bytes <= 0 {
+		t.Errorf("Stats() = %+v, want 2 buffers with bytes", stats)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("%d spill files left in %s", len(entries), dir)
+	}
+}
+
+func TestSpillWorkspace(t *testing.T) {
+	t.Parallel()
+	x := new(big.Int).SetBits(randomNat(t, 3000))
+	want := new(big.Int).Mul(x, x)
+	k, m := GetFFTParams(2*len(x.Bits()) + 2)
+	n := ValueSize(k, m, 2)
+	words := (n + 1) << k
+
+	tests := []struct {
+		name      string
+		threshold int64
+		spilled   bool
+	}{
+		{"disabled", 0, false},
+		{"below threshold", 1 << 40, false},
+		{"above threshold", 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			spill := NewSpill(tt.threshold, t.TempDir())
+			ws, release, err := spill.Workspace(11*(n+1), 3*words)
+			if err != nil {
+				t.Fatalf("Workspace: %v", err)
+			}
+			if (ws != nil) != tt.spilled {
+				t.Fatalf("Workspace() mapped = %v, want %v", ws != nil, tt.spilled)
+			}
+			p := PolyFromInt(x, k, m)
+			v, err := p.TransformWithBump(n, ws)
+			if err != nil {
+				t.Fatalf("TransformWithBump: %v", err)
+			}
+			sq, err := v.SqrWithBump(ws)
+			if err != nil {
+				t.Fatalf("SqrWithBump: %v", err)
+			}
+			r, err := sq.InvTransformWithBump(ws)
+			if err != nil {
+				t.Fatalf("InvTransformWithBump: %v", err)
+			}
+			r.M = m
+			got := r.IntToBigInt(new(big.Int))
+			release()
+			if got.Cmp(want) != 0 {
+				t.Error("square differs from math/big")
+			}
+			if got := spill.Stats().Buffers > 0; got != tt.spilled {
+				t.Errorf("Stats().Buffers > 0 = %v, want %v", got, tt.spilled)
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+// Package bugreport builds ready-to-file reports for result mismatches.
+// When the algorithms of a comparison disagree, or a result differs from a
+// reference value (the OEIS b-file, an external program), a Report gathers
+// what a maintainer needs to reproduce the problem — build and Go versions,
+// the command line, the resolved configuration, the calibration profile, n
+// and a digest of every result — together with a divergence analysis that
+// checks each distinct result against residues of F(n) computed
+// independently with modular arithmetic, to tell which algorithm is wrong.
+package bugreport
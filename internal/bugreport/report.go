@@ -0,0 +1,389 @@
+package bugreport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"math/bits"
+	"net/url"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/audit"
+	"github.com/agbru/fibcalc/internal/calibration"
+	"github.com/agbru/fibcalc/internal/config"
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/fsguard"
+	"github.com/agbru/fibcalc/internal/orchestration"
+)
+
+// IssueURL is the page that opens a new issue in the project's tracker.
+const IssueURL = "https://github.com/agbru/fibcalc/issues/new"
+
+// Build identifies the binary that produced a report.
+type Build struct {
+	Version   string
+	Commit    string
+	BuildDate string
+}
+
+// Result is the outcome of one algorithm, reduced to what a report needs.
+type Result struct {
+	Algorithm string
+	Duration  time.Duration
+	// SHA256 is the digest of the value (see audit.HashResult), "" if the
+	// algorithm failed.
+	SHA256 string
+	Bits   int
+	// Err is the error message of a failed algorithm.
+	Err string
+	// Kind categorizes the failure, if any, including a mismatching value.
+	Kind apperrors.ErrorKind
+}
+
+// Check compares a residue of a result with the same residue of F(n)
+// computed independently by fibonacci.FastDoublingMod.
+type Check struct {
+	// Modulus names the modulus (e.g. "10^20").
+	Modulus  string
+	Expected string
+	Actual   string
+}
+
+// OK reports whether the residue of the result is the expected one.
+func (c Check) OK() bool {
+	return c.Expected == c.Actual
+}
+
+// Group is a distinct value returned by one or more algorithms.
+type Group struct {
+	SHA256     string
+	Bits       int
+	Algorithms []string
+	// Checks holds the residue checks of the value, one per reference
+	// modulus.
+	Checks []Check
+	// LowestDiffBit and HighestDiffBit are the positions of the lowest and
+	// highest bits in which the value differs from the first group's, and
+	// DiffBits the number of differing bits. They are -1, -1 and 0 for the
+	// first group.
+	LowestDiffBit  int
+	HighestDiffBit int
+	DiffBits       int
+}
+
+// Consistent reports whether the value passes every residue check, which
+// makes it very likely to be F(n).
+func (g Group) Consistent() bool {
+	for _, c := range g.Checks {
+		if !c.OK() {
+			return false
+		}
+	}
+	return true
+}
+
+// Report describes a result mismatch for filing in the issue tracker.
+type Report struct {
+	Created   time.Time
+	Build     Build
+	GoVersion string
+	Platform  string
+	NumCPU    int
+	// Args are the command-line arguments, without the program name.
+	Args   []string
+	Config config.AppConfig
+	// ProfilePath is the calibration profile consulted, and Profile its
+	// content, nil if there is none valid for this machine.
+	ProfilePath string
+	Profile     *calibration.CalibrationProfile
+	N           uint64
+	// ExpectedBits is the bit length F(n) should have (see expectedBits).
+	ExpectedBits int
+	Results      []Result
+	// Groups holds the distinct values, the one returned by the most
+	// algorithms first.
+	Groups []Group
+}
+
+// referenceModuli are the moduli of the residue checks: the last decimal
+// digits, and two primes that catch errors anywhere in the value.
+var referenceModuli = []struct {
+	name string
+	m    *big.Int
+}{
+	{"10^20", new(big.Int).Exp(big.NewInt(10), big.NewInt(20), nil)},
+	{"2^61-1", new(big.Int).SetUint64(1<<61 - 1)},
+	{"2^64-59", new(big.Int).SetUint64(math.MaxUint64 - 58)},
+}
+
+// New builds the report of a comparison whose results disagree, and
+// analyzes how they differ. A reference value a result failed to match,
+// such as an OEIS term, is passed as one more result.
+//
+// Parameters:
+//   - cfg: The configuration of the run.
+//   - args: The command-line arguments, without the program name.
+//   - build: The version of the binary.
+//   - results: The results of the comparison.
+//
+// Returns:
+//   - *Report: The report.
+func New(cfg config.AppConfig, args []string, build Build, results []orchestration.CalculationResult) *Report {
+	r := &Report{
+		Created:      time.Now(),
+		Build:        build,
+		GoVersion:    runtime.Version(),
+		Platform:     runtime.GOOS + "/" + runtime.GOARCH,
+		NumCPU:       runtime.NumCPU(),
+		Args:         args,
+		Config:       cfg,
+		ProfilePath:  cfg.CalibrationProfile,
+		N:            cfg.N,
+		ExpectedBits: expectedBits(cfg.N),
+	}
+	if r.ProfilePath == "" {
+		r.ProfilePath = calibration.GetDefaultProfilePath()
+	}
+	if profile, ok := calibration.LoadOrCreateProfile(r.ProfilePath); ok {
+		r.Profile = profile
+	}
+
+	var values []*big.Int
+	for _, res := range results {
+		entry := Result{Algorithm: res.Name, Duration: res.Duration, Kind: res.ErrorKind()}
+		if res.Err != nil {
+			entry.Err = res.Err.Error()
+			r.Results = append(r.Results, entry)
+			continue
+		}
+		entry.SHA256 = audit.HashResult(res.Result)
+		entry.Bits = res.Result.BitLen()
+		r.Results = append(r.Results, entry)
+
+		i := slices.IndexFunc(r.Groups, func(g Group) bool { return g.SHA256 == entry.SHA256 })
+		if i < 0 {
+			r.Groups = append(r.Groups, Group{SHA256: entry.SHA256, Bits: entry.Bits})
+			values = append(values, res.Result)
+			i = len(r.Groups) - 1
+		}
+		r.Groups[i].Algorithms = append(r.Groups[i].Algorithms, res.Name)
+	}
+
+	// Most shared value first, keeping the order of the results otherwise
+	order := make([]int, len(r.Groups))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return len(r.Groups[b].Algorithms) - len(r.Groups[a].Algorithms)
+	})
+	groups := make([]Group, len(order))
+	sorted := make([]*big.Int, len(order))
+	for i, j := range order {
+		groups[i], sorted[i] = r.Groups[j], values[j]
+	}
+	r.Groups = groups
+	analyze(r.Groups, sorted, r.N)
+	return r
+}
+
+// analyze fills the residue checks of each group and compares its value
+// with the first group's.
+func analyze(groups []Group, values []*big.Int, n uint64) {
+	expected := make([]*big.Int, len(referenceModuli))
+	for i, ref := range referenceModuli {
+		expected[i], _ = fibonacci.FastDoublingMod(n, ref.m)
+	}
+	diff := new(big.Int)
+	residue := new(big.Int)
+	for i := range groups {
+		g := &groups[i]
+		for j, ref := range referenceModuli {
+			residue.Mod(values[i], ref.m)
+			g.Checks = append(g.Checks, Check{Modulus: ref.name, Expected: expected[j].String(), Actual: residue.String()})
+		}
+		g.LowestDiffBit, g.HighestDiffBit = -1, -1
+		if i == 0 {
+			continue
+		}
+		diff.Xor(values[i], values[0])
+		if diff.Sign() != 0 {
+			g.LowestDiffBit = int(diff.TrailingZeroBits())
+			g.HighestDiffBit = diff.BitLen() - 1
+			for _, w := range diff.Bits() {
+				g.DiffBits += bits.OnesCount(uint(w))
+			}
+		}
+	}
+}
+
+// expectedBits returns the bit length of F(n): exactly for n ≤ 93, where
+// F(n) fits in a uint64, and otherwise estimated as
+// ⌊n·log2(φ) − log2(√5)⌋ + 1, which is exact except when the logarithm is
+// within floating-point error of an integer.
+func expectedBits(n uint64) int {
+	if n <= 93 {
+		a, b := uint64(0), uint64(1)
+		for range n {
+			a, b = b, a+b
+		}
+		return bits.Len64(a)
+	}
+	phi := (1 + math.Sqrt(5)) / 2
+	return int(math.Floor(float64(n)*math.Log2(phi)-math.Log2(math.Sqrt(5)))) + 1
+}
+
+// Title returns the suggested title of the issue.
+//
+// Returns:
+//   - string: E.g. "Result mismatch for F(1000000) (fast, matrix, fft)".
+func (r *Report) Title() string {
+	names := make([]string, 0, len(r.Results))
+	for _, res := range r.Results {
+		names = append(names, res.Algorithm)
+	}
+	return fmt.Sprintf("Result mismatch for F(%d) (%s)", r.N, strings.Join(names, ", "))
+}
+
+// IssueLink returns the URL of a new issue prefilled with the report's
+// title. The report itself is too long for a URL and must be attached or
+// pasted.
+//
+// Returns:
+//   - string: The URL.
+func (r *Report) IssueLink() string {
+	q := url.Values{"title": {r.Title()}, "labels": {"bug"}}
+	return IssueURL + "?" + q.Encode()
+}
+
+// DefaultFileName returns the file name to write a report created at t to.
+//
+// Parameters:
+//   - t: The creation time of the report.
+//
+// Returns:
+//   - string: E.g. "fibcalc-bugreport-20250101-120000.md".
+func DefaultFileName(t time.Time) string {
+	return "fibcalc-bugreport-" + t.Format("20060102-150405") + ".md"
+}
+
+// WriteMarkdown writes the report as Markdown, ready to paste into an
+// issue.
+//
+// Parameters:
+//   - w: The writer to write the report to.
+//
+// Returns:
+//   - error: An error if writing fails.
+func (r *Report) WriteMarkdown(w io.Writer) error {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# %s\n\n", r.Title())
+	fmt.Fprintf(&b, "Different values were obtained for F(%d). ", r.N)
+	fmt.Fprintf(&b, "This report was generated by fibcalc on %s.\n\n", r.Created.UTC().Format(time.RFC3339))
+
+	fmt.Fprintf(&b, "## Environment\n\n")
+	fmt.Fprintf(&b, "| | |\n|---|---|\n")
+	fmt.Fprintf(&b, "| Version | %s |\n", r.Build.Version)
+	fmt.Fprintf(&b, "| Commit | %s |\n", r.Build.Commit)
+	fmt.Fprintf(&b, "| Built | %s |\n", r.Build.BuildDate)
+	fmt.Fprintf(&b, "| Go | %s |\n", r.GoVersion)
+	fmt.Fprintf(&b, "| Platform | %s |\n", r.Platform)
+	fmt.Fprintf(&b, "| CPUs | %d |\n\n", r.NumCPU)
+
+	fmt.Fprintf(&b, "## Command line\n\n```\nfibcalc %s\n```\n\n", strings.Join(r.Args, " "))
+
+	fmt.Fprintf(&b, "## Configuration\n\n")
+	writeJSON(&b, r.Config)
+
+	fmt.Fprintf(&b, "## Calibration profile\n\n")
+	if r.Profile != nil {
+		fmt.Fprintf(&b, "From `%s`:\n\n", r.ProfilePath)
+		writeJSON(&b, r.Profile)
+	} else {
+		fmt.Fprintf(&b, "None valid for this machine at `%s`.\n\n", r.ProfilePath)
+	}
+
+	fmt.Fprintf(&b, "## Results\n\n")
+	fmt.Fprintf(&b, "| Algorithm | Duration | Bits | SHA-256 |\n|---|---|---|---|\n")
+	for _, res := range r.Results {
+		if res.Err != "" {
+			label := "error"
+			if res.Kind != apperrors.ErrorKindInternal {
+				label = res.Kind.String()
+			}
+			fmt.Fprintf(&b, "| %s | %s | - | %s: %s |\n", res.Algorithm, res.Duration, label, res.Err)
+			continue
+		}
+		fmt.Fprintf(&b, "| %s | %s | %d | `%s` |\n", res.Algorithm, res.Duration, res.Bits, res.SHA256)
+	}
+
+	fmt.Fprintf(&b, "\n## Divergence analysis\n\n")
+	fmt.Fprintf(&b, "F(%d) should have about %d bits. Each distinct value is checked against F(%d) modulo %s, computed independently with modular fast doubling.\n\n",
+		r.N, r.ExpectedBits, r.N, moduliNames())
+	for i, g := range r.Groups {
+		verdict := "passes every check, likely correct"
+		if !g.Consistent() {
+			verdict = "fails a check, incorrect"
+		}
+		fmt.Fprintf(&b, "### Value %d: %s\n\n", i+1, strings.Join(g.Algorithms, ", "))
+		fmt.Fprintf(&b, "- SHA-256 `%s`, %d bits: %s.\n", g.SHA256, g.Bits, verdict)
+		for _, c := range g.Checks {
+			mark := "ok"
+			if !c.OK() {
+				mark = "expected " + c.Expected
+			}
+			fmt.Fprintf(&b, "- mod %s = %s (%s)\n", c.Modulus, c.Actual, mark)
+		}
+		if i > 0 && g.DiffBits > 0 {
+			fmt.Fprintf(&b, "- Differs from value 1 in %d bit(s), from bit %d to bit %d.\n", g.DiffBits, g.LowestDiffBit, g.HighestDiffBit)
+		}
+		fmt.Fprintln(&b)
+	}
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// WriteFile writes the report as Markdown to path.
+//
+// Parameters:
+//   - path: The file to create or overwrite.
+//
+// Returns:
+//   - error: An error if the file cannot be written.
+func (r *Report) WriteFile(path string) error {
+	var b bytes.Buffer
+	if err := r.WriteMarkdown(&b); err != nil {
+		return err
+	}
+	if err := fsguard.WriteFile(path, b.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write bug report %q: %w", path, err)
+	}
+	return nil
+}
+
+// writeJSON writes v as an indented JSON code block.
+func writeJSON(b *bytes.Buffer, v any) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		fmt.Fprintf(b, "(cannot encode: %v)\n\n", err)
+		return
+	}
+	fmt.Fprintf(b, "```json\n%s\n```\n\n", data)
+}
+
+// moduliNames lists the reference moduli for the report.
+func moduliNames() string {
+	names := make([]string, len(referenceModuli))
+	for i, ref := range referenceModuli {
+		names[i] = ref.name
+	}
+	return strings.Join(names, ", ")
+}
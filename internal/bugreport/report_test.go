@@ -0,0 +1,122 @@
+package bugreport
+
+import (
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/audit"
+	"github.com/agbru/fibcalc/internal/config"
+	"github.com/agbru/fibcalc/internal/orchestration"
+)
+
+// fib returns F(n) computed iteratively.
+func fib(n uint64) *big.Int {
+	a, b := big.NewInt(0), big.NewInt(1)
+	for range n {
+		a.Add(a, b)
+		a, b = b, a
+	}
+	return a
+}
+
+func mismatchReport(t *testing.T) (*Report, *big.Int, *big.Int) {
+	t.Helper()
+	const n = 1000
+	good := fib(n)
+	bad := new(big.Int).Xor(good, new(big.Int).Lsh(big.NewInt(5), 100)) // bits 100 and 102
+	cfg := config.AppConfig{N: n, Algo: "all", CalibrationProfile: filepath.Join(t.TempDir(), "none.json")}
+	results := []orchestration.CalculationResult{
+		{Name: "fast", Result: good, Duration: time.Millisecond},
+		{Name: "fft", Result: bad, Duration: 2 * time.Millisecond},
+		{Name: "matrix", Result: good, Duration: 3 * time.Millisecond},
+		{Name: "lowmem", Err: errors.New("out of disk")},
+	}
+	return New(cfg, []string{"-n", "1000"}, Build{Version: "v1.2.3", Commit: "abc123"}, results), good, bad
+}
+
+func TestNewGroupsAndAnalysis(t *testing.T) {
+	t.Parallel()
+	r, good, bad := mismatchReport(t)
+
+	if len(r.Results) != 4 || r.Results[3].Err != "out of disk" || r.Results[0].SHA256 != audit.HashResult(good) {
+		t.Fatalf("unexpected results: %+v", r.Results)
+	}
+	if len(r.Groups) != 2 {
+		t.Fatalf("expected 2 groups, got %d", len(r.Groups))
+	}
+	first, second := r.Groups[0], r.Groups[1]
+	if strings.Join(first.Algorithms, ",") != "fast,matrix" || first.SHA256 != audit.HashResult(good) {
+		t.Errorf("first group = %+v, want the value shared by fast and matrix", first)
+	}
+	if !first.Consistent() {
+		t.Errorf("the correct value should pass the checks: %+v", first.Checks)
+	}
+	if strings.Join(second.Algorithms, ",") != "fft" || second.SHA256 != audit.HashResult(bad) {
+		t.Errorf("second group = %+v, want fft's value", second)
+	}
+	if second.Consistent() {
+		t.Errorf("the wrong value should fail a check: %+v", second.Checks)
+	}
+	if second.LowestDiffBit != 100 || second.HighestDiffBit != 102 || second.DiffBits != 2 {
+		t.Errorf("diff = bits %d..%d (%d), want 100..102 (2)", second.LowestDiffBit, second.HighestDiffBit, second.DiffBits)
+	}
+	if r.ExpectedBits != good.BitLen() {
+		t.Errorf("ExpectedBits = %d, want %d", r.ExpectedBits, good.BitLen())
+	}
+	if r.Profile != nil {
+		t.Errorf("expected no calibration profile, got %v", r.Profile)
+	}
+}
+
+func TestExpectedBits(t *testing.T) {
+	t.Parallel()
+	for _, n := range []uint64{0, 1, 2, 3, 10, 93, 94, 1000, 12345} {
+		if got, want := expectedBits(n), fib(n).BitLen(); got != want {
+			t.Errorf("expectedBits(%d) = %d, want %d", n, got, want)
+		}
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	t.Parallel()
+	r, _, _ := mismatchReport(t)
+	path := filepath.Join(t.TempDir(), DefaultFileName(r.Created))
+	if err := r.WriteFile(path); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := string(data)
+	for _, want := range []string{
+		"# Result mismatch for F(1000) (fast, fft, matrix, lowmem)",
+		"| Version | v1.2.3 |",
+		"fibcalc -n 1000",
+		`"N": 1000`,
+		"None valid for this machine",
+		"error: out of disk",
+		"### Value 1: fast, matrix",
+		"likely correct",
+		"### Value 2: fft",
+		"from bit 100 to bit 102",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("report does not contain %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestIssueLink(t *testing.T) {
+	t.Parallel()
+	r, _, _ := mismatchReport(t)
+	link := r.IssueLink()
+	if !strings.HasPrefix(link, IssueURL+"?") || !strings.Contains(link, "title=Result+mismatch+for+F%281000%29") {
+		t.Errorf("unexpected issue link %q", link)
+	}
+}
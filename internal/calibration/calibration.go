@@ -6,12 +6,14 @@ import (
 	"fmt"
 	"io"
 	"runtime"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/agbru/fibcalc/internal/config"
 	apperrors "github.com/agbru/fibcalc/internal/errors"
 	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/format"
 	"github.com/agbru/fibcalc/internal/progress"
 	"github.com/agbru/fibcalc/internal/ui"
 )
@@ -25,10 +27,14 @@ type CalibrationOptions struct {
 	// ProfilePath is the path to save/load the calibration profile.
 	// If empty, uses the default path.
 	ProfilePath string
-	// SaveProfile indicates whether to save the calibration results.
+	// SaveProfile indicates whether to save the calibration results. It
+	// is false with --profile-readonly.
 	SaveProfile bool
 	// LoadProfile indicates whether to try loading an existing profile.
 	LoadProfile bool
+	// Seed determines the order in which thresholds are tested. It is
+	// printed and saved in the profile so the run can be reproduced.
+	Seed int64
 }
 
 // calibrationResult holds the result of a single threshold test.
@@ -70,7 +76,7 @@ func RunCalibrationWithOptions(ctx context.Context, out io.Writer, calculatorReg
 		profile, loaded := LoadOrCreateProfile(opts.ProfilePath)
 		if loaded && profile.IsValid() {
 			fmt.Fprintf(out, "%sLoaded existing calibration profile from %s%s\n",
-				ui.ColorGreen(), GetDefaultProfilePath(), ui.ColorReset())
+				ui.ColorGreen(), profileDisplayPath(opts.ProfilePath), ui.ColorReset())
 			fmt.Fprintf(out, "Profile: %s\n", profile.String())
 			fmt.Fprintf(out, "\n%s✅ Using cached calibration: %s--threshold %d%s\n",
 				ui.ColorGreen(), ui.ColorYellow(), profile.OptimalParallelThreshold, ui.ColorReset())
@@ -84,10 +90,11 @@ func RunCalibrationWithOptions(ctx context.Context, out io.Writer, calculatorReg
 		return apperrors.ExitErrorGeneric
 	}
 
-	// Use adaptive thresholds based on CPU characteristics
-	thresholdsToTest := GenerateParallelThresholds()
-	fmt.Fprintf(out, "%sUsing adaptive thresholds for %d CPU cores%s\n",
-		ui.ColorCyan(), runtime.NumCPU(), ui.ColorReset())
+	// Use adaptive thresholds based on CPU characteristics, tested in
+	// seeded random order
+	thresholdsToTest := shuffled(GenerateParallelThresholds(), newRand(opts.Seed))
+	fmt.Fprintf(out, "%sUsing adaptive thresholds for %d CPU cores (seed %d)%s\n",
+		ui.ColorCyan(), runtime.NumCPU(), opts.Seed, ui.ColorReset())
 
 	results := make([]calibrationResult, 0, len(thresholdsToTest))
 	bestDuration := time.Duration(1<<63 - 1)
@@ -138,27 +145,37 @@ func RunCalibrationWithOptions(ctx context.Context, out io.Writer, calculatorReg
 
 	calibrationDuration := time.Since(calibrationStart)
 
-	// Print results table
+	// Print results table in threshold order, regardless of test order
+	sort.Slice(results, func(i, j int) bool { return results[i].Threshold < results[j].Threshold })
 	printCalibrationResults(out, results, bestThreshold)
 
 	fmt.Fprintf(out, "\n%s✅ Recommendation for this machine: %s--threshold %d%s\n",
 		ui.ColorGreen(), ui.ColorYellow(), bestThreshold, ui.ColorReset())
 
 	// Save profile if requested
-	if opts.SaveProfile {
+	if !opts.SaveProfile {
+		fmt.Fprintf(out, "Calibration profile not saved (--profile-readonly).\n")
+	} else {
 		profile := NewProfile()
+		profile.CalibratedAt = calibrationStart
+		profile.Confidence = 1
 		profile.OptimalParallelThreshold = bestThreshold
 		profile.OptimalFFTThreshold = config.EstimateOptimalFFTThreshold()
 		profile.OptimalStrassenThreshold = config.EstimateOptimalStrassenThreshold()
 		profile.CalibrationN = fibonacci.CalibrationN
 		profile.CalibrationTime = calibrationDuration.String()
+		profile.Seed = opts.Seed
 
-		if err := profile.SaveProfile(opts.ProfilePath); err != nil {
+		switch err := profile.SaveProfile(opts.ProfilePath); {
+		case errors.Is(err, ErrProfileSuperseded):
+			fmt.Fprintf(out, "Calibration profile not saved: %s holds a more recent calibration.\n",
+				profileDisplayPath(opts.ProfilePath))
+		case err != nil:
 			fmt.Fprintf(out, "%sWarning: failed to save profile: %v%s\n",
 				ui.ColorYellow(), err, ui.ColorReset())
-		} else {
+		default:
 			fmt.Fprintf(out, "%sCalibration profile saved to %s%s\n",
-				ui.ColorGreen(), GetDefaultProfilePath(), ui.ColorReset())
+				ui.ColorGreen(), profileDisplayPath(opts.ProfilePath), ui.ColorReset())
 		}
 	}
 
@@ -218,8 +235,10 @@ func AutoCalibrateWithProfile(parentCtx context.Context, cfg config.AppConfig, o
 		return updated, true
 	}
 
+	calibrationStart := time.Now()
+
 	// Try quick micro-benchmarks first (~100ms)
-	microResults, err := QuickCalibrate(parentCtx)
+	microResults, err := QuickCalibrateWithSeed(parentCtx, cfg.Seed)
 	if err == nil && microResults.Confidence >= 0.5 {
 		updated := cfg
 		updated.Threshold = microResults.ParallelThreshold
@@ -228,19 +247,19 @@ func AutoCalibrateWithProfile(parentCtx context.Context, cfg config.AppConfig, o
 
 		fmt.Fprintf(out, "%sQuick calibration%s (%v): parallelism=%s%d%s bits, FFT=%s%d%s bits (confidence: %.0f%%)\n",
 			ui.ColorGreen(), ui.ColorReset(),
-			microResults.Duration.Round(time.Millisecond),
+			format.FormatExecutionDuration(microResults.Duration),
 			ui.ColorYellow(), updated.Threshold, ui.ColorReset(),
 			ui.ColorYellow(), updated.FFTThreshold, ui.ColorReset(),
 			microResults.Confidence*100)
 
 		// Save profile for future use
-		saveCalibrationProfile(updated, profilePath, out)
+		saveCalibrationProfile(updated, profilePath, out, calibrationStart, microResults.Confidence)
 		return updated, true
 	}
 
 	// Fall back to full calibration if quick calibration failed or has low confidence
 
-	runner := newCalibrationRunner(parentCtx, cfg.Timeout)
+	runner := newCalibrationRunner(parentCtx, cfg.Timeout, cfg.Seed)
 
 	// Find optimal thresholds
 	bestPar, bestParDur := runner.findBestParallelThreshold(fastCalc, cfg.Threshold)
@@ -260,7 +279,7 @@ func AutoCalibrateWithProfile(parentCtx context.Context, cfg config.AppConfig, o
 	}
 
 	// Save profile and print output
-	saveCalibrationProfile(updated, profilePath, out)
+	saveCalibrationProfile(updated, profilePath, out, calibrationStart, 1)
 	printCalibrationOutput(updated, out)
 
 	return updated, true
@@ -274,12 +293,22 @@ func LoadCachedCalibration(cfg config.AppConfig, profilePath string) (updated co
 	if !loaded || !profile.IsValid() {
 		return cfg, false
 	}
+	return ApplyProfile(cfg, profile), true
+}
 
-	updated = cfg
-	updated.Threshold = profile.OptimalParallelThreshold
-	updated.FFTThreshold = profile.OptimalFFTThreshold
-	updated.StrassenThreshold = profile.OptimalStrassenThreshold
-	return updated, true
+// ApplyProfile returns cfg with the thresholds of a calibration profile.
+//
+// Parameters:
+//   - cfg: The configuration.
+//   - profile: A profile valid for this machine.
+//
+// Returns:
+//   - config.AppConfig: The configuration with the profile's thresholds.
+func ApplyProfile(cfg config.AppConfig, profile *CalibrationProfile) config.AppConfig {
+	cfg.Threshold = profile.OptimalParallelThreshold
+	cfg.FFTThreshold = profile.OptimalFFTThreshold
+	cfg.StrassenThreshold = profile.OptimalStrassenThreshold
+	return cfg
 }
 
 // applyCalibrationResults updates the configuration with the calibration results.
@@ -315,21 +344,66 @@ func applyCalibrationResults(cfg config.AppConfig, bestPar int, bestParDur time.
 	return updated, true
 }
 
-// saveCalibrationProfile saves the calibration results to a profile.
+// saveCalibrationProfile saves the calibration results to a profile, unless
+// cfg.ProfileReadOnly is set. A profile kept because another process saved
+// a more recent calibration meanwhile is not reported.
 //
 // Parameters:
 //   - cfg: The updated configuration with calibration results.
 //   - profilePath: The path to save the profile.
 //   - out: The writer for warning messages.
-func saveCalibrationProfile(cfg config.AppConfig, profilePath string, out io.Writer) {
+//   - start: When the calibration started.
+//   - confidence: The confidence in the results, from 0 to 1.
+func saveCalibrationProfile(cfg config.AppConfig, profilePath string, out io.Writer, start time.Time, confidence float64) {
+	if cfg.ProfileReadOnly {
+		return
+	}
 	profile := NewProfile()
 	profile.OptimalParallelThreshold = cfg.Threshold
 	profile.OptimalFFTThreshold = cfg.FFTThreshold
 	profile.OptimalStrassenThreshold = cfg.StrassenThreshold
 	profile.CalibrationN = fibonacci.CalibrationN
+	profile.CalibrationTime = time.Since(start).String()
+	profile.CalibratedAt = start
+	profile.Confidence = confidence
+	profile.Seed = cfg.Seed
 
-	if err := profile.SaveProfile(profilePath); err != nil {
+	if err := profile.SaveProfile(profilePath); err != nil && !errors.Is(err, ErrProfileSuperseded) {
 		fmt.Fprintf(out, "%sWarning: could not save calibration profile: %v%s\n",
 			ui.ColorYellow(), err, ui.ColorReset())
 	}
 }
+
+// FFTCrossover returns the operand size (in bits) above which FFT
+// multiplication beats math/big on this machine, for use by the
+// algorithm cost model. The calibration profile is preferred; without a
+// valid profile the hardware-based estimate is used.
+//
+// Parameters:
+//   - profilePath: The path to the calibration profile.
+//
+// Returns:
+//   - int: The crossover size in bits.
+//   - string: Where the value came from, for display.
+func FFTCrossover(profilePath string) (bits int, source string) {
+	profile, loaded := LoadOrCreateProfile(profilePath)
+	if !loaded {
+		profile = nil
+	}
+	return ProfileFFTCrossover(profile)
+}
+
+// ProfileFFTCrossover is FFTCrossover for a profile already loaded.
+//
+// Parameters:
+//   - profile: The calibration profile, nil if there is none.
+//
+// Returns:
+//   - int: The crossover size in bits.
+//   - string: Where the value came from, for display.
+func ProfileFFTCrossover(profile *CalibrationProfile) (bits int, source string) {
+	if profile.IsValid() && profile.OptimalFFTThreshold > 0 {
+		return profile.OptimalFFTThreshold, "calibration profile"
+	}
+	return EstimateOptimalFFTThreshold(), "hardware estimate"
+}
@@ -15,6 +15,7 @@ import (
 	apperrors "github.com/agbru/fibcalc/internal/errors"
 	"github.com/agbru/fibcalc/internal/fibonacci"
 	"github.com/agbru/fibcalc/internal/progress"
+	"github.com/rs/zerolog"
 )
 
 // noopProgressDisplay drains the progress channel without output (for tests).
@@ -343,7 +344,7 @@ func TestAutoCalibrateWithProfile(t *testing.T) {
 func TestCalibrationRunner(t *testing.T) {
 	t.Parallel()
 	ctx := context.Background()
-	runner := newCalibrationRunner(ctx, 1*time.Second)
+	runner := newCalibrationRunner(ctx, 1*time.Second, 0)
 	calc := &MockCalculator{name: "fast"}
 
 	// Test findBestParallelThreshold
@@ -374,6 +375,19 @@ func TestCalibrationRunner(t *testing.T) {
 	}
 }
 
+func TestCalibrationRunnerLogger(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	ctx := zerolog.New(&buf).WithContext(context.Background())
+	runner := newCalibrationRunner(ctx, 1*time.Second, 0)
+	if _, err := runner.runTrial(&MockCalculator{name: "fast"}, fibonacci.Options{ParallelThreshold: 4096}); err != nil {
+		t.Fatalf("runTrial() error = %v", err)
+	}
+	if out := buf.String(); !strings.Contains(out, `"message":"calibration trial"`) || !strings.Contains(out, `"calculator":"fast"`) {
+		t.Errorf("the context logger did not receive the trial:\n%s", out)
+	}
+}
+
 func TestApplyCalibrationResults(t *testing.T) {
 	t.Parallel()
 	cfg := config.AppConfig{}
@@ -0,0 +1,72 @@
+// This file implements the advisory lock that serializes the writes of
+// concurrent fibcalc processes to a calibration profile.
+
+package calibration
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/fsguard"
+)
+
+const (
+	// profileLockTimeout bounds the wait for the lock of a profile held by
+	// another fibcalc process.
+	profileLockTimeout = 10 * time.Second
+
+	// profileLockRetryInterval is the delay between two attempts to take
+	// a busy lock.
+	profileLockRetryInterval = 50 * time.Millisecond
+)
+
+// ErrProfileLocked is returned when the lock of a calibration profile
+// could not be taken before the timeout, another process holding it.
+var ErrProfileLocked = errors.New("calibration profile is locked by another process")
+
+// errLockBusy is returned by tryLockFile when another process holds the
+// lock.
+var errLockBusy = errors.New("lock busy")
+
+// lockProfile takes an exclusive advisory lock on the calibration profile
+// at path, through the companion file path+".lock", retrying while another
+// process holds it. The lock file is left in place, since removing it
+// would let a third process lock a new file while the second still holds
+// the old one. On platforms without file locking, the lock is a no-op.
+//
+// Parameters:
+//   - path: The path of the profile.
+//   - timeout: How long to wait for a busy lock.
+//
+// Returns:
+//   - func(): Releases the lock.
+//   - error: ErrProfileLocked if the lock stayed busy, or an error if the
+//     lock file could not be opened.
+func lockProfile(path string, timeout time.Duration) (unlock func(), err error) {
+	f, err := fsguard.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open profile lock: %w", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		err = tryLockFile(f)
+		if err == nil {
+			return func() {
+				_ = unlockFile(f)
+				_ = f.Close()
+			}, nil
+		}
+		if !errors.Is(err, errLockBusy) {
+			_ = f.Close()
+			return nil, fmt.Errorf("failed to lock profile: %w", err)
+		}
+		if time.Now().After(deadline) {
+			_ = f.Close()
+			return nil, ErrProfileLocked
+		}
+		time.Sleep(profileLockRetryInterval)
+	}
+}
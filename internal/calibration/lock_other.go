@@ -0,0 +1,16 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd || windows)
+
+package calibration
+
+import "os"
+
+// tryLockFile does nothing: file locking is not available on this
+// platform, so concurrent writes of the profile are not serialized.
+func tryLockFile(*os.File) error {
+	return nil
+}
+
+// unlockFile does nothing.
+func unlockFile(*os.File) error {
+	return nil
+}
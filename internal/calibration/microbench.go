@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/agbru/fibcalc/internal/bigfft"
+	"github.com/rs/zerolog"
 )
 
 // ─────────────────────────────────────────────────────────────────────────────
@@ -48,6 +49,8 @@ type MicroBenchmark struct {
 	Iterations int
 	// Timeout is the maximum duration for the entire benchmark
 	Timeout time.Duration
+	// Seed determines the order in which test configurations are scheduled
+	Seed int64
 }
 
 // ThresholdResults contains the estimated optimal thresholds from micro-benchmarks.
@@ -132,6 +135,10 @@ func (mb *MicroBenchmark) runParallelTests(ctx context.Context) []testResult {
 		)
 	}
 
+	// Schedule in seeded random order so that no configuration is
+	// systematically measured first, on a cold machine
+	configs = shuffled(configs, newRand(mb.Seed))
+
 	// Limit concurrency to avoid overwhelming the system
 	semaphore := make(chan struct{}, runtime.NumCPU())
 
@@ -148,6 +155,13 @@ func (mb *MicroBenchmark) runParallelTests(ctx context.Context) []testResult {
 			}
 
 			dur, err := mb.runSingleTest(ctx, c.wordSize, c.useFFT, c.parallel)
+			zerolog.Ctx(ctx).Debug().
+				Int("words", c.wordSize).
+				Bool("fft", c.useFFT).
+				Bool("parallel", c.parallel).
+				Dur("duration", dur).
+				Err(err).
+				Msg("multiplication benchmark")
 
 			mu.Lock()
 			results = append(results, testResult{
@@ -359,7 +373,22 @@ func (mb *MicroBenchmark) findParallelCrossover(bySize map[int][]testResult) int
 //   - ThresholdResults: The estimated optimal thresholds
 //   - error: An error if calibration failed
 func QuickCalibrate(ctx context.Context) (ThresholdResults, error) {
+	return QuickCalibrateWithSeed(ctx, 0)
+}
+
+// QuickCalibrateWithSeed is like QuickCalibrate but schedules the
+// micro-benchmarks in an order derived from seed, for reproducible runs.
+//
+// Parameters:
+//   - ctx: The context for cancellation
+//   - seed: The seed for the scheduling order
+//
+// Returns:
+//   - ThresholdResults: The estimated optimal thresholds
+//   - error: An error if calibration failed
+func QuickCalibrateWithSeed(ctx context.Context, seed int64) (ThresholdResults, error) {
 	mb := NewMicroBenchmark()
+	mb.Seed = seed
 	return mb.RunQuick(ctx)
 }
 
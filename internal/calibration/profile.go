@@ -4,11 +4,14 @@ package calibration
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
 	"time"
+
+	"github.com/agbru/fibcalc/internal/fsguard"
 )
 
 // CalibrationProfile stores the results of a calibration run.
@@ -32,6 +35,13 @@ type CalibrationProfile struct {
 	CalibratedAt    time.Time `json:"calibrated_at"`
 	CalibrationN    uint64    `json:"calibration_n"`
 	CalibrationTime string    `json:"calibration_time"`
+	// Seed is the seed that ordered the calibration trials; rerunning
+	// with --seed set to this value reproduces the same trial order.
+	Seed int64 `json:"seed"`
+	// Confidence is the confidence in the thresholds, from 0 to 1: 1 for
+	// a full calibration, the confidence of the micro-benchmarks for a
+	// quick one, and 0 for profiles written before it was recorded.
+	Confidence float64 `json:"confidence"`
 
 	// Version for forward compatibility
 	ProfileVersion int `json:"profile_version"`
@@ -56,6 +66,15 @@ func GetDefaultProfilePath() string {
 	return filepath.Join(home, DefaultProfileFileName)
 }
 
+// profileDisplayPath returns the path of the profile to print: path, or the
+// default path if it is empty.
+func profileDisplayPath(path string) string {
+	if path == "" {
+		return GetDefaultProfilePath()
+	}
+	return path
+}
+
 // NewProfile creates a new CalibrationProfile with current hardware info.
 func NewProfile() *CalibrationProfile {
 	return &CalibrationProfile{
@@ -79,6 +98,12 @@ func getCPUModel() string {
 	return fmt.Sprintf("%s-%d-cores", runtime.GOARCH, runtime.NumCPU())
 }
 
+// ErrProfileSuperseded is returned by SaveProfile when the profile on disk
+// was calibrated later than the one being saved, and with at least the same
+// confidence, typically by another fibcalc process running concurrently.
+// The profile on disk is kept.
+var ErrProfileSuperseded = errors.New("calibration profile superseded by a more recent one")
+
 // loadProfile loads a calibration profile from the specified path.
 // Returns nil and an error if the file doesn't exist or can't be parsed.
 func loadProfile(path string) (*CalibrationProfile, error) {
@@ -102,6 +127,21 @@ func loadProfile(path string) (*CalibrationProfile, error) {
 
 // SaveProfile saves the calibration profile to the specified path.
 // If path is empty, uses the default profile path.
+//
+// Concurrent fibcalc processes are serialized by an advisory lock on the
+// profile (see lockProfile). Under the lock, the profile on disk is read
+// back and kept if it is valid for this machine and supersedes p (see
+// supersedes), so that the most recent of the most confident calibrations
+// wins, whatever the order in which the processes finish. The file is
+// replaced atomically, so readers never see a partial profile.
+//
+// Parameters:
+//   - path: The path of the profile, or "" for the default one.
+//
+// Returns:
+//   - error: ErrProfileSuperseded if the profile on disk was kept,
+//     ErrProfileLocked if another process held the lock too long, or an
+//     error if the profile could not be written.
 func (p *CalibrationProfile) SaveProfile(path string) error {
 	if path == "" {
 		path = GetDefaultProfilePath()
@@ -111,24 +151,61 @@ func (p *CalibrationProfile) SaveProfile(path string) error {
 	// Ensure directory exists with restrictive permissions
 	dir := filepath.Dir(path)
 	if dir != "" && dir != "." {
-		if err := os.MkdirAll(dir, 0750); err != nil {
+		if err := fsguard.MkdirAll(dir, 0750); err != nil {
 			return fmt.Errorf("failed to create directory: %w", err)
 		}
 	}
 
+	unlock, err := lockProfile(path, profileLockTimeout)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if current, err := loadProfile(path); err == nil && current.IsValid() && current.supersedes(p) {
+		return ErrProfileSuperseded
+	}
+
 	data, err := json.MarshalIndent(p, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal profile: %w", err)
 	}
 
-	// Note: os.WriteFile creates file with specified permissions (0600)
-	if err := os.WriteFile(path, data, 0600); err != nil {
+	if err := writeFileAtomic(path, data); err != nil {
 		return fmt.Errorf("failed to write profile: %w", err)
 	}
 
 	return nil
 }
 
+// supersedes reports whether p should be kept over other: p is more
+// confident, or as confident and calibrated later.
+func (p *CalibrationProfile) supersedes(other *CalibrationProfile) bool {
+	if p.Confidence != other.Confidence {
+		return p.Confidence > other.Confidence
+	}
+	return p.CalibratedAt.After(other.CalibratedAt)
+}
+
+// writeFileAtomic writes data to a temporary file in the directory of path,
+// with permissions 0600, then renames it over path.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := fsguard.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer fsguard.Remove(tmp.Name()) // no-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return fsguard.Rename(tmp.Name(), path)
+}
+
 // IsValid checks if the profile is valid for the current hardware.
 // A profile is considered valid if:
 // - The profile version matches
@@ -178,12 +255,13 @@ func (p *CalibrationProfile) String() string {
 	}
 
 	return fmt.Sprintf(
-		"CalibrationProfile{CPU: %s, Parallel: %d bits, FFT: %d bits, Strassen: %d bits, Calibrated: %s}",
+		"CalibrationProfile{CPU: %s, Parallel: %d bits, FFT: %d bits, Strassen: %d bits, Calibrated: %s, Seed: %d}",
 		p.CPUModel,
 		p.OptimalParallelThreshold,
 		p.OptimalFFTThreshold,
 		p.OptimalStrassenThreshold,
 		p.CalibratedAt.Format(time.RFC3339),
+		p.Seed,
 	)
 }
 
@@ -1,11 +1,16 @@
 package calibration
 
 import (
+	"errors"
+	"io"
 	"os"
 	"path/filepath"
 	"runtime"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/agbru/fibcalc/internal/config"
 )
 
 func TestNewProfile(t *testing.T) {
@@ -64,6 +69,7 @@ func TestProfileSaveLoad(t *testing.T) {
 	original.OptimalStrassenThreshold = 256
 	original.CalibrationN = 10000000
 	original.CalibrationTime = "1m30s"
+	original.Seed = 42
 
 	if err := original.SaveProfile(profilePath); err != nil {
 		t.Fatalf("SaveProfile failed: %v", err)
@@ -99,6 +105,10 @@ func TestProfileSaveLoad(t *testing.T) {
 	if loaded.NumCPU != original.NumCPU {
 		t.Errorf("NumCPU = %d, want %d", loaded.NumCPU, original.NumCPU)
 	}
+
+	if loaded.Seed != original.Seed {
+		t.Errorf("Seed = %d, want %d", loaded.Seed, original.Seed)
+	}
 }
 
 func TestProfileIsValid(t *testing.T) {
@@ -260,3 +270,121 @@ func TestGetDefaultProfilePath(t *testing.T) {
 	}
 }
 
+
+// TestSaveProfileConcurrent checks that profiles saved concurrently to the
+// same path leave a complete, valid profile.
+func TestSaveProfileConcurrent(t *testing.T) {
+	t.Parallel()
+	profilePath := filepath.Join(t.TempDir(), "profile.json")
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			profile := NewProfile()
+			profile.OptimalParallelThreshold = 1024 * (i + 1)
+			profile.Confidence = 1
+			if err := profile.SaveProfile(profilePath); err != nil && !errors.Is(err, ErrProfileSuperseded) {
+				t.Errorf("SaveProfile failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	loaded, err := loadProfile(profilePath)
+	if err != nil {
+		t.Fatalf("profile unreadable after concurrent saves: %v", err)
+	}
+	if !loaded.IsValid() || loaded.OptimalParallelThreshold == 0 {
+		t.Errorf("invalid profile after concurrent saves: %s", loaded)
+	}
+}
+
+func TestLockProfileTimeout(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" && runtime.GOOS != "windows" {
+		t.Skip("file locking not tested on this platform")
+	}
+	profilePath := filepath.Join(t.TempDir(), "profile.json")
+
+	unlock, err := lockProfile(profilePath, time.Second)
+	if err != nil {
+		t.Fatalf("lockProfile failed: %v", err)
+	}
+	if _, err := lockProfile(profilePath, 100*time.Millisecond); !errors.Is(err, ErrProfileLocked) {
+		t.Errorf("second lockProfile = %v, want ErrProfileLocked", err)
+	}
+	unlock()
+
+	unlock, err = lockProfile(profilePath, 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("lockProfile after unlock failed: %v", err)
+	}
+	unlock()
+}
+
+// TestSaveProfileMergePolicy checks that the most recent of the most
+// confident calibrations is kept, whatever the order of the saves.
+func TestSaveProfileMergePolicy(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	tests := []struct {
+		name          string
+		stored, saved float64 // confidences
+		storedAge     time.Duration
+		wantKept      bool
+	}{
+		{"newer stored profile is kept", 1, 1, 0, true},
+		{"older stored profile is replaced", 1, 1, 2 * time.Minute, false},
+		{"more confident stored profile is kept", 1, 0.6, 2 * time.Minute, true},
+		{"less confident stored profile is replaced", 0.6, 1, 0, false},
+		{"profile without confidence is replaced", 0, 0.6, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			profilePath := filepath.Join(t.TempDir(), "profile.json")
+
+			stored := NewProfile()
+			stored.OptimalParallelThreshold = 1111
+			stored.Confidence = tt.stored
+			stored.CalibratedAt = now.Add(-tt.storedAge)
+			if err := stored.SaveProfile(profilePath); err != nil {
+				t.Fatalf("SaveProfile failed: %v", err)
+			}
+
+			saved := NewProfile()
+			saved.OptimalParallelThreshold = 2222
+			saved.Confidence = tt.saved
+			saved.CalibratedAt = now.Add(-time.Minute)
+			err := saved.SaveProfile(profilePath)
+			if got := errors.Is(err, ErrProfileSuperseded); got != tt.wantKept {
+				t.Errorf("SaveProfile = %v, superseded %v, want %v", err, got, tt.wantKept)
+			}
+
+			loaded, err := loadProfile(profilePath)
+			if err != nil {
+				t.Fatalf("loadProfile failed: %v", err)
+			}
+			want := 2222
+			if tt.wantKept {
+				want = 1111
+			}
+			if loaded.OptimalParallelThreshold != want {
+				t.Errorf("stored threshold = %d, want %d", loaded.OptimalParallelThreshold, want)
+			}
+		})
+	}
+}
+
+func TestSaveCalibrationProfileReadOnly(t *testing.T) {
+	t.Parallel()
+	profilePath := filepath.Join(t.TempDir(), "profile.json")
+	cfg := config.AppConfig{Threshold: 4096, ProfileReadOnly: true}
+
+	saveCalibrationProfile(cfg, profilePath, io.Discard, time.Now(), 1)
+	if _, err := os.Stat(profilePath); !os.IsNotExist(err) {
+		t.Errorf("profile written with ProfileReadOnly set (stat: %v)", err)
+	}
+}
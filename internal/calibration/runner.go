@@ -2,24 +2,28 @@ package calibration
 
 import (
 	"context"
+	"math/rand/v2"
 	"time"
 
 	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/rs/zerolog"
 )
 
 // calibrationRunner encapsulates the trial run logic for calibration.
 type calibrationRunner struct {
 	ctx      context.Context
 	perTrial time.Duration
+	rng      *rand.Rand
 }
 
-// newCalibrationRunner creates a new calibration runner.
-func newCalibrationRunner(ctx context.Context, timeout time.Duration) *calibrationRunner {
+// newCalibrationRunner creates a new calibration runner whose candidate
+// order is derived from seed.
+func newCalibrationRunner(ctx context.Context, timeout time.Duration, seed int64) *calibrationRunner {
 	perTrial := timeout / 6
 	if perTrial < 2*time.Second {
 		perTrial = 2 * time.Second
 	}
-	return &calibrationRunner{ctx: ctx, perTrial: perTrial}
+	return &calibrationRunner{ctx: ctx, perTrial: perTrial, rng: newRand(seed)}
 }
 
 // runTrial executes a single calibration trial with the given calculator and options.
@@ -36,7 +40,16 @@ func (r *calibrationRunner) runTrial(calc fibonacci.Calculator, opts fibonacci.O
 	defer cancel()
 	start := time.Now()
 	_, err = calc.Calculate(ctx, nil, 0, fibonacci.CalibrationN, opts)
-	return time.Since(start), err
+	duration = time.Since(start)
+	zerolog.Ctx(r.ctx).Debug().
+		Str("calculator", calc.Name()).
+		Int("parallel_threshold", opts.ParallelThreshold).
+		Int("fft_threshold", opts.FFTThreshold).
+		Int("strassen_threshold", opts.StrassenThreshold).
+		Dur("duration", duration).
+		Err(err).
+		Msg("calibration trial")
+	return duration, err
 }
 
 // findBestParallelThreshold finds the optimal parallel threshold.
@@ -49,7 +62,7 @@ func (r *calibrationRunner) runTrial(calc fibonacci.Calculator, opts fibonacci.O
 //   - int: The best parallel threshold found.
 //   - time.Duration: The duration achieved with the best threshold.
 func (r *calibrationRunner) findBestParallelThreshold(calc fibonacci.Calculator, defaultThreshold int) (threshold int, duration time.Duration) {
-	candidates := GenerateQuickParallelThresholds()
+	candidates := shuffled(GenerateQuickParallelThresholds(), r.rng)
 	best := defaultThreshold
 	bestDur := time.Duration(1<<63 - 1)
 
@@ -76,7 +89,7 @@ func (r *calibrationRunner) findBestParallelThreshold(calc fibonacci.Calculator,
 //   - int: The best FFT threshold found.
 //   - time.Duration: The duration achieved with the best threshold.
 func (r *calibrationRunner) findBestFFTThreshold(calc fibonacci.Calculator, parallelThreshold, defaultThreshold int) (threshold int, duration time.Duration) {
-	candidates := GenerateQuickFFTThresholds()
+	candidates := shuffled(GenerateQuickFFTThresholds(), r.rng)
 	best := defaultThreshold
 	bestDur := time.Duration(1<<63 - 1)
 
@@ -103,7 +116,7 @@ func (r *calibrationRunner) findBestFFTThreshold(calc fibonacci.Calculator, para
 //   - int: The best Strassen threshold found.
 //   - time.Duration: The duration achieved with the best threshold.
 func (r *calibrationRunner) findBestStrassenThreshold(calc fibonacci.Calculator, parallelThreshold, defaultThreshold int) (threshold int, duration time.Duration) {
-	candidates := GenerateQuickStrassenThresholds()
+	candidates := shuffled(GenerateQuickStrassenThresholds(), r.rng)
 	best := defaultThreshold
 	bestDur := time.Duration(1<<63 - 1)
 
@@ -0,0 +1,32 @@
+// This file implements seeded randomization of calibration trial order.
+
+package calibration
+
+import (
+	"math/rand/v2"
+)
+
+// newRand returns a deterministic random source for the given seed, so that
+// the same seed always yields the same trial order.
+func newRand(seed int64) *rand.Rand {
+	return rand.New(rand.NewPCG(uint64(seed), 0))
+}
+
+// shuffled returns a copy of items in an order derived from rng.
+//
+// Trials are run in random order so that systematic drift during a
+// calibration (CPU frequency scaling, thermal throttling, cache warm-up)
+// does not consistently favour the candidates tested first or last.
+//
+// Parameters:
+//   - items: The items to shuffle; the slice is not modified.
+//   - rng: The random source.
+//
+// Returns:
+//   - []T: A shuffled copy of items.
+func shuffled[T any](items []T, rng *rand.Rand) []T {
+	out := make([]T, len(items))
+	copy(out, items)
+	rng.Shuffle(len(out), func(i, j int) { out[i], out[j] = out[j], out[i] })
+	return out
+}
@@ -0,0 +1,35 @@
+package calibration
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestShuffledIsReproducible(t *testing.T) {
+	t.Parallel()
+	items := []int{0, 256, 512, 1024, 2048, 4096, 8192, 16384}
+
+	a := shuffled(items, newRand(42))
+	b := shuffled(items, newRand(42))
+	if !slices.Equal(a, b) {
+		t.Errorf("same seed gave different orders: %v vs %v", a, b)
+	}
+
+	sorted := slices.Clone(a)
+	slices.Sort(sorted)
+	if !slices.Equal(sorted, items) {
+		t.Errorf("shuffled(%v) = %v is not a permutation", items, a)
+	}
+	if items[0] != 0 || items[7] != 16384 {
+		t.Error("shuffled must not modify its input")
+	}
+
+	// Different seeds should produce at least one different order.
+	differs := false
+	for seed := int64(1); seed < 10 && !differs; seed++ {
+		differs = !slices.Equal(a, shuffled(items, newRand(seed)))
+	}
+	if !differs {
+		t.Error("different seeds should produce different orders")
+	}
+}
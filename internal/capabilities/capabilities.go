@@ -0,0 +1,163 @@
+package capabilities
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+
+	"github.com/agbru/fibcalc/internal/bigfft"
+	"github.com/agbru/fibcalc/internal/energy"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/fibonacci/memory"
+	"github.com/agbru/fibcalc/internal/perfevent"
+)
+
+// Capability is the status of one optional subsystem.
+type Capability struct {
+	// Name identifies the subsystem.
+	Name string `json:"name"`
+	// Compiled is true if the subsystem is built into this binary, by
+	// build tag or target platform.
+	Compiled bool `json:"compiled"`
+	// Available is true if the subsystem can be used on this machine; it
+	// implies Compiled.
+	Available bool `json:"available"`
+	// Detail describes the subsystem's state or why it is unavailable.
+	Detail string `json:"detail"`
+}
+
+// Report is the capabilities of a binary on a machine.
+type Report struct {
+	Version      string       `json:"version"`
+	GoVersion    string       `json:"go_version"`
+	Platform     string       `json:"platform"`
+	NumCPU       int          `json:"num_cpu"`
+	Capabilities []Capability `json:"capabilities"`
+}
+
+// Detect probes the optional subsystems. The probes are short trials
+// (opening counters, mapping a page), safe to run at any time.
+//
+// Parameters:
+//   - version: The fibcalc version.
+//   - factory: The calculators built into the binary.
+//
+// Returns:
+//   - Report: The status of every subsystem, in a fixed order.
+func Detect(version string, factory fibonacci.CalculatorFactory) Report {
+	return Report{
+		Version:   version,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+		NumCPU:    runtime.NumCPU(),
+		Capabilities: []Capability{
+			simd(),
+			gmp(factory),
+			perfCounters(),
+			rapl(),
+			mmapWriter(),
+			paranoid(),
+			{Name: "clipboard", Detail: "export results to a file instead (--output, or e in the TUI summary)"},
+		},
+	}
+}
+
+// gmp reports whether the binary was built with the gmp tag.
+func gmp(factory fibonacci.CalculatorFactory) Capability {
+	if _, err := factory.Get("gmp"); err != nil {
+		return Capability{Name: "gmp", Detail: "build with -tags=gmp and libgmp"}
+	}
+	return Capability{Name: "gmp", Compiled: true, Available: true, Detail: "GMP calculator registered as --algo gmp"}
+}
+
+// perfCounters reports whether hardware counters can be opened.
+func perfCounters() Capability {
+	c := Capability{Name: "perf-counters", Compiled: runtime.GOOS == "linux"}
+	if err := perfevent.Available(); err != nil {
+		c.Detail = err.Error()
+		return c
+	}
+	c.Available, c.Detail = true, "perf_event_open: cycles, instructions, cache misses (--perf-counters)"
+	return c
+}
+
+// rapl reports whether the RAPL energy counters can be read.
+func rapl() Capability {
+	c := Capability{Name: "rapl", Compiled: runtime.GOOS == "linux"}
+	if err := energy.RAPLAvailable(); err != nil {
+		c.Detail = fmt.Sprintf("%v; --energy estimates from the TDP", err)
+		return c
+	}
+	c.Available, c.Detail = true, "package energy counters readable (--energy)"
+	return c
+}
+
+// mmapWriter reports whether the low-memory calculator can keep its
+// operands in memory-mapped files.
+func mmapWriter() Capability {
+	c := Capability{Name: "mmap", Compiled: memory.MappingSupported()}
+	if !c.Compiled {
+		c.Detail = "no memory mapping on " + runtime.GOOS + ": lowmem buffers stay on the heap"
+		return c
+	}
+	m, err := memory.NewMappedWords("", 1)
+	if err != nil {
+		c.Detail = err.Error()
+		return c
+	}
+	if err := m.Close(); err != nil {
+		c.Detail = err.Error()
+		return c
+	}
+	c.Available, c.Detail = true, "lowmem operands can be mapped from temporary files"
+	return c
+}
+
+// paranoid reports whether the bigfft_paranoid tag enabled the checks for
+// the whole binary; --paranoid enables them for one run in any build.
+func paranoid() Capability {
+	if !bigfft.Paranoid() {
+		return Capability{Name: "paranoid-build", Detail: "build with -tags=bigfft_paranoid, or use --paranoid per run"}
+	}
+	return Capability{Name: "paranoid-build", Compiled: true, Available: true, Detail: "FFT invariants checked on every run"}
+}
+
+// Write prints the report as an aligned table.
+//
+// Parameters:
+//   - w: The destination.
+//   - r: The report.
+func Write(w io.Writer, r Report) {
+	fmt.Fprintf(w, "fibcalc %s (%s, %s, %d CPUs)\n\n", r.Version, r.GoVersion, r.Platform, r.NumCPU)
+	for _, c := range r.Capabilities {
+		fmt.Fprintf(w, "  %-16s %-13s %s\n", c.Name, c.Status(), c.Detail)
+	}
+}
+
+// WriteJSON prints the report as indented JSON.
+//
+// Parameters:
+//   - w: The destination.
+//   - r: The report.
+//
+// Returns:
+//   - error: An error if the report cannot be written.
+func WriteJSON(w io.Writer, r Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// Status summarizes c in a word: "available", "unavailable" (built in but
+// unusable here) or "not built in".
+func (c Capability) Status() string {
+	switch {
+	case c.Available:
+		return "available"
+	case c.Compiled:
+		return "unavailable"
+	default:
+		return "not built in"
+	}
+}
@@ -0,0 +1,60 @@
+package capabilities
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/agbru/fibcalc/internal/fibonacci"
+)
+
+func TestDetect(t *testing.T) {
+	r := Detect("v1.2.3", fibonacci.NewDefaultFactory())
+	if r.Version != "v1.2.3" || r.Platform == "" || r.NumCPU <= 0 {
+		t.Errorf("unexpected report header: %+v", r)
+	}
+	seen := make(map[string]bool)
+	for _, c := range r.Capabilities {
+		if seen[c.Name] {
+			t.Errorf("capability %s reported twice", c.Name)
+		}
+		seen[c.Name] = true
+		if c.Available && !c.Compiled {
+			t.Errorf("%s is available but not built in", c.Name)
+		}
+		if c.Detail == "" {
+			t.Errorf("%s has no detail", c.Name)
+		}
+	}
+	for _, name := range []string{"simd", "gmp", "perf-counters", "rapl", "mmap", "paranoid-build", "clipboard"} {
+		if !seen[name] {
+			t.Errorf("capability %s missing", name)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	r := Report{Version: "v1", GoVersion: "go1.25", Platform: "linux/amd64", NumCPU: 8, Capabilities: []Capability{
+		{Name: "gmp", Compiled: true, Available: true, Detail: "registered"},
+		{Name: "rapl", Compiled: true, Detail: "permission denied"},
+		{Name: "clipboard", Detail: "use files"},
+	}}
+
+	var text bytes.Buffer
+	Write(&text, r)
+	for _, want := range []string{"fibcalc v1 (go1.25, linux/amd64, 8 CPUs)", "gmp              available", "rapl             unavailable", "clipboard        not built in"} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("expected %q in:\n%s", want, text.String())
+		}
+	}
+
+	var out bytes.Buffer
+	if err := WriteJSON(&out, r); err != nil {
+		t.Fatal(err)
+	}
+	var decoded Report
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil || len(decoded.Capabilities) != 3 || !decoded.Capabilities[0].Available {
+		t.Errorf("JSON round trip = %+v, %v", decoded, err)
+	}
+}
@@ -0,0 +1,6 @@
+// Package capabilities reports which optional subsystems of fibcalc are
+// compiled into the binary and usable on the current machine: SIMD
+// detection, the GMP backend, hardware counters, RAPL energy counters,
+// memory-mapped buffers and so on. It backs `fibcalc capabilities`, meant
+// for support scripts, and the about screen of the TUI.
+package capabilities
@@ -0,0 +1,16 @@
+//go:build amd64
+
+package capabilities
+
+import "github.com/agbru/fibcalc/internal/bigfft"
+
+// simd reports the SIMD features detected by bigfft.
+func simd() Capability {
+	features := bigfft.GetCPUFeatures()
+	return Capability{
+		Name:      "simd",
+		Compiled:  true,
+		Available: features.SIMDLevel != bigfft.SIMDNone,
+		Detail:    features.String(),
+	}
+}
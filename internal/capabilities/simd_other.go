@@ -0,0 +1,14 @@
+//go:build !amd64
+
+package capabilities
+
+import "runtime"
+
+// simd reports that SIMD detection is only compiled in on amd64. Vector
+// arithmetic still uses the assembly of math/big where it has one.
+func simd() Capability {
+	return Capability{
+		Name:   "simd",
+		Detail: "CPU feature detection is only compiled in on amd64, not " + runtime.GOARCH,
+	}
+}
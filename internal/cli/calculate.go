@@ -4,9 +4,13 @@ import (
 	"fmt"
 	"io"
 	"runtime"
+	"strings"
 
 	"github.com/agbru/fibcalc/internal/config"
 	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/fibonacci/memory"
+	"github.com/agbru/fibcalc/internal/format"
+	"github.com/agbru/fibcalc/internal/orchestration"
 	"github.com/agbru/fibcalc/internal/ui"
 )
 
@@ -20,11 +24,32 @@ import (
 func PrintExecutionConfig(cfg config.AppConfig, out io.Writer) {
 	fmt.Fprintf(out, "--- Execution Configuration ---\n")
 	fmt.Fprintf(out, "Calculating %sF(%d)%s with a timeout of %s%s%s.\n",
-		ui.ColorMagenta(), cfg.N, ui.ColorReset(), ui.ColorYellow(), cfg.Timeout, ui.ColorReset())
+		ui.ColorMagenta(), cfg.N, ui.ColorReset(), ui.ColorYellow(), format.FormatExecutionDuration(cfg.Timeout), ui.ColorReset())
 	fmt.Fprintf(out, "Environment: %s%d%s logical processors, Go %s%s%s.\n",
 		ui.ColorCyan(), runtime.NumCPU(), ui.ColorReset(), ui.ColorCyan(), runtime.Version(), ui.ColorReset())
 	fmt.Fprintf(out, "Optimization thresholds: Parallelism=%s%d%s bits, FFT=%s%d%s bits.\n",
 		ui.ColorCyan(), cfg.Threshold, ui.ColorReset(), ui.ColorCyan(), cfg.FFTThreshold, ui.ColorReset())
+	if cfg.Warmup > 0 {
+		fmt.Fprintf(out, "Warm-up: %s%d%s untimed run(s) per algorithm on F(%d).\n",
+			ui.ColorCyan(), cfg.Warmup, ui.ColorReset(), orchestration.WarmupIndex(cfg.N))
+	}
+	if cfg.Paranoid {
+		fmt.Fprintf(out, "Paranoid mode: %sFFT invariants and sampled coefficients checked%s.\n",
+			ui.ColorCyan(), ui.ColorReset())
+	}
+	if cfg.Nice != 0 || cfg.IONice != "" || cfg.Background {
+		var parts []string
+		if cfg.Nice != 0 {
+			parts = append(parts, fmt.Sprintf("nice %d", cfg.Nice))
+		}
+		if cfg.IONice != "" {
+			parts = append(parts, "I/O "+cfg.IONice)
+		}
+		if cfg.Background {
+			parts = append(parts, fmt.Sprintf("%d processor(s) (background)", runtime.GOMAXPROCS(0)))
+		}
+		fmt.Fprintf(out, "Priority: %s%s%s.\n", ui.ColorCyan(), strings.Join(parts, ", "), ui.ColorReset())
+	}
 }
 
 
@@ -32,11 +57,19 @@ func PrintExecutionConfig(cfg config.AppConfig, out io.Writer) {
 //
 // Parameters:
 //   - calculators: The slice of calculators that will be executed.
+//   - mode: How multiple calculators are scheduled.
 //   - out: The writer for standard output.
-func PrintExecutionMode(calculators []fibonacci.Calculator, out io.Writer) {
+func PrintExecutionMode(calculators []fibonacci.Calculator, mode orchestration.CompareMode, out io.Writer) {
 	var modeDesc string
 	if len(calculators) > 1 {
-		modeDesc = "Parallel comparison of all algorithms"
+		switch mode {
+		case orchestration.CompareSequential:
+			modeDesc = "Sequential comparison of all algorithms (one at a time)"
+		case orchestration.CompareStaggered:
+			modeDesc = fmt.Sprintf("Staggered comparison of all algorithms (%s start offset)", orchestration.StaggerDelay)
+		default:
+			modeDesc = "Parallel comparison of all algorithms"
+		}
 	} else {
 		modeDesc = fmt.Sprintf("Single calculation with the %s%s%s algorithm",
 			ui.ColorGreen(), calculators[0].Name(), ui.ColorReset())
@@ -45,3 +78,14 @@ func PrintExecutionMode(calculators []fibonacci.Calculator, out io.Writer) {
 	fmt.Fprintf(out, "\n--- Starting Execution ---\n")
 }
 
+
+// PrintMemoryPressure warns that the memory approached the runtime's soft
+// limit during the calculation and that the transform caches were dropped.
+//
+// Parameters:
+//   - p: The pressure reported by memory.PressureMonitor.
+//   - out: The writer for the warning.
+func PrintMemoryPressure(p memory.Pressure, out io.Writer) {
+	fmt.Fprintf(out, "%sWarning:%s memory at %s of the %s limit; FFT transform caches dropped and disabled.\n",
+		ui.ColorYellow(), ui.ColorReset(), format.FormatBytes(p.Used), format.FormatBytes(p.Limit))
+}
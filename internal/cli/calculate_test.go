@@ -43,7 +43,7 @@ func TestPrintExecutionMode(t *testing.T) {
 		var buf bytes.Buffer
 		calculators := []fibonacci.Calculator{factory.MustGet("fast")}
 
-		PrintExecutionMode(calculators, &buf)
+		PrintExecutionMode(calculators, orchestration.CompareParallel, &buf)
 
 		output := buf.String()
 		if output == "" {
@@ -56,7 +56,7 @@ func TestPrintExecutionMode(t *testing.T) {
 		var buf bytes.Buffer
 		calculators := orchestration.GetCalculatorsToRun("all", factory)
 
-		PrintExecutionMode(calculators, &buf)
+		PrintExecutionMode(calculators, orchestration.CompareParallel, &buf)
 
 		output := buf.String()
 		if output == "" {
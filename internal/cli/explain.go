@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/ui"
+)
+
+// ExplainDoubling prints how fast doubling computes F(n) and returns the
+// step hook that explains each step, for --explain.
+//
+// Parameters:
+//   - out: The output writer.
+//   - n: The index.
+//
+// Returns:
+//   - fibonacci.StepHook: The hook to set in fibonacci.Options.OnStep.
+func ExplainDoubling(out io.Writer, n uint64) fibonacci.StepHook {
+	fmt.Fprintf(out, "\n%s--- Fast doubling: F(%d) ---%s\n", ui.ColorBold(), n, ui.ColorReset())
+	fmt.Fprintf(out, "n = %d = %s in binary. Starting from k = 0, F(0) = 0, F(1) = 1, each bit\n", n, strconv.FormatUint(n, 2))
+	fmt.Fprintln(out, "from the most significant doubles k, then adds 1 to k if the bit is 1:")
+	fmt.Fprintln(out, "  F(2k)   = F(k) × (2×F(k+1) - F(k))")
+	fmt.Fprintln(out, "  F(2k+1) = F(k+1)² + F(k)²")
+	fmt.Fprintln(out, "  F(k+2)  = F(k) + F(k+1)")
+
+	// The values before the step, to show the identities applied
+	var k uint64
+	fk, fk1 := big.NewInt(0), big.NewInt(1)
+	bit := -1
+	return func(e fibonacci.StepEvent) {
+		if e.Bit != bit {
+			bit = e.Bit
+			fmt.Fprintf(out, "%sBit %d = %d%s\n", ui.ColorCyan(), e.Bit, (n>>uint(e.Bit))&1, ui.ColorReset())
+		}
+		a, b := e.Values[0], e.Values[1]
+		switch e.Kind {
+		case fibonacci.StepDoubling:
+			fmt.Fprintf(out, "  double: k = %d → %d\n", k, e.K)
+			fmt.Fprintf(out, "    F(%d) = %s × (2×%s - %s) = %s%s%s\n", e.K, fk, fk1, fk, ui.ColorGreen(), a, ui.ColorReset())
+			fmt.Fprintf(out, "    F(%d) = %s² + %s² = %s%s%s\n", e.K+1, fk1, fk, ui.ColorGreen(), b, ui.ColorReset())
+		case fibonacci.StepAddition:
+			fmt.Fprintf(out, "  add:    k = %d → %d\n", k, e.K)
+			fmt.Fprintf(out, "    F(%d) = %s + %s = %s%s%s\n", e.K+1, fk, fk1, ui.ColorGreen(), b, ui.ColorReset())
+		}
+		k = e.K
+		fk.Set(a)
+		fk1.Set(b)
+	}
+}
+
+// ExplainMatrix prints how matrix exponentiation computes F(n) and returns
+// the step hook that explains each step, for --explain.
+//
+// Parameters:
+//   - out: The output writer.
+//   - n: The index.
+//
+// Returns:
+//   - fibonacci.StepHook: The hook to set in fibonacci.Options.OnStep.
+func ExplainMatrix(out io.Writer, n uint64) fibonacci.StepHook {
+	fmt.Fprintf(out, "\n%s--- Matrix exponentiation: F(%d) ---%s\n", ui.ColorBold(), n, ui.ColorReset())
+	fmt.Fprintln(out, "With Q = [1 1; 1 0], Q^m = [F(m+1) F(m); F(m) F(m-1)], so F(n) is the")
+	fmt.Fprintln(out, "top-left entry of Q^(n-1).")
+	if n > 0 {
+		fmt.Fprintf(out, "n - 1 = %d = %s in binary. Starting from R = I and P = Q, each bit from\n", n-1, strconv.FormatUint(n-1, 2))
+		fmt.Fprintln(out, "the least significant multiplies R by P if it is 1, then squares P.")
+	}
+
+	bit := -1
+	return func(e fibonacci.StepEvent) {
+		if e.Bit != bit {
+			bit = e.Bit
+			fmt.Fprintf(out, "%sBit %d = %d%s\n", ui.ColorCyan(), e.Bit, ((n-1)>>uint(e.Bit))&1, ui.ColorReset())
+		}
+		op := "R = R × P"
+		if e.Kind == fibonacci.StepMatrixSquare {
+			op = "P = P²   "
+		}
+		fmt.Fprintf(out, "  %s = Q^%-3d = %s[%s %s; %s %s]%s\n",
+			op, e.K, ui.ColorGreen(), e.Values[0], e.Values[1], e.Values[2], e.Values[3], ui.ColorReset())
+	}
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/ui"
+)
+
+func TestExplain(t *testing.T) {
+	ui.InitTheme(true)
+	defer ui.InitTheme(false)
+
+	tests := []struct {
+		algo    string
+		explain func(io.Writer, uint64) fibonacci.StepHook
+		want    []string
+	}{
+		{"fast", ExplainDoubling, []string{
+			"n = 13 = 1101 in binary.",
+			"Bit 1 = 0\n  double: k = 3 → 6\n    F(6) = 2 × (2×3 - 2) = 8\n    F(7) = 3² + 2² = 13\n",
+			"  add:    k = 12 → 13\n    F(14) = 144 + 233 = 377\n",
+		}},
+		{"matrix", ExplainMatrix, []string{
+			"n - 1 = 12 = 1100 in binary.",
+			"Bit 2 = 1\n  R = R × P = Q^4   = [5 3; 3 2]\n  P = P²    = Q^8   = [34 21; 21 13]\n",
+			"  R = R × P = Q^12  = [233 144; 144 89]\n",
+		}},
+	}
+	for _, tt := range tests {
+		calc, err := fibonacci.NewDefaultFactory().Get(tt.algo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		opts := fibonacci.Options{DisableTables: true, OnStep: tt.explain(&buf, 13)}
+		if _, err := calc.Calculate(context.Background(), nil, 0, 13, opts); err != nil {
+			t.Fatalf("%s: Calculate() error = %v", tt.algo, err)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("%s: output does not contain %q:\n%s", tt.algo, want, buf.String())
+			}
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/format"
+	"github.com/agbru/fibcalc/internal/orchestration"
+)
+
+// GHASummary is the content of a GitHub Actions job summary (--gha-summary).
+type GHASummary struct {
+	// N is the index computed.
+	N uint64
+	// Version is the fibcalc version.
+	Version string
+	// Results are the runs of the algorithms, in the order of the results
+	// table.
+	Results []orchestration.CalculationResult
+}
+
+// WriteGHASummary writes the results as a Markdown section for the file
+// named by GITHUB_STEP_SUMMARY: a heading, the platform and one table row
+// per algorithm with its duration, its ratio to the fastest successful run,
+// the result size and its status.
+//
+// Parameters:
+//   - w: The writer, the summary file opened for appending.
+//   - s: The summary.
+//
+// Returns:
+//   - error: An error if the summary cannot be written.
+func WriteGHASummary(w io.Writer, s GHASummary) error {
+	var fastest int64
+	for _, res := range s.Results {
+		if res.Err == nil && res.Duration > 0 && (fastest == 0 || res.Duration.Nanoseconds() < fastest) {
+			fastest = res.Duration.Nanoseconds()
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "### fibcalc: F(%s)\n\n", format.FormatNumberString(strconv.FormatUint(s.N, 10)))
+	fmt.Fprintf(&b, "fibcalc %s, %s/%s, %d CPUs\n\n", s.Version, runtime.GOOS, runtime.GOARCH, runtime.NumCPU())
+	b.WriteString("| Algorithm | Duration | vs fastest | Result bits | Status |\n")
+	b.WriteString("|---|---:|---:|---:|---|\n")
+	for _, res := range s.Results {
+		duration := format.FormatExecutionDuration(res.Duration)
+		if res.Duration == 0 {
+			duration = "< 1µs"
+		}
+		ratio, bits := "-", "-"
+		if res.Err == nil && fastest > 0 {
+			ratio = fmt.Sprintf("%.2f×", float64(res.Duration.Nanoseconds())/float64(fastest))
+		}
+		if res.Result != nil {
+			bits = format.FormatNumberString(strconv.Itoa(res.Result.BitLen()))
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n", markdownCell(res.Name), duration, ratio, bits, markdownCell(ghaStatus(res)))
+	}
+	b.WriteString("\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ghaStatus returns the status cell of a result in the job summary.
+func ghaStatus(res orchestration.CalculationResult) string {
+	kind := res.ErrorKind()
+	switch {
+	case kind == apperrors.ErrorKindNone:
+		return "✅ Success"
+	case kind == apperrors.ErrorKindCanceled:
+		return "⏹ " + kindLabels[kind]
+	case res.Err == nil:
+		return "❌ " + kindLabels[kind]
+	}
+	return fmt.Sprintf("❌ %s (%v)", kindLabels[kind], res.Err)
+}
+
+// markdownCell escapes s for a Markdown table cell.
+func markdownCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ", "\r", "").Replace(s)
+}
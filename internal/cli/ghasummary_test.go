@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/orchestration"
+)
+
+func TestWriteGHASummary(t *testing.T) {
+	t.Parallel()
+	var b strings.Builder
+	err := WriteGHASummary(&b, GHASummary{
+		N:       1000000,
+		Version: "v1.2.3",
+		Results: []orchestration.CalculationResult{
+			{Name: "Fast", Result: big.NewInt(55), Duration: 10 * time.Millisecond},
+			{Name: "Matrix", Result: big.NewInt(55), Duration: 25 * time.Millisecond},
+			{Name: "FFT", Duration: time.Second, Err: errors.New("bad | value")},
+		},
+	})
+	if err != nil {
+		t.Fatalf("WriteGHASummary() error = %v", err)
+	}
+	got := b.String()
+	for _, want := range []string{
+		"### fibcalc: F(1,000,000)\n",
+		"fibcalc v1.2.3, ",
+		"| Algorithm | Duration | vs fastest | Result bits | Status |\n",
+		"| Fast | 10ms | 1.00× | 6 | ✅ Success |\n",
+		"| Matrix | 25ms | 2.50× | 6 | ✅ Success |\n",
+		`| FFT | 1s | - | - | ❌ Failure (bad \| value) |` + "\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("summary lacks %q:\n%s", want, got)
+		}
+	}
+}
@@ -2,10 +2,12 @@ package cli
 
 import (
 	"bytes"
+	"fmt"
 	"math/big"
 	"testing"
 	"time"
 
+	"github.com/agbru/fibcalc/internal/metrics"
 	"github.com/agbru/fibcalc/internal/testutil"
 	"github.com/agbru/fibcalc/internal/ui"
 )
@@ -44,7 +46,7 @@ func TestDisplayResult_Golden(t *testing.T) {
 			verbose:  false,
 			details:  true,
 			showValue: false,
-			expected: "Result binary size: 6 bits.\n\n--- Detailed result analysis ---\nCalculation time        : < 1µs\nNumber of digits      : 2\n",
+			expected: "Result binary size: 6 bits.\n\n--- Detailed result analysis ---\nCalculation time        : < 1µs\nNumber of digits      : 2\nSHA-256 (big-endian)   : 7902699be42c8a8e46fbbb4501726517e86b22c56a189f7625a6da49081b2451\n",
 		},
 	}
 
@@ -71,3 +73,41 @@ func TestDisplayQuietResult_Golden(t *testing.T) {
 		t.Errorf("Golden mismatch quiet. Want %q, Got %q", expected, buf.String())
 	}
 }
+
+// TestDisplayIndicators_Golden checks that the default indicators render
+// as they did before they became pluggable, colors included.
+func TestDisplayIndicators_Golden(t *testing.T) {
+	defer ui.SetCurrentTheme(ui.GetCurrentTheme())
+	ui.SetCurrentTheme(ui.DarkTheme)
+	result, n, duration := big.NewInt(832040), uint64(30), 2*time.Millisecond
+
+	// The output of the fixed indicator list
+	ind := metrics.Compute(result, n, duration)
+	var want bytes.Buffer
+	fmt.Fprintf(&want, "\n%s--- Indicators of interest ---%s\n", ui.ColorBold(), ui.ColorReset())
+	fmt.Fprintf(&want, "Throughput (bits)       : %s%s%s\n",
+		ui.ColorGreen(), metrics.FormatBitsPerSecond(ind.BitsPerSecond), ui.ColorReset())
+	fmt.Fprintf(&want, "Throughput (digits)     : %s%s%s\n",
+		ui.ColorGreen(), metrics.FormatDigitsPerSecond(ind.DigitsPerSecond), ui.ColorReset())
+	fmt.Fprintf(&want, "Doubling steps          : %s%d%s  (%s%.2f steps/s%s)\n",
+		ui.ColorCyan(), ind.DoublingSteps, ui.ColorReset(),
+		ui.ColorCyan(), ind.StepsPerSecond, ui.ColorReset())
+	fmt.Fprintf(&want, "Golden ratio deviation  : %s%.4f%%%s\n",
+		ui.ColorMagenta(), ind.GoldenRatioDeviation, ui.ColorReset())
+	fmt.Fprintf(&want, "Digital root            : %s%d%s\n",
+		ui.ColorMagenta(), ind.DigitalRoot, ui.ColorReset())
+	fmt.Fprintf(&want, "Last 20 digits          : %s%s%s\n",
+		ui.ColorMagenta(), ind.LastDigits, ui.ColorReset())
+	parity := "odd"
+	if ind.IsEven {
+		parity = "even"
+	}
+	fmt.Fprintf(&want, "Parity                  : %s%s%s\n",
+		ui.ColorMagenta(), parity, ui.ColorReset())
+
+	var got bytes.Buffer
+	displayIndicators(&got, result, n, duration, metrics.DefaultRegistry.Indicators())
+	if got.String() != want.String() {
+		t.Errorf("indicators differ from the fixed list.\nWant:\n%q\nGot:\n%q", want.String(), got.String())
+	}
+}
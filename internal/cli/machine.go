@@ -0,0 +1,186 @@
+package cli
+
+import (
+	"bufio"
+	"encoding"
+	"encoding/json"
+	"io"
+	"math/big"
+	"reflect"
+	"strings"
+
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+)
+
+// MachineSchemaVersion is the schema_version of MachineResult documents.
+// It is incremented when a field is removed or renamed, or changes type or
+// meaning. Fields are added without incrementing it, so parsers must
+// ignore the fields they do not know.
+const MachineSchemaVersion = 1
+
+// MachineResult is the document --machine writes to stdout: the outcome of
+// the run for programs, while the human-readable output goes to stderr.
+// MachineSchema describes it as a JSON Schema.
+type MachineResult struct {
+	// SchemaVersion is MachineSchemaVersion in the documents written by
+	// DisplayMachineResult.
+	SchemaVersion int `json:"schema_version"`
+	// Version is the fibcalc version.
+	Version string `json:"version"`
+	// N is the index computed.
+	N uint64 `json:"n"`
+	// ExitCode is the exit code of the process.
+	ExitCode int `json:"exit_code"`
+	// Algorithm is the display name of the fastest successful run.
+	Algorithm string `json:"algorithm,omitempty"`
+	// Value is its result in decimal, the whole of F(n) unless LastDigits
+	// is set.
+	Value string `json:"value,omitempty"`
+	// Result, if Value is empty, is the value that DisplayMachineResult
+	// writes as Value, streamed by WriteResultStreamed rather than
+	// converted to a string.
+	Result *big.Int `json:"-"`
+	// Fallbacks, with --fallback, are the algorithms that failed before
+	// the one of Value, in the order they were tried.
+	Fallbacks []string `json:"fallbacks,omitempty"`
+	// LastDigits, with --last-digits, is the number of digits of Value:
+	// F(n) mod 10^LastDigits, zero-padded.
+	LastDigits int `json:"last_digits,omitempty"`
+	// ResultSHA256 is the digest of the value (see audit.HashResult), to
+	// compare results across machines without transferring them.
+	ResultSHA256 string `json:"result_sha256,omitempty"`
+	// Runs are the runs of the algorithms, in the order of the results
+	// table.
+	Runs []MachineRun `json:"runs"`
+	// Resources is the resource usage of the run, where the platform
+	// reports it.
+	Resources *MachineResources `json:"resources,omitempty"`
+}
+
+// MachineResources is the resource usage of the run in a MachineResult, as
+// reported by getrusage. CPU times and context switches cover the run;
+// MaxRSSBytes is the peak of the process.
+type MachineResources struct {
+	UserCPUNs                  int64  `json:"user_cpu_ns"`
+	SystemCPUNs                int64  `json:"system_cpu_ns"`
+	MaxRSSBytes                uint64 `json:"max_rss_bytes"`
+	VoluntaryContextSwitches   int64  `json:"voluntary_context_switches"`
+	InvoluntaryContextSwitches int64  `json:"involuntary_context_switches"`
+}
+
+// MachineRun is the run of one algorithm in a MachineResult. With --algo
+// all, the document has one per algorithm, failed ones included, and its
+// top-level fields summarize them.
+type MachineRun struct {
+	// Algorithm is the registered name of the calculator ("fast", "fft").
+	Algorithm string `json:"algorithm"`
+	// Name is the display name of the calculator.
+	Name       string `json:"name"`
+	DurationNs int64  `json:"duration_ns"`
+	ResultBits int    `json:"result_bits,omitempty"`
+	// ResultDigits is the number of decimal digits of the run's value.
+	ResultDigits int `json:"result_digits,omitempty"`
+	// ResultSHA256 is the digest of the run's value (see audit.HashResult).
+	ResultSHA256 string `json:"result_sha256,omitempty"`
+	// ErrorKind categorizes a failure, and Error describes it; both are
+	// omitted on success.
+	ErrorKind apperrors.ErrorKind `json:"error_kind,omitempty"`
+	Error     string              `json:"error,omitempty"`
+}
+
+// DisplayMachineResult writes the document as a single JSON line. A
+// Result is written as the last field, value.
+//
+// Parameters:
+//   - out: The output writer, stdout.
+//   - doc: The document.
+//
+// Returns:
+//   - error: An error if the document cannot be written.
+func DisplayMachineResult(out io.Writer, doc MachineResult) error {
+	doc.SchemaVersion = MachineSchemaVersion
+	if doc.Runs == nil {
+		doc.Runs = []MachineRun{}
+	}
+	if doc.Result == nil || doc.Value != "" {
+		return json.NewEncoder(out).Encode(doc)
+	}
+
+	// The document without its value ends with the closing brace, which the
+	// value is written before; its digits need no escaping
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(out)
+	bw.Write(data[:len(data)-1])
+	bw.WriteString(`,"value":"`)
+	if err := WriteResultStreamed(bw, doc.Result, 0); err != nil {
+		return err
+	}
+	bw.WriteString("\"}\n")
+	return bw.Flush()
+}
+
+// MachineSchema returns the JSON Schema of the MachineResult documents,
+// generated from the Go types: fields without omitempty are required, and
+// types encoded as text, such as the error kinds, are strings.
+//
+// Returns:
+//   - []byte: The schema, indented, ending with a newline.
+func MachineSchema() []byte {
+	schema := jsonSchema(reflect.TypeFor[MachineResult]())
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["title"] = "fibcalc --machine result"
+	schema["properties"].(map[string]any)["schema_version"] = map[string]any{"const": MachineSchemaVersion}
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		panic(err) // maps of strings and numbers always encode
+	}
+	return append(data, '\n')
+}
+
+// textMarshaler is the type of the values encoding/json writes as strings.
+var textMarshaler = reflect.TypeFor[encoding.TextMarshaler]()
+
+// jsonSchema returns the schema of the JSON encoding of values of type t.
+func jsonSchema(t reflect.Type) map[string]any {
+	if t.Implements(textMarshaler) {
+		return map[string]any{"type": "string"}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return jsonSchema(t.Elem())
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": jsonSchema(t.Elem())}
+	case reflect.Struct:
+		properties := make(map[string]any)
+		required := []string{}
+		for i := range t.NumField() {
+			f := t.Field(i)
+			name, options, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if !f.IsExported() || name == "-" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			properties[name] = jsonSchema(f.Type)
+			if !strings.Contains(options, "omitempty") {
+				required = append(required, name)
+			}
+		}
+		return map[string]any{"type": "object", "properties": properties, "required": required}
+	}
+	return map[string]any{}
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+)
+
+// TestDisplayMachineResultStreamed checks that a Result is written as the
+// value Value would give.
+func TestDisplayMachineResultStreamed(t *testing.T) {
+	t.Parallel()
+	value, _ := new(big.Int).SetString("-123456789012345678901234567890", 10)
+	var out bytes.Buffer
+	if err := DisplayMachineResult(&out, MachineResult{N: 100, Result: value, ResultSHA256: "abc"}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(out.String(), "\n") != 1 || !strings.HasSuffix(out.String(), "\n") {
+		t.Errorf("document is not a single line:\n%s", out.String())
+	}
+	var doc MachineResult
+	if err := json.Unmarshal(out.Bytes(), &doc); err != nil {
+		t.Fatalf("document is not valid JSON: %v\n%s", err, out.String())
+	}
+	if doc.Value != value.String() || doc.N != 100 || doc.ResultSHA256 != "abc" || doc.SchemaVersion != MachineSchemaVersion {
+		t.Errorf("document = %+v, want the value %s", doc, value)
+	}
+}
+
+// TestMachineSchema checks that a document written by DisplayMachineResult
+// has the fields the schema requires, and none it does not describe.
+func TestMachineSchema(t *testing.T) {
+	t.Parallel()
+	var schema struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+		Required   []string                   `json:"required"`
+	}
+	if err := json.Unmarshal(MachineSchema(), &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+
+	var out bytes.Buffer
+	err := DisplayMachineResult(&out, MachineResult{
+		Version:   "v1.2.3",
+		N:         10,
+		Algorithm: "Fast",
+		Value:     "55",
+		Runs: []MachineRun{
+			{Algorithm: "fast", Name: "Fast", DurationNs: 1000, ResultBits: 6},
+			{Algorithm: "fft", Name: "FFT", DurationNs: 2000, ErrorKind: apperrors.ErrorKindTimeout, Error: "timeout"},
+		},
+		Resources: &MachineResources{MaxRSSBytes: 1 << 20},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(out.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(doc["schema_version"]); got != "1" {
+		t.Errorf("schema_version = %s, want 1", got)
+	}
+	for _, name := range schema.Required {
+		if _, ok := doc[name]; !ok {
+			t.Errorf("document lacks the required field %q", name)
+		}
+	}
+	for name := range doc {
+		if _, ok := schema.Properties[name]; !ok {
+			t.Errorf("field %q is not in the schema", name)
+		}
+	}
+}
@@ -12,9 +12,10 @@
 //     Pure formatting helpers (duration, numbers, ETA) live in the format
 //     package and should be imported from there directly.
 //
-//   - Write* functions write data to files on the filesystem.
-//     They handle file creation, directory setup, and error handling.
-//     Examples: [WriteResultToFile].
+//   - Write* functions write data to files on the filesystem, or stream it
+//     to an [io.Writer]. They handle file creation, directory setup, and
+//     error handling.
+//     Examples: [WriteResultToFile], [WriteResultStreamed].
 //
 //   - Print* functions write to stdout as convenience wrappers.
 //     Examples: [PrintExecutionConfig], [PrintExecutionMode].
@@ -22,26 +23,58 @@
 package cli
 
 import (
+	"bufio"
+	"crypto/ed25519"
 	"fmt"
 	"io"
+	"math"
 	"math/big"
 	"os"
 	"path/filepath"
 	"time"
 
+	"github.com/agbru/fibcalc/internal/audit"
+	"github.com/agbru/fibcalc/internal/format"
+	"github.com/agbru/fibcalc/internal/fsguard"
+	"github.com/agbru/fibcalc/internal/metrics"
+	"github.com/agbru/fibcalc/internal/provenance"
 	"github.com/agbru/fibcalc/internal/ui"
 )
 
+// DefaultStreamChunkDigits is the number of digits WriteResultStreamed
+// converts at a time when given no chunk size: small enough for each chunk
+// to be converted by big.Int.Text, large enough for the splitting to stay
+// shallow.
+const DefaultStreamChunkDigits = 10_000
+
 // OutputConfig holds configuration for result output.
 type OutputConfig struct {
 	// OutputFile is the path to save the result (empty for no file output).
 	OutputFile string
 	// Quiet mode suppresses verbose output.
 	Quiet bool
+	// QuietDuration, if set, adds the duration in this unit to the quiet
+	// output, with DurationPrecision decimals (see FormatQuietLine).
+	QuietDuration format.DurationUnit
+	// DurationPrecision is the number of decimals of QuietDuration.
+	DurationPrecision int
 	// Verbose shows the full result value.
 	Verbose bool
 	// ShowValue enables the calculated value display when true (disabled by default).
 	ShowValue bool
+	// Seed is the run's random seed, recorded in the output file header
+	// when non-zero.
+	Seed int64
+	// Truncation controls how long values are shortened on screen.
+	Truncation Truncation
+	// Indicators are the indicators shown with the result. Nil shows all
+	// of metrics.DefaultRegistry.
+	Indicators []metrics.Indicator
+	// SigningKey, if set, signs the output file: its header then records
+	// the version, the digest of the value and the signature.
+	SigningKey ed25519.PrivateKey
+	// Version is the fibcalc version recorded in signed files.
+	Version string
 }
 
 // WriteResultToFile writes a calculation result to a file.
@@ -65,17 +98,19 @@ func WriteResultToFile(result *big.Int, n uint64, duration time.Duration, algo s
 	// Ensure directory exists with restrictive permissions
 	dir := filepath.Dir(outputPath)
 	if dir != "" && dir != "." {
-		if err := os.MkdirAll(dir, 0750); err != nil {
+		if err := fsguard.MkdirAll(dir, 0750); err != nil {
 			return fmt.Errorf("failed to create directory %q: %w", dir, err)
 		}
 	}
 
 	// Create file with restrictive (0600) permissions
-	file, err := os.OpenFile(outputPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	f, err := fsguard.OpenFile(outputPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
 	if err != nil {
 		return fmt.Errorf("failed to create output file %q: %w", outputPath, err)
 	}
-	defer file.Close()
+	defer f.Close()
+	file := bufio.NewWriter(f)
+	digits := DecimalDigits(result)
 
 	// Write header
 	fmt.Fprintf(file, "# Fibonacci Calculation Result\n")
@@ -84,15 +119,144 @@ func WriteResultToFile(result *big.Int, n uint64, duration time.Duration, algo s
 	fmt.Fprintf(file, "# Duration: %s\n", duration)
 	fmt.Fprintf(file, "# N: %d\n", n)
 	fmt.Fprintf(file, "# Bits: %d\n", result.BitLen())
-	fmt.Fprintf(file, "# Digits: %d\n", len(result.String()))
+	fmt.Fprintf(file, "# Digits: %d\n", digits)
+	if config.Seed != 0 {
+		fmt.Fprintf(file, "# Seed: %d\n", config.Seed)
+	}
+	if config.SigningKey != nil {
+		// The signed header records the digest
+		statement := provenance.NewStatement(n, algo, config.Version, result)
+		provenance.WriteHeader(file, provenance.Sign(config.SigningKey, statement))
+	} else {
+		fmt.Fprintf(file, "# Result-SHA256: %s\n", audit.HashResult(result))
+	}
 	fmt.Fprintf(file, "\n")
 
-	// Write result
-	fmt.Fprintf(file, "F(%d) =\n%s\n", n, result.String())
-
+	// Stream the result, wrapped and ruled with --wrap, so that its decimal
+	// string is never held in memory
+	fmt.Fprintf(file, "F(%d) =\n", n)
+	if width := config.Truncation.Wrap; width > 0 && digits > width {
+		rw := newRuledWriter(file, digits, width)
+		err = WriteResultStreamed(rw, result, 0)
+		if err == nil {
+			err = rw.Close()
+		}
+	} else if err = WriteResultStreamed(file, result, 0); err == nil {
+		_, err = file.WriteString("\n")
+	}
+	if err == nil {
+		err = file.Flush()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write output file %q: %w", outputPath, err)
+	}
 	return nil
 }
 
+// WriteResultStreamed writes the decimal expansion of a result to w, as
+// result.String() would but without materializing it: the value is split
+// recursively by powers of 10^(chunkDigits·2^k), and the chunks of
+// chunkDigits digits are converted and written from the most significant
+// down. Only a chunk of digits is held at a time instead of the decimal
+// string, a byte per digit (209 MB for F(10^9)) and as much again while
+// String copies it; the temporaries of the divisions, which the conversion
+// of String also needs, remain. No newline is written.
+//
+// Parameters:
+//   - w: The writer, buffered by the function.
+//   - result: The value to write.
+//   - chunkDigits: The number of digits converted at a time; 0 or less uses
+//     DefaultStreamChunkDigits.
+//
+// Returns:
+//   - error: An error if writing fails.
+func WriteResultStreamed(w io.Writer, result *big.Int, chunkDigits int) error {
+	if chunkDigits <= 0 {
+		chunkDigits = DefaultStreamChunkDigits
+	}
+	bw := bufio.NewWriter(w)
+	x := result
+	if x.Sign() < 0 {
+		bw.WriteByte('-')
+		x = new(big.Int).Abs(x)
+	}
+
+	// powers[k] = 10^(chunkDigits·2^k), up to the last one whose square
+	// does not exceed x, so that x < powers[len(powers)-1]²
+	powers := []*big.Int{new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(chunkDigits)), nil)}
+	for {
+		last := powers[len(powers)-1]
+		// last² ≥ 2^(2·(bits-1)), so it exceeds x without computing it
+		if 2*(last.BitLen()-1) >= x.BitLen() {
+			break
+		}
+		square := new(big.Int).Mul(last, last)
+		if square.Cmp(x) > 0 {
+			break
+		}
+		powers = append(powers, square)
+	}
+
+	if err := writeDecimalChunks(bw, x, powers, len(powers)-1, chunkDigits, false); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// writeDecimalChunks writes x < powers[k]², of chunkDigits·2^(k+1) digits
+// once padded, by writing its quotient and remainder by powers[k]; below
+// powers[0], x is a single chunk. Leading zeros are written only when pad
+// is set, i.e. for the parts that follow the most significant one.
+func writeDecimalChunks(bw *bufio.Writer, x *big.Int, powers []*big.Int, k, chunkDigits int, pad bool) error {
+	if k < 0 {
+		s := x.Text(10)
+		if pad {
+			for i := len(s); i < chunkDigits; i++ {
+				bw.WriteByte('0')
+			}
+		}
+		_, err := bw.WriteString(s)
+		return err
+	}
+	if !pad && x.Cmp(powers[k]) < 0 {
+		// No high part: skip the level rather than write zeros
+		return writeDecimalChunks(bw, x, powers, k-1, chunkDigits, false)
+	}
+	q, r := new(big.Int).QuoRem(x, powers[k], new(big.Int))
+	if err := writeDecimalChunks(bw, q, powers, k-1, chunkDigits, pad); err != nil {
+		return err
+	}
+	return writeDecimalChunks(bw, r, powers, k-1, chunkDigits, true)
+}
+
+// DecimalDigits returns the number of decimal digits of |x| without
+// converting it: 2^(bits-1) ≤ |x| < 2^bits gives the count within one, and
+// a comparison with a power of ten settles it. For F(n) itself,
+// fibonacci.DecimalDigits needs no value.
+//
+// Parameters:
+//   - x: The number.
+//
+// Returns:
+//   - int: The number of decimal digits of |x|, 1 for zero.
+func DecimalDigits(x *big.Int) int {
+	bits := x.BitLen()
+	if bits == 0 {
+		return 1
+	}
+	d := int(float64(bits-1)*math.Log10(2)) + 1
+	// Guard against the rounding of the estimate as well
+	p := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(d-1)), nil)
+	switch {
+	case x.CmpAbs(p) < 0:
+		return d - 1
+	case x.CmpAbs(p.Mul(p, big.NewInt(10))) >= 0:
+		return d + 1
+	default:
+		return d
+	}
+}
+
 // FormatQuietResult formats a result for quiet mode output.
 // Returns a single-line result suitable for scripting.
 //
@@ -118,6 +282,46 @@ func DisplayQuietResult(out io.Writer, result *big.Int, n uint64, duration time.
 	fmt.Fprintln(out, FormatQuietResult(result, n, duration))
 }
 
+// FormatQuietLine formats the quiet output of a value: the value alone, or
+// followed by a tab and the duration in config.QuietDuration when set. The
+// duration keeps its unit and decimals whatever its magnitude, so that the
+// lines of several runs parse alike.
+//
+// Parameters:
+//   - value: The value printed, such as the decimal digits of the result.
+//   - duration: The calculation duration.
+//   - config: Output configuration.
+//
+// Returns:
+//   - string: The line, without its newline.
+func FormatQuietLine(value string, duration time.Duration, config OutputConfig) string {
+	if config.QuietDuration == "" {
+		return value
+	}
+	return value + "\t" + format.FormatDurationIn(duration, config.QuietDuration, config.DurationPrecision)
+}
+
+// WriteQuietResult writes the quiet output of a result: its line as
+// FormatQuietLine formats it, with the value streamed by
+// WriteResultStreamed rather than converted to a string.
+//
+// Parameters:
+//   - out: The output writer.
+//   - result: The calculated Fibonacci number.
+//   - duration: The calculation duration.
+//   - config: Output configuration.
+//
+// Returns:
+//   - error: An error if writing fails.
+func WriteQuietResult(out io.Writer, result *big.Int, duration time.Duration, config OutputConfig) error {
+	if err := WriteResultStreamed(out, result, 0); err != nil {
+		return err
+	}
+	// The line of an empty value is what follows it
+	_, err := fmt.Fprintln(out, FormatQuietLine("", duration, config))
+	return err
+}
+
 // DisplayResultWithConfig displays a result with the given output configuration.
 // This is a unified function that handles all output modes.
 //
@@ -130,14 +334,16 @@ func DisplayQuietResult(out io.Writer, result *big.Int, n uint64, duration time.
 //   - config: Output configuration.
 //
 // Returns:
-//   - error: An error if file output fails.
+//   - error: An error if the quiet output or the file output fails.
 func DisplayResultWithConfig(out io.Writer, result *big.Int, n uint64, duration time.Duration, algo string, config OutputConfig) error {
 	// Handle quiet mode
 	if config.Quiet {
-		DisplayQuietResult(out, result, n, duration)
+		if err := WriteQuietResult(out, result, duration, config); err != nil {
+			return err
+		}
 	} else {
 		// Use standard display
-		DisplayResult(result, n, duration, config.Verbose, true, config.ShowValue, out)
+		displayResult(result, n, duration, config.Verbose, true, config.ShowValue, config.Truncation, config.Indicators, out)
 	}
 
 	// Save to file if requested
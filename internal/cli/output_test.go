@@ -2,12 +2,16 @@ package cli
 
 import (
 	"bytes"
+	"errors"
 	"math/big"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/agbru/fibcalc/internal/audit"
+	"github.com/agbru/fibcalc/internal/format"
 )
 
 func TestWriteResultToFile(t *testing.T) {
@@ -83,6 +87,109 @@ func TestWriteResultToFile(t *testing.T) {
 	}
 }
 
+func TestWriteResultToFileRecordsSeed(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "result.txt")
+
+	if err := WriteResultToFile(big.NewInt(55), 10, time.Millisecond, "fast", OutputConfig{OutputFile: path, Seed: 1234}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	if !strings.Contains(string(content), "# Seed: 1234\n") {
+		t.Errorf("File header should record the seed:\n%s", content)
+	}
+	if digest := "# Result-SHA256: " + audit.HashResult(big.NewInt(55)) + "\n"; !strings.Contains(string(content), digest) {
+		t.Errorf("File header should record the digest:\n%s", content)
+	}
+}
+
+func TestWriteResultToFileWraps(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "result.txt")
+
+	// F(50) = 12586269025
+	cfg := OutputConfig{OutputFile: path, Truncation: Truncation{Wrap: 5}}
+	if err := WriteResultToFile(big.NewInt(12586269025), 50, time.Millisecond, "fast", cfg); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	want := "\nF(50) =\n      +0\n[ 1]  12586\n[ 6]  26902\n[11]  5\n"
+	if !strings.HasSuffix(string(content), want) {
+		t.Errorf("File should end with the wrapped value %q:\n%s", want, content)
+	}
+}
+
+func TestWriteResultToFileStreamsLargeValue(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "result.txt")
+
+	// 3^50000 has 23,857 digits, more than two chunks
+	result := new(big.Int).Exp(big.NewInt(3), big.NewInt(50_000), nil)
+	if err := WriteResultToFile(result, 1, time.Millisecond, "fast", OutputConfig{OutputFile: path}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	want := result.String()
+	if !strings.Contains(string(content), "# Digits: 23857\n") {
+		t.Errorf("File header should record 23857 digits:\n%.300s", content)
+	}
+	if !strings.HasSuffix(string(content), "\nF(1) =\n"+want+"\n") {
+		t.Error("File should end with the full value")
+	}
+}
+
+func TestWriteResultStreamed(t *testing.T) {
+	t.Parallel()
+	ten := big.NewInt(10)
+	values := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(7),
+		big.NewInt(-12586269025),
+		new(big.Int).Exp(ten, big.NewInt(40), nil),
+		new(big.Int).Sub(new(big.Int).Exp(ten, big.NewInt(40), nil), big.NewInt(1)),
+		new(big.Int).Exp(big.NewInt(3), big.NewInt(20_000), nil),
+	}
+	// A value whose middle chunks are zeros
+	sparse := new(big.Int).Exp(ten, big.NewInt(30_000), nil)
+	values = append(values, sparse.Add(sparse, big.NewInt(42)))
+
+	for _, chunkDigits := range []int{0, 1, 3, 7, 64} {
+		for _, v := range values {
+			var buf bytes.Buffer
+			if err := WriteResultStreamed(&buf, v, chunkDigits); err != nil {
+				t.Fatalf("WriteResultStreamed: %v", err)
+			}
+			if got, want := buf.String(), v.String(); got != want {
+				t.Errorf("chunkDigits %d: WriteResultStreamed(%.20s…) = %.20s… (%d digits), want %d digits",
+					chunkDigits, want, got, len(got), len(want))
+			}
+		}
+	}
+}
+
+func TestDecimalDigits(t *testing.T) {
+	t.Parallel()
+	ten := big.NewInt(10)
+	for _, exp := range []int64{0, 1, 2, 15, 16, 19, 20, 100, 1234} {
+		p := new(big.Int).Exp(ten, big.NewInt(exp), nil)
+		for _, v := range []*big.Int{new(big.Int).Sub(p, big.NewInt(1)), p, new(big.Int).Neg(p)} {
+			want := len(new(big.Int).Abs(v).String())
+			if got := DecimalDigits(v); got != want {
+				t.Errorf("DecimalDigits(%s) = %d, want %d", v, got, want)
+			}
+		}
+	}
+}
+
 func TestFormatQuietResult(t *testing.T) {
 	t.Parallel()
 	result := big.NewInt(55)
@@ -106,6 +213,49 @@ func TestFormatQuietResult(t *testing.T) {
 	})
 }
 
+func TestFormatQuietLine(t *testing.T) {
+	t.Parallel()
+	d := 1234567891 * time.Nanosecond
+	tests := []struct {
+		config OutputConfig
+		want   string
+	}{
+		{OutputConfig{}, "55"},
+		{OutputConfig{QuietDuration: format.DurationNanoseconds}, "55\t1234567891"},
+		{OutputConfig{QuietDuration: format.DurationSeconds, DurationPrecision: 2}, "55\t1.23"},
+	}
+	for _, tt := range tests {
+		if got := FormatQuietLine("55", d, tt.config); got != tt.want {
+			t.Errorf("FormatQuietLine(%+v) = %q, want %q", tt.config, got, tt.want)
+		}
+	}
+}
+
+// failingWriter fails every write, as a closed stdout does.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("broken pipe") }
+
+func TestWriteQuietResult(t *testing.T) {
+	t.Parallel()
+	large, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	var buf bytes.Buffer
+	config := OutputConfig{QuietDuration: format.DurationNanoseconds}
+	if err := WriteQuietResult(&buf, large, 1500*time.Nanosecond, config); err != nil {
+		t.Fatalf("WriteQuietResult: %v", err)
+	}
+	if want := large.String() + "\t1500\n"; buf.String() != want {
+		t.Errorf("WriteQuietResult wrote %q, want %q", buf.String(), want)
+	}
+
+	if err := WriteQuietResult(failingWriter{}, large, time.Second, OutputConfig{}); err == nil {
+		t.Error("WriteQuietResult to a failing writer: expected an error")
+	}
+	if err := DisplayResultWithConfig(failingWriter{}, large, 100, time.Second, "fast", OutputConfig{Quiet: true}); err == nil {
+		t.Error("DisplayResultWithConfig to a failing writer: expected an error")
+	}
+}
+
 func TestDisplayQuietResult(t *testing.T) {
 	t.Parallel()
 	result := big.NewInt(55)
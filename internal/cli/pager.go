@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/agbru/fibcalc/internal/format"
+)
+
+const (
+	// pagerLineDigits is the number of digits on each line of a paged
+	// value.
+	pagerLineDigits = 100
+	// rulerGroupDigits is the size of the digit groups of a ruled value.
+	rulerGroupDigits = 10
+)
+
+// pagerCommand returns the command line of the pager: $PAGER, else less or
+// more if found in the PATH. An empty command line means no pager.
+var pagerCommand = func() string {
+	if cmdline := strings.TrimSpace(os.Getenv("PAGER")); cmdline != "" {
+		return cmdline
+	}
+	for _, name := range []string{"less", "more"} {
+		if _, err := exec.LookPath(name); err == nil {
+			return name
+		}
+	}
+	return ""
+}
+
+// pagerTerminal returns the terminal file out writes to, which the pager
+// takes over, or nil if out is not the standard output or error of a
+// terminal.
+var pagerTerminal = func(out io.Writer) *os.File {
+	if !isTerminal(out) {
+		return nil
+	}
+	fd := out.(interface{ Fd() uintptr }).Fd()
+	for _, f := range []*os.File{os.Stdout, os.Stderr} {
+		if f.Fd() == fd {
+			return f
+		}
+	}
+	return nil
+}
+
+// pageValue shows a value in the pager, ruled by writeRuledValue so that
+// the pager's search finds digits and positions alike.
+//
+// Parameters:
+//   - out: The writer the value would be printed to.
+//   - n: The index of the value.
+//   - value: The value, in decimal.
+//   - width: The number of digits per line; 0 uses pagerLineDigits.
+//
+// Returns:
+//   - bool: false, with nothing shown, if out is not a terminal or no pager
+//     can be started.
+func pageValue(out io.Writer, n uint64, value string, width int) bool {
+	tty := pagerTerminal(out)
+	if tty == nil {
+		return false
+	}
+	cmdline := pagerCommand()
+	if cmdline == "" {
+		return false
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "F(%s), %s digits\n\n", format.FormatNumberString(strconv.FormatUint(n, 10)),
+		format.FormatNumberString(strconv.Itoa(len(value))))
+	if width <= 0 {
+		width = pagerLineDigits
+	}
+	if err := writeRuledValue(&text, value, width); err != nil {
+		return false
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", cmdline)
+	} else {
+		cmd = exec.Command("sh", "-c", cmdline)
+	}
+	cmd.Stdin = strings.NewReader(text.String())
+	cmd.Stdout = tty
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		return false
+	}
+	// Quitting the pager early is not an error
+	_ = cmd.Wait()
+	return true
+}
+
+// writeRuledValue writes a value in lines of width digits, each preceded by
+// the position of its first digit (from 1, the most significant), in
+// groups of ten digits under a ruler of their positions within the line.
+//
+// Parameters:
+//   - w: The writer.
+//   - value: The value, in decimal.
+//   - width: The number of digits per line.
+//
+// Returns:
+//   - error: An error if writing fails.
+func writeRuledValue(w io.Writer, value string, width int) error {
+	rw := newRuledWriter(w, len(value), width)
+	if _, err := io.WriteString(rw, value); err != nil {
+		return err
+	}
+	return rw.Close()
+}
+
+// ruledWriter rules the digits written to it like writeRuledValue, so that
+// a value streamed by WriteResultStreamed is ruled without ever holding its
+// decimal string. The number of digits must be known up front, as it sets
+// the width of the positions.
+type ruledWriter struct {
+	bw    *bufio.Writer
+	width int
+	label int
+	// pos is the number of digits written so far.
+	pos int
+}
+
+// newRuledWriter writes the ruler of a value of digits digits to w and
+// returns the writer of its lines.
+//
+// Parameters:
+//   - w: The writer.
+//   - digits: The number of digits of the value.
+//   - width: The number of digits per line.
+//
+// Returns:
+//   - *ruledWriter: The writer of the digits, to be closed once the value
+//     is written.
+func newRuledWriter(w io.Writer, digits, width int) *ruledWriter {
+	rw := &ruledWriter{
+		bw:    bufio.NewWriter(w),
+		width: width,
+		label: len(format.FormatNumberString(strconv.Itoa(digits))),
+	}
+
+	// The ruler: the position within the line of each group
+	ruler := fmt.Sprintf("%*s ", rw.label+2, "")
+	for col := 0; col < min(width, digits); col += rulerGroupDigits {
+		ruler += fmt.Sprintf(" %-*s", rulerGroupDigits, "+"+strconv.Itoa(col))
+	}
+	rw.bw.WriteString(strings.TrimRight(ruler, " ") + "\n")
+	return rw
+}
+
+// Write writes digits of the value, starting a line every width digits and
+// a group every rulerGroupDigits.
+func (rw *ruledWriter) Write(p []byte) (int, error) {
+	written := len(p)
+	for len(p) > 0 {
+		col := rw.pos % rw.width
+		if col == 0 {
+			if rw.pos > 0 {
+				rw.bw.WriteByte('\n')
+			}
+			fmt.Fprintf(rw.bw, "[%*s] ", rw.label, format.FormatNumberString(strconv.Itoa(rw.pos+1)))
+		}
+		if col%rulerGroupDigits == 0 {
+			rw.bw.WriteByte(' ')
+		}
+		// The rest of the group, cut at the end of the line
+		k := min(len(p), rulerGroupDigits-col%rulerGroupDigits, rw.width-col)
+		if _, err := rw.bw.Write(p[:k]); err != nil {
+			return written - len(p), err
+		}
+		rw.pos += k
+		p = p[k:]
+	}
+	return written, nil
+}
+
+// Close ends the last line and flushes the output.
+func (rw *ruledWriter) Close() error {
+	if rw.pos > 0 {
+		rw.bw.WriteByte('\n')
+	}
+	return rw.bw.Flush()
+}
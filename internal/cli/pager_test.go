@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/agbru/fibcalc/internal/ui"
+)
+
+func TestWriteRuledValue(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	if err := writeRuledValue(&buf, strings.Repeat("1234567890", 2)+"12345", 20); err != nil {
+		t.Fatalf("writeRuledValue() error = %v", err)
+	}
+	want := "      +0         +10\n" +
+		"[ 1]  1234567890 1234567890\n" +
+		"[21]  12345\n"
+	if buf.String() != want {
+		t.Errorf("writeRuledValue() =\n%q\nwant\n%q", buf.String(), want)
+	}
+}
+
+// stubPager makes the pager a command writing to a temporary file, which
+// stands for the terminal, and restores the pager on cleanup.
+func stubPager(t *testing.T, cmdline string) *os.File {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	tty, err := os.Create(filepath.Join(t.TempDir(), "tty"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { tty.Close() })
+
+	oldCommand, oldTerminal := pagerCommand, pagerTerminal
+	pagerCommand = func() string { return cmdline }
+	pagerTerminal = func(io.Writer) *os.File { return tty }
+	t.Cleanup(func() { pagerCommand, pagerTerminal = oldCommand, oldTerminal })
+	return tty
+}
+
+func TestDisplayCalculatedValue_Pager(t *testing.T) {
+	ui.InitTheme(true)
+	defer ui.InitTheme(false)
+	tty := stubPager(t, "cat")
+	value, _ := new(big.Int).SetString(strings.Repeat("1234567890", 6), 10)
+
+	// Below the threshold, the value is printed
+	var buf bytes.Buffer
+	displayCalculatedValue(&buf, value, 7, true, Truncation{PageAt: 61})
+	if !strings.Contains(buf.String(), "123,456,789,012") {
+		t.Errorf("value below --pager-at not printed:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	displayCalculatedValue(&buf, value, 7, true, Truncation{PageAt: 60})
+	if !strings.Contains(buf.String(), "F(7) = 60 digits, shown in the pager.") || strings.Contains(buf.String(), "123") {
+		t.Errorf("unexpected output when paged:\n%s", buf.String())
+	}
+	paged, err := os.ReadFile(tty.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(paged), "F(7), 60 digits\n") || !strings.Contains(string(paged), "[ 1]  1234567890") {
+		t.Errorf("unexpected pager input:\n%s", paged)
+	}
+
+	// Truncated values are never paged
+	buf.Reset()
+	displayCalculatedValue(&buf, value, 7, false, Truncation{At: 50, Edges: 5, PageAt: 10})
+	if !strings.Contains(buf.String(), "(truncated)") {
+		t.Errorf("expected a truncated value without --verbose:\n%s", buf.String())
+	}
+}
+
+func TestDisplayCalculatedValue_Wrap(t *testing.T) {
+	ui.InitTheme(true)
+	defer ui.InitTheme(false)
+	value, _ := new(big.Int).SetString(strings.Repeat("1234567890", 3), 10)
+
+	for _, verbose := range []bool{true, false} {
+		var buf bytes.Buffer
+		displayCalculatedValue(&buf, value, 7, verbose, Truncation{At: 100, Wrap: 20})
+		if want := "F(7) =\n      +0         +10\n[ 1]  1234567890 1234567890\n[21]  1234567890\n"; !strings.Contains(buf.String(), want) {
+			t.Errorf("verbose=%v: output does not contain %q:\n%s", verbose, want, buf.String())
+		}
+	}
+
+	// Values that fit on a line are not wrapped
+	var buf bytes.Buffer
+	displayCalculatedValue(&buf, value, 7, true, Truncation{Wrap: 30})
+	if !strings.Contains(buf.String(), "123,456,789,012") {
+		t.Errorf("a value of the wrap width should not be wrapped:\n%s", buf.String())
+	}
+}
+
+func TestPageValue_NoPager(t *testing.T) {
+	stubPager(t, "")
+	if pageValue(&bytes.Buffer{}, 1, "1", 0) {
+		t.Error("pageValue() = true without a pager")
+	}
+	pagerTerminal = func(io.Writer) *os.File { return nil }
+	pagerCommand = func() string { return "cat" }
+	if pageValue(&bytes.Buffer{}, 1, "1", 0) {
+		t.Error("pageValue() = true without a terminal")
+	}
+}
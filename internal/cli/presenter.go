@@ -6,30 +6,45 @@ import (
 	"sync"
 	"time"
 
+	"github.com/agbru/fibcalc/internal/clock"
+	"github.com/agbru/fibcalc/internal/energy"
 	apperrors "github.com/agbru/fibcalc/internal/errors"
 	"github.com/agbru/fibcalc/internal/format"
+	"github.com/agbru/fibcalc/internal/metrics"
 	"github.com/agbru/fibcalc/internal/progress"
 	"github.com/agbru/fibcalc/internal/orchestration"
+	"github.com/agbru/fibcalc/internal/sysmon"
 	"github.com/agbru/fibcalc/internal/ui"
 )
 
 // CLIProgressReporter implements orchestration.ProgressReporter for CLI output.
-// It wraps the DisplayProgress function to provide a spinner and progress bar
+// It wraps the DisplayProgress function to provide a live progress block
 // display during calculations.
-type CLIProgressReporter struct{}
+type CLIProgressReporter struct {
+	// RefreshRate is the refresh period of the display. Zero uses
+	// ProgressRefreshRate.
+	RefreshRate time.Duration
+}
 
 // Verify that CLIProgressReporter implements orchestration.ProgressReporter.
 var _ orchestration.ProgressReporter = CLIProgressReporter{}
 
-// DisplayProgress displays a spinner and progress bar for ongoing calculations.
-func (CLIProgressReporter) DisplayProgress(wg *sync.WaitGroup, progressChan <-chan progress.ProgressUpdate, numCalculators int, out io.Writer) {
-	DisplayProgress(wg, progressChan, numCalculators, out)
+// DisplayProgress displays the progress of ongoing calculations.
+func (r CLIProgressReporter) DisplayProgress(wg *sync.WaitGroup, progressChan <-chan progress.ProgressUpdate, numCalculators int, out io.Writer) {
+	displayProgress(wg, progressChan, numCalculators, out, r.RefreshRate, clock.Real{})
 }
 
 // CLIResultPresenter implements orchestration.ResultPresenter for CLI output.
 // It provides formatted, colorized output for calculation results in the
 // command-line interface.
-type CLIResultPresenter struct{}
+type CLIResultPresenter struct {
+	// Truncation controls how long values are shortened. The zero value
+	// uses the defaults.
+	Truncation Truncation
+	// Indicators are the indicators shown with the details. Nil shows
+	// all of metrics.DefaultRegistry.
+	Indicators []metrics.Indicator
+}
 
 // Verify interface compliance.
 var (
@@ -44,9 +59,40 @@ var (
 func (CLIResultPresenter) PresentComparisonTable(results []orchestration.CalculationResult, out io.Writer) {
 	fmt.Fprintf(out, "\n--- Comparison Summary ---\n")
 
+	// Hardware counter columns are only shown when at least one result has them
+	showCounters := false
+	for _, res := range results {
+		if res.Counters != nil {
+			showCounters = true
+			break
+		}
+	}
+
+	// The energy column is only shown when at least one result has it
+	showEnergy := false
+	for _, res := range results {
+		if res.Energy != nil {
+			showEnergy = true
+			break
+		}
+	}
+
+	// The spill column is only shown when at least one result spilled
+	showSpill := false
+	for _, res := range results {
+		if res.SpillStats != nil {
+			showSpill = true
+			break
+		}
+	}
+
 	// Find the maximum algorithm name width for proper alignment
 	maxNameLen := 9 // "Algorithm" header length
 	maxDurationLen := 8 // "Duration" header length
+	maxMissesLen := 10 // "LLC misses" header length
+	maxBandwidthLen := 6 // "Mem BW" header length
+	maxEnergyLen := 6 // "Energy" header length
+	maxSpillLen := 7 // "Spilled" header length
 	for _, res := range results {
 		if len(res.Name) > maxNameLen {
 			maxNameLen = len(res.Name)
@@ -58,31 +104,115 @@ func (CLIResultPresenter) PresentComparisonTable(results []orchestration.Calcula
 		if len(duration) > maxDurationLen {
 			maxDurationLen = len(duration)
 		}
+		misses, bandwidth := counterColumns(res)
+		maxMissesLen = max(maxMissesLen, len(misses))
+		maxBandwidthLen = max(maxBandwidthLen, len(bandwidth))
+		maxEnergyLen = max(maxEnergyLen, len(energyColumn(res)))
+		maxSpillLen = max(maxSpillLen, len(spillColumn(res)))
 	}
 
 	// Print header with proper padding
-	fmt.Fprintf(out, "%sAlgorithm%s%s   %sDuration%s%s   %sStatus%s\n",
+	fmt.Fprintf(out, "%sAlgorithm%s%s   %sDuration%s%s   ",
 		ui.ColorUnderline(), ui.ColorReset(), padRight("", maxNameLen-9),
-		ui.ColorUnderline(), ui.ColorReset(), padRight("", maxDurationLen-8),
-		ui.ColorUnderline(), ui.ColorReset())
+		ui.ColorUnderline(), ui.ColorReset(), padRight("", maxDurationLen-8))
+	if showCounters {
+		fmt.Fprintf(out, "%sLLC misses%s%s   %sMem BW%s%s   ",
+			ui.ColorUnderline(), ui.ColorReset(), padRight("", maxMissesLen-10),
+			ui.ColorUnderline(), ui.ColorReset(), padRight("", maxBandwidthLen-6))
+	}
+	if showEnergy {
+		fmt.Fprintf(out, "%sEnergy%s%s   ",
+			ui.ColorUnderline(), ui.ColorReset(), padRight("", maxEnergyLen-6))
+	}
+	if showSpill {
+		fmt.Fprintf(out, "%sSpilled%s%s   ",
+			ui.ColorUnderline(), ui.ColorReset(), padRight("", maxSpillLen-7))
+	}
+	fmt.Fprintf(out, "%sStatus%s\n", ui.ColorUnderline(), ui.ColorReset())
 
 	// Print each result row
 	for _, res := range results {
-		var status string
-		if res.Err != nil {
-			status = fmt.Sprintf("%s❌ Failure (%v)%s", ui.ColorRed(), res.Err, ui.ColorReset())
-		} else {
-			status = fmt.Sprintf("%s✅ Success%s", ui.ColorGreen(), ui.ColorReset())
-		}
+		status := resultStatus(res)
 		duration := format.FormatExecutionDuration(res.Duration)
 		if res.Duration == 0 {
 			duration = "< 1µs"
 		}
-		fmt.Fprintf(out, "%s%s%s%s   %s%s%s%s   %s\n",
+		fmt.Fprintf(out, "%s%s%s%s   %s%s%s%s   ",
 			ui.ColorBlue(), res.Name, ui.ColorReset(), padRight("", maxNameLen-len(res.Name)),
-			ui.ColorYellow(), duration, ui.ColorReset(), padRight("", maxDurationLen-len(duration)),
-			status)
+			ui.ColorYellow(), duration, ui.ColorReset(), padRight("", maxDurationLen-len(duration)))
+		if showCounters {
+			misses, bandwidth := counterColumns(res)
+			fmt.Fprintf(out, "%s%s   %s%s   ",
+				misses, padRight("", maxMissesLen-len(misses)),
+				bandwidth, padRight("", maxBandwidthLen-len(bandwidth)))
+		}
+		if showEnergy {
+			e := energyColumn(res)
+			fmt.Fprintf(out, "%s%s   ", e, padRight("", maxEnergyLen-len(e)))
+		}
+		if showSpill {
+			s := spillColumn(res)
+			fmt.Fprintf(out, "%s%s   ", s, padRight("", maxSpillLen-len(s)))
+		}
+		fmt.Fprintf(out, "%s\n", status)
+	}
+}
+
+// kindLabels are the status labels of the failed results by kind.
+var kindLabels = map[apperrors.ErrorKind]string{
+	apperrors.ErrorKindTimeout:  "Timeout",
+	apperrors.ErrorKindCanceled: "Canceled",
+	apperrors.ErrorKindOOM:      "Out of memory",
+	apperrors.ErrorKindInternal: "Failure",
+	apperrors.ErrorKindMismatch: "Mismatch",
+	apperrors.ErrorKindStalled:  "Stalled",
+}
+
+// resultStatus returns the status cell of a result in the comparison
+// table: its error kind with the error, if any.
+func resultStatus(res orchestration.CalculationResult) string {
+	kind := res.ErrorKind()
+	switch {
+	case kind == apperrors.ErrorKindNone:
+		return fmt.Sprintf("%s✅ Success%s", ui.ColorGreen(), ui.ColorReset())
+	case kind == apperrors.ErrorKindCanceled:
+		return fmt.Sprintf("%s⏹ %s%s", ui.ColorYellow(), kindLabels[kind], ui.ColorReset())
+	case res.Err == nil:
+		return fmt.Sprintf("%s❌ %s%s", ui.ColorRed(), kindLabels[kind], ui.ColorReset())
 	}
+	return fmt.Sprintf("%s❌ %s (%v)%s", ui.ColorRed(), kindLabels[kind], res.Err, ui.ColorReset())
+}
+
+// counterColumns returns the LLC-miss and memory-bandwidth cells for a
+// result, or "n/a" when no hardware counters were collected.
+func counterColumns(res orchestration.CalculationResult) (misses, bandwidth string) {
+	if res.Counters == nil {
+		return "n/a", "n/a"
+	}
+	return format.FormatNumberString(fmt.Sprintf("%d", res.Counters.CacheMisses)),
+		format.FormatBytes(uint64(res.Counters.Bandwidth())) + "/s"
+}
+
+// energyColumn returns the energy cell for a result: joules, prefixed with
+// "~" when estimated rather than measured, or "n/a" without a reading.
+func energyColumn(res orchestration.CalculationResult) string {
+	if res.Energy == nil {
+		return "n/a"
+	}
+	prefix := ""
+	if res.Energy.Source == energy.SourceEstimate {
+		prefix = "~"
+	}
+	return fmt.Sprintf("%s%.2f J", prefix, res.Energy.Joules)
+}
+
+// spillColumn returns the spill cell for a result: the size of the
+// transform buffers placed in temporary files, or "-" if none was.
+func spillColumn(res orchestration.CalculationResult) string {
+	if res.SpillStats == nil {
+		return "-"
+	}
+	return format.FormatBytes(uint64(res.SpillStats.Bytes))
 }
 
 // padRight returns a string of spaces with the given length.
@@ -94,9 +224,9 @@ func padRight(s string, length int) string {
 }
 
 // PresentResult displays the final calculation result using the CLI's
-// DisplayResult function.
-func (CLIResultPresenter) PresentResult(result orchestration.CalculationResult, n uint64, verbose, details, showValue bool, out io.Writer) {
-	DisplayResult(result.Result, n, result.Duration, verbose, details, showValue, out)
+// DisplayResult function, truncated according to the presenter's settings.
+func (p CLIResultPresenter) PresentResult(result orchestration.CalculationResult, n uint64, verbose, details, showValue bool, out io.Writer) {
+	displayResult(result.Result, n, result.Duration, verbose, details, showValue, p.Truncation, p.Indicators, out)
 }
 
 // FormatDuration formats a duration for display using the CLI's standard
@@ -110,6 +240,26 @@ func (CLIResultPresenter) HandleError(err error, duration time.Duration, out io.
 	return apperrors.HandleCalculationError(err, duration, out, CLIColorProvider{})
 }
 
+// DisplayResourceUsage shows the resource usage of a run, for --details:
+// CPU time, the peak resident memory of the process and context switches.
+//
+// Parameters:
+//   - u: The usage of the run.
+//   - wall: The wall-clock time of the run, to relate the CPU time to.
+//   - out: The output writer.
+func DisplayResourceUsage(u sysmon.Usage, wall time.Duration, out io.Writer) {
+	fmt.Fprintf(out, "\n%s--- Resource usage ---%s\n", ui.ColorBold(), ui.ColorReset())
+	cpu := fmt.Sprintf("%s user, %s system",
+		format.FormatExecutionDuration(u.UserCPU), format.FormatExecutionDuration(u.SystemCPU))
+	if wall > 0 {
+		cpu += fmt.Sprintf(" (%.1f cores on average)", u.CPU().Seconds()/wall.Seconds())
+	}
+	fmt.Fprintf(out, "%-24s: %s%s%s\n", "CPU time", ui.ColorGreen(), cpu, ui.ColorReset())
+	fmt.Fprintf(out, "%-24s: %s%s%s\n", "Max RSS", ui.ColorCyan(), format.FormatBytes(u.MaxRSS), ui.ColorReset())
+	fmt.Fprintf(out, "%-24s: %s%d voluntary, %d involuntary%s\n", "Context switches",
+		ui.ColorCyan(), u.VoluntaryCtxSwitches, u.InvoluntaryCtxSwitches, ui.ColorReset())
+}
+
 // DisplayMemoryStats shows memory statistics after a calculation.
 func DisplayMemoryStats(heapAlloc, totalAlloc uint64, numGC uint32, pauseTotalNs uint64, out io.Writer) {
 	fmt.Fprintf(out, "\nMemory Stats:\n")
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/bigfft"
+	"github.com/agbru/fibcalc/internal/energy"
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/orchestration"
+	"github.com/agbru/fibcalc/internal/perfevent"
+	"github.com/agbru/fibcalc/internal/sysmon"
+	"github.com/agbru/fibcalc/internal/testutil"
+)
+
+func TestPresentComparisonTableCounters(t *testing.T) {
+	t.Parallel()
+	results := []orchestration.CalculationResult{
+		{Name: "Fast Doubling", Result: big.NewInt(55), Duration: time.Millisecond},
+		{Name: "Matrix", Result: big.NewInt(55), Duration: 2 * time.Millisecond},
+	}
+
+	t.Run("without counters", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		CLIResultPresenter{}.PresentComparisonTable(results, &buf)
+		if strings.Contains(buf.String(), "LLC misses") {
+			t.Errorf("counter columns shown without counters:\n%s", buf.String())
+		}
+	})
+
+	t.Run("with counters", func(t *testing.T) {
+		t.Parallel()
+		withCounters := append([]orchestration.CalculationResult(nil), results...)
+		withCounters[0].Counters = &perfevent.Counts{CacheMisses: 1234567, CacheReferences: 2000000, Duration: time.Second}
+		var buf bytes.Buffer
+		CLIResultPresenter{}.PresentComparisonTable(withCounters, &buf)
+		out := testutil.StripAnsiCodes(buf.String())
+		for _, want := range []string{"LLC misses", "Mem BW", "1,234,567", "75.4 MB/s", "n/a"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("output missing %q:\n%s", want, out)
+			}
+		}
+	})
+}
+
+func TestPresentComparisonTableEnergy(t *testing.T) {
+	t.Parallel()
+	results := []orchestration.CalculationResult{
+		{Name: "Fast Doubling", Result: big.NewInt(55), Energy: &energy.Reading{Joules: 12.345, Source: energy.SourceRAPL}},
+		{Name: "Matrix", Result: big.NewInt(55), Energy: &energy.Reading{Joules: 3.2, Source: energy.SourceEstimate}},
+		{Name: "FFT", Err: errors.New("boom")},
+	}
+	var buf bytes.Buffer
+	CLIResultPresenter{}.PresentComparisonTable(results, &buf)
+	out := testutil.StripAnsiCodes(buf.String())
+	for _, want := range []string{"Energy", "12.35 J", "~3.20 J", "n/a"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestPresentComparisonTableSpill(t *testing.T) {
+	t.Parallel()
+	results := []orchestration.CalculationResult{
+		{Name: "Fast Doubling", Result: big.NewInt(55), SpillStats: &bigfft.SpillStats{Buffers: 12, Bytes: 3 << 30}},
+		{Name: "Matrix", Result: big.NewInt(55)},
+	}
+	var buf bytes.Buffer
+	CLIResultPresenter{}.PresentComparisonTable(results, &buf)
+	out := testutil.StripAnsiCodes(buf.String())
+	for _, want := range []string{"Spilled", "3.0 GB", "-"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	CLIResultPresenter{}.PresentComparisonTable(results[1:], &buf)
+	if strings.Contains(buf.String(), "Spilled") {
+		t.Errorf("spill column shown without spilled buffers:\n%s", buf.String())
+	}
+}
+
+func TestPresentComparisonTableErrorKinds(t *testing.T) {
+	t.Parallel()
+	results := []orchestration.CalculationResult{
+		{Name: "fast", Result: big.NewInt(55)},
+		{Name: "fft", Result: big.NewInt(56), Kind: apperrors.ErrorKindMismatch},
+		{Name: "matrix", Err: context.DeadlineExceeded, Kind: apperrors.ErrorKindTimeout},
+		{Name: "lowmem", Err: errors.New("disk full")},
+	}
+	var buf bytes.Buffer
+	CLIResultPresenter{}.PresentComparisonTable(results, &buf)
+	out := testutil.StripAnsiCodes(buf.String())
+	for _, want := range []string{"✅ Success", "❌ Mismatch\n", "❌ Timeout (context deadline exceeded)", "❌ Failure (disk full)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestDisplayResourceUsage(t *testing.T) {
+	t.Parallel()
+	usage := sysmon.Usage{
+		UserCPU:                3 * time.Second,
+		SystemCPU:              time.Second,
+		MaxRSS:                 50 << 20,
+		VoluntaryCtxSwitches:   12,
+		InvoluntaryCtxSwitches: 3,
+	}
+	var buf bytes.Buffer
+	DisplayResourceUsage(usage, 2*time.Second, &buf)
+	out := testutil.StripAnsiCodes(buf.String())
+	for _, want := range []string{"Resource usage", "3s user, 1s system (2.0 cores on average)", "50.0 MB", "12 voluntary, 3 involuntary"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+// Multi-line progress rendering for the command-line interface.
+
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"golang.org/x/term"
+
+	"github.com/agbru/fibcalc/internal/format"
+	"github.com/agbru/fibcalc/internal/ui"
+)
+
+// progressState is a snapshot of a running calculation, as shown by a
+// progressView.
+type progressState struct {
+	// label names the progress value ("Progress" or "Avg progress").
+	label string
+	// progress is the normalized progress (0.0 to 1.0).
+	progress float64
+	// phase describes what the calculation is doing.
+	phase string
+	// eta is the formatted estimated time remaining.
+	eta string
+	// rate is the progress made per second, as a fraction.
+	rate float64
+	// heapAlloc is the number of bytes currently allocated on the heap.
+	heapAlloc uint64
+}
+
+// progressView renders the state of a running calculation. It decouples
+// DisplayProgress from the terminal it draws on, so that the display can be
+// chosen per output and replaced in tests.
+type progressView interface {
+	// Update redraws the view with the latest state.
+	//
+	// Parameters:
+	//   - s: The current state of the calculation.
+	Update(s progressState)
+	// Finish draws the final state. The view is not updated afterwards.
+	//
+	// Parameters:
+	//   - s: The final state of the calculation.
+	Finish(s progressState)
+}
+
+// newProgressView selects the progress display for out: the multi-line
+// block on a terminal that supports cursor movement, and a single summary
+// line otherwise, so that redirected output is not filled with redraws.
+var newProgressView = func(out io.Writer) progressView {
+	if isTerminal(out) && ui.Console().VT {
+		return newBlockProgress(out)
+	}
+	return &lineProgress{out: out}
+}
+
+// isTerminal reports whether out is an interactive terminal. Wrappers such
+// as ui.NewSafeWriter expose the descriptor of the file they write to.
+func isTerminal(out io.Writer) bool {
+	f, ok := out.(interface{ Fd() uintptr })
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// blockProgress renders the progress as a block of lines that is repainted
+// in place: the bar and percentage, the phase and ETA, then the throughput
+// and heap usage.
+type blockProgress struct {
+	out   io.Writer
+	lines int // lines drawn by the previous repaint
+
+	bar   lipgloss.Style
+	label lipgloss.Style
+	value lipgloss.Style
+	dim   lipgloss.Style
+}
+
+// newBlockProgress creates a block display writing to out, colored with the
+// current theme.
+func newBlockProgress(out io.Writer) *blockProgress {
+	theme := ui.GetCurrentTUITheme()
+	return &blockProgress{
+		out:   out,
+		bar:   lipgloss.NewStyle().Foreground(theme.Accent),
+		label: lipgloss.NewStyle().Foreground(theme.Dim).Width(12),
+		value: lipgloss.NewStyle().Foreground(theme.Text).Bold(true),
+		dim:   lipgloss.NewStyle().Foreground(theme.Dim),
+	}
+}
+
+// Update repaints the block with s.
+func (b *blockProgress) Update(s progressState) {
+	b.repaint(b.render(s))
+}
+
+// Finish repaints the block a last time and leaves it on screen.
+func (b *blockProgress) Finish(s progressState) {
+	b.repaint(b.render(s))
+	b.lines = 0
+}
+
+// repaint moves the cursor back over the previous block and draws block in
+// its place, clearing each line first so shorter values leave no residue.
+func (b *blockProgress) repaint(block string) {
+	var sb strings.Builder
+	if b.lines > 0 {
+		sb.WriteString(ansi.CursorUp(b.lines))
+		sb.WriteString("\r")
+	}
+	lines := strings.Split(block, "\n")
+	for _, line := range lines {
+		sb.WriteString(ansi.EraseEntireLine)
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	b.lines = len(lines)
+	fmt.Fprint(b.out, sb.String())
+}
+
+// render lays out s as the lines of the block.
+func (b *blockProgress) render(s progressState) string {
+	rate := "warming up"
+	if s.rate > 0 {
+		rate = fmt.Sprintf("%.2f %%/s", s.rate*100)
+	}
+	rows := []string{
+		fmt.Sprintf("%s %s  %s",
+			b.bar.Render(format.ProgressBar(s.progress, ProgressBarWidth)),
+			b.value.Render(fmt.Sprintf("%6.2f%%", s.progress*100)),
+			b.dim.Render(s.label)),
+		b.label.Render("Phase") + b.value.Render(s.phase) + "   " +
+			b.dim.Render("ETA ") + b.value.Render(s.eta),
+		b.label.Render("Throughput") + b.value.Render(rate) + "   " +
+			b.dim.Render("Memory ") + b.value.Render(format.FormatBytes(s.heapAlloc)+" heap"),
+	}
+	return strings.Join(rows, "\n")
+}
+
+// lineProgress is the display for outputs that are not terminals. It skips
+// intermediate updates and prints a single line once the calculation ends.
+type lineProgress struct {
+	out io.Writer
+}
+
+// Update is a no-op: redrawing is not possible on a plain stream.
+func (l *lineProgress) Update(progressState) {}
+
+// Finish prints the final progress, bar and ETA on one line.
+func (l *lineProgress) Finish(s progressState) {
+	fmt.Fprintf(l.out, "%s: %6.2f%% [%s] ETA: %s\n",
+		s.label, s.progress*100, format.ProgressBar(s.progress, ProgressBarWidth), s.eta)
+}
+
+// progressPhase describes the phase of the calculation from the progress
+// and the number of calculators that have finished.
+//
+// Parameters:
+//   - progress: The average progress (0.0 to 1.0).
+//   - done: The number of calculators that have finished.
+//   - total: The number of calculators.
+//
+// Returns:
+//   - string: A short description of the phase.
+func progressPhase(progress float64, done, total int) string {
+	var phase string
+	switch {
+	case progress <= 0:
+		phase = "Starting"
+	case done == total:
+		phase = "Finishing"
+	default:
+		phase = "Computing"
+	}
+	if total > 1 {
+		phase += fmt.Sprintf(" (%d/%d calculators done)", done, total)
+	}
+	return phase
+}
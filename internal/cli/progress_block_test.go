@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestBlockProgress_RepaintsInPlace(t *testing.T) {
+	var buf bytes.Buffer
+	b := newBlockProgress(&buf)
+	s := progressState{label: "Progress", progress: 0.25, phase: "Computing", eta: "10s", rate: 0.05, heapAlloc: 2 << 20}
+
+	b.Update(s)
+	first := buf.String()
+	if strings.Contains(first, ansi.CursorUp(3)) {
+		t.Errorf("first repaint should not move the cursor up: %q", first)
+	}
+	if got := strings.Count(first, "\n"); got != 3 {
+		t.Errorf("block has %d lines, want 3", got)
+	}
+
+	buf.Reset()
+	s.progress = 0.5
+	b.Update(s)
+	if !strings.HasPrefix(buf.String(), ansi.CursorUp(3)) {
+		t.Errorf("second repaint should start by moving up over the block: %q", buf.String())
+	}
+
+	plain := ansi.Strip(buf.String())
+	for _, want := range []string{"50.00%", "Phase", "Computing", "ETA", "10s", "Throughput", "5.00 %/s", "Memory", "2.0 MB heap"} {
+		if !strings.Contains(plain, want) {
+			t.Errorf("block missing %q:\n%s", want, plain)
+		}
+	}
+}
+
+func TestBlockProgress_FinishLeavesBlock(t *testing.T) {
+	var buf bytes.Buffer
+	b := newBlockProgress(&buf)
+	s := progressState{label: "Progress", progress: 1, phase: "Done", eta: "< 1s"}
+
+	b.Update(s)
+	b.Finish(s)
+	if b.lines != 0 {
+		t.Errorf("lines = %d after Finish, want 0 so later output is not overwritten", b.lines)
+	}
+	if !strings.Contains(ansi.Strip(buf.String()), "warming up") {
+		t.Errorf("zero rate should be shown as warming up:\n%s", ansi.Strip(buf.String()))
+	}
+}
+
+func TestNewProgressView_NonTerminal(t *testing.T) {
+	if _, ok := newProgressView(&bytes.Buffer{}).(*lineProgress); !ok {
+		t.Error("a buffer should get the single-line display")
+	}
+}
+
+func TestProgressPhase(t *testing.T) {
+	tests := []struct {
+		progress    float64
+		done, total int
+		want        string
+	}{
+		{0, 0, 1, "Starting"},
+		{0.5, 0, 1, "Computing"},
+		{1, 1, 1, "Finishing"},
+		{0.6, 1, 3, "Computing (1/3 calculators done)"},
+		{1, 3, 3, "Finishing (3/3 calculators done)"},
+	}
+	for _, tt := range tests {
+		if got := progressPhase(tt.progress, tt.done, tt.total); got != tt.want {
+			t.Errorf("progressPhase(%v, %d, %d) = %q, want %q", tt.progress, tt.done, tt.total, got, tt.want)
+		}
+	}
+}
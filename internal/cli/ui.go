@@ -1,72 +1,67 @@
 package cli
 
-import (
-	"time"
-
-	"github.com/briandowns/spinner"
-)
+import "github.com/agbru/fibcalc/internal/config"
 
 const (
-	// TruncationLimit is the digit threshold from which a result is truncated
-	// in standard output to avoid cluttering the terminal.
-	TruncationLimit = 100
-	// DisplayEdges specifies the number of digits to display at the beginning
-	// and end of a truncated number.
-	DisplayEdges = 25
+	// TruncationLimit is the default digit threshold from which a result is
+	// truncated in standard output to avoid cluttering the terminal.
+	TruncationLimit = config.DefaultTruncateAt
+	// DisplayEdges specifies the default number of digits to display at the
+	// beginning and end of a truncated number.
+	DisplayEdges = config.DefaultEdgeDigits
 	// HexDisplayEdges specifies the number of hex characters to display at the
 	// beginning and end of a truncated hexadecimal number.
 	HexDisplayEdges = 40
-	// ProgressRefreshRate defines the refresh frequency of the progress bar.
+	// ProgressRefreshRate defines the default refresh frequency of the
+	// progress display (see --progress-refresh).
 	// Optimized to 200ms to reduce updates and improve performance.
-	ProgressRefreshRate = 200 * time.Millisecond
+	ProgressRefreshRate = config.DefaultProgressRefresh
 	// ProgressBarWidth defines the width in characters of the progress bar.
 	ProgressBarWidth = 40
 )
 
-// Spinner is an interface that abstracts the behavior of a terminal spinner.
-// This allows for the decoupling of the `DisplayProgress` function from a
-// specific spinner implementation, facilitating easier testing and maintenance.
-// It defines the essential controls for a spinner: starting, stopping, and
-// updating its status message.
-type Spinner interface {
-	// Start begins the spinner animation.
-	Start()
-	// Stop halts the spinner animation.
-	Stop()
-	// UpdateSuffix sets the text that is displayed after the spinner.
-	//
-	// Parameters:
-	//   - suffix: The text string to display.
-	UpdateSuffix(suffix string)
-}
-
-// realSpinner is a wrapper for the `spinner.Spinner` that implements the
-// `Spinner` interface. This adapter allows the `spinner` library to be used
-// within the application's CLI framework.
-type realSpinner struct {
-	s *spinner.Spinner
-}
-
-// Start begins the spinner animation.
-func (rs *realSpinner) Start() {
-	rs.s.Start()
+// Truncation controls how a long result is shortened, paged or wrapped.
+// The zero value uses TruncationLimit and DisplayEdges, and never pages
+// nor wraps.
+type Truncation struct {
+	// At is the digit count above which a result is truncated. 0 never
+	// truncates.
+	At int
+	// Edges is the number of digits kept at each end of a truncated result.
+	Edges int
+	// PageAt is the digit count from which a full result shown on a
+	// terminal is opened in a pager. 0 never pages.
+	PageAt int
+	// Wrap is the number of digits per line of a full result, on screen
+	// and in files, each line preceded by the position of its first digit.
+	// 0 does not wrap.
+	Wrap int
 }
 
-// Stop halts the spinner animation.
-func (rs *realSpinner) Stop() {
-	rs.s.Stop()
-}
-
-// UpdateSuffix sets the text that is displayed after the spinner.
+// TruncationFromConfig returns the truncation settings of cfg.
 //
 // Parameters:
-//   - suffix: The string to display.
-func (rs *realSpinner) UpdateSuffix(suffix string) {
-	rs.s.Suffix = suffix
+//   - cfg: The application configuration.
+//
+// Returns:
+//   - Truncation: The --truncate-at, --edge-digits, --pager-at and --wrap
+//     settings.
+func TruncationFromConfig(cfg config.AppConfig) Truncation {
+	return Truncation{At: cfg.TruncateAt, Edges: cfg.EdgeDigits, PageAt: cfg.PagerAt, Wrap: cfg.Wrap}
+}
+
+// resolve fills in the defaults of an unset Truncation.
+func (t Truncation) resolve() Truncation {
+	if t == (Truncation{}) {
+		return Truncation{At: TruncationLimit, Edges: DisplayEdges}
+	}
+	if t.Edges <= 0 {
+		t.Edges = DisplayEdges
+	}
+	return t
 }
 
-var newSpinner = func(options ...spinner.Option) Spinner {
-	// Using the same interval as ProgressRefreshRate to synchronize
-	s := spinner.New(spinner.CharSets[11], ProgressRefreshRate, options...)
-	return &realSpinner{s}
+// applies reports whether a result of numDigits digits is truncated.
+func (t Truncation) applies(numDigits int) bool {
+	return t.At > 0 && numDigits > t.At && numDigits > 2*t.Edges
 }
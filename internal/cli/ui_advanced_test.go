@@ -9,23 +9,20 @@ import (
 	"time"
 
 	"github.com/agbru/fibcalc/internal/progress"
-	"github.com/briandowns/spinner"
 )
 
-// MockSpinner duplicated for advanced tests if needed, but since it is in ui_test.go which is in the same package (cli),
-// it should be available when running `go test ./internal/cli`.
-// However, if we run `go test ./internal/cli/ui_advanced_test.go ...`, we need to include ui_test.go or redefine it.
-// Since we want `go test ./internal/cli` to work, we don't redefine it.
+// mockProgressView is defined in ui_test.go, which is in the same package (cli),
+// so it is available when running `go test ./internal/cli`.
 
 // TestDisplayProgress_LoopCoverage ensures the ticker and updates are processed
 func TestDisplayProgress_LoopCoverage(t *testing.T) {
-	// Setup mock spinner
-	originalNewSpinner := newSpinner
-	defer func() { newSpinner = originalNewSpinner }()
+	// Setup mock progress view
+	originalNewProgressView := newProgressView
+	defer func() { newProgressView = originalNewProgressView }()
 
-	mockS := &MockSpinner{}
-	newSpinner = func(options ...spinner.Option) Spinner {
-		return mockS
+	mockV := &mockProgressView{}
+	newProgressView = func(io.Writer) progressView {
+		return mockV
 	}
 
 	var wg sync.WaitGroup
@@ -48,8 +45,11 @@ func TestDisplayProgress_LoopCoverage(t *testing.T) {
 	DisplayProgress(&wg, progressChan, 1, out)
 	wg.Wait()
 
-	if !mockS.started {
-		t.Error("Spinner should have started")
+	if len(mockV.updates) == 0 {
+		t.Error("Progress view should have been updated by the ticker")
+	}
+	if !mockV.finished {
+		t.Error("Progress view should have been finished")
 	}
 }
 
@@ -6,87 +6,112 @@ import (
 	"fmt"
 	"io"
 	"math/big"
+	"runtime"
+	"strconv"
 	"sync"
 	"time"
 
+	"github.com/agbru/fibcalc/internal/audit"
+	"github.com/agbru/fibcalc/internal/clock"
 	"github.com/agbru/fibcalc/internal/format"
 	"github.com/agbru/fibcalc/internal/metrics"
 	"github.com/agbru/fibcalc/internal/orchestration"
 	"github.com/agbru/fibcalc/internal/progress"
 	"github.com/agbru/fibcalc/internal/ui"
-	"github.com/briandowns/spinner"
 )
 
-// DisplayProgress manages the asynchronous display of a spinner and progress bar.
+// DisplayProgress manages the asynchronous display of the calculation progress.
 // It is designed to run in a dedicated goroutine and orchestrates the UI updates
 // for the duration of the calculations.
 //
+// On a terminal, the progress is drawn as a block showing the bar, the phase,
+// the ETA, the throughput and the heap usage, repainted in place. Other
+// outputs receive a single line with the final progress.
+//
 // The function's responsibilities include:
 //   - Receiving progress updates from a channel.
 //   - Aggregating these updates to calculate the average progress.
 //   - Calculating and displaying the estimated time remaining (ETA).
-//   - Periodically refreshing the spinner and progress bar.
+//   - Periodically refreshing the progress display.
 //   - Gracefully shutting down when the progress channel is closed.
 //
 // Parameters:
 //   - wg: A WaitGroup to signal when the display routine is complete.
 //   - progressChan: The channel receiving progress updates.
 //   - numCalculators: The number of calculators contributing to the progress.
-//   - out: The io.Writer to which the progress bar is rendered.
+//   - out: The io.Writer to which the progress is rendered.
 func DisplayProgress(wg *sync.WaitGroup, progressChan <-chan progress.ProgressUpdate, numCalculators int, out io.Writer) {
+	displayProgress(wg, progressChan, numCalculators, out, ProgressRefreshRate, clock.Real{})
+}
+
+// displayProgress implements DisplayProgress, refreshing the display every
+// refresh; zero uses ProgressRefreshRate. Time is read from clk, the wall
+// clock if nil.
+func displayProgress(wg *sync.WaitGroup, progressChan <-chan progress.ProgressUpdate, numCalculators int, out io.Writer, refresh time.Duration, clk clock.Clock) {
 	defer wg.Done()
+	if refresh <= 0 {
+		refresh = ProgressRefreshRate
+	}
+	clk = clock.Or(clk)
 
-	agg := orchestration.NewProgressAggregator(numCalculators)
+	agg := orchestration.NewProgressAggregatorClock(numCalculators, clk)
 	if agg == nil {
 		orchestration.DrainChannel(progressChan)
 		return
 	}
 
-	s := newSpinner(spinner.WithWriter(out))
-	s.Start()
-	spinnerStopped := false
-	defer func() {
-		if !spinnerStopped {
-			s.Stop()
-		}
-	}()
-
+	view := newProgressView(out)
 	label := "Progress"
 	if agg.IsMultiCalculator() {
 		label = "Avg progress"
 	}
 
-	ticker := time.NewTicker(ProgressRefreshRate)
+	start := clk.Now()
+	finished := make(map[int]bool, numCalculators)
+	snapshot := func() progressState {
+		avg := agg.CalculateAverage()
+		var ms runtime.MemStats
+		runtime.ReadMemStats(&ms)
+		s := progressState{
+			label:     label,
+			progress:  avg,
+			phase:     progressPhase(avg, len(finished), numCalculators),
+			heapAlloc: ms.HeapAlloc,
+		}
+		if elapsed := clk.Since(start).Seconds(); elapsed > 0 {
+			s.rate = avg / elapsed
+		}
+		return s
+	}
+
+	ticker := clk.NewTicker(refresh)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case update, ok := <-progressChan:
 			if !ok {
-				// Stop the spinner first to free the line
-				if !spinnerStopped {
-					s.Stop()
-					spinnerStopped = true
-				}
-
 				// Display actual final progress (not hardcoded 100%).
 				// Progress may be less than 100% if calculation was canceled or timed out.
-				finalProgress := agg.CalculateAverage()
-				bar := format.ProgressBar(finalProgress, ProgressBarWidth)
-				etaStr := "< 1s"
-				if finalProgress < 1.0 {
-					etaStr = "N/A (interrupted)"
+				s := snapshot()
+				s.eta = "< 1s"
+				s.phase = "Done"
+				if s.progress < 1.0 {
+					s.eta = "N/A (interrupted)"
+					s.phase = "Interrupted"
 				}
-				fmt.Fprintf(out, "%s: %6.2f%% [%s] ETA: %s\n", label, finalProgress*100, bar, etaStr)
+				view.Finish(s)
 				return
 			}
 			agg.Update(update)
-		case <-ticker.C:
-			avgProgress := agg.CalculateAverage()
-			eta := agg.GetETA()
-			bar := format.ProgressBar(avgProgress, ProgressBarWidth)
-			etaStr := format.FormatETA(eta)
-			s.UpdateSuffix(fmt.Sprintf(" %s: %6.2f%% [%s] ETA: %s", label, avgProgress*100, bar, etaStr))
+			if update.Value >= 1.0 {
+				finished[update.CalculatorIndex] = true
+			}
+		case <-ticker.C():
+			s := snapshot()
+			low, high := agg.GetETARange()
+			s.eta = format.FormatETAWithRange(agg.GetETA(), low, high)
+			view.Update(s)
 		}
 	}
 }
@@ -102,7 +127,8 @@ func displayResultHeader(out io.Writer, bitLen int) {
 }
 
 // displayDetailedAnalysis prints detailed execution metrics including
-// calculation time, number of digits, and scientific notation for large numbers.
+// calculation time, number of digits, scientific notation for large numbers
+// and the digest of the result, to compare results across machines.
 //
 // Parameters:
 //   - out: The io.Writer for the output.
@@ -117,8 +143,7 @@ func displayDetailedAnalysis(out io.Writer, result *big.Int, duration time.Durat
 	}
 	fmt.Fprintf(out, "Calculation time        : %s%s%s\n", ui.ColorGreen(), durationStr, ui.ColorReset())
 
-	resultStr := result.String()
-	numDigits := len(resultStr)
+	numDigits := DecimalDigits(result)
 	fmt.Fprintf(out, "Number of digits      : %s%s%s\n",
 		ui.ColorCyan(), format.FormatNumberString(fmt.Sprintf("%d", numDigits)), ui.ColorReset())
 
@@ -126,47 +151,99 @@ func displayDetailedAnalysis(out io.Writer, result *big.Int, duration time.Durat
 		f := new(big.Float).SetInt(result)
 		fmt.Fprintf(out, "Scientific notation    : %s%.6e%s\n", ui.ColorCyan(), f, ui.ColorReset())
 	}
+	fmt.Fprintf(out, "SHA-256 (big-endian)   : %s%s%s\n", ui.ColorCyan(), audit.HashResult(result), ui.ColorReset())
 }
 
 // displayCalculatedValue prints the Fibonacci value, truncating if necessary.
+// A full value longer than trunc.Wrap digits is wrapped and ruled.
 //
 // Parameters:
 //   - out: The io.Writer for the output.
 //   - result: The calculation result.
 //   - n: The index of the Fibonacci number calculated.
-//   - verbose: If true, prints the full number regardless of size.
-func displayCalculatedValue(out io.Writer, result *big.Int, n uint64, verbose bool) {
-	resultStr := result.String()
-	numDigits := len(resultStr)
+//   - verbose: If true, prints the full number regardless of size, in a
+//     pager on a terminal from trunc.PageAt digits.
+//   - trunc: The truncation settings.
+func displayCalculatedValue(out io.Writer, result *big.Int, n uint64, verbose bool, trunc Truncation) {
+	// The truncated value needs only its edges: the decimal string is
+	// built for the full value alone
+	numDigits := DecimalDigits(result)
 
 	fmt.Fprintf(out, "\n%s--- Calculated value ---%s\n", ui.ColorBold(), ui.ColorReset())
 
 	if verbose {
+		resultStr := result.String()
+		if trunc.PageAt > 0 && numDigits >= trunc.PageAt && pageValue(out, n, resultStr, trunc.Wrap) {
+			fmt.Fprintf(out, "F(%s%d%s) = %s%s%s digits, shown in the pager.\n",
+				ui.ColorMagenta(), n, ui.ColorReset(),
+				ui.ColorCyan(), format.FormatNumberString(strconv.Itoa(numDigits)), ui.ColorReset())
+			return
+		}
+		if trunc.Wrap > 0 && numDigits > trunc.Wrap {
+			displayWrappedValue(out, n, resultStr, trunc.Wrap)
+			return
+		}
 		fmt.Fprintf(out, "F(%s%d%s) =\n%s%s%s\n",
 			ui.ColorMagenta(), n, ui.ColorReset(),
 			ui.ColorGreen(), format.FormatNumberString(resultStr), ui.ColorReset())
 		return
 	}
 
-	if numDigits > TruncationLimit {
+	if trunc = trunc.resolve(); trunc.applies(numDigits) {
+		head, tail := decimalEdges(result, numDigits, trunc.Edges)
 		fmt.Fprintf(out, "F(%s%d%s) (truncated) = %s%s...%s%s\n",
 			ui.ColorMagenta(), n, ui.ColorReset(),
-			ui.ColorGreen(), resultStr[:DisplayEdges], resultStr[numDigits-DisplayEdges:], ui.ColorReset())
+			ui.ColorGreen(), head, tail, ui.ColorReset())
 		fmt.Fprintf(out, "(Tip: use the %s-v%s or %s--verbose%s option to display the full value)\n",
 			ui.ColorYellow(), ui.ColorReset(), ui.ColorYellow(), ui.ColorReset())
 		return
 	}
 
+	resultStr := result.String()
+	if trunc.Wrap > 0 && numDigits > trunc.Wrap {
+		displayWrappedValue(out, n, resultStr, trunc.Wrap)
+		return
+	}
 	fmt.Fprintf(out, "F(%s%d%s) = %s%s%s\n",
 		ui.ColorMagenta(), n, ui.ColorReset(),
 		ui.ColorGreen(), format.FormatNumberString(resultStr), ui.ColorReset())
 }
 
+// decimalEdges returns the first and last k digits of x, of digits
+// digits, by dividing x rather than converting it whole.
+func decimalEdges(x *big.Int, digits, k int) (head, tail string) {
+	ten := big.NewInt(10)
+	low := new(big.Int).Exp(ten, big.NewInt(int64(k)), nil)
+	rest := new(big.Int).Abs(x)
+	tail = fmt.Sprintf("%0*s", k, new(big.Int).Mod(rest, low).Text(10))
+	high := rest.Quo(rest, low.Exp(ten, big.NewInt(int64(digits-k)), nil))
+	head = high.Text(10)
+	if x.Sign() < 0 {
+		head = "-" + head
+	}
+	return head, tail
+}
+
+// displayWrappedValue prints a full value in lines of width digits, each
+// preceded by the position of its first digit (see writeRuledValue).
+//
+// Parameters:
+//   - out: The io.Writer for the output.
+//   - n: The index of the Fibonacci number calculated.
+//   - value: The value, in decimal.
+//   - width: The number of digits per line.
+func displayWrappedValue(out io.Writer, n uint64, value string, width int) {
+	fmt.Fprintf(out, "F(%s%d%s) =\n%s", ui.ColorMagenta(), n, ui.ColorReset(), ui.ColorGreen())
+	_ = writeRuledValue(out, value, width)
+	fmt.Fprint(out, ui.ColorReset())
+}
+
 // DisplayResult formats and prints the final calculation result.
 // It provides different levels of detail based on the verbose and details flags,
 // including metadata like binary size, number of digits, and scientific
 // notation. For very large numbers, it truncates the output unless verbose is
-// true.
+// true, using the default truncation settings. The details show every
+// registered indicator.
 //
 // Parameters:
 //   - result: The calculation result.
@@ -177,47 +254,64 @@ func displayCalculatedValue(out io.Writer, result *big.Int, n uint64, verbose bo
 //   - showValue: If true, displays the calculated value section (disabled by default).
 //   - out: The io.Writer for the output.
 func DisplayResult(result *big.Int, n uint64, duration time.Duration, verbose, details, showValue bool, out io.Writer) {
+	displayResult(result, n, duration, verbose, details, showValue, Truncation{}, nil, out)
+}
+
+// displayResult implements DisplayResult with the given truncation settings
+// and indicators; nil indicators means all of metrics.DefaultRegistry.
+func displayResult(result *big.Int, n uint64, duration time.Duration, verbose, details, showValue bool, trunc Truncation, indicators []metrics.Indicator, out io.Writer) {
 	displayResultHeader(out, result.BitLen())
 
 	if details {
 		displayDetailedAnalysis(out, result, duration)
 		if duration > 0 {
-			displayIndicators(out, metrics.Compute(result, n, duration))
+			if indicators == nil {
+				indicators = metrics.DefaultRegistry.Indicators()
+			}
+			displayIndicators(out, result, n, duration, indicators)
 		}
 	}
 
 	if showValue {
-		displayCalculatedValue(out, result, n, verbose)
+		displayCalculatedValue(out, result, n, verbose, trunc)
 	}
 }
 
 // displayIndicators prints post-calculation indicators of interest.
 // These are computed after the calculation completes, so they have zero
-// impact on the measured execution time.
-func displayIndicators(out io.Writer, ind *metrics.Indicators) {
+// impact on the measured execution time. Nothing is printed without
+// indicators.
+func displayIndicators(out io.Writer, result *big.Int, n uint64, duration time.Duration, indicators []metrics.Indicator) {
+	if len(indicators) == 0 {
+		return
+	}
 	fmt.Fprintf(out, "\n%s--- Indicators of interest ---%s\n", ui.ColorBold(), ui.ColorReset())
+	for _, ind := range indicators {
+		// A count and its rate are highlighted apart
+		if rated, ok := ind.(metrics.RatedIndicator); ok {
+			value, rate := rated.ComputeRated(result, n, duration)
+			fmt.Fprintf(out, "%-24s: %s%s%s  (%s%s%s)\n",
+				ind.Label(), ui.ColorCyan(), value, ui.ColorReset(), ui.ColorCyan(), rate, ui.ColorReset())
+			continue
+		}
+		color := ui.ColorMagenta()
+		if ind.Category() == metrics.CategoryPerf {
+			color = ui.ColorGreen()
+		}
+		fmt.Fprintf(out, "%-24s: %s%s%s\n",
+			ind.Label(), color, ind.Compute(result, n, duration), ui.ColorReset())
+	}
+}
 
-	// Performance
-	fmt.Fprintf(out, "Throughput (bits)       : %s%s%s\n",
-		ui.ColorGreen(), metrics.FormatBitsPerSecond(ind.BitsPerSecond), ui.ColorReset())
-	fmt.Fprintf(out, "Throughput (digits)     : %s%s%s\n",
-		ui.ColorGreen(), metrics.FormatDigitsPerSecond(ind.DigitsPerSecond), ui.ColorReset())
-	fmt.Fprintf(out, "Doubling steps          : %s%d%s  (%s%.2f steps/s%s)\n",
-		ui.ColorCyan(), ind.DoublingSteps, ui.ColorReset(),
-		ui.ColorCyan(), ind.StepsPerSecond, ui.ColorReset())
-
-	// Mathematical
-	fmt.Fprintf(out, "Golden ratio deviation  : %s%.4f%%%s\n",
-		ui.ColorMagenta(), ind.GoldenRatioDeviation, ui.ColorReset())
-	fmt.Fprintf(out, "Digital root            : %s%d%s\n",
-		ui.ColorMagenta(), ind.DigitalRoot, ui.ColorReset())
-	fmt.Fprintf(out, "Last 20 digits          : %s%s%s\n",
-		ui.ColorMagenta(), ind.LastDigits, ui.ColorReset())
-
-	parity := "odd"
-	if ind.IsEven {
-		parity = "even"
-	}
-	fmt.Fprintf(out, "Parity                  : %s%s%s\n",
-		ui.ColorMagenta(), parity, ui.ColorReset())
+// DisplayIndicatorList prints the registered indicators with their
+// category and description, for --indicators list.
+//
+// Parameters:
+//   - out: The output writer.
+//   - indicators: The indicators to list.
+func DisplayIndicatorList(out io.Writer, indicators []metrics.Indicator) {
+	for _, ind := range indicators {
+		fmt.Fprintf(out, "%s%-18s%s %-5s %s\n",
+			ui.ColorCyan(), ind.Name(), ui.ColorReset(), ind.Category(), ind.Label())
+	}
 }
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"math/big"
 	"strings"
@@ -9,28 +10,33 @@ import (
 	"testing"
 	"time"
 
+	"github.com/agbru/fibcalc/internal/clock"
+	"github.com/agbru/fibcalc/internal/fibtest"
+	"github.com/agbru/fibcalc/internal/format"
+	"github.com/agbru/fibcalc/internal/metrics"
 	"github.com/agbru/fibcalc/internal/progress"
 	"github.com/agbru/fibcalc/internal/ui"
-	"github.com/briandowns/spinner"
 )
 
-// MockSpinner for testing
-type MockSpinner struct {
-	started bool
-	stopped bool
-	suffix  string
+// mockProgressView records the states it is given.
+type mockProgressView struct {
+	mu       sync.Mutex
+	updates  []progressState
+	finished bool
+	final    progressState
 }
 
-func (m *MockSpinner) Start() {
-	m.started = true
+func (m *mockProgressView) Update(s progressState) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.updates = append(m.updates, s)
 }
 
-func (m *MockSpinner) Stop() {
-	m.stopped = true
-}
-
-func (m *MockSpinner) UpdateSuffix(suffix string) {
-	m.suffix = suffix
+func (m *mockProgressView) Finish(s progressState) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.finished = true
+	m.final = s
 }
 
 func TestDisplayResult(t *testing.T) {
@@ -103,15 +109,30 @@ func TestDisplayResult(t *testing.T) {
 	}
 }
 
-func TestRealSpinner(t *testing.T) {
-	t.Parallel()
-	s := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
-	rs := &realSpinner{s}
+func TestDisplayResultIndicators(t *testing.T) {
+	ui.InitTheme(false)
+	perf, err := metrics.DefaultRegistry.Select("perf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	displayResult(big.NewInt(55), 10, time.Millisecond, false, true, false, Truncation{}, perf, &buf)
+	out := buf.String()
+	if !strings.Contains(out, "Throughput (bits)") || !strings.Contains(out, "Doubling steps") {
+		t.Errorf("selected indicators missing:\n%s", out)
+	}
+	if strings.Contains(out, "Digital root") {
+		t.Errorf("unselected indicator shown:\n%s", out)
+	}
 
-	// Just verify these methods don't panic
-	rs.Start()
-	rs.UpdateSuffix(" test")
-	rs.Stop()
+	buf.Reset()
+	DisplayResult(big.NewInt(55), 10, time.Millisecond, false, true, false, &buf)
+	for _, ind := range metrics.DefaultRegistry.Indicators() {
+		if !strings.Contains(buf.String(), ind.Label()) {
+			t.Errorf("DisplayResult() missing indicator %q:\n%s", ind.Label(), buf.String())
+		}
+	}
 }
 
 func TestColors(t *testing.T) {
@@ -131,17 +152,12 @@ func TestColors(t *testing.T) {
 }
 
 func TestDisplayProgress(t *testing.T) {
-	// Override newSpinner to use mock
-	// Note: We can't easily override newSpinner since it's a var but local to the package?
-	// Ah, it IS a var in ui.go: var newSpinner = func...
-	// So we can override it!
+	originalNewProgressView := newProgressView
+	defer func() { newProgressView = originalNewProgressView }()
 
-	originalNewSpinner := newSpinner
-	defer func() { newSpinner = originalNewSpinner }()
-
-	mockS := &MockSpinner{}
-	newSpinner = func(options ...spinner.Option) Spinner {
-		return mockS
+	mockV := &mockProgressView{}
+	newProgressView = func(io.Writer) progressView {
+		return mockV
 	}
 
 	var wg sync.WaitGroup
@@ -160,11 +176,128 @@ func TestDisplayProgress(t *testing.T) {
 	DisplayProgress(&wg, progressChan, 1, out)
 	wg.Wait()
 
-	if !mockS.started {
-		t.Error("Spinner should have started")
+	if !mockV.finished {
+		t.Fatal("Progress view should have been finished")
+	}
+	if mockV.final.phase != "Interrupted" {
+		t.Errorf("final phase = %q, want %q", mockV.final.phase, "Interrupted")
+	}
+	if mockV.final.eta != "N/A (interrupted)" {
+		t.Errorf("final ETA = %q, want %q", mockV.final.eta, "N/A (interrupted)")
+	}
+}
+
+func TestDisplayProgress_NonTerminalPrintsFinalLine(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	progressChan := make(chan progress.ProgressUpdate, 1)
+	progressChan <- progress.ProgressUpdate{CalculatorIndex: 0, Value: 1.0}
+	close(progressChan)
+
+	var buf bytes.Buffer
+	DisplayProgress(&wg, progressChan, 1, &buf)
+	wg.Wait()
+
+	want := "Progress: 100.00% ["
+	if !strings.HasPrefix(buf.String(), want) || !strings.HasSuffix(buf.String(), "ETA: < 1s\n") {
+		t.Errorf("output = %q, want a single final line starting with %q", buf.String(), want)
+	}
+	if strings.Contains(buf.String(), "\x1b[") {
+		t.Errorf("output to a non-terminal should not contain escape sequences: %q", buf.String())
+	}
+}
+
+// TestDisplayProgress_ReplayFixture feeds a captured comparison run to the
+// reporter and checks that the view follows it to completion.
+func TestDisplayProgress_ReplayFixture(t *testing.T) {
+	originalNewProgressView := newProgressView
+	defer func() { newProgressView = originalNewProgressView }()
+	mockV := &mockProgressView{}
+	newProgressView = func(io.Writer) progressView {
+		return mockV
+	}
+
+	fixture, err := fibtest.EmbeddedFixture("compare-20m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	progressChan := make(chan progress.ProgressUpdate)
+	go func() {
+		defer close(progressChan)
+		_ = fixture.Replay(context.Background(), progressChan, 300*time.Millisecond)
+	}()
+	DisplayProgress(&wg, progressChan, len(fixture.Calculators), io.Discard)
+	wg.Wait()
+
+	mockV.mu.Lock()
+	defer mockV.mu.Unlock()
+	if !mockV.finished || mockV.final.progress != 1 {
+		t.Fatalf("final state = %+v, want finished at 100%%", mockV.final)
+	}
+	if mockV.final.label != "Avg progress" {
+		t.Errorf("label = %q, want %q for several calculators", mockV.final.label, "Avg progress")
+	}
+	last := 0.0
+	for _, s := range mockV.updates {
+		if s.progress < last {
+			t.Fatalf("average progress went back from %v to %v", last, s.progress)
+		}
+		last = s.progress
+	}
+}
+
+// TestDisplayProgress_ETAWarmup checks that the ticker of the CLI shows no
+// ETA during the warm-up, although a rate is known after 100ms.
+func TestDisplayProgress_ETAWarmup(t *testing.T) {
+	originalNewProgressView := newProgressView
+	defer func() { newProgressView = originalNewProgressView }()
+	mockV := &mockProgressView{}
+	newProgressView = func(io.Writer) progressView {
+		return mockV
+	}
+
+	const refresh = 200 * time.Millisecond
+	clk := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	var wg sync.WaitGroup
+	wg.Add(1)
+	progressChan := make(chan progress.ProgressUpdate)
+	go displayProgress(&wg, progressChan, 1, io.Discard, refresh, clk)
+	for clk.Pending() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	for step := 1; step <= 6; step++ {
+		progressChan <- progress.ProgressUpdate{Value: float64(step) / 10}
+		clk.Advance(refresh)
+		// Wait for the tick to be displayed
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			mockV.mu.Lock()
+			n := len(mockV.updates)
+			mockV.mu.Unlock()
+			if n >= step {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("step %d: no display update", step)
+			}
+			time.Sleep(time.Millisecond)
+		}
 	}
-	if !mockS.stopped {
-		t.Error("Spinner should have stopped")
+	close(progressChan)
+	wg.Wait()
+
+	for i, s := range mockV.updates {
+		elapsed := time.Duration(i+1) * refresh
+		warmingUp := s.eta == format.FormatETA(0)
+		if elapsed < format.ETAWarmupDuration && !warmingUp {
+			t.Errorf("at %v: ETA %q shown during the warm-up", elapsed, s.eta)
+		}
+		if elapsed >= format.ETAWarmupDuration && warmingUp {
+			t.Errorf("at %v: no ETA after the warm-up", elapsed)
+		}
 	}
 }
 
@@ -178,3 +311,31 @@ func TestDisplayProgress_ZeroCalculators(t *testing.T) {
 	wg.Wait()
 	// Should return immediately, coverage check
 }
+
+func TestDisplayCalculatedValue_Truncation(t *testing.T) {
+	ui.InitTheme(true)
+	defer ui.InitTheme(false)
+
+	// 60-digit value: 123456789012...
+	value, _ := new(big.Int).SetString(strings.Repeat("1234567890", 6), 10)
+
+	tests := []struct {
+		name  string
+		trunc Truncation
+		want  string
+	}{
+		{"default keeps short values", Truncation{}, "F(1) = 123,456,789,012"},
+		{"custom threshold and edges", Truncation{At: 50, Edges: 5}, "F(1) (truncated) = 12345...67890"},
+		{"zero edges use the default", Truncation{At: 50}, "F(1) (truncated) = " + strings.Repeat("1234567890", 2) + "12345..."},
+		{"zero threshold never truncates", Truncation{At: 0, Edges: 5}, "F(1) = 123,456,789,012"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			displayCalculatedValue(&buf, value, 1, false, tt.trunc)
+			if !strings.Contains(buf.String(), tt.want) {
+				t.Errorf("output does not contain %q:\n%s", tt.want, buf.String())
+			}
+		})
+	}
+}
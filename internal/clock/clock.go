@@ -0,0 +1,66 @@
+// Package clock abstracts the wall clock so that time-dependent code
+// (progress ETA, adaptive reporting, timing middleware, TUI ticks) can be
+// driven deterministically in tests.
+//
+// Production code uses Real, which delegates to the time package. Tests use
+// a Fake, whose time only moves when Advance is called.
+package clock
+
+import "time"
+
+// Clock is the source of time for timing-sensitive code.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// Since returns the time elapsed since t.
+	Since(t time.Time) time.Duration
+	// After returns a channel that receives the current time once d has
+	// elapsed.
+	After(d time.Duration) <-chan time.Time
+	// NewTicker returns a ticker that sends the current time every d.
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker delivers ticks at regular intervals, like time.Ticker.
+type Ticker interface {
+	// C returns the channel on which the ticks are delivered.
+	C() <-chan time.Time
+	// Stop turns off the ticker. No more ticks are sent after Stop returns.
+	Stop()
+}
+
+// Real is the Clock backed by the time package.
+type Real struct{}
+
+// Now returns time.Now().
+func (Real) Now() time.Time { return time.Now() }
+
+// Since returns time.Since(t).
+func (Real) Since(t time.Time) time.Duration { return time.Since(t) }
+
+// After returns time.After(d).
+func (Real) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// NewTicker returns a Ticker wrapping time.NewTicker(d).
+func (Real) NewTicker(d time.Duration) Ticker { return realTicker{time.NewTicker(d)} }
+
+// realTicker adapts *time.Ticker to the Ticker interface.
+type realTicker struct{ t *time.Ticker }
+
+func (r realTicker) C() <-chan time.Time { return r.t.C }
+func (r realTicker) Stop()               { r.t.Stop() }
+
+// Or returns c, or Real if c is nil. It lets optional Clock fields and
+// parameters default to the wall clock.
+//
+// Parameters:
+//   - c: The clock to use, possibly nil.
+//
+// Returns:
+//   - Clock: c, or Real{} when c is nil.
+func Or(c Clock) Clock {
+	if c == nil {
+		return Real{}
+	}
+	return c
+}
@@ -0,0 +1,122 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+var epoch = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestOr(t *testing.T) {
+	t.Parallel()
+	if _, ok := Or(nil).(Real); !ok {
+		t.Errorf("Or(nil) = %T, want Real", Or(nil))
+	}
+	f := NewFake(epoch)
+	if Or(f) != Clock(f) {
+		t.Error("Or(f) should return f")
+	}
+}
+
+func TestFakeNowSince(t *testing.T) {
+	t.Parallel()
+	f := NewFake(epoch)
+	if !f.Now().Equal(epoch) {
+		t.Fatalf("Now() = %v, want %v", f.Now(), epoch)
+	}
+	f.Advance(1500 * time.Millisecond)
+	if got := f.Since(epoch); got != 1500*time.Millisecond {
+		t.Errorf("Since() = %v, want 1.5s", got)
+	}
+	f.Advance(-time.Second)
+	if got := f.Since(epoch); got != 1500*time.Millisecond {
+		t.Errorf("negative Advance moved the clock: Since() = %v", got)
+	}
+}
+
+func TestFakeAfter(t *testing.T) {
+	t.Parallel()
+	f := NewFake(epoch)
+	ch := f.After(time.Second)
+	f.Advance(999 * time.Millisecond)
+	select {
+	case <-ch:
+		t.Fatal("After fired early")
+	default:
+	}
+	f.Advance(time.Millisecond)
+	select {
+	case at := <-ch:
+		if !at.Equal(epoch.Add(time.Second)) {
+			t.Errorf("After delivered %v, want %v", at, epoch.Add(time.Second))
+		}
+	default:
+		t.Fatal("After did not fire")
+	}
+	if f.Pending() != 0 {
+		t.Errorf("Pending() = %d after firing, want 0", f.Pending())
+	}
+
+	select {
+	case <-f.After(0):
+	default:
+		t.Error("After(0) should fire immediately")
+	}
+}
+
+func TestFakeTicker(t *testing.T) {
+	t.Parallel()
+	f := NewFake(epoch)
+	tk := f.NewTicker(100 * time.Millisecond)
+
+	var ticks []time.Duration
+	for range 3 {
+		f.Advance(100 * time.Millisecond)
+		select {
+		case at := <-tk.C():
+			ticks = append(ticks, at.Sub(epoch))
+		default:
+			t.Fatal("ticker did not fire")
+		}
+	}
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond}
+	for i := range want {
+		if ticks[i] != want[i] {
+			t.Errorf("tick %d at %v, want %v", i, ticks[i], want[i])
+		}
+	}
+
+	// A slow receiver drops ticks instead of blocking Advance.
+	f.Advance(time.Second)
+	<-tk.C()
+	select {
+	case <-tk.C():
+		t.Error("ticker buffered more than one tick")
+	default:
+	}
+
+	tk.Stop()
+	f.Advance(time.Second)
+	select {
+	case <-tk.C():
+		t.Error("ticker fired after Stop")
+	default:
+	}
+}
+
+func TestFakeFiresInTimeOrder(t *testing.T) {
+	t.Parallel()
+	f := NewFake(epoch)
+	late := f.After(300 * time.Millisecond)
+	early := f.After(100 * time.Millisecond)
+	f.Advance(time.Second)
+	if got := (<-early).Sub(epoch); got != 100*time.Millisecond {
+		t.Errorf("early timer fired at %v", got)
+	}
+	if got := (<-late).Sub(epoch); got != 300*time.Millisecond {
+		t.Errorf("late timer fired at %v", got)
+	}
+	if !f.Now().Equal(epoch.Add(time.Second)) {
+		t.Errorf("Now() = %v after Advance", f.Now())
+	}
+}
@@ -0,0 +1,152 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Fake is a Clock whose time only moves when Advance is called. Timers and
+// tickers created from it fire during Advance, in time order, so tests of
+// timing-dependent code do not depend on the scheduler or on sleeps.
+//
+// Channels returned by After and tickers are buffered with capacity one and
+// written without blocking: like time.Ticker, a slow receiver drops ticks.
+// Fake is safe for concurrent use.
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+// fakeWaiter is a pending After timer (period 0) or an active ticker.
+type fakeWaiter struct {
+	at     time.Time
+	period time.Duration
+	ch     chan time.Time
+}
+
+// NewFake returns a Fake clock set to start.
+//
+// Parameters:
+//   - start: The initial time of the clock.
+//
+// Returns:
+//   - *Fake: The fake clock.
+func NewFake(start time.Time) *Fake {
+	return &Fake{now: start}
+}
+
+// Now returns the fake current time.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Since returns the fake time elapsed since t.
+func (f *Fake) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+// After returns a channel that receives the fake time once the clock has
+// been advanced by at least d. A non-positive d fires immediately.
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w := &fakeWaiter{at: f.now.Add(d), ch: make(chan time.Time, 1)}
+	if d <= 0 {
+		w.ch <- f.now
+		return w.ch
+	}
+	f.waiters = append(f.waiters, w)
+	return w.ch
+}
+
+// NewTicker returns a ticker firing every d of fake time. It panics if d is
+// not positive, like time.NewTicker.
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w := &fakeWaiter{at: f.now.Add(d), period: d, ch: make(chan time.Time, 1)}
+	f.waiters = append(f.waiters, w)
+	return &fakeTicker{clock: f, w: w}
+}
+
+// Advance moves the clock forward by d, firing every timer and ticker that
+// falls due on the way, in time order.
+//
+// Parameters:
+//   - d: The duration to move forward; non-positive values are ignored.
+func (f *Fake) Advance(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	end := f.now.Add(d)
+	for {
+		w := f.nextDueLocked(end)
+		if w == nil {
+			break
+		}
+		f.now = w.at
+		select {
+		case w.ch <- w.at:
+		default:
+		}
+		if w.period > 0 {
+			w.at = w.at.Add(w.period)
+		} else {
+			f.removeLocked(w)
+		}
+	}
+	f.now = end
+}
+
+// Pending returns the number of timers and tickers waiting to fire. Tests
+// use it to wait until the code under test has armed its timer.
+func (f *Fake) Pending() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.waiters)
+}
+
+// nextDueLocked returns the earliest waiter due at or before end, or nil.
+// The caller must hold f.mu.
+func (f *Fake) nextDueLocked(end time.Time) *fakeWaiter {
+	sort.SliceStable(f.waiters, func(i, j int) bool {
+		return f.waiters[i].at.Before(f.waiters[j].at)
+	})
+	if len(f.waiters) == 0 || f.waiters[0].at.After(end) {
+		return nil
+	}
+	return f.waiters[0]
+}
+
+// removeLocked drops w from the waiters. The caller must hold f.mu.
+func (f *Fake) removeLocked(w *fakeWaiter) {
+	for i, x := range f.waiters {
+		if x == w {
+			f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+// fakeTicker is a Ticker driven by a Fake clock.
+type fakeTicker struct {
+	clock *Fake
+	w     *fakeWaiter
+}
+
+func (t *fakeTicker) C() <-chan time.Time { return t.w.ch }
+
+func (t *fakeTicker) Stop() {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	t.clock.removeLocked(t.w)
+}
@@ -5,10 +5,20 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"math"
+	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/agbru/fibcalc/internal/energy"
 	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/fibonacci/memory"
+	"github.com/agbru/fibcalc/internal/format"
+	"github.com/agbru/fibcalc/internal/metrics"
+	"github.com/agbru/fibcalc/internal/orchestration"
+	"github.com/agbru/fibcalc/internal/priority"
+	"github.com/agbru/fibcalc/internal/progress"
+	"github.com/agbru/fibcalc/internal/push"
 )
 
 const (
@@ -27,22 +37,102 @@ const (
 	DefaultTimeout = 5 * time.Minute
 	// DefaultAlgo is the default algorithm selection.
 	DefaultAlgo = "all"
+	// AutoAlgo selects the expected-fastest algorithm for the requested n.
+	AutoAlgo = "auto"
+	// FallbackAuto tries the other algorithms by increasing estimated cost
+	// for the requested n.
+	FallbackAuto = "auto"
+	// DefaultCompareMode is the default scheduling of multiple algorithms.
+	DefaultCompareMode = "parallel"
+	// DefaultTruncateAt is the digit count above which a displayed result
+	// is truncated.
+	DefaultTruncateAt = 100
+	// DefaultEdgeDigits is the number of digits shown at each end of a
+	// truncated result.
+	DefaultEdgeDigits = 25
+	// DefaultPagerAt is the digit count from which a full value shown on a
+	// terminal is opened in a pager.
+	DefaultPagerAt = 100_000
+	// MaxExplainN is the largest index --explain traces, beyond which the
+	// values no longer fit on a line.
+	MaxExplainN = 100
+	// DefaultSessionPool is the budget of the calculation states the TUI
+	// keeps across restarts.
+	DefaultSessionPool = "512M"
+	// DefaultProgressRefresh is the refresh period of the CLI progress
+	// display.
+	DefaultProgressRefresh = 200 * time.Millisecond
+	// DefaultTUIRefresh is the refresh and sampling period of the TUI
+	// dashboard.
+	DefaultTUIRefresh = 500 * time.Millisecond
+	// SoftRealtimeTarget is the longest the display should be held up by
+	// the calculation in --soft-realtime mode, reported against the GC
+	// pauses and the lateness of the TUI refreshes.
+	SoftRealtimeTarget = 50 * time.Millisecond
+	// DefaultTUIIdle is the inactivity after which the TUI shows its idle
+	// screen.
+	DefaultTUIIdle = 10 * time.Minute
+	// MinRefresh is the shortest refresh period accepted, so that a typo
+	// does not keep a core busy redrawing.
+	MinRefresh = 10 * time.Millisecond
 )
 
+// compareModes lists the values accepted by --compare-mode: the modes of
+// orchestration.CompareModes, so that the two lists cannot drift apart.
+var compareModes = compareModeNames()
+
+// compareModeNames returns the names of orchestration.CompareModes.
+func compareModeNames() []string {
+	names := make([]string, len(orchestration.CompareModes))
+	for i, mode := range orchestration.CompareModes {
+		names[i] = string(mode)
+	}
+	return names
+}
+
+// durationFormats lists the values accepted by --duration-format.
+var durationFormats = []string{"auto", "compact", "verbose"}
+
+// durationLocales lists the values accepted by --duration-locale.
+var durationLocales = []string{"en", "fr"}
+
+// quietDurationUnits lists the values accepted by --quiet-duration.
+var quietDurationUnits = []string{"ns", "ms", "s"}
+
+// failModes lists the values accepted by the hidden --fail-mode flag.
+var failModes = []string{"timeout", "mismatch", "panic", "oom"}
+
 // AppConfig aggregates the application's configuration parameters, parsed from
 // command-line flags. It encapsulates all settings that control the execution,
 // from the Fibonacci index to calculate, to performance-tuning parameters.
 type AppConfig struct {
 	// N is the index of the Fibonacci number to be calculated.
 	N uint64
+	// NSeries, if set, replaces N by a series of indexes, computed one
+	// after the other, such as "10^6..10^7 step x2" (see ParseNSeries).
+	NSeries string
 	// Verbose, if true, instructs the application to display the full calculated number.
 	Verbose bool
 	// Details, if true, provides a detailed report including performance metrics.
 	Details bool
 	// Timeout sets the maximum duration for the calculation.
 	Timeout time.Duration
+	// TimeoutFactor, when positive, replaces Timeout with this multiple of
+	// the predicted duration of the calculation.
+	TimeoutFactor float64
+	// StallFactor, when positive, starts a watchdog that reports a
+	// calculation making no progress for this many times the expected
+	// time until its next progress report.
+	StallFactor float64
+	// StallAbort, if true, makes the watchdog abort a stalled calculation,
+	// which exits with apperrors.ExitErrorStalled.
+	StallAbort bool
 	// Algo specifies the algorithm to use ("all", "fast", "matrix", etc.).
 	Algo string
+	// Fallback, if set, retries a single algorithm that failed, other than
+	// by timeout or cancellation, with the next one: a comma-separated
+	// list of algorithms tried in order, or FallbackAuto.
+	Fallback string
 	// Threshold determines the bit size at which multiplications are parallelized.
 	Threshold int
 	// FFTThreshold is the bit size threshold for using FFT-based multiplication.
@@ -59,11 +149,42 @@ type AppConfig struct {
 	// If set, the application will load/save calibration results from/to this file.
 	// If empty, uses the default path (~/.fibcalc_calibration.json).
 	CalibrationProfile string
+	// ProfileReadOnly, if true, uses the calibration profile without ever
+	// writing it, for profiles shared between users or machines.
+	ProfileReadOnly bool
+	// Watch, if true, runs the calculation again each time the calibration
+	// profile changes, with its new thresholds, and compares the timings
+	// with the previous run.
+	Watch bool
 	// OutputFile, if specified, saves the result to this file path.
 	OutputFile string
+	// SignKey, if set, is the path of a PEM ed25519 private key. Result
+	// files and saved reports are then signed with it (see the provenance
+	// package), for `fibcalc verify-signature`.
+	SignKey string
 	// Quiet mode - minimal output for scripting purposes.
 	// Suppresses progress bars, banners, and informational messages.
 	Quiet bool
+	// Machine, if true, writes only a JSON result document to stdout and
+	// everything meant for people (banners, progress, results table,
+	// warnings) to stderr, so that stdout can be piped to a parser.
+	Machine bool
+	// GHASummary, if true, appends a Markdown table of the results to the
+	// GitHub Actions job summary, the file named by GITHUB_STEP_SUMMARY.
+	// It does nothing outside GitHub Actions.
+	GHASummary bool
+	// ExecOnComplete, if set, is a shell command run once the calculation
+	// is over, with FIB_N, FIB_DURATION_MS, FIB_DIGITS, FIB_OUTPUT_FILE
+	// and FIB_EXIT describing the run in its environment. It is set by
+	// the flag only: no environment variable runs a command.
+	ExecOnComplete string
+	// Explain, if true, prints each step of fast doubling (and of matrix
+	// exponentiation with --algo matrix or all) with the identities used
+	// and the intermediate values, for n <= MaxExplainN.
+	Explain bool
+	// OEIS, if true, checks the result against the OEIS b-file of A000045,
+	// downloaded on first use and cached, when it lists F(n).
+	OEIS bool
 	// Completion, if set, generates shell completion script for the specified shell.
 	// Valid values are: "bash", "zsh", "fish", "powershell".
 	Completion string
@@ -71,6 +192,9 @@ type AppConfig struct {
 	ShowValue bool
 	// TUI, if true, launches the interactive TUI dashboard instead of CLI mode.
 	TUI bool
+	// LogFile, if set with TUI, is a file that receives the plain-text
+	// configuration, progress and results the CLI would have printed.
+	LogFile string
 	// LastDigits, if > 0, computes only the last K decimal digits of F(N).
 	// Uses O(K) memory via modular arithmetic.
 	LastDigits int
@@ -78,13 +202,182 @@ type AppConfig struct {
 	// Accepts human-readable formats like "8G", "512M", "1024K".
 	// The application warns and exits if the estimated memory exceeds this limit.
 	MemoryLimit string
-	// GCControl sets the GC control mode ("auto", "aggressive", "disabled").
+	// SpillThreshold, if set, is the size (e.g., "512M") above which the
+	// FFT transform buffers of a multiplication are placed in
+	// memory-mapped temporary files instead of the heap (see bigfft.Spill).
+	SpillThreshold string
+	// SpillDir is the directory of the spill files; the system temporary
+	// directory if empty.
+	SpillDir string
+	// SessionPool is the size (e.g., "512M") of the calculation states the
+	// TUI keeps across restarts, so that a restart reuses their buffers
+	// (see fibonacci.StatePool); "0" disables it.
+	SessionPool string
+	// MemoryPressure is the fraction of the runtime's soft memory limit
+	// (GOMEMLIMIT, or the one set while the GC is disabled) from which the
+	// transform caches are dropped and disabled with a warning, rather than
+	// letting the GC run back to back (see memory.PressureMonitor); 0
+	// disables the monitor.
+	MemoryPressure float64
+	// GCControl sets the GC control mode ("auto", "aggressive", "disabled",
+	// "realtime").
 	GCControl string
+	// SoftRealtime bounds GC pauses and the compute chunks of the
+	// calculation so that the progress display and the TUI keep refreshing
+	// smoothly, for demos, at a small cost in throughput (see
+	// fibonacci.Options.SoftRealtime).
+	SoftRealtime bool
 	// MaxGoroutines limits the number of goroutines for parallel multiplication.
 	// A value of 0 means automatic (e.g. NumCPU * 2).
 	MaxGoroutines int
 	// Force bypasses safety limits like the maximum value of N.
 	Force bool
+	// CompareMode controls how algorithms are scheduled when several run
+	// ("parallel", "sequential", "staggered"). Sequential gives each
+	// algorithm the whole machine for fair timings.
+	CompareMode string
+	// AuditLog, if set, is the path of an append-only JSON Lines file that
+	// records every invocation (args, resolved config, result hash, duration,
+	// exit code). The file is rotated when it grows too large.
+	AuditLog string
+	// History records a summary of every algorithm's run in the history
+	// database queried by `fibcalc history`.
+	History bool
+	// HistoryFile is the history database; empty means
+	// history.DefaultPath() (~/.fibcalc_history.jsonl).
+	HistoryFile string
+	// NoWrite, if true, guarantees that the run writes no file: writes are
+	// denied process-wide (see fsguard), the history and calibration
+	// profile updates are turned off and the flags that write files are
+	// rejected.
+	NoWrite bool
+	// ProgressPolicy is what happens to progress updates when the display
+	// falls behind: "drop", "drop-oldest", "coalesce" or "block" (see
+	// progress.Policy).
+	ProgressPolicy string
+	// ProgressTimeout is how long the "block" policy waits for the display.
+	ProgressTimeout time.Duration
+	// ProgressRefresh is the refresh period of the CLI progress display;
+	// zero uses DefaultProgressRefresh.
+	ProgressRefresh time.Duration
+	// TUIRefresh is the period of the TUI dashboard: it redraws and samples
+	// the memory and system metrics together, once per period. Longer
+	// periods wake the machine less often, which matters on battery. Zero
+	// uses DefaultTUIRefresh.
+	TUIRefresh time.Duration
+	// TUIIdle is the time without keyboard input after which the TUI
+	// replaces the dashboard with a dimmed screen showing only the progress
+	// and the ETA, redrawn rarely; 0 disables it.
+	TUIIdle time.Duration
+	// MetricsPush, if set, is the URL that run summaries and live gauges
+	// are pushed to (an InfluxDB write URL or an OTLP/HTTP collector).
+	MetricsPush string
+	// MetricsPushFormat is the wire format of MetricsPush: "influx" or
+	// "otlp".
+	MetricsPushFormat string
+	// MetricsPushInterval is the period of the live gauges.
+	MetricsPushInterval time.Duration
+	// Telemetry opts in to anonymous performance reports: the consent is
+	// recorded, and from then on every successful run is submitted to the
+	// telemetry endpoint until `fibcalc telemetry off`.
+	Telemetry bool
+	// TelemetryEndpoint is the URL the reports are submitted to, required
+	// to opt in since there is no built-in endpoint.
+	TelemetryEndpoint string
+	// PerfCounters enables Linux hardware counters (LLC misses, estimated
+	// memory bandwidth) per algorithm. Algorithms then run sequentially.
+	PerfCounters bool
+	// RuntimeTrace, if set, writes a runtime execution trace of the
+	// heaviest doubling steps: "FILE[:TIME]" as parsed by
+	// ParseRuntimeTrace, the duration bounding the capture window.
+	RuntimeTrace string
+	// Energy adds the energy used by each algorithm to the comparison,
+	// measured with RAPL on Linux or estimated from TDP and CPU time.
+	// Algorithms then run sequentially.
+	Energy bool
+	// TDP is the processor's thermal design power in watts, used to
+	// estimate the energy when it cannot be measured.
+	TDP int
+	// Indicators selects the indicators shown with the detailed result:
+	// "all", a category ("perf", "math") or comma-separated names (see
+	// metrics.Registry.Select). "list" lists them instead of calculating.
+	Indicators string
+	// NoTable disables the precomputed results for small n (F(0) to
+	// F(1000)), so that the algorithms run for every n.
+	NoTable bool
+	// Paranoid checks the invariants of every FFT multiplication and
+	// compares sampled coefficients against a slow reference (see
+	// bigfft.SetParanoid), failing instead of returning a corrupted result.
+	Paranoid bool
+	// AdaptiveParallelism samples the CPU load of other processes during the
+	// calculation and multiplies sequentially while it is high, in parallel
+	// again once it drops (see sysmon.ContentionMonitor).
+	AdaptiveParallelism bool
+	// Warmup is the number of untimed runs of each algorithm on a small n
+	// before the measured run, so that first-run effects do not skew the
+	// comparison. 0 disables warm-up.
+	Warmup int
+	// Nice is the CPU niceness to run at, -20 (highest priority) to 19.
+	// 0 leaves it unchanged.
+	Nice int
+	// IONice is the I/O scheduling class, "CLASS[:LEVEL]" as parsed by
+	// priority.ParseIOPriority (Linux only). Empty leaves it unchanged.
+	IONice string
+	// Background lowers the priority for long runs: niceness 19, idle I/O
+	// and half the processors, unless Nice, IONice or MaxGoroutines say
+	// otherwise.
+	Background bool
+	// Seed drives every randomized ordering (calibration trial order,
+	// micro-benchmark scheduling). 0 picks a fresh seed at startup, which
+	// is then reported so the run can be reproduced.
+	Seed int64
+	// Baseline, if set, is a run report saved from the TUI summary screen.
+	// The TUI compares the current run against it side by side. Ignored
+	// outside TUI mode.
+	Baseline string
+	// ASCII restricts the output to ASCII characters (progress bars, box
+	// drawing, units). It is enabled automatically on consoles that cannot
+	// display Unicode.
+	ASCII bool
+	// TruncateAt is the digit count above which the displayed value is
+	// truncated (without --verbose). 0 never truncates.
+	TruncateAt int
+	// EdgeDigits is the number of digits kept at the beginning and at the
+	// end of a truncated value. 0 uses DefaultEdgeDigits.
+	EdgeDigits int
+	// PagerAt is the digit count from which a full value (--verbose) shown
+	// on a terminal is opened in a pager ($PAGER, else less or more). 0
+	// never pages.
+	PagerAt int
+	// Wrap is the number of digits per line of full values, on screen and
+	// in --output files, each line preceded by the position of its first
+	// digit. 0 does not wrap.
+	Wrap int
+	// DurationFormat is the style of the durations shown by the CLI and the
+	// TUI: "auto", "compact" or "verbose" (see format.DurationStyle).
+	DurationFormat string
+	// DurationPrecision is the number of decimals of the compact and
+	// verbose duration styles, from 0 to 3.
+	DurationPrecision int
+	// DurationLocale is the locale of durations: "en" or "fr".
+	DurationLocale string
+	// QuietDuration, if set, adds the calculation duration to the quiet
+	// output, in a fixed unit: "ns", "ms" or "s", the last two with
+	// DurationPrecision decimals (see format.FormatDurationIn).
+	QuietDuration string
+	// FailMode, if set, makes every calculation fail in the given way
+	// ("timeout", "mismatch", "panic", "oom") so that integrations can test
+	// their error handling. Set with the hidden --fail-mode flag.
+	FailMode string
+	// FailAfter is the time from the start of a calculation at which the
+	// FailMode failure occurs (hidden --fail-after flag).
+	FailAfter time.Duration
+	// HelpFull, if true, prints the full help (flags by group, environment
+	// variables, exit codes and examples) instead of running.
+	HelpFull bool
+	// MachineSchema, if true, prints the JSON Schema of the --machine
+	// document instead of running.
+	MachineSchema bool
 }
 
 // Validate checks the semantic consistency of the configuration parameters.
@@ -103,6 +396,20 @@ func (c AppConfig) Validate(availableAlgos []string) error {
 	if c.Timeout <= 0 {
 		errs = append(errs, apperrors.NewConfigError("timeout value must be strictly positive"))
 	}
+	if c.MemoryPressure < 0 || c.MemoryPressure >= 1 || math.IsNaN(c.MemoryPressure) {
+		errs = append(errs, apperrors.NewConfigError("--memory-pressure must be a fraction of the memory limit below 1, or 0 to disable: %v", c.MemoryPressure))
+	}
+	if c.TimeoutFactor < 0 || math.IsNaN(c.TimeoutFactor) || math.IsInf(c.TimeoutFactor, 0) {
+		errs = append(errs, apperrors.NewConfigError("--timeout-factor must be a positive number, or 0 to use --timeout: %v", c.TimeoutFactor))
+	}
+	if c.StallFactor < 0 || math.IsNaN(c.StallFactor) || math.IsInf(c.StallFactor, 0) {
+		errs = append(errs, apperrors.NewConfigError("--stall-factor must be a positive number, or 0 to disable: %v", c.StallFactor))
+	} else if c.StallFactor > 0 && (c.TUI || c.Calibrate) {
+		errs = append(errs, apperrors.NewConfigError("--stall-factor cannot be combined with --tui or --calibrate"))
+	}
+	if c.StallAbort && c.StallFactor == 0 {
+		errs = append(errs, apperrors.NewConfigError("--stall-abort requires --stall-factor"))
+	}
 	if c.Threshold < 0 {
 		errs = append(errs, apperrors.NewConfigError("parallelism threshold cannot be negative: %d", c.Threshold))
 	}
@@ -112,6 +419,67 @@ func (c AppConfig) Validate(availableAlgos []string) error {
 	if c.MaxGoroutines < 0 {
 		errs = append(errs, apperrors.NewConfigError("max goroutines cannot be negative: %d", c.MaxGoroutines))
 	}
+	if c.Energy && c.TDP <= 0 {
+		errs = append(errs, apperrors.NewConfigError("--tdp must be strictly positive: %d", c.TDP))
+	}
+	if _, err := progress.ParsePolicy(c.ProgressPolicy); err != nil {
+		errs = append(errs, apperrors.NewConfigError("invalid --progress-policy: %v", err))
+	}
+	if c.ProgressTimeout < 0 {
+		errs = append(errs, apperrors.NewConfigError("--progress-timeout cannot be negative: %s", c.ProgressTimeout))
+	}
+	// Zero selects the default period
+	if c.ProgressRefresh != 0 && c.ProgressRefresh < MinRefresh {
+		errs = append(errs, apperrors.NewConfigError("--progress-refresh must be at least %s: %s", MinRefresh, c.ProgressRefresh))
+	}
+	if c.TUIRefresh != 0 && c.TUIRefresh < MinRefresh {
+		errs = append(errs, apperrors.NewConfigError("--tui-refresh must be at least %s: %s", MinRefresh, c.TUIRefresh))
+	}
+	if c.TUIIdle < 0 {
+		errs = append(errs, apperrors.NewConfigError("--tui-idle cannot be negative: %s", c.TUIIdle))
+	}
+	if c.MetricsPush != "" {
+		if err := push.ValidateURL(c.MetricsPush); err != nil {
+			errs = append(errs, apperrors.NewConfigError("invalid --metrics-push: %v", err))
+		}
+		if c.MetricsPushInterval <= 0 {
+			errs = append(errs, apperrors.NewConfigError("--metrics-push-interval must be strictly positive: %s", c.MetricsPushInterval))
+		}
+	}
+	if _, err := push.ParseFormat(c.MetricsPushFormat); err != nil {
+		errs = append(errs, apperrors.NewConfigError("invalid --metrics-push-format: %v", err))
+	}
+	if c.TelemetryEndpoint != "" {
+		if err := push.ValidateURL(c.TelemetryEndpoint); err != nil {
+			errs = append(errs, apperrors.NewConfigError("invalid --telemetry-endpoint: %v", err))
+		}
+	} else if c.Telemetry {
+		errs = append(errs, apperrors.NewConfigError("--telemetry requires --telemetry-endpoint: there is no built-in endpoint"))
+	}
+	if c.Indicators != metrics.SelectList {
+		if _, err := metrics.DefaultRegistry.Select(c.Indicators); err != nil {
+			errs = append(errs, apperrors.NewConfigError("invalid --indicators: %v. Valid values are: all, list, perf, math or names among [%s]", err, strings.Join(metrics.DefaultRegistry.Names(), ", ")))
+		}
+	}
+	if c.SpillThreshold != "" {
+		if _, err := memory.ParseMemoryLimit(c.SpillThreshold); err != nil {
+			errs = append(errs, apperrors.NewConfigError("invalid --spill-threshold: %v", err))
+		}
+	}
+	if c.SessionPool != "" {
+		if _, err := memory.ParseMemoryLimit(c.SessionPool); err != nil {
+			errs = append(errs, apperrors.NewConfigError("invalid --session-pool: %v", err))
+		}
+	}
+	if c.Warmup < 0 {
+		errs = append(errs, apperrors.NewConfigError("--warmup cannot be negative: %d", c.Warmup))
+	}
+	if c.Nice < priority.MinNice || c.Nice > priority.MaxNice {
+		errs = append(errs, apperrors.NewConfigError("--nice must be between %d and %d: %d", priority.MinNice, priority.MaxNice, c.Nice))
+	}
+	if _, err := priority.ParseIOPriority(c.IONice); err != nil {
+		errs = append(errs, apperrors.NewConfigError("invalid --ionice: %v", err))
+	}
 	isAlgoAvailable := false
 	for _, a := range availableAlgos {
 		if a == c.Algo {
@@ -119,8 +487,110 @@ func (c AppConfig) Validate(availableAlgos []string) error {
 			break
 		}
 	}
-	if c.Algo != "all" && !isAlgoAvailable {
-		errs = append(errs, apperrors.NewConfigError("unrecognized algorithm: '%s'. Valid algorithms are: 'all' or [%s]", c.Algo, strings.Join(availableAlgos, ", ")))
+	if c.Algo != "all" && c.Algo != AutoAlgo && !isAlgoAvailable {
+		candidates := append([]string{"all", AutoAlgo}, availableAlgos...)
+		errs = append(errs, apperrors.NewConfigError("%s Valid algorithms are: 'all', 'auto' or [%s]", unknownValueError("algorithm", c.Algo, candidates), strings.Join(availableAlgos, ", ")))
+	}
+	if c.Fallback != "" {
+		if c.Algo == "all" || c.TUI || c.LastDigits > 0 {
+			errs = append(errs, apperrors.NewConfigError("--fallback needs a single --algo and cannot be combined with --tui or --last-digits"))
+		}
+		for _, name := range c.FallbackOrder() {
+			if !containsString(availableAlgos, name) {
+				errs = append(errs, apperrors.NewConfigError("invalid --fallback: %s Valid algorithms are: [%s]", unknownValueError("algorithm", name, availableAlgos), strings.Join(availableAlgos, ", ")))
+			}
+		}
+	}
+	if c.DurationFormat != "" && !containsString(durationFormats, c.DurationFormat) {
+		errs = append(errs, apperrors.NewConfigError("%s Valid formats are: [%s]", unknownValueError("duration format", c.DurationFormat, durationFormats), strings.Join(durationFormats, ", ")))
+	}
+	if c.DurationLocale != "" && !containsString(durationLocales, c.DurationLocale) {
+		errs = append(errs, apperrors.NewConfigError("%s Valid locales are: [%s]", unknownValueError("duration locale", c.DurationLocale, durationLocales), strings.Join(durationLocales, ", ")))
+	}
+	if c.DurationPrecision < 0 || c.DurationPrecision > 3 {
+		errs = append(errs, apperrors.NewConfigError("--duration-precision must be between 0 and 3: %d", c.DurationPrecision))
+	}
+	if c.QuietDuration != "" {
+		if !containsString(quietDurationUnits, c.QuietDuration) {
+			errs = append(errs, apperrors.NewConfigError("%s Valid units are: [%s]", unknownValueError("duration unit", c.QuietDuration, quietDurationUnits), strings.Join(quietDurationUnits, ", ")))
+		}
+		if !c.Quiet {
+			errs = append(errs, apperrors.NewConfigError("--quiet-duration requires --quiet"))
+		}
+	}
+	if c.CompareMode != "" && !containsString(compareModes, c.CompareMode) {
+		errs = append(errs, apperrors.NewConfigError("%s Valid modes are: [%s]", unknownValueError("compare mode", c.CompareMode, compareModes), strings.Join(compareModes, ", ")))
+	}
+	if c.TruncateAt < 0 {
+		errs = append(errs, apperrors.NewConfigError("--truncate-at cannot be negative: %d", c.TruncateAt))
+	}
+	if c.EdgeDigits < 0 {
+		errs = append(errs, apperrors.NewConfigError("--edge-digits cannot be negative: %d", c.EdgeDigits))
+	} else if c.EdgeDigits > 0 && c.TruncateAt > 0 && 2*c.EdgeDigits >= c.TruncateAt {
+		errs = append(errs, apperrors.NewConfigError("--edge-digits (%d) must be less than half of --truncate-at (%d)", c.EdgeDigits, c.TruncateAt))
+	}
+	if c.PagerAt < 0 {
+		errs = append(errs, apperrors.NewConfigError("--pager-at cannot be negative: %d", c.PagerAt))
+	}
+	if c.Wrap < 0 {
+		errs = append(errs, apperrors.NewConfigError("--wrap cannot be negative: %d", c.Wrap))
+	}
+	if c.FailMode != "" && !containsString(failModes, c.FailMode) {
+		errs = append(errs, apperrors.NewConfigError("%s Valid modes are: [%s]", unknownValueError("fail mode", c.FailMode, failModes), strings.Join(failModes, ", ")))
+	}
+	if c.FailMode == "mismatch" && c.Algo != "all" {
+		errs = append(errs, apperrors.NewConfigError("--fail-mode mismatch requires --algo all (it makes the algorithms disagree)"))
+	}
+	if c.FailAfter < 0 {
+		errs = append(errs, apperrors.NewConfigError("--fail-after cannot be negative: %s", c.FailAfter))
+	} else if c.FailAfter > 0 && c.FailMode == "" {
+		errs = append(errs, apperrors.NewConfigError("--fail-after requires --fail-mode"))
+	}
+	if c.Explain {
+		if c.N > MaxExplainN {
+			errs = append(errs, apperrors.NewConfigError("--explain is limited to n <= %d, got %d", MaxExplainN, c.N))
+		}
+		if c.TUI || c.Calibrate || c.Watch || c.Machine || c.NSeries != "" || c.LastDigits > 0 {
+			errs = append(errs, apperrors.NewConfigError("--explain cannot be combined with --tui, --calibrate, --watch, --machine, --n-series or --last-digits"))
+		}
+	}
+	if c.Watch && (c.TUI || c.Calibrate) {
+		errs = append(errs, apperrors.NewConfigError("--watch cannot be combined with --tui or --calibrate"))
+	}
+	if c.Machine && (c.TUI || c.Calibrate || c.Watch) {
+		errs = append(errs, apperrors.NewConfigError("--machine cannot be combined with --tui, --calibrate or --watch"))
+	}
+	if c.NSeries != "" {
+		if c.TUI || c.Calibrate || c.Watch || c.LastDigits > 0 {
+			errs = append(errs, apperrors.NewConfigError("--n-series cannot be combined with --tui, --calibrate, --watch or --last-digits"))
+		}
+		if series, err := ParseNSeries(c.NSeries); err != nil {
+			errs = append(errs, apperrors.NewConfigError("%v", err))
+		} else if largest := series[len(series)-1]; largest > 1_000_000_000 && !c.Force {
+			errs = append(errs, apperrors.NewConfigError("--n-series reaches n=%d, which is extremely large and may crash the system. Add --force to bypass this safety limit", largest))
+		}
+	}
+	if c.SoftRealtime && c.GCControl != "auto" && c.GCControl != "realtime" {
+		errs = append(errs, apperrors.NewConfigError("--soft-realtime runs the GC in realtime mode; it cannot be combined with --gc-control %s", c.GCControl))
+	}
+	if c.ExecOnComplete != "" && (c.TUI || c.Calibrate || c.Watch || c.NSeries != "") {
+		errs = append(errs, apperrors.NewConfigError("--exec-on-complete cannot be combined with --tui, --calibrate, --watch or --n-series"))
+	}
+	if c.RuntimeTrace != "" {
+		if c.TUI || c.Calibrate || c.Watch || c.NSeries != "" || c.Explain || c.LastDigits > 0 {
+			errs = append(errs, apperrors.NewConfigError("--runtime-trace cannot be combined with --tui, --calibrate, --watch, --n-series, --explain or --last-digits"))
+		}
+		if _, _, err := ParseRuntimeTrace(c.RuntimeTrace); err != nil {
+			errs = append(errs, apperrors.NewConfigError("invalid --runtime-trace: %v", err))
+		}
+	}
+	if c.NoWrite {
+		if writers := c.fileWriters(); len(writers) > 0 {
+			errs = append(errs, apperrors.NewConfigError("--no-write cannot be combined with %s, which write files", strings.Join(writers, ", ")))
+		}
+		if c.ExecOnComplete != "" {
+			errs = append(errs, apperrors.NewConfigError("--no-write cannot be combined with --exec-on-complete, whose command may write files"))
+		}
 	}
 	if c.N > 1_000_000_000 && !c.Force && c.LastDigits == 0 {
 		errs = append(errs, apperrors.NewConfigError("n=%d is extremely large and may crash the system. Add --force to bypass this safety limit, or use --last-digits", c.N))
@@ -128,6 +598,69 @@ func (c AppConfig) Validate(availableAlgos []string) error {
 	return errors.Join(errs...)
 }
 
+// SpillThresholdBytes returns SpillThreshold in bytes.
+//
+// Returns:
+//   - int64: The threshold, or 0 if spilling is disabled or the size is
+//     invalid (which Validate reports).
+func (c AppConfig) SpillThresholdBytes() int64 {
+	if c.SpillThreshold == "" {
+		return 0
+	}
+	size, err := memory.ParseMemoryLimit(c.SpillThreshold)
+	if err != nil {
+		return 0
+	}
+	return int64(min(size, math.MaxInt64))
+}
+
+// SessionPoolBytes returns SessionPool in bytes.
+//
+// Returns:
+//   - int64: The budget, or 0 if the pool is disabled or the size is
+//     invalid (which Validate reports).
+func (c AppConfig) SessionPoolBytes() int64 {
+	if c.SessionPool == "" {
+		return 0
+	}
+	size, err := memory.ParseMemoryLimit(c.SessionPool)
+	if err != nil {
+		return 0
+	}
+	return int64(min(size, math.MaxInt64))
+}
+
+// FallbackOrder returns the algorithms listed by Fallback.
+//
+// Returns:
+//   - []string: The algorithms in order; nil if Fallback is empty or
+//     FallbackAuto.
+func (c AppConfig) FallbackOrder() []string {
+	if c.Fallback == "" || c.Fallback == FallbackAuto {
+		return nil
+	}
+	var order []string
+	for _, name := range strings.Split(c.Fallback, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			order = append(order, name)
+		}
+	}
+	return order
+}
+
+// Durations returns the format of the durations shown by the run.
+//
+// Returns:
+//   - format.DurationFormat: The format of DurationFormat,
+//     DurationPrecision and DurationLocale.
+func (c AppConfig) Durations() format.DurationFormat {
+	return format.DurationFormat{
+		Style:     format.DurationStyle(c.DurationFormat),
+		Precision: c.DurationPrecision,
+		Locale:    c.DurationLocale,
+	}
+}
+
 // ParseConfig parses the command-line arguments and populates an AppConfig
 // struct. It defines all the command-line flags, sets their default values, and
 // handles the parsing process. After parsing, it performs validation on the
@@ -148,52 +681,199 @@ func (c AppConfig) Validate(availableAlgos []string) error {
 //   - AppConfig: The populated configuration struct.
 //   - error: An error if flag parsing fails or validation fails.
 func ParseConfig(programName string, args []string, errorWriter io.Writer, availableAlgos []string) (AppConfig, error) {
-	fs := flag.NewFlagSet(programName, flag.ContinueOnError)
-	fs.SetOutput(errorWriter)
-	algoHelp := fmt.Sprintf("Algorithm to use: 'all' (default) or one of [%s].", strings.Join(availableAlgos, ", "))
-
 	config := AppConfig{}
-	fs.Uint64Var(&config.N, "n", DefaultN, "Index n of the Fibonacci number to calculate.")
-	fs.BoolVar(&config.Verbose, "v", false, "Display the full value of the result (can be very long).")
-	fs.BoolVar(&config.Verbose, "verbose", false, "Alias for -v.")
-	fs.BoolVar(&config.Details, "d", false, "Display performance details and result metadata.")
-	fs.BoolVar(&config.Details, "details", false, "Alias for -d.")
-	fs.DurationVar(&config.Timeout, "timeout", DefaultTimeout, "Maximum execution time for the calculation.")
-	fs.StringVar(&config.Algo, "algo", DefaultAlgo, algoHelp)
-	fs.IntVar(&config.Threshold, "threshold", 0, "Threshold (in bits) for activating parallelism in multiplications (0 for auto).")
-	fs.IntVar(&config.FFTThreshold, "fft-threshold", 0, "Threshold (in bits) to enable FFT multiplication (0 for auto).")
-	fs.IntVar(&config.StrassenThreshold, "strassen-threshold", 0, "Threshold (in bits) to switch to Strassen's algorithm in matrix multiplication (0 for auto).")
-	fs.BoolVar(&config.Calibrate, "calibrate", false, "Runs calibration mode to determine the optimal parallelism threshold.")
-	fs.BoolVar(&config.AutoCalibrate, "auto-calibrate", false, "Enables quick automatic calibration at startup (may increase loading time).")
-	fs.StringVar(&config.CalibrationProfile, "calibration-profile", "", "Path to calibration profile file (default: ~/.fibcalc_calibration.json).")
-	// New CLI enhancement flags
-	fs.StringVar(&config.OutputFile, "output", "", "Output file path for the result.")
-	fs.StringVar(&config.OutputFile, "o", "", "Output file path (shorthand).")
-	fs.BoolVar(&config.Quiet, "quiet", false, "Quiet mode - minimal output for scripts.")
-	fs.BoolVar(&config.Quiet, "q", false, "Quiet mode (shorthand).")
-	fs.StringVar(&config.Completion, "completion", "", "Generate shell completion script (bash, zsh, fish, powershell).")
-	fs.BoolVar(&config.ShowValue, "calculate", false, "Display the calculated value (disabled by default).")
-	fs.BoolVar(&config.ShowValue, "c", false, "Display the calculated value (shorthand).")
-	fs.BoolVar(&config.TUI, "tui", false, "Launch interactive TUI dashboard.")
-	fs.IntVar(&config.LastDigits, "last-digits", 0, "Compute only the last K decimal digits (uses O(K) memory).")
-	fs.StringVar(&config.MemoryLimit, "memory-limit", "", "Maximum memory budget (e.g., 8G, 512M). Warns if estimate exceeds limit.")
-	fs.StringVar(&config.GCControl, "gc-control", "auto", "GC control during calculation (auto, aggressive, disabled).")
-	fs.IntVar(&config.MaxGoroutines, "max-goroutines", 0, "Max goroutines for parallel operations (0 for auto).")
-	fs.BoolVar(&config.Force, "force", false, "Force calculation even if n exceeds safety limits (N > 1,000,000,000).")
-	setCustomUsage(fs)
+	fs := newFlagSet(programName, &config, availableAlgos)
 
-	if err := fs.Parse(args); err != nil {
-		return AppConfig{}, err
+	// The flag package reports an unknown flag with the whole usage message;
+	// report it with the closest flags instead.
+	fs.SetOutput(io.Discard)
+	err := fs.Parse(args)
+	fs.SetOutput(errorWriter)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fs.Usage()
+			return AppConfig{}, err
+		}
+		if msg := unknownFlagError(fs, err); msg != "" {
+			fmt.Fprintln(errorWriter, "Configuration error:", msg)
+			fmt.Fprintf(errorWriter, "Run '%s --help' for the list of flags.\n", filepath.Base(fs.Name()))
+		} else {
+			fmt.Fprintln(errorWriter, err)
+			fs.Usage()
+		}
+		return AppConfig{}, apperrors.NewConfigError("%v", err)
 	}
 
 	// Apply environment variable overrides for flags not explicitly set
 	applyEnvOverrides(&config, fs)
 
-	config.Algo = strings.ToLower(config.Algo)
+	normalize(&config)
+	applyNoWrite(&config)
 	if err := config.Validate(availableAlgos); err != nil {
 		fmt.Fprintln(errorWriter, "Configuration error:", err)
 		fs.Usage()
-		return AppConfig{}, errors.New("invalid configuration")
+		return AppConfig{}, apperrors.NewConfigError("invalid configuration")
 	}
 	return config, nil
 }
+
+// newFlagSet defines every command-line flag of the application, bound to
+// the fields of c. It is the single definition of the flags: ParseConfig
+// parses with it and the full help and man page describe it (see help.go).
+//
+// Parameters:
+//   - programName: The name of the program, used in the usage message.
+//   - c: The configuration the flags are bound to.
+//   - availableAlgos: The valid algorithm names, listed in the --algo help.
+//
+// Returns:
+//   - *flag.FlagSet: The flag set, which continues on error.
+func newFlagSet(programName string, c *AppConfig, availableAlgos []string) *flag.FlagSet {
+	fs := flag.NewFlagSet(programName, flag.ContinueOnError)
+	algoHelp := fmt.Sprintf("Algorithm to use: 'all' (default), 'auto' (expected fastest for n) or one of [%s].", strings.Join(availableAlgos, ", "))
+
+	fs.Uint64Var(&c.N, "n", DefaultN, "Index n of the Fibonacci number to calculate.")
+	fs.BoolVar(&c.Verbose, "v", false, "Display the full value of the result (can be very long).")
+	fs.BoolVar(&c.Verbose, "verbose", false, "Alias for -v.")
+	fs.BoolVar(&c.Details, "d", false, "Display performance details and result metadata.")
+	fs.BoolVar(&c.Details, "details", false, "Alias for -d.")
+	fs.DurationVar(&c.Timeout, "timeout", DefaultTimeout, "Maximum execution time for the calculation.")
+	fs.Float64Var(&c.TimeoutFactor, "timeout-factor", 0, "Set the timeout to this multiple of the predicted duration instead (e.g. 2.0; 0 uses --timeout).")
+	fs.Float64Var(&c.StallFactor, "stall-factor", 0, "Report a calculation making no progress for this many times the expected time until its next progress report (e.g. 10; 0 disables), with a goroutine dump.")
+	fs.BoolVar(&c.StallAbort, "stall-abort", false, "Abort a stalled calculation (see --stall-factor), exiting with code 5.")
+	fs.StringVar(&c.Algo, "algo", DefaultAlgo, algoHelp)
+	fs.StringVar(&c.Fallback, "fallback", "", "If the algorithm fails (not by timeout), retry with these algorithms in order: a comma-separated list, or auto (by estimated cost).")
+	fs.IntVar(&c.Threshold, "threshold", 0, "Threshold (in bits) for activating parallelism in multiplications (0 for auto).")
+	fs.IntVar(&c.FFTThreshold, "fft-threshold", 0, "Threshold (in bits) to enable FFT multiplication (0 for auto).")
+	fs.IntVar(&c.StrassenThreshold, "strassen-threshold", 0, "Threshold (in bits) to switch to Strassen's algorithm in matrix multiplication (0 for auto).")
+	fs.BoolVar(&c.Calibrate, "calibrate", false, "Runs calibration mode to determine the optimal parallelism threshold.")
+	fs.BoolVar(&c.AutoCalibrate, "auto-calibrate", false, "Enables quick automatic calibration at startup (may increase loading time).")
+	fs.StringVar(&c.CalibrationProfile, "calibration-profile", "", "Path to calibration profile file (default: ~/.fibcalc_calibration.json).")
+	fs.BoolVar(&c.ProfileReadOnly, "profile-readonly", false, "Never write the calibration profile; calibration results only apply to this run.")
+	fs.BoolVar(&c.Watch, "watch", false, "Run again each time the calibration profile changes, showing the timings against the previous run.")
+	// New CLI enhancement flags
+	fs.StringVar(&c.OutputFile, "output", "", "Output file path for the result.")
+	fs.StringVar(&c.OutputFile, "o", "", "Output file path (shorthand).")
+	fs.StringVar(&c.SignKey, "sign-key", "", "Sign result files and reports with this PEM ed25519 private key.")
+	fs.BoolVar(&c.Quiet, "quiet", false, "Quiet mode - minimal output for scripts.")
+	fs.BoolVar(&c.Quiet, "q", false, "Quiet mode (shorthand).")
+	fs.BoolVar(&c.Machine, "machine", false, "Write only a JSON result document to stdout; the human-readable output goes to stderr.")
+	fs.BoolVar(&c.MachineSchema, "machine-schema", false, "Print the JSON Schema of the --machine document and exit.")
+	fs.BoolVar(&c.GHASummary, "gha-summary", false, "Append a Markdown table of the results to the GitHub Actions job summary ($GITHUB_STEP_SUMMARY), if set.")
+	fs.StringVar(&c.ExecOnComplete, "exec-on-complete", "", "Run this shell command once the calculation is over, with FIB_N, FIB_DURATION_MS, FIB_DIGITS, FIB_OUTPUT_FILE and FIB_EXIT set.")
+	fs.BoolVar(&c.Explain, "explain", false, fmt.Sprintf("Explain each fast doubling step (and matrix step with --algo matrix or all) with its identities and values, for N <= %d.", MaxExplainN))
+	fs.BoolVar(&c.OEIS, "oeis", false, "Check the result against the OEIS b-file of A000045 (downloaded on first use and cached) when it lists F(N).")
+	fs.StringVar(&c.Completion, "completion", "", "Generate shell completion script (bash, zsh, fish, powershell).")
+	fs.BoolVar(&c.ShowValue, "calculate", false, "Display the calculated value (disabled by default).")
+	fs.BoolVar(&c.ShowValue, "c", false, "Display the calculated value (shorthand).")
+	fs.BoolVar(&c.TUI, "tui", false, "Launch interactive TUI dashboard.")
+	fs.StringVar(&c.LogFile, "log-file", "", "With --tui, also write the plain-text progress and results the CLI would print to this file.")
+	fs.StringVar(&c.NSeries, "n-series", "", "Run once per index of a series instead of -n, e.g. \"10^6..10^7 step x2\" (or step +D), then show how the timings scale.")
+	fs.IntVar(&c.LastDigits, "last-digits", 0, "Compute only the last K decimal digits (uses O(K) memory).")
+	fs.StringVar(&c.MemoryLimit, "memory-limit", "", "Maximum memory budget (e.g., 8G, 512M). Warns if estimate exceeds limit.")
+	fs.StringVar(&c.SpillThreshold, "spill-threshold", "", "Place FFT buffers larger than this size (e.g., 512M) in memory-mapped temporary files, to run slightly beyond RAM.")
+	fs.StringVar(&c.SpillDir, "spill-dir", "", "Directory of the --spill-threshold files (default: the system temporary directory).")
+	fs.StringVar(&c.SessionPool, "session-pool", DefaultSessionPool, "With --tui, keep calculation states up to this size (e.g., 1G) across restarts to reuse their buffers (0 to disable).")
+	fs.Float64Var(&c.MemoryPressure, "memory-pressure", memory.DefaultPressureThreshold, "Fraction of the Go memory limit (GOMEMLIMIT) from which FFT transform caches are dropped and disabled with a warning (0 to disable).")
+	fs.StringVar(&c.GCControl, "gc-control", "auto", "GC control during calculation (auto, aggressive, disabled, realtime).")
+	fs.BoolVar(&c.SoftRealtime, "soft-realtime", false, "Bound GC pauses and compute chunks so that the display refreshes smoothly (for demos), at a small cost in throughput.")
+	fs.IntVar(&c.MaxGoroutines, "max-goroutines", 0, "Max goroutines for parallel operations (0 for auto).")
+	fs.BoolVar(&c.Force, "force", false, "Force calculation even if n exceeds safety limits (N > 1,000,000,000).")
+	fs.StringVar(&c.CompareMode, "compare-mode", DefaultCompareMode, "Scheduling of multiple algorithms: parallel, sequential (fair timings) or staggered.")
+	fs.StringVar(&c.AuditLog, "audit-log", "", "Append a JSON record of each invocation to this file (rotated by size).")
+	fs.BoolVar(&c.History, "history", true, "Record each run in the history database queried by 'fibcalc history' (--history=false to disable).")
+	fs.StringVar(&c.HistoryFile, "history-file", "", "History database path (default: ~/.fibcalc_history.jsonl).")
+	fs.BoolVar(&c.NoWrite, "no-write", false, "Write no file at all (no history, profile, cache, report or log), for restricted or hermetic environments.")
+	fs.StringVar(&c.ProgressPolicy, "progress-policy", string(progress.PolicyDrop), "When the display falls behind: drop new updates, drop-oldest, coalesce to the latest per algorithm, or block (up to --progress-timeout).")
+	fs.DurationVar(&c.ProgressTimeout, "progress-timeout", progress.DefaultBlockTimeout, "How long the block progress policy waits for the display.")
+	fs.DurationVar(&c.ProgressRefresh, "progress-refresh", DefaultProgressRefresh, "Refresh period of the CLI progress display.")
+	fs.DurationVar(&c.TUIRefresh, "tui-refresh", DefaultTUIRefresh, "Refresh period of the TUI dashboard, which samples its memory and system metrics at the same time.")
+	fs.DurationVar(&c.TUIIdle, "tui-idle", DefaultTUIIdle, "Inactivity after which the TUI dims to a minimal progress and ETA screen until a key is pressed (0 disables).")
+	fs.StringVar(&c.MetricsPush, "metrics-push", "", "Push run summaries and live gauges to this InfluxDB write URL or OTLP/HTTP metrics endpoint.")
+	fs.StringVar(&c.MetricsPushFormat, "metrics-push-format", string(push.FormatInflux), "Wire format of --metrics-push: influx (line protocol) or otlp (OTLP/HTTP JSON).")
+	fs.DurationVar(&c.MetricsPushInterval, "metrics-push-interval", push.DefaultInterval, "Period of the live gauges pushed during a calculation.")
+	fs.BoolVar(&c.Telemetry, "telemetry", false, "Opt in to anonymous performance reports (hardware hash, n, algorithm, duration, thresholds) until 'fibcalc telemetry off'.")
+	fs.StringVar(&c.TelemetryEndpoint, "telemetry-endpoint", "", "URL the --telemetry reports are submitted to.")
+	fs.BoolVar(&c.PerfCounters, "perf-counters", false, "Report LLC misses and memory bandwidth per algorithm (Linux perf_event; runs algorithms sequentially).")
+	fs.StringVar(&c.RuntimeTrace, "runtime-trace", "", fmt.Sprintf("Write a runtime execution trace of the heaviest doubling steps to FILE, for go tool trace, over at most TIME (FILE[:TIME], default %s).", DefaultTraceWindow))
+	fs.BoolVar(&c.Energy, "energy", false, "Report the energy used per algorithm (RAPL on Linux, else estimated from --tdp; runs algorithms sequentially).")
+	fs.IntVar(&c.TDP, "tdp", energy.DefaultTDP, "Processor thermal design power in watts, for the energy estimate.")
+	fs.StringVar(&c.Indicators, "indicators", metrics.SelectAll, "Indicators shown with --details: all, perf, math, comma-separated names, or list to print them.")
+	fs.BoolVar(&c.NoTable, "no-table", false, "Run the algorithms even for n <= 1000 instead of returning the values embedded in the binary.")
+	fs.BoolVar(&c.Paranoid, "paranoid", false, "Check the invariants of every FFT multiplication against a slow reference, to catch corruption early.")
+	fs.BoolVar(&c.AdaptiveParallelism, "adaptive-parallelism", false, "Multiply sequentially while other processes keep the CPUs busy, in parallel again when they calm down.")
+	fs.IntVar(&c.Warmup, "warmup", 0, "Untimed runs of each algorithm on a small n before the measured run (0 to disable).")
+	fs.IntVar(&c.Nice, "nice", 0, "CPU niceness, -20 (highest priority) to 19 (0 leaves it unchanged).")
+	fs.StringVar(&c.IONice, "ionice", "", "I/O scheduling class: idle, best-effort[:0-7] or realtime[:0-7] (Linux).")
+	fs.BoolVar(&c.Background, "background", false, "Run at low priority: nice 19, idle I/O and half the processors.")
+	fs.Int64Var(&c.Seed, "seed", 0, "Seed for randomized calibration ordering (0 for a fresh seed, reported for reproducibility).")
+	fs.StringVar(&c.Baseline, "baseline", "", "Run report saved from the TUI summary to compare the current run against (TUI only).")
+	fs.IntVar(&c.TruncateAt, "truncate-at", DefaultTruncateAt, "Truncate displayed values longer than this many digits (0 to never truncate).")
+	fs.IntVar(&c.EdgeDigits, "edge-digits", DefaultEdgeDigits, "Digits shown at each end of a truncated value.")
+	fs.IntVar(&c.PagerAt, "pager-at", DefaultPagerAt, "Open full values (--verbose) of at least this many digits in a pager on a terminal (0 to never page).")
+	fs.IntVar(&c.Wrap, "wrap", 0, "Print full values in lines of this many digits, each preceded by the position of its first digit (0 to not wrap).")
+	fs.StringVar(&c.DurationFormat, "duration-format", string(format.DurationAuto), "Style of durations: auto, compact (rounded, 1.23s) or verbose (2 minutes 3.46 seconds).")
+	fs.IntVar(&c.DurationPrecision, "duration-precision", format.DefaultDurationPrecision, "Decimals of compact and verbose durations (0 to 3).")
+	fs.StringVar(&c.DurationLocale, "duration-locale", "en", "Locale of durations: en, or fr (decimal comma, French unit names).")
+	fs.StringVar(&c.QuietDuration, "quiet-duration", "", "In quiet mode, print the duration after the value, separated by a tab, in ns, ms or s (--duration-precision decimals, decimal point).")
+	// Hidden failure-injection flags (see hiddenFlags)
+	fs.StringVar(&c.FailMode, "fail-mode", "", "Simulate a failure: timeout, mismatch, panic or oom.")
+	fs.DurationVar(&c.FailAfter, "fail-after", 0, "Time from the start of a calculation at which --fail-mode fails.")
+	fs.BoolVar(&c.ASCII, "ascii", false, "Restrict output to ASCII characters (for legacy consoles).")
+	fs.BoolVar(&c.HelpFull, "help-full", false, "Show the full help: flags by group, environment variables, exit codes and examples.")
+	setCustomUsage(fs)
+	return fs
+}
+
+// normalize lowercases the case-insensitive values of c.
+func normalize(c *AppConfig) {
+	c.Algo = strings.ToLower(c.Algo)
+	c.CompareMode = strings.ToLower(c.CompareMode)
+	c.DurationFormat = strings.ToLower(c.DurationFormat)
+	c.DurationLocale = strings.ToLower(c.DurationLocale)
+	c.QuietDuration = strings.ToLower(c.QuietDuration)
+	c.Fallback = strings.ToLower(c.Fallback)
+}
+
+// applyNoWrite turns off, for --no-write, the features that write files
+// unless disabled: the history and the calibration profile updates. The
+// flags that request a file are rejected by Validate instead (see
+// fileWriters).
+func applyNoWrite(c *AppConfig) {
+	if c.NoWrite {
+		c.History = false
+		c.ProfileReadOnly = true
+	}
+}
+
+// fileWriters returns the flags set in c that request a file to be written.
+func (c AppConfig) fileWriters() []string {
+	var flags []string
+	for _, w := range []struct {
+		flag string
+		set  bool
+	}{
+		{"--output", c.OutputFile != ""},
+		{"--log-file", c.LogFile != ""},
+		{"--audit-log", c.AuditLog != ""},
+		{"--runtime-trace", c.RuntimeTrace != ""},
+		{"--spill-threshold", c.SpillThreshold != ""},
+		{"--gha-summary", c.GHASummary},
+		{"--telemetry", c.Telemetry},
+	} {
+		if w.set {
+			flags = append(flags, w.flag)
+		}
+	}
+	return flags
+}
+
+// containsString reports whether s is present in list.
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -3,8 +3,11 @@ package config
 import (
 	"bytes"
 	"os"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/agbru/fibcalc/internal/format"
 )
 
 // TestParseConfigEnvironmentVariables tests environment variable parsing.
@@ -201,3 +204,698 @@ func TestGetEnvHelpers(t *testing.T) {
 		}
 	})
 }
+
+// TestParseConfigSeed tests the --seed flag and its FIBCALC_SEED override.
+func TestParseConfigSeed(t *testing.T) {
+	algos := []string{"fast", "matrix", "fft"}
+
+	t.Run("flag", func(t *testing.T) {
+		cfg, err := ParseConfig("test", []string{"--seed", "-7"}, &bytes.Buffer{}, algos)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Seed != -7 {
+			t.Errorf("expected Seed=-7, got %d", cfg.Seed)
+		}
+	})
+
+	t.Run("environment", func(t *testing.T) {
+		t.Setenv(EnvPrefix+"SEED", "123456789")
+		cfg, err := ParseConfig("test", []string{}, &bytes.Buffer{}, algos)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Seed != 123456789 {
+			t.Errorf("expected Seed=123456789, got %d", cfg.Seed)
+		}
+	})
+
+	t.Run("flag wins over environment", func(t *testing.T) {
+		t.Setenv(EnvPrefix+"SEED", "1")
+		cfg, err := ParseConfig("test", []string{"--seed", "2"}, &bytes.Buffer{}, algos)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Seed != 2 {
+			t.Errorf("expected Seed=2, got %d", cfg.Seed)
+		}
+	})
+}
+
+// TestParseConfigBaseline tests the --baseline flag and its FIBCALC_BASELINE override.
+func TestParseConfigBaseline(t *testing.T) {
+	algos := []string{"fast", "matrix", "fft"}
+
+	t.Run("flag", func(t *testing.T) {
+		cfg, err := ParseConfig("test", []string{"--tui", "--baseline", "run.json"}, &bytes.Buffer{}, algos)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Baseline != "run.json" {
+			t.Errorf("expected Baseline=run.json, got %q", cfg.Baseline)
+		}
+	})
+
+	t.Run("environment", func(t *testing.T) {
+		t.Setenv(EnvPrefix+"BASELINE", "env.json")
+		cfg, err := ParseConfig("test", []string{}, &bytes.Buffer{}, algos)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Baseline != "env.json" {
+			t.Errorf("expected Baseline=env.json, got %q", cfg.Baseline)
+		}
+	})
+}
+
+func TestParseConfigASCII(t *testing.T) {
+	algos := []string{"fast", "matrix", "fft"}
+
+	t.Run("flag", func(t *testing.T) {
+		cfg, err := ParseConfig("test", []string{"--ascii"}, &bytes.Buffer{}, algos)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !cfg.ASCII {
+			t.Error("expected ASCII=true")
+		}
+	})
+
+	t.Run("environment", func(t *testing.T) {
+		t.Setenv(EnvPrefix+"ASCII", "yes")
+		cfg, err := ParseConfig("test", []string{}, &bytes.Buffer{}, algos)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !cfg.ASCII {
+			t.Error("expected ASCII=true from FIBCALC_ASCII")
+		}
+	})
+}
+
+func TestParseConfigTruncation(t *testing.T) {
+	algos := []string{"fast", "matrix", "fft"}
+
+	t.Run("defaults", func(t *testing.T) {
+		cfg, err := ParseConfig("test", []string{}, &bytes.Buffer{}, algos)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.TruncateAt != DefaultTruncateAt || cfg.EdgeDigits != DefaultEdgeDigits {
+			t.Errorf("expected %d/%d, got %d/%d", DefaultTruncateAt, DefaultEdgeDigits, cfg.TruncateAt, cfg.EdgeDigits)
+		}
+		if cfg.PagerAt != DefaultPagerAt {
+			t.Errorf("expected pager-at %d, got %d", DefaultPagerAt, cfg.PagerAt)
+		}
+	})
+
+	t.Run("flags", func(t *testing.T) {
+		cfg, err := ParseConfig("test", []string{"--truncate-at", "1000", "--edge-digits", "200"}, &bytes.Buffer{}, algos)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.TruncateAt != 1000 || cfg.EdgeDigits != 200 {
+			t.Errorf("expected 1000/200, got %d/%d", cfg.TruncateAt, cfg.EdgeDigits)
+		}
+	})
+
+	t.Run("environment", func(t *testing.T) {
+		t.Setenv(EnvPrefix+"TRUNCATE_AT", "0")
+		t.Setenv(EnvPrefix+"EDGE_DIGITS", "10")
+		t.Setenv(EnvPrefix+"PAGER_AT", "0")
+		t.Setenv(EnvPrefix+"WRAP", "80")
+		cfg, err := ParseConfig("test", []string{}, &bytes.Buffer{}, algos)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.TruncateAt != 0 || cfg.EdgeDigits != 10 || cfg.PagerAt != 0 || cfg.Wrap != 80 {
+			t.Errorf("expected 0/10/0/80, got %d/%d/%d/%d", cfg.TruncateAt, cfg.EdgeDigits, cfg.PagerAt, cfg.Wrap)
+		}
+	})
+
+	for _, args := range [][]string{
+		{"--truncate-at", "-1"},
+		{"--edge-digits", "-5"},
+		{"--pager-at", "-1"},
+		{"--wrap", "-1"},
+		{"--truncate-at", "40", "--edge-digits", "20"},
+	} {
+		t.Run("invalid "+strings.Join(args, " "), func(t *testing.T) {
+			if _, err := ParseConfig("test", args, &bytes.Buffer{}, algos); err == nil {
+				t.Errorf("expected an error for %v", args)
+			}
+		})
+	}
+}
+
+func TestParseConfigDurations(t *testing.T) {
+	algos := []string{"fast", "matrix", "fft"}
+
+	t.Run("flags", func(t *testing.T) {
+		cfg, err := ParseConfig("test", []string{"--duration-format", "Verbose", "--duration-precision", "1", "--duration-locale", "FR"}, &bytes.Buffer{}, algos)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := format.DurationFormat{Style: format.DurationVerbose, Precision: 1, Locale: "fr"}
+		if got := cfg.Durations(); got != want {
+			t.Errorf("Durations() = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("quiet duration", func(t *testing.T) {
+		cfg, err := ParseConfig("test", []string{"-q", "--quiet-duration", "MS"}, &bytes.Buffer{}, algos)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.QuietDuration != "ms" {
+			t.Errorf("QuietDuration = %q, want ms", cfg.QuietDuration)
+		}
+	})
+
+	t.Run("environment", func(t *testing.T) {
+		t.Setenv(EnvPrefix+"DURATION_FORMAT", "compact")
+		t.Setenv(EnvPrefix+"DURATION_PRECISION", "0")
+		cfg, err := ParseConfig("test", []string{}, &bytes.Buffer{}, algos)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.DurationFormat != "compact" || cfg.DurationPrecision != 0 {
+			t.Errorf("expected compact/0, got %s/%d", cfg.DurationFormat, cfg.DurationPrecision)
+		}
+	})
+
+	for _, args := range [][]string{
+		{"--duration-format", "short"},
+		{"--duration-precision", "4"},
+		{"--duration-locale", "de"},
+		{"--quiet-duration", "us", "-q"},
+		{"--quiet-duration", "ms"},
+	} {
+		t.Run("invalid "+strings.Join(args, " "), func(t *testing.T) {
+			if _, err := ParseConfig("test", args, &bytes.Buffer{}, algos); err == nil {
+				t.Errorf("expected an error for %v", args)
+			}
+		})
+	}
+}
+
+func TestParseConfigMachine(t *testing.T) {
+	algos := []string{"fast", "matrix", "fft"}
+
+	cfg, err := ParseConfig("test", []string{"--machine"}, &bytes.Buffer{}, algos)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.Machine {
+		t.Error("expected Machine to be set by --machine")
+	}
+
+	for _, other := range []string{"--tui", "--calibrate", "--watch"} {
+		if _, err := ParseConfig("test", []string{"--machine", other}, &bytes.Buffer{}, algos); err == nil {
+			t.Errorf("expected an error for --machine with %s", other)
+		}
+	}
+}
+
+func TestParseConfigFallback(t *testing.T) {
+	algos := []string{"fast", "matrix", "fft"}
+
+	cfg, err := ParseConfig("test", []string{"--algo", "fast", "--fallback", "Matrix, fft"}, &bytes.Buffer{}, algos)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := cfg.FallbackOrder(); len(got) != 2 || got[0] != "matrix" || got[1] != "fft" {
+		t.Errorf("FallbackOrder() = %v, want [matrix fft]", got)
+	}
+
+	for _, args := range [][]string{
+		{"--algo", "fast", "--fallback", "matrx"},
+		{"--fallback", "auto"},
+		{"--algo", "fast", "--fallback", "auto", "--tui"},
+	} {
+		if _, err := ParseConfig("test", args, &bytes.Buffer{}, algos); err == nil {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+}
+
+func TestParseConfigRefresh(t *testing.T) {
+	algos := []string{"fast", "matrix", "fft"}
+
+	cfg, err := ParseConfig("test", nil, &bytes.Buffer{}, algos)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.ProgressRefresh != DefaultProgressRefresh || cfg.TUIRefresh != DefaultTUIRefresh {
+		t.Errorf("expected the default periods, got %s/%s", cfg.ProgressRefresh, cfg.TUIRefresh)
+	}
+
+	cfg, err = ParseConfig("test", []string{"--progress-refresh", "1s", "--tui-refresh", "2s"}, &bytes.Buffer{}, algos)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.ProgressRefresh != time.Second || cfg.TUIRefresh != 2*time.Second {
+		t.Errorf("expected 1s/2s, got %s/%s", cfg.ProgressRefresh, cfg.TUIRefresh)
+	}
+
+	for _, args := range [][]string{
+		{"--progress-refresh", "1ms"},
+		{"--tui-refresh", "-1s"},
+	} {
+		if _, err := ParseConfig("test", args, &bytes.Buffer{}, algos); err == nil {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+}
+
+func TestParseConfigTelemetry(t *testing.T) {
+	algos := []string{"fast", "matrix", "fft"}
+
+	cfg, err := ParseConfig("test", []string{"--telemetry", "--telemetry-endpoint", "https://example.test/v1/records"}, &bytes.Buffer{}, algos)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.Telemetry || cfg.TelemetryEndpoint != "https://example.test/v1/records" {
+		t.Errorf("expected the opt-in with its endpoint, got %v/%q", cfg.Telemetry, cfg.TelemetryEndpoint)
+	}
+
+	for _, args := range [][]string{
+		{"--telemetry"},
+		{"--telemetry", "--telemetry-endpoint", "ftp://example.test"},
+	} {
+		if _, err := ParseConfig("test", args, &bytes.Buffer{}, algos); err == nil {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+}
+
+func TestParseConfigExplain(t *testing.T) {
+	algos := []string{"fast", "matrix", "fft"}
+
+	cfg, err := ParseConfig("test", []string{"-n", "100", "--explain"}, &bytes.Buffer{}, algos)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.Explain {
+		t.Error("expected --explain to be set")
+	}
+
+	for _, args := range [][]string{
+		{"-n", "101", "--explain"},
+		{"-n", "10", "--explain", "--tui"},
+		{"-n", "10", "--explain", "--n-series", "5,10"},
+	} {
+		if _, err := ParseConfig("test", args, &bytes.Buffer{}, algos); err == nil {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+}
+
+func TestParseConfigStallWatchdog(t *testing.T) {
+	algos := []string{"fast", "matrix", "fft"}
+
+	cfg, err := ParseConfig("test", []string{"--stall-factor", "10", "--stall-abort"}, &bytes.Buffer{}, algos)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.StallFactor != 10 || !cfg.StallAbort {
+		t.Errorf("expected 10/true, got %v/%v", cfg.StallFactor, cfg.StallAbort)
+	}
+
+	for _, args := range [][]string{
+		{"--stall-factor", "-1"},
+		{"--stall-factor", "NaN"},
+		{"--stall-abort"},
+		{"--stall-factor", "10", "--tui"},
+	} {
+		if _, err := ParseConfig("test", args, &bytes.Buffer{}, algos); err == nil {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+}
+
+func TestParseConfigFailureInjection(t *testing.T) {
+	algos := []string{"fast", "matrix", "fft"}
+
+	cfg, err := ParseConfig("test", []string{"--fail-mode", "timeout", "--fail-after", "30s"}, &bytes.Buffer{}, algos)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.FailMode != "timeout" || cfg.FailAfter != 30*time.Second {
+		t.Errorf("expected timeout/30s, got %q/%s", cfg.FailMode, cfg.FailAfter)
+	}
+
+	for _, args := range [][]string{
+		{"--fail-mode", "meltdown"},
+		{"--fail-after", "1s"},
+		{"--fail-mode", "oom", "--fail-after", "-1s"},
+		{"--fail-mode", "mismatch", "--algo", "fast"},
+	} {
+		t.Run("invalid "+strings.Join(args, " "), func(t *testing.T) {
+			if _, err := ParseConfig("test", args, &bytes.Buffer{}, algos); err == nil {
+				t.Errorf("expected an error for %v", args)
+			}
+		})
+	}
+}
+
+func TestUsageHidesFailureInjectionFlags(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := ParseConfig("test", []string{"--help"}, &buf, []string{"fast"}); err == nil {
+		t.Fatal("expected the help error")
+	}
+	if strings.Contains(buf.String(), "fail-") {
+		t.Errorf("usage should not list the failure-injection flags:\n%s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "-truncate-at") {
+		t.Errorf("usage should still list regular flags:\n%s", buf.String())
+	}
+}
+
+func TestParseConfigWarmup(t *testing.T) {
+	algos := []string{"fast", "matrix", "fft"}
+
+	t.Run("flag", func(t *testing.T) {
+		cfg, err := ParseConfig("test", []string{"--warmup", "3"}, &bytes.Buffer{}, algos)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Warmup != 3 {
+			t.Errorf("expected Warmup=3, got %d", cfg.Warmup)
+		}
+	})
+
+	t.Run("environment", func(t *testing.T) {
+		t.Setenv(EnvPrefix+"WARMUP", "2")
+		cfg, err := ParseConfig("test", []string{}, &bytes.Buffer{}, algos)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Warmup != 2 {
+			t.Errorf("expected Warmup=2 from FIBCALC_WARMUP, got %d", cfg.Warmup)
+		}
+	})
+
+	t.Run("negative", func(t *testing.T) {
+		if _, err := ParseConfig("test", []string{"--warmup", "-1"}, &bytes.Buffer{}, algos); err == nil {
+			t.Error("expected an error for a negative --warmup")
+		}
+	})
+}
+
+func TestParseConfigEnergy(t *testing.T) {
+	algos := []string{"fast", "matrix", "fft"}
+
+	t.Run("flag", func(t *testing.T) {
+		cfg, err := ParseConfig("test", []string{"--energy", "--tdp", "125"}, &bytes.Buffer{}, algos)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !cfg.Energy || cfg.TDP != 125 {
+			t.Errorf("expected Energy=true TDP=125, got %v %d", cfg.Energy, cfg.TDP)
+		}
+	})
+
+	t.Run("environment", func(t *testing.T) {
+		t.Setenv(EnvPrefix+"ENERGY", "true")
+		t.Setenv(EnvPrefix+"TDP", "45")
+		cfg, err := ParseConfig("test", []string{}, &bytes.Buffer{}, algos)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !cfg.Energy || cfg.TDP != 45 {
+			t.Errorf("expected Energy=true TDP=45 from the environment, got %v %d", cfg.Energy, cfg.TDP)
+		}
+	})
+
+	t.Run("non-positive TDP", func(t *testing.T) {
+		if _, err := ParseConfig("test", []string{"--energy", "--tdp", "0"}, &bytes.Buffer{}, algos); err == nil {
+			t.Error("expected an error for --tdp 0 with --energy")
+		}
+	})
+}
+
+func TestParseConfigIndicators(t *testing.T) {
+	algos := []string{"fast", "matrix", "fft"}
+
+	for _, spec := range []string{"all", "list", "perf", "math,bit-throughput"} {
+		cfg, err := ParseConfig("test", []string{"--indicators", spec}, &bytes.Buffer{}, algos)
+		if err != nil {
+			t.Errorf("--indicators %s: unexpected error: %v", spec, err)
+		} else if cfg.Indicators != spec {
+			t.Errorf("expected Indicators=%q, got %q", spec, cfg.Indicators)
+		}
+	}
+	if _, err := ParseConfig("test", []string{"--indicators", "perf,nope"}, &bytes.Buffer{}, algos); err == nil {
+		t.Error("expected an error for an unknown indicator")
+	}
+
+	t.Setenv(EnvPrefix+"INDICATORS", "math")
+	cfg, err := ParseConfig("test", []string{}, &bytes.Buffer{}, algos)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Indicators != "math" {
+		t.Errorf("expected Indicators=math from FIBCALC_INDICATORS, got %q", cfg.Indicators)
+	}
+}
+
+func TestParseConfigNoTable(t *testing.T) {
+	algos := []string{"fast", "matrix", "fft"}
+
+	cfg, err := ParseConfig("test", []string{"--no-table"}, &bytes.Buffer{}, algos)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.NoTable {
+		t.Error("expected NoTable=true from --no-table")
+	}
+
+	t.Setenv(EnvPrefix+"NO_TABLE", "1")
+	cfg, err = ParseConfig("test", []string{}, &bytes.Buffer{}, algos)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.NoTable {
+		t.Error("expected NoTable=true from FIBCALC_NO_TABLE")
+	}
+}
+
+func TestParseConfigPriority(t *testing.T) {
+	algos := []string{"fast", "matrix", "fft"}
+
+	t.Run("flags", func(t *testing.T) {
+		cfg, err := ParseConfig("test", []string{"--nice", "10", "--ionice", "best-effort:7", "--background"}, &bytes.Buffer{}, algos)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Nice != 10 || cfg.IONice != "best-effort:7" || !cfg.Background {
+			t.Errorf("expected nice 10, ionice best-effort:7 and background, got %d, %q, %v", cfg.Nice, cfg.IONice, cfg.Background)
+		}
+	})
+
+	t.Run("environment", func(t *testing.T) {
+		t.Setenv(EnvPrefix+"NICE", "5")
+		t.Setenv(EnvPrefix+"IONICE", "idle")
+		t.Setenv(EnvPrefix+"BACKGROUND", "true")
+		cfg, err := ParseConfig("test", []string{}, &bytes.Buffer{}, algos)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Nice != 5 || cfg.IONice != "idle" || !cfg.Background {
+			t.Errorf("expected nice 5, ionice idle and background from the environment, got %d, %q, %v", cfg.Nice, cfg.IONice, cfg.Background)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, args := range [][]string{{"--nice", "20"}, {"--nice", "-21"}, {"--ionice", "slow"}, {"--ionice", "idle:2"}} {
+			if _, err := ParseConfig("test", args, &bytes.Buffer{}, algos); err == nil {
+				t.Errorf("expected an error for %v", args)
+			}
+		}
+	})
+}
+
+func TestParseConfigMetricsPush(t *testing.T) {
+	algos := []string{"fast", "matrix", "fft"}
+
+	t.Run("flag", func(t *testing.T) {
+		cfg, err := ParseConfig("test", []string{"--metrics-push", "http://localhost:4318/v1/metrics", "--metrics-push-format", "otlp", "--metrics-push-interval", "2s"}, &bytes.Buffer{}, algos)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.MetricsPush != "http://localhost:4318/v1/metrics" || cfg.MetricsPushFormat != "otlp" || cfg.MetricsPushInterval != 2*time.Second {
+			t.Errorf("unexpected metrics push settings: %q %q %s", cfg.MetricsPush, cfg.MetricsPushFormat, cfg.MetricsPushInterval)
+		}
+	})
+
+	t.Run("environment", func(t *testing.T) {
+		t.Setenv(EnvPrefix+"METRICS_PUSH", "http://influx:8086/api/v2/write?org=o&bucket=b")
+		t.Setenv(EnvPrefix+"METRICS_PUSH_INTERVAL", "30s")
+		cfg, err := ParseConfig("test", []string{}, &bytes.Buffer{}, algos)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.MetricsPush == "" || cfg.MetricsPushFormat != "influx" || cfg.MetricsPushInterval != 30*time.Second {
+			t.Errorf("unexpected metrics push settings from the environment: %q %q %s", cfg.MetricsPush, cfg.MetricsPushFormat, cfg.MetricsPushInterval)
+		}
+	})
+
+	for _, args := range [][]string{
+		{"--metrics-push", "localhost:8086"},
+		{"--metrics-push-format", "statsd"},
+		{"--metrics-push", "http://localhost:8086", "--metrics-push-interval", "0s"},
+	} {
+		if _, err := ParseConfig("test", args, &bytes.Buffer{}, algos); err == nil {
+			t.Errorf("%v: expected an error", args)
+		}
+	}
+}
+
+func TestParseConfigProgressPolicy(t *testing.T) {
+	algos := []string{"fast", "matrix", "fft"}
+
+	cfg, err := ParseConfig("test", []string{}, &bytes.Buffer{}, algos)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.ProgressPolicy != "drop" || cfg.ProgressTimeout != 50*time.Millisecond {
+		t.Errorf("unexpected defaults: %q %s", cfg.ProgressPolicy, cfg.ProgressTimeout)
+	}
+
+	t.Setenv(EnvPrefix+"PROGRESS_POLICY", "coalesce")
+	cfg, err = ParseConfig("test", []string{"--progress-timeout", "1s"}, &bytes.Buffer{}, algos)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.ProgressPolicy != "coalesce" || cfg.ProgressTimeout != time.Second {
+		t.Errorf("unexpected progress settings: %q %s", cfg.ProgressPolicy, cfg.ProgressTimeout)
+	}
+
+	for _, args := range [][]string{
+		{"--progress-policy", "latest"},
+		{"--progress-timeout", "-1s"},
+	} {
+		if _, err := ParseConfig("test", args, &bytes.Buffer{}, algos); err == nil {
+			t.Errorf("%v: expected an error", args)
+		}
+	}
+}
+
+func TestParseConfigTimeoutFactor(t *testing.T) {
+	algos := []string{"fast", "matrix", "fft"}
+
+	cfg, err := ParseConfig("test", []string{}, &bytes.Buffer{}, algos)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.TimeoutFactor != 0 {
+		t.Errorf("TimeoutFactor = %v by default, want 0", cfg.TimeoutFactor)
+	}
+
+	t.Setenv(EnvPrefix+"TIMEOUT_FACTOR", "2.5")
+	cfg, err = ParseConfig("test", []string{}, &bytes.Buffer{}, algos)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.TimeoutFactor != 2.5 {
+		t.Errorf("TimeoutFactor = %v from the environment, want 2.5", cfg.TimeoutFactor)
+	}
+
+	for _, factor := range []string{"-1", "NaN", "+Inf"} {
+		if _, err := ParseConfig("test", []string{"--timeout-factor", factor}, &bytes.Buffer{}, algos); err == nil {
+			t.Errorf("--timeout-factor %s: expected an error", factor)
+		}
+	}
+}
+
+func TestParseConfigExecOnComplete(t *testing.T) {
+	algos := []string{"fast", "matrix", "fft"}
+	cfg, err := ParseConfig("test", []string{"--exec-on-complete", "notify-send done"}, &bytes.Buffer{}, algos)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.ExecOnComplete != "notify-send done" {
+		t.Errorf("ExecOnComplete = %q, want the flag value", cfg.ExecOnComplete)
+	}
+
+	// The environment must not run a command
+	t.Setenv(EnvPrefix+"EXEC_ON_COMPLETE", "notify-send done")
+	if cfg, err := ParseConfig("test", []string{}, &bytes.Buffer{}, algos); err != nil || cfg.ExecOnComplete != "" {
+		t.Errorf("ExecOnComplete from the environment = %q, %v, want none", cfg.ExecOnComplete, err)
+	}
+
+	for _, args := range [][]string{
+		{"--exec-on-complete", "true", "--tui"},
+		{"--exec-on-complete", "true", "--calibrate"},
+		{"--exec-on-complete", "true", "--n-series", "10..20"},
+		{"--exec-on-complete", "true", "--no-write"},
+	} {
+		var errOut bytes.Buffer
+		if _, err := ParseConfig("test", args, &errOut, algos); err == nil || !strings.Contains(errOut.String(), "--exec-on-complete") {
+			t.Errorf("expected an --exec-on-complete error for %v, got %v:\n%s", args, err, errOut.String())
+		}
+	}
+}
+
+func TestParseConfigProfileReadOnly(t *testing.T) {
+	algos := []string{"fast", "matrix", "fft"}
+	cfg, err := ParseConfig("test", []string{"--profile-readonly"}, &bytes.Buffer{}, algos)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.ProfileReadOnly {
+		t.Error("ProfileReadOnly = false, want true with --profile-readonly")
+	}
+
+	t.Setenv(EnvPrefix+"PROFILE_READONLY", "true")
+	cfg, err = ParseConfig("test", []string{}, &bytes.Buffer{}, algos)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.ProfileReadOnly {
+		t.Error("ProfileReadOnly = false, want true from the environment")
+	}
+}
+
+func TestParseConfigNoWrite(t *testing.T) {
+	algos := []string{"fast", "matrix", "fft"}
+	cfg, err := ParseConfig("test", []string{"--no-write"}, &bytes.Buffer{}, algos)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.NoWrite || cfg.History || !cfg.ProfileReadOnly {
+		t.Errorf("NoWrite=%v History=%v ProfileReadOnly=%v, want true, false, true", cfg.NoWrite, cfg.History, cfg.ProfileReadOnly)
+	}
+
+	var errOut bytes.Buffer
+	_, err = ParseConfig("test", []string{"--no-write", "-o", "f.txt", "--audit-log", "audit.jsonl"}, &errOut, algos)
+	if err == nil || !strings.Contains(errOut.String(), "--no-write cannot be combined with --output, --audit-log") {
+		t.Errorf("expected a --no-write error naming --output and --audit-log, got %v:\n%s", err, errOut.String())
+	}
+
+	t.Setenv(EnvPrefix+"NO_WRITE", "true")
+	if cfg, err := ParseConfig("test", []string{}, &bytes.Buffer{}, algos); err != nil || !cfg.NoWrite {
+		t.Errorf("NoWrite from the environment = %v, %v, want true", cfg.NoWrite, err)
+	}
+}
+
+func TestParseConfigSoftRealtime(t *testing.T) {
+	algos := []string{"fast", "matrix", "fft"}
+	for _, args := range [][]string{{"--soft-realtime"}, {"--soft-realtime", "--gc-control", "realtime"}} {
+		if cfg, err := ParseConfig("test", args, &bytes.Buffer{}, algos); err != nil || !cfg.SoftRealtime {
+			t.Errorf("ParseConfig(%v) = SoftRealtime %v, %v, want true", args, cfg.SoftRealtime, err)
+		}
+	}
+
+	var errOut bytes.Buffer
+	_, err := ParseConfig("test", []string{"--soft-realtime", "--gc-control", "aggressive"}, &errOut, algos)
+	if err == nil || !strings.Contains(errOut.String(), "cannot be combined with --gc-control aggressive") {
+		t.Errorf("expected a --gc-control conflict, got %v:\n%s", err, errOut.String())
+	}
+
+	t.Setenv(EnvPrefix+"SOFT_REALTIME", "1")
+	if cfg, err := ParseConfig("test", []string{}, &bytes.Buffer{}, algos); err != nil || !cfg.SoftRealtime {
+		t.Errorf("SoftRealtime from the environment = %v, %v, want true", cfg.SoftRealtime, err)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"os"
 	"testing"
 	"time"
+
+	"github.com/agbru/fibcalc/internal/orchestration"
 )
 
 func TestParseConfig(t *testing.T) {
@@ -180,6 +182,14 @@ func TestConfigValidate(t *testing.T) {
 		}
 	})
 
+	t.Run("AutoAlgo", func(t *testing.T) {
+		t.Parallel()
+		c := AppConfig{Timeout: 1 * time.Second, Algo: AutoAlgo}
+		if err := c.Validate(availableAlgos); err != nil {
+			t.Errorf("'auto' should be accepted: %v", err)
+		}
+	})
+
 	t.Run("InvalidTimeout", func(t *testing.T) {
 		t.Parallel()
 		c := AppConfig{Timeout: 0, Threshold: 10, FFTThreshold: 10, Algo: "fast"}
@@ -219,6 +229,26 @@ func TestConfigValidate(t *testing.T) {
 			t.Error("Algo 'all' should be valid")
 		}
 	})
+
+	t.Run("CompareMode", func(t *testing.T) {
+		t.Parallel()
+		for _, mode := range []string{"", "parallel", "sequential", "staggered"} {
+			c := AppConfig{Timeout: 1 * time.Second, Algo: "all", CompareMode: mode}
+			if err := c.Validate(availableAlgos); err != nil {
+				t.Errorf("Compare mode %q should be valid: %v", mode, err)
+			}
+		}
+		for _, mode := range orchestration.CompareModes {
+			c := AppConfig{Timeout: 1 * time.Second, Algo: "all", CompareMode: string(mode)}
+			if err := c.Validate(availableAlgos); err != nil {
+				t.Errorf("Orchestration compare mode %q should be valid: %v", mode, err)
+			}
+		}
+		c := AppConfig{Timeout: 1 * time.Second, Algo: "all", CompareMode: "random"}
+		if err := c.Validate(availableAlgos); err == nil {
+			t.Error("Expected error for unknown compare mode")
+		}
+	})
 }
 
 func TestEnvHelpers(t *testing.T) {
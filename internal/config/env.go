@@ -115,6 +115,11 @@ var envOverrides = []envOverride{
 			c.N = parsed
 		}
 	}},
+	{"SEED", []string{"seed"}, func(c *AppConfig, v string) {
+		if parsed, err := strconv.ParseInt(v, 10, 64); err == nil {
+			c.Seed = parsed
+		}
+	}},
 	{"THRESHOLD", []string{"threshold"}, func(c *AppConfig, v string) {
 		if parsed, err := strconv.Atoi(v); err == nil {
 			c.Threshold = parsed
@@ -137,20 +142,65 @@ var envOverrides = []envOverride{
 			c.Timeout = parsed
 		}
 	}},
+	{"TIMEOUT_FACTOR", []string{"timeout-factor"}, func(c *AppConfig, v string) {
+		if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+			c.TimeoutFactor = parsed
+		}
+	}},
+	{"STALL_FACTOR", []string{"stall-factor"}, func(c *AppConfig, v string) {
+		if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+			c.StallFactor = parsed
+		}
+	}},
 
 	// String overrides
 	{"ALGO", []string{"algo"}, func(c *AppConfig, v string) {
 		c.Algo = v
 	}},
+	{"FALLBACK", []string{"fallback"}, func(c *AppConfig, v string) {
+		c.Fallback = v
+	}},
+	{"N_SERIES", []string{"n-series"}, func(c *AppConfig, v string) {
+		c.NSeries = v
+	}},
 	{"OUTPUT", []string{"output", "o"}, func(c *AppConfig, v string) {
 		c.OutputFile = v
 	}},
+	{"SIGN_KEY", []string{"sign-key"}, func(c *AppConfig, v string) {
+		c.SignKey = v
+	}},
 	{"CALIBRATION_PROFILE", []string{"calibration-profile"}, func(c *AppConfig, v string) {
 		c.CalibrationProfile = v
 	}},
+	{"PROFILE_READONLY", []string{"profile-readonly"}, func(c *AppConfig, v string) {
+		c.ProfileReadOnly = parseBoolEnv(v, c.ProfileReadOnly)
+	}},
 	{"MEMORY_LIMIT", []string{"memory-limit"}, func(c *AppConfig, v string) {
 		c.MemoryLimit = v
 	}},
+	{"SPILL_THRESHOLD", []string{"spill-threshold"}, func(c *AppConfig, v string) {
+		c.SpillThreshold = v
+	}},
+	{"SPILL_DIR", []string{"spill-dir"}, func(c *AppConfig, v string) {
+		c.SpillDir = v
+	}},
+	{"COMPARE_MODE", []string{"compare-mode"}, func(c *AppConfig, v string) {
+		c.CompareMode = v
+	}},
+	{"AUDIT_LOG", []string{"audit-log"}, func(c *AppConfig, v string) {
+		c.AuditLog = v
+	}},
+	{"LOG_FILE", []string{"log-file"}, func(c *AppConfig, v string) {
+		c.LogFile = v
+	}},
+	{"SESSION_POOL", []string{"session-pool"}, func(c *AppConfig, v string) {
+		c.SessionPool = v
+	}},
+	{"MEMORY_PRESSURE", []string{"memory-pressure"}, func(c *AppConfig, v string) {
+		if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+			c.MemoryPressure = parsed
+		}
+	}},
 
 	// Boolean overrides
 	{"VERBOSE", []string{"v", "verbose"}, func(c *AppConfig, v string) {
@@ -162,18 +212,170 @@ var envOverrides = []envOverride{
 	{"QUIET", []string{"quiet", "q"}, func(c *AppConfig, v string) {
 		c.Quiet = parseBoolEnv(v, c.Quiet)
 	}},
+	{"MACHINE", []string{"machine"}, func(c *AppConfig, v string) {
+		c.Machine = parseBoolEnv(v, c.Machine)
+	}},
+	{"GHA_SUMMARY", []string{"gha-summary"}, func(c *AppConfig, v string) {
+		c.GHASummary = parseBoolEnv(v, c.GHASummary)
+	}},
+	{"EXPLAIN", []string{"explain"}, func(c *AppConfig, v string) {
+		c.Explain = parseBoolEnv(v, c.Explain)
+	}},
+	{"OEIS", []string{"oeis"}, func(c *AppConfig, v string) {
+		c.OEIS = parseBoolEnv(v, c.OEIS)
+	}},
+	{"STALL_ABORT", []string{"stall-abort"}, func(c *AppConfig, v string) {
+		c.StallAbort = parseBoolEnv(v, c.StallAbort)
+	}},
 	{"CALIBRATE", []string{"calibrate"}, func(c *AppConfig, v string) {
 		c.Calibrate = parseBoolEnv(v, c.Calibrate)
 	}},
 	{"AUTO_CALIBRATE", []string{"auto-calibrate"}, func(c *AppConfig, v string) {
 		c.AutoCalibrate = parseBoolEnv(v, c.AutoCalibrate)
 	}},
+	{"WATCH", []string{"watch"}, func(c *AppConfig, v string) {
+		c.Watch = parseBoolEnv(v, c.Watch)
+	}},
 	{"CALCULATE", []string{"calculate", "c"}, func(c *AppConfig, v string) {
 		c.ShowValue = parseBoolEnv(v, c.ShowValue)
 	}},
 	{"TUI", []string{"tui"}, func(c *AppConfig, v string) {
 		c.TUI = parseBoolEnv(v, c.TUI)
 	}},
+	{"HISTORY", []string{"history"}, func(c *AppConfig, v string) {
+		c.History = parseBoolEnv(v, c.History)
+	}},
+	{"HISTORY_FILE", []string{"history-file"}, func(c *AppConfig, v string) {
+		c.HistoryFile = v
+	}},
+	{"NO_WRITE", []string{"no-write"}, func(c *AppConfig, v string) {
+		c.NoWrite = parseBoolEnv(v, c.NoWrite)
+	}},
+	{"SOFT_REALTIME", []string{"soft-realtime"}, func(c *AppConfig, v string) {
+		c.SoftRealtime = parseBoolEnv(v, c.SoftRealtime)
+	}},
+	{"PROGRESS_POLICY", []string{"progress-policy"}, func(c *AppConfig, v string) {
+		c.ProgressPolicy = v
+	}},
+	{"PROGRESS_TIMEOUT", []string{"progress-timeout"}, func(c *AppConfig, v string) {
+		if parsed, err := time.ParseDuration(v); err == nil {
+			c.ProgressTimeout = parsed
+		}
+	}},
+	{"PROGRESS_REFRESH", []string{"progress-refresh"}, func(c *AppConfig, v string) {
+		if parsed, err := time.ParseDuration(v); err == nil {
+			c.ProgressRefresh = parsed
+		}
+	}},
+	{"TUI_REFRESH", []string{"tui-refresh"}, func(c *AppConfig, v string) {
+		if parsed, err := time.ParseDuration(v); err == nil {
+			c.TUIRefresh = parsed
+		}
+	}},
+	{"TUI_IDLE", []string{"tui-idle"}, func(c *AppConfig, v string) {
+		if parsed, err := time.ParseDuration(v); err == nil {
+			c.TUIIdle = parsed
+		}
+	}},
+	{"METRICS_PUSH", []string{"metrics-push"}, func(c *AppConfig, v string) {
+		c.MetricsPush = v
+	}},
+	{"METRICS_PUSH_FORMAT", []string{"metrics-push-format"}, func(c *AppConfig, v string) {
+		c.MetricsPushFormat = v
+	}},
+	{"METRICS_PUSH_INTERVAL", []string{"metrics-push-interval"}, func(c *AppConfig, v string) {
+		if parsed, err := time.ParseDuration(v); err == nil {
+			c.MetricsPushInterval = parsed
+		}
+	}},
+	{"TELEMETRY", []string{"telemetry"}, func(c *AppConfig, v string) {
+		c.Telemetry = parseBoolEnv(v, c.Telemetry)
+	}},
+	{"TELEMETRY_ENDPOINT", []string{"telemetry-endpoint"}, func(c *AppConfig, v string) {
+		c.TelemetryEndpoint = v
+	}},
+	{"PERF_COUNTERS", []string{"perf-counters"}, func(c *AppConfig, v string) {
+		c.PerfCounters = parseBoolEnv(v, c.PerfCounters)
+	}},
+	{"RUNTIME_TRACE", []string{"runtime-trace"}, func(c *AppConfig, v string) {
+		c.RuntimeTrace = v
+	}},
+	{"ENERGY", []string{"energy"}, func(c *AppConfig, v string) {
+		c.Energy = parseBoolEnv(v, c.Energy)
+	}},
+	{"TDP", []string{"tdp"}, func(c *AppConfig, v string) {
+		if parsed, err := strconv.Atoi(v); err == nil {
+			c.TDP = parsed
+		}
+	}},
+	{"INDICATORS", []string{"indicators"}, func(c *AppConfig, v string) {
+		c.Indicators = v
+	}},
+	{"NO_TABLE", []string{"no-table"}, func(c *AppConfig, v string) {
+		c.NoTable = parseBoolEnv(v, c.NoTable)
+	}},
+	{"PARANOID", []string{"paranoid"}, func(c *AppConfig, v string) {
+		c.Paranoid = parseBoolEnv(v, c.Paranoid)
+	}},
+	{"ADAPTIVE_PARALLELISM", []string{"adaptive-parallelism"}, func(c *AppConfig, v string) {
+		c.AdaptiveParallelism = parseBoolEnv(v, c.AdaptiveParallelism)
+	}},
+	{"BASELINE", []string{"baseline"}, func(c *AppConfig, v string) {
+		c.Baseline = v
+	}},
+	{"ASCII", []string{"ascii"}, func(c *AppConfig, v string) {
+		c.ASCII = parseBoolEnv(v, c.ASCII)
+	}},
+	{"TRUNCATE_AT", []string{"truncate-at"}, func(c *AppConfig, v string) {
+		if parsed, err := strconv.Atoi(v); err == nil {
+			c.TruncateAt = parsed
+		}
+	}},
+	{"EDGE_DIGITS", []string{"edge-digits"}, func(c *AppConfig, v string) {
+		if parsed, err := strconv.Atoi(v); err == nil {
+			c.EdgeDigits = parsed
+		}
+	}},
+	{"PAGER_AT", []string{"pager-at"}, func(c *AppConfig, v string) {
+		if parsed, err := strconv.Atoi(v); err == nil {
+			c.PagerAt = parsed
+		}
+	}},
+	{"WRAP", []string{"wrap"}, func(c *AppConfig, v string) {
+		if parsed, err := strconv.Atoi(v); err == nil {
+			c.Wrap = parsed
+		}
+	}},
+	{"DURATION_FORMAT", []string{"duration-format"}, func(c *AppConfig, v string) {
+		c.DurationFormat = v
+	}},
+	{"DURATION_PRECISION", []string{"duration-precision"}, func(c *AppConfig, v string) {
+		if parsed, err := strconv.Atoi(v); err == nil {
+			c.DurationPrecision = parsed
+		}
+	}},
+	{"DURATION_LOCALE", []string{"duration-locale"}, func(c *AppConfig, v string) {
+		c.DurationLocale = v
+	}},
+	{"QUIET_DURATION", []string{"quiet-duration"}, func(c *AppConfig, v string) {
+		c.QuietDuration = v
+	}},
+	{"WARMUP", []string{"warmup"}, func(c *AppConfig, v string) {
+		if parsed, err := strconv.Atoi(v); err == nil {
+			c.Warmup = parsed
+		}
+	}},
+	{"NICE", []string{"nice"}, func(c *AppConfig, v string) {
+		if parsed, err := strconv.Atoi(v); err == nil {
+			c.Nice = parsed
+		}
+	}},
+	{"IONICE", []string{"ionice"}, func(c *AppConfig, v string) {
+		c.IONice = v
+	}},
+	{"BACKGROUND", []string{"background"}, func(c *AppConfig, v string) {
+		c.Background = parseBoolEnv(v, c.Background)
+	}},
 }
 
 // parseBoolEnv parses a boolean environment variable value.
@@ -196,14 +398,26 @@ func parseBoolEnv(val string, defaultVal bool) bool {
 // Supported environment variables (all prefixed with FIBCALC_):
 //   - N, ALGO, TIMEOUT, THRESHOLD, FFT_THRESHOLD, STRASSEN_THRESHOLD,
 //     VERBOSE, DETAILS, QUIET, CALIBRATE, AUTO_CALIBRATE, CALCULATE,
-//     OUTPUT, CALIBRATION_PROFILE, MEMORY_LIMIT, TUI
+//     OUTPUT, CALIBRATION_PROFILE, MEMORY_LIMIT, COMPARE_MODE, AUDIT_LOG, TUI,
+//     PERF_COUNTERS, SEED, BASELINE, ASCII, TRUNCATE_AT, EDGE_DIGITS,
+//     WARMUP, NO_TABLE, NICE, IONICE, BACKGROUND, ENERGY, TDP,
+//     INDICATORS, HISTORY, HISTORY_FILE, METRICS_PUSH, METRICS_PUSH_FORMAT,
+//     METRICS_PUSH_INTERVAL, PROGRESS_POLICY, PROGRESS_TIMEOUT, TIMEOUT_FACTOR,
+//     PARANOID, SIGN_KEY, ADAPTIVE_PARALLELISM, SPILL_THRESHOLD, SPILL_DIR,
+//     LOG_FILE, SESSION_POOL, DURATION_FORMAT, DURATION_PRECISION,
+//     DURATION_LOCALE, MEMORY_PRESSURE, WATCH, MACHINE, N_SERIES, FALLBACK,
+//     PROGRESS_REFRESH, TUI_REFRESH, TUI_IDLE, TELEMETRY, TELEMETRY_ENDPOINT,
+//     GHA_SUMMARY, PAGER_AT, WRAP, OEIS, EXPLAIN, STALL_FACTOR, STALL_ABORT,
+//     RUNTIME_TRACE, QUIET_DURATION, PROFILE_READONLY, NO_WRITE,
+//     SOFT_REALTIME
 func applyEnvOverrides(config *AppConfig, fs *flag.FlagSet) {
 	for _, o := range envOverrides {
-		if isFlagSetAny(fs, o.flags...) {
+		// Checked in this order since fs.Visit sorts the flags on every
+		// call, and few of the variables are usually set
+		val := os.Getenv(EnvPrefix + o.envKey)
+		if val == "" || isFlagSetAny(fs, o.flags...) {
 			continue
 		}
-		if val := os.Getenv(EnvPrefix + o.envKey); val != "" {
-			o.apply(config, val)
-		}
+		o.apply(config, val)
 	}
 }
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+)
+
+// EnvStatus describes a FIBCALC_* environment variable for `fibcalc env`.
+type EnvStatus struct {
+	// Name is the full variable name (e.g. "FIBCALC_N").
+	Name string
+	// Flags lists the flags the variable stands for (nil if unknown).
+	Flags []string
+	// Value is the variable's value ("" if unset).
+	Value string
+	// Set reports whether the variable has a non-empty value. Empty
+	// variables are ignored, like unset ones.
+	Set bool
+	// Overridden reports whether one of Flags was given on the command line,
+	// which takes precedence over the variable.
+	Overridden bool
+	// Err explains why the value is rejected, nil if it is valid or unset.
+	// Invalid values are otherwise ignored silently by ParseConfig.
+	Err error
+}
+
+// CheckEnv reports the status of every supported FIBCALC_* variable, in the
+// order of envOverrides, followed by the unknown FIBCALC_* variables found
+// in the environment.
+//
+// Each value is checked on its own: it must parse as the type of its flag,
+// and applying it to the configuration given by args (or to the defaults, if
+// args alone are invalid) must pass Validate.
+//
+// Parameters:
+//   - args: The command-line flags, to tell which variables they override.
+//   - availableAlgos: The valid algorithm names, for validation.
+//
+// Returns:
+//   - []EnvStatus: The status of each variable.
+//   - error: A ConfigError if args cannot be parsed.
+func CheckEnv(args []string, availableAlgos []string) ([]EnvStatus, error) {
+	base := AppConfig{}
+	fs := newFlagSet("fibcalc env", &base, availableAlgos)
+	fs.SetOutput(io.Discard)
+	if err := fs.Parse(args); err != nil {
+		if msg := unknownFlagError(fs, err); msg != "" {
+			return nil, apperrors.NewConfigError("%s", msg)
+		}
+		return nil, apperrors.NewConfigError("%v", err)
+	}
+	normalize(&base)
+	if base.Validate(availableAlgos) != nil {
+		base = defaultConfig(availableAlgos)
+	}
+
+	statuses := make([]EnvStatus, 0, len(envOverrides))
+	keys := make([]string, 0, len(envOverrides))
+	for _, o := range envOverrides {
+		name := EnvPrefix + o.envKey
+		keys = append(keys, o.envKey)
+		value := os.Getenv(name)
+		st := EnvStatus{
+			Name:       name,
+			Flags:      o.flags,
+			Value:      value,
+			Set:        value != "",
+			Overridden: isFlagSetAny(fs, o.flags...),
+		}
+		if st.Set {
+			st.Err = checkEnvValue(o, value, base, availableAlgos)
+		}
+		statuses = append(statuses, st)
+	}
+
+	var unknown []EnvStatus
+	for _, kv := range os.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+		key, found := strings.CutPrefix(name, EnvPrefix)
+		if !found || slices.Contains(keys, key) {
+			continue
+		}
+		msg := "unknown variable"
+		if hint := didYouMean(suggest(key, keys), EnvPrefix+"%s"); hint != "" {
+			msg += ". " + hint
+		}
+		unknown = append(unknown, EnvStatus{Name: name, Value: value, Set: value != "", Err: errors.New(msg)})
+	}
+	slices.SortFunc(unknown, func(a, b EnvStatus) int { return strings.Compare(a.Name, b.Name) })
+	return append(statuses, unknown...), nil
+}
+
+// checkEnvValue validates the value of an environment override: it must
+// parse as the type of the override's flag, and applying it to base must
+// leave a valid configuration.
+func checkEnvValue(o envOverride, value string, base AppConfig, availableAlgos []string) error {
+	f := newFlagSet("fibcalc env", &AppConfig{}, availableAlgos).Lookup(o.flags[0])
+	if bf, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && bf.IsBoolFlag() {
+		if parseBoolEnv(value, true) != parseBoolEnv(value, false) {
+			return fmt.Errorf("invalid boolean %q (use true/false, 1/0 or yes/no)", value)
+		}
+	} else if err := f.Value.Set(value); err != nil {
+		kind, _ := flag.UnquoteUsage(f)
+		return fmt.Errorf("invalid %s %q", kind, value)
+	}
+
+	c := base
+	o.apply(&c, value)
+	normalize(&c)
+	return c.Validate(availableAlgos)
+}
+
+// defaultConfig returns the configuration with every flag at its default.
+func defaultConfig(availableAlgos []string) AppConfig {
+	c := AppConfig{}
+	newFlagSet("fibcalc", &c, availableAlgos)
+	return c
+}
+
+// WriteEnvReport writes the statuses from CheckEnv as a table.
+//
+// Parameters:
+//   - w: The writer to write the table to.
+//   - statuses: The statuses to list.
+//
+// Returns:
+//   - bool: True if every variable that is set is valid.
+func WriteEnvReport(w io.Writer, statuses []EnvStatus) bool {
+	ok := true
+	fmt.Fprintf(w, "%-28s %-20s %-22s %s\n", "VARIABLE", "VALUE", "FLAG", "STATUS")
+	for _, st := range statuses {
+		value := st.Value
+		if !st.Set {
+			value = "(unset)"
+		} else if r := []rune(value); len(r) > 20 {
+			// By rune, not to split a multi-byte character
+			value = string(r[:17]) + "..."
+		}
+
+		flags := "-"
+		if len(st.Flags) > 0 {
+			flags = FlagInfo{Names: st.Flags}.Signature()
+		}
+
+		var status string
+		switch {
+		case st.Err != nil && st.Flags == nil:
+			status = st.Err.Error()
+			ok = false
+		case st.Err != nil:
+			status = "invalid: " + st.Err.Error()
+			ok = false
+		case !st.Set:
+			status = "-"
+		case st.Overridden:
+			status = "ok (overridden by flag)"
+		default:
+			status = "ok"
+		}
+		fmt.Fprintf(w, "%-28s %-20s %-22s %s\n", st.Name, value, flags, status)
+	}
+	return ok
+}
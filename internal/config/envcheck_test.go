@@ -0,0 +1,119 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// statusOf returns the status of the variable name, failing if it is absent.
+func statusOf(t *testing.T, statuses []EnvStatus, name string) EnvStatus {
+	t.Helper()
+	for _, st := range statuses {
+		if st.Name == name {
+			return st
+		}
+	}
+	t.Fatalf("no status for %s", name)
+	return EnvStatus{}
+}
+
+func TestCheckEnv(t *testing.T) {
+	algos := []string{"fast", "matrix"}
+	t.Setenv("FIBCALC_N", "1000")
+	t.Setenv("FIBCALC_ALGO", "fats")
+	t.Setenv("FIBCALC_TIMEOUT", "soon")
+	t.Setenv("FIBCALC_QUIET", "yes")
+	t.Setenv("FIBCALC_VERBOSE", "maybe")
+	t.Setenv("FIBCALC_ALOG", "fast")
+	t.Setenv("FIBCALC_THRESHOLD", "")
+
+	statuses, err := CheckEnv([]string{"-q"}, algos)
+	if err != nil {
+		t.Fatalf("CheckEnv: %v", err)
+	}
+	if len(statuses) != len(envOverrides)+1 {
+		t.Errorf("got %d statuses, want %d", len(statuses), len(envOverrides)+1)
+	}
+
+	if st := statusOf(t, statuses, "FIBCALC_N"); !st.Set || st.Overridden || st.Err != nil {
+		t.Errorf("FIBCALC_N = %+v, want set, not overridden, valid", st)
+	}
+	if st := statusOf(t, statuses, "FIBCALC_QUIET"); !st.Overridden || st.Err != nil {
+		t.Errorf("FIBCALC_QUIET = %+v, want overridden by -q and valid", st)
+	}
+	if st := statusOf(t, statuses, "FIBCALC_THRESHOLD"); st.Set || st.Err != nil {
+		t.Errorf("empty FIBCALC_THRESHOLD = %+v, want unset", st)
+	}
+	if st := statusOf(t, statuses, "FIBCALC_ALGO"); st.Err == nil || !strings.Contains(st.Err.Error(), "Did you mean 'fast'?") {
+		t.Errorf("FIBCALC_ALGO error = %v, want a suggestion", st.Err)
+	}
+	if st := statusOf(t, statuses, "FIBCALC_TIMEOUT"); st.Err == nil || !strings.Contains(st.Err.Error(), "invalid duration") {
+		t.Errorf("FIBCALC_TIMEOUT error = %v, want an invalid duration", st.Err)
+	}
+	if st := statusOf(t, statuses, "FIBCALC_VERBOSE"); st.Err == nil {
+		t.Error("FIBCALC_VERBOSE=maybe should be invalid")
+	}
+	if st := statusOf(t, statuses, "FIBCALC_ALOG"); st.Err == nil || !strings.Contains(st.Err.Error(), "FIBCALC_ALGO") {
+		t.Errorf("FIBCALC_ALOG error = %v, want unknown with a suggestion", st.Err)
+	}
+}
+
+// TestCheckEnvUsesFlags verifies that values are validated together with
+// the flags given on the command line.
+func TestCheckEnvUsesFlags(t *testing.T) {
+	t.Setenv("FIBCALC_N", "2000000000")
+
+	statuses, err := CheckEnv(nil, []string{"fast"})
+	if err != nil {
+		t.Fatalf("CheckEnv: %v", err)
+	}
+	if st := statusOf(t, statuses, "FIBCALC_N"); st.Err == nil {
+		t.Error("FIBCALC_N above the safety limit should be invalid without --force")
+	}
+
+	statuses, err = CheckEnv([]string{"--force"}, []string{"fast"})
+	if err != nil {
+		t.Fatalf("CheckEnv: %v", err)
+	}
+	if st := statusOf(t, statuses, "FIBCALC_N"); st.Err != nil {
+		t.Errorf("FIBCALC_N with --force: %v", st.Err)
+	}
+
+	if _, err := CheckEnv([]string{"--nope"}, []string{"fast"}); err == nil {
+		t.Error("CheckEnv should reject unknown flags")
+	}
+}
+
+func TestWriteEnvReport(t *testing.T) {
+	t.Parallel()
+	statuses := []EnvStatus{
+		{Name: "FIBCALC_N", Flags: []string{"n"}, Value: "10", Set: true},
+		{Name: "FIBCALC_QUIET", Flags: []string{"quiet", "q"}, Value: "1", Set: true, Overridden: true},
+		{Name: "FIBCALC_ALGO", Flags: []string{"algo"}},
+	}
+	var buf bytes.Buffer
+	if !WriteEnvReport(&buf, statuses) {
+		t.Error("WriteEnvReport reported invalid variables")
+	}
+	out := buf.String()
+	for _, want := range []string{"VARIABLE", "-q, --quiet", "ok (overridden by flag)", "(unset)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report does not contain %q:\n%s", want, out)
+		}
+	}
+
+	// Long values are shortened by rune, keeping multi-byte characters whole
+	buf.Reset()
+	WriteEnvReport(&buf, []EnvStatus{{Name: "FIBCALC_LOG_FILE", Value: "/tmp/résumé-écrit-été.log", Set: true}})
+	if !utf8.Valid(buf.Bytes()) || !strings.Contains(buf.String(), "/tmp/résumé-écrit...") {
+		t.Errorf("long value not shortened by rune:\n%s", buf.String())
+	}
+
+	statuses = append(statuses, EnvStatus{Name: "FIBCALC_X", Value: "1", Set: true, Err: errors.New("unknown variable")})
+	if WriteEnvReport(&bytes.Buffer{}, statuses) {
+		t.Error("WriteEnvReport should report an invalid variable")
+	}
+}
@@ -0,0 +1,320 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+)
+
+// FlagInfo describes a command-line flag for the full help and the man page.
+type FlagInfo struct {
+	// Names lists the flag's names, the one whose usage is shown first.
+	Names []string
+	// Value is the placeholder of the flag's value ("" for boolean flags).
+	Value string
+	// Usage is the description given to the flag package.
+	Usage string
+	// Default is the default value, "" when it is not worth showing.
+	Default string
+	// EnvVar is the environment variable that sets the flag, if any.
+	EnvVar string
+}
+
+// FlagGroup is a titled group of related flags.
+type FlagGroup struct {
+	Title string
+	Flags []FlagInfo
+}
+
+// CommandInfo describes a subcommand (fibcalc <name> ...).
+type CommandInfo struct {
+	Name    string
+	Args    string
+	Summary string
+}
+
+// Commands lists the subcommands handled before flag parsing.
+var Commands = []CommandInfo{
+	{"install-manpages", "[--dir DIR]", "Install the fibcalc(1) man page (default: ~/.local/share/man/man1, or /usr/local/share/man/man1 as root)."},
+	{"env", "[flags]", "List the FIBCALC_* variables with their values, whether the given flags override them and whether they are valid. Exits with 4 if one is not."},
+	{"digits", "[--last K | --first K] [-q] N", "Print the last K decimal digits of F(N) (default 20) with modular arithmetic, in milliseconds for any N, or with --first the first K, bounded rigorously with interval arithmetic (N up to 3e9). This is partial output, not the full value."},
+	{"history", "[--since AGE] [--algo NAME] [-n N] [--sort ORDER] [--limit K] [--failed] [--json]", "List past runs recorded in the history database (~/.fibcalc_history.jsonl), newest first or sorted by duration or n, to follow performance over time and across versions."},
+	{"algos", "[--json | --markdown]", "List the registered algorithms with the metadata each one declares: complexity, memory profile, parallelism and the range of n it is recommended for, which --algo auto chooses from. --markdown prints the table of docs/algorithms/COMPARISON.md."},
+	{"capabilities", "[--json]", "Report which optional subsystems are built into this binary and available on this machine (SIMD, GMP, hardware and energy counters, memory-mapped buffers), for support scripts."},
+	{"crosscheck", "-n N --external CMD [--algo NAME] [--timeout D]", "Run an external program printing F(N) ({n} in CMD is replaced by N) and compare its output with fibcalc's result, listing the positions of differing digits, to validate a migration from another tool. Digit grouping, an \"F(n) =\" prefix and 0x hexadecimal are accepted. Exits with 3 on a mismatch."},
+	{"plot", "FILE [--svg OUT] [--width W] [--height H]", "Chart the duration of each algorithm against n from a file of --machine documents (- for stdin), such as the output of --n-series with --algo all and --machine, on log-log axes: as text in the terminal, or with --svg as an SVG file. Lists the crossovers, where one algorithm overtakes another."},
+	{"telemetry", "status|off", "Show whether anonymous performance reports are enabled (opt in with --telemetry) and where they go, or stop them."},
+	{"verify-signature", "[--key PUBKEY] FILE...", "Check the signatures of result files and reports written with --sign-key: the value matches the signed digest, the metadata is unchanged and, with --key, the signer is that key. Exits with 3 if a file does not verify."},
+}
+
+// flagEntry places a flag in a group. names[0] is the flag whose usage is
+// shown; value is the placeholder of the flag's value, "" for booleans.
+type flagEntry struct {
+	names []string
+	value string
+}
+
+// flagLayout groups the flags defined by newFlagSet for the full help and
+// the man page. Every visible flag must appear exactly once.
+var flagLayout = []struct {
+	title   string
+	entries []flagEntry
+}{
+	{"Calculation", []flagEntry{
+		{[]string{"n"}, "N"},
+		{[]string{"algo"}, "NAME"},
+		{[]string{"fallback"}, "LIST"},
+		{[]string{"timeout"}, "DURATION"},
+		{[]string{"timeout-factor"}, "FACTOR"},
+		{[]string{"stall-factor"}, "FACTOR"},
+		{[]string{"stall-abort"}, ""},
+		{[]string{"n-series"}, "SPEC"},
+		{[]string{"last-digits"}, "K"},
+		{[]string{"compare-mode"}, "MODE"},
+		{[]string{"force"}, ""},
+	}},
+	{"Output", []flagEntry{
+		{[]string{"calculate", "c"}, ""},
+		{[]string{"v", "verbose"}, ""},
+		{[]string{"d", "details"}, ""},
+		{[]string{"indicators"}, "SPEC"},
+		{[]string{"quiet", "q"}, ""},
+		{[]string{"machine"}, ""},
+		{[]string{"machine-schema"}, ""},
+		{[]string{"gha-summary"}, ""},
+		{[]string{"exec-on-complete"}, "CMD"},
+		{[]string{"oeis"}, ""},
+		{[]string{"explain"}, ""},
+		{[]string{"output", "o"}, "FILE"},
+		{[]string{"sign-key"}, "FILE"},
+		{[]string{"truncate-at"}, "DIGITS"},
+		{[]string{"edge-digits"}, "DIGITS"},
+		{[]string{"pager-at"}, "DIGITS"},
+		{[]string{"wrap"}, "DIGITS"},
+		{[]string{"duration-format"}, "STYLE"},
+		{[]string{"duration-precision"}, "N"},
+		{[]string{"duration-locale"}, "LOCALE"},
+		{[]string{"quiet-duration"}, "UNIT"},
+		{[]string{"tui"}, ""},
+		{[]string{"log-file"}, "FILE"},
+		{[]string{"progress-policy"}, "POLICY"},
+		{[]string{"progress-timeout"}, "DURATION"},
+		{[]string{"progress-refresh"}, "DURATION"},
+		{[]string{"tui-refresh"}, "DURATION"},
+		{[]string{"tui-idle"}, "DURATION"},
+		{[]string{"baseline"}, "FILE"},
+		{[]string{"ascii"}, ""},
+		{[]string{"audit-log"}, "FILE"},
+		{[]string{"history"}, ""},
+		{[]string{"history-file"}, "FILE"},
+		{[]string{"no-write"}, ""},
+		{[]string{"metrics-push"}, "URL"},
+		{[]string{"metrics-push-format"}, "FORMAT"},
+		{[]string{"metrics-push-interval"}, "DURATION"},
+		{[]string{"telemetry"}, ""},
+		{[]string{"telemetry-endpoint"}, "URL"},
+	}},
+	{"Performance tuning", []flagEntry{
+		{[]string{"threshold"}, "BITS"},
+		{[]string{"fft-threshold"}, "BITS"},
+		{[]string{"strassen-threshold"}, "BITS"},
+		{[]string{"calibrate"}, ""},
+		{[]string{"auto-calibrate"}, ""},
+		{[]string{"calibration-profile"}, "FILE"},
+		{[]string{"profile-readonly"}, ""},
+		{[]string{"watch"}, ""},
+		{[]string{"seed"}, "SEED"},
+		{[]string{"max-goroutines"}, "N"},
+		{[]string{"adaptive-parallelism"}, ""},
+		{[]string{"memory-limit"}, "SIZE"},
+		{[]string{"spill-threshold"}, "SIZE"},
+		{[]string{"spill-dir"}, "DIR"},
+		{[]string{"session-pool"}, "SIZE"},
+		{[]string{"memory-pressure"}, "FRACTION"},
+		{[]string{"gc-control"}, "MODE"},
+		{[]string{"soft-realtime"}, ""},
+		{[]string{"perf-counters"}, ""},
+		{[]string{"runtime-trace"}, "FILE[:TIME]"},
+		{[]string{"energy"}, ""},
+		{[]string{"tdp"}, "WATTS"},
+		{[]string{"warmup"}, "K"},
+		{[]string{"no-table"}, ""},
+		{[]string{"paranoid"}, ""},
+		{[]string{"nice"}, "N"},
+		{[]string{"ionice"}, "CLASS[:LEVEL]"},
+		{[]string{"background"}, ""},
+	}},
+	{"Miscellaneous", []flagEntry{
+		{[]string{"completion"}, "SHELL"},
+		{[]string{"help-full"}, ""},
+		{[]string{"help", "h"}, ""},
+		{[]string{"version", "V"}, ""},
+	}},
+}
+
+// builtinFlags describes the flags handled outside newFlagSet: -h by the
+// flag package and -V by the entry point.
+var builtinFlags = map[string]string{
+	"help":    "Show the usage message.",
+	"version": "Print version information.",
+}
+
+// helpExamples lists example invocations with what they do.
+var helpExamples = []struct{ command, summary string }{
+	{"fibcalc -n 1000000", "Compute F(1,000,000) with every algorithm and compare them."},
+	{"fibcalc -n 10000000 --algo fast -c", "Compute F(10,000,000) with fast doubling and print the value."},
+	{"fibcalc -n 100000000000 --last-digits 20", "Print the last 20 digits of F(10^11) in O(20) memory."},
+	{`fibcalc --n-series "10^6..10^7 step x2" --algo all`, "Time every algorithm at 1M, 2M, 4M and 8M and fit how they scale."},
+	{"fibcalc -n 50000000 --tui", "Follow a calculation in the interactive dashboard."},
+	{"fibcalc --calibrate", "Measure the optimal thresholds for this machine and save them."},
+	{"fibcalc --completion bash > /etc/bash_completion.d/fibcalc", "Install bash completion."},
+}
+
+// exitCodes documents the process exit codes.
+var exitCodes = []struct {
+	code    int
+	meaning string
+}{
+	{apperrors.ExitSuccess, "Success."},
+	{apperrors.ExitErrorGeneric, "Unexpected error."},
+	{apperrors.ExitErrorTimeout, "The calculation exceeded --timeout."},
+	{apperrors.ExitErrorMismatch, "The algorithms returned different results."},
+	{apperrors.ExitErrorConfig, "Invalid flags or configuration, or not enough memory."},
+	{apperrors.ExitErrorStalled, "The stall watchdog aborted the calculation (--stall-abort)."},
+	{apperrors.ExitErrorVerification, "The result differs from the external reference (--oeis, crosscheck)."},
+	{apperrors.ExitErrorNoReference, "The external reference could not be obtained (--oeis b-file, crosscheck program)."},
+	{apperrors.ExitErrorCanceled, "Canceled (e.g. Ctrl+C)."},
+}
+
+// FlagGroups describes the visible flags, grouped, with their environment
+// variables. It is built from the flag definitions of newFlagSet, so the
+// help and the man page cannot drift from the flags actually parsed.
+//
+// Parameters:
+//   - availableAlgos: The valid algorithm names, listed in the --algo help.
+//
+// Returns:
+//   - []FlagGroup: The groups, in display order.
+func FlagGroups(availableAlgos []string) []FlagGroup {
+	fs := newFlagSet("fibcalc", &AppConfig{}, availableAlgos)
+	groups := make([]FlagGroup, 0, len(flagLayout))
+	for _, g := range flagLayout {
+		group := FlagGroup{Title: g.title}
+		for _, e := range g.entries {
+			info := FlagInfo{Names: e.names, Value: e.value, EnvVar: envVarFor(e.names)}
+			if f := fs.Lookup(e.names[0]); f != nil {
+				info.Usage = f.Usage
+				if f.DefValue != "" && f.DefValue != "0" && f.DefValue != "false" {
+					info.Default = f.DefValue
+				}
+			} else {
+				info.Usage = builtinFlags[e.names[0]]
+			}
+			group.Flags = append(group.Flags, info)
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// envVarFor returns the environment variable that sets one of names, or ""
+// if there is none.
+func envVarFor(names []string) string {
+	for _, o := range envOverrides {
+		for _, n := range names {
+			if slices.Contains(o.flags, n) {
+				return EnvPrefix + o.envKey
+			}
+		}
+	}
+	return ""
+}
+
+// Signature formats the flag's names and value placeholder, short names
+// first (e.g. "-o, --output FILE").
+//
+// Returns:
+//   - string: The signature.
+func (f FlagInfo) Signature() string {
+	names := slices.Clone(f.Names)
+	slices.SortStableFunc(names, func(a, b string) int { return len(a) - len(b) })
+	parts := make([]string, len(names))
+	for i, n := range names {
+		if len(n) == 1 {
+			parts[i] = "-" + n
+		} else {
+			parts[i] = "--" + n
+		}
+	}
+	sig := strings.Join(parts, ", ")
+	if f.Value != "" {
+		sig += " " + f.Value
+	}
+	return sig
+}
+
+// WriteFullHelp writes the --help-full output: usage, subcommands, flags by
+// group with their defaults and environment variables, other environment
+// variables, exit codes and examples. It is plain text so that it can be
+// piped.
+//
+// Parameters:
+//   - w: The writer to write the help to.
+//   - programName: The name of the program, used in the usage lines.
+//   - availableAlgos: The valid algorithm names, listed in the --algo help.
+func WriteFullHelp(w io.Writer, programName string, availableAlgos []string) {
+	fmt.Fprintf(w, "Fibonacci Calculator\nHigh-performance modular Fibonacci calculator.\n\n")
+	fmt.Fprintf(w, "Usage:\n  %s [flags]\n", programName)
+	for _, c := range Commands {
+		fmt.Fprintf(w, "  %s %s %s\n", programName, c.Name, c.Args)
+	}
+
+	fmt.Fprintf(w, "\nCommands:\n")
+	for _, c := range Commands {
+		fmt.Fprintf(w, "  %-28s %s\n", c.Name, c.Summary)
+	}
+
+	for _, g := range FlagGroups(availableAlgos) {
+		fmt.Fprintf(w, "\n%s:\n", g.Title)
+		for _, f := range g.Flags {
+			fmt.Fprintf(w, "  %-28s %s", f.Signature(), f.Usage)
+			if f.Default != "" {
+				fmt.Fprintf(w, " (default %s)", f.Default)
+			}
+			fmt.Fprintln(w)
+			if f.EnvVar != "" {
+				fmt.Fprintf(w, "  %-28s env: %s\n", "", f.EnvVar)
+			}
+		}
+	}
+
+	fmt.Fprintf(w, "\nEnvironment:\n")
+	fmt.Fprintf(w, "  %-28s %s\n", EnvPrefix+"*", "Set the flag noted above; a flag on the command line wins.")
+	fmt.Fprintf(w, "  %-28s %s\n", "NO_COLOR", "Disable colored output (https://no-color.org/).")
+
+	fmt.Fprintf(w, "\nExit codes:\n")
+	for _, e := range exitCodes {
+		fmt.Fprintf(w, "  %-4d %s\n", e.code, e.meaning)
+	}
+
+	fmt.Fprintf(w, "\nExamples:\n")
+	for _, e := range helpExamples {
+		fmt.Fprintf(w, "  %s\n      %s\n", e.command, e.summary)
+	}
+}
+
+// visibleFlagNames returns the names of the flags of fs that are not
+// hidden.
+func visibleFlagNames(fs *flag.FlagSet) []string {
+	var names []string
+	fs.VisitAll(func(f *flag.Flag) {
+		if !hiddenFlags[f.Name] {
+			names = append(names, f.Name)
+		}
+	})
+	return names
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestFlagLayoutCoversFlags verifies that every visible flag appears exactly
+// once in the help layout, with a value placeholder exactly when it takes a
+// value.
+func TestFlagLayoutCoversFlags(t *testing.T) {
+	t.Parallel()
+	fs := newFlagSet("fibcalc", &AppConfig{}, []string{"fast"})
+
+	seen := map[string]int{}
+	for _, g := range flagLayout {
+		for _, e := range g.entries {
+			for _, name := range e.names {
+				seen[name]++
+			}
+			f := fs.Lookup(e.names[0])
+			if f == nil {
+				if _, ok := builtinFlags[e.names[0]]; !ok {
+					t.Errorf("layout lists undefined flag %q", e.names[0])
+				}
+				continue
+			}
+			bf, isBool := f.Value.(interface{ IsBoolFlag() bool })
+			if isBool = isBool && bf.IsBoolFlag(); isBool != (e.value == "") {
+				t.Errorf("flag %q: boolean=%v but value placeholder %q", f.Name, isBool, e.value)
+			}
+		}
+	}
+	for _, name := range visibleFlagNames(fs) {
+		if seen[name] != 1 {
+			t.Errorf("flag %q appears %d times in the help layout, want 1", name, seen[name])
+		}
+	}
+	for name := range hiddenFlags {
+		if seen[name] != 0 {
+			t.Errorf("hidden flag %q appears in the help layout", name)
+		}
+	}
+}
+
+func TestFlagInfoSignature(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		info FlagInfo
+		want string
+	}{
+		{FlagInfo{Names: []string{"n"}, Value: "N"}, "-n N"},
+		{FlagInfo{Names: []string{"output", "o"}, Value: "FILE"}, "-o, --output FILE"},
+		{FlagInfo{Names: []string{"v", "verbose"}}, "-v, --verbose"},
+	}
+	for _, tt := range tests {
+		if got := tt.info.Signature(); got != tt.want {
+			t.Errorf("Signature() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestFlagGroupsEnvVars(t *testing.T) {
+	t.Parallel()
+	env := map[string]string{}
+	for _, g := range FlagGroups([]string{"fast"}) {
+		for _, f := range g.Flags {
+			env[f.Names[0]] = f.EnvVar
+		}
+	}
+	if env["n"] != "FIBCALC_N" {
+		t.Errorf("env var of -n = %q, want FIBCALC_N", env["n"])
+	}
+	if env["v"] != "FIBCALC_VERBOSE" {
+		t.Errorf("env var of -v = %q, want FIBCALC_VERBOSE", env["v"])
+	}
+	if env["completion"] != "" {
+		t.Errorf("env var of --completion = %q, want none", env["completion"])
+	}
+}
+
+func TestWriteFullHelp(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	WriteFullHelp(&buf, "fibcalc", []string{"fast", "matrix"})
+	out := buf.String()
+	for _, want := range []string{
+		"Usage:", "install-manpages", "Calculation:", "Performance tuning:",
+		"-o, --output FILE", "env: FIBCALC_OUTPUT", "Exit codes:", "130", "Examples:", "NO_COLOR",
+		"[fast, matrix]",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("full help does not contain %q", want)
+		}
+	}
+	if strings.Contains(out, "fail-mode") {
+		t.Error("full help shows the hidden --fail-mode flag")
+	}
+}
+
+func TestWriteManPage(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	if err := WriteManPage(&buf, "v1.2.3", []string{"fast"}); err != nil {
+		t.Fatalf("WriteManPage: %v", err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, `.TH FIBCALC 1 "" "fibcalc v1.2.3"`) {
+		t.Errorf("man page does not start with the title header:\n%.80s", out)
+	}
+	for _, want := range []string{
+		".SH SYNOPSIS", ".SH OPTIONS", ".SS Output", `\fB\-o, \-\-output FILE\fR`,
+		`Environment: \fBFIBCALC_OUTPUT\fR.`, ".SH EXIT STATUS", ".SH EXAMPLES",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("man page does not contain %q", want)
+		}
+	}
+}
+
+func TestRoffEscape(t *testing.T) {
+	t.Parallel()
+	tests := []struct{ in, want string }{
+		{"--algo", `\-\-algo`},
+		{`a\b`, `a\eb`},
+		{".hidden", `\&.hidden`},
+		{"'quoted'", `\&'quoted'`},
+	}
+	for _, tt := range tests {
+		if got := roffEscape(tt.in); got != tt.want {
+			t.Errorf("roffEscape(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
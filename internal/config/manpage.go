@@ -0,0 +1,103 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteManPage writes the fibcalc(1) man page in roff format, generated from
+// the same flag metadata as the full help (see FlagGroups).
+//
+// Parameters:
+//   - w: The writer to write the page to.
+//   - version: The version shown in the page footer.
+//   - availableAlgos: The valid algorithm names, listed in the --algo help.
+//
+// Returns:
+//   - error: An error if writing failed.
+func WriteManPage(w io.Writer, version string, availableAlgos []string) error {
+	bw := bufio.NewWriter(w)
+	p := func(format string, a ...any) { fmt.Fprintf(bw, format+"\n", a...) }
+
+	p(`.TH FIBCALC 1 "" "fibcalc %s" "User Commands"`, roffEscape(version))
+	p(".SH NAME")
+	p(`fibcalc \- high\-performance Fibonacci calculator`)
+
+	p(".SH SYNOPSIS")
+	p(".B fibcalc")
+	p(`[\fIflags\fR]`)
+	for _, c := range Commands {
+		p(".br")
+		p(".B fibcalc %s", roffEscape(c.Name))
+		p("%s", roffEscape(c.Args))
+	}
+
+	p(".SH DESCRIPTION")
+	p("%s", roffEscape("fibcalc computes F(n), the n-th Fibonacci number, with fast doubling, "+
+		"matrix exponentiation or FFT-based multiplication. By default it runs every "+
+		"algorithm in parallel and checks that their results agree."))
+
+	p(".SH COMMANDS")
+	for _, c := range Commands {
+		p(".TP")
+		p(`.B %s`, roffEscape(c.Name))
+		p("%s", roffEscape(c.Summary))
+	}
+
+	p(".SH OPTIONS")
+	for _, g := range FlagGroups(availableAlgos) {
+		p(".SS %s", roffEscape(g.Title))
+		for _, f := range g.Flags {
+			p(".TP")
+			p(`\fB%s\fR`, roffEscape(f.Signature()))
+			text := f.Usage
+			if f.Default != "" {
+				text += " Default: " + f.Default + "."
+			}
+			p("%s", roffEscape(text))
+			if f.EnvVar != "" {
+				p(`Environment: \fB%s\fR.`, roffEscape(f.EnvVar))
+			}
+		}
+	}
+
+	p(".SH ENVIRONMENT")
+	p(".TP")
+	p(`.B %s*`, roffEscape(EnvPrefix))
+	p("%s", roffEscape("Each variable sets the option that names it above. An option given on the command line takes precedence."))
+	p(".TP")
+	p(".B NO_COLOR")
+	p("Disable colored output (https://no\\-color.org/).")
+
+	p(".SH EXIT STATUS")
+	for _, e := range exitCodes {
+		p(".TP")
+		p(".B %d", e.code)
+		p("%s", roffEscape(e.meaning))
+	}
+
+	p(".SH EXAMPLES")
+	for _, e := range helpExamples {
+		p(".TP")
+		p(`.B %s`, roffEscape(e.command))
+		p("%s", roffEscape(e.summary))
+	}
+
+	p(".SH SEE ALSO")
+	p("https://github.com/agbru/fibcalc")
+	return bw.Flush()
+}
+
+// roffEscape escapes s for use in roff text: backslashes and hyphens are
+// escaped, and a leading period or quote is protected from being read as a
+// request.
+func roffEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	s = strings.ReplaceAll(s, "-", `\-`)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
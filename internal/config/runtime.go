@@ -0,0 +1,62 @@
+package config
+
+import (
+	"github.com/agbru/fibcalc/internal/format"
+	"github.com/agbru/fibcalc/internal/ui"
+)
+
+// RuntimeConfig is an immutable snapshot of the presentation settings that
+// are otherwise kept in process-wide state: the color theme, the console
+// capabilities and the duration format. It is built once per run (at startup, or per request in a
+// long-running process) and passed to the components that render output,
+// so that concurrent runs with different options do not race on the ui
+// globals.
+//
+// The zero value is not meaningful; use NewRuntimeConfig.
+type RuntimeConfig struct {
+	theme     ui.Theme
+	console   ui.ConsoleSupport
+	durations format.DurationFormat
+}
+
+// NewRuntimeConfig captures the runtime settings for cfg.
+//
+// Parameters:
+//   - cfg: The application configuration.
+//   - console: The console capabilities, as detected by ui.InitConsole.
+//
+// Returns:
+//   - RuntimeConfig: The snapshot. Colors are disabled when the console has
+//     no ANSI support or NO_COLOR is set.
+func NewRuntimeConfig(cfg AppConfig, console ui.ConsoleSupport) RuntimeConfig {
+	if cfg.ASCII {
+		console.Unicode = false
+	}
+	return RuntimeConfig{
+		theme:     ui.ResolveTheme(!console.VT),
+		console:   console,
+		durations: cfg.Durations(),
+	}
+}
+
+// Theme returns the ANSI color theme of the run.
+func (r RuntimeConfig) Theme() ui.Theme { return r.theme }
+
+// TUITheme returns the lipgloss palette of the run.
+func (r RuntimeConfig) TUITheme() ui.TUITheme { return r.theme.TUI() }
+
+// Console returns the console capabilities of the run.
+func (r RuntimeConfig) Console() ui.ConsoleSupport { return r.console }
+
+// Durations returns the duration format of the run.
+func (r RuntimeConfig) Durations() format.DurationFormat { return r.durations }
+
+// Install makes r the process-wide default, for the code that still reads
+// the ui globals (ui.GetCurrentTheme, ui.Console) and the duration format
+// of format.FormatExecutionDuration. Only the process's main run should
+// install its snapshot.
+func (r RuntimeConfig) Install() {
+	ui.SetConsole(r.console)
+	ui.SetCurrentTheme(r.theme)
+	format.SetDurationFormat(r.durations)
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/agbru/fibcalc/internal/ui"
+)
+
+func TestNewRuntimeConfig(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	os.Unsetenv("NO_COLOR")
+
+	tests := []struct {
+		name        string
+		cfg         AppConfig
+		console     ui.ConsoleSupport
+		wantTheme   string
+		wantUnicode bool
+	}{
+		{"ansi console", AppConfig{}, ui.ConsoleSupport{VT: true, Unicode: true}, "dark", true},
+		{"no ansi", AppConfig{}, ui.ConsoleSupport{Unicode: true}, "none", true},
+		{"ascii forced", AppConfig{ASCII: true}, ui.ConsoleSupport{VT: true, Unicode: true}, "dark", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := NewRuntimeConfig(tt.cfg, tt.console)
+			if got := rt.Theme().Name; got != tt.wantTheme {
+				t.Errorf("Theme() = %q, want %q", got, tt.wantTheme)
+			}
+			if got := rt.Console().Unicode; got != tt.wantUnicode {
+				t.Errorf("Console().Unicode = %v, want %v", got, tt.wantUnicode)
+			}
+			if got := rt.TUITheme(); got != rt.Theme().TUI() {
+				t.Error("TUITheme() does not match Theme().TUI()")
+			}
+		})
+	}
+}
+
+// TestRuntimeConfigIsolated verifies that a snapshot is not affected by
+// later changes to the process-wide theme.
+func TestRuntimeConfigIsolated(t *testing.T) {
+	original := ui.GetCurrentTheme()
+	defer ui.SetCurrentTheme(original)
+	t.Setenv("NO_COLOR", "")
+	os.Unsetenv("NO_COLOR")
+
+	rt := NewRuntimeConfig(AppConfig{}, ui.ConsoleSupport{VT: true})
+	ui.SetTheme("none")
+	if got := rt.Theme().Name; got != "dark" {
+		t.Errorf("Theme() = %q after SetTheme, want %q", got, "dark")
+	}
+}
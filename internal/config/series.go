@@ -0,0 +1,127 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// MaxSeriesPoints bounds the number of indexes of an --n-series, so that a
+// typo in the step does not queue thousands of runs.
+const MaxSeriesPoints = 100
+
+// ParseNSeries expands an --n-series specification into its indexes.
+//
+// The specification is "START..END", optionally followed by "step xF"
+// (geometric, each index F times the previous one, F > 1) or "step +D"
+// (arithmetic, D more each time); the default step is x2. The bounds are
+// integers, powers ("10^6") or decimal exponents ("2.5e6"). END is
+// included when the steps reach it exactly.
+//
+// Parameters:
+//   - spec: The specification, such as "10^6..10^7 step x2".
+//
+// Returns:
+//   - []uint64: The indexes, increasing and without duplicates.
+//   - error: An error if the specification is invalid or has more than
+//     MaxSeriesPoints indexes.
+func ParseNSeries(spec string) ([]uint64, error) {
+	rangePart, stepPart, hasStep := strings.Cut(strings.TrimSpace(spec), " step ")
+	lo, hi, ok := strings.Cut(rangePart, "..")
+	if !ok {
+		return nil, fmt.Errorf("invalid n-series %q: expected START..END [step xF|+D]", spec)
+	}
+	start, err := ParseIndex(lo)
+	if err != nil {
+		return nil, fmt.Errorf("invalid n-series %q: %w", spec, err)
+	}
+	end, err := ParseIndex(hi)
+	if err != nil {
+		return nil, fmt.Errorf("invalid n-series %q: %w", spec, err)
+	}
+	if end < start {
+		return nil, fmt.Errorf("invalid n-series %q: END is below START", spec)
+	}
+
+	step := "x2"
+	if hasStep {
+		step = strings.TrimSpace(stepPart)
+	}
+	var next func(uint64) uint64
+	switch {
+	case strings.HasPrefix(step, "x"):
+		factor, err := strconv.ParseFloat(step[1:], 64)
+		if err != nil || factor <= 1 || math.IsInf(factor, 0) {
+			return nil, fmt.Errorf("invalid n-series step %q: the factor must be a number above 1", step)
+		}
+		if start == 0 {
+			return nil, fmt.Errorf("invalid n-series %q: a geometric series cannot start at 0", spec)
+		}
+		next = func(n uint64) uint64 {
+			// At least one more, for factors that round back to n
+			return max(n+1, uint64(math.Round(float64(n)*factor)))
+		}
+	default:
+		delta, err := strconv.ParseUint(strings.TrimPrefix(step, "+"), 10, 64)
+		if err != nil || delta == 0 {
+			return nil, fmt.Errorf("invalid n-series step %q: expected xF or +D with D > 0", step)
+		}
+		next = func(n uint64) uint64 { return n + delta }
+	}
+
+	var series []uint64
+	for n := start; n <= end; n = next(n) {
+		if len(series) == MaxSeriesPoints {
+			return nil, fmt.Errorf("n-series %q has more than %d indexes; use a larger step", spec, MaxSeriesPoints)
+		}
+		series = append(series, n)
+		if next(n) <= n { // overflow
+			break
+		}
+	}
+	return series, nil
+}
+
+// ParseIndex parses a Fibonacci index written as an integer ("1000000"),
+// a power ("10^6") or a decimal exponent ("1e6", "2.5e6"), as the bounds
+// of an --n-series and the index of `fibcalc crosscheck` are.
+//
+// Parameters:
+//   - s: The index.
+//
+// Returns:
+//   - uint64: The index.
+//   - error: An error if s is not an index or does not fit in a uint64.
+func ParseIndex(s string) (uint64, error) {
+	s = strings.TrimSpace(s)
+	if base, exp, ok := strings.Cut(s, "^"); ok {
+		b, errB := strconv.ParseUint(base, 10, 64)
+		e, errE := strconv.ParseUint(exp, 10, 64)
+		if errB != nil || errE != nil {
+			return 0, fmt.Errorf("invalid index %q", s)
+		}
+		if b <= 1 && e > 0 {
+			return b, nil
+		}
+		v := uint64(1)
+		for range min(e, 64) {
+			if b != 0 && v > math.MaxUint64/b {
+				return 0, fmt.Errorf("index %q is too large", s)
+			}
+			v *= b
+		}
+		return v, nil
+	}
+	if v, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return v, nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v < 0 || v != math.Trunc(v) || !strings.ContainsAny(s, "eE") {
+		return 0, fmt.Errorf("invalid index %q", s)
+	}
+	if v >= math.MaxUint64 {
+		return 0, fmt.Errorf("index %q is too large", s)
+	}
+	return uint64(v), nil
+}
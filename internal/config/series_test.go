@@ -0,0 +1,63 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseNSeries(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		spec string
+		want []uint64
+	}{
+		{"10^6..10^7 step x2", []uint64{1_000_000, 2_000_000, 4_000_000, 8_000_000}},
+		{"10^6..10^7", []uint64{1_000_000, 2_000_000, 4_000_000, 8_000_000}},
+		{"1e3..2e3 step +500", []uint64{1000, 1500, 2000}},
+		{"100..400 step 100", []uint64{100, 200, 300, 400}},
+		{"10..20 step x1.5", []uint64{10, 15}},
+		{"3..5 step x1.1", []uint64{3, 4, 5}},
+		{"7..7", []uint64{7}},
+	}
+	for _, tt := range tests {
+		got, err := ParseNSeries(tt.spec)
+		if err != nil {
+			t.Errorf("ParseNSeries(%q) error: %v", tt.spec, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("ParseNSeries(%q) = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+
+	for _, spec := range []string{
+		"1000",
+		"10..5",
+		"0..100 step x2",
+		"1..10 step x1",
+		"1..10 step +0",
+		"1..10 step /2",
+		"1.5..10",
+		"2.5e0..10",
+		"2^64..2^65",
+		"1..1000 step +1",
+	} {
+		if _, err := ParseNSeries(spec); err == nil {
+			t.Errorf("ParseNSeries(%q) = nil error, want an error", spec)
+		}
+	}
+}
+
+func TestParseIndex(t *testing.T) {
+	t.Parallel()
+	for s, want := range map[string]uint64{"1000000": 1_000_000, "10^6": 1_000_000, "1e6": 1_000_000, "2.5e6": 2_500_000, " 42 ": 42} {
+		if got, err := ParseIndex(s); err != nil || got != want {
+			t.Errorf("ParseIndex(%q) = %d, %v; want %d", s, got, err, want)
+		}
+	}
+	for _, s := range []string{"", "-1", "1.5", "1e-3", "2^64", "1e20", "ten"} {
+		if _, err := ParseIndex(s); err == nil {
+			t.Errorf("ParseIndex(%q) = nil error, want an error", s)
+		}
+	}
+}
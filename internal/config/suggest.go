@@ -0,0 +1,149 @@
+package config
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// maxSuggestions is the number of close matches offered for a mistyped
+// flag or value.
+const maxSuggestions = 3
+
+// minPrefixMatch is the shortest common prefix that makes a candidate a
+// match regardless of the edit distance (e.g. --algoritm for --algo).
+const minPrefixMatch = 3
+
+// levenshtein returns the edit distance between a and b: the number of
+// single-character insertions, deletions, substitutions and transpositions
+// of adjacent characters turning a into b (the optimal string alignment
+// variant, so that "fats" is one edit away from "fast").
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	// d[i][j] is the distance between ra[:i] and rb[:j].
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+// suggest returns the candidates close enough to input to be a likely typo,
+// closest first and at most maxSuggestions of them. A candidate is close if
+// its edit distance is at most a third of the input's length (at least 1),
+// or if one of the two is a prefix of the other.
+//
+// Parameters:
+//   - input: The mistyped value.
+//   - candidates: The valid values.
+//
+// Returns:
+//   - []string: The suggestions, empty if no candidate is close.
+func suggest(input string, candidates []string) []string {
+	type match struct {
+		value string
+		dist  int
+	}
+	input = strings.ToLower(input)
+	limit := max(1, len(input)/3)
+	var matches []match
+	for _, c := range candidates {
+		lc := strings.ToLower(c)
+		d := levenshtein(input, lc)
+		prefix := min(len(input), len(lc)) >= minPrefixMatch &&
+			(strings.HasPrefix(input, lc) || strings.HasPrefix(lc, input))
+		if d <= limit || prefix {
+			matches = append(matches, match{c, d})
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b match) int { return cmp.Compare(a.dist, b.dist) })
+
+	var out []string
+	for _, m := range matches[:min(len(matches), maxSuggestions)] {
+		out = append(out, m.value)
+	}
+	return out
+}
+
+// didYouMean formats suggestions as a sentence, quoting each one with
+// format (e.g. "--%s" or "'%s'").
+//
+// Returns:
+//   - string: "Did you mean X?" or "Did you mean one of: X, Y?", or "" when
+//     there are no suggestions.
+func didYouMean(suggestions []string, format string) string {
+	if len(suggestions) == 0 {
+		return ""
+	}
+	quoted := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		quoted[i] = fmt.Sprintf(format, s)
+	}
+	if len(quoted) == 1 {
+		return fmt.Sprintf("Did you mean %s?", quoted[0])
+	}
+	return fmt.Sprintf("Did you mean one of: %s?", strings.Join(quoted, ", "))
+}
+
+// unknownFlagPrefix starts the error the flag package returns for a flag
+// that is not defined.
+const unknownFlagPrefix = "flag provided but not defined: -"
+
+// unknownFlagError reports a flag that is not defined, with the closest
+// visible flags of fs.
+//
+// Parameters:
+//   - fs: The flag set the arguments were parsed with.
+//   - err: The error returned by fs.Parse.
+//
+// Returns:
+//   - string: The message to print, or "" if err is not an unknown-flag
+//     error.
+func unknownFlagError(fs *flag.FlagSet, err error) string {
+	name, ok := strings.CutPrefix(err.Error(), unknownFlagPrefix)
+	if !ok {
+		return ""
+	}
+	msg := fmt.Sprintf("unknown flag: --%s", name)
+	if hint := didYouMean(suggest(name, visibleFlagNames(fs)), "--%s"); hint != "" {
+		msg += ". " + hint
+	}
+	return msg
+}
+
+// unknownValueError builds the error message for a value that is not among
+// valid, with the closest valid values, as a full sentence.
+//
+// Parameters:
+//   - what: The kind of value (e.g. "algorithm").
+//   - value: The value given.
+//   - valid: The valid values.
+//
+// Returns:
+//   - string: The message, e.g. "unrecognized algorithm: 'fats'. Did you
+//     mean 'fast'?".
+func unknownValueError(what, value string, valid []string) string {
+	msg := fmt.Sprintf("unrecognized %s: '%s'.", what, value)
+	if hint := didYouMean(suggest(value, valid), "'%s'"); hint != "" {
+		msg += " " + hint
+	}
+	return msg
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+)
+
+func TestLevenshtein(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"fast", "fast", 0},
+		{"", "fast", 4},
+		{"fats", "fast", 1},
+		{"matirx", "matrix", 1},
+		{"kitten", "sitting", 3},
+		{"algoritm", "algo", 4},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := levenshtein(tt.b, tt.a); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	t.Parallel()
+	algos := []string{"all", "auto", "fast", "fft", "lowmem", "matrix"}
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"fats", []string{"fast"}},
+		{"FAST", []string{"fast"}},
+		{"matrx", []string{"matrix"}},
+		{"lowmemory", []string{"lowmem"}},
+		{"xyzzy", nil},
+	}
+	for _, tt := range tests {
+		if got := suggest(tt.input, algos); !slices.Equal(got, tt.want) {
+			t.Errorf("suggest(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+
+	// The prefix check ignores case on both sides
+	env := []string{"COMPARE_MODE", "TIMEOUT"}
+	if got := suggest("compare_mode_sequential", env); !slices.Equal(got, []string{"COMPARE_MODE"}) {
+		t.Errorf("suggest by prefix of an upper-case candidate = %v, want [COMPARE_MODE]", got)
+	}
+
+	if got := suggest("ab", []string{"aa", "ac", "ad", "ae"}); len(got) != maxSuggestions {
+		t.Errorf("suggest returned %d suggestions, want %d", len(got), maxSuggestions)
+	}
+}
+
+func TestDidYouMean(t *testing.T) {
+	t.Parallel()
+	if got := didYouMean(nil, "'%s'"); got != "" {
+		t.Errorf("didYouMean(nil) = %q, want empty", got)
+	}
+	if got, want := didYouMean([]string{"algo"}, "--%s"), "Did you mean --algo?"; got != want {
+		t.Errorf("didYouMean = %q, want %q", got, want)
+	}
+	if got, want := didYouMean([]string{"a", "b"}, "'%s'"), "Did you mean one of: 'a', 'b'?"; got != want {
+		t.Errorf("didYouMean = %q, want %q", got, want)
+	}
+}
+
+func TestParseConfigSuggestions(t *testing.T) {
+	t.Parallel()
+	algos := []string{"fast", "fft", "matrix"}
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"mistyped flag", []string{"--algoritm", "fast"}, "unknown flag: --algoritm. Did you mean --algo?"},
+		{"mistyped algorithm", []string{"--algo", "fats"}, "unrecognized algorithm: 'fats'. Did you mean 'fast'?"},
+		{"mistyped compare mode", []string{"--compare-mode", "paralel"}, "Did you mean 'parallel'?"},
+		{"hidden flags are not suggested", []string{"--fail-mod", "timeout"}, "unknown flag: --fail-mod\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var buf bytes.Buffer
+			_, err := ParseConfig("fibcalc", tt.args, &buf, algos)
+			if !errors.As(err, new(apperrors.ConfigError)) {
+				t.Fatalf("ParseConfig error = %v, want a ConfigError", err)
+			}
+			if !strings.Contains(buf.String(), tt.want) {
+				t.Errorf("output does not contain %q:\n%s", tt.want, buf.String())
+			}
+		})
+	}
+}
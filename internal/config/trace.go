@@ -0,0 +1,42 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultTraceWindow is the length of the --runtime-trace capture window
+// when the specification does not give one.
+const DefaultTraceWindow = 30 * time.Second
+
+// ParseRuntimeTrace splits a --runtime-trace specification into the trace
+// file and the length of the capture window.
+//
+// The specification is "FILE" or "FILE:TIME", such as "trace.out:10s".
+// A suffix after the last colon that is not a duration belongs to the file
+// name, so that "C:\trace.out" is a file.
+//
+// Parameters:
+//   - spec: The specification.
+//
+// Returns:
+//   - string: The trace file.
+//   - time.Duration: The window, DefaultTraceWindow if not given.
+//   - error: An error if the file name is empty or the window not positive.
+func ParseRuntimeTrace(spec string) (string, time.Duration, error) {
+	path, window := spec, DefaultTraceWindow
+	if i := strings.LastIndexByte(spec, ':'); i >= 0 {
+		if d, err := time.ParseDuration(spec[i+1:]); err == nil {
+			path, window = spec[:i], d
+		}
+	}
+	if path == "" {
+		return "", 0, errors.New("missing trace file")
+	}
+	if window <= 0 {
+		return "", 0, fmt.Errorf("the capture window must be positive, got %s", window)
+	}
+	return path, window, nil
+}
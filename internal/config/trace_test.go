@@ -0,0 +1,50 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestParseRuntimeTrace(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		spec   string
+		path   string
+		window time.Duration
+	}{
+		{"trace.out", "trace.out", DefaultTraceWindow},
+		{"trace.out:10s", "trace.out", 10 * time.Second},
+		{"/tmp/a:b.out:1m30s", "/tmp/a:b.out", 90 * time.Second},
+		{`C:\trace.out`, `C:\trace.out`, DefaultTraceWindow},
+	}
+	for _, tt := range tests {
+		path, window, err := ParseRuntimeTrace(tt.spec)
+		if err != nil || path != tt.path || window != tt.window {
+			t.Errorf("ParseRuntimeTrace(%q) = %q, %v, %v, want %q, %v", tt.spec, path, window, err, tt.path, tt.window)
+		}
+	}
+
+	for _, spec := range []string{"", ":10s", "trace.out:0s", "trace.out:-5s"} {
+		if _, _, err := ParseRuntimeTrace(spec); err == nil {
+			t.Errorf("ParseRuntimeTrace(%q) should fail", spec)
+		}
+	}
+}
+
+func TestParseConfigRuntimeTrace(t *testing.T) {
+	t.Parallel()
+	algos := []string{"fast", "matrix", "fft"}
+	if _, err := ParseConfig("test", []string{"--runtime-trace", "trace.out:5s"}, &bytes.Buffer{}, algos); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, args := range [][]string{
+		{"--runtime-trace", "trace.out:0s"},
+		{"--runtime-trace", "trace.out", "--tui"},
+		{"--runtime-trace", "trace.out", "--n-series", "10..20"},
+	} {
+		if _, err := ParseConfig("test", args, &bytes.Buffer{}, algos); err == nil {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+}
@@ -8,6 +8,13 @@ import (
 	"github.com/agbru/fibcalc/internal/ui"
 )
 
+// hiddenFlags lists the flags left out of the usage message. They exist to
+// test integrations, not for regular use.
+var hiddenFlags = map[string]bool{
+	"fail-after": true,
+	"fail-mode":  true,
+}
+
 // setCustomUsage configures the flag set with a colored usage function.
 func setCustomUsage(fs *flag.FlagSet) {
 	fs.Usage = func() {
@@ -25,6 +32,9 @@ func setCustomUsage(fs *flag.FlagSet) {
 		fmt.Fprintf(out, "%sUsage:%s\n  %s [flags]\n\n%sFlags:%s\n", t.Warning, t.Reset, fs.Name(), t.Warning, t.Reset)
 
 		fs.VisitAll(func(f *flag.Flag) {
+			if hiddenFlags[f.Name] {
+				return
+			}
 			name, usage := flag.UnquoteUsage(f)
 			flagSig := fmt.Sprintf("-%s", f.Name)
 			if len(name) > 0 {
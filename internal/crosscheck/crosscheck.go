@@ -0,0 +1,183 @@
+package crosscheck
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/agbru/fibcalc/internal/format"
+)
+
+// Placeholder is replaced by n in the external command line.
+const Placeholder = "{n}"
+
+// Range is a run of differing digits, by position from the most
+// significant digit, 1-based and inclusive.
+type Range struct {
+	First int
+	Last  int
+}
+
+// Comparison is the outcome of comparing two decimal values.
+type Comparison struct {
+	// Equal is true if the values are the same.
+	Equal bool
+	// Digits and ExternalDigits are the lengths of the two values.
+	Digits         int
+	ExternalDigits int
+	// Prefix and Suffix are the numbers of leading and trailing digits the
+	// values have in common.
+	Prefix int
+	Suffix int
+	// Differing is the number of positions holding different digits, and
+	// Ranges groups them into runs. Both are only set when the values
+	// have the same length, positions being meaningless otherwise.
+	Differing int
+	Ranges    []Range
+}
+
+// Expand returns the command line for n: each Placeholder is replaced by
+// n or, without one, n is appended as the last argument.
+func Expand(cmdline string, n uint64) string {
+	index := strconv.FormatUint(n, 10)
+	if strings.Contains(cmdline, Placeholder) {
+		return strings.ReplaceAll(cmdline, Placeholder, index)
+	}
+	return cmdline + " " + index
+}
+
+// Command builds the command that runs the external program for n.
+//
+// Parameters:
+//   - ctx: The context; canceling it kills the program.
+//   - cmdline: The command line, expanded for n (see Expand) and run by the
+//     shell (sh, or cmd on Windows).
+//   - n: The index.
+//
+// Returns:
+//   - *exec.Cmd: The command, not started.
+func Command(ctx context.Context, cmdline string, n uint64) *exec.Cmd {
+	cmdline = Expand(cmdline, n)
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", cmdline)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", cmdline)
+}
+
+// Run runs the external program for n and returns its normalized output.
+//
+// Parameters:
+//   - ctx: The context; canceling it kills the program.
+//   - cmdline: The command line (see Command).
+//   - n: The index.
+//   - stderr: The writer the program's standard error goes to.
+//
+// Returns:
+//   - string: The value printed by the program, in canonical decimal.
+//   - error: An error if the program fails or prints no value.
+func Run(ctx context.Context, cmdline string, n uint64, stderr io.Writer) (string, error) {
+	cmd := Command(ctx, cmdline, n)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", fmt.Errorf("external command failed: %w", err)
+	}
+	value, err := Normalize(out.String())
+	if err != nil {
+		return "", fmt.Errorf("external command output: %w", err)
+	}
+	return value, nil
+}
+
+// Normalize extracts the value printed by an external program as canonical
+// decimal digits, without sign nor leading zeros. Digit grouping with
+// whitespace, commas, underscores or apostrophes, an "F(n) = " style
+// prefix (anything up to the last '=') and a 0x hexadecimal prefix are
+// accepted.
+//
+// Parameters:
+//   - output: The program's output.
+//
+// Returns:
+//   - string: The value.
+//   - error: An error if the output is not a non-negative integer.
+func Normalize(output string) (string, error) {
+	if i := strings.LastIndexByte(output, '='); i >= 0 {
+		output = output[i+1:]
+	}
+	value := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', '\n', '\r', ',', '_', '\'':
+			return -1
+		}
+		return r
+	}, output)
+	value = strings.TrimPrefix(value, "+")
+	if value == "" {
+		return "", fmt.Errorf("no value found")
+	}
+
+	if hex, ok := strings.CutPrefix(strings.ToLower(value), "0x"); ok {
+		v, ok := new(big.Int).SetString(hex, 16)
+		if !ok {
+			return "", fmt.Errorf("invalid hexadecimal value %q", format.Abbreviate(value, 20, 20))
+		}
+		return v.String(), nil
+	}
+	if i := strings.IndexFunc(value, func(r rune) bool { return r < '0' || r > '9' }); i >= 0 {
+		r, _ := utf8.DecodeRuneInString(value[i:])
+		return "", fmt.Errorf("unexpected %q in the value %q", string(r), format.Abbreviate(value, 20, 20))
+	}
+	value = strings.TrimLeft(value, "0")
+	if value == "" {
+		return "0", nil
+	}
+	return value, nil
+}
+
+// Compare compares the reference value with the external one, both in
+// canonical decimal.
+//
+// Parameters:
+//   - ref: fibcalc's value.
+//   - ext: The external program's value.
+//
+// Returns:
+//   - Comparison: Where the values differ.
+func Compare(ref, ext string) Comparison {
+	c := Comparison{Equal: ref == ext, Digits: len(ref), ExternalDigits: len(ext)}
+	shorter := min(len(ref), len(ext))
+	for c.Prefix < shorter && ref[c.Prefix] == ext[c.Prefix] {
+		c.Prefix++
+	}
+	for c.Suffix < shorter && ref[len(ref)-1-c.Suffix] == ext[len(ext)-1-c.Suffix] {
+		c.Suffix++
+	}
+	if c.Equal || len(ref) != len(ext) {
+		return c
+	}
+
+	for i := 0; i < len(ref); i++ {
+		if ref[i] == ext[i] {
+			continue
+		}
+		c.Differing++
+		if k := len(c.Ranges); k > 0 && c.Ranges[k-1].Last == i {
+			c.Ranges[k-1].Last = i + 1
+			continue
+		}
+		c.Ranges = append(c.Ranges, Range{First: i + 1, Last: i + 1})
+	}
+	return c
+}
@@ -0,0 +1,77 @@
+package crosscheck
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+	for in, want := range map[string]string{
+		"354224848179261915075\n":              "354224848179261915075",
+		"F(100) = 354,224,848,179,261,915,075": "354224848179261915075",
+		"354_224_848 179261915075":             "354224848179261915075",
+		"  0\r\n":                              "0",
+		"0x37":                                 "55",
+		"+00055":                               "55",
+	} {
+		got, err := Normalize(in)
+		if err != nil || got != want {
+			t.Errorf("Normalize(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "\n", "12.5", "-55", "1.2e10", "F(10) = ...55", "0xZZ"} {
+		if got, err := Normalize(in); err == nil {
+			t.Errorf("Normalize(%q) = %q, want an error", in, got)
+		}
+	}
+
+	// Multi-byte characters are quoted whole, not split mid-rune
+	_, err := Normalize(strings.Repeat("9", 30) + "€" + strings.Repeat("é", 30))
+	if err == nil || !utf8.ValidString(err.Error()) || !strings.Contains(err.Error(), `unexpected "€"`) || !strings.Contains(err.Error(), "...") {
+		t.Errorf("Normalize of a non-ASCII value = %v, want the character and the value quoted whole", err)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		ref, ext string
+		want     Comparison
+	}{
+		{"equal", "12345", "12345", Comparison{Equal: true, Digits: 5, ExternalDigits: 5, Prefix: 5, Suffix: 5}},
+		{"runs", "1234567890", "1294567801", Comparison{Digits: 10, ExternalDigits: 10, Prefix: 2, Suffix: 0, Differing: 3, Ranges: []Range{{3, 3}, {9, 10}}}},
+		{"lengths", "1234567", "12345", Comparison{Digits: 7, ExternalDigits: 5, Prefix: 5, Suffix: 0}},
+	}
+	for _, tt := range tests {
+		if got := Compare(tt.ref, tt.ext); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Compare() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	got, err := Run(context.Background(), "echo 'F({n}) = 1,000'", 15, &bytes.Buffer{})
+	if err != nil || got != "1000" {
+		t.Errorf("Run() = %q, %v; want 1000", got, err)
+	}
+	// Without the placeholder, n is the last argument
+	if got, err := Run(context.Background(), "echo", 55, &bytes.Buffer{}); err != nil || got != "55" {
+		t.Errorf("Run() without placeholder = %q, %v; want 55", got, err)
+	}
+
+	var stderr bytes.Buffer
+	if _, err := Run(context.Background(), "echo oops >&2; exit 3", 1, &stderr); err == nil || !strings.Contains(stderr.String(), "oops") {
+		t.Errorf("Run() of a failing command = %v with stderr %q", err, stderr.String())
+	}
+}
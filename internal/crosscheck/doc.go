@@ -0,0 +1,6 @@
+// Package crosscheck compares fibcalc's results with those of an external
+// program, for users validating a migration from another tool with
+// `fibcalc crosscheck`. The external command's output is normalized (digit
+// grouping, an "F(n) =" prefix or hexadecimal are accepted) and the two
+// values are compared digit by digit, reporting where they differ.
+package crosscheck
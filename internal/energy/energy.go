@@ -0,0 +1,128 @@
+// Package energy estimates the energy used by a calculation so that the
+// efficiency of different algorithms can be compared. On Linux it reads the
+// RAPL package energy counters (/sys/class/powercap) when they are readable;
+// elsewhere, or without permission, it falls back to a heuristic: the CPU
+// time of the process as a share of the machine's processors, times the
+// processor's thermal design power (TDP).
+//
+// macOS reports energy through powermetrics, which requires root and samples
+// at fixed intervals; it is not used, so macOS always gets the estimate.
+package energy
+
+import (
+	"runtime"
+	"time"
+)
+
+// DefaultTDP is the thermal design power, in watts, assumed by the heuristic
+// when none is given: a typical desktop processor.
+const DefaultTDP = 65
+
+// Source identifies how a Reading was obtained.
+type Source string
+
+const (
+	// SourceRAPL is a measurement from the RAPL package energy counters.
+	SourceRAPL Source = "rapl"
+	// SourceEstimate is the TDP × CPU-time heuristic.
+	SourceEstimate Source = "estimate"
+)
+
+// Reading is the energy used during a session.
+type Reading struct {
+	// Joules is the energy used.
+	Joules float64
+	// Duration is the wall-clock time the session lasted.
+	Duration time.Duration
+	// Source tells whether Joules was measured or estimated.
+	Source Source
+}
+
+// Watts returns the average power over the session, or 0 for an empty
+// session.
+func (r Reading) Watts() float64 {
+	if r.Duration <= 0 {
+		return 0
+	}
+	return r.Joules / r.Duration.Seconds()
+}
+
+// Session accumulates the energy used from Start to Stop.
+type Session struct {
+	tdp   float64
+	rapl  *raplSession
+	cpu   time.Duration
+	start time.Time
+}
+
+// Start begins measuring. RAPL counters are used when available, the
+// heuristic otherwise; Start itself never fails.
+//
+// Parameters:
+//   - tdp: The processor's thermal design power in watts for the heuristic.
+//     Zero or negative means DefaultTDP.
+//
+// Returns:
+//   - *Session: The running session, to be stopped with Stop.
+func Start(tdp float64) *Session {
+	if tdp <= 0 {
+		tdp = DefaultTDP
+	}
+	s := &Session{tdp: tdp}
+	if r, err := startRAPL(); err == nil {
+		s.rapl = r
+	} else {
+		s.cpu = processCPUTime()
+	}
+	s.start = time.Now()
+	return s
+}
+
+// RAPLAvailable reports whether the RAPL counters can be read in the
+// current process, or why not. It performs a short trial session.
+func RAPLAvailable() error {
+	r, err := startRAPL()
+	if err != nil {
+		return err
+	}
+	_, err = r.stop()
+	return err
+}
+
+// Stop ends the session and returns the energy used since Start.
+//
+// Returns:
+//   - Reading: The measured or estimated energy.
+func (s *Session) Stop() Reading {
+	elapsed := time.Since(s.start)
+	if s.rapl != nil {
+		if joules, err := s.rapl.stop(); err == nil {
+			return Reading{Joules: joules, Duration: elapsed, Source: SourceRAPL}
+		}
+	}
+	cpu := processCPUTime() - s.cpu
+	if s.rapl != nil || cpu <= 0 {
+		// No CPU time baseline, or no CPU time accounting on this
+		// platform: assume the whole machine was busy.
+		cpu = elapsed * time.Duration(runtime.NumCPU())
+	}
+	return Reading{Joules: Estimate(s.tdp, cpu, runtime.NumCPU()), Duration: elapsed, Source: SourceEstimate}
+}
+
+// Estimate returns the energy, in joules, of cpu time spread over a
+// processor with the given TDP and number of logical CPUs: a fully loaded
+// processor draws its TDP.
+//
+// Parameters:
+//   - tdp: The thermal design power in watts.
+//   - cpu: The CPU time used, summed over all threads.
+//   - numCPU: The number of logical CPUs, positive.
+//
+// Returns:
+//   - float64: The estimated energy in joules.
+func Estimate(tdp float64, cpu time.Duration, numCPU int) float64 {
+	if numCPU <= 0 {
+		numCPU = 1
+	}
+	return tdp * cpu.Seconds() / float64(numCPU)
+}
@@ -0,0 +1,44 @@
+package energy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEstimate(t *testing.T) {
+	// 8 CPUs fully busy for 2 s at 80 W: 160 J.
+	if got := Estimate(80, 16*time.Second, 8); got != 160 {
+		t.Errorf("Estimate() = %v, want 160", got)
+	}
+	if got := Estimate(80, time.Second, 0); got != 80 {
+		t.Errorf("Estimate() with no CPU count = %v, want 80", got)
+	}
+}
+
+func TestReadingWatts(t *testing.T) {
+	r := Reading{Joules: 30, Duration: 2 * time.Second}
+	if got := r.Watts(); got != 15 {
+		t.Errorf("Watts() = %v, want 15", got)
+	}
+	if got := (Reading{Joules: 1}).Watts(); got != 0 {
+		t.Errorf("Watts() of an empty session = %v, want 0", got)
+	}
+}
+
+func TestStartStop(t *testing.T) {
+	s := Start(0)
+	if s.tdp != DefaultTDP {
+		t.Errorf("tdp = %v, want DefaultTDP", s.tdp)
+	}
+	deadline := time.Now().Add(20 * time.Millisecond)
+	for x := 0; time.Now().Before(deadline); x++ {
+		_ = x * x
+	}
+	r := s.Stop()
+	if r.Source != SourceRAPL && r.Source != SourceEstimate {
+		t.Errorf("Source = %q", r.Source)
+	}
+	if r.Joules <= 0 || r.Duration <= 0 {
+		t.Errorf("Stop() = %+v, want positive energy and duration", r)
+	}
+}
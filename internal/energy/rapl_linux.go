@@ -0,0 +1,89 @@
+//go:build linux
+
+package energy
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// raplRoot is the powercap directory; a variable so tests can point it at
+// a fake tree.
+var raplRoot = "/sys/class/powercap"
+
+// raplPackage matches the top-level (package) RAPL zones. Subzones such as
+// intel-rapl:0:0 (cores) are part of their package and not summed again.
+var raplPackage = regexp.MustCompile(`^intel-rapl:\d+$`)
+
+// raplZone is one package energy counter.
+type raplZone struct {
+	dir   string
+	start uint64
+	wrap  uint64 // max_energy_range_uj, where the counter wraps to 0
+}
+
+// raplSession holds the counters of every package at Start.
+type raplSession struct {
+	zones []raplZone
+}
+
+// startRAPL records the energy counter of every package. It fails when
+// there is no RAPL zone or a counter is not readable: since Linux 5.10,
+// energy_uj is only readable by root unless the permissions are relaxed.
+func startRAPL() (*raplSession, error) {
+	entries, err := os.ReadDir(raplRoot)
+	if err != nil {
+		return nil, err
+	}
+	s := &raplSession{}
+	for _, e := range entries {
+		if !raplPackage.MatchString(e.Name()) {
+			continue
+		}
+		dir := filepath.Join(raplRoot, e.Name())
+		start, err := readMicrojoules(dir, "energy_uj")
+		if err != nil {
+			return nil, err
+		}
+		wrap, err := readMicrojoules(dir, "max_energy_range_uj")
+		if err != nil {
+			return nil, err
+		}
+		s.zones = append(s.zones, raplZone{dir: dir, start: start, wrap: wrap})
+	}
+	if len(s.zones) == 0 {
+		return nil, errors.New("energy: no RAPL package zone")
+	}
+	return s, nil
+}
+
+// stop returns the energy used by all packages since startRAPL, in joules,
+// allowing for one wrap of each counter.
+func (r *raplSession) stop() (float64, error) {
+	var total uint64
+	for _, z := range r.zones {
+		end, err := readMicrojoules(z.dir, "energy_uj")
+		if err != nil {
+			return 0, err
+		}
+		if end >= z.start {
+			total += end - z.start
+		} else {
+			total += z.wrap - z.start + end
+		}
+	}
+	return float64(total) / 1e6, nil
+}
+
+// readMicrojoules reads an integer counter file of a powercap zone.
+func readMicrojoules(dir, name string) (uint64, error) {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+}
@@ -0,0 +1,66 @@
+//go:build linux
+
+package energy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakeZone writes the counter files of a powercap zone under root.
+func fakeZone(t *testing.T, root, name, energy, wrap string) {
+	t.Helper()
+	dir := filepath.Join(root, name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "energy_uj"), []byte(energy+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "max_energy_range_uj"), []byte(wrap+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRAPLSession(t *testing.T) {
+	root := t.TempDir()
+	saved := raplRoot
+	raplRoot = root
+	t.Cleanup(func() { raplRoot = saved })
+
+	fakeZone(t, root, "intel-rapl:0", "1000000", "10000000")
+	fakeZone(t, root, "intel-rapl:1", "9500000", "10000000")
+	// A subzone, already counted in its package
+	fakeZone(t, root, "intel-rapl:0:0", "0", "10000000")
+
+	r, err := startRAPL()
+	if err != nil {
+		t.Fatalf("startRAPL() error = %v", err)
+	}
+	if len(r.zones) != 2 {
+		t.Fatalf("zones = %d, want 2", len(r.zones))
+	}
+
+	// Package 0 uses 2 J; package 1 wraps and uses 1.5 J.
+	fakeZone(t, root, "intel-rapl:0", "3000000", "10000000")
+	fakeZone(t, root, "intel-rapl:1", "1000000", "10000000")
+	fakeZone(t, root, "intel-rapl:0:0", "5000000", "10000000")
+	joules, err := r.stop()
+	if err != nil {
+		t.Fatalf("stop() error = %v", err)
+	}
+	if joules != 3.5 {
+		t.Errorf("stop() = %v J, want 3.5", joules)
+	}
+}
+
+func TestRAPLUnavailable(t *testing.T) {
+	saved := raplRoot
+	raplRoot = t.TempDir()
+	t.Cleanup(func() { raplRoot = saved })
+
+	if _, err := startRAPL(); err == nil {
+		t.Error("startRAPL() without zones should fail")
+	}
+}
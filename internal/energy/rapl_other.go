@@ -0,0 +1,18 @@
+//go:build !linux
+
+package energy
+
+import "errors"
+
+// raplSession is a placeholder on platforms without RAPL powercap support.
+type raplSession struct{}
+
+// startRAPL always fails on this platform.
+func startRAPL() (*raplSession, error) {
+	return nil, errors.New("energy: RAPL is only available on Linux")
+}
+
+// stop is never called on this platform.
+func (r *raplSession) stop() (float64, error) {
+	return 0, errors.New("energy: RAPL is only available on Linux")
+}
@@ -15,6 +15,9 @@ const (
 	ExitErrorTimeout  = 2   // Indicates the operation timed out.
 	ExitErrorMismatch = 3   // Indicates a result mismatch between algorithms.
 	ExitErrorConfig   = 4   // Indicates a configuration error.
+	ExitErrorStalled  = 5   // Indicates the stall watchdog aborted the calculation.
+	ExitErrorVerification = 6 // Indicates the result differs from an external reference (--oeis, crosscheck).
+	ExitErrorNoReference  = 7 // Indicates the external reference could not be obtained, so the result is unverified.
 	ExitErrorCanceled = 130 // Indicates the operation was canceled (e.g., SIGINT).
 )
 
@@ -100,6 +103,24 @@ func (e ValidationError) Error() string {
 	return fmt.Sprintf("validation error for %q: %s", e.Field, e.Message)
 }
 
+// StallError reports a calculation aborted by the stall watchdog
+// (--stall-abort) because it made no progress for longer than its stall
+// period.
+type StallError struct {
+	// Idle is the time since the last progress of the calculation.
+	Idle time.Duration
+	// Period is the stall period it exceeded.
+	Period time.Duration
+}
+
+// Error returns a formatted message describing the stall.
+//
+// Returns:
+//   - string: The error message string.
+func (e StallError) Error() string {
+	return fmt.Sprintf("stalled: no progress for %s (stall period %s)", e.Idle, e.Period)
+}
+
 // MemoryError represents a memory limit exceeded condition. It captures the
 // requested, available, and limit memory values for diagnostic purposes.
 type MemoryError struct {
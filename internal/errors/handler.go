@@ -1,7 +1,6 @@
 package apperrors
 
 import (
-	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -48,22 +47,22 @@ func HandleCalculationError(err error, duration time.Duration, out io.Writer, co
 		msgSuffix = fmt.Sprintf(" after %s%s%s", colors.Yellow(), duration, colors.Reset())
 	}
 
-	if errors.Is(err, context.DeadlineExceeded) {
+	switch kind := KindOf(err); kind {
+	case ErrorKindTimeout:
 		fmt.Fprintf(out, "Status: Failure (Timeout). The execution limit was reached%s.\n", msgSuffix)
-		return ExitErrorTimeout
-	}
-	if errors.Is(err, context.Canceled) {
+		return kind.ExitCode()
+	case ErrorKindCanceled:
 		fmt.Fprintf(out, "%sStatus: Canceled%s.%s\n", colors.Yellow(), msgSuffix, colors.Reset())
-		return ExitErrorCanceled
-	}
-
-	var memErr MemoryError
-	if errors.As(err, &memErr) {
+		return kind.ExitCode()
+	case ErrorKindOOM:
 		fmt.Fprintf(out, "Status: Failure (Out of Memory). %v\n", err)
 		fmt.Fprintf(out, "%s   Hint: The requested calculation exceeds the memory limit.\n", colors.Yellow())
 		fmt.Fprintf(out, "   - Try using --last-digits K to compute only the last digits using minimal memory.\n")
 		fmt.Fprintf(out, "   - Increase the --memory-limit if your system has sufficient RAM.%s\n", colors.Reset())
-		return ExitErrorConfig
+		return kind.ExitCode()
+	case ErrorKindStalled:
+		fmt.Fprintf(out, "Status: Failure (Stalled). The watchdog aborted the calculation%s: %v.\n", msgSuffix, err)
+		return kind.ExitCode()
 	}
 
 	var cfgErr ConfigError
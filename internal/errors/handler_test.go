@@ -46,6 +46,13 @@ func TestHandleCalculationError(t *testing.T) {
 			expectedCode: ExitErrorCanceled,
 			expectedMsg:  "[YELLOW]Status: Canceled after [YELLOW]500ms[RESET].[RESET]",
 		},
+		{
+			name:         "Stall Error",
+			err:          fmt.Errorf("calculator fast: %w", StallError{Idle: 2 * time.Minute, Period: time.Minute}),
+			duration:     3 * time.Minute,
+			expectedCode: ExitErrorStalled,
+			expectedMsg:  "Status: Failure (Stalled). The watchdog aborted the calculation after 3m0s: calculator fast: stalled: no progress for 2m0s (stall period 1m0s).",
+		},
 		{
 			name:         "Generic Error",
 			err:          fmt.Errorf("random error"),
@@ -0,0 +1,113 @@
+package apperrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrorKind categorizes the outcome of a calculation that did not produce
+// a trusted result. Presenters, exit-code mapping and machine-readable
+// output all use it instead of inspecting the error each time.
+type ErrorKind int
+
+const (
+	// ErrorKindNone means the calculation succeeded.
+	ErrorKindNone ErrorKind = iota
+	// ErrorKindTimeout means the calculation exceeded its deadline.
+	ErrorKindTimeout
+	// ErrorKindCanceled means the calculation was canceled (e.g. SIGINT).
+	ErrorKindCanceled
+	// ErrorKindOOM means the calculation ran out of its memory budget.
+	ErrorKindOOM
+	// ErrorKindInternal means any other failure, including recovered
+	// panics.
+	ErrorKindInternal
+	// ErrorKindMismatch means the calculation completed but its value
+	// disagrees with the other algorithms'.
+	ErrorKindMismatch
+	// ErrorKindStalled means the stall watchdog aborted the calculation
+	// (see StallError).
+	ErrorKindStalled
+)
+
+// errorKindNames are the names returned by String, in ErrorKind order.
+var errorKindNames = [...]string{"", "timeout", "canceled", "oom", "internal", "mismatch", "stalled"}
+
+// String returns the lower-case name of the kind ("timeout", "oom", ...),
+// or "" for ErrorKindNone.
+func (k ErrorKind) String() string {
+	if k < 0 || int(k) >= len(errorKindNames) {
+		return "internal"
+	}
+	return errorKindNames[k]
+}
+
+// MarshalText encodes the kind as its name, so that it appears as a string
+// in JSON output.
+func (k ErrorKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText decodes a name written by MarshalText.
+func (k *ErrorKind) UnmarshalText(text []byte) error {
+	for i, name := range errorKindNames {
+		if name == string(text) {
+			*k = ErrorKind(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown error kind %q", text)
+}
+
+// ExitCode returns the process exit code for a failure of this kind.
+//
+// Returns:
+//   - int: ExitSuccess for ErrorKindNone, otherwise the matching ExitError*
+//     code.
+func (k ErrorKind) ExitCode() int {
+	switch k {
+	case ErrorKindNone:
+		return ExitSuccess
+	case ErrorKindTimeout:
+		return ExitErrorTimeout
+	case ErrorKindCanceled:
+		return ExitErrorCanceled
+	case ErrorKindOOM:
+		return ExitErrorConfig
+	case ErrorKindMismatch:
+		return ExitErrorMismatch
+	case ErrorKindStalled:
+		return ExitErrorStalled
+	}
+	return ExitErrorGeneric
+}
+
+// KindOf categorizes a calculation error. A mismatch is not an error of a
+// single calculation, so it is never returned: it is established by
+// comparing results.
+//
+// Parameters:
+//   - err: The error returned by the calculation, possibly wrapped.
+//
+// Returns:
+//   - ErrorKind: ErrorKindNone for nil, ErrorKindInternal for errors of
+//     no known category.
+func KindOf(err error) ErrorKind {
+	var memErr MemoryError
+	var stallErr StallError
+	switch {
+	case err == nil:
+		return ErrorKindNone
+	case errors.As(err, &stallErr):
+		// The watchdog aborts by canceling the context
+		return ErrorKindStalled
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorKindTimeout
+	case errors.Is(err, context.Canceled):
+		return ErrorKindCanceled
+	case errors.As(err, &memErr):
+		return ErrorKindOOM
+	}
+	return ErrorKindInternal
+}
@@ -0,0 +1,62 @@
+package apperrors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestKindOf(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		err  error
+		kind ErrorKind
+		exit int
+	}{
+		{nil, ErrorKindNone, ExitSuccess},
+		{fmt.Errorf("calculator fast: %w", context.DeadlineExceeded), ErrorKindTimeout, ExitErrorTimeout},
+		{fmt.Errorf("calculator fast: %w", context.Canceled), ErrorKindCanceled, ExitErrorCanceled},
+		{fmt.Errorf("calculator fast: %w", MemoryError{Requested: 1}), ErrorKindOOM, ExitErrorConfig},
+		{errors.New("panic: boom"), ErrorKindInternal, ExitErrorGeneric},
+		{fmt.Errorf("calculator fast: %w: %w", StallError{time.Minute, 50 * time.Second}, context.Canceled), ErrorKindStalled, ExitErrorStalled},
+	}
+	for _, tt := range tests {
+		kind := KindOf(tt.err)
+		if kind != tt.kind {
+			t.Errorf("KindOf(%v) = %v, want %v", tt.err, kind, tt.kind)
+		}
+		if kind.ExitCode() != tt.exit {
+			t.Errorf("%v.ExitCode() = %d, want %d", kind, kind.ExitCode(), tt.exit)
+		}
+	}
+	if ErrorKindMismatch.ExitCode() != ExitErrorMismatch {
+		t.Errorf("ErrorKindMismatch.ExitCode() = %d, want %d", ErrorKindMismatch.ExitCode(), ExitErrorMismatch)
+	}
+}
+
+func TestErrorKindJSON(t *testing.T) {
+	t.Parallel()
+	type record struct {
+		Kind ErrorKind `json:"kind,omitempty"`
+	}
+	for kind := ErrorKindNone; kind <= ErrorKindStalled; kind++ {
+		data, err := json.Marshal(record{kind})
+		if err != nil {
+			t.Fatalf("Marshal(%v): %v", kind, err)
+		}
+		var back record
+		if err := json.Unmarshal(data, &back); err != nil || back.Kind != kind {
+			t.Errorf("round trip of %v through %s = %v, %v", kind, data, back.Kind, err)
+		}
+	}
+	if data, _ := json.Marshal(record{ErrorKindOOM}); string(data) != `{"kind":"oom"}` {
+		t.Errorf("ErrorKindOOM encodes as %s", data)
+	}
+	var back record
+	if err := json.Unmarshal([]byte(`{"kind":"meltdown"}`), &back); err == nil {
+		t.Error("an unknown kind should not decode")
+	}
+}
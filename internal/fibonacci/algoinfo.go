@@ -0,0 +1,142 @@
+package fibonacci
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/agbru/fibcalc/internal/format"
+)
+
+// AlgorithmInfo describes a calculator for `fibcalc algos`, the automatic
+// selection of --algo auto, the TUI and the generated documentation. Each
+// calculator declares its own in an Info method next to Name, so that the
+// description changes with the code it describes.
+type AlgorithmInfo struct {
+	// Name is the registry name, e.g. "fast". Set by Algorithms.
+	Name string `json:"name"`
+	// DisplayName is the calculator's Name(). Set by Algorithms.
+	DisplayName string `json:"display_name"`
+	// Aliases are the other names the factory accepts for the calculator.
+	Aliases []string `json:"aliases,omitempty"`
+	// Complexity is the asymptotic running time, M(n) being the cost of
+	// multiplying two n-bit numbers.
+	Complexity string `json:"complexity"`
+	// Memory describes where the operands live and how much memory the
+	// calculation takes at its peak.
+	Memory string `json:"memory"`
+	// Parallel reports whether the multiplications of a step run on
+	// several cores.
+	Parallel bool `json:"parallel"`
+	// MinN is the smallest n the calculator is recommended for.
+	MinN uint64 `json:"min_n"`
+	// MaxN is the largest n the calculator is recommended for, 0 if there
+	// is no upper bound.
+	MaxN uint64 `json:"max_n,omitempty"`
+	// Notes says when to choose the calculator.
+	Notes string `json:"notes"`
+}
+
+// describer is implemented by the calculators that describe themselves.
+type describer interface {
+	Info() AlgorithmInfo
+}
+
+// Recommends reports whether n lies in the recommended range of the
+// calculator. A calculator that does not describe itself has no range and
+// is recommended for every n.
+//
+// Parameters:
+//   - n: The index of the Fibonacci number to compute.
+//
+// Returns:
+//   - bool: true if MinN ≤ n and, when MaxN is set, n ≤ MaxN.
+func (i AlgorithmInfo) Recommends(n uint64) bool {
+	return n >= i.MinN && (i.MaxN == 0 || n <= i.MaxN)
+}
+
+// RangeString describes the recommended range of n for display, e.g.
+// "n ≥ 1,000,000" or "any n".
+//
+// Returns:
+//   - string: The range, with thousand separators.
+func (i AlgorithmInfo) RangeString() string {
+	minN := format.FormatNumberString(strconv.FormatUint(i.MinN, 10))
+	maxN := format.FormatNumberString(strconv.FormatUint(i.MaxN, 10))
+	switch {
+	case i.MinN == 0 && i.MaxN == 0:
+		return "any n"
+	case i.MaxN == 0:
+		return "n ≥ " + minN
+	case i.MinN == 0:
+		return "n ≤ " + maxN
+	default:
+		return minN + " ≤ n ≤ " + maxN
+	}
+}
+
+// Algorithms describes the calculators registered in a factory, sorted by
+// name. Calculators without an Info method are listed with their names
+// only.
+//
+// Parameters:
+//   - factory: The calculator factory to inspect.
+//
+// Returns:
+//   - []AlgorithmInfo: One description per registered calculator.
+func Algorithms(factory CalculatorFactory) []AlgorithmInfo {
+	aliases := make(map[string][]string)
+	if af, ok := factory.(interface{ Aliases() map[string]string }); ok {
+		for alias, target := range af.Aliases() {
+			aliases[target] = append(aliases[target], alias)
+		}
+	}
+
+	var infos []AlgorithmInfo
+	for _, name := range factory.List() {
+		calc, err := factory.Get(name)
+		if err != nil {
+			continue
+		}
+		var info AlgorithmInfo
+		if d, ok := calc.(describer); ok {
+			info = d.Info()
+		}
+		info.Name = name
+		info.DisplayName = calc.Name()
+		info.Aliases = aliases[name]
+		sort.Strings(info.Aliases)
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// RecommendedFor returns the names of the registered calculators whose
+// recommended range includes n, for --algo auto to choose from.
+//
+// Parameters:
+//   - factory: The calculator factory to inspect.
+//   - n: The index of the Fibonacci number to compute.
+//
+// Returns:
+//   - []string: The sorted names of the recommended calculators.
+func RecommendedFor(factory CalculatorFactory, n uint64) []string {
+	var names []string
+	for _, info := range Algorithms(factory) {
+		if info.Recommends(n) {
+			names = append(names, info.Name)
+		}
+	}
+	return names
+}
+
+// Info returns the description of the wrapped coreCalculator, or an empty
+// one if it does not describe itself.
+//
+// Returns:
+//   - AlgorithmInfo: The calculator's metadata, without its names.
+func (c *FibCalculator) Info() AlgorithmInfo {
+	if d, ok := c.core.(describer); ok {
+		return d.Info()
+	}
+	return AlgorithmInfo{}
+}
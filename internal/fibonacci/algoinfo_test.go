@@ -0,0 +1,90 @@
+package fibonacci
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAlgorithms(t *testing.T) {
+	t.Parallel()
+	factory := NewDefaultFactory()
+	_ = factory.Register("plugin", func() coreCalculator { return &mockCoreCalculator{} })
+
+	infos := Algorithms(factory)
+	var names []string
+	for _, info := range infos {
+		names = append(names, info.Name)
+	}
+	if want := factory.List(); !slices.Equal(names, want) {
+		t.Fatalf("Algorithms names = %v, want %v", names, want)
+	}
+
+	for _, info := range infos {
+		if info.DisplayName != factory.MustGet(info.Name).Name() {
+			t.Errorf("%s: DisplayName = %q, want the calculator's Name()", info.Name, info.DisplayName)
+		}
+		if info.Name == "plugin" {
+			if info.Complexity != "" || !info.Recommends(0) {
+				t.Errorf("a calculator without Info should have no metadata and no range: %+v", info)
+			}
+			continue
+		}
+		if info.Complexity == "" || info.Memory == "" || info.Notes == "" {
+			t.Errorf("%s: incomplete metadata: %+v", info.Name, info)
+		}
+	}
+
+	fast := infos[slices.IndexFunc(infos, func(i AlgorithmInfo) bool { return i.Name == "fast" })]
+	if !slices.Equal(fast.Aliases, []string{"fast-doubling", "fd"}) || !fast.Parallel {
+		t.Errorf("fast = %+v, want parallel with aliases fast-doubling and fd", fast)
+	}
+}
+
+func TestRecommendedFor(t *testing.T) {
+	t.Parallel()
+	factory := NewDefaultFactory()
+	tests := []struct {
+		n    uint64
+		want []string
+	}{
+		{1_000, []string{"fast", "matrix"}},
+		{10_000_000, []string{"fast", "fft", "matrix"}},
+		{2_000_000_000, []string{"fast", "fft", "lowmem", "matrix"}},
+	}
+	for _, tt := range tests {
+		if got := RecommendedFor(factory, tt.n); !slices.Equal(got, tt.want) {
+			t.Errorf("RecommendedFor(%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestAlgorithmInfoRecommends(t *testing.T) {
+	t.Parallel()
+	info := AlgorithmInfo{MinN: 10, MaxN: 20}
+	for n, want := range map[uint64]bool{9: false, 10: true, 20: true, 21: false} {
+		if got := info.Recommends(n); got != want {
+			t.Errorf("Recommends(%d) = %v, want %v", n, got, want)
+		}
+	}
+	if !(AlgorithmInfo{MinN: 10}).Recommends(1 << 62) {
+		t.Error("MaxN 0 should leave the range unbounded")
+	}
+}
+
+func TestAlgorithmInfoRangeString(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		info AlgorithmInfo
+		want string
+	}{
+		{AlgorithmInfo{}, "any n"},
+		{AlgorithmInfo{MinN: 1_000_000}, "n ≥ 1,000,000"},
+		{AlgorithmInfo{MaxN: 5000}, "n ≤ 5,000"},
+		{AlgorithmInfo{MinN: 10, MaxN: 20}, "10 ≤ n ≤ 20"},
+	}
+	for _, tt := range tests {
+		if got := tt.info.RangeString(); got != tt.want {
+			t.Errorf("RangeString(%+v) = %q, want %q", tt.info, got, tt.want)
+		}
+	}
+}
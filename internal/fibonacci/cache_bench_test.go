@@ -13,10 +13,6 @@ func BenchmarkCacheImpact(b *testing.B) {
 	// Test with a large N where FFT will be used
 	n := uint64(10_000_000) // F(10M) uses FFT multiplication
 
-	// Reset cache to defaults before each benchmark
-	defaultConfig := bigfft.DefaultTransformCacheConfig()
-	bigfft.SetTransformCacheConfig(defaultConfig)
-
 	calc := NewCalculator(&OptimizedFastDoubling{})
 	ctx := context.Background()
 
@@ -27,7 +23,6 @@ func BenchmarkCacheImpact(b *testing.B) {
 
 	b.Run("WithDefaultCache", func(b *testing.B) {
 		// Use default cache configuration
-		configureFFTCache(opts)
 		b.ResetTimer()
 
 		for i := 0; i < b.N; i++ {
@@ -48,7 +43,6 @@ func BenchmarkCacheImpact(b *testing.B) {
 			FFTCacheMaxEntries: 256,   // Larger cache
 			FFTCacheEnabled:    &enabled,
 		}
-		configureFFTCache(optsOptimized)
 		b.ResetTimer()
 
 		for i := 0; i < b.N; i++ {
@@ -67,7 +61,6 @@ func BenchmarkCacheImpact(b *testing.B) {
 			FFTThreshold:      DefaultFFTThreshold,
 			FFTCacheEnabled:   &disabled,
 		}
-		configureFFTCache(optsDisabled)
 		b.ResetTimer()
 
 		for i := 0; i < b.N; i++ {
@@ -77,9 +70,6 @@ func BenchmarkCacheImpact(b *testing.B) {
 			}
 		}
 	})
-
-	// Reset to defaults
-	bigfft.SetTransformCacheConfig(defaultConfig)
 }
 
 // BenchmarkCacheHitRate measures cache hit rate for iterative calculations
@@ -88,9 +78,6 @@ func BenchmarkCacheHitRate(b *testing.B) {
 	// Use a moderate N that will trigger FFT but complete quickly
 	n := uint64(1_000_000)
 
-	defaultConfig := bigfft.DefaultTransformCacheConfig()
-	bigfft.SetTransformCacheConfig(defaultConfig)
-
 	calc := NewCalculator(&OptimizedFastDoubling{})
 	ctx := context.Background()
 
@@ -104,7 +91,10 @@ func BenchmarkCacheHitRate(b *testing.B) {
 		FFTCacheEnabled:    &enabled,
 	}
 
-	configureFFTCache(opts)
+	// Attach the cache up front so its statistics can be read afterwards;
+	// each calculation otherwise creates its own
+	cache := bigfft.NewTransformCache(transformCacheConfig(opts))
+	opts.transformCache = cache
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -115,11 +105,7 @@ func BenchmarkCacheHitRate(b *testing.B) {
 	}
 
 	// Check cache statistics
-	cache := bigfft.GetTransformCache()
 	stats := cache.Stats()
 	b.Logf("Cache stats - Hits: %d, Misses: %d, Hit Rate: %.2f%%, Size: %d",
 		stats.Hits, stats.Misses, stats.HitRate*100, stats.Size)
-
-	// Reset to defaults
-	bigfft.SetTransformCacheConfig(defaultConfig)
 }
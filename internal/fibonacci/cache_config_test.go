@@ -1,14 +1,17 @@
 package fibonacci
 
 import (
+	"context"
+	"os"
+	"sync"
 	"testing"
 
 	"github.com/agbru/fibcalc/internal/bigfft"
 )
 
-// TestConfigureFFTCacheDefault verifies that configureFFTCache uses default
-// values when options are not specified.
-func TestConfigureFFTCacheDefault(t *testing.T) {
+// TestTransformCacheConfigDefault verifies that transformCacheConfig uses
+// default values when options are not specified.
+func TestTransformCacheConfigDefault(t *testing.T) {
 	t.Parallel()
 	opts := Options{
 		ParallelThreshold: 4096,
@@ -16,25 +19,15 @@ func TestConfigureFFTCacheDefault(t *testing.T) {
 		// FFTCache options not set - should use defaults
 	}
 
-	configureFFTCache(opts)
-
-	// Verify cache is configured with defaults
-	cache := bigfft.GetTransformCache()
-	stats := cache.Stats()
-
-	// Cache should be enabled by default
-	if stats.Size < 0 {
-		t.Error("Cache should be initialized")
+	got := transformCacheConfig(opts)
+	if want := bigfft.DefaultTransformCacheConfig(); got != want {
+		t.Errorf("transformCacheConfig() = %+v, want defaults %+v", got, want)
 	}
-
-	// Reset to defaults for other tests
-	defaultConfig := bigfft.DefaultTransformCacheConfig()
-	bigfft.SetTransformCacheConfig(defaultConfig)
 }
 
-// TestConfigureFFTCacheCustom verifies that configureFFTCache applies custom
-// configuration values when provided.
-func TestConfigureFFTCacheCustom(t *testing.T) {
+// TestTransformCacheConfigCustom verifies that transformCacheConfig applies
+// custom configuration values when provided.
+func TestTransformCacheConfigCustom(t *testing.T) {
 	t.Parallel()
 	enabled := true
 	opts := Options{
@@ -45,25 +38,15 @@ func TestConfigureFFTCacheCustom(t *testing.T) {
 		FFTCacheEnabled:    &enabled,
 	}
 
-	configureFFTCache(opts)
-
-	// Verify cache configuration was applied
-	cache := bigfft.GetTransformCache()
-	stats := cache.Stats()
-
-	// Cache should be enabled
-	if stats.Size < 0 {
-		t.Error("Cache should be initialized")
+	got := transformCacheConfig(opts)
+	if got.MinBitLen != 50000 || got.MaxEntries != 256 || !got.Enabled {
+		t.Errorf("transformCacheConfig() = %+v, want custom values applied", got)
 	}
-
-	// Reset to defaults for other tests
-	defaultConfig := bigfft.DefaultTransformCacheConfig()
-	bigfft.SetTransformCacheConfig(defaultConfig)
 }
 
-// TestConfigureFFTCacheDisabled verifies that configureFFTCache can disable
-// the cache when requested.
-func TestConfigureFFTCacheDisabled(t *testing.T) {
+// TestTransformCacheConfigDisabled verifies that transformCacheConfig can
+// disable the cache when requested.
+func TestTransformCacheConfigDisabled(t *testing.T) {
 	t.Parallel()
 	disabled := false
 	opts := Options{
@@ -72,18 +55,110 @@ func TestConfigureFFTCacheDisabled(t *testing.T) {
 		FFTCacheEnabled:   &disabled,
 	}
 
-	configureFFTCache(opts)
+	if transformCacheConfig(opts).Enabled {
+		t.Error("cache should be disabled")
+	}
+}
+
+// TestWithTransformCache verifies that each calculation gets its own cache
+// and that an attached cache is preserved.
+func TestWithTransformCache(t *testing.T) {
+	t.Parallel()
+	a := Options{}.WithTransformCache()
+	b := Options{}.WithTransformCache()
+	if a.transformCache == nil || b.transformCache == nil {
+		t.Fatal("WithTransformCache did not attach a cache")
+	}
+	if a.transformCache == b.transformCache {
+		t.Error("separate calculations should not share a cache")
+	}
+	if again := a.WithTransformCache(); again.transformCache != a.transformCache {
+		t.Error("an attached cache should be kept")
+	}
+}
+
+// TestTransformCacheStats verifies that the statistics of an attached cache
+// reflect the calculation that used it.
+func TestTransformCacheStats(t *testing.T) {
+	t.Parallel()
+	if _, ok := (Options{}).TransformCacheStats(); ok {
+		t.Error("expected no stats without an attached cache")
+	}
+
+	enabled := true
+	opts := Options{FFTThreshold: 10_000, FFTCacheEnabled: &enabled, FFTCacheMinBitLen: 1000}.WithTransformCache()
+	// The matrix products go through the cached FFT path
+	if _, err := NewCalculator(&MatrixExponentiation{}).Calculate(context.Background(), nil, 0, 2_000_000, opts); err != nil {
+		t.Fatalf("Calculate: %v", err)
+	}
+	stats, ok := opts.TransformCacheStats()
+	if !ok {
+		t.Fatal("expected stats for an attached cache")
+	}
+	if stats.Hits+stats.Misses == 0 {
+		t.Error("expected the calculation to use the attached cache")
+	}
+}
+
+// TestConcurrentCalculationsWithDifferentCacheSettings runs calculations with
+// conflicting cache settings concurrently; with per-calculation caches they
+// must not interfere (run with -race).
+func TestConcurrentCalculationsWithDifferentCacheSettings(t *testing.T) {
+	t.Parallel()
+	enabled, disabled := true, false
+	variants := []Options{
+		{FFTThreshold: 10_000, FFTCacheEnabled: &enabled, FFTCacheMinBitLen: 1000},
+		{FFTThreshold: 10_000, FFTCacheEnabled: &disabled},
+	}
+	const n = 200_000
+	want, err := NewCalculator(&OptimizedFastDoubling{}).Calculate(context.Background(), nil, 0, n, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	// Verify cache is disabled
-	cache := bigfft.GetTransformCache()
-	stats := cache.Stats()
+	var wg sync.WaitGroup
+	for _, opts := range variants {
+		wg.Add(1)
+		go func(opts Options) {
+			defer wg.Done()
+			got, err := NewCalculator(&OptimizedFastDoubling{}).Calculate(context.Background(), nil, 0, n, opts)
+			if err != nil || got.Cmp(want) != 0 {
+				t.Errorf("calculation with %+v: err = %v, mismatch = %v", opts, err, got.Cmp(want) != 0)
+			}
+		}(opts)
+	}
+	wg.Wait()
+}
 
-	// Cache should still exist but be disabled
-	if stats.Size < 0 {
-		t.Error("Cache should still be initialized")
+// TestSpillStats verifies that a spill moves the transform buffers of both
+// the fast doubling steps and the matrix products to disk without changing
+// the results.
+func TestSpillStats(t *testing.T) {
+	t.Parallel()
+	if _, ok := (Options{}).WithTransformCache().SpillStats(); ok {
+		t.Error("expected no spill without a threshold")
 	}
 
-	// Reset to defaults for other tests
-	defaultConfig := bigfft.DefaultTransformCacheConfig()
-	bigfft.SetTransformCacheConfig(defaultConfig)
+	const n = 500_000
+	want, err := NewCalculator(&OptimizedFastDoubling{}).Calculate(context.Background(), nil, 0, n, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, core := range []coreCalculator{&OptimizedFastDoubling{}, &MatrixExponentiation{}} {
+		dir := t.TempDir()
+		opts := Options{FFTThreshold: 10_000, SpillThresholdBytes: 1, SpillDir: dir}.WithTransformCache()
+		got, err := NewCalculator(core).Calculate(context.Background(), nil, 0, n, opts)
+		if err != nil {
+			t.Fatalf("%s: Calculate: %v", core.Name(), err)
+		}
+		if got.Cmp(want) != 0 {
+			t.Errorf("%s: spilled result differs", core.Name())
+		}
+		if stats, ok := opts.SpillStats(); !ok || stats.Buffers == 0 || stats.Bytes == 0 {
+			t.Errorf("%s: SpillStats() = %+v, %v, want spilled buffers", core.Name(), stats, ok)
+		}
+		if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+			t.Errorf("%s: %d spill files left", core.Name(), len(entries))
+		}
+	}
 }
@@ -7,6 +7,7 @@ import (
 
 	"github.com/agbru/fibcalc/internal/bigfft"
 	"github.com/agbru/fibcalc/internal/fibonacci/memory"
+	"github.com/agbru/fibcalc/internal/parallel"
 	"github.com/rs/zerolog/log"
 )
 
@@ -19,11 +20,17 @@ const (
 // Calculator defines the public interface for a Fibonacci calculator.
 // It is the primary abstraction used by the application's orchestration layer to
 // interact with different Fibonacci calculation algorithms.
+//
+// Concurrency: a single Calculator may run any number of Calculate calls at
+// once. CalculatorFactory.Get returns the same instance to every caller, so
+// implementations must keep no per-calculation state in the receiver, and
+// the returned *big.Int belongs to the caller alone (it never shares storage
+// with pooled state or with another call's result).
 type Calculator interface {
 	// Calculate executes the calculation of the n-th Fibonacci number. It is
-	// designed for safe concurrent execution and supports cancellation through the
-	// provided context. Progress updates are sent asynchronously to the
-	// progressChan.
+	// safe to call concurrently on the same Calculator and supports
+	// cancellation through the provided context. Progress updates are sent
+	// asynchronously to the progressChan.
 	//
 	// Parameters:
 	//   - ctx: The context for managing cancellation and deadlines.
@@ -45,7 +52,11 @@ type Calculator interface {
 }
 
 // coreCalculator defines the internal interface for a pure calculation
-// algorithm.
+// algorithm. FibCalculator calls CalculateCore concurrently on the same
+// value, so the receiver must be read-only during a calculation: working
+// state comes from the pools (AcquireState, acquireMatrixState) or is
+// allocated per call, and the result is detached from it before it is
+// released.
 type coreCalculator interface {
 	CalculateCore(ctx context.Context, reporter ProgressCallback, n uint64, opts Options) (*big.Int, error)
 	Name() string
@@ -88,9 +99,9 @@ func (c *FibCalculator) Name() string {
 }
 
 // Calculate orchestrates the calculation process.
-// It first checks for small values of `n` (≤93) which can be computed
-// efficiently using iterative addition without the overhead of the full
-// algorithm. For larger values, it adapts the progressChan into a
+// It first checks for small values of `n` (≤ MaxTableN), whose results are
+// read from precomputed tables without the overhead of the full algorithm
+// (unless opts.DisableTables is set). For larger values, it adapts the progressChan into a
 // ProgressCallback callback and delegates the core calculation to the wrapped
 // coreCalculator. This method ensures that progress is reported completely upon
 // successful calculation.
@@ -136,12 +147,35 @@ func (c *FibCalculator) Calculate(ctx context.Context, progressChan chan<- Progr
 //   - *big.Int: The calculated Fibonacci number.
 //   - error: An error if one occurred.
 func (c *FibCalculator) CalculateWithObservers(ctx context.Context, subject *ProgressSubject, calcIndex int, n uint64, opts Options) (result *big.Int, err error) {
+	// Small n: read F(n) from the precomputed tables, skipping GC control,
+	// pools and the core algorithm entirely.
+	if !opts.DisableTables {
+		if result, ok := precomputed(n); ok {
+			if subject != nil {
+				subject.Notify(calcIndex, 1.0)
+			}
+			return result, nil
+		}
+	}
+
+	// GOMAXPROCS may have changed since the last calculation
+	parallel.RefreshProcessors()
+
 	// GC control for large calculations
 	gcMode := opts.GCMode
 	if gcMode == "" {
 		gcMode = "auto"
 	}
+	if opts.SoftRealtime {
+		gcMode = string(memory.GCModeRealtime)
+		defer parallel.EnterSoftRealtime()()
+	}
+	logger := calcLogger(ctx, opts, nil)
+	opts.Logger = logger
 	gcCtrl := memory.NewGCController(gcMode, n)
+	if logger != nil {
+		gcCtrl.SetLogger(*logger)
+	}
 	gcCtrl.Begin()
 	defer gcCtrl.End()
 
@@ -152,7 +186,11 @@ func (c *FibCalculator) CalculateWithObservers(ctx context.Context, subject *Pro
 		if err != nil {
 			status = "error"
 		}
-		log.Trace().
+		event := log.Trace()
+		if logger != nil {
+			event = logger.Trace()
+		}
+		event.
 			Str("algo", c.core.Name()).
 			Uint64("n", n).
 			Float64("duration", duration).
@@ -163,21 +201,33 @@ func (c *FibCalculator) CalculateWithObservers(ctx context.Context, subject *Pro
 	// Create a reporter that notifies all observers.
 	// Fast path: if no observers are registered, use a no-op reporter
 	// to avoid lock acquisition and iteration overhead on every progress update.
-	// Use Freeze() to create a lock-free snapshot for the calculation loop.
+	// Use Freeze() to create a lock-free snapshot for the calculation loop,
+	// and adapt the update rate to wall time (~10/s) rather than step count.
 	var reporter ProgressCallback
 	if subject != nil && subject.ObserverCount() > 0 {
-		reporter = subject.Freeze(calcIndex)
+		adaptive := NewAdaptiveReporter(subject.Freeze(calcIndex), ProgressUpdateInterval)
+		defer adaptive.Stop()
+		reporter = adaptive.Report
 	} else {
 		reporter = func(float64) {} // No-op reporter
 	}
-
-	if n <= MaxFibUint64 {
-		reporter(1.0)
-		return calculateSmall(n), nil
+	if heartbeat := opts.Heartbeat; heartbeat != nil {
+		display := reporter
+		reporter = func(progress float64) {
+			display(progress)
+			heartbeat(progress)
+		}
 	}
 
-	// Configure FFT cache based on options for optimal performance
-	configureFFTCache(opts)
+	// Give this calculation its own FFT transform cache, configured from opts
+	opts = opts.WithTransformCache()
+	if logger != nil {
+		opts.transformCache.SetLogger(*logger)
+	}
+	if opts.MemoryPressure != nil {
+		cache := opts.transformCache
+		defer opts.MemoryPressure.OnPressure(func(memory.Pressure) { cache.Disable() })()
+	}
 
 	// Pre-warm pools once for large calculations (one-time initialization)
 	bigfft.EnsurePoolsWarmed(n)
@@ -188,21 +238,3 @@ func (c *FibCalculator) CalculateWithObservers(ctx context.Context, subject *Pro
 	}
 	return result, err
 }
-
-// calculateSmall returns the n-th Fibonacci number for small n using
-// iterative addition. This replaces the old LUT approach.
-func calculateSmall(n uint64) *big.Int {
-	if n == 0 {
-		return big.NewInt(0)
-	}
-	if n == 1 {
-		return big.NewInt(1)
-	}
-	a := big.NewInt(0)
-	b := big.NewInt(1)
-	for i := uint64(2); i <= n; i++ {
-		a.Add(a, b)
-		a, b = b, a
-	}
-	return b
-}
@@ -52,6 +52,20 @@ func (c *GMPCalculator) Name() string {
 	return "GMP (Fast Doubling)"
 }
 
+// Info describes the algorithm for listings and automatic selection. Below
+// MinN, the cgo call overhead makes math/big faster.
+//
+// Returns:
+//   - AlgorithmInfo: The algorithm's metadata.
+func (c *GMPCalculator) Info() AlgorithmInfo {
+	return AlgorithmInfo{
+		Complexity: "O(M(n) log n), GMP multiplication",
+		Memory:     "C heap managed by GMP, outside the Go heap and its limits",
+		MinN:       100_000_000,
+		Notes:      "For extremely large n, where GMP's assembly routines beat math/big.",
+	}
+}
+
 // findHighestBit returns the number of bits needed to represent n.
 // For n=0, returns 0. For n>0, returns floor(log2(n)) + 1.
 func findHighestBit(n uint64) int {
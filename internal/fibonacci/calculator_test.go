@@ -1,7 +1,11 @@
 package fibonacci
 
 import (
+	"context"
+	"fmt"
+	"math/big"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -59,3 +63,96 @@ func TestFibCalculator_Name(t *testing.T) {
 		}
 	})
 }
+
+// TestCalculatorConcurrentCalculate runs many calculations at once on the
+// single instance that the factory hands out for each algorithm, as a
+// server or batch mode would, and checks that every result is right and
+// owned by its caller. Run with -race to also catch shared state.
+func TestCalculatorConcurrentCalculate(t *testing.T) {
+	t.Parallel()
+
+	// Low thresholds so that the parallel and FFT paths run on small n
+	opts := Options{ParallelThreshold: 512, FFTThreshold: 2048, DisableTables: true}
+	ns := []uint64{0, 1, 2, 94, 187, 1000, 4093, 10007, 20000}
+	if testing.Short() {
+		ns = ns[:len(ns)-1]
+	}
+	want := referenceFibs(ns)
+
+	factory := NewDefaultFactory()
+	for _, name := range factory.List() {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			calc, err := factory.Get(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			const rounds = 3
+			results := make([]*big.Int, rounds*len(ns))
+			errs := make([]error, len(results))
+			var wg sync.WaitGroup
+			for i := range results {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					progress := make(chan ProgressUpdate, 8)
+					go func() {
+						for range progress {
+						}
+					}()
+					results[i], errs[i] = calc.Calculate(context.Background(), progress, i, ns[i%len(ns)], opts)
+					close(progress)
+				}()
+			}
+			wg.Wait()
+
+			// Calculations after the batch reuse the pooled state: they
+			// must not change the results handed out before.
+			for _, n := range ns {
+				if _, err := calc.Calculate(context.Background(), nil, 0, n, opts); err != nil {
+					t.Fatalf("F(%d): %v", n, err)
+				}
+			}
+			for i, got := range results {
+				n := ns[i%len(ns)]
+				if errs[i] != nil {
+					t.Errorf("F(%d): %v", n, errs[i])
+				} else if got.Cmp(want[n]) != 0 {
+					t.Errorf("F(%d) = %s, want %s", n, abbreviate(got), abbreviate(want[n]))
+				}
+			}
+		})
+	}
+}
+
+// referenceFibs computes F(n) for each n by plain addition.
+func referenceFibs(ns []uint64) map[uint64]*big.Int {
+	var last uint64
+	for _, n := range ns {
+		last = max(last, n)
+	}
+	wanted := make(map[uint64]bool, len(ns))
+	for _, n := range ns {
+		wanted[n] = true
+	}
+	fibs := make(map[uint64]*big.Int, len(ns))
+	a, b := big.NewInt(0), big.NewInt(1)
+	for i := uint64(0); i <= last; i++ {
+		if wanted[i] {
+			fibs[i] = new(big.Int).Set(a)
+		}
+		a.Add(a, b)
+		a, b = b, a
+	}
+	return fibs
+}
+
+// abbreviate shortens a huge value for error messages.
+func abbreviate(x *big.Int) string {
+	s := x.String()
+	if len(s) <= 40 {
+		return s
+	}
+	return fmt.Sprintf("%s...%s (%d digits)", s[:20], s[len(s)-20:], len(s))
+}
@@ -7,6 +7,7 @@ import (
 	"runtime"
 	"sync"
 
+	"github.com/agbru/fibcalc/internal/fibonacci/mul"
 	"github.com/agbru/fibcalc/internal/parallel"
 	"github.com/rs/zerolog"
 )
@@ -91,6 +92,27 @@ func SetTaskLogger(l zerolog.Logger) {
 	taskLogger = l
 }
 
+// calcLogger returns the logger of a calculation: opts.Logger, else the
+// logger attached to ctx (see zerolog.Logger.WithContext), else fallback.
+//
+// Parameters:
+//   - ctx: The context of the calculation.
+//   - opts: The options of the calculation.
+//   - fallback: The logger used when the caller injected none.
+//
+// Returns:
+//   - *zerolog.Logger: The logger.
+func calcLogger(ctx context.Context, opts Options, fallback *zerolog.Logger) *zerolog.Logger {
+	if opts.Logger != nil {
+		return opts.Logger
+	}
+	// zerolog.Ctx returns a disabled logger when ctx carries none
+	if l := zerolog.Ctx(ctx); l.GetLevel() != zerolog.Disabled {
+		return l
+	}
+	return fallback
+}
+
 // ─────────────────────────────────────────────────────────────────────────────
 // Parallel Execution Helper
 // ─────────────────────────────────────────────────────────────────────────────
@@ -144,15 +166,15 @@ type task interface {
 // multiplicationTask represents a single multiplication operation
 // to be executed either sequentially or in parallel.
 type multiplicationTask struct {
-	dest         **big.Int
-	a, b         *big.Int
-	fftThreshold int
+	dest    **big.Int
+	a, b    *big.Int
+	backend mul.Multiplier
 }
 
 // execute performs the multiplication task.
 func (t *multiplicationTask) execute() error {
 	var err error
-	*t.dest, err = smartMultiply(*t.dest, t.a, t.b, t.fftThreshold)
+	*t.dest, err = t.backend.MulTo(*t.dest, t.a, t.b)
 	return err
 }
 
@@ -161,15 +183,15 @@ func (t *multiplicationTask) execute() error {
 // Squaring is optimized compared to general multiplication because
 // it exploits the symmetry of the computation.
 type squaringTask struct {
-	dest         **big.Int
-	x            *big.Int
-	fftThreshold int
+	dest    **big.Int
+	x       *big.Int
+	backend mul.Multiplier
 }
 
 // execute performs the squaring task.
 func (t *squaringTask) execute() error {
 	var err error
-	*t.dest, err = smartSquare(*t.dest, t.x, t.fftThreshold)
+	*t.dest, err = t.backend.SqrTo(*t.dest, t.x)
 	return err
 }
 
@@ -183,6 +205,7 @@ func (t *squaringTask) execute() error {
 //   - PT: A pointer type to T that implements the task interface.
 //
 // Parameters:
+//   - logger: The logger of the calculation; nil means the task logger.
 //   - tasks: The slice of tasks to execute (values, not pointers).
 //   - inParallel: Whether to execute tasks in parallel.
 //
@@ -191,8 +214,11 @@ func (t *squaringTask) execute() error {
 func executeTasks[T any, PT interface {
 	*T
 	task
-}](tasks []T, inParallel bool) error {
-	taskLogger.Debug().
+}](logger *zerolog.Logger, tasks []T, inParallel bool) error {
+	if logger == nil {
+		logger = &taskLogger
+	}
+	logger.Debug().
 		Int("task_count", len(tasks)).
 		Bool("parallel", inParallel).
 		Msg("executing tasks")
@@ -226,19 +252,23 @@ func executeTasks[T any, PT interface {
 // both types of operations need to be executed together.
 //
 // Parameters:
+//   - logger: The logger of the calculation; nil means the task logger.
 //   - sqrTasks: The squaring tasks to execute.
 //   - mulTasks: The multiplication tasks to execute.
 //   - inParallel: Whether to execute tasks in parallel.
 //
 // Returns:
 //   - error: An error if any task failed.
-func executeMixedTasks(sqrTasks []squaringTask, mulTasks []multiplicationTask, inParallel bool) error {
+func executeMixedTasks(logger *zerolog.Logger, sqrTasks []squaringTask, mulTasks []multiplicationTask, inParallel bool) error {
 	totalTasks := len(sqrTasks) + len(mulTasks)
 	if totalTasks == 0 {
 		return nil
 	}
 
-	taskLogger.Debug().
+	if logger == nil {
+		logger = &taskLogger
+	}
+	logger.Debug().
 		Int("sqr_tasks", len(sqrTasks)).
 		Int("mul_tasks", len(mulTasks)).
 		Int("total_tasks", totalTasks).
@@ -1,8 +1,14 @@
 package fibonacci
 
 import (
+	"bytes"
+	"context"
 	"math/big"
+	"strings"
 	"testing"
+
+	"github.com/agbru/fibcalc/internal/fibonacci/mul"
+	"github.com/rs/zerolog"
 )
 
 // ─────────────────────────────────────────────────────────────────────────────
@@ -62,14 +68,14 @@ func TestExecuteTasksSequential(t *testing.T) {
 	var result *big.Int
 	tasks := []multiplicationTask{
 		{
-			dest:         &result,
-			a:            x,
-			b:            y,
-			fftThreshold: 0,
+			dest:    &result,
+			a:       x,
+			b:       y,
+			backend: mul.Big{},
 		},
 	}
 
-	err := executeTasks[multiplicationTask, *multiplicationTask](tasks, false)
+	err := executeTasks[multiplicationTask, *multiplicationTask](nil, tasks, false)
 	if err != nil {
 		t.Fatalf("executeTasks failed: %v", err)
 	}
@@ -89,9 +95,9 @@ func TestExecuteTasksParallel(t *testing.T) {
 	// Create multiple multiplication tasks
 	var results [3]*big.Int
 	tasks := []multiplicationTask{
-		{dest: &results[0], a: big.NewInt(10), b: big.NewInt(20), fftThreshold: 0},
-		{dest: &results[1], a: big.NewInt(30), b: big.NewInt(40), fftThreshold: 0},
-		{dest: &results[2], a: big.NewInt(50), b: big.NewInt(60), fftThreshold: 0},
+		{dest: &results[0], a: big.NewInt(10), b: big.NewInt(20), backend: mul.Big{}},
+		{dest: &results[1], a: big.NewInt(30), b: big.NewInt(40), backend: mul.Big{}},
+		{dest: &results[2], a: big.NewInt(50), b: big.NewInt(60), backend: mul.Big{}},
 	}
 
 	expectedResults := []*big.Int{
@@ -100,7 +106,7 @@ func TestExecuteTasksParallel(t *testing.T) {
 		big.NewInt(3000),
 	}
 
-	err := executeTasks[multiplicationTask, *multiplicationTask](tasks, true)
+	err := executeTasks[multiplicationTask, *multiplicationTask](nil, tasks, true)
 	if err != nil {
 		t.Fatalf("executeTasks parallel failed: %v", err)
 	}
@@ -126,13 +132,13 @@ func TestExecuteSquaringTasks(t *testing.T) {
 	var result *big.Int
 	tasks := []squaringTask{
 		{
-			dest:         &result,
-			x:            x,
-			fftThreshold: 0,
+			dest:    &result,
+			x:       x,
+			backend: mul.Big{},
 		},
 	}
 
-	err := executeTasks[squaringTask, *squaringTask](tasks, false)
+	err := executeTasks[squaringTask, *squaringTask](nil, tasks, false)
 	if err != nil {
 		t.Fatalf("executeTasks failed: %v", err)
 	}
@@ -153,12 +159,12 @@ func TestExecuteSquaringTasks(t *testing.T) {
 func TestExecuteMixedTasksEmpty(t *testing.T) {
 	t.Parallel()
 
-	err := executeMixedTasks(nil, nil, false)
+	err := executeMixedTasks(nil, nil, nil, false)
 	if err != nil {
 		t.Errorf("executeMixedTasks with empty slices failed: %v", err)
 	}
 
-	err = executeMixedTasks(nil, nil, true)
+	err = executeMixedTasks(nil, nil, nil, true)
 	if err != nil {
 		t.Errorf("executeMixedTasks parallel with empty slices failed: %v", err)
 	}
@@ -171,13 +177,13 @@ func TestExecuteMixedTasksSequential(t *testing.T) {
 	var sqrResult, mulResult *big.Int
 
 	sqrTasks := []squaringTask{
-		{dest: &sqrResult, x: big.NewInt(10), fftThreshold: 0},
+		{dest: &sqrResult, x: big.NewInt(10), backend: mul.Big{}},
 	}
 	mulTasks := []multiplicationTask{
-		{dest: &mulResult, a: big.NewInt(5), b: big.NewInt(6), fftThreshold: 0},
+		{dest: &mulResult, a: big.NewInt(5), b: big.NewInt(6), backend: mul.Big{}},
 	}
 
-	err := executeMixedTasks(sqrTasks, mulTasks, false)
+	err := executeMixedTasks(nil, sqrTasks, mulTasks, false)
 	if err != nil {
 		t.Fatalf("executeMixedTasks failed: %v", err)
 	}
@@ -198,15 +204,15 @@ func TestExecuteMixedTasksParallel(t *testing.T) {
 	var mulResults [2]*big.Int
 
 	sqrTasks := []squaringTask{
-		{dest: &sqrResults[0], x: big.NewInt(10), fftThreshold: 0},
-		{dest: &sqrResults[1], x: big.NewInt(20), fftThreshold: 0},
+		{dest: &sqrResults[0], x: big.NewInt(10), backend: mul.Big{}},
+		{dest: &sqrResults[1], x: big.NewInt(20), backend: mul.Big{}},
 	}
 	mulTasks := []multiplicationTask{
-		{dest: &mulResults[0], a: big.NewInt(3), b: big.NewInt(4), fftThreshold: 0},
-		{dest: &mulResults[1], a: big.NewInt(5), b: big.NewInt(6), fftThreshold: 0},
+		{dest: &mulResults[0], a: big.NewInt(3), b: big.NewInt(4), backend: mul.Big{}},
+		{dest: &mulResults[1], a: big.NewInt(5), b: big.NewInt(6), backend: mul.Big{}},
 	}
 
-	err := executeMixedTasks(sqrTasks, mulTasks, true)
+	err := executeMixedTasks(nil, sqrTasks, mulTasks, true)
 	if err != nil {
 		t.Fatalf("executeMixedTasks parallel failed: %v", err)
 	}
@@ -227,3 +233,39 @@ func TestExecuteMixedTasksParallel(t *testing.T) {
 		t.Errorf("mulResults[1] = %v, want 30", mulResults[1])
 	}
 }
+
+func TestCalcLogger(t *testing.T) {
+	t.Parallel()
+	fallback := zerolog.Nop()
+	fromCtx := zerolog.New(&bytes.Buffer{})
+	fromOpts := zerolog.New(&bytes.Buffer{})
+	ctx := fromCtx.WithContext(context.Background())
+
+	if got := calcLogger(context.Background(), Options{}, &fallback); got != &fallback {
+		t.Error("without an injected logger, the fallback should be used")
+	}
+	if got := calcLogger(ctx, Options{}, &fallback); got != zerolog.Ctx(ctx) {
+		t.Error("the logger of the context should be used")
+	}
+	if got := calcLogger(ctx, Options{Logger: &fromOpts}, &fallback); got != &fromOpts {
+		t.Error("Options.Logger should take precedence over the context")
+	}
+}
+
+// TestCalculationLogger verifies that the logger injected through the
+// context reaches the calculator and its parallel tasks.
+func TestCalculationLogger(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf).Level(zerolog.TraceLevel)
+	ctx := logger.WithContext(context.Background())
+	calc := NewCalculator(&MatrixExponentiation{})
+	if _, err := calc.Calculate(ctx, nil, 0, 5000, Options{ParallelThreshold: 1, DisableTables: true}); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"message":"executing`, `"message":"calculation completed"`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("log lacks %s:\n%s", want, buf.String())
+		}
+	}
+}
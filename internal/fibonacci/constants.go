@@ -35,6 +35,13 @@ const (
 	// is faster. 3072 bits is the crossover point on typical hardware.
 	DefaultStrassenThreshold = 3072
 
+	// SoftRealtimeMaxMulBits caps the FFT threshold in soft real-time mode
+	// (see Options.SoftRealtime). A math/big multiplication cannot yield
+	// before it returns: about 6ms at 500,000 bits on a current core, 60ms
+	// at 2,000,000. Larger operands are multiplied by FFT, whose transform
+	// passes and pointwise products yield between them.
+	SoftRealtimeMaxMulBits = 500_000
+
 	// ParallelFFTThreshold is the bit size threshold above which parallel
 	// execution of FFT multiplications becomes beneficial.
 	//
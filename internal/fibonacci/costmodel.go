@@ -0,0 +1,237 @@
+package fibonacci
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// karatsubaExponent is log2(3), the asymptotic exponent of math/big's
+	// Karatsuba multiplication.
+	karatsubaExponent = 1.585
+
+	// squareCostFactor is the cost of a squaring relative to a general
+	// multiplication of the same size.
+	squareCostFactor = 0.67
+
+	// parallelEfficiency discounts the ideal speedup of running the
+	// multiplications of one step on several cores.
+	parallelEfficiency = 0.7
+)
+
+// CostModel estimates the relative running time of the built-in algorithms
+// from the multiplications each one performs per bit of n and the cost of
+// a multiplication at each operand size.
+//
+// The cost of an FFT multiplication is scaled so that it equals the cost of
+// a math/big multiplication at FFTCrossoverBits, the measured size above
+// which FFT wins. The calculators themselves switch at FFTThreshold, so a
+// threshold that differs from the crossover penalizes the adaptive
+// algorithms and can make the FFT-only calculator the better choice.
+type CostModel struct {
+	// FFTCrossoverBits is the operand size (in bits) above which FFT
+	// multiplication is faster than math/big on this machine.
+	FFTCrossoverBits int
+	// FFTThreshold is the size (in bits) at which the adaptive calculators
+	// switch to FFT multiplication (0 disables FFT).
+	FFTThreshold int
+	// ParallelThreshold is the size (in bits) above which a step's
+	// multiplications run in parallel (0 disables parallelism).
+	ParallelThreshold int
+	// StrassenThreshold is the size (in bits) above which matrix products
+	// use Strassen's 7-multiplication variant (0 disables it).
+	StrassenThreshold int
+	// NumCPU is the number of cores available to parallel steps.
+	NumCPU int
+}
+
+// algorithmProfile describes how a calculator spends its multiplications.
+type algorithmProfile struct {
+	// squares and muls per step (for matrix: the squaring of P).
+	squares, muls float64
+	// productMuls is the number of multiplications of the res*P product,
+	// which happens on the set bits of n (matrix only).
+	productMuls float64
+	// fftOnly forces FFT multiplication at every size.
+	fftOnly bool
+	// parallel reports whether the calculator parallelizes its steps.
+	parallel bool
+	// maxParallel is the number of independent multiplications per step.
+	maxParallel int
+}
+
+// costProfiles maps the built-in calculator names to their profiles.
+var costProfiles = map[string]algorithmProfile{
+	// F(2k+1) = F(k)² + F(k+1)², F(2k) = F(k)(2F(k+1) - F(k))
+	"fast": {squares: 2, muls: 1, parallel: true, maxParallel: 3},
+	// Same step as "fast", but always FFT and never parallel
+	"fft": {squares: 2, muls: 1, fftOnly: true},
+	// Symmetric squaring (a², b², d², b(a+d)) plus res*P on set bits
+	"matrix": {squares: 3, muls: 1, productMuls: 8, parallel: true, maxParallel: 4},
+}
+
+// Estimate returns the relative cost of computing F(n) with the named
+// calculator. Costs are only meaningful relative to each other.
+//
+// Parameters:
+//   - name: The calculator name ("fast", "matrix" or "fft").
+//   - n: The index of the Fibonacci number to compute.
+//
+// Returns:
+//   - float64: The estimated cost in arbitrary units.
+//   - bool: false if the model does not know the calculator.
+func (m CostModel) Estimate(name string, n uint64) (float64, bool) {
+	p, ok := costProfiles[name]
+	if !ok {
+		return 0, false
+	}
+	if n < 2 {
+		return 0, true
+	}
+
+	productFraction := float64(bits.OnesCount64(n)) / float64(bits.Len64(n))
+	total := 0.0
+	// Operand sizes halve at each step going back from the final one; the
+	// geometric decay makes the smallest steps negligible.
+	for size := float64(n) * FibonacciGrowthFactor; size >= 64; size /= 2 {
+		b := int(size)
+		unit := m.mulCost(b, p.fftOnly)
+		productMuls := p.productMuls
+		if productMuls > 0 && m.StrassenThreshold > 0 && b > m.StrassenThreshold {
+			productMuls = 7
+		}
+		stepOps := p.squares*squareCostFactor + p.muls + productMuls*productFraction
+		total += stepOps * unit / m.speedup(p, b)
+	}
+	return total, true
+}
+
+// Extrapolate predicts how long computing F(to) takes with the named
+// calculator from the measured time of F(from), scaling it by the ratio of
+// their estimated costs. Calculators the model does not know are assumed to
+// scale like "fast".
+//
+// Parameters:
+//   - name: The calculator name.
+//   - from: The index of the measured run.
+//   - took: The duration of the measured run.
+//   - to: The index to predict.
+//
+// Returns:
+//   - time.Duration: The predicted duration, took itself if from ≥ to.
+func (m CostModel) Extrapolate(name string, from uint64, took time.Duration, to uint64) time.Duration {
+	if from >= to {
+		return took
+	}
+	if _, ok := costProfiles[name]; !ok {
+		name = "fast"
+	}
+	base, _ := m.Estimate(name, from)
+	target, _ := m.Estimate(name, to)
+	if base <= 0 {
+		return took
+	}
+	return time.Duration(float64(took) * target / base)
+}
+
+// mulCost returns the cost of multiplying two operands of the given size.
+func (m CostModel) mulCost(sizeBits int, fftOnly bool) float64 {
+	words := float64(sizeBits) / 64
+	if words < 1 {
+		words = 1
+	}
+	useFFT := fftOnly || (m.FFTThreshold > 0 && sizeBits > m.FFTThreshold)
+	if !useFFT {
+		return math.Pow(words, karatsubaExponent)
+	}
+	crossover := float64(m.FFTCrossoverBits) / 64
+	if crossover < 4 {
+		crossover = 4
+	}
+	// Scale FFT so both curves meet at the crossover.
+	k := math.Pow(crossover, karatsubaExponent-1) / math.Log2(crossover)
+	return k * words * math.Log2(math.Max(words, 2))
+}
+
+// speedup returns the parallel speedup of a step on operands of the given size.
+func (m CostModel) speedup(p algorithmProfile, sizeBits int) float64 {
+	if !p.parallel || m.NumCPU <= 1 || m.ParallelThreshold <= 0 || sizeBits <= m.ParallelThreshold {
+		return 1
+	}
+	workers := min(m.NumCPU, p.maxParallel)
+	return 1 + float64(workers-1)*parallelEfficiency
+}
+
+// Selection is the outcome of choosing an algorithm with a CostModel.
+type Selection struct {
+	// Name is the chosen calculator.
+	Name string
+	// Costs holds the estimated cost of each modeled calculator.
+	Costs map[string]float64
+	// Rationale explains the choice in one line.
+	Rationale string
+}
+
+// Select chooses the modeled calculator with the lowest estimated cost
+// for F(n). Calculators the model does not know are ignored; if none is
+// known, DefaultSelectionPolicy decides.
+//
+// Parameters:
+//   - n: The index of the Fibonacci number to compute.
+//   - available: The names of the registered calculators.
+//
+// Returns:
+//   - Selection: The chosen calculator and the reasoning behind it.
+func (m CostModel) Select(n uint64, available []string) Selection {
+	costs := make(map[string]float64, len(available))
+	var modeled []string
+	for _, name := range available {
+		if cost, ok := m.Estimate(name, n); ok {
+			costs[name] = cost
+			modeled = append(modeled, name)
+		}
+	}
+	if len(modeled) == 0 {
+		return Selection{
+			Name:      DefaultSelectionPolicy(n, available),
+			Costs:     costs,
+			Rationale: "no cost model for the registered algorithms; using the default",
+		}
+	}
+
+	// Stable order: cheapest first, ties broken by name.
+	sort.Slice(modeled, func(i, j int) bool {
+		if costs[modeled[i]] != costs[modeled[j]] {
+			return costs[modeled[i]] < costs[modeled[j]]
+		}
+		return modeled[i] < modeled[j]
+	})
+	best := modeled[0]
+
+	parts := make([]string, 0, len(modeled))
+	for _, name := range modeled {
+		ratio := 1.0
+		if costs[best] > 0 {
+			ratio = costs[name] / costs[best]
+		}
+		parts = append(parts, fmt.Sprintf("%s %.2fx", name, ratio))
+	}
+	rationale := fmt.Sprintf("estimated cost %s (FFT crossover %d bits, FFT threshold %d bits, %d CPUs)",
+		strings.Join(parts, ", "), m.FFTCrossoverBits, m.FFTThreshold, m.NumCPU)
+
+	return Selection{Name: best, Costs: costs, Rationale: rationale}
+}
+
+// Policy adapts the model to a SelectionPolicy for DefaultFactory.
+//
+// Returns:
+//   - SelectionPolicy: A policy that picks the cheapest modeled calculator.
+func (m CostModel) Policy() SelectionPolicy {
+	return func(n uint64, available []string) string {
+		return m.Select(n, available).Name
+	}
+}
@@ -0,0 +1,111 @@
+package fibonacci
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCostModelEstimate(t *testing.T) {
+	t.Parallel()
+	m := CostModel{FFTCrossoverBits: 500_000, FFTThreshold: 500_000, ParallelThreshold: 4096, NumCPU: 8}
+
+	if _, ok := m.Estimate("plugin", 1000); ok {
+		t.Error("Estimate should not model unknown calculators")
+	}
+	if cost, ok := m.Estimate("fast", 1); !ok || cost != 0 {
+		t.Errorf("Estimate(fast, 1) = %v, %v; want 0, true", cost, ok)
+	}
+
+	small, _ := m.Estimate("fast", 10_000)
+	large, _ := m.Estimate("fast", 1_000_000)
+	if large <= small {
+		t.Errorf("cost should grow with n: F(10k)=%v, F(1M)=%v", small, large)
+	}
+}
+
+func TestCostModelExtrapolate(t *testing.T) {
+	t.Parallel()
+	m := CostModel{FFTCrossoverBits: 500_000, FFTThreshold: 500_000, ParallelThreshold: 4096, NumCPU: 8}
+
+	if got := m.Extrapolate("fast", 1_000_000, time.Second, 1000); got != time.Second {
+		t.Errorf("Extrapolate to a smaller n = %v, want the measured 1s", got)
+	}
+	got := m.Extrapolate("fast", 1_000_000, 10*time.Millisecond, 10_000_000)
+	// Superlinear but subquadratic: 10x the index costs 10x-100x the time
+	if got <= 100*time.Millisecond || got >= time.Second {
+		t.Errorf("Extrapolate(fast, 1M→10M, 10ms) = %v, want between 100ms and 1s", got)
+	}
+	if plugin := m.Extrapolate("plugin", 1_000_000, 10*time.Millisecond, 10_000_000); plugin != got {
+		t.Errorf("unknown calculators should scale like fast: %v, want %v", plugin, got)
+	}
+}
+
+func TestCostModelSelect(t *testing.T) {
+	t.Parallel()
+	all := []string{"fast", "fft", "matrix"}
+
+	tests := []struct {
+		name  string
+		model CostModel
+		n     uint64
+		want  string
+	}{
+		{
+			name:  "small n prefers fast doubling",
+			model: CostModel{FFTCrossoverBits: 500_000, FFTThreshold: 500_000, ParallelThreshold: 4096, NumCPU: 8},
+			n:     1_000,
+			want:  "fast",
+		},
+		{
+			name:  "large n on many cores prefers parallel fast doubling",
+			model: CostModel{FFTCrossoverBits: 500_000, FFTThreshold: 500_000, ParallelThreshold: 4096, NumCPU: 8},
+			n:     100_000_000,
+			want:  "fast",
+		},
+		{
+			name:  "FFT disabled for adaptive calculators makes fft win for huge n",
+			model: CostModel{FFTCrossoverBits: 500_000, FFTThreshold: 0, NumCPU: 1},
+			n:     100_000_000,
+			want:  "fft",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			sel := tt.model.Select(tt.n, all)
+			if sel.Name != tt.want {
+				t.Errorf("Select(%d) = %s, want %s (%s)", tt.n, sel.Name, tt.want, sel.Rationale)
+			}
+			if len(sel.Costs) != len(all) {
+				t.Errorf("expected costs for %d algorithms, got %v", len(all), sel.Costs)
+			}
+			if !strings.HasPrefix(sel.Rationale, "estimated cost "+tt.want+" 1.00x") {
+				t.Errorf("rationale should lead with the choice: %q", sel.Rationale)
+			}
+		})
+	}
+}
+
+func TestCostModelSelectFallsBackForUnknownCalculators(t *testing.T) {
+	t.Parallel()
+	sel := CostModel{}.Select(1000, []string{"plugin-a", "plugin-b"})
+	if sel.Name != "plugin-a" {
+		t.Errorf("Select = %s, want the default policy's choice plugin-a", sel.Name)
+	}
+}
+
+func TestCostModelPolicy(t *testing.T) {
+	t.Parallel()
+	factory := NewDefaultFactory()
+	factory.SetSelectionPolicy(CostModel{FFTCrossoverBits: 500_000, NumCPU: 1}.Policy())
+
+	calc, err := factory.Select(100_000_000)
+	if err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	if calc != factory.MustGet("fft") {
+		t.Errorf("expected fft with FFT disabled for adaptive calculators, got %s", calc.Name())
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/agbru/fibcalc/internal/fibonacci/threshold"
+	"github.com/agbru/fibcalc/internal/parallel"
 )
 
 // DoublingFramework encapsulates the common Fast Doubling algorithm logic.
@@ -150,6 +151,8 @@ func (f *DoublingFramework) ExecuteDoublingLoop(ctx context.Context, reporter Pr
 	// Normalize options to ensure consistent default threshold handling
 	currentOpts := normalizeOptions(opts)
 	dtm := f.dynamicThreshold
+	// k is the index of FK, only tracked for the step hook
+	var k uint64
 
 	for i := numBits - 1; i >= 0; i-- {
 		// Gate context cancellation check to reduce per-iteration overhead (IMPROVE §2.1)
@@ -162,6 +165,10 @@ func (f *DoublingFramework) ExecuteDoublingLoop(ctx context.Context, reporter Pr
 			}
 		}
 
+		// Let the progress display and signal handling run on a single
+		// processor; the multiplications also yield within the step.
+		parallel.MaybeYield()
+
 		// Track iteration timing for dynamic threshold adjustment
 		var iterStart time.Time
 		if dtm != nil {
@@ -184,11 +191,22 @@ func (f *DoublingFramework) ExecuteDoublingLoop(ctx context.Context, reporter Pr
 		// Execute the three multiplications for the doubling step:
 		// T3 = FK × FK1, T2 = FK², T1 = FK1²
 		// All three have independent destinations and read-only sources.
-		shouldParallel := useParallel && shouldParallelizeMultiplicationCached(currentOpts, fkBitLen, fk1BitLen)
+		shouldParallel := useParallel && shouldParallelizeMultiplicationCached(currentOpts, fkBitLen, fk1BitLen) &&
+			(currentOpts.ParallelGate == nil || currentOpts.ParallelGate.AllowParallel())
 		if shouldParallel {
 			usedParallel = true
 		}
-		if err := f.strategy.ExecuteStep(ctx, s, currentOpts, shouldParallel); err != nil {
+		// FFT steps report their progress as they run: the last few steps
+		// take most of the time and would otherwise leave progress frozen.
+		if usedFFT {
+			stepStart, stepWork := workDone, powers[numBits-1-i]
+			s.stepProgress = func(fraction float64) {
+				reporter((stepStart + fraction*stepWork) / totalWork)
+			}
+		}
+		err := f.strategy.ExecuteStep(ctx, s, currentOpts, shouldParallel)
+		s.stepProgress = nil
+		if err != nil {
 			return nil, fmt.Errorf("doubling step failed at bit %d/%d: %w", i, numBits-1, err)
 		}
 
@@ -204,6 +222,10 @@ func (f *DoublingFramework) ExecuteDoublingLoop(ctx context.Context, reporter Pr
 		// T2 and T3 become the old FK and FK1, now temporaries.
 		// T1 becomes the old T2 (free).
 		s.FK, s.FK1, s.T2, s.T3, s.T1 = s.T3, s.T1, s.FK, s.FK1, s.T2
+		if currentOpts.OnStep != nil {
+			k *= 2
+			currentOpts.OnStep(StepEvent{Kind: StepDoubling, Bit: i, Bits: numBits, K: k, Values: []*big.Int{s.FK, s.FK1}})
+		}
 
 		// Addition Step: If the i-th bit of n is 1, update F(k) and F(k+1)
 		// F(k) <- F(k+1)
@@ -217,6 +239,10 @@ func (f *DoublingFramework) ExecuteDoublingLoop(ctx context.Context, reporter Pr
 			// s.FK1 becomes the new sum (s.T1)
 			// s.T1 becomes the old s.FK, now a temporary
 			s.FK, s.FK1, s.T1 = s.FK1, s.T1, s.FK
+			if currentOpts.OnStep != nil {
+				k++
+				currentOpts.OnStep(StepEvent{Kind: StepAddition, Bit: i, Bits: numBits, K: k, Values: []*big.Int{s.FK, s.FK1}})
+			}
 		}
 
 		// Record metrics and check for threshold adjustments
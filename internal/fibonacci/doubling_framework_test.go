@@ -1,8 +1,12 @@
 package fibonacci
 
 import (
+	"context"
+	"math/big"
 	"testing"
+	"time"
 
+	"github.com/agbru/fibcalc/internal/bigfft"
 	"github.com/agbru/fibcalc/internal/fibonacci/threshold"
 )
 
@@ -59,3 +63,142 @@ func TestNewDoublingFrameworkWithDynamicThresholds(t *testing.T) {
 		}
 	})
 }
+
+// midStepStrategy reports half of every step that offers step progress.
+type midStepStrategy struct {
+	AdaptiveStrategy
+	midSteps int
+}
+
+func (m *midStepStrategy) ExecuteStep(ctx context.Context, s *CalculationState, opts Options, inParallel bool) error {
+	if s.stepProgress != nil {
+		m.midSteps++
+		s.stepProgress(0.5)
+	}
+	return m.AdaptiveStrategy.ExecuteStep(ctx, s, opts, inParallel)
+}
+
+func TestExecuteDoublingLoop_ReportsWithinFFTSteps(t *testing.T) {
+	t.Parallel()
+	strategy := &midStepStrategy{}
+	var reports []float64
+	reporter := func(p float64) { reports = append(reports, p) }
+
+	s := AcquireState()
+	defer ReleaseState(s)
+	const n = 100_000
+	got, err := NewDoublingFramework(strategy).ExecuteDoublingLoop(context.Background(), reporter, n,
+		Options{FFTThreshold: 10_000, ParallelThreshold: 1 << 30}, s, false)
+	if err != nil {
+		t.Fatalf("ExecuteDoublingLoop: %v", err)
+	}
+	if got.Cmp(calculateReference(n)) != 0 {
+		t.Fatal("wrong result")
+	}
+	if strategy.midSteps == 0 {
+		t.Fatal("no FFT step was offered step progress")
+	}
+	for i := 1; i < len(reports); i++ {
+		if reports[i] < reports[i-1] {
+			t.Errorf("progress went backwards: %v", reports)
+			break
+		}
+	}
+	if s.stepProgress != nil {
+		t.Error("stepProgress should be cleared after the loop")
+	}
+}
+
+// parallelCountingStrategy counts the steps executed in parallel.
+type parallelCountingStrategy struct {
+	AdaptiveStrategy
+	parallel, steps int
+}
+
+func (p *parallelCountingStrategy) ExecuteStep(ctx context.Context, s *CalculationState, opts Options, inParallel bool) error {
+	p.steps++
+	if inParallel {
+		p.parallel++
+	}
+	return p.AdaptiveStrategy.ExecuteStep(ctx, s, opts, inParallel)
+}
+
+// fixedGate is a ParallelGate with a fixed answer.
+type fixedGate bool
+
+func (g fixedGate) AllowParallel() bool { return bool(g) }
+
+func TestExecuteDoublingLoop_ParallelGate(t *testing.T) {
+	t.Parallel()
+	const n = 100_000
+	tests := []struct {
+		name         string
+		gate         ParallelGate
+		wantParallel bool
+	}{
+		{"no gate", nil, true},
+		{"allowing gate", fixedGate(true), true},
+		{"refusing gate", fixedGate(false), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			strategy := &parallelCountingStrategy{}
+			s := AcquireState()
+			defer ReleaseState(s)
+			opts := Options{ParallelThreshold: 64, FFTThreshold: 1 << 30, ParallelGate: tt.gate}
+			got, err := NewDoublingFramework(strategy).ExecuteDoublingLoop(context.Background(), func(float64) {}, n, opts, s, true)
+			if err != nil {
+				t.Fatalf("ExecuteDoublingLoop: %v", err)
+			}
+			if got.Cmp(calculateReference(n)) != 0 {
+				t.Fatal("wrong result")
+			}
+			if gotParallel := strategy.parallel > 0; gotParallel != tt.wantParallel {
+				t.Errorf("%d of %d steps in parallel, want parallel steps: %v", strategy.parallel, strategy.steps, tt.wantParallel)
+			}
+		})
+	}
+}
+
+// calculateReference computes F(n) with plain iteration.
+func calculateReference(n uint64) *big.Int {
+	a, b := big.NewInt(0), big.NewInt(1)
+	for range n {
+		a.Add(a, b)
+		a, b = b, a
+	}
+	return a
+}
+
+func TestWatchStepProgress(t *testing.T) {
+	t.Parallel()
+	x := new(big.Int).Lsh(big.NewInt(1), 100_000)
+	k, m := bigfft.GetFFTParams(2*len(x.Bits()) + FFTSafetyMarginWords)
+	n := bigfft.ValueSize(k, m, 2)
+	meter := new(bigfft.WorkMeter)
+	p := bigfft.PolyFromInt(x, k, m)
+	p.Meter = meter
+	if _, err := p.Transform(n); err != nil {
+		t.Fatalf("Transform: %v", err)
+	}
+
+	got := make(chan float64, 100)
+	stop := watchStepProgress(meter, 2*bigfft.TransformWork(k, n), func(f float64) { got <- f })
+	select {
+	case f := <-got:
+		if f != 0.5 {
+			t.Errorf("fraction = %v, want 0.5 after one of two transforms", f)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no progress reported")
+	}
+	stop()
+	for len(got) > 0 {
+		<-got
+	}
+	time.Sleep(ProgressUpdateInterval)
+	if len(got) != 0 {
+		t.Error("progress reported after stop")
+	}
+}
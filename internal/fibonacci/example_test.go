@@ -45,7 +45,7 @@ func ExampleDefaultFactory() {
 
 	fmt.Println(result)
 	// Output:
-	// [fast fft matrix]
+	// [fast fft lowmem matrix]
 	// 55
 }
 
@@ -0,0 +1,111 @@
+// This file provides failure injection: a middleware that makes calculations
+// fail in a chosen way, so that integrations can test their error handling.
+
+package fibonacci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/fibonacci/memory"
+)
+
+// FailureMode selects the failure simulated by WithFailureInjection.
+type FailureMode string
+
+const (
+	// FailTimeout makes the calculation fail as if its deadline was reached.
+	FailTimeout FailureMode = "timeout"
+	// FailMismatch returns a result offset by the calculator index, so that
+	// compared algorithms disagree (the first calculator stays correct).
+	FailMismatch FailureMode = "mismatch"
+	// FailPanic panics inside the calculation.
+	FailPanic FailureMode = "panic"
+	// FailOOM makes the calculation fail with an out-of-memory error.
+	FailOOM FailureMode = "oom"
+)
+
+// errInjected cancels the calculation when an injected failure fires.
+var errInjected = errors.New("injected failure")
+
+// FailureInjection describes a simulated failure.
+type FailureInjection struct {
+	// Mode is the kind of failure.
+	Mode FailureMode
+	// After is the time from the start of the calculation at which the
+	// failure occurs. A calculation that finishes earlier is held until then,
+	// so the failure always happens at the same point.
+	After time.Duration
+}
+
+// NewFailureInjection returns the failure injection for a --fail-mode and
+// --fail-after pair, or nil when mode is empty.
+//
+// Parameters:
+//   - mode: The failure mode ("timeout", "mismatch", "panic", "oom" or "").
+//   - after: The delay before the failure.
+//
+// Returns:
+//   - *FailureInjection: The injection, or nil if none is requested.
+func NewFailureInjection(mode string, after time.Duration) *FailureInjection {
+	if mode == "" {
+		return nil
+	}
+	return &FailureInjection{Mode: FailureMode(mode), After: after}
+}
+
+// WithFailureInjection simulates the failure f after f.After. Timeout, panic
+// and out-of-memory failures stop the calculation at that point; mismatch
+// lets it complete and then alters the result. A cancellation of the parent
+// context is returned as is, before any failure is injected.
+//
+// Panics are not recovered here: place WithRecovery outside this middleware.
+func WithFailureInjection(f FailureInjection) Middleware {
+	return NewMiddleware(func(_ string, next CalculateFunc) CalculateFunc {
+		return func(ctx context.Context, progressChan chan<- ProgressUpdate, calcIndex int, n uint64, opts Options) (*big.Int, error) {
+			start := time.Now()
+			calcCtx := ctx
+			if f.Mode != FailMismatch {
+				var cancel context.CancelCauseFunc
+				calcCtx, cancel = context.WithCancelCause(ctx)
+				defer cancel(nil)
+				timer := time.AfterFunc(f.After, func() { cancel(errInjected) })
+				defer timer.Stop()
+			}
+
+			result, err := next(calcCtx, progressChan, calcIndex, n, opts)
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if wait := f.After - time.Since(start); wait > 0 {
+				select {
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				case <-time.After(wait):
+				}
+			}
+
+			msg := fmt.Sprintf("%v (%s) after %s", errInjected, f.Mode, f.After)
+			switch f.Mode {
+			case FailTimeout:
+				return nil, fmt.Errorf("%s: %w", msg, context.DeadlineExceeded)
+			case FailPanic:
+				panic(msg)
+			case FailOOM:
+				return nil, fmt.Errorf("%s: %w", msg, apperrors.MemoryError{
+					Requested: memory.EstimateMemoryUsage(n).TotalBytes,
+				})
+			case FailMismatch:
+				if err != nil {
+					return nil, err
+				}
+				return new(big.Int).Add(result, big.NewInt(int64(calcIndex))), nil
+			}
+			return result, err
+		}
+	})
+}
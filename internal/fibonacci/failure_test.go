@@ -0,0 +1,104 @@
+package fibonacci
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+)
+
+func TestNewFailureInjection(t *testing.T) {
+	t.Parallel()
+	if f := NewFailureInjection("", time.Second); f != nil {
+		t.Errorf("empty mode = %+v, want nil", f)
+	}
+	f := NewFailureInjection("oom", time.Second)
+	if f == nil || f.Mode != FailOOM || f.After != time.Second {
+		t.Errorf("NewFailureInjection(oom, 1s) = %+v", f)
+	}
+}
+
+func TestWithFailureInjection(t *testing.T) {
+	t.Parallel()
+	const after = 20 * time.Millisecond
+
+	tests := []struct {
+		mode  FailureMode
+		check func(t *testing.T, res *big.Int, err error)
+	}{
+		{FailTimeout, func(t *testing.T, res *big.Int, err error) {
+			if res != nil || !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("got (%v, %v), want a deadline error", res, err)
+			}
+		}},
+		{FailOOM, func(t *testing.T, res *big.Int, err error) {
+			var memErr apperrors.MemoryError
+			if res != nil || !errors.As(err, &memErr) {
+				t.Errorf("got (%v, %v), want a MemoryError", res, err)
+			}
+		}},
+		{FailPanic, func(t *testing.T, res *big.Int, err error) {
+			if res != nil || err == nil || !strings.Contains(err.Error(), "panic: injected failure (panic)") {
+				t.Errorf("got (%v, %v), want a recovered panic", res, err)
+			}
+		}},
+		{FailMismatch, func(t *testing.T, res *big.Int, err error) {
+			// Calculator index 2 is offset by 2.
+			if err != nil || res.Cmp(big.NewInt(57)) != 0 {
+				t.Errorf("got (%v, %v), want (57, nil)", res, err)
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			t.Parallel()
+			calc := WrapCalculator(&MockCalculator{Result: big.NewInt(55)},
+				WithRecovery(), WithFailureInjection(FailureInjection{Mode: tt.mode, After: after}))
+
+			start := time.Now()
+			res, err := calc.Calculate(context.Background(), nil, 2, 10, Options{})
+			if elapsed := time.Since(start); elapsed < after {
+				t.Errorf("failure after %v, want at least %v", elapsed, after)
+			}
+			tt.check(t, res, err)
+		})
+	}
+}
+
+func TestWithFailureInjection_StopsLongCalculation(t *testing.T) {
+	t.Parallel()
+	calc := WrapCalculator(&MockCalculator{Fn: func(ctx context.Context, _ uint64) (*big.Int, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}}, WithFailureInjection(FailureInjection{Mode: FailTimeout, After: 10 * time.Millisecond}))
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := calc.Calculate(context.Background(), nil, 0, 10, Options{})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("err = %v, want a deadline error", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the injected failure did not stop the calculation")
+	}
+}
+
+func TestWithFailureInjection_ParentCanceled(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calc := WrapCalculator(&MockCalculator{Result: big.NewInt(1)},
+		WithFailureInjection(FailureInjection{Mode: FailOOM, After: time.Hour}))
+
+	if _, err := calc.Calculate(ctx, nil, 0, 10, Options{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
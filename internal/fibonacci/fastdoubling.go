@@ -6,7 +6,6 @@ import (
 	"runtime"
 	"sync"
 
-	"github.com/agbru/fibcalc/internal/fibonacci/memory"
 	"github.com/agbru/fibcalc/internal/fibonacci/threshold"
 )
 
@@ -75,6 +74,19 @@ func (fd *OptimizedFastDoubling) Name() string {
 	return "Fast Doubling (O(log n), Parallel, Zero-Alloc)"
 }
 
+// Info describes the algorithm for listings and automatic selection.
+//
+// Returns:
+//   - AlgorithmInfo: The algorithm's metadata.
+func (fd *OptimizedFastDoubling) Info() AlgorithmInfo {
+	return AlgorithmInfo{
+		Complexity: "O(M(n) log n), 3 multiplications per bit",
+		Memory:     "heap, about 15 operands of F(n) at the peak, pooled across runs",
+		Parallel:   true,
+		Notes:      "The default: the fastest calculator at every size without calibration data.",
+	}
+}
+
 // CalculateCore computes F(n) using the Fast Doubling algorithm.
 //
 // This function orchestrates the entire calculation process, which includes:
@@ -94,25 +106,8 @@ func (fd *OptimizedFastDoubling) Name() string {
 //   - *big.Int: The calculated Fibonacci number.
 //   - error: An error if one occurred (e.g., context cancellation).
 func (fd *OptimizedFastDoubling) CalculateCore(ctx context.Context, reporter ProgressCallback, n uint64, opts Options) (*big.Int, error) {
-	s := AcquireState()
-	defer ReleaseState(s)
-
-	// Create arena for contiguous memory allocation.
-	// Pre-size all big.Int buffers from the arena to avoid per-buffer
-	// GC tracking and reduce memory fragmentation.
-	arena := memory.NewCalculationArena(n)
-	if n > 1000 {
-		estimatedBits := int(float64(n) * FibonacciGrowthFactor)
-		estimatedWords := (estimatedBits + 63) / 64
-		arena.PreSizeFromArena(s.FK, estimatedWords)
-		arena.PreSizeFromArena(s.FK1, estimatedWords)
-		s.FK.SetInt64(0)
-		s.FK1.SetInt64(1)
-		arena.PreSizeFromArena(s.T1, estimatedWords)
-		arena.PreSizeFromArena(s.T2, estimatedWords)
-		arena.PreSizeFromArena(s.T3, estimatedWords)
-	}
-	_ = arena // arena's backing block lives until the function returns
+	s := opts.StatePool.acquire(n)
+	defer opts.StatePool.release(s)
 
 	// Normalize options to ensure consistent default threshold handling
 	normalizedOpts := normalizeOptions(opts)
@@ -135,6 +130,9 @@ func (fd *OptimizedFastDoubling) CalculateCore(ctx context.Context, reporter Pro
 			AdjustmentInterval:       interval,
 			Enabled:                  true,
 		})
+		if opts.Logger != nil {
+			dtm.SetLogger(*opts.Logger)
+		}
 		framework = NewDoublingFrameworkWithDynamicThresholds(strategy, dtm)
 	} else {
 		framework = NewDoublingFramework(strategy)
@@ -218,6 +216,11 @@ func shouldParallelizeMultiplicationCached(opts Options, fkBitLen, fk1BitLen int
 // intermediate multiplication results.
 type CalculationState struct {
 	FK, FK1, T1, T2, T3 *big.Int
+
+	// stepProgress, if set, receives the fraction (0.0 to 1.0) of the
+	// current doubling step completed so far. It is only honored by FFT
+	// steps, which are the ones long enough to need it.
+	stepProgress func(fraction float64)
 }
 
 // Reset prepares the state for a new calculation.
@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/agbru/fibcalc/internal/bigfft"
+	"github.com/agbru/fibcalc/internal/fibonacci/mul"
 )
 
 // FFTSafetyMarginWords is the safety margin added to FFT word count to avoid overflow.
@@ -45,39 +47,17 @@ func sqrFFT(x *big.Int) (*big.Int, error) {
 	return bigfft.Sqr(x)
 }
 
+// smartMultiply performs multiplication with the default tiered backend:
+// FFT (internal/bigfft) when both operands exceed fftThreshold bits, and
+// math/big otherwise.
 func smartMultiply(z, x, y *big.Int, fftThreshold int) (*big.Int, error) {
-	if z == nil {
-		z = new(big.Int)
-	}
-
-	bx := x.BitLen()
-	by := y.BitLen()
-
-	// Tier 1: FFT Multiplication for very large operands
-	if fftThreshold > 0 && bx > fftThreshold && by > fftThreshold {
-		return bigfft.MulTo(z, x, y)
-	}
-
-	// Tier 2: math/big Multiplication (uses optimized algorithms internally)
-	return z.Mul(x, y), nil
+	return mul.NewTiered(fftThreshold).MulTo(z, x, y)
 }
 
 // smartSquare performs optimized squaring, choosing between math/big.Mul and
 // FFT (internal/bigfft) based on the operand size.
 func smartSquare(z, x *big.Int, fftThreshold int) (*big.Int, error) {
-	if z == nil {
-		z = new(big.Int)
-	}
-
-	bx := x.BitLen()
-
-	// Tier 1: FFT Squaring for very large operands
-	if fftThreshold > 0 && bx > fftThreshold {
-		return bigfft.SqrTo(z, x)
-	}
-
-	// Tier 2: math/big Squaring (uses optimized algorithms internally)
-	return z.Mul(x, x), nil
+	return mul.NewTiered(fftThreshold).SqrTo(z, x)
 }
 
 // executeDoublingStepFFT performs the three multiplications of a doubling step
@@ -100,21 +80,68 @@ func executeDoublingStepFFT(ctx context.Context, s *CalculationState, opts Optio
 	n := nWords
 
 	pFk := bigfft.PolyFromInt(s.FK, k, m)
-	fkPoly, err := pFk.Transform(n)
+	pFk1 := bigfft.PolyFromInt(s.FK1, k, m)
+	if s.stepProgress != nil {
+		// Two forward transforms, then three pointwise products and three
+		// inverse transforms.
+		meter := new(bigfft.WorkMeter)
+		pFk.Meter, pFk1.Meter = meter, meter
+		total := 5*bigfft.TransformWork(k, n) + 3*bigfft.PointwiseWork(k, n)
+		defer watchStepProgress(meter, total, s.stepProgress)()
+	}
+
+	// With a spill, large transforms keep their values in a mapped file
+	// released once the products are done
+	words := (n + 1) << k
+	ws, release, err := opts.spill.Workspace(2*words+4*(n+1), 2*words)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	fkPoly, err := pFk.TransformWithBump(n, ws)
 	if err != nil {
 		return fmt.Errorf("FFT transform FK failed: %w", err)
 	}
 
-	pFk1 := bigfft.PolyFromInt(s.FK1, k, m)
-	fk1Poly, err := pFk1.Transform(n)
+	fk1Poly, err := pFk1.TransformWithBump(n, ws)
 	if err != nil {
 		return fmt.Errorf("FFT transform FK1 failed: %w", err)
 	}
 
 	if inParallel {
-		return executeFFTTransformsParallel(ctx, &fkPoly, &fk1Poly, s, m)
+		return executeFFTTransformsParallel(ctx, &fkPoly, &fk1Poly, s, m, opts.spill)
+	}
+	return executeFFTTransformsSequential(ctx, &fkPoly, &fk1Poly, s, m, opts.spill)
+}
+
+// watchStepProgress reports the work counted by meter as a fraction of
+// total, every half ProgressUpdateInterval, until the returned function is
+// called. The stop function waits for the last report, so report is never
+// called concurrently with the caller once it returns.
+func watchStepProgress(meter *bigfft.WorkMeter, total uint64, report func(float64)) (stop func()) {
+	if total == 0 {
+		return func() {}
+	}
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(ProgressUpdateInterval / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				report(min(1, float64(meter.Done())/float64(total)))
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-finished
 	}
-	return executeFFTTransformsSequential(ctx, &fkPoly, &fk1Poly, s, m)
 }
 
 // executeFFTTransformsParallel performs the three FFT pointwise multiplications
@@ -126,93 +153,83 @@ func executeDoublingStepFFT(ctx context.Context, s *CalculationState, opts Optio
 // PolValues are never modified. Multiple concurrent readers with no writers
 // is safe, eliminating two Clone() calls that previously allocated and
 // copied K*(n+1) words each (e.g., ~hundreds of KB for F(10M)).
-func executeFFTTransformsParallel(ctx context.Context, fkPoly, fk1Poly *bigfft.PolValues, s *CalculationState, m int) error {
+func executeFFTTransformsParallel(ctx context.Context, fkPoly, fk1Poly *bigfft.PolValues, s *CalculationState, m int, spill *bigfft.Spill) error {
 	return executeParallel3(ctx,
 		func() error {
-			v, err := fkPoly.Mul(fk1Poly)
-			if err != nil {
-				return err
-			}
-			p, err := v.InvTransform()
-			if err != nil {
-				return err
-			}
-			p.M = m
-			s.T3 = p.IntToBigInt(s.T3)
-			return nil
+			return fftProduct(s.T3, fkPoly, fk1Poly, m, spill)
 		},
 		func() error {
-			v, err := fk1Poly.Sqr()
-			if err != nil {
-				return err
-			}
-			p, err := v.InvTransform()
-			if err != nil {
-				return err
-			}
-			p.M = m
-			s.T1 = p.IntToBigInt(s.T1)
-			return nil
+			return fftProduct(s.T1, fk1Poly, nil, m, spill)
 		},
 		func() error {
-			v, err := fkPoly.Sqr()
-			if err != nil {
-				return err
-			}
-			p, err := v.InvTransform()
-			if err != nil {
-				return err
-			}
-			p.M = m
-			s.T2 = p.IntToBigInt(s.T2)
-			return nil
+			return fftProduct(s.T2, fkPoly, nil, m, spill)
 		},
 	)
 }
 
 // executeFFTTransformsSequential performs the three FFT pointwise multiplications
 // and inverse transforms sequentially with context cancellation checks between operations.
-func executeFFTTransformsSequential(ctx context.Context, fkPoly, fk1Poly *bigfft.PolValues, s *CalculationState, m int) error {
-	v1, err := fkPoly.Mul(fk1Poly)
-	if err != nil {
+func executeFFTTransformsSequential(ctx context.Context, fkPoly, fk1Poly *bigfft.PolValues, s *CalculationState, m int, spill *bigfft.Spill) error {
+	if err := fftProduct(s.T3, fkPoly, fk1Poly, m, spill); err != nil {
 		return err
 	}
-	p1, err := v1.InvTransform()
-	if err != nil {
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("canceled after FFT multiply: %w", err)
+	}
+
+	if err := fftProduct(s.T1, fk1Poly, nil, m, spill); err != nil {
 		return err
 	}
-	p1.M = m
-	s.T3 = p1.IntToBigInt(s.T3)
 
 	if err := ctx.Err(); err != nil {
-		return fmt.Errorf("canceled after FFT multiply: %w", err)
+		return fmt.Errorf("canceled after FFT square FK1: %w", err)
 	}
 
-	v2, err := fk1Poly.Sqr()
-	if err != nil {
+	if err := fftProduct(s.T2, fkPoly, nil, m, spill); err != nil {
 		return err
 	}
-	p2, err := v2.InvTransform()
+
+	return nil
+}
+
+// fftProduct computes the pointwise product of v and w, or the square of v
+// if w is nil, and stores its inverse transform in z, reusing its buffer.
+// Each product has its own spill workspace, so that the parallel products
+// do not share one.
+//
+// Parameters:
+//   - z: The destination.
+//   - v, w: The transformed operands.
+//   - m: The coefficient length of the result in words.
+//   - spill: The spill of the calculation, or nil.
+//
+// Returns:
+//   - error: An error if a transform or the spill failed.
+func fftProduct(z *big.Int, v, w *bigfft.PolValues, m int, spill *bigfft.Spill) error {
+	// The product and its inverse, with the pointwise multiplication
+	// buffer (8n words) and the inverse transform temporaries
+	words := (v.N + 1) << v.K
+	ws, release, err := spill.Workspace(11*(v.N+1), 2*words)
 	if err != nil {
 		return err
 	}
-	p2.M = m
-	s.T1 = p2.IntToBigInt(s.T1)
+	defer release()
 
-	if err := ctx.Err(); err != nil {
-		return fmt.Errorf("canceled after FFT square FK1: %w", err)
+	var r bigfft.PolValues
+	if w == nil {
+		r, err = v.SqrWithBump(ws)
+	} else {
+		r, err = v.MulWithBump(w, ws)
 	}
-
-	v3, err := fkPoly.Sqr()
 	if err != nil {
 		return err
 	}
-	p3, err := v3.InvTransform()
+	p, err := r.InvTransformWithBump(ws)
 	if err != nil {
 		return err
 	}
-	p3.M = m
-	s.T2 = p3.IntToBigInt(s.T2)
-
+	p.M = m
+	p.IntToBigInt(z)
 	return nil
 }
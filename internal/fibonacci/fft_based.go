@@ -3,8 +3,6 @@ package fibonacci
 import (
 	"context"
 	"math/big"
-
-	"github.com/agbru/fibcalc/internal/fibonacci/memory"
 )
 
 // FFTBasedCalculator is a specialized Fibonacci calculator that uses the Fast
@@ -28,6 +26,20 @@ func (c *FFTBasedCalculator) Name() string {
 	return "FFT-Based Doubling"
 }
 
+// Info describes the algorithm for listings and automatic selection. Below
+// the FFT threshold, FFT multiplication loses to math/big, hence MinN.
+//
+// Returns:
+//   - AlgorithmInfo: The algorithm's metadata.
+func (c *FFTBasedCalculator) Info() AlgorithmInfo {
+	return AlgorithmInfo{
+		Complexity: "O(M(n) log n), every multiplication by FFT",
+		Memory:     "heap, fast doubling operands plus FFT transform buffers",
+		MinN:       1_000_000,
+		Notes:      "Wins over the adaptive calculators only when they switch to FFT late or never.",
+	}
+}
+
 // CalculateCore computes F(n) using the Fast Doubling algorithm, with all
 // multiplications performed via FFT.
 //
@@ -47,23 +59,8 @@ func (c *FFTBasedCalculator) Name() string {
 //   - *big.Int: The calculated Fibonacci number.
 //   - error: An error if one occurred (e.g., context cancellation).
 func (c *FFTBasedCalculator) CalculateCore(ctx context.Context, reporter ProgressCallback, n uint64, opts Options) (*big.Int, error) {
-	s := AcquireState()
-	defer ReleaseState(s)
-
-	// Create arena for contiguous memory allocation.
-	arena := memory.NewCalculationArena(n)
-	if n > 1000 {
-		estimatedBits := int(float64(n) * FibonacciGrowthFactor)
-		estimatedWords := (estimatedBits + 63) / 64
-		arena.PreSizeFromArena(s.FK, estimatedWords)
-		arena.PreSizeFromArena(s.FK1, estimatedWords)
-		s.FK.SetInt64(0)
-		s.FK1.SetInt64(1)
-		arena.PreSizeFromArena(s.T1, estimatedWords)
-		arena.PreSizeFromArena(s.T2, estimatedWords)
-		arena.PreSizeFromArena(s.T3, estimatedWords)
-	}
-	_ = arena
+	s := opts.StatePool.acquire(n)
+	defer opts.StatePool.release(s)
 
 	// Use framework with FFT-only strategy
 	strategy := &FFTOnlyStrategy{}
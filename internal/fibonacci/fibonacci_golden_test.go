@@ -34,6 +34,7 @@ func TestCalculatorsAgainstGoldenFile(t *testing.T) {
 		"FastDoubling": NewCalculator(&OptimizedFastDoubling{}),
 		"MatrixExp":    NewCalculator(&MatrixExponentiation{}),
 		"FFTBased":     NewCalculator(&FFTBasedCalculator{}),
+		"LowMemory":    NewCalculator(&LowMemoryFastDoubling{}),
 	}
 
 	ctx := context.Background()
@@ -50,7 +51,7 @@ func TestCalculatorsAgainstGoldenFile(t *testing.T) {
 					expected := new(big.Int)
 					expected.SetString(tc.Result, 10)
 
-					got, err := calc.Calculate(ctx, nil, 0, tc.N, Options{ParallelThreshold: DefaultParallelThreshold})
+					got, err := calc.Calculate(ctx, nil, 0, tc.N, Options{ParallelThreshold: DefaultParallelThreshold, DisableTables: true})
 					if err != nil {
 						t.Fatalf("Calculation failed for N=%d: %v", tc.N, err)
 					}
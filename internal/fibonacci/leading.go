@@ -0,0 +1,207 @@
+package fibonacci
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+	"strings"
+)
+
+// MaxLeadingDigitsN is the largest n accepted by LeadingDigits: beyond it,
+// φ^n overflows the exponent range of big.Float.
+const MaxLeadingDigitsN uint64 = 3_000_000_000
+
+// leadingGuard is the number of digits computed beyond the requested ones,
+// so that the rounding of the last requested digit is usually settled.
+const leadingGuard = 10
+
+// leadingAttempts is the number of times LeadingDigits doubles the working
+// precision before settling for fewer certain digits.
+const leadingAttempts = 3
+
+// LeadingDigits returns the first k decimal digits of F(n) without
+// computing F(n), from F(n) = φ^n/√5 − ψ^n/√5 where |ψ^n/√5| < 1/2.
+//
+// φ^n/√5 is evaluated in interval arithmetic (big.Float with directed
+// rounding), so the result is an interval that certainly contains F(n);
+// only the digits shared by both ends of the interval are returned. If the
+// precision is not enough for k certain digits, it is doubled a few times,
+// and then fewer than k digits are returned rather than uncertain ones.
+// When F(n) has at most k digits (plus a margin), its exact value is
+// computed with modular arithmetic instead.
+//
+// Parameters:
+//   - ctx: The context for cancellation.
+//   - n: The index, at most MaxLeadingDigitsN.
+//   - k: The number of digits wanted, positive.
+//
+// Returns:
+//   - string: The certain leading digits, at most k of them.
+//   - uint64: The number of decimal digits of F(n).
+//   - error: An error if k or n is out of range, or ctx is done.
+func LeadingDigits(ctx context.Context, n uint64, k int) (string, uint64, error) {
+	if k <= 0 {
+		return "", 0, fmt.Errorf("digit count must be positive, got %d", k)
+	}
+	if n > MaxLeadingDigitsN {
+		return "", 0, fmt.Errorf("leading digits are limited to n ≤ %d, got %d", MaxLeadingDigitsN, n)
+	}
+
+	estimate := DecimalDigits(n)
+	if uint64(k)+leadingGuard >= estimate {
+		// F(n) is short enough to compute exactly: modulo 10^(digits+2),
+		// the residue is the value itself.
+		digits, err := LastDigits(ctx, n, int(estimate)+2)
+		if err != nil {
+			return "", 0, err
+		}
+		value := strings.TrimLeft(digits, "0")
+		if value == "" {
+			value = "0"
+		}
+		return value[:min(k, len(value))], uint64(len(value)), nil
+	}
+
+	// Drop all digits but the first k+leadingGuard: bound F(n)/10^shift.
+	shift := estimate - uint64(k) - leadingGuard
+	prec := uint(math.Ceil(float64(k+leadingGuard)*math.Log2(10))) + 2*uint(bits.Len64(n)) + 64
+	var prefix string
+	var total uint64
+	for range leadingAttempts {
+		lo, hi, err := leadingBounds(ctx, n, shift, prec)
+		if err != nil {
+			return "", 0, err
+		}
+		a, b := lo.String(), hi.String()
+		if len(a) == len(b) {
+			prefix, total = commonPrefix(a, b), uint64(len(a))+shift
+			if len(prefix) >= k {
+				return prefix[:k], total, nil
+			}
+		}
+		prec *= 2
+	}
+	if prefix == "" {
+		return "", 0, fmt.Errorf("cannot bound the leading digits of F(%d)", n)
+	}
+	return prefix, total, nil
+}
+
+// DecimalDigits returns the number of decimal digits of F(n) from the
+// closed form ⌊n·log10(φ) − log10(√5)⌋ + 1, without computing F(n). The
+// float64 evaluation may be off by one for huge n, when the fractional part
+// of the closed form is within rounding of an integer.
+//
+// Parameters:
+//   - n: The index of the Fibonacci number.
+//
+// Returns:
+//   - uint64: The number of decimal digits of F(n).
+func DecimalDigits(n uint64) uint64 {
+	if n < 2 {
+		return 1
+	}
+	return uint64(math.Floor(float64(n)*math.Log10(math.Phi)-math.Log10(math.Sqrt(5)))) + 1
+}
+
+// leadingBounds returns integers lo ≤ ⌊F(n)/10^shift⌋ ≤ hi, evaluated at
+// prec bits with outward rounding.
+func leadingBounds(ctx context.Context, n, shift uint64, prec uint) (*big.Int, *big.Int, error) {
+	sqrt5Lo, sqrt5Hi := sqrtBounds(5, prec)
+	one := big.NewFloat(1)
+	phiLo := new(big.Float).SetPrec(prec).SetMode(big.ToNegativeInf).Add(sqrt5Lo, one)
+	phiLo.Quo(phiLo, big.NewFloat(2))
+	phiHi := new(big.Float).SetPrec(prec).SetMode(big.ToPositiveInf).Add(sqrt5Hi, one)
+	phiHi.Quo(phiHi, big.NewFloat(2))
+
+	// F(n) ∈ [φ^n/√5 − 1/2, φ^n/√5 + 1/2] and 10^shift ∈ [tenLo, tenHi].
+	lo, err := powBound(ctx, phiLo, n, prec, big.ToNegativeInf)
+	if err != nil {
+		return nil, nil, err
+	}
+	lo.Quo(lo, sqrt5Hi).Sub(lo, big.NewFloat(0.5))
+	hi, err := powBound(ctx, phiHi, n, prec, big.ToPositiveInf)
+	if err != nil {
+		return nil, nil, err
+	}
+	hi.Quo(hi, sqrt5Lo).Add(hi, big.NewFloat(0.5))
+
+	ten := big.NewFloat(10)
+	tenLo, err := powBound(ctx, ten, shift, prec, big.ToNegativeInf)
+	if err != nil {
+		return nil, nil, err
+	}
+	tenHi, err := powBound(ctx, ten, shift, prec, big.ToPositiveInf)
+	if err != nil {
+		return nil, nil, err
+	}
+	lo.Quo(lo, tenHi)
+	hi.Quo(hi, tenLo)
+
+	// Both are positive, so truncation is the floor.
+	loInt, _ := lo.Int(nil)
+	hiInt, _ := hi.Int(nil)
+	return loInt, hiInt, nil
+}
+
+// sqrtBounds returns lo ≤ √x ≤ hi at prec bits. big.Float.Sqrt does not
+// guarantee its rounding, so the bounds are checked by squaring exactly.
+func sqrtBounds(x int64, prec uint) (*big.Float, *big.Float) {
+	target := new(big.Float).SetInt64(x)
+	root := new(big.Float).SetPrec(prec).Sqrt(target)
+	square := func(f *big.Float) *big.Float {
+		return new(big.Float).SetPrec(2*prec+2).Mul(f, f)
+	}
+	lo, hi := new(big.Float).Copy(root), new(big.Float).Copy(root)
+	for square(lo).Cmp(target) > 0 {
+		lo = nextFloat(lo, prec, -1)
+	}
+	for square(hi).Cmp(target) < 0 {
+		hi = nextFloat(hi, prec, 1)
+	}
+	return lo, hi
+}
+
+// nextFloat returns the neighbor of the positive x at prec bits, above it if
+// dir > 0 and below it otherwise.
+func nextFloat(x *big.Float, prec uint, dir int) *big.Float {
+	exp := x.MantExp(nil)
+	ulp := new(big.Float).SetMantExp(big.NewFloat(1), exp-int(prec))
+	z := new(big.Float).SetPrec(prec)
+	if dir > 0 {
+		return z.SetMode(big.ToPositiveInf).Add(x, ulp)
+	}
+	return z.SetMode(big.ToNegativeInf).Sub(x, ulp)
+}
+
+// powBound returns base^e at prec bits, every product rounded with mode.
+// For base ≥ 1 and a directed mode, the result is a bound of the exact
+// power in that direction.
+func powBound(ctx context.Context, base *big.Float, e uint64, prec uint, mode big.RoundingMode) (*big.Float, error) {
+	result := new(big.Float).SetPrec(prec).SetMode(mode).SetInt64(1)
+	b := new(big.Float).SetPrec(prec).SetMode(mode).Set(base)
+	for e > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if e&1 == 1 {
+			result.Mul(result, b)
+		}
+		e >>= 1
+		if e > 0 {
+			b.Mul(b, b)
+		}
+	}
+	return result, nil
+}
+
+// commonPrefix returns the longest common prefix of a and b.
+func commonPrefix(a, b string) string {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return a[:i]
+}
@@ -0,0 +1,71 @@
+package fibonacci
+
+import (
+	"context"
+	"testing"
+)
+
+func TestLeadingDigits(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		n uint64
+		k int
+	}{
+		{0, 5}, {1, 1}, {10, 1}, {10, 5}, {100, 10}, {1000, 30}, {2000, 50}, {12345, 40}, {100000, 100},
+	} {
+		want := calculateReference(tc.n).String()
+		got, total, err := LeadingDigits(context.Background(), tc.n, tc.k)
+		if err != nil {
+			t.Fatalf("LeadingDigits(%d, %d) error: %v", tc.n, tc.k, err)
+		}
+		if total != uint64(len(want)) {
+			t.Errorf("LeadingDigits(%d, %d) total = %d, want %d", tc.n, tc.k, total, len(want))
+		}
+		if got != want[:min(tc.k, len(want))] {
+			t.Errorf("LeadingDigits(%d, %d) = %q, want %q", tc.n, tc.k, got, want[:min(tc.k, len(want))])
+		}
+	}
+}
+
+func TestLeadingDigitsLargeN(t *testing.T) {
+	t.Parallel()
+
+	// F(10^9) begins with 79523178745.
+	got, total, err := LeadingDigits(context.Background(), 1_000_000_000, 11)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "79523178745" || total != 208987640 {
+		t.Errorf("LeadingDigits(1e9, 11) = %q with %d digits, want \"79523178745\" with 208987640", got, total)
+	}
+}
+
+func TestDecimalDigits(t *testing.T) {
+	t.Parallel()
+
+	for _, n := range []uint64{0, 1, 2, 6, 7, 93, 1000, 12345, 100000} {
+		if got, want := DecimalDigits(n), uint64(len(calculateReference(n).String())); got != want {
+			t.Errorf("DecimalDigits(%d) = %d, want %d", n, got, want)
+		}
+	}
+	if got := DecimalDigits(1_000_000_000); got != 208987640 {
+		t.Errorf("DecimalDigits(1e9) = %d, want 208987640", got)
+	}
+}
+
+func TestLeadingDigitsErrors(t *testing.T) {
+	t.Parallel()
+
+	if _, _, err := LeadingDigits(context.Background(), 10, 0); err == nil {
+		t.Error("LeadingDigits with k = 0 should fail")
+	}
+	if _, _, err := LeadingDigits(context.Background(), MaxLeadingDigitsN+1, 5); err == nil {
+		t.Error("LeadingDigits beyond MaxLeadingDigitsN should fail")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := LeadingDigits(ctx, 1_000_000, 5); err == nil {
+		t.Error("LeadingDigits with a canceled context should fail")
+	}
+}
@@ -0,0 +1,314 @@
+// This file provides the "lowmem" calculator: fast doubling with operands
+// kept in memory-mapped temporary files, for values that do not fit in RAM
+// alongside the temporaries of the other algorithms.
+
+package fibonacci
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"slices"
+
+	"github.com/agbru/fibcalc/internal/fibonacci/memory"
+	"github.com/agbru/fibcalc/internal/parallel"
+)
+
+const (
+	// lowMemBlocks is the number of blocks an operand is split into for
+	// multiplication. More blocks mean smaller heap temporaries and more
+	// multiplications.
+	lowMemBlocks = 8
+	// lowMemMinBlockWords is the minimum size of a multiplication block,
+	// below which the per-block overhead dominates.
+	lowMemMinBlockWords = 4096
+	// lowMemBuffers is the number of mapped operands: F(k), F(k+1), the
+	// 2F(k+1) - F(k) factor and the two results of a doubling step.
+	lowMemBuffers = 5
+)
+
+// LowMemoryFastDoubling computes F(n) with the fast doubling identities
+// while keeping F(k), F(k+1) and the step results in memory-mapped
+// temporary files (in os.TempDir()) instead of the Go heap.
+//
+// Multiplications stream one operand through the product in blocks: each
+// block is multiplied by the other operand on the heap and the partial
+// product is added into the mapped result at its offset. Only one partial
+// product and the multiplication's own temporaries live on the heap, so the
+// peak heap stays close to a single full-size operand, against about fifteen
+// for "fast". The operating system pages the mapped operands in and out as
+// needed, which makes F(1e9) possible on a 16 GB machine.
+//
+// The price is speed: each multiplication is split into lowMemBlocks block
+// products, steps are not parallelized, and paging adds disk I/O. Use it
+// only when the other calculators run out of memory.
+type LowMemoryFastDoubling struct {
+	// blockWords overrides the multiplication block size (0 means automatic).
+	blockWords int
+}
+
+// Name returns the descriptive name of the algorithm.
+//
+// Returns:
+//   - string: The name of the algorithm.
+func (c *LowMemoryFastDoubling) Name() string {
+	return "Low-Memory Fast Doubling (mmap, Streamed Blocks)"
+}
+
+// Info describes the algorithm for listings and automatic selection. Below
+// MinN, every other calculator fits in the memory of a common machine.
+//
+// Returns:
+//   - AlgorithmInfo: The algorithm's metadata.
+func (c *LowMemoryFastDoubling) Info() AlgorithmInfo {
+	return AlgorithmInfo{
+		Complexity: "O(M(n) log n), each multiplication split into blocks",
+		Memory:     "memory-mapped files, about one operand of F(n) on the heap",
+		MinN:       1_000_000_000,
+		Notes:      "Only when the other calculators run out of memory; paging makes it slow.",
+	}
+}
+
+// CalculateCore computes F(n) with mapped operands.
+//
+// Parameters:
+//   - ctx: The context for managing cancellation and deadlines.
+//   - reporter: The function used for reporting progress.
+//   - n: The index of the Fibonacci number to calculate.
+//   - opts: Configuration options for the calculation.
+//
+// Returns:
+//   - *big.Int: The calculated Fibonacci number, copied to the heap.
+//   - error: An error if the temporary files could not be created or the
+//     context was canceled.
+func (c *LowMemoryFastDoubling) CalculateCore(ctx context.Context, reporter ProgressCallback, n uint64, opts Options) (*big.Int, error) {
+	opts = normalizeOptions(opts)
+
+	// F(n+1) is the largest value computed; the margin covers the
+	// rounding of FibonacciGrowthFactor and the carry word of products.
+	words := int(float64(n+1)*FibonacciGrowthFactor)/bits.UintSize + 4
+	var bufs [lowMemBuffers]*mappedInt
+	for i := range bufs {
+		mw, err := memory.NewMappedWords("", words)
+		if err != nil {
+			return nil, err
+		}
+		bufs[i] = &mappedInt{buf: mw}
+		defer mw.Close()
+	}
+
+	s := &lowMemStep{ctx: ctx, fftThreshold: opts.FFTThreshold, blockWords: c.blockWords}
+	fk, fk1, t, a, b := bufs[0], bufs[1], bufs[2], bufs[3], bufs[4]
+	fk1.setOne()
+
+	numBits := bits.Len64(n)
+	var lastReported float64
+	totalWork := CalcTotalWork(numBits)
+	currentWork := 0.0
+	powers := PrecomputePowers4(numBits)
+
+	for i := numBits - 1; i > 0; i-- {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		// (F(k), F(k+1)) -> (F(2k), F(2k+1))
+		if err := s.double2k(a, fk, fk1, t); err != nil {
+			return nil, err
+		}
+		if err := s.double2k1(b, fk, fk1); err != nil {
+			return nil, err
+		}
+		fk, fk1, a, b = a, b, fk, fk1
+
+		// (F(k), F(k+1)) -> (F(k+1), F(k+2)) when the bit is set
+		if (n>>i)&1 == 1 {
+			addMapped(t, fk, fk1)
+			fk, fk1, t = fk1, t, fk
+		}
+		currentWork = ReportStepProgress(reporter, &lastReported, totalWork, currentWork, i, numBits, powers)
+	}
+
+	// The last step only needs F(n): F(2k) or F(2k+1) depending on the
+	// parity of n.
+	result := a
+	var err error
+	if n&1 == 0 {
+		err = s.double2k(a, fk, fk1, t)
+	} else {
+		result = b
+		err = s.double2k1(b, fk, fk1)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if numBits > 0 {
+		ReportStepProgress(reporter, &lastReported, totalWork, currentWork, 0, numBits, powers)
+	}
+	return new(big.Int).SetBits(slices.Clone(result.words())), nil
+}
+
+// mappedInt is a non-negative integer stored in a mapped buffer. The words
+// past the significant ones are always zero, so results can be accumulated
+// in place.
+type mappedInt struct {
+	buf *memory.MappedWords
+	n   int // number of significant words
+}
+
+// words returns the significant words, least significant first.
+func (m *mappedInt) words() []big.Word {
+	return m.buf.Words[:m.n:m.n]
+}
+
+// view returns a big.Int that shares the buffer. It must only be read.
+func (m *mappedInt) view() *big.Int {
+	return new(big.Int).SetBits(m.words())
+}
+
+// reset sets m to zero.
+func (m *mappedInt) reset() {
+	clear(m.buf.Words[:m.n])
+	m.n = 0
+}
+
+// setOne sets m to one.
+func (m *mappedInt) setOne() {
+	m.reset()
+	m.buf.Words[0] = 1
+	m.n = 1
+}
+
+// setLen sets the number of words that may be significant, capped at the
+// buffer size, and drops the leading zero words.
+func (m *mappedInt) setLen(n int) {
+	m.n = min(n, len(m.buf.Words))
+	for m.n > 0 && m.buf.Words[m.n-1] == 0 {
+		m.n--
+	}
+}
+
+// lowMemStep holds the settings shared by the multiplications of a
+// calculation, and the heap buffer reused for their partial products.
+type lowMemStep struct {
+	ctx          context.Context
+	fftThreshold int
+	blockWords   int
+	partial      *big.Int
+}
+
+// double2k computes F(2k) = F(k) * (2F(k+1) - F(k)) into dst, using t for
+// the second factor.
+func (s *lowMemStep) double2k(dst, fk, fk1, t *mappedInt) error {
+	doubleSubMapped(t, fk1, fk)
+	dst.reset()
+	return s.mulAdd(dst, fk, t)
+}
+
+// double2k1 computes F(2k+1) = F(k+1)² + F(k)² into dst.
+func (s *lowMemStep) double2k1(dst, fk, fk1 *mappedInt) error {
+	dst.reset()
+	if err := s.mulAdd(dst, fk1, fk1); err != nil {
+		return err
+	}
+	return s.mulAdd(dst, fk, fk)
+}
+
+// mulAdd adds x * y to dst. x is multiplied by y one block of words at a
+// time, and each partial product is added into dst at the block's offset.
+// The context is checked between blocks.
+func (s *lowMemStep) mulAdd(dst, x, y *mappedInt) error {
+	if x.n == 0 || y.n == 0 {
+		return nil
+	}
+	if x.n+y.n > len(dst.buf.Words) {
+		return fmt.Errorf("lowmem: product of %d and %d words exceeds the %d-word buffer",
+			x.n, y.n, len(dst.buf.Words))
+	}
+	block := s.blockWords
+	if block <= 0 {
+		block = max(lowMemMinBlockWords, (x.n+lowMemBlocks-1)/lowMemBlocks)
+	}
+
+	yv := y.view()
+	xw := x.words()
+	for off := 0; off < len(xw); off += block {
+		if err := s.ctx.Err(); err != nil {
+			return err
+		}
+		parallel.MaybeYield()
+		end := min(off+block, len(xw))
+		xb := new(big.Int).SetBits(xw[off:end:end])
+		if xb.Sign() == 0 {
+			continue
+		}
+		p, err := smartMultiply(s.partial, xb, yv, s.fftThreshold)
+		if err != nil {
+			return err
+		}
+		s.partial = p
+		addWordsAt(dst.buf.Words, off, p.Bits())
+	}
+	dst.setLen(max(dst.n, x.n+y.n+1))
+	return nil
+}
+
+// addWordsAt adds p to z, shifted left by off words, propagating the carry.
+// z must be large enough to hold the sum.
+func addWordsAt(z []big.Word, off int, p []big.Word) {
+	z = z[off:]
+	var carry uint
+	for i, w := range p {
+		var sum uint
+		sum, carry = bits.Add(uint(z[i]), uint(w), carry)
+		z[i] = big.Word(sum)
+	}
+	for i := len(p); carry != 0; i++ {
+		var sum uint
+		sum, carry = bits.Add(uint(z[i]), 0, carry)
+		z[i] = big.Word(sum)
+	}
+}
+
+// doubleSubMapped sets dst = 2*x - y, which is non-negative for
+// x = F(k+1) and y = F(k).
+func doubleSubMapped(dst, x, y *mappedInt) {
+	dst.reset()
+	xw, yw := x.words(), y.words()
+	z := dst.buf.Words
+	var shiftCarry, borrow uint
+	for i, w := range xw {
+		d := uint(w)<<1 | shiftCarry
+		shiftCarry = uint(w) >> (bits.UintSize - 1)
+		var sub uint
+		if i < len(yw) {
+			sub = uint(yw[i])
+		}
+		d, borrow = bits.Sub(d, sub, borrow)
+		z[i] = big.Word(d)
+	}
+	z[len(xw)] = big.Word(shiftCarry - borrow)
+	dst.setLen(len(xw) + 1)
+}
+
+// addMapped sets dst = x + y.
+func addMapped(dst, x, y *mappedInt) {
+	dst.reset()
+	if x.n < y.n {
+		x, y = y, x
+	}
+	xw, yw := x.words(), y.words()
+	z := dst.buf.Words
+	var carry uint
+	for i, w := range xw {
+		var v uint
+		if i < len(yw) {
+			v = uint(yw[i])
+		}
+		var sum uint
+		sum, carry = bits.Add(uint(w), v, carry)
+		z[i] = big.Word(sum)
+	}
+	z[len(xw)] = big.Word(carry)
+	dst.setLen(len(xw) + 1)
+}
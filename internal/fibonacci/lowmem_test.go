@@ -0,0 +1,73 @@
+package fibonacci
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"os"
+	"testing"
+)
+
+func TestLowMemoryFastDoubling_MatchesFastDoubling(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ctx := context.Background()
+	// Small blocks force several block products per multiplication, and a
+	// low FFT threshold exercises the FFT path on the block products.
+	opts := Options{FFTThreshold: 2048}
+	for _, n := range []uint64{0, 1, 2, 3, 94, 1000, 4095, 4096, 100_001} {
+		want, err := (&OptimizedFastDoubling{}).CalculateCore(ctx, func(float64) {}, n, Options{})
+		if err != nil {
+			t.Fatalf("fast F(%d): %v", n, err)
+		}
+		for _, block := range []int{0, 1, 7} {
+			got, err := (&LowMemoryFastDoubling{blockWords: block}).CalculateCore(ctx, func(float64) {}, n, opts)
+			if err != nil {
+				t.Fatalf("lowmem F(%d), block %d: %v", n, block, err)
+			}
+			if got.Cmp(want) != 0 {
+				t.Errorf("lowmem F(%d), block %d = %v, want %v", n, block, got, want)
+			}
+		}
+	}
+}
+
+func TestLowMemoryFastDoubling_RemovesTempFiles(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("TMPDIR", dir)
+
+	var last float64
+	_, err := (&LowMemoryFastDoubling{}).CalculateCore(context.Background(), func(p float64) { last = p }, 10_000, Options{})
+	if err != nil {
+		t.Fatalf("CalculateCore: %v", err)
+	}
+	if last != 1 {
+		t.Errorf("last progress = %v, want 1", last)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("%d temporary files left in %s", len(entries), dir)
+	}
+}
+
+func TestLowMemoryFastDoubling_Canceled(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := (&LowMemoryFastDoubling{}).CalculateCore(ctx, func(float64) {}, 1_000_000, Options{})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestAddWordsAt(t *testing.T) {
+	t.Parallel()
+	max := ^big.Word(0)
+	z := []big.Word{1, max, max, 0}
+	addWordsAt(z, 1, []big.Word{1})
+	want := []big.Word{1, 0, 0, 1}
+	for i := range want {
+		if z[i] != want[i] {
+			t.Fatalf("z = %v, want %v", z, want)
+		}
+	}
+}
@@ -53,6 +53,19 @@ func (c *MatrixExponentiation) Name() string {
 	return "Matrix Exponentiation (O(log n), Parallel, Zero-Alloc)"
 }
 
+// Info describes the algorithm for listings and automatic selection.
+//
+// Returns:
+//   - AlgorithmInfo: The algorithm's metadata.
+func (c *MatrixExponentiation) Info() AlgorithmInfo {
+	return AlgorithmInfo{
+		Complexity: "O(M(n) log n), 4 multiplications per bit (7 with Strassen)",
+		Memory:     "heap, pooled 2x2 matrix states, more temporaries than fast doubling",
+		Parallel:   true,
+		Notes:      "Slower than fast doubling; its independent arithmetic cross-checks it.",
+	}
+}
+
 // CalculateCore computes F(n) using the matrix exponentiation method.
 //
 // This function implements the binary exponentiation algorithm to efficiently
@@ -71,6 +84,7 @@ func (c *MatrixExponentiation) Name() string {
 func (c *MatrixExponentiation) CalculateCore(ctx context.Context, reporter ProgressCallback, n uint64, opts Options) (*big.Int, error) {
 	state := acquireMatrixState()
 	defer releaseMatrixState(state)
+	state.logger = calcLogger(ctx, opts, &taskLogger)
 
 	// Use framework for the matrix exponentiation loop
 	framework := NewMatrixFramework()
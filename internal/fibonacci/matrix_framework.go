@@ -9,6 +9,8 @@ import (
 	"math/big"
 	"math/bits"
 	"runtime"
+
+	"github.com/agbru/fibcalc/internal/parallel"
 )
 
 // MatrixFramework encapsulates the common Matrix Exponentiation algorithm logic.
@@ -53,27 +55,39 @@ func (f *MatrixFramework) ExecuteMatrixLoop(ctx context.Context, reporter Progre
 	powers := PrecomputePowers4(numBits)
 	workDone := 0.0
 	lastReportedProgress := -1.0
+	// The exponents of the result and of the power of Q, only tracked for
+	// the step hook
+	resExp, pExp := uint64(0), uint64(1)
 
 	for i := 0; i < numBits; i++ {
 		if err := ctx.Err(); err != nil {
 			return nil, fmt.Errorf("matrix exponentiation calculation canceled at bit %d/%d: %w", i, numBits-1, err)
 		}
+		parallel.MaybeYield()
 
 		if (exponent>>uint(i))&1 == 1 {
 			// Decide on parallelism based on the max size of the operands involved
 			inParallel := useParallel && maxBitLenMatrix(state.p) > normalizedOpts.ParallelThreshold
-			if err := multiplyMatrices(state.tempMatrix, state.res, state.p, state, inParallel, normalizedOpts.FFTThreshold, normalizedOpts.StrassenThreshold); err != nil {
+			if err := multiplyMatrices(state.tempMatrix, state.res, state.p, state, inParallel, normalizedOpts.multiplier(), normalizedOpts.StrassenThreshold); err != nil {
 				return nil, fmt.Errorf("matrix multiplication failed at bit %d/%d: %w", i, numBits-1, err)
 			}
 			state.res, state.tempMatrix = state.tempMatrix, state.res
+			if opts.OnStep != nil {
+				resExp += pExp
+				opts.OnStep(StepEvent{Kind: StepMatrixMultiply, Bit: i, Bits: numBits, K: resExp, Values: state.res.entries()})
+			}
 		}
 
 		if i < numBits-1 {
 			inParallel := useParallel && maxBitLenMatrix(state.p) > normalizedOpts.ParallelThreshold
-			if err := squareSymmetricMatrixFunc(state.tempMatrix, state.p, state, inParallel, normalizedOpts.FFTThreshold); err != nil {
+			if err := squareSymmetricMatrixFunc(state.tempMatrix, state.p, state, inParallel, normalizedOpts.multiplier()); err != nil {
 				return nil, fmt.Errorf("matrix squaring failed at bit %d/%d: %w", i, numBits-1, err)
 			}
 			state.p, state.tempMatrix = state.tempMatrix, state.p
+			if opts.OnStep != nil {
+				pExp *= 2
+				opts.OnStep(StepEvent{Kind: StepMatrixSquare, Bit: i, Bits: numBits, K: pExp, Values: state.p.entries()})
+			}
 		}
 
 		// Harmonized reporting via common utility function
@@ -2,6 +2,8 @@ package fibonacci
 
 import (
 	"sync/atomic"
+
+	"github.com/agbru/fibcalc/internal/fibonacci/mul"
 )
 
 // defaultStrassenThresholdBits controls the switch to Strassen's algorithm.
@@ -42,20 +44,20 @@ func GetDefaultStrassenThreshold() int {
 //   - m2: The second matrix operand.
 //   - state: The matrix state providing temporary storage.
 //   - inParallel: Whether to execute the operation in parallel.
-//   - fftThreshold: The threshold for using FFT-based multiplication.
+//   - backend: The multiplication backend.
 //   - strassenThreshold: The bit size threshold to switch to Strassen's algorithm.
 //
 // Returns:
 //   - error: An error if the calculation failed.
-func multiplyMatrices(dest, m1, m2 *matrix, state *matrixState, inParallel bool, fftThreshold int, strassenThreshold int) error {
+func multiplyMatrices(dest, m1, m2 *matrix, state *matrixState, inParallel bool, backend mul.Multiplier, strassenThreshold int) error {
 	strassenThresholdBits := strassenThreshold
 	if strassenThresholdBits == 0 {
 		strassenThresholdBits = GetDefaultStrassenThreshold()
 	}
 	if maxBitLenTwoMatrices(m1, m2) <= strassenThresholdBits {
-		return multiplyMatrix2x2(dest, m1, m2, state, inParallel, fftThreshold)
+		return multiplyMatrix2x2(dest, m1, m2, state, inParallel, backend)
 	}
-	return multiplyMatrixStrassen(dest, m1, m2, state, inParallel, fftThreshold)
+	return multiplyMatrixStrassen(dest, m1, m2, state, inParallel, backend)
 }
 
 // multiplyMatrixStrassen implements the Strassen-Winograd algorithm for 2x2 matrices.
@@ -68,11 +70,11 @@ func multiplyMatrices(dest, m1, m2 *matrix, state *matrixState, inParallel bool,
 //   - m2: The second matrix operand.
 //   - state: The matrix state providing temporary storage.
 //   - inParallel: Whether to execute the operation in parallel.
-//   - fftThreshold: The threshold for using FFT-based multiplication.
+//   - backend: The multiplication backend.
 //
 // Returns:
 //   - error: An error if the calculation failed.
-func multiplyMatrixStrassen(dest, m1, m2 *matrix, state *matrixState, inParallel bool, fftThreshold int) error {
+func multiplyMatrixStrassen(dest, m1, m2 *matrix, state *matrixState, inParallel bool, backend mul.Multiplier) error {
 	// Winograd's variant uses 7 multiplications and 15 additions/subtractions.
 	//
 	// Pre-computations (8 additions/subtractions) are handled by computeStrassenIntermediates.
@@ -90,15 +92,15 @@ func multiplyMatrixStrassen(dest, m1, m2 *matrix, state *matrixState, inParallel
 
 	// 2. Execute the 7 multiplications using the generic task executor
 	tasks := []multiplicationTask{
-		{&p1, s2, s6, fftThreshold},
-		{&p2, m1.a, m2.a, fftThreshold},
-		{&p3, m1.b, m2.c, fftThreshold},
-		{&p4, s3, s7, fftThreshold},
-		{&p5, s1, s5, fftThreshold},
-		{&p6, s4, m2.d, fftThreshold},
-		{&p7, m1.d, s8, fftThreshold},
+		{&p1, s2, s6, backend},
+		{&p2, m1.a, m2.a, backend},
+		{&p3, m1.b, m2.c, backend},
+		{&p4, s3, s7, backend},
+		{&p5, s1, s5, backend},
+		{&p6, s4, m2.d, backend},
+		{&p7, m1.d, s8, backend},
 	}
-	if err := executeTasks[multiplicationTask, *multiplicationTask](tasks, inParallel); err != nil {
+	if err := executeTasks[multiplicationTask, *multiplicationTask](state.logger, tasks, inParallel); err != nil {
 		return err
 	}
 
@@ -177,29 +179,29 @@ func assembleStrassenResult(dest *matrix, state *matrixState) {
 //   - mat: The symmetric matrix to square.
 //   - state: The matrix state providing temporary storage.
 //   - inParallel: Whether to execute the operation in parallel.
-//   - fftThreshold: The threshold for using FFT-based multiplication.
+//   - backend: The multiplication backend.
 //
 // Returns:
 //   - error: An error if the calculation failed.
-func squareSymmetricMatrix(dest, mat *matrix, state *matrixState, inParallel bool, fftThreshold int) error {
+func squareSymmetricMatrix(dest, mat *matrix, state *matrixState, inParallel bool, backend mul.Multiplier) error {
 	a2, b2, d2 := state.t1, state.t2, state.t3
 	bAd, ad := state.t4, state.t5
 	ad.Add(mat.a, mat.d)
 
 	// Execute the 3 squaring operations using optimized squaring
 	sqrTasks := []squaringTask{
-		{&a2, mat.a, fftThreshold},
-		{&b2, mat.b, fftThreshold},
-		{&d2, mat.d, fftThreshold},
+		{&a2, mat.a, backend},
+		{&b2, mat.b, backend},
+		{&d2, mat.d, backend},
 	}
 
 	// Execute the 1 general multiplication (b * (a+d))
 	mulTasks := []multiplicationTask{
-		{&bAd, mat.b, ad, fftThreshold},
+		{&bAd, mat.b, ad, backend},
 	}
 
 	// Use unified execution function for both parallel and sequential cases
-	if err := executeMixedTasks(sqrTasks, mulTasks, inParallel); err != nil {
+	if err := executeMixedTasks(state.logger, sqrTasks, mulTasks, inParallel); err != nil {
 		return err
 	}
 
@@ -219,11 +221,11 @@ func squareSymmetricMatrix(dest, mat *matrix, state *matrixState, inParallel boo
 //   - m2: The second matrix operand.
 //   - state: The matrix state providing temporary storage.
 //   - inParallel: Whether to execute the operation in parallel.
-//   - fftThreshold: The threshold for using FFT-based multiplication.
+//   - backend: The multiplication backend.
 //
 // Returns:
 //   - error: An error if the calculation failed.
-func multiplyMatrix2x2(dest, m1, m2 *matrix, state *matrixState, inParallel bool, fftThreshold int) error {
+func multiplyMatrix2x2(dest, m1, m2 *matrix, state *matrixState, inParallel bool, backend mul.Multiplier) error {
 	// m1 = [[a,b],[c,d]], m2 = [[e,f],[g,h]]
 	// Uses buffers from the state to avoid allocations
 	// a = a*e + b*g
@@ -239,16 +241,16 @@ func multiplyMatrix2x2(dest, m1, m2 *matrix, state *matrixState, inParallel bool
 
 	// Execute the 8 multiplications using the generic task executor
 	tasks := []multiplicationTask{
-		{&ae, m1.a, m2.a, fftThreshold},
-		{&bg, m1.b, m2.c, fftThreshold},
-		{&af, m1.a, m2.b, fftThreshold},
-		{&bh, m1.b, m2.d, fftThreshold},
-		{&ce, m1.c, m2.a, fftThreshold},
-		{&dg, m1.d, m2.c, fftThreshold},
-		{&cf, m1.c, m2.b, fftThreshold},
-		{&dh, m1.d, m2.d, fftThreshold},
+		{&ae, m1.a, m2.a, backend},
+		{&bg, m1.b, m2.c, backend},
+		{&af, m1.a, m2.b, backend},
+		{&bh, m1.b, m2.d, backend},
+		{&ce, m1.c, m2.a, backend},
+		{&dg, m1.d, m2.c, backend},
+		{&cf, m1.c, m2.b, backend},
+		{&dh, m1.d, m2.d, backend},
 	}
-	if err := executeTasks[multiplicationTask, *multiplicationTask](tasks, inParallel); err != nil {
+	if err := executeTasks[multiplicationTask, *multiplicationTask](state.logger, tasks, inParallel); err != nil {
 		return err
 	}
 
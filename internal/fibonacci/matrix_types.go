@@ -3,6 +3,8 @@ package fibonacci
 import (
 	"math/big"
 	"sync"
+
+	"github.com/rs/zerolog"
 )
 
 // matrix represents a 2x2 matrix of *big.Int values.
@@ -42,6 +44,11 @@ func (m *matrix) Set(other *matrix) {
 	m.d.Set(other.d)
 }
 
+// entries returns the entries a, b, c, d of the matrix, for a StepEvent.
+func (m *matrix) entries() []*big.Int {
+	return []*big.Int{m.a, m.b, m.c, m.d}
+}
+
 // SetIdentity configures the matrix as an identity matrix.
 // The identity matrix is the multiplicative identity for matrix multiplication,
 // and is defined as:
@@ -81,6 +88,9 @@ type matrixState struct {
 	s1, s2, s3, s4, s5, s6, s7, s8 *big.Int
 	// General purpose temporaries for symmetric squaring
 	t1, t2, t3, t4, t5 *big.Int
+	// logger receives the task distribution of the calculation; nil means
+	// the task logger.
+	logger *zerolog.Logger
 }
 
 // Reset resets the state for a new use.
@@ -88,6 +98,7 @@ type matrixState struct {
 func (s *matrixState) Reset() {
 	s.res.SetIdentity()
 	s.p.SetBaseQ()
+	s.logger = nil
 }
 
 // matrixStatePool is a `sync.Pool` for `matrixState` objects. Object pools are a
@@ -4,6 +4,7 @@ import (
 	"math"
 	"runtime"
 	"runtime/debug"
+	"sync"
 
 	"github.com/rs/zerolog"
 )
@@ -15,11 +16,42 @@ const (
 	GCModeAuto       GCMode = "auto"
 	GCModeAggressive GCMode = "aggressive"
 	GCModeDisabled   GCMode = "disabled"
+	// GCModeRealtime keeps the GC running for soft real-time mode: short,
+	// regular pauses instead of no pause until a long one (see Begin).
+	GCModeRealtime GCMode = "realtime"
 )
 
 // GCAutoThreshold is the minimum N for auto GC control to activate.
 const GCAutoThreshold uint64 = 1_000_000
 
+// RealtimeGCPercent is the GOGC of GCModeRealtime. Above the default 100,
+// it halves the number of collections and leaves the pacer a longer runway,
+// so that mark assists, which stall whichever goroutine allocates, the
+// display's included, stay rare.
+const RealtimeGCPercent = 200
+
+// safetyLimit tracks the soft memory limit the controllers set while the GC
+// is disabled. Calculators of --algo all run concurrently, so the first
+// Begin records the user's limit (GOMEMLIMIT or debug.SetMemoryLimit) and
+// the last End forgets it.
+var safetyLimit struct {
+	mu     sync.Mutex
+	owners int
+	user   int64
+}
+
+// userMemoryLimit returns the soft memory limit of the user: the limit in
+// effect, or the one it replaced while a controller sets the safety limit.
+func userMemoryLimit() int64 {
+	safetyLimit.mu.Lock()
+	defer safetyLimit.mu.Unlock()
+	if safetyLimit.owners > 0 {
+		return safetyLimit.user
+	}
+	// A negative input reads the limit without changing it
+	return debug.SetMemoryLimit(-1)
+}
+
 // GCController manages Go's garbage collector during intensive calculations.
 // It disables GC during computation and restores it afterward, reducing
 // pause times and memory overhead for large calculations.
@@ -27,6 +59,7 @@ type GCController struct {
 	mode              GCMode
 	originalGCPercent int
 	active            bool
+	ownsLimit         bool
 	logger            zerolog.Logger
 	startStats        runtime.MemStats
 	endStats          runtime.MemStats
@@ -38,13 +71,16 @@ type GCStats struct {
 	TotalAlloc   uint64
 	NumGC        uint32
 	PauseTotalNs uint64
+	// MaxPauseNs is the longest stop-the-world pause of a cycle, among the
+	// last 256 cycles (the runtime keeps no older pauses).
+	MaxPauseNs uint64
 }
 
 // NewGCController creates a GC controller for the given mode and N.
 func NewGCController(mode string, n uint64) *GCController {
 	gc := &GCController{mode: GCMode(mode), logger: zerolog.Nop()}
 	switch gc.mode {
-	case GCModeAggressive:
+	case GCModeAggressive, GCModeRealtime:
 		gc.active = true
 	case GCModeAuto:
 		gc.active = n >= GCAutoThreshold
@@ -59,18 +95,33 @@ func (gc *GCController) SetLogger(l zerolog.Logger) {
 	gc.logger = l
 }
 
-// Begin disables GC if the controller is active.
+// Begin disables GC if the controller is active. In GCModeRealtime it
+// only sets RealtimeGCPercent instead: a disabled GC collects back to back
+// with heavy mark assists once the heap reaches the safety limit, and End
+// then forces a full collection, both long stalls for a live display.
 func (gc *GCController) Begin() {
 	if !gc.active {
 		return
 	}
 	runtime.ReadMemStats(&gc.startStats)
+	if gc.mode == GCModeRealtime {
+		gc.originalGCPercent = debug.SetGCPercent(RealtimeGCPercent)
+		gc.logger.Debug().Int("gc_percent", RealtimeGCPercent).Msg("gc paced for soft real-time")
+		return
+	}
 	gc.originalGCPercent = debug.SetGCPercent(-1)
 	// Set soft memory limit as OOM safety net.
 	if gc.startStats.Sys > 0 {
 		limit := int64(float64(gc.startStats.Sys) * 3)
 		if limit > 0 {
+			safetyLimit.mu.Lock()
+			if safetyLimit.owners == 0 {
+				safetyLimit.user = debug.SetMemoryLimit(-1)
+			}
+			safetyLimit.owners++
 			debug.SetMemoryLimit(limit)
+			safetyLimit.mu.Unlock()
+			gc.ownsLimit = true
 		}
 	}
 	gc.logger.Debug().
@@ -86,7 +137,20 @@ func (gc *GCController) End() {
 	}
 	runtime.ReadMemStats(&gc.endStats)
 	debug.SetGCPercent(gc.originalGCPercent)
+	if gc.mode == GCModeRealtime {
+		gc.logger.Debug().
+			Uint32("gc_cycles", gc.endStats.NumGC-gc.startStats.NumGC).
+			Uint64("max_pause_ns", gc.Stats().MaxPauseNs).
+			Msg("gc pacing restored")
+		return
+	}
 	debug.SetMemoryLimit(math.MaxInt64)
+	if gc.ownsLimit {
+		safetyLimit.mu.Lock()
+		safetyLimit.owners--
+		safetyLimit.mu.Unlock()
+		gc.ownsLimit = false
+	}
 	runtime.GC()
 	gc.logger.Debug().
 		Str("mode", string(gc.mode)).
@@ -98,10 +162,40 @@ func (gc *GCController) End() {
 
 // Stats returns GC statistics delta between Begin and End.
 func (gc *GCController) Stats() GCStats {
-	return GCStats{
-		HeapAlloc:    gc.endStats.HeapAlloc,
-		TotalAlloc:   gc.endStats.TotalAlloc - gc.startStats.TotalAlloc,
-		NumGC:        gc.endStats.NumGC - gc.startStats.NumGC,
-		PauseTotalNs: gc.endStats.PauseTotalNs - gc.startStats.PauseTotalNs,
+	return gcDelta(&gc.startStats, &gc.endStats)
+}
+
+// MeasureGC starts measuring the garbage collections, for callers that
+// report them without controlling the GC.
+//
+// Returns:
+//   - func() GCStats: Returns the statistics of the collections since
+//     MeasureGC was called.
+func MeasureGC() func() GCStats {
+	var start runtime.MemStats
+	runtime.ReadMemStats(&start)
+	return func() GCStats {
+		var end runtime.MemStats
+		runtime.ReadMemStats(&end)
+		return gcDelta(&start, &end)
+	}
+}
+
+// gcDelta returns the statistics of the collections between two readings.
+func gcDelta(start, end *runtime.MemStats) GCStats {
+	stats := GCStats{
+		HeapAlloc:    end.HeapAlloc,
+		TotalAlloc:   end.TotalAlloc - start.TotalAlloc,
+		NumGC:        end.NumGC - start.NumGC,
+		PauseTotalNs: end.PauseTotalNs - start.PauseTotalNs,
+	}
+	// PauseNs[i%256] holds the pause of cycle i+1
+	first := start.NumGC
+	if end.NumGC-first > uint32(len(end.PauseNs)) {
+		first = end.NumGC - uint32(len(end.PauseNs))
+	}
+	for i := first; i < end.NumGC; i++ {
+		stats.MaxPauseNs = max(stats.MaxPauseNs, end.PauseNs[i%uint32(len(end.PauseNs))])
 	}
+	return stats
 }
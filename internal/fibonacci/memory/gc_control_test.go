@@ -1,6 +1,8 @@
 package memory
 
 import (
+	"runtime"
+	"runtime/debug"
 	"testing"
 )
 
@@ -74,3 +76,38 @@ func TestGCController_Stats_AfterBeginEnd(t *testing.T) {
 	// (we can't assert exact values due to runtime variability)
 	_ = stats
 }
+
+// TestGCController_Realtime changes the global GC percent, so it does not
+// run in parallel.
+func TestGCController_Realtime(t *testing.T) {
+	gc := NewGCController("realtime", 100)
+	if !gc.active {
+		t.Fatal("GC controller should be active in realtime mode regardless of N")
+	}
+	original := debug.SetGCPercent(100)
+	defer debug.SetGCPercent(original)
+
+	gc.Begin()
+	if got := debug.SetGCPercent(RealtimeGCPercent); got != RealtimeGCPercent {
+		t.Errorf("GC percent during a realtime calculation = %d, want %d", got, RealtimeGCPercent)
+	}
+	runtime.GC()
+	gc.End()
+	if got := debug.SetGCPercent(100); got != 100 {
+		t.Errorf("GC percent after End = %d, want the original 100", got)
+	}
+
+	stats := gc.Stats()
+	if stats.NumGC == 0 || stats.MaxPauseNs == 0 || stats.MaxPauseNs > stats.PauseTotalNs {
+		t.Errorf("Stats() = %+v, want the collection and its pause", stats)
+	}
+}
+
+func TestMeasureGC(t *testing.T) {
+	t.Parallel()
+	stop := MeasureGC()
+	runtime.GC()
+	if stats := stop(); stats.NumGC == 0 || stats.MaxPauseNs > stats.PauseTotalNs {
+		t.Errorf("MeasureGC() = %+v, want at least the forced collection", stats)
+	}
+}
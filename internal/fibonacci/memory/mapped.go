@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"os"
+	"unsafe"
+
+	"github.com/agbru/fibcalc/internal/fsguard"
+)
+
+// wordBytes is the size of a big.Word in bytes.
+const wordBytes = bits.UintSize / 8
+
+// MappedWords is a big.Word buffer backed by a memory-mapped temporary file
+// instead of the Go heap. The operating system pages it in and out as it is
+// accessed, so large operands can exceed the available RAM at the cost of
+// disk I/O, and they are not scanned by the garbage collector.
+//
+// The buffer is zeroed on creation. Close must be called to release the
+// mapping and delete the file.
+type MappedWords struct {
+	// Words is the mapped buffer. It must not be used after Close.
+	Words []big.Word
+
+	file *os.File
+	data []byte
+}
+
+// MappingSupported reports whether MappedWords buffers are memory-mapped
+// on this platform; elsewhere they fall back to heap buffers.
+func MappingSupported() bool {
+	return mappingSupported
+}
+
+// NewMappedWords creates a temporary file in dir, sized for words big.Words,
+// and maps it into memory. The file is sparse: disk space is only used for
+// the pages that are written. When writes are denied (--no-write, see
+// fsguard), the buffer falls back to the heap, as on platforms without
+// memory mapping.
+//
+// Parameters:
+//   - dir: The directory for the temporary file; os.TempDir() if empty.
+//   - words: The capacity of the buffer in words.
+//
+// Returns:
+//   - *MappedWords: The mapped buffer.
+//   - error: An error if the file could not be created or mapped.
+func NewMappedWords(dir string, words int) (*MappedWords, error) {
+	if words <= 0 {
+		return nil, fmt.Errorf("memory: invalid mapped buffer size %d", words)
+	}
+	f, err := fsguard.CreateTemp(dir, "fibcalc-lowmem-*.bin")
+	if errors.Is(err, fsguard.ErrDenied) {
+		return &MappedWords{Words: make([]big.Word, words)}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("memory: creating mapped buffer: %w", err)
+	}
+	size := int64(words) * wordBytes
+	if err := f.Truncate(size); err != nil {
+		f.Close()
+		fsguard.Remove(f.Name())
+		return nil, fmt.Errorf("memory: sizing mapped buffer: %w", err)
+	}
+	data, err := mapFile(f, int(size))
+	if err != nil {
+		f.Close()
+		fsguard.Remove(f.Name())
+		return nil, fmt.Errorf("memory: mapping %s: %w", f.Name(), err)
+	}
+	return &MappedWords{
+		Words: unsafe.Slice((*big.Word)(unsafe.Pointer(unsafe.SliceData(data))), words),
+		file:  f,
+		data:  data,
+	}, nil
+}
+
+// Close unmaps the buffer, closes the file and deletes it. It is safe to
+// call Close more than once.
+//
+// Returns:
+//   - error: The first error encountered, if any.
+func (m *MappedWords) Close() error {
+	if m == nil || m.file == nil {
+		return nil
+	}
+	err := unmapFile(m.data)
+	m.Words, m.data = nil, nil
+	if cerr := m.file.Close(); err == nil {
+		err = cerr
+	}
+	if rerr := fsguard.Remove(m.file.Name()); err == nil {
+		err = rerr
+	}
+	m.file = nil
+	return err
+}
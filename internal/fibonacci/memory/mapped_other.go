@@ -0,0 +1,19 @@
+//go:build !unix && !windows
+
+package memory
+
+import "os"
+
+// mappingSupported reports that buffers fall back to the heap.
+const mappingSupported = false
+
+// mapFile falls back to a heap buffer on platforms without memory mapping.
+// The file is still created so that behavior is otherwise the same.
+func mapFile(_ *os.File, size int) ([]byte, error) {
+	return make([]byte, size), nil
+}
+
+// unmapFile is a no-op for heap buffers.
+func unmapFile([]byte) error {
+	return nil
+}
@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"os"
+	"testing"
+
+	"github.com/agbru/fibcalc/internal/fsguard"
+)
+
+func TestMappedWords(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	m, err := NewMappedWords(dir, 1<<16)
+	if err != nil {
+		t.Fatalf("NewMappedWords: %v", err)
+	}
+	if len(m.Words) != 1<<16 {
+		t.Fatalf("len(Words) = %d, want %d", len(m.Words), 1<<16)
+	}
+	for i, w := range m.Words {
+		if w != 0 {
+			t.Fatalf("Words[%d] = %d, want a zeroed buffer", i, w)
+		}
+	}
+	m.Words[0], m.Words[len(m.Words)-1] = 42, 7
+	if m.Words[0] != 42 || m.Words[len(m.Words)-1] != 7 {
+		t.Error("writes to the mapped buffer were not kept")
+	}
+
+	if err := m.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := m.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("Close left %d files in %s", len(entries), dir)
+	}
+}
+
+func TestNewMappedWords_InvalidSize(t *testing.T) {
+	t.Parallel()
+	if _, err := NewMappedWords(t.TempDir(), 0); err == nil {
+		t.Error("NewMappedWords(0) should fail")
+	}
+}
+
+// TestNewMappedWordsDenied checks the heap fallback when writes are denied.
+// It denies writes process-wide, so it does not run in parallel.
+func TestNewMappedWordsDenied(t *testing.T) {
+	dir := t.TempDir()
+	defer fsguard.Deny()()
+
+	m, err := NewMappedWords(dir, 1024)
+	if err != nil {
+		t.Fatalf("NewMappedWords with writes denied: %v", err)
+	}
+	if len(m.Words) != 1024 {
+		t.Errorf("len(Words) = %d, want 1024", len(m.Words))
+	}
+	m.Words[1023] = 7
+	if err := m.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("NewMappedWords created %d files with writes denied", len(entries))
+	}
+}
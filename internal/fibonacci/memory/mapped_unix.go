@@ -0,0 +1,24 @@
+//go:build unix
+
+package memory
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// mappingSupported reports that buffers are memory-mapped files.
+const mappingSupported = true
+
+// mapFile maps the first size bytes of f for reading and writing. Writes
+// go to the file, so dirty pages can be flushed and evicted under memory
+// pressure.
+func mapFile(f *os.File, size int) ([]byte, error) {
+	return unix.Mmap(int(f.Fd()), 0, size, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_SHARED)
+}
+
+// unmapFile releases a mapping created by mapFile.
+func unmapFile(data []byte) error {
+	return unix.Munmap(data)
+}
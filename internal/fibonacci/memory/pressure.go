@@ -0,0 +1,182 @@
+package memory
+
+import (
+	"context"
+	"math"
+	"runtime/metrics"
+	"slices"
+	"sync"
+	"time"
+)
+
+// DefaultPressureThreshold is the fraction of the memory limit from which
+// PressureMonitor reports pressure.
+const DefaultPressureThreshold = 0.9
+
+// pressureRearm is the fraction of the threshold below which the monitor
+// reports pressure again after a crossing, so that a heap hovering around
+// the threshold does not repeat the warning every sample.
+const pressureRearm = 0.9
+
+// DefaultPressureInterval is the sampling period of PressureMonitor.Run.
+const DefaultPressureInterval = 250 * time.Millisecond
+
+// Pressure describes a crossing of the pressure threshold.
+type Pressure struct {
+	// Used is the memory the Go runtime held, heap and runtime structures,
+	// minus what it had released to the OS: the amount the GC compares
+	// with the limit.
+	Used uint64
+	// Limit is the soft memory limit of the user (GOMEMLIMIT or
+	// debug.SetMemoryLimit). The safety limit GCController sets while the
+	// GC is disabled is not one: it is three times the memory at the start
+	// of the calculation, which the calculation outgrows by design.
+	Limit uint64
+}
+
+// PressureMonitor samples the memory of the Go runtime against its soft
+// memory limit and calls its handlers when the memory approaches it. Near
+// the limit, the GC runs back to back to stay under it and a large
+// multiplication crawls; the handlers give memory back first, such as the
+// FFT transform caches. Without a limit it never reports pressure. A
+// PressureMonitor is safe for concurrent use.
+type PressureMonitor struct {
+	threshold float64
+	read      func() (used, limit uint64)
+
+	mu       sync.Mutex
+	handlers []pressureHandler // in registration order
+	nextID   int
+	pressed  bool
+	events   int64
+}
+
+// NewPressureMonitor returns a monitor of the runtime's memory limit.
+//
+// Parameters:
+//   - threshold: The fraction of the limit from which it reports pressure,
+//     in (0, 1); otherwise DefaultPressureThreshold.
+//
+// Returns:
+//   - *PressureMonitor: The monitor; call Run to start sampling.
+func NewPressureMonitor(threshold float64) *PressureMonitor {
+	if threshold <= 0 || threshold >= 1 {
+		threshold = DefaultPressureThreshold
+	}
+	return &PressureMonitor{threshold: threshold, read: readRuntimeMemory}
+}
+
+// pressureHandler is a handler registered with OnPressure.
+type pressureHandler struct {
+	id int
+	fn func(Pressure)
+}
+
+// runtimeMemorySamples are the runtime/metrics read by readRuntimeMemory.
+var runtimeMemorySamples = []string{"/memory/classes/total:bytes", "/memory/classes/heap/released:bytes"}
+
+// readRuntimeMemory returns the memory the runtime holds and the user's
+// soft limit (see userMemoryLimit), read without stopping the world.
+func readRuntimeMemory() (used, limit uint64) {
+	samples := make([]metrics.Sample, len(runtimeMemorySamples))
+	for i, name := range runtimeMemorySamples {
+		samples[i].Name = name
+	}
+	metrics.Read(samples)
+	total, released := samples[0].Value.Uint64(), samples[1].Value.Uint64()
+	if released < total {
+		used = total - released
+	}
+	return used, uint64(userMemoryLimit())
+}
+
+// OnPressure registers a handler called, from the sampling goroutine, each
+// time the memory crosses the threshold. Handlers run in the order they
+// were registered.
+//
+// Parameters:
+//   - handler: The function to call.
+//
+// Returns:
+//   - func(): Unregisters the handler, so that it no longer keeps what it
+//     references alive.
+func (m *PressureMonitor) OnPressure(handler func(Pressure)) func() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := m.nextID
+	m.nextID++
+	m.handlers = append(m.handlers, pressureHandler{id, handler})
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.handlers = slices.DeleteFunc(m.handlers, func(h pressureHandler) bool { return h.id == id })
+	}
+}
+
+// Run samples the memory every interval until ctx is canceled. The limit is
+// read at each sample, as it can change during the run.
+//
+// Parameters:
+//   - ctx: Stops the sampling when canceled.
+//   - interval: The sampling period; zero means DefaultPressureInterval.
+func (m *PressureMonitor) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultPressureInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		m.Observe(m.read())
+	}
+}
+
+// Observe records a sample and calls the handlers if it crosses the
+// threshold. The monitor reports pressure again once the memory has fallen
+// back under most of the threshold.
+//
+// Parameters:
+//   - used: The memory held by the runtime.
+//   - limit: The soft memory limit; math.MaxInt64 means none.
+//
+// Returns:
+//   - bool: true if the sample was reported as pressure.
+func (m *PressureMonitor) Observe(used, limit uint64) bool {
+	if limit == 0 || limit >= math.MaxInt64 {
+		return false
+	}
+	fraction := float64(used) / float64(limit)
+
+	m.mu.Lock()
+	switch {
+	case fraction >= m.threshold && !m.pressed:
+		m.pressed = true
+	case fraction < m.threshold*pressureRearm:
+		m.pressed = false
+		m.mu.Unlock()
+		return false
+	default:
+		m.mu.Unlock()
+		return false
+	}
+	m.events++
+	handlers := slices.Clone(m.handlers)
+	m.mu.Unlock()
+
+	p := Pressure{Used: used, Limit: limit}
+	for _, h := range handlers {
+		h.fn(p)
+	}
+	return true
+}
+
+// Events returns how many times the monitor reported pressure.
+func (m *PressureMonitor) Events() int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.events
+}
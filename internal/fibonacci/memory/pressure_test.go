@@ -0,0 +1,94 @@
+package memory
+
+import (
+	"math"
+	"runtime"
+	"runtime/debug"
+	"testing"
+)
+
+func TestPressureMonitor_Observe(t *testing.T) {
+	t.Parallel()
+
+	m := NewPressureMonitor(0.8)
+	var got []Pressure
+	m.OnPressure(func(p Pressure) { got = append(got, p) })
+	steps := []struct {
+		used, limit uint64
+		pressure    bool
+	}{
+		{50, 100, false},
+		{85, 100, true},
+		{95, 100, false}, // still above: reported once
+		{75, 100, false}, // under the threshold but not re-armed
+		{90, 100, false},
+		{70, 100, false}, // re-armed under 0.72
+		{80, 100, true},
+		{900, math.MaxInt64, false}, // no limit
+	}
+	for i, s := range steps {
+		if p := m.Observe(s.used, s.limit); p != s.pressure {
+			t.Errorf("step %d: Observe(%d, %d) = %v, want %v", i, s.used, s.limit, p, s.pressure)
+		}
+	}
+	if m.Events() != 2 || len(got) != 2 || got[0] != (Pressure{Used: 85, Limit: 100}) {
+		t.Errorf("events = %d, handler calls = %v, want 2 starting with 85 of 100", m.Events(), got)
+	}
+}
+
+func TestPressureMonitor_Unregister(t *testing.T) {
+	t.Parallel()
+
+	m := NewPressureMonitor(0)
+	var order []int
+	m.OnPressure(func(Pressure) { order = append(order, 1) })
+	remove := m.OnPressure(func(Pressure) { order = append(order, 2) })
+	m.OnPressure(func(Pressure) { order = append(order, 3) })
+	remove()
+
+	m.Observe(95, 100)
+	if len(order) != 2 || order[0] != 1 || order[1] != 3 {
+		t.Errorf("handlers called = %v, want [1 3]", order)
+	}
+}
+
+func TestReadRuntimeMemory(t *testing.T) {
+	t.Parallel()
+
+	used, limit := readRuntimeMemory()
+	if used == 0 || limit == 0 {
+		t.Errorf("readRuntimeMemory() = %d, %d; want non-zero", used, limit)
+	}
+}
+
+// TestPressureMonitor_GCSafetyLimit checks that the safety limit set while
+// the GC is disabled, which a large calculation outgrows, is not taken for
+// the user's limit. Not parallel: it changes the GC settings of the process.
+func TestPressureMonitor_GCSafetyLimit(t *testing.T) {
+	defer debug.SetMemoryLimit(debug.SetMemoryLimit(math.MaxInt64))
+
+	gc := NewGCController("auto", GCAutoThreshold)
+	gc.Begin()
+	defer gc.End()
+	safety := debug.SetMemoryLimit(-1)
+	if safety == math.MaxInt64 {
+		t.Fatal("GC auto mode set no safety limit")
+	}
+	// Outgrow the safety limit, as the calculation would
+	hold := make([]byte, safety)
+	for i := 0; i < len(hold); i += 4096 {
+		hold[i] = 1
+	}
+
+	m := NewPressureMonitor(0)
+	fired := false
+	m.OnPressure(func(Pressure) { fired = true })
+	used, limit := m.read()
+	if m.Observe(used, limit) || fired {
+		t.Errorf("pressure reported at %d of the safety limit %d, want none without a user limit", used, safety)
+	}
+	if limit != math.MaxInt64 {
+		t.Errorf("limit = %d, want the user's (none)", limit)
+	}
+	runtime.KeepAlive(hold)
+}
@@ -0,0 +1,195 @@
+// This file provides the calculator middleware layer: decorators that add
+// cross-cutting behaviour (panic recovery, timing, retries, memory limits,
+// tracing) around any Calculator implementation.
+
+package fibonacci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/clock"
+	"github.com/agbru/fibcalc/internal/fibonacci/memory"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrTransient marks a calculation error as transient. WithRetry only
+// retries errors that wrap it (e.g., fmt.Errorf("...: %w", ErrTransient)).
+var ErrTransient = errors.New("transient calculation error")
+
+// ErrMemoryLimitExceeded is returned by WithMemoryLimit when the estimated
+// memory of a calculation exceeds the configured limit.
+var ErrMemoryLimitExceeded = errors.New("estimated memory exceeds limit")
+
+// CalculateFunc has the signature of Calculator.Calculate.
+type CalculateFunc func(ctx context.Context, progressChan chan<- ProgressUpdate, calcIndex int, n uint64, opts Options) (*big.Int, error)
+
+// Middleware decorates a Calculator with additional behaviour.
+type Middleware func(Calculator) Calculator
+
+// wrappedCalculator is the Calculator produced by a middleware. It keeps the
+// name of the calculator it wraps.
+type wrappedCalculator struct {
+	name      string
+	calculate CalculateFunc
+}
+
+// Calculate runs the decorated calculation.
+func (w *wrappedCalculator) Calculate(ctx context.Context, progressChan chan<- ProgressUpdate, calcIndex int, n uint64, opts Options) (*big.Int, error) {
+	return w.calculate(ctx, progressChan, calcIndex, n, opts)
+}
+
+// Name returns the name of the wrapped calculator.
+func (w *wrappedCalculator) Name() string {
+	return w.name
+}
+
+// NewMiddleware builds a Middleware from a function that decorates the next
+// CalculateFunc in the chain.
+//
+// Parameters:
+//   - decorate: Receives the wrapped calculator's name and Calculate method
+//     and returns the decorated function.
+//
+// Returns:
+//   - Middleware: The middleware.
+func NewMiddleware(decorate func(name string, next CalculateFunc) CalculateFunc) Middleware {
+	return func(next Calculator) Calculator {
+		return &wrappedCalculator{name: next.Name(), calculate: decorate(next.Name(), next.Calculate)}
+	}
+}
+
+// WrapCalculator applies middlewares to a calculator. The first middleware
+// is the outermost one: WrapCalculator(c, A, B) runs A, then B, then c.
+//
+// Parameters:
+//   - calc: The calculator to decorate.
+//   - middlewares: The middlewares to apply, outermost first.
+//
+// Returns:
+//   - Calculator: The decorated calculator, with the same Name.
+func WrapCalculator(calc Calculator, middlewares ...Middleware) Calculator {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		calc = middlewares[i](calc)
+	}
+	return calc
+}
+
+// WithRecovery converts a panic in the calculation into an error
+// ("panic: <value>"), so that a faulty algorithm cannot crash the whole
+// application.
+func WithRecovery() Middleware {
+	return NewMiddleware(func(_ string, next CalculateFunc) CalculateFunc {
+		return func(ctx context.Context, progressChan chan<- ProgressUpdate, calcIndex int, n uint64, opts Options) (result *big.Int, err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					result, err = nil, fmt.Errorf("panic: %v", r)
+				}
+			}()
+			return next(ctx, progressChan, calcIndex, n, opts)
+		}
+	})
+}
+
+// WithTiming reports the wall-clock duration of every calculation.
+//
+// Parameters:
+//   - observe: Called after each calculation with the calculator name, the
+//     elapsed time and the calculation error (nil on success).
+func WithTiming(observe func(name string, d time.Duration, err error)) Middleware {
+	return WithTimingClock(clock.Real{}, observe)
+}
+
+// WithTimingClock is WithTiming with an explicit time source, so that
+// reported durations are deterministic under a fake clock.
+//
+// Parameters:
+//   - clk: The time source (the wall clock if nil).
+//   - observe: Called after each calculation with the calculator name, the
+//     elapsed time and the calculation error (nil on success).
+func WithTimingClock(clk clock.Clock, observe func(name string, d time.Duration, err error)) Middleware {
+	clk = clock.Or(clk)
+	return NewMiddleware(func(name string, next CalculateFunc) CalculateFunc {
+		return func(ctx context.Context, progressChan chan<- ProgressUpdate, calcIndex int, n uint64, opts Options) (*big.Int, error) {
+			start := clk.Now()
+			result, err := next(ctx, progressChan, calcIndex, n, opts)
+			observe(name, clk.Since(start), err)
+			return result, err
+		}
+	})
+}
+
+// WithRetry re-runs a calculation that failed with an error wrapping
+// ErrTransient, up to maxAttempts attempts in total, waiting backoff between
+// attempts (doubling each time). Non-transient errors and context
+// cancellation are returned immediately.
+//
+// Parameters:
+//   - maxAttempts: The total number of attempts (values below 1 mean 1).
+//   - backoff: The delay before the first retry.
+func WithRetry(maxAttempts int, backoff time.Duration) Middleware {
+	maxAttempts = max(maxAttempts, 1)
+	return NewMiddleware(func(name string, next CalculateFunc) CalculateFunc {
+		return func(ctx context.Context, progressChan chan<- ProgressUpdate, calcIndex int, n uint64, opts Options) (*big.Int, error) {
+			delay := backoff
+			for attempt := 1; ; attempt++ {
+				result, err := next(ctx, progressChan, calcIndex, n, opts)
+				if err == nil || attempt >= maxAttempts || !errors.Is(err, ErrTransient) {
+					return result, err
+				}
+				calcLogger(ctx, opts, &log.Logger).Debug().
+					Str("algo", name).
+					Int("attempt", attempt).
+					Err(err).
+					Msg("retrying transient calculation error")
+				select {
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				case <-time.After(delay):
+				}
+				delay *= 2
+			}
+		}
+	})
+}
+
+// WithMemoryLimit rejects calculations whose estimated memory usage (see
+// memory.EstimateMemoryUsage) exceeds limit bytes, before any work is done.
+//
+// Parameters:
+//   - limit: The memory budget in bytes; 0 disables the check.
+func WithMemoryLimit(limit uint64) Middleware {
+	return NewMiddleware(func(_ string, next CalculateFunc) CalculateFunc {
+		return func(ctx context.Context, progressChan chan<- ProgressUpdate, calcIndex int, n uint64, opts Options) (*big.Int, error) {
+			if limit > 0 {
+				if est := memory.EstimateMemoryUsage(n); est.TotalBytes > limit {
+					return nil, fmt.Errorf("%w: F(%d) needs about %d bytes, limit is %d bytes",
+						ErrMemoryLimitExceeded, n, est.TotalBytes, limit)
+				}
+			}
+			return next(ctx, progressChan, calcIndex, n, opts)
+		}
+	})
+}
+
+// WithTracing logs the start and end of every calculation at trace level,
+// to the logger of the calculation (see Options.Logger) or the global one.
+func WithTracing() Middleware {
+	return NewMiddleware(func(name string, next CalculateFunc) CalculateFunc {
+		return func(ctx context.Context, progressChan chan<- ProgressUpdate, calcIndex int, n uint64, opts Options) (*big.Int, error) {
+			logger := calcLogger(ctx, opts, &log.Logger)
+			logger.Trace().Str("algo", name).Int("index", calcIndex).Uint64("n", n).Msg("calculation started")
+			start := time.Now()
+			result, err := next(ctx, progressChan, calcIndex, n, opts)
+			event := logger.Trace().Str("algo", name).Int("index", calcIndex).Dur("duration", time.Since(start))
+			if err != nil {
+				event = event.Err(err)
+			}
+			event.Msg("calculation finished")
+			return result, err
+		}
+	})
+}
@@ -0,0 +1,155 @@
+package fibonacci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestWrapCalculatorOrder verifies that the first middleware is outermost
+// and that the wrapped calculator keeps its name.
+func TestWrapCalculatorOrder(t *testing.T) {
+	t.Parallel()
+	var order []string
+	trace := func(label string) Middleware {
+		return NewMiddleware(func(_ string, next CalculateFunc) CalculateFunc {
+			return func(ctx context.Context, progressChan chan<- ProgressUpdate, calcIndex int, n uint64, opts Options) (*big.Int, error) {
+				order = append(order, label)
+				return next(ctx, progressChan, calcIndex, n, opts)
+			}
+		})
+	}
+	calc := WrapCalculator(&MockCalculator{Result: big.NewInt(1)}, trace("outer"), trace("inner"))
+
+	if calc.Name() != "mock" {
+		t.Errorf("Name() = %q, want %q", calc.Name(), "mock")
+	}
+	if _, err := calc.Calculate(context.Background(), nil, 0, 10, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(order, ",") != "outer,inner" {
+		t.Errorf("order = %v, want [outer inner]", order)
+	}
+}
+
+func TestWithRecovery(t *testing.T) {
+	t.Parallel()
+	calc := WrapCalculator(&MockCalculator{Fn: func(context.Context, uint64) (*big.Int, error) {
+		panic("boom")
+	}}, WithRecovery())
+
+	res, err := calc.Calculate(context.Background(), nil, 0, 10, Options{})
+	if res != nil || err == nil || !strings.Contains(err.Error(), "panic: boom") {
+		t.Errorf("got (%v, %v), want panic converted to error", res, err)
+	}
+}
+
+func TestWithTiming(t *testing.T) {
+	t.Parallel()
+	var gotName string
+	var gotDuration time.Duration
+	calc := WrapCalculator(&MockCalculator{Fn: func(context.Context, uint64) (*big.Int, error) {
+		time.Sleep(5 * time.Millisecond)
+		return big.NewInt(1), nil
+	}}, WithTiming(func(name string, d time.Duration, err error) {
+		gotName, gotDuration = name, d
+	}))
+
+	if _, err := calc.Calculate(context.Background(), nil, 0, 10, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if gotName != "mock" || gotDuration < 5*time.Millisecond {
+		t.Errorf("observed (%q, %v), want (mock, >= 5ms)", gotName, gotDuration)
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	t.Parallel()
+
+	t.Run("retries transient errors", func(t *testing.T) {
+		t.Parallel()
+		attempts := 0
+		calc := WrapCalculator(&MockCalculator{Fn: func(context.Context, uint64) (*big.Int, error) {
+			attempts++
+			if attempts < 3 {
+				return nil, fmt.Errorf("flaky: %w", ErrTransient)
+			}
+			return big.NewInt(55), nil
+		}}, WithRetry(3, time.Millisecond))
+
+		res, err := calc.Calculate(context.Background(), nil, 0, 10, Options{})
+		if err != nil || res.Int64() != 55 || attempts != 3 {
+			t.Errorf("got (%v, %v) after %d attempts, want 55 after 3", res, err, attempts)
+		}
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		t.Parallel()
+		attempts := 0
+		calc := WrapCalculator(&MockCalculator{Fn: func(context.Context, uint64) (*big.Int, error) {
+			attempts++
+			return nil, ErrTransient
+		}}, WithRetry(2, time.Millisecond))
+
+		if _, err := calc.Calculate(context.Background(), nil, 0, 10, Options{}); !errors.Is(err, ErrTransient) || attempts != 2 {
+			t.Errorf("err = %v after %d attempts, want ErrTransient after 2", err, attempts)
+		}
+	})
+
+	t.Run("does not retry permanent errors", func(t *testing.T) {
+		t.Parallel()
+		attempts := 0
+		calc := WrapCalculator(&MockCalculator{Fn: func(context.Context, uint64) (*big.Int, error) {
+			attempts++
+			return nil, errors.New("permanent")
+		}}, WithRetry(5, time.Millisecond))
+
+		if _, err := calc.Calculate(context.Background(), nil, 0, 10, Options{}); err == nil || attempts != 1 {
+			t.Errorf("err = %v after %d attempts, want failure after 1", err, attempts)
+		}
+	})
+
+	t.Run("stops on context cancellation", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(context.Background())
+		calc := WrapCalculator(&MockCalculator{Fn: func(context.Context, uint64) (*big.Int, error) {
+			cancel()
+			return nil, ErrTransient
+		}}, WithRetry(5, time.Hour))
+
+		if _, err := calc.Calculate(ctx, nil, 0, 10, Options{}); !errors.Is(err, context.Canceled) {
+			t.Errorf("err = %v, want context.Canceled", err)
+		}
+	})
+}
+
+func TestWithMemoryLimit(t *testing.T) {
+	t.Parallel()
+	called := false
+	calc := WrapCalculator(&MockCalculator{Fn: func(context.Context, uint64) (*big.Int, error) {
+		called = true
+		return big.NewInt(1), nil
+	}}, WithMemoryLimit(1<<20))
+
+	if _, err := calc.Calculate(context.Background(), nil, 0, 1_000_000_000, Options{}); !errors.Is(err, ErrMemoryLimitExceeded) {
+		t.Errorf("err = %v, want ErrMemoryLimitExceeded", err)
+	}
+	if called {
+		t.Error("calculation ran despite exceeding the memory limit")
+	}
+	if _, err := calc.Calculate(context.Background(), nil, 0, 1000, Options{}); err != nil || !called {
+		t.Errorf("small calculation: err = %v, called = %v", err, called)
+	}
+}
+
+func TestWithTracing(t *testing.T) {
+	t.Parallel()
+	calc := WrapCalculator(&MockCalculator{Err: errors.New("fail")}, WithTracing())
+	if _, err := calc.Calculate(context.Background(), nil, 0, 10, Options{}); err == nil {
+		t.Error("tracing middleware should pass errors through")
+	}
+}
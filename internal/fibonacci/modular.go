@@ -1,9 +1,11 @@
 package fibonacci
 
 import (
+	"context"
 	"fmt"
 	"math/big"
 	"math/bits"
+	"strings"
 )
 
 // FastDoublingMod computes F(n) mod m using the fast doubling algorithm.
@@ -15,6 +17,12 @@ import (
 //	F(2k)   = F(k) * (2*F(k+1) - F(k))  mod m
 //	F(2k+1) = F(k+1)² + F(k)²            mod m
 func FastDoublingMod(n uint64, m *big.Int) (*big.Int, error) {
+	return fastDoublingMod(context.Background(), n, m)
+}
+
+// fastDoublingMod is FastDoublingMod with cancellation: ctx is checked at
+// each bit of n, so that large moduli can be interrupted.
+func fastDoublingMod(ctx context.Context, n uint64, m *big.Int) (*big.Int, error) {
 	if m == nil || m.Sign() <= 0 {
 		return nil, fmt.Errorf("modulus must be positive")
 	}
@@ -31,6 +39,9 @@ func FastDoublingMod(n uint64, m *big.Int) (*big.Int, error) {
 	numBits := bits.Len64(n)
 
 	for i := numBits - 1; i >= 0; i-- {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		// F(2k) = F(k) * (2*F(k+1) - F(k)) mod m
 		t1.Lsh(fk1, 1)
 		t1.Sub(t1, fk)
@@ -63,3 +74,31 @@ func FastDoublingMod(n uint64, m *big.Int) (*big.Int, error) {
 
 	return fk, nil
 }
+
+// LastDigits returns the last k decimal digits of F(n), computed as
+// F(n) mod 10^k with modular fast doubling: O(k) memory and about log2(n)
+// products of k-digit numbers, so milliseconds for k in the thousands
+// whatever the size of n. The result is only a window on F(n), not its
+// value.
+//
+// Parameters:
+//   - ctx: The context for cancellation.
+//   - n: The index of the Fibonacci number.
+//   - k: The number of digits, at least 1.
+//
+// Returns:
+//   - string: Exactly k digits, zero-padded on the left (F(10) with k=3
+//     gives "055").
+//   - error: An error if k is not positive or ctx is canceled.
+func LastDigits(ctx context.Context, n uint64, k int) (string, error) {
+	if k <= 0 {
+		return "", fmt.Errorf("digit count must be positive, got %d", k)
+	}
+	mod := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(k)), nil)
+	r, err := fastDoublingMod(ctx, n, mod)
+	if err != nil {
+		return "", err
+	}
+	digits := r.String()
+	return strings.Repeat("0", k-len(digits)) + digits, nil
+}
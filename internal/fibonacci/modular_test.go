@@ -82,3 +82,36 @@ func TestFastDoublingMod_InvalidModulus(t *testing.T) {
 		t.Error("expected error for negative modulus")
 	}
 }
+
+func TestLastDigits(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		n    uint64
+		k    int
+		want string
+	}{
+		{0, 1, "0"},
+		{10, 3, "055"},
+		{100, 5, "15075"},
+		{1000, 6, "228875"},
+	}
+	for _, tc := range cases {
+		got, err := LastDigits(context.Background(), tc.n, tc.k)
+		if err != nil {
+			t.Fatalf("LastDigits(%d, %d) error: %v", tc.n, tc.k, err)
+		}
+		if got != tc.want {
+			t.Errorf("LastDigits(%d, %d) = %q, want %q", tc.n, tc.k, got, tc.want)
+		}
+	}
+
+	if _, err := LastDigits(context.Background(), 10, 0); err == nil {
+		t.Error("LastDigits with k = 0 should fail")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := LastDigits(ctx, 1<<40, 20); err == nil {
+		t.Error("LastDigits with a canceled context should fail")
+	}
+}
@@ -0,0 +1,218 @@
+// Package mul defines the pluggable big-integer multiplication backends used
+// by the Fibonacci calculators. A backend implements Multiplier; the default
+// is a Tiered backend that uses math/big below the FFT threshold and bigfft
+// above it. Alternative backends (NTT, GMP, instrumented test doubles) can be
+// registered by name and selected through fibonacci.Options.
+package mul
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/agbru/fibcalc/internal/bigfft"
+)
+
+// Multiplier is a big-integer multiplication backend.
+//
+// The To variants store the result in z, reusing its buffer when possible;
+// z may be nil, in which case a new *big.Int is allocated. All methods return
+// the result, which callers must use instead of assuming it is z.
+type Multiplier interface {
+	// Name returns the identifier of the backend (e.g., "big", "fft").
+	Name() string
+	// Mul returns x * y as a new *big.Int.
+	Mul(x, y *big.Int) (*big.Int, error)
+	// Sqr returns x * x as a new *big.Int.
+	Sqr(x *big.Int) (*big.Int, error)
+	// MulTo computes x * y into z.
+	MulTo(z, x, y *big.Int) (*big.Int, error)
+	// SqrTo computes x * x into z.
+	SqrTo(z, x *big.Int) (*big.Int, error)
+}
+
+// Big multiplies with math/big, which internally switches to Karatsuba for
+// large operands.
+type Big struct{}
+
+// Name returns "big".
+func (Big) Name() string { return "big" }
+
+// Mul returns x * y.
+func (Big) Mul(x, y *big.Int) (*big.Int, error) { return new(big.Int).Mul(x, y), nil }
+
+// Sqr returns x * x.
+func (Big) Sqr(x *big.Int) (*big.Int, error) { return new(big.Int).Mul(x, x), nil }
+
+// MulTo computes x * y into z.
+func (Big) MulTo(z, x, y *big.Int) (*big.Int, error) {
+	if z == nil {
+		z = new(big.Int)
+	}
+	return z.Mul(x, y), nil
+}
+
+// SqrTo computes x * x into z.
+func (Big) SqrTo(z, x *big.Int) (*big.Int, error) {
+	if z == nil {
+		z = new(big.Int)
+	}
+	return z.Mul(x, x), nil
+}
+
+// FFT multiplies with the Schönhage–Strassen implementation in bigfft. Note
+// that bigfft itself falls back to math/big for operands below its internal
+// word threshold.
+type FFT struct {
+	// Cache is the transform cache to use. If nil, bigfft's global cache is
+	// used; calculators pass a per-calculation cache instead.
+	Cache *bigfft.TransformCache
+	// Spill, if set, maps the transform buffers of large multiplications
+	// from temporary files (see bigfft.Spill).
+	Spill *bigfft.Spill
+}
+
+// Name returns "fft".
+func (FFT) Name() string { return "fft" }
+
+// Mul returns x * y.
+func (f FFT) Mul(x, y *big.Int) (*big.Int, error) {
+	if f.Cache == nil && f.Spill == nil {
+		return bigfft.Mul(x, y)
+	}
+	return bigfft.MulToWithSpill(new(big.Int), x, y, f.cache(), f.Spill)
+}
+
+// Sqr returns x * x.
+func (f FFT) Sqr(x *big.Int) (*big.Int, error) {
+	if f.Cache == nil && f.Spill == nil {
+		return bigfft.Sqr(x)
+	}
+	return bigfft.SqrToWithSpill(new(big.Int), x, f.cache(), f.Spill)
+}
+
+// MulTo computes x * y into z.
+func (f FFT) MulTo(z, x, y *big.Int) (*big.Int, error) {
+	if z == nil {
+		z = new(big.Int)
+	}
+	if f.Cache == nil && f.Spill == nil {
+		return bigfft.MulTo(z, x, y)
+	}
+	return bigfft.MulToWithSpill(z, x, y, f.cache(), f.Spill)
+}
+
+// SqrTo computes x * x into z.
+func (f FFT) SqrTo(z, x *big.Int) (*big.Int, error) {
+	if z == nil {
+		z = new(big.Int)
+	}
+	if f.Cache == nil && f.Spill == nil {
+		return bigfft.SqrTo(z, x)
+	}
+	return bigfft.SqrToWithSpill(z, x, f.cache(), f.Spill)
+}
+
+// cache returns the transform cache to use: Cache, or bigfft's global cache.
+func (f FFT) cache() *bigfft.TransformCache {
+	if f.Cache == nil {
+		return bigfft.GetTransformCache()
+	}
+	return f.Cache
+}
+
+// Tiered dispatches to Large when the operands exceed Threshold bits and to
+// Small otherwise. A Threshold of 0 or less always selects Small.
+type Tiered struct {
+	Small     Multiplier
+	Large     Multiplier
+	Threshold int
+}
+
+// NewTiered returns the default backend: math/big below threshold bits and
+// bigfft above.
+//
+// Parameters:
+//   - threshold: The operand bit length above which FFT is used.
+//
+// Returns:
+//   - Tiered: The tiered backend.
+func NewTiered(threshold int) Tiered {
+	return Tiered{Small: Big{}, Large: FFT{}, Threshold: threshold}
+}
+
+// Name describes both tiers, e.g. "big+fft".
+func (t Tiered) Name() string { return t.Small.Name() + "+" + t.Large.Name() }
+
+// Mul returns x * y using the tier selected by the operand sizes.
+func (t Tiered) Mul(x, y *big.Int) (*big.Int, error) { return t.forPair(x, y).Mul(x, y) }
+
+// Sqr returns x * x using the tier selected by the operand size.
+func (t Tiered) Sqr(x *big.Int) (*big.Int, error) { return t.forPair(x, x).Sqr(x) }
+
+// MulTo computes x * y into z using the tier selected by the operand sizes.
+func (t Tiered) MulTo(z, x, y *big.Int) (*big.Int, error) { return t.forPair(x, y).MulTo(z, x, y) }
+
+// SqrTo computes x * x into z using the tier selected by the operand size.
+func (t Tiered) SqrTo(z, x *big.Int) (*big.Int, error) { return t.forPair(x, x).SqrTo(z, x) }
+
+// forPair selects Large only when both operands are above the threshold.
+func (t Tiered) forPair(x, y *big.Int) Multiplier {
+	if t.Threshold > 0 && x.BitLen() > t.Threshold && y.BitLen() > t.Threshold {
+		return t.Large
+	}
+	return t.Small
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Registry
+// ─────────────────────────────────────────────────────────────────────────────
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Multiplier{
+		Big{}.Name(): Big{},
+		FFT{}.Name(): FFT{},
+	}
+)
+
+// Register makes a backend available by name, replacing any backend
+// previously registered under the same name.
+//
+// Parameters:
+//   - m: The backend to register; its Name() is used as the key.
+func Register(m Multiplier) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[m.Name()] = m
+}
+
+// Lookup returns the backend registered under name.
+//
+// Parameters:
+//   - name: The backend name (e.g., "big", "fft").
+//
+// Returns:
+//   - Multiplier: The backend.
+//   - error: An error if no backend has that name.
+func Lookup(name string) (Multiplier, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	if m, ok := registry[name]; ok {
+		return m, nil
+	}
+	return nil, fmt.Errorf("unknown multiplication backend %q", name)
+}
+
+// Names returns the registered backend names in sorted order.
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,102 @@
+package mul
+
+import (
+	"math/big"
+	"testing"
+)
+
+// countingMultiplier records which operations were invoked.
+type countingMultiplier struct {
+	Big
+	calls int
+}
+
+func (c *countingMultiplier) Name() string { return "counting" }
+
+func (c *countingMultiplier) MulTo(z, x, y *big.Int) (*big.Int, error) {
+	c.calls++
+	return c.Big.MulTo(z, x, y)
+}
+
+func (c *countingMultiplier) SqrTo(z, x *big.Int) (*big.Int, error) {
+	c.calls++
+	return c.Big.SqrTo(z, x)
+}
+
+func TestBackendsAgree(t *testing.T) {
+	t.Parallel()
+	x := new(big.Int).Lsh(big.NewInt(3), 200_000)
+	x.Sub(x, big.NewInt(12345))
+	y := new(big.Int).Lsh(big.NewInt(7), 190_000)
+	y.Neg(y)
+	wantMul := new(big.Int).Mul(x, y)
+	wantSqr := new(big.Int).Mul(x, x)
+
+	for _, m := range []Multiplier{Big{}, FFT{}, NewTiered(1000)} {
+		t.Run(m.Name(), func(t *testing.T) {
+			t.Parallel()
+			if got, err := m.Mul(x, y); err != nil || got.Cmp(wantMul) != 0 {
+				t.Errorf("Mul mismatch (err=%v)", err)
+			}
+			if got, err := m.Sqr(x); err != nil || got.Cmp(wantSqr) != 0 {
+				t.Errorf("Sqr mismatch (err=%v)", err)
+			}
+			if got, err := m.MulTo(nil, x, y); err != nil || got.Cmp(wantMul) != 0 {
+				t.Errorf("MulTo(nil) mismatch (err=%v)", err)
+			}
+			z := new(big.Int)
+			if got, err := m.SqrTo(z, x); err != nil || got.Cmp(wantSqr) != 0 {
+				t.Errorf("SqrTo mismatch (err=%v)", err)
+			}
+		})
+	}
+}
+
+func TestTieredSelection(t *testing.T) {
+	t.Parallel()
+	small, large := &countingMultiplier{}, &countingMultiplier{}
+	tiered := Tiered{Small: small, Large: large, Threshold: 64}
+	a := new(big.Int).Lsh(big.NewInt(1), 100)
+	b := big.NewInt(3)
+
+	tiered.MulTo(nil, a, b) // one operand below the threshold
+	tiered.SqrTo(nil, b)
+	if small.calls != 2 || large.calls != 0 {
+		t.Errorf("small=%d large=%d, want 2/0", small.calls, large.calls)
+	}
+	tiered.MulTo(nil, a, a)
+	tiered.SqrTo(nil, a)
+	if large.calls != 2 {
+		t.Errorf("large=%d, want 2", large.calls)
+	}
+
+	disabled := Tiered{Small: small, Large: large, Threshold: 0}
+	disabled.SqrTo(nil, a)
+	if large.calls != 2 {
+		t.Error("threshold 0 should never select the large tier")
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+	for _, name := range []string{"big", "fft"} {
+		if _, err := Lookup(name); err != nil {
+			t.Errorf("Lookup(%q) error = %v", name, err)
+		}
+	}
+	if _, err := Lookup("ntt"); err == nil {
+		t.Error("Lookup of unregistered backend should fail")
+	}
+
+	Register(&countingMultiplier{})
+	if m, err := Lookup("counting"); err != nil || m.Name() != "counting" {
+		t.Errorf("registered backend not found: %v", err)
+	}
+	found := false
+	for _, name := range Names() {
+		found = found || name == "counting"
+	}
+	if !found {
+		t.Errorf("Names() = %v, missing registered backend", Names())
+	}
+}
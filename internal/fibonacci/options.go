@@ -2,7 +2,12 @@
 
 package fibonacci
 
-import "github.com/agbru/fibcalc/internal/bigfft"
+import (
+	"github.com/agbru/fibcalc/internal/bigfft"
+	"github.com/agbru/fibcalc/internal/fibonacci/memory"
+	"github.com/agbru/fibcalc/internal/fibonacci/mul"
+	"github.com/rs/zerolog"
+)
 
 // Options configures the Fibonacci calculation.
 type Options struct {
@@ -34,8 +39,96 @@ type Options struct {
 	// If 0, uses the default (5 iterations). Only used when EnableDynamicThresholds is true.
 	DynamicAdjustmentInterval int
 	// GCMode controls the garbage collector during calculation.
-	// Valid values: "auto" (default), "aggressive", "disabled".
+	// Valid values: "auto" (default), "aggressive", "disabled", "realtime".
 	GCMode string
+	// SoftRealtime bounds the stalls the calculation causes to other
+	// goroutines, so that a live display refreshes smoothly, at a small
+	// cost in throughput: the GC runs in memory.GCModeRealtime whatever
+	// GCMode, compute loops yield every parallel.YieldSlice, and the FFT
+	// threshold is capped at SoftRealtimeMaxMulBits, with dynamic
+	// thresholds off so that it stays there.
+	SoftRealtime bool
+	// DisableTables makes calculators run their algorithm for every n,
+	// instead of returning F(n) for n <= MaxTableN from the precomputed
+	// tables (see TableValue). Useful to test or time the algorithms on
+	// small inputs.
+	DisableTables bool
+	// Backend overrides the big-integer multiplication backend used by the
+	// adaptive strategy and the matrix algorithm. If nil, math/big is used
+	// below FFTThreshold and bigfft above it (see mul.NewTiered).
+	Backend mul.Multiplier
+	// ParallelGate, if set, is consulted before each doubling step that
+	// would multiply in parallel; when it refuses, the step multiplies
+	// sequentially. It lets a monitor of external CPU load (see
+	// sysmon.ContentionMonitor) back off on a busy machine.
+	ParallelGate ParallelGate
+	// SpillThresholdBytes, if positive, places the FFT transform buffers of
+	// multiplications whose buffers exceed it in memory-mapped temporary
+	// files instead of the heap (see bigfft.Spill), so that the operating
+	// system can page them out. It lets a calculation run slightly beyond
+	// the available RAM, at the cost of disk I/O.
+	SpillThresholdBytes int64
+	// SpillDir is the directory of the spill files; os.TempDir() if empty.
+	SpillDir string
+	// StatePool, if set, provides the working states of the doubling
+	// calculators and keeps them after the calculation, so that the next
+	// calculation of the session reuses their buffers.
+	StatePool *StatePool
+	// MemoryPressure, if set, disables the transform cache of the
+	// calculation and drops its entries when the memory approaches the
+	// runtime's soft limit (see memory.PressureMonitor).
+	MemoryPressure *memory.PressureMonitor
+	// OnStep, if set, is called after each step of the fast doubling loop
+	// (fast and fft calculators) and of the matrix loop, with the values
+	// it produced, for educational traces (--explain). Values read from
+	// the tables produce no step (see DisableTables).
+	OnStep StepHook
+	// Heartbeat, if set, receives the progress reports of the algorithm
+	// itself, before the progress display coalesces and interpolates them
+	// (see progress.AdaptiveReporter): it stops when the calculation stops
+	// making progress, for stall detection.
+	Heartbeat ProgressCallback
+	// Logger, if set, receives the diagnostics of the calculation: task
+	// distribution, GC control, threshold adjustments, transform cache
+	// statistics. Otherwise the logger attached to the context with
+	// zerolog.Logger.WithContext is used, and failing that the package
+	// loggers (see SetTaskLogger).
+	Logger *zerolog.Logger
+
+	// transformCache is the FFT transform cache of the current calculation,
+	// created from the FFTCache* fields by CalculateWithObservers. Keeping it
+	// in the options rather than in bigfft's global cache lets concurrent
+	// calculations use different cache settings without interfering.
+	transformCache *bigfft.TransformCache
+	// spill is the spill of the current calculation, created from the
+	// Spill* fields by WithTransformCache.
+	spill *bigfft.Spill
+}
+
+// ParallelGate decides, while a calculation runs, whether parallel
+// multiplications are currently worthwhile. It must be safe for concurrent
+// use.
+type ParallelGate interface {
+	// AllowParallel reports whether the next step may multiply in parallel.
+	AllowParallel() bool
+}
+
+// multiplier returns the multiplication backend selected by opts.
+//
+// Returns:
+//   - mul.Multiplier: opts.Backend if set, otherwise the default tiered
+//     backend bound to the calculation's transform cache.
+func (opts Options) multiplier() mul.Multiplier {
+	if opts.Backend != nil {
+		return opts.Backend
+	}
+	return mul.Tiered{Small: mul.Big{}, Large: opts.fftBackend(), Threshold: opts.FFTThreshold}
+}
+
+// fftBackend returns the FFT backend bound to the calculation's transform
+// cache (bigfft's global cache if none was set up).
+func (opts Options) fftBackend() mul.FFT {
+	return mul.FFT{Cache: opts.transformCache, Spill: opts.spill}
 }
 
 // normalizeOptions returns a copy of opts with default values filled in for zero values.
@@ -57,20 +150,25 @@ func normalizeOptions(opts Options) Options {
 	if normalized.StrassenThreshold == 0 {
 		normalized.StrassenThreshold = DefaultStrassenThreshold
 	}
+	if normalized.SoftRealtime {
+		normalized.FFTThreshold = min(normalized.FFTThreshold, SoftRealtimeMaxMulBits)
+		normalized.EnableDynamicThresholds = false
+	}
 	return normalized
 }
 
-// configureFFTCache configures the FFT transform cache based on the provided options.
-// This optimization allows reusing expensive FFT transforms across iterations,
-// providing 15-30% speedup for large calculations where FFT is used.
-func configureFFTCache(opts Options) {
-	// Get default config to use as base
-	defaultConfig := bigfft.DefaultTransformCacheConfig()
-	config := bigfft.TransformCacheConfig{
-		MaxEntries: defaultConfig.MaxEntries,
-		MinBitLen:  defaultConfig.MinBitLen,
-		Enabled:    defaultConfig.Enabled,
-	}
+// transformCacheConfig builds the FFT transform cache configuration from the
+// provided options, starting from bigfft's defaults. Caching allows reusing
+// expensive FFT transforms across iterations, providing 15-30% speedup for
+// large calculations where FFT is used.
+//
+// Parameters:
+//   - opts: The calculation options.
+//
+// Returns:
+//   - bigfft.TransformCacheConfig: The cache configuration for this calculation.
+func transformCacheConfig(opts Options) bigfft.TransformCacheConfig {
+	config := bigfft.DefaultTransformCacheConfig()
 
 	// Override with user-provided options if specified
 	if opts.FFTCacheMaxEntries > 0 {
@@ -82,7 +180,44 @@ func configureFFTCache(opts Options) {
 	if opts.FFTCacheEnabled != nil {
 		config.Enabled = *opts.FFTCacheEnabled
 	}
+	return config
+}
+
+// WithTransformCache returns a copy of opts carrying a fresh transform cache
+// configured from opts, unless one is already attached, and the spill of
+// SpillThresholdBytes if set. Callers that want the cache or spill
+// statistics of a calculation attach them themselves and read them back
+// with TransformCacheStats and SpillStats.
+func (opts Options) WithTransformCache() Options {
+	if opts.transformCache == nil {
+		opts.transformCache = bigfft.NewTransformCache(transformCacheConfig(opts))
+	}
+	if opts.spill == nil {
+		opts.spill = bigfft.NewSpill(opts.SpillThresholdBytes, opts.SpillDir)
+	}
+	return opts
+}
+
+// TransformCacheStats returns the statistics of the attached transform cache.
+//
+// Returns:
+//   - bigfft.CacheStats: The hits, misses and size of the cache.
+//   - bool: false if no cache is attached.
+func (opts Options) TransformCacheStats() (bigfft.CacheStats, bool) {
+	if opts.transformCache == nil {
+		return bigfft.CacheStats{}, false
+	}
+	return opts.transformCache.Stats(), true
+}
 
-	// Apply configuration to global cache
-	bigfft.SetTransformCacheConfig(config)
+// SpillStats returns the statistics of the attached spill.
+//
+// Returns:
+//   - bigfft.SpillStats: The buffers moved to disk.
+//   - bool: false if spilling is disabled.
+func (opts Options) SpillStats() (bigfft.SpillStats, bool) {
+	if opts.spill == nil {
+		return bigfft.SpillStats{}, false
+	}
+	return opts.spill.Stats(), true
 }
@@ -2,6 +2,8 @@ package fibonacci
 
 import (
 	"testing"
+
+	"github.com/agbru/fibcalc/internal/parallel"
 )
 
 // ─────────────────────────────────────────────────────────────────────────────
@@ -64,6 +66,18 @@ func TestNormalizeOptions(t *testing.T) {
 		}
 	})
 
+	t.Run("soft real-time caps the FFT threshold", func(t *testing.T) {
+		t.Parallel()
+		normalized := normalizeOptions(Options{FFTThreshold: 4_000_000, EnableDynamicThresholds: true, SoftRealtime: true})
+		if normalized.FFTThreshold != SoftRealtimeMaxMulBits || normalized.EnableDynamicThresholds {
+			t.Errorf("FFTThreshold = %d, dynamic thresholds %v, want %d without dynamic thresholds",
+				normalized.FFTThreshold, normalized.EnableDynamicThresholds, SoftRealtimeMaxMulBits)
+		}
+		if got := normalizeOptions(Options{FFTThreshold: 100_000, SoftRealtime: true}).FFTThreshold; got != 100_000 {
+			t.Errorf("FFTThreshold = %d, want a lower threshold kept", got)
+		}
+	})
+
 	t.Run("does not modify original options", func(t *testing.T) {
 		t.Parallel()
 		original := Options{
@@ -116,3 +130,30 @@ func TestOptionsFFTCacheEnabled(t *testing.T) {
 		}
 	})
 }
+
+// TestCalculateSoftRealtime verifies that a soft real-time calculation
+// yields throughout and leaves the mode when it returns. It changes the
+// global yield and GC settings, so it does not run in parallel.
+func TestCalculateSoftRealtime(t *testing.T) {
+	before := parallel.NeedsYield()
+	yielding := true
+	opts := Options{
+		SoftRealtime:  true,
+		DisableTables: true,
+		OnStep:        func(StepEvent) { yielding = yielding && parallel.NeedsYield() },
+	}
+	calc := NewCalculator(&OptimizedFastDoubling{})
+	got, err := calc.Calculate(t.Context(), nil, 0, 10_000, opts)
+	if err != nil {
+		t.Fatalf("Calculate: %v", err)
+	}
+	if want := calculateReference(10_000); got.Cmp(want) != 0 {
+		t.Error("soft real-time mode changed the result")
+	}
+	if !yielding {
+		t.Error("compute loops did not yield during the soft real-time calculation")
+	}
+	if parallel.NeedsYield() != before {
+		t.Error("the calculation did not leave soft real-time mode")
+	}
+}
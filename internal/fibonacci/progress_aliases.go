@@ -29,8 +29,14 @@ type (
 
 	// NoOpObserver is a type alias for progress.NoOpObserver.
 	NoOpObserver = progress.NoOpObserver
+
+	// AdaptiveReporter is a type alias for progress.AdaptiveReporter.
+	AdaptiveReporter = progress.AdaptiveReporter
 )
 
+// ProgressUpdateInterval is the target interval between adaptive progress updates.
+const ProgressUpdateInterval = progress.ProgressUpdateInterval
+
 // Re-exported constructors and functions from internal/progress.
 var (
 	// NewProgressSubject creates a new progress subject.
@@ -45,6 +51,9 @@ var (
 	// NewNoOpObserver creates a new no-op observer.
 	NewNoOpObserver = progress.NewNoOpObserver
 
+	// NewAdaptiveReporter creates a time-based adaptive progress reporter.
+	NewAdaptiveReporter = progress.NewAdaptiveReporter
+
 	// CalcTotalWork calculates the total work for O(log n) algorithms.
 	CalcTotalWork = progress.CalcTotalWork
 
@@ -42,13 +42,55 @@ func SetRegistryLogger(l zerolog.Logger) {
 	registryLogger = l
 }
 
+// AutoAlgorithm is the pseudo-algorithm name that defers the choice of
+// calculator to the factory's SelectionPolicy.
+const AutoAlgorithm = "auto"
+
+// SelectionPolicy picks the calculator to use for computing F(n).
+//
+// Parameters:
+//   - n: The index of the Fibonacci number to compute.
+//   - available: The sorted names of the registered calculators.
+//
+// Returns:
+//   - string: The name of the chosen calculator.
+type SelectionPolicy func(n uint64, available []string) string
+
+// DefaultSelectionPolicy chooses "fast" when it is registered, since fast
+// doubling is the quickest algorithm across all ranges without calibration
+// data, and otherwise falls back to the first available calculator.
+//
+// Parameters:
+//   - n: The index of the Fibonacci number to compute (unused).
+//   - available: The sorted names of the registered calculators.
+//
+// Returns:
+//   - string: The name of the chosen calculator, or "" if none is available.
+func DefaultSelectionPolicy(_ uint64, available []string) string {
+	for _, name := range available {
+		if name == "fast" {
+			return name
+		}
+	}
+	if len(available) > 0 {
+		return available[0]
+	}
+	return ""
+}
+
 // DefaultFactory is the default implementation of CalculatorFactory.
 // It maintains a thread-safe registry of calculator creators and
-// caches Calculator instances for reuse.
+// caches Calculator instances for reuse. All methods are safe for
+// concurrent use, so calculators and aliases can be registered while
+// other goroutines are resolving them.
 type DefaultFactory struct {
 	mu          sync.RWMutex
 	creators    map[string]func() coreCalculator
 	calculators map[string]Calculator
+	aliases     map[string]string
+	deprecated  map[string]string
+	warned      map[string]bool
+	policy      SelectionPolicy
 }
 
 // NewDefaultFactory creates a new DefaultFactory with the standard
@@ -58,6 +100,11 @@ type DefaultFactory struct {
 //   - "fast": OptimizedFastDoubling (O(log n), Parallel, Zero-Alloc)
 //   - "matrix": MatrixExponentiation (O(log n), Parallel, Zero-Alloc)
 //   - "fft": FFTBasedCalculator (O(log n), FFT-accelerated)
+//   - "lowmem": LowMemoryFastDoubling (memory-mapped operands, streamed blocks)
+//
+// Pre-registered aliases:
+//   - "fd", "fast-doubling" → "fast"
+//   - "mat" → "matrix"
 //
 // Returns:
 //   - *DefaultFactory: A new factory with default calculators registered.
@@ -65,12 +112,22 @@ func NewDefaultFactory() *DefaultFactory {
 	f := &DefaultFactory{
 		creators:    make(map[string]func() coreCalculator),
 		calculators: make(map[string]Calculator),
+		aliases:     make(map[string]string),
+		deprecated:  make(map[string]string),
+		warned:      make(map[string]bool),
+		policy:      DefaultSelectionPolicy,
 	}
 
 	// Register the default calculators
 	_ = f.Register("fast", func() coreCalculator { return &OptimizedFastDoubling{} })
 	_ = f.Register("matrix", func() coreCalculator { return &MatrixExponentiation{} })
 	_ = f.Register("fft", func() coreCalculator { return &FFTBasedCalculator{} })
+	_ = f.Register("lowmem", func() coreCalculator { return &LowMemoryFastDoubling{} })
+
+	// Register the default aliases
+	_ = f.RegisterAlias("fd", "fast")
+	_ = f.RegisterAlias("fast-doubling", "fast")
+	_ = f.RegisterAlias("mat", "matrix")
 
 	return f
 }
@@ -78,20 +135,183 @@ func NewDefaultFactory() *DefaultFactory {
 // Register adds a new calculator type to the factory.
 // The creator function is called lazily when the calculator is first requested.
 // If a calculator with the same name already exists, it will be replaced.
+// A calculator registered under the name of an existing alias shadows it:
+// the alias is removed.
 //
 // Parameters:
 //   - name: The unique identifier for the calculator type.
 //   - creator: A function that creates a new coreCalculator instance.
+//
+// Returns:
+//   - error: An error if the name is empty or reserved, or the creator is nil.
 func (f *DefaultFactory) Register(name string, creator func() coreCalculator) error {
+	if name == "" || name == AutoAlgorithm || name == "all" {
+		return fmt.Errorf("invalid calculator name: %q", name)
+	}
+	if creator == nil {
+		return fmt.Errorf("nil creator for calculator: %s", name)
+	}
+
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
 	f.creators[name] = creator
+	delete(f.aliases, name)
 	// Clear cached calculator if it exists, so it will be recreated with the new creator
 	delete(f.calculators, name)
 	return nil
 }
 
+// RegisterAlias adds an alternative name for a registered calculator.
+// Aliases resolve to their target in Get, Create and Has, so "fd" and
+// "fast" return the same cached instance.
+//
+// Parameters:
+//   - alias: The alternative name.
+//   - target: The name of the registered calculator the alias points to.
+//
+// Returns:
+//   - error: An error if the alias clashes with a calculator name or the
+//     target is not registered.
+func (f *DefaultFactory) RegisterAlias(alias, target string) error {
+	if alias == "" || alias == AutoAlgorithm || alias == "all" {
+		return fmt.Errorf("invalid calculator alias: %q", alias)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, exists := f.creators[alias]; exists {
+		return fmt.Errorf("alias %s clashes with a registered calculator", alias)
+	}
+	if _, exists := f.creators[target]; !exists {
+		return fmt.Errorf("alias %s targets unknown calculator: %s", alias, target)
+	}
+	f.aliases[alias] = target
+	return nil
+}
+
+// Deprecate marks a calculator name or alias as deprecated. It keeps
+// working, but Resolve logs the notice the first time the name is used
+// and DeprecationNotice reports it so callers can warn the user.
+//
+// Parameters:
+//   - name: The calculator name or alias to deprecate.
+//   - notice: A short explanation, e.g. "use 'fast' instead".
+func (f *DefaultFactory) Deprecate(name, notice string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deprecated[name] = notice
+}
+
+// DeprecationNotice reports whether a name is deprecated.
+//
+// Parameters:
+//   - name: The calculator name or alias to check.
+//
+// Returns:
+//   - string: The deprecation notice, if any.
+//   - bool: true if the name is deprecated.
+func (f *DefaultFactory) DeprecationNotice(name string) (string, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	notice, ok := f.deprecated[name]
+	return notice, ok
+}
+
+// Aliases returns a copy of the registered aliases, keyed by alias.
+//
+// Returns:
+//   - map[string]string: A map of alias to target calculator name.
+func (f *DefaultFactory) Aliases() map[string]string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	result := make(map[string]string, len(f.aliases))
+	for alias, target := range f.aliases {
+		result[alias] = target
+	}
+	return result
+}
+
+// Resolve returns the canonical calculator name for a name or alias.
+// Deprecated names are logged once per factory.
+//
+// Parameters:
+//   - name: The calculator name or alias.
+//
+// Returns:
+//   - string: The canonical calculator name.
+//   - error: An error if the name is not registered.
+func (f *DefaultFactory) Resolve(name string) (string, error) {
+	f.mu.RLock()
+	canonical, ok := f.resolveLocked(name)
+	notice, deprecated := f.deprecated[name]
+	warned := f.warned[name]
+	f.mu.RUnlock()
+
+	if !ok {
+		return "", fmt.Errorf("unknown calculator: %s", name)
+	}
+	if deprecated && !warned {
+		f.mu.Lock()
+		if !f.warned[name] {
+			f.warned[name] = true
+			registryLogger.Warn().Str("calculator", name).Str("notice", notice).Msg("deprecated calculator name")
+		}
+		f.mu.Unlock()
+	}
+	return canonical, nil
+}
+
+// resolveLocked maps a name or alias to a registered calculator name.
+// The caller must hold f.mu.
+func (f *DefaultFactory) resolveLocked(name string) (string, bool) {
+	if _, ok := f.creators[name]; ok {
+		return name, true
+	}
+	if target, ok := f.aliases[name]; ok {
+		if _, exists := f.creators[target]; exists {
+			return target, true
+		}
+	}
+	return "", false
+}
+
+// SetSelectionPolicy replaces the policy used to resolve AutoAlgorithm.
+// A nil policy restores DefaultSelectionPolicy.
+//
+// Parameters:
+//   - policy: The selection policy to use.
+func (f *DefaultFactory) SetSelectionPolicy(policy SelectionPolicy) {
+	if policy == nil {
+		policy = DefaultSelectionPolicy
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.policy = policy
+}
+
+// Select returns the calculator the selection policy picks for F(n).
+//
+// Parameters:
+//   - n: The index of the Fibonacci number to compute.
+//
+// Returns:
+//   - Calculator: The selected Calculator instance.
+//   - error: An error if the policy picks an unregistered calculator.
+func (f *DefaultFactory) Select(n uint64) (Calculator, error) {
+	f.mu.RLock()
+	policy := f.policy
+	f.mu.RUnlock()
+
+	name := policy(n, f.List())
+	if name == "" {
+		return nil, fmt.Errorf("no calculator available for n=%d", n)
+	}
+	registryLogger.Debug().Str("calculator", name).Uint64("n", n).Msg("calculator selected by policy")
+	return f.Get(name)
+}
+
 // Create creates a new Calculator instance by name.
 // Unlike Get(), this always creates a fresh instance without caching.
 //
@@ -102,8 +322,14 @@ func (f *DefaultFactory) Register(name string, creator func() coreCalculator) er
 //   - Calculator: A new Calculator instance.
 //   - error: An error if the calculator type is not registered.
 func (f *DefaultFactory) Create(name string) (Calculator, error) {
+	canonical, err := f.Resolve(name)
+	if err != nil {
+		registryLogger.Debug().Str("calculator", name).Msg("calculator not found")
+		return nil, err
+	}
+
 	f.mu.RLock()
-	creator, ok := f.creators[name]
+	creator, ok := f.creators[canonical]
 	f.mu.RUnlock()
 
 	if !ok {
@@ -115,8 +341,9 @@ func (f *DefaultFactory) Create(name string) (Calculator, error) {
 	return calc, nil
 }
 
-// Get returns a Calculator instance by name.
-// Instances are cached and reused for subsequent calls with the same name.
+// Get returns a Calculator instance by name or alias.
+// Instances are created lazily on first use, then cached and reused for
+// subsequent calls with the same name or any of its aliases.
 // This is the preferred method for most use cases.
 //
 // Parameters:
@@ -126,9 +353,15 @@ func (f *DefaultFactory) Create(name string) (Calculator, error) {
 //   - Calculator: The Calculator instance.
 //   - error: An error if the calculator type is not registered.
 func (f *DefaultFactory) Get(name string) (Calculator, error) {
+	canonical, err := f.Resolve(name)
+	if err != nil {
+		registryLogger.Debug().Str("calculator", name).Msg("calculator not found")
+		return nil, err
+	}
+
 	// Check cache first with read lock
 	f.mu.RLock()
-	if calc, exists := f.calculators[name]; exists {
+	if calc, exists := f.calculators[canonical]; exists {
 		f.mu.RUnlock()
 		return calc, nil
 	}
@@ -139,18 +372,20 @@ func (f *DefaultFactory) Get(name string) (Calculator, error) {
 	defer f.mu.Unlock()
 
 	// Double-check after acquiring write lock
-	if calc, exists := f.calculators[name]; exists {
+	if calc, exists := f.calculators[canonical]; exists {
 		return calc, nil
 	}
 
-	creator, ok := f.creators[name]
+	// The calculator may have been replaced or removed between the
+	// two lock acquisitions.
+	creator, ok := f.creators[canonical]
 	if !ok {
 		registryLogger.Debug().Str("calculator", name).Msg("calculator not found")
 		return nil, fmt.Errorf("unknown calculator: %s", name)
 	}
 
 	calc := NewCalculator(creator())
-	f.calculators[name] = calc
+	f.calculators[canonical] = calc
 	registryLogger.Debug().Str("calculator", name).Msg("calculator created and cached")
 	return calc, nil
 }
@@ -217,20 +452,58 @@ func (f *DefaultFactory) MustGet(name string) Calculator {
 	return calc
 }
 
-// Has checks if a calculator with the given name is registered.
+// Has checks if a calculator with the given name or alias is registered.
 //
 // Parameters:
-//   - name: The name of the calculator to check.
+//   - name: The name or alias of the calculator to check.
 //
 // Returns:
 //   - bool: true if the calculator is registered, false otherwise.
 func (f *DefaultFactory) Has(name string) bool {
 	f.mu.RLock()
 	defer f.mu.RUnlock()
-	_, exists := f.creators[name]
+	_, exists := f.resolveLocked(name)
 	return exists
 }
 
+// AcceptedNames returns the names a factory accepts in Get: the registered
+// calculators plus, for factories that support them, their aliases.
+//
+// Parameters:
+//   - factory: The calculator factory to inspect.
+//
+// Returns:
+//   - []string: A sorted slice of accepted names.
+func AcceptedNames(factory CalculatorFactory) []string {
+	names := factory.List()
+	if af, ok := factory.(interface{ Aliases() map[string]string }); ok {
+		for alias := range af.Aliases() {
+			names = append(names, alias)
+		}
+		sort.Strings(names)
+	}
+	return names
+}
+
+// DeprecationNotice returns the deprecation notice for a calculator name,
+// for factories that support deprecation.
+//
+// Parameters:
+//   - factory: The calculator factory to query.
+//   - name: The calculator name or alias.
+//
+// Returns:
+//   - string: The deprecation notice, if any.
+//   - bool: true if the name is deprecated.
+func DeprecationNotice(factory CalculatorFactory, name string) (string, bool) {
+	if df, ok := factory.(interface {
+		DeprecationNotice(string) (string, bool)
+	}); ok {
+		return df.DeprecationNotice(name)
+	}
+	return "", false
+}
+
 // globalFactory is the default global factory instance.
 var globalFactory = NewDefaultFactory()
 
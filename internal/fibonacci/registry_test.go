@@ -157,3 +157,155 @@ func TestGlobalFactory(t *testing.T) {
 		t.Error("Global factory should have 'global_test' calculator")
 	}
 }
+
+func TestDefaultFactory_Aliases(t *testing.T) {
+	t.Parallel()
+	factory := NewDefaultFactory()
+
+	fast, err := factory.Get("fast")
+	if err != nil {
+		t.Fatalf("Get(fast) failed: %v", err)
+	}
+	for _, alias := range []string{"fd", "fast-doubling"} {
+		calc, err := factory.Get(alias)
+		if err != nil {
+			t.Fatalf("Get(%s) failed: %v", alias, err)
+		}
+		if calc != fast {
+			t.Errorf("Get(%s) should return the cached 'fast' instance", alias)
+		}
+		if !factory.Has(alias) {
+			t.Errorf("Has(%s) should be true", alias)
+		}
+	}
+
+	if err := factory.RegisterAlias("fast", "matrix"); err == nil {
+		t.Error("RegisterAlias should reject aliases that clash with a calculator")
+	}
+	if err := factory.RegisterAlias("nope", "missing"); err == nil {
+		t.Error("RegisterAlias should reject unknown targets")
+	}
+
+	// Registering a calculator under an alias name shadows the alias.
+	if err := factory.Register("mat", func() coreCalculator { return &mockCoreCalculator{} }); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if _, ok := factory.Aliases()["mat"]; ok {
+		t.Error("Register should remove the shadowed alias")
+	}
+
+	names := AcceptedNames(factory)
+	want := map[string]bool{"fast": true, "fd": true, "fast-doubling": true, "fft": true, "lowmem": true, "matrix": true, "mat": true}
+	if len(names) != len(want) {
+		t.Fatalf("AcceptedNames = %v, want %d names", names, len(want))
+	}
+	for _, name := range names {
+		if !want[name] {
+			t.Errorf("unexpected accepted name %q", name)
+		}
+	}
+}
+
+func TestDefaultFactory_RegisterRejectsReservedNames(t *testing.T) {
+	t.Parallel()
+	factory := NewDefaultFactory()
+	for _, name := range []string{"", AutoAlgorithm, "all"} {
+		if err := factory.Register(name, func() coreCalculator { return &mockCoreCalculator{} }); err == nil {
+			t.Errorf("Register(%q) should fail", name)
+		}
+	}
+	if err := factory.Register("nil", nil); err == nil {
+		t.Error("Register should reject a nil creator")
+	}
+}
+
+func TestDefaultFactory_Deprecation(t *testing.T) {
+	t.Parallel()
+	factory := NewDefaultFactory()
+	factory.Deprecate("fd", "use 'fast' instead")
+
+	notice, ok := DeprecationNotice(factory, "fd")
+	if !ok || notice != "use 'fast' instead" {
+		t.Errorf("DeprecationNotice(fd) = %q, %v", notice, ok)
+	}
+	if _, ok := DeprecationNotice(factory, "fast"); ok {
+		t.Error("'fast' should not be deprecated")
+	}
+	if _, ok := DeprecationNotice(NewTestFactory(nil), "fd"); ok {
+		t.Error("factories without deprecation support should report none")
+	}
+
+	// Deprecated names keep resolving.
+	canonical, err := factory.Resolve("fd")
+	if err != nil || canonical != "fast" {
+		t.Errorf("Resolve(fd) = %q, %v; want fast", canonical, err)
+	}
+}
+
+func TestDefaultFactory_SelectionPolicy(t *testing.T) {
+	t.Parallel()
+	factory := NewDefaultFactory()
+
+	calc, err := factory.Select(1_000)
+	if err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	if calc != factory.MustGet("fast") {
+		t.Error("default policy should select 'fast'")
+	}
+
+	factory.SetSelectionPolicy(func(n uint64, available []string) string {
+		if n > 1_000_000 {
+			return "fft"
+		}
+		return "matrix"
+	})
+	if calc, _ := factory.Select(10); calc != factory.MustGet("matrix") {
+		t.Error("custom policy should select 'matrix' for small n")
+	}
+	if calc, _ := factory.Select(10_000_000); calc != factory.MustGet("fft") {
+		t.Error("custom policy should select 'fft' for large n")
+	}
+
+	factory.SetSelectionPolicy(func(uint64, []string) string { return "missing" })
+	if _, err := factory.Select(10); err == nil {
+		t.Error("Select should fail when the policy picks an unknown calculator")
+	}
+}
+
+// TestDefaultFactory_ConcurrentGetRegister exercises Get, Register and
+// RegisterAlias from many goroutines; run with -race to detect data races.
+func TestDefaultFactory_ConcurrentGetRegister(t *testing.T) {
+	t.Parallel()
+	factory := NewDefaultFactory()
+	const goroutines = 16
+
+	var wg sync.WaitGroup
+	wg.Add(goroutines * 2)
+	for i := range goroutines {
+		go func(idx int) {
+			defer wg.Done()
+			name := "plugin"
+			if idx%2 == 0 {
+				name = "fd"
+			}
+			_, _ = factory.Get(name)
+			_ = factory.List()
+			_ = factory.Has("plugin-alias")
+		}(i)
+		go func() {
+			defer wg.Done()
+			_ = factory.Register("plugin", func() coreCalculator { return &mockCoreCalculator{} })
+			_ = factory.RegisterAlias("plugin-alias", "plugin")
+		}()
+	}
+	wg.Wait()
+
+	calc, err := factory.Get("plugin-alias")
+	if err != nil {
+		t.Fatalf("Get(plugin-alias) failed: %v", err)
+	}
+	if calc.Name() != "mock" {
+		t.Errorf("plugin-alias resolved to %q, want mock", calc.Name())
+	}
+}
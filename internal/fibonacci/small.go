@@ -0,0 +1,49 @@
+package fibonacci
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// MaxFibUint128 is the largest n for which F(n) fits in 128 bits:
+// F(186) < 2^128 <= F(187). Up to this index, Calculate serves results from
+// a table of 128-bit values, cheaper to convert than the embedded table
+// (see MaxTableN).
+const MaxFibUint128 = 186
+
+// uint128 is an unsigned 128-bit integer.
+type uint128 struct {
+	hi, lo uint64
+}
+
+// add returns a + b, wrapping around on overflow.
+func (a uint128) add(b uint128) uint128 {
+	lo, carry := bits.Add64(a.lo, b.lo, 0)
+	hi, _ := bits.Add64(a.hi, b.hi, carry)
+	return uint128{hi: hi, lo: lo}
+}
+
+// bigInt converts u to a newly allocated big.Int.
+func (u uint128) bigInt() *big.Int {
+	if bits.UintSize == 64 {
+		return new(big.Int).SetBits([]big.Word{big.Word(u.lo), big.Word(u.hi)})
+	}
+	return new(big.Int).SetBits([]big.Word{
+		big.Word(u.lo), big.Word(u.lo >> 32), big.Word(u.hi), big.Word(u.hi >> 32),
+	})
+}
+
+// smallFibs holds F(0) to F(MaxFibUint128).
+var smallFibs = func() (t [MaxFibUint128 + 1]uint128) {
+	t[1] = uint128{lo: 1}
+	for i := 2; i < len(t); i++ {
+		t[i] = t[i-1].add(t[i-2])
+	}
+	return t
+}()
+
+// calculateSmall returns F(n) for n <= MaxFibUint128 from the 128-bit
+// table. Only the result is a big.Int, so it takes nanoseconds.
+func calculateSmall(n uint64) *big.Int {
+	return smallFibs[n].bigInt()
+}
@@ -0,0 +1,60 @@
+package fibonacci
+
+import (
+	"context"
+	"math/big"
+	"testing"
+)
+
+// TestCalculateSmall checks the 128-bit table against big.Int addition and
+// that it stops at the last Fibonacci number below 2^128.
+func TestCalculateSmall(t *testing.T) {
+	t.Parallel()
+	a, b := big.NewInt(0), big.NewInt(1)
+	for n := uint64(0); n <= MaxFibUint128; n++ {
+		if got := calculateSmall(n); got.Cmp(a) != 0 {
+			t.Fatalf("calculateSmall(%d) = %s, want %s", n, got, a)
+		}
+		a.Add(a, b)
+		a, b = b, a
+	}
+	limit := new(big.Int).Lsh(big.NewInt(1), 128)
+	if calculateSmall(MaxFibUint128).Cmp(limit) >= 0 || a.Cmp(limit) < 0 {
+		t.Errorf("F(%d) should be the largest Fibonacci number below 2^128", MaxFibUint128)
+	}
+}
+
+// TestCalculateSmallFastPath verifies that calculators serve n up to
+// MaxTableN from the tables, reporting completion, and compute larger n.
+func TestCalculateSmallFastPath(t *testing.T) {
+	t.Parallel()
+	ref := NewCalculator(&MatrixExponentiation{})
+	calc := NewCalculator(&OptimizedFastDoubling{})
+	for _, n := range []uint64{MaxFibUint64, MaxFibUint64 + 1, MaxFibUint128, MaxFibUint128 + 1, MaxTableN, MaxTableN + 1} {
+		progress := make(chan ProgressUpdate, 16)
+		got, err := calc.Calculate(context.Background(), progress, 0, n, Options{})
+		if err != nil {
+			t.Fatalf("Calculate(%d): %v", n, err)
+		}
+		want, _ := ref.Calculate(context.Background(), nil, 0, n, Options{DisableTables: true})
+		if got.Cmp(want) != 0 {
+			t.Errorf("Calculate(%d) = %s, want %s", n, got, want)
+		}
+		if n <= MaxTableN {
+			if len(progress) != 1 || (<-progress).Value != 1.0 {
+				t.Errorf("Calculate(%d) should report completion once", n)
+			}
+		}
+	}
+}
+
+func BenchmarkCalculateSmall(b *testing.B) {
+	calc := NewCalculator(&OptimizedFastDoubling{})
+	ctx := context.Background()
+	b.ReportAllocs()
+	for i := 0; b.Loop(); i++ {
+		if _, err := calc.Calculate(ctx, nil, 0, uint64(i%(MaxFibUint128+1)), Options{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
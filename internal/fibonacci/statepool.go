@@ -0,0 +1,189 @@
+package fibonacci
+
+import (
+	"cmp"
+	"math/big"
+	"math/bits"
+	"slices"
+	"sync"
+
+	"github.com/agbru/fibcalc/internal/fibonacci/memory"
+)
+
+// StatePool keeps the working states of the doubling calculators between
+// the calculations of a session, such as the restarts of the TUI. The
+// package pool drops states above MaxPooledBitLen and is emptied by the
+// garbage collector, so a restart for a large n reallocates every buffer;
+// a StatePool holds on to the largest states, within a byte budget, until
+// the session ends.
+//
+// A nil *StatePool uses the package pool. A StatePool is safe for
+// concurrent use.
+type StatePool struct {
+	mu       sync.Mutex
+	maxBytes int64
+	bytes    int64
+	states   []*CalculationState // by increasing size
+
+	hits, misses int64
+}
+
+// StatePoolStats summarizes the use of a StatePool.
+type StatePoolStats struct {
+	// States is the number of states kept.
+	States int
+	// Bytes is the size of their buffers.
+	Bytes int64
+	// Hits counts the calculations that reused a kept state.
+	Hits int64
+	// Misses counts the calculations that found none.
+	Misses int64
+}
+
+// NewStatePool returns a pool keeping states of up to maxBytes in total.
+//
+// Parameters:
+//   - maxBytes: The budget of the kept buffers; 0 or less disables the pool
+//     (NewStatePool returns nil).
+//
+// Returns:
+//   - *StatePool: The pool, or nil if disabled.
+func NewStatePool(maxBytes int64) *StatePool {
+	if maxBytes <= 0 {
+		return nil
+	}
+	return &StatePool{maxBytes: maxBytes}
+}
+
+// Stats returns the current use of the pool.
+//
+// Returns:
+//   - StatePoolStats: The counts; zero for a nil pool.
+func (p *StatePool) Stats() StatePoolStats {
+	if p == nil {
+		return StatePoolStats{}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return StatePoolStats{States: len(p.states), Bytes: p.bytes, Hits: p.hits, Misses: p.misses}
+}
+
+// acquire returns a reset state with buffers sized for F(n): the smallest
+// kept state large enough for it, else the largest kept one, grown, else
+// one from the package pool. A state is large enough when all its buffers
+// but one are: the calculation hands the buffer of F(n) to its caller and
+// replaces it with an empty one. The buffers of a nil pool's states come from a
+// single arena block; a pool sizes them one by one instead, as a state
+// kept in it would otherwise hold on to the whole block.
+func (p *StatePool) acquire(n uint64) *CalculationState {
+	if p == nil {
+		s := AcquireState()
+		presizeState(s, n)
+		return s
+	}
+	words := stateWords(n)
+	need := 4 * int64(words) * bits.UintSize / 8
+	p.mu.Lock()
+	i := slices.IndexFunc(p.states, func(s *CalculationState) bool {
+		return stateBytes(s) >= need
+	})
+	if i < 0 {
+		i = len(p.states) - 1
+	}
+	var s *CalculationState
+	if i >= 0 {
+		p.hits++
+		s = p.states[i]
+		p.states = slices.Delete(p.states, i, i+1)
+		p.bytes -= stateBytes(s)
+	} else {
+		p.misses++
+	}
+	p.mu.Unlock()
+
+	if s == nil {
+		s = AcquireState()
+	}
+	for _, z := range []*big.Int{s.FK, s.FK1, s.T1, s.T2, s.T3} {
+		preSizeBigInt(z, words)
+	}
+	s.Reset()
+	return s
+}
+
+// release keeps s for the next calculations, evicting the smallest states
+// to stay within the budget. States that do not fit go to the package pool.
+func (p *StatePool) release(s *CalculationState) {
+	if p == nil {
+		ReleaseState(s)
+		return
+	}
+	size := stateBytes(s)
+	if size > p.maxBytes {
+		ReleaseState(s)
+		return
+	}
+	s.stepProgress = nil
+
+	p.mu.Lock()
+	i, _ := slices.BinarySearchFunc(p.states, size, func(k *CalculationState, size int64) int {
+		return cmp.Compare(stateBytes(k), size)
+	})
+	p.states = slices.Insert(p.states, i, s)
+	p.bytes += size
+	var evicted []*CalculationState
+	for p.bytes > p.maxBytes {
+		evicted = append(evicted, p.states[0])
+		p.bytes -= stateBytes(p.states[0])
+		p.states = p.states[1:]
+	}
+	p.mu.Unlock()
+
+	for _, e := range evicted {
+		ReleaseState(e)
+	}
+}
+
+// stateWords returns the capacity in words the buffers of a state need for
+// F(n), or 0 for the n computed without pre-sizing.
+func stateWords(n uint64) int {
+	if n <= 1000 {
+		return 0
+	}
+	estimatedBits := int(float64(n) * FibonacciGrowthFactor)
+	return (estimatedBits + 63) / 64
+}
+
+// minStateCap returns the capacity in words of the smallest buffer of s.
+func minStateCap(s *CalculationState) int {
+	return min(cap(s.FK.Bits()), cap(s.FK1.Bits()), cap(s.T1.Bits()), cap(s.T2.Bits()), cap(s.T3.Bits()))
+}
+
+// stateBytes returns the size of the buffers of s.
+func stateBytes(s *CalculationState) int64 {
+	words := 0
+	for _, z := range []*big.Int{s.FK, s.FK1, s.T1, s.T2, s.T3} {
+		words += cap(z.Bits())
+	}
+	return int64(words) * bits.UintSize / 8
+}
+
+// presizeState gives the buffers of s the capacity of F(n) from a single
+// arena block, unless they already have it.
+func presizeState(s *CalculationState, n uint64) {
+	words := stateWords(n)
+	if words == 0 || minStateCap(s) >= words {
+		return
+	}
+	// Create arena for contiguous memory allocation.
+	// Pre-size all big.Int buffers from the arena to avoid per-buffer
+	// GC tracking and reduce memory fragmentation.
+	arena := memory.NewCalculationArena(n)
+	arena.PreSizeFromArena(s.FK, words)
+	arena.PreSizeFromArena(s.FK1, words)
+	s.FK.SetInt64(0)
+	s.FK1.SetInt64(1)
+	arena.PreSizeFromArena(s.T1, words)
+	arena.PreSizeFromArena(s.T2, words)
+	arena.PreSizeFromArena(s.T3, words)
+}
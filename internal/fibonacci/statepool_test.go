@@ -0,0 +1,56 @@
+package fibonacci
+
+import (
+	"context"
+	"math/big"
+	"testing"
+)
+
+func TestStatePoolReusesStates(t *testing.T) {
+	t.Parallel()
+	const n = 200_000
+	want, err := NewCalculator(&OptimizedFastDoubling{}).Calculate(context.Background(), nil, 0, n, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pool := NewStatePool(1 << 30)
+	opts := Options{StatePool: pool}
+	for _, core := range []coreCalculator{&OptimizedFastDoubling{}, &FFTBasedCalculator{}, &OptimizedFastDoubling{}} {
+		got, err := NewCalculator(core).Calculate(context.Background(), nil, 0, n, opts)
+		if err != nil {
+			t.Fatalf("%s: %v", core.Name(), err)
+		}
+		if got.Cmp(want) != 0 {
+			t.Fatalf("%s: result differs with a state pool", core.Name())
+		}
+	}
+	stats := pool.Stats()
+	if stats.Hits != 2 || stats.Misses != 1 {
+		t.Errorf("hits/misses = %d/%d, want 2/1", stats.Hits, stats.Misses)
+	}
+	if stats.States != 1 || stats.Bytes < 4*int64(stateWords(n))*8 {
+		t.Errorf("kept %d states of %d bytes, want one sized for F(%d)", stats.States, stats.Bytes, n)
+	}
+}
+
+func TestStatePoolBudget(t *testing.T) {
+	t.Parallel()
+	sized := func(words int) *CalculationState {
+		s := &CalculationState{FK: new(big.Int), FK1: new(big.Int), T1: new(big.Int), T2: new(big.Int), T3: new(big.Int)}
+		preSizeBigInt(s.T1, words)
+		return s
+	}
+	small, large := sized(100), sized(1000)
+	pool := NewStatePool(stateBytes(large) + stateBytes(small)/2)
+	pool.release(small)
+	pool.release(large)
+	pool.release(sized(1 << 20)) // over the budget alone
+
+	if stats := pool.Stats(); stats.States != 1 || stats.Bytes != stateBytes(large) {
+		t.Errorf("kept %d states of %d bytes, want the largest one (%d bytes)", stats.States, stats.Bytes, stateBytes(large))
+	}
+	if NewStatePool(0) != nil {
+		t.Error("NewStatePool(0) should disable the pool")
+	}
+}
@@ -0,0 +1,42 @@
+package fibonacci
+
+import "math/big"
+
+// StepKind identifies the operation of a StepEvent.
+type StepKind int
+
+const (
+	// StepDoubling is a fast doubling step: F(k), F(k+1) become F(2k),
+	// F(2k+1).
+	StepDoubling StepKind = iota
+	// StepAddition is the fast doubling step of a 1 bit of n: F(k), F(k+1)
+	// become F(k+1), F(k+2).
+	StepAddition
+	// StepMatrixMultiply multiplies the result matrix by the current power
+	// of Q, for a 1 bit of the exponent.
+	StepMatrixMultiply
+	// StepMatrixSquare squares the current power of Q.
+	StepMatrixSquare
+)
+
+// StepEvent describes a step of the fast doubling or matrix loop, for
+// Options.OnStep.
+type StepEvent struct {
+	// Kind is the operation of the step.
+	Kind StepKind
+	// Bit is the bit being processed, of n for fast doubling and of n-1
+	// for the matrix loop; Bits is the number of bits.
+	Bit  int
+	Bits int
+	// K is the index reached: F(K) and F(K+1) after a doubling or addition
+	// step, the exponent of the updated matrix Q^K after a matrix step.
+	K uint64
+	// Values are the state after the step: F(K) and F(K+1), or the
+	// entries a, b, c, d of the updated matrix. They belong to the
+	// calculation and are only valid during the callback.
+	Values []*big.Int
+}
+
+// StepHook receives the steps of a calculation. It is called on the
+// calculation's goroutine, which it holds up, so it must return quickly.
+type StepHook func(StepEvent)
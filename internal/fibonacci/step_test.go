@@ -0,0 +1,75 @@
+package fibonacci
+
+import (
+	"context"
+	"math/big"
+	"testing"
+)
+
+// fibRef returns F(k) by iteration.
+func fibRef(k uint64) *big.Int {
+	a, b := big.NewInt(0), big.NewInt(1)
+	for ; k > 0; k-- {
+		a.Add(a, b)
+		a, b = b, a
+	}
+	return a
+}
+
+func TestOnStepDoubling(t *testing.T) {
+	t.Parallel()
+	const n = 45 // 101101
+	for _, name := range []string{"fast", "fft"} {
+		calc, err := NewDefaultFactory().Get(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var kinds []StepKind
+		var last uint64
+		opts := Options{DisableTables: true, OnStep: func(e StepEvent) {
+			kinds = append(kinds, e.Kind)
+			last = e.K
+			if e.Values[0].Cmp(fibRef(e.K)) != 0 || e.Values[1].Cmp(fibRef(e.K+1)) != 0 {
+				t.Errorf("%s: step %d to K=%d has F(K), F(K+1) = %v, %v", name, e.Kind, e.K, e.Values[0], e.Values[1])
+			}
+		}}
+		if _, err := calc.Calculate(context.Background(), nil, 0, n, opts); err != nil {
+			t.Fatalf("%s: Calculate() error = %v", name, err)
+		}
+		// One doubling per bit, one addition per 1 bit
+		want := []StepKind{StepDoubling, StepAddition, StepDoubling, StepDoubling, StepAddition, StepDoubling, StepAddition, StepDoubling, StepDoubling, StepAddition}
+		if len(kinds) != len(want) || last != n {
+			t.Errorf("%s: steps %v ending at K=%d, want %v ending at %d", name, kinds, last, want, n)
+		}
+	}
+}
+
+func TestOnStepMatrix(t *testing.T) {
+	t.Parallel()
+	const n = 13 // Q^12, 12 = 1100
+	calc, err := NewDefaultFactory().Get("matrix")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var multiplied, squared []uint64
+	opts := Options{DisableTables: true, OnStep: func(e StepEvent) {
+		// Q^K = [[F(K+1) F(K)] [F(K) F(K-1)]]
+		if e.Values[0].Cmp(fibRef(e.K+1)) != 0 || e.Values[1].Cmp(fibRef(e.K)) != 0 || e.Values[3].Cmp(fibRef(e.K-1)) != 0 {
+			t.Errorf("step %d to Q^%d has entries %v", e.Kind, e.K, e.Values)
+		}
+		if e.Kind == StepMatrixMultiply {
+			multiplied = append(multiplied, e.K)
+		} else {
+			squared = append(squared, e.K)
+		}
+	}}
+	if _, err := calc.Calculate(context.Background(), nil, 0, n, opts); err != nil {
+		t.Fatalf("Calculate() error = %v", err)
+	}
+	if len(multiplied) != 2 || multiplied[0] != 4 || multiplied[1] != 12 {
+		t.Errorf("result exponents %v, want [4 12]", multiplied)
+	}
+	if len(squared) != 3 || squared[2] != 8 {
+		t.Errorf("squared powers %v, want [2 4 8]", squared)
+	}
+}
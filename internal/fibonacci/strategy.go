@@ -83,9 +83,9 @@ type DoublingStepExecutor interface {
 	ExecuteStep(ctx context.Context, s *CalculationState, opts Options, inParallel bool) error
 }
 
-// AdaptiveStrategy uses smartMultiply and smartSquare to adaptively choose
-// between math/big and FFT-based multiplication based on operand sizes
-// and thresholds.
+// AdaptiveStrategy delegates to the multiplication backend selected by the
+// options: by default math/big below the FFT threshold and FFT above it, or
+// opts.Backend when one is injected.
 type AdaptiveStrategy struct{}
 
 // Name returns the name of the adaptive strategy.
@@ -93,21 +93,22 @@ func (s *AdaptiveStrategy) Name() string {
 	return "Adaptive (math/big + FFT)"
 }
 
-// Multiply performs adaptive multiplication using smartMultiply.
+// Multiply performs adaptive multiplication using the selected backend.
 func (s *AdaptiveStrategy) Multiply(z, x, y *big.Int, opts Options) (*big.Int, error) {
-	return smartMultiply(z, x, y, opts.FFTThreshold)
+	return opts.multiplier().MulTo(z, x, y)
 }
 
-// Square performs adaptive squaring using smartSquare.
+// Square performs adaptive squaring using the selected backend.
 func (s *AdaptiveStrategy) Square(z, x *big.Int, opts Options) (*big.Int, error) {
-	return smartSquare(z, x, opts.FFTThreshold)
+	return opts.multiplier().SqrTo(z, x)
 }
 
 // ExecuteStep performs a doubling step, choosing between standard logic
 // and optimized FFT transform reuse based on operand size.
 func (s *AdaptiveStrategy) ExecuteStep(ctx context.Context, state *CalculationState, opts Options, inParallel bool) error {
-	// If operands are large enough for FFT, use specialized reuse logic
-	if opts.FFTThreshold > 0 && state.FK1.BitLen() > opts.FFTThreshold {
+	// If operands are large enough for FFT, use specialized reuse logic,
+	// unless a custom backend was injected and must see every multiplication
+	if opts.Backend == nil && opts.FFTThreshold > 0 && state.FK1.BitLen() > opts.FFTThreshold {
 		return executeDoublingStepFFT(ctx, state, opts, inParallel)
 	}
 	// Fallback to standard doubling step multiplication
@@ -124,18 +125,18 @@ func (s *FFTOnlyStrategy) Name() string {
 	return "FFT-Only"
 }
 
-// Multiply performs FFT-based multiplication using mulFFT.
+// Multiply performs FFT-based multiplication with the calculation's FFT backend.
 func (s *FFTOnlyStrategy) Multiply(z, x, y *big.Int, opts Options) (*big.Int, error) {
-	res, err := mulFFT(x, y)
+	res, err := opts.fftBackend().Mul(x, y)
 	if err != nil {
 		return nil, fmt.Errorf("FFT multiplication failed: %w", err)
 	}
 	return setOrReturn(z, res), nil
 }
 
-// Square performs FFT-based squaring using sqrFFT.
+// Square performs FFT-based squaring with the calculation's FFT backend.
 func (s *FFTOnlyStrategy) Square(z, x *big.Int, opts Options) (*big.Int, error) {
-	res, err := sqrFFT(x)
+	res, err := opts.fftBackend().Sqr(x)
 	if err != nil {
 		return nil, fmt.Errorf("FFT squaring failed: %w", err)
 	}
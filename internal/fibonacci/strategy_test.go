@@ -3,7 +3,10 @@ package fibonacci
 import (
 	"context"
 	"math/big"
+	"sync/atomic"
 	"testing"
+
+	"github.com/agbru/fibcalc/internal/fibonacci/mul"
 )
 
 // TestSetOrReturn tests the setOrReturn helper function.
@@ -347,3 +350,47 @@ func TestKaratsubaStrategy_ExecuteStep(t *testing.T) {
 		}
 	})
 }
+
+// countingBackend is a mul.Multiplier that counts the operations it performs.
+type countingBackend struct {
+	mul.Big
+	calls atomic.Int64
+}
+
+func (c *countingBackend) Name() string { return "counting" }
+
+func (c *countingBackend) MulTo(z, x, y *big.Int) (*big.Int, error) {
+	c.calls.Add(1)
+	return c.Big.MulTo(z, x, y)
+}
+
+func (c *countingBackend) SqrTo(z, x *big.Int) (*big.Int, error) {
+	c.calls.Add(1)
+	return c.Big.SqrTo(z, x)
+}
+
+// TestOptionsBackendInjection verifies that a backend injected through
+// Options is used by the calculators and still yields correct results.
+func TestOptionsBackendInjection(t *testing.T) {
+	t.Parallel()
+	const n = 50_000
+	want, err := NewCalculator(&OptimizedFastDoubling{}).Calculate(context.Background(), nil, 0, n, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, core := range []coreCalculator{&OptimizedFastDoubling{}, &MatrixExponentiation{}} {
+		backend := &countingBackend{}
+		calc := NewCalculator(core)
+		got, err := calc.Calculate(context.Background(), nil, 0, n, Options{Backend: backend})
+		if err != nil {
+			t.Fatalf("%s: %v", calc.Name(), err)
+		}
+		if got.Cmp(want) != 0 {
+			t.Errorf("%s: result mismatch with injected backend", calc.Name())
+		}
+		if backend.calls.Load() == 0 {
+			t.Errorf("%s: injected backend was never called", calc.Name())
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package fibonacci
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+)
+
+//go:generate go run ../../cmd/generate-table -out fibtable.bin
+
+// MaxTableN is the largest n whose F(n) is embedded in the binary. Up to
+// this index, Calculate returns the embedded value unless
+// Options.DisableTables is set.
+const MaxTableN = 1000
+
+// tableMagic starts the embedded table (see cmd/generate-table for the
+// format).
+const tableMagic = "FIBT"
+
+// tableData is F(0)..F(MaxTableN) as generated by cmd/generate-table.
+//
+//go:embed fibtable.bin
+var tableData []byte
+
+// table holds the decoded values, decoded on first use.
+var table struct {
+	once   sync.Once
+	values []*big.Int
+	err    error
+}
+
+// decodeTable decodes the table format written by cmd/generate-table.
+//
+// Parameters:
+//   - data: The encoded table.
+//
+// Returns:
+//   - []*big.Int: The values, F(0) first.
+//   - error: An error if data is not a well-formed table.
+func decodeTable(data []byte) ([]*big.Int, error) {
+	rest, ok := bytes.CutPrefix(data, []byte(tableMagic))
+	if !ok {
+		return nil, errors.New("fibonacci table: bad magic")
+	}
+	count, k := binary.Uvarint(rest)
+	if k <= 0 || count > uint64(len(rest)) {
+		return nil, errors.New("fibonacci table: bad count")
+	}
+	rest = rest[k:]
+	values := make([]*big.Int, 0, count)
+	for i := range count {
+		size, k := binary.Uvarint(rest)
+		if k <= 0 || size > uint64(len(rest)-k) {
+			return nil, fmt.Errorf("fibonacci table: truncated at F(%d)", i)
+		}
+		values = append(values, new(big.Int).SetBytes(rest[k:k+int(size)]))
+		rest = rest[k+int(size):]
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("fibonacci table: trailing data")
+	}
+	return values, nil
+}
+
+// TableValue returns F(n) from the table embedded in the binary. The values
+// were computed independently of the algorithms, so they also serve as
+// built-in reference values to check calculators against.
+//
+// Parameters:
+//   - n: The index, at most MaxTableN.
+//
+// Returns:
+//   - *big.Int: A copy of F(n) that the caller may modify.
+//   - bool: False if n is beyond the table (or the table is unusable).
+func TableValue(n uint64) (*big.Int, bool) {
+	table.once.Do(func() {
+		table.values, table.err = decodeTable(tableData)
+	})
+	if table.err != nil || n >= uint64(len(table.values)) {
+		return nil, false
+	}
+	return new(big.Int).Set(table.values[n]), true
+}
+
+// precomputed returns F(n) from the 128-bit table or, beyond it, from the
+// embedded table.
+//
+// Returns:
+//   - *big.Int: F(n), newly allocated.
+//   - bool: False if n is beyond both tables.
+func precomputed(n uint64) (*big.Int, bool) {
+	if n <= MaxFibUint128 {
+		return calculateSmall(n), true
+	}
+	return TableValue(n)
+}
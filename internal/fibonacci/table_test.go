@@ -0,0 +1,103 @@
+package fibonacci
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// TestTableValue checks the embedded table against big.Int addition.
+func TestTableValue(t *testing.T) {
+	t.Parallel()
+	a, b := big.NewInt(0), big.NewInt(1)
+	for n := uint64(0); n <= MaxTableN; n++ {
+		got, ok := TableValue(n)
+		if !ok || got.Cmp(a) != 0 {
+			t.Fatalf("TableValue(%d) = %v, %v; want %s", n, got, ok, a)
+		}
+		a.Add(a, b)
+		a, b = b, a
+	}
+	if _, ok := TableValue(MaxTableN + 1); ok {
+		t.Errorf("TableValue(%d) should be beyond the table", MaxTableN+1)
+	}
+
+	// Callers get a copy they may modify
+	v, _ := TableValue(500)
+	v.SetInt64(0)
+	if again, _ := TableValue(500); again.Sign() == 0 {
+		t.Error("modifying a returned value changed the table")
+	}
+}
+
+func TestDecodeTableErrors(t *testing.T) {
+	t.Parallel()
+	for name, data := range map[string][]byte{
+		"empty":     nil,
+		"bad magic": []byte("FIBX\x01\x00"),
+		"bad count": []byte("FIBT"),
+		"truncated": []byte("FIBT\x02\x00\x05\x01"),
+		"trailing":  []byte("FIBT\x01\x00\x00"),
+	} {
+		if _, err := decodeTable(data); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	values, err := decodeTable([]byte("FIBT\x02\x00\x01\x01"))
+	if err != nil || len(values) != 2 || values[0].Sign() != 0 || values[1].Int64() != 1 {
+		t.Errorf("decodeTable = %v, %v; want [0 1]", values, err)
+	}
+}
+
+// TestCalculatorsAgainstTable runs every algorithm, bypassing the tables,
+// on each n of the embedded table: the table is the built-in reference for
+// small inputs.
+func TestCalculatorsAgainstTable(t *testing.T) {
+	t.Parallel()
+	step := uint64(1)
+	if testing.Short() {
+		step = 7
+	}
+	calculators := map[string]Calculator{
+		"FastDoubling": NewCalculator(&OptimizedFastDoubling{}),
+		"MatrixExp":    NewCalculator(&MatrixExponentiation{}),
+		"FFTBased":     NewCalculator(&FFTBasedCalculator{}),
+		"LowMemory":    NewCalculator(&LowMemoryFastDoubling{}),
+	}
+	for name, calc := range calculators {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			for n := uint64(0); n <= MaxTableN; n += step {
+				want, _ := TableValue(n)
+				got, err := calc.Calculate(context.Background(), nil, 0, n, Options{DisableTables: true})
+				if err != nil || got.Cmp(want) != 0 {
+					t.Fatalf("F(%d) = %v, %v; want %s", n, got, err, want)
+				}
+			}
+		})
+	}
+}
+
+// failingCore is a coreCalculator that always fails.
+type failingCore struct{}
+
+func (failingCore) Name() string { return "failing" }
+
+func (failingCore) CalculateCore(context.Context, ProgressCallback, uint64, Options) (*big.Int, error) {
+	return nil, errors.New("core called")
+}
+
+// TestCalculateDisableTables verifies that the tables bypass the algorithm
+// unless DisableTables is set.
+func TestCalculateDisableTables(t *testing.T) {
+	t.Parallel()
+	calc := NewCalculator(failingCore{})
+	want, _ := TableValue(MaxTableN)
+	if got, err := calc.Calculate(context.Background(), nil, 0, MaxTableN, Options{}); err != nil || got.Cmp(want) != 0 {
+		t.Errorf("Calculate(%d) = %v, %v; want the table value", MaxTableN, got, err)
+	}
+	if _, err := calc.Calculate(context.Background(), nil, 0, 10, Options{DisableTables: true}); err == nil {
+		t.Error("with DisableTables the algorithm should run")
+	}
+}
@@ -0,0 +1,135 @@
+package fibtest
+
+import (
+	"context"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/fibonacci"
+)
+
+// Calculator is a fake fibonacci.Calculator. It sends its scripted
+// progress, waits its delay, then returns its result or error. It is safe
+// for concurrent use as long as its fields are not modified during a
+// calculation.
+type Calculator struct {
+	name string
+	// Result is the value returned; Reference(n) if nil and Err is nil.
+	Result *big.Int
+	// Err, if set, is returned instead of a result.
+	Err error
+	// Progress is the sequence of progress values sent during the
+	// calculation. A successful calculation ends with 1.0 if the script
+	// does not.
+	Progress []float64
+	// Delay is the duration of the calculation, spread evenly over the
+	// progress steps. A canceled context ends it early with ctx.Err().
+	Delay time.Duration
+
+	at    []float64 // fraction of Delay at which each step is sent; even if nil
+	calls atomic.Int64
+}
+
+// Verify interface compliance at compile time.
+var _ fibonacci.Calculator = (*Calculator)(nil)
+
+// Fixed returns a calculator that immediately returns result.
+//
+// Parameters:
+//   - name: The name of the calculator.
+//   - result: The value returned; nil returns the true F(n).
+//
+// Returns:
+//   - *Calculator: The fake.
+func Fixed(name string, result *big.Int) *Calculator {
+	return &Calculator{name: name, Result: result}
+}
+
+// Scripted returns a calculator that reports the given progress values,
+// taking delay in total, before returning result.
+//
+// Parameters:
+//   - name: The name of the calculator.
+//   - result: The value returned; nil returns the true F(n).
+//   - delay: The duration of the calculation.
+//   - steps: The progress values, in [0, 1].
+//
+// Returns:
+//   - *Calculator: The fake.
+func Scripted(name string, result *big.Int, delay time.Duration, steps ...float64) *Calculator {
+	return &Calculator{name: name, Result: result, Delay: delay, Progress: steps}
+}
+
+// Failing returns a calculator that fails with err after delay.
+//
+// Parameters:
+//   - name: The name of the calculator.
+//   - err: The error returned.
+//   - delay: How long the calculation runs before failing.
+//
+// Returns:
+//   - *Calculator: The fake.
+func Failing(name string, err error, delay time.Duration) *Calculator {
+	return &Calculator{name: name, Err: err, Delay: delay}
+}
+
+// Name returns the calculator name.
+func (c *Calculator) Name() string {
+	return c.name
+}
+
+// Calls returns how many calculations the calculator ran.
+func (c *Calculator) Calls() int {
+	return int(c.calls.Load())
+}
+
+// Calculate runs the script.
+func (c *Calculator) Calculate(ctx context.Context, progressChan chan<- fibonacci.ProgressUpdate, calcIndex int, n uint64, _ fibonacci.Options) (*big.Int, error) {
+	c.calls.Add(1)
+	steps := len(c.Progress)
+	start := time.Now()
+	send := func(value float64) {
+		if progressChan != nil {
+			progressChan <- fibonacci.ProgressUpdate{CalculatorIndex: calcIndex, Value: value}
+		}
+	}
+
+	for i := 0; i < max(steps, 1); i++ {
+		pause := c.Delay / time.Duration(max(steps, 1))
+		if i < len(c.at) {
+			pause = time.Duration(c.at[i]*float64(c.Delay)) - time.Since(start)
+		}
+		if err := sleep(ctx, pause); err != nil {
+			return nil, err
+		}
+		if i < steps {
+			send(c.Progress[i])
+		}
+	}
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	if steps == 0 || c.Progress[steps-1] != 1.0 {
+		send(1.0)
+	}
+	if c.Result == nil {
+		return Reference(n), nil
+	}
+	return new(big.Int).Set(c.Result), nil
+}
+
+// sleep waits d, or returns ctx.Err() if ctx ends first.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,7 @@
+// Package fibtest provides test doubles for the calculation pipeline:
+// configurable fake calculators (fixed result, scripted progress, delayed
+// error), a progress recorder usable as an observer or a reporter, fixtures
+// replaying the progress stream of captured runs, and helpers comparing
+// results with reference values and golden files. Tests of the
+// orchestration, CLI and TUI use them instead of their own mocks.
+package fibtest
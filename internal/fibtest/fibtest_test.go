@@ -0,0 +1,72 @@
+package fibtest
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/orchestration"
+)
+
+func TestReference(t *testing.T) {
+	t.Parallel()
+	for n, want := range []int64{0, 1, 1, 2, 3, 5, 8, 13, 21, 34, 55} {
+		if got := Reference(uint64(n)); got.Int64() != want {
+			t.Errorf("Reference(%d) = %v, want %d", n, got, want)
+		}
+	}
+}
+
+func TestCalculator_ScriptedThroughOrchestration(t *testing.T) {
+	t.Parallel()
+	calcs := []fibonacci.Calculator{
+		Scripted("Scripted", nil, 10*time.Millisecond, 0.25, 0.5),
+		Fixed("Fixed", big.NewInt(42)),
+	}
+	var rec ProgressRecorder
+	results := orchestration.ExecuteCalculationsWithOptions(context.Background(), calcs, 30, fibonacci.Options{},
+		orchestration.ExecutionOptions{Mode: orchestration.CompareSequential}, &rec, nil)
+
+	AssertFibonacci(t, 30, results[0].Result)
+	if results[1].Result.Int64() != 42 {
+		t.Errorf("Fixed result = %v, want 42", results[1].Result)
+	}
+	if got := rec.Values(0); !slices.Equal(got, []float64{0.25, 0.5, 1}) {
+		t.Errorf("progress of the scripted calculator = %v, want [0.25 0.5 1]", got)
+	}
+	if got := rec.Values(1); !slices.Equal(got, []float64{1}) {
+		t.Errorf("progress of the fixed calculator = %v, want [1]", got)
+	}
+}
+
+func TestCalculator_Failing(t *testing.T) {
+	t.Parallel()
+	boom := errors.New("boom")
+	c := Failing("Failing", boom, 5*time.Millisecond)
+	if _, err := c.Calculate(context.Background(), nil, 0, 10, fibonacci.Options{}); !errors.Is(err, boom) {
+		t.Errorf("Calculate() error = %v, want %v", err, boom)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	slow := Failing("Slow", boom, time.Hour)
+	if _, err := slow.Calculate(ctx, nil, 0, 10, fibonacci.Options{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Calculate() on a canceled context error = %v, want context.Canceled", err)
+	}
+	if c.Calls() != 1 || slow.Calls() != 1 {
+		t.Errorf("Calls() = %d, %d; want 1, 1", c.Calls(), slow.Calls())
+	}
+}
+
+func TestAssertGolden(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.golden")
+	t.Setenv(UpdateGoldenEnv, "1")
+	AssertGolden(t, path, []byte("\x1b[32mF(10) = 55\x1b[0m\n"))
+	t.Setenv(UpdateGoldenEnv, "")
+	AssertGolden(t, path, []byte("F(10) = 55\n"))
+}
@@ -0,0 +1,210 @@
+package fibtest
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/clock"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/progress"
+)
+
+// FixtureVersion is the version of the fixture format written by Save.
+const FixtureVersion = 1
+
+// Fixture is the progress stream of a real run, with its timing normalized:
+// each update carries the fraction of the run elapsed when it was sent, so
+// that a replay can be stretched to any duration and is independent of the
+// machine that captured it. Fixtures are JSON files; the ones captured from
+// this repository's calculators are embedded (see EmbeddedFixture).
+type Fixture struct {
+	// Version is the format version, FixtureVersion.
+	Version int `json:"version"`
+	// N is the index computed by the captured run.
+	N uint64 `json:"n"`
+	// Calculators are the names of the calculators, by index.
+	Calculators []string `json:"calculators"`
+	// Updates are the progress updates, in the order they were sent.
+	Updates []FixtureUpdate `json:"updates"`
+}
+
+// FixtureUpdate is a progress update of a Fixture.
+type FixtureUpdate struct {
+	// At is the fraction of the run elapsed when the update was sent, in
+	// [0, 1] and non-decreasing along the stream.
+	At float64 `json:"at"`
+	// Calculator is the index of the calculator that sent it.
+	Calculator int `json:"calculator"`
+	// Value is the progress reported, in [0, 1].
+	Value float64 `json:"value"`
+}
+
+//go:embed fixtures/*.json
+var embeddedFixtures embed.FS
+
+// EmbeddedFixture returns a fixture shipped with the package, captured from
+// a real run (see fixtures/).
+//
+// Parameters:
+//   - name: The file name without extension, such as "fast-doubling-100m".
+//
+// Returns:
+//   - *Fixture: The fixture.
+//   - error: An error if it does not exist.
+func EmbeddedFixture(name string) (*Fixture, error) {
+	data, err := embeddedFixtures.ReadFile("fixtures/" + name + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("fixture %q: %w", name, err)
+	}
+	return parseFixture(name, data)
+}
+
+// LoadFixture reads and validates a fixture file.
+//
+// Parameters:
+//   - path: The JSON file.
+//
+// Returns:
+//   - *Fixture: The fixture.
+//   - error: An error if the file cannot be read or is not a valid fixture.
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("reading fixture: %w", err)
+	}
+	return parseFixture(path, data)
+}
+
+// parseFixture decodes and validates the fixture named name.
+func parseFixture(name string, data []byte) (*Fixture, error) {
+	var f Fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("fixture %s: %w", name, err)
+	}
+	if err := f.Validate(); err != nil {
+		return nil, fmt.Errorf("fixture %s: %w", name, err)
+	}
+	return &f, nil
+}
+
+// Validate checks the version, the calculator indexes, and that the times
+// and values are fractions with non-decreasing times.
+//
+// Returns:
+//   - error: The first problem found, or nil.
+func (f *Fixture) Validate() error {
+	if f.Version != FixtureVersion {
+		return fmt.Errorf("unsupported version %d (want %d)", f.Version, FixtureVersion)
+	}
+	last := 0.0
+	for i, u := range f.Updates {
+		switch {
+		case u.Calculator < 0 || u.Calculator >= len(f.Calculators):
+			return fmt.Errorf("update %d: calculator %d out of range", i, u.Calculator)
+		case u.At < last || u.At > 1:
+			return fmt.Errorf("update %d: time %g out of order or range", i, u.At)
+		case u.Value < 0 || u.Value > 1:
+			return fmt.Errorf("update %d: value %g out of range", i, u.Value)
+		}
+		last = u.At
+	}
+	return nil
+}
+
+// Save writes the fixture as indented JSON, creating the directory.
+//
+// Parameters:
+//   - path: The JSON file.
+//
+// Returns:
+//   - error: An error if the fixture is invalid or cannot be written.
+func (f *Fixture) Save(path string) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+// Advance replays the fixture on a fake clock: for each update it advances
+// clk to the update's time in a run lasting d, then calls fn. Nothing
+// sleeps, so the clock-dependent output of the code under test (ETA,
+// throughput, elapsed time) is the same on every machine.
+//
+// Parameters:
+//   - clk: The clock of the code under test, at the start of the run.
+//   - d: The duration of the replayed run.
+//   - fn: Called with each update.
+func (f *Fixture) Advance(clk *clock.Fake, d time.Duration, fn func(progress.ProgressUpdate)) {
+	var elapsed time.Duration
+	for _, u := range f.Updates {
+		at := time.Duration(u.At * float64(d))
+		clk.Advance(at - elapsed)
+		elapsed = at
+		fn(progress.ProgressUpdate{CalculatorIndex: u.Calculator, Value: u.Value})
+	}
+}
+
+// Replay sends the updates on ch in real time, as in a run lasting d; with
+// d zero it sends them back to back. It does not close ch.
+//
+// Parameters:
+//   - ctx: Stops the replay when canceled.
+//   - ch: The progress channel of the code under test.
+//   - d: The duration of the replayed run.
+//
+// Returns:
+//   - error: ctx.Err() if the replay was canceled, or nil.
+func (f *Fixture) Replay(ctx context.Context, ch chan<- progress.ProgressUpdate, d time.Duration) error {
+	start := time.Now()
+	for _, u := range f.Updates {
+		if err := sleep(ctx, time.Duration(u.At*float64(d))-time.Since(start)); err != nil {
+			return err
+		}
+		select {
+		case ch <- progress.ProgressUpdate{CalculatorIndex: u.Calculator, Value: u.Value}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// FakeCalculators returns a fake calculator per calculator of the fixture,
+// each sending its own updates at their times in a run lasting d, then
+// returning Reference(n) for the n it is given, which need not be the
+// fixture's. Run together, they reproduce the captured stream up to
+// scheduling.
+//
+// Parameters:
+//   - d: The duration of the replayed run.
+//
+// Returns:
+//   - []fibonacci.Calculator: The calculators, by index.
+func (f *Fixture) FakeCalculators(d time.Duration) []fibonacci.Calculator {
+	fakes := make([]*Calculator, len(f.Calculators))
+	for i, name := range f.Calculators {
+		fakes[i] = &Calculator{name: name, Delay: d}
+	}
+	for _, u := range f.Updates {
+		c := fakes[u.Calculator]
+		c.Progress = append(c.Progress, u.Value)
+		c.at = append(c.at, u.At)
+	}
+	calcs := make([]fibonacci.Calculator, len(fakes))
+	for i, c := range fakes {
+		calcs[i] = c
+	}
+	return calcs
+}
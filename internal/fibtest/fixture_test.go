@@ -0,0 +1,191 @@
+package fibtest
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/clock"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/orchestration"
+	"github.com/agbru/fibcalc/internal/progress"
+)
+
+// captures are the embedded fixtures, recorded from real runs with
+// UPDATE_GOLDEN=1.
+var captures = []struct {
+	name string
+	n    uint64
+	mode orchestration.CompareMode
+	algo []string
+}{
+	{"fast-doubling-100m", 100_000_000, orchestration.CompareSequential, []string{"fast"}},
+	{"compare-20m", 20_000_000, orchestration.CompareParallel, []string{"fast", "matrix", "fft"}},
+}
+
+// TestEmbeddedFixtures validates the embedded fixtures, or recaptures them
+// from real runs with UPDATE_GOLDEN=1.
+func TestEmbeddedFixtures(t *testing.T) {
+	for _, c := range captures {
+		t.Run(c.name, func(t *testing.T) {
+			if os.Getenv(UpdateGoldenEnv) == "1" {
+				// The new file is embedded at the next build
+				capture(t, c.name, c.n, c.mode, c.algo)
+				return
+			}
+			f, err := EmbeddedFixture(c.name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if f.N != c.n || len(f.Calculators) != len(c.algo) || len(f.Updates) < 2*len(c.algo) {
+				t.Errorf("fixture = n %d, %d calculators, %d updates; want n %d, %d calculators and a few updates each",
+					f.N, len(f.Calculators), len(f.Updates), c.n, len(c.algo))
+			}
+		})
+	}
+}
+
+// capture runs the calculators and writes their progress stream to
+// fixtures/name.json.
+func capture(t *testing.T, name string, n uint64, mode orchestration.CompareMode, algos []string) {
+	t.Helper()
+	factory := fibonacci.NewDefaultFactory()
+	var calcs []fibonacci.Calculator
+	var names []string
+	for _, algo := range algos {
+		calc, err := factory.Get(algo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		calcs = append(calcs, calc)
+		names = append(names, calc.Name())
+	}
+	var rec ProgressRecorder
+	rec.Start()
+	results := orchestration.ExecuteCalculationsWithOptions(context.Background(), calcs, n, fibonacci.Options{},
+		orchestration.ExecutionOptions{Mode: mode}, &rec, nil)
+	for _, r := range results {
+		if r.Err != nil {
+			t.Fatalf("%s: %v", r.Name, r.Err)
+		}
+	}
+	if err := rec.Fixture(n, names).Save(filepath.Join("fixtures", name+".json")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFixture_SaveLoad(t *testing.T) {
+	t.Parallel()
+	var rec ProgressRecorder
+	rec.Update(0, 0.5)
+	rec.Update(1, 0.25)
+	rec.Update(0, 1)
+	f := rec.Fixture(42, []string{"A", "B"})
+	if f.Updates[0].At != 0 || f.Updates[2].At != 1 {
+		t.Errorf("times = %v, want normalized from 0 to 1", f.Updates)
+	}
+
+	path := filepath.Join(t.TempDir(), "run.json")
+	if err := f.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadFixture(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.N != 42 || !slices.Equal(loaded.Calculators, f.Calculators) || !slices.Equal(loaded.Updates, f.Updates) {
+		t.Errorf("LoadFixture() = %+v, want %+v", loaded, f)
+	}
+}
+
+func TestFixture_Validate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		f    Fixture
+	}{
+		{"version", Fixture{Version: 2}},
+		{"calculator", Fixture{Version: FixtureVersion, Calculators: []string{"A"}, Updates: []FixtureUpdate{{Calculator: 1}}}},
+		{"order", Fixture{Version: FixtureVersion, Calculators: []string{"A"}, Updates: []FixtureUpdate{{At: 0.5}, {At: 0.2}}}},
+		{"value", Fixture{Version: FixtureVersion, Calculators: []string{"A"}, Updates: []FixtureUpdate{{Value: 1.5}}}},
+	}
+	for _, tt := range tests {
+		if err := tt.f.Validate(); err == nil {
+			t.Errorf("%s: Validate() = nil, want an error", tt.name)
+		}
+	}
+}
+
+func testFixture() *Fixture {
+	return &Fixture{
+		Version:     FixtureVersion,
+		N:           100,
+		Calculators: []string{"A", "B"},
+		Updates: []FixtureUpdate{
+			{At: 0.1, Calculator: 0, Value: 0.2},
+			{At: 0.5, Calculator: 1, Value: 0.5},
+			{At: 1, Calculator: 0, Value: 1},
+			{At: 1, Calculator: 1, Value: 1},
+		},
+	}
+}
+
+func TestFixture_Advance(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clk := clock.NewFake(start)
+	var times []time.Duration
+	testFixture().Advance(clk, 10*time.Second, func(progress.ProgressUpdate) {
+		times = append(times, clk.Since(start))
+	})
+	if want := []time.Duration{time.Second, 5 * time.Second, 10 * time.Second, 10 * time.Second}; !slices.Equal(times, want) {
+		t.Errorf("clock at each update = %v, want %v", times, want)
+	}
+}
+
+func TestFixture_Replay(t *testing.T) {
+	t.Parallel()
+	f := testFixture()
+	ch := make(chan progress.ProgressUpdate, len(f.Updates))
+	if err := f.Replay(context.Background(), ch, 0); err != nil {
+		t.Fatal(err)
+	}
+	close(ch)
+	var got []progress.ProgressUpdate
+	for u := range ch {
+		got = append(got, u)
+	}
+	if len(got) != 4 || got[1] != (progress.ProgressUpdate{CalculatorIndex: 1, Value: 0.5}) {
+		t.Errorf("replayed %v", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := f.Replay(ctx, make(chan progress.ProgressUpdate), time.Hour); err == nil {
+		t.Error("Replay() on a canceled context = nil, want an error")
+	}
+}
+
+func TestFixture_FakeCalculators(t *testing.T) {
+	t.Parallel()
+	calcs := testFixture().FakeCalculators(20 * time.Millisecond)
+	var rec ProgressRecorder
+	results := orchestration.ExecuteCalculationsWithOptions(context.Background(), calcs, 20, fibonacci.Options{},
+		orchestration.ExecutionOptions{Mode: orchestration.CompareParallel}, &rec, nil)
+
+	for _, r := range results {
+		if r.Result.Cmp(big.NewInt(6765)) != 0 {
+			t.Errorf("%s = %v, want 6765", r.Name, r.Result)
+		}
+	}
+	if got := rec.Values(0); !slices.Equal(got, []float64{0.2, 1}) {
+		t.Errorf("progress of A = %v, want [0.2 1]", got)
+	}
+	if got := rec.Values(1); !slices.Equal(got, []float64{0.5, 1}) {
+		t.Errorf("progress of B = %v, want [0.5 1]", got)
+	}
+}
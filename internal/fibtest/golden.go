@@ -0,0 +1,76 @@
+package fibtest
+
+import (
+	"bytes"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/agbru/fibcalc/internal/testutil"
+)
+
+// UpdateGoldenEnv is the environment variable that makes AssertGolden
+// rewrite the golden files with the current output instead of comparing.
+const UpdateGoldenEnv = "UPDATE_GOLDEN"
+
+// Reference returns F(n) computed by plain iteration, independently of the
+// calculators under test. It takes O(n²) time; keep n below a few hundred
+// thousand.
+//
+// Parameters:
+//   - n: The index.
+//
+// Returns:
+//   - *big.Int: F(n).
+func Reference(n uint64) *big.Int {
+	a, b := big.NewInt(0), big.NewInt(1)
+	for range n {
+		a.Add(a, b)
+		a, b = b, a
+	}
+	return a
+}
+
+// AssertFibonacci fails t unless got is F(n).
+//
+// Parameters:
+//   - t: The test.
+//   - n: The index.
+//   - got: The computed value.
+func AssertFibonacci(t testing.TB, n uint64, got *big.Int) {
+	t.Helper()
+	want := Reference(n)
+	if got == nil || got.Cmp(want) != 0 {
+		t.Errorf("F(%d) = %v, want %v", n, got, want)
+	}
+}
+
+// AssertGolden compares got, without ANSI escape sequences, with the golden
+// file at path (usually under testdata). With UPDATE_GOLDEN=1 in the
+// environment it writes got to the file instead.
+//
+// Parameters:
+//   - t: The test.
+//   - path: The golden file.
+//   - got: The output.
+func AssertGolden(t testing.TB, path string, got []byte) {
+	t.Helper()
+	got = []byte(testutil.StripAnsiCodes(string(got)))
+	if os.Getenv(UpdateGoldenEnv) == "1" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		t.Fatalf("reading golden file: %v (run with %s=1 to create it)", err, UpdateGoldenEnv)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s (run with %s=1 to update it)\ngot:\n%s\nwant:\n%s", path, UpdateGoldenEnv, got, want)
+	}
+}
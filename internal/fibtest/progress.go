@@ -0,0 +1,113 @@
+package fibtest
+
+import (
+	"io"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/orchestration"
+	"github.com/agbru/fibcalc/internal/progress"
+)
+
+// ProgressRecorder records progress updates. It can be registered as a
+// progress.ProgressObserver or passed as an orchestration.ProgressReporter,
+// and is safe for concurrent use. It notes when each update arrives, so
+// that a recording can be saved as a Fixture.
+type ProgressRecorder struct {
+	mu      sync.Mutex
+	start   time.Time
+	updates []progress.ProgressUpdate
+	times   []time.Duration // since start, by update
+}
+
+// Verify interface compliance at compile time.
+var (
+	_ progress.ProgressObserver      = (*ProgressRecorder)(nil)
+	_ orchestration.ProgressReporter = (*ProgressRecorder)(nil)
+)
+
+// Update records an update, as an observer.
+func (r *ProgressRecorder) Update(calcIndex int, value float64) {
+	now := time.Now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.start.IsZero() {
+		r.start = now
+	}
+	r.updates = append(r.updates, progress.ProgressUpdate{CalculatorIndex: calcIndex, Value: value})
+	r.times = append(r.times, now.Sub(r.start))
+}
+
+// Start sets the start of the run that Fixture measures times from; by
+// default it is the first update.
+func (r *ProgressRecorder) Start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.start = time.Now()
+}
+
+// DisplayProgress records the updates of progressChan until it is closed,
+// as a reporter.
+func (r *ProgressRecorder) DisplayProgress(wg *sync.WaitGroup, progressChan <-chan progress.ProgressUpdate, _ int, _ io.Writer) {
+	defer wg.Done()
+	for u := range progressChan {
+		r.Update(u.CalculatorIndex, u.Value)
+	}
+}
+
+// Updates returns the recorded updates, in order.
+func (r *ProgressRecorder) Updates() []progress.ProgressUpdate {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.updates)
+}
+
+// Values returns the recorded progress values of one calculator, in order.
+//
+// Parameters:
+//   - calcIndex: The index of the calculator.
+//
+// Returns:
+//   - []float64: Its values.
+func (r *ProgressRecorder) Values(calcIndex int) []float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var values []float64
+	for _, u := range r.updates {
+		if u.CalculatorIndex == calcIndex {
+			values = append(values, u.Value)
+		}
+	}
+	return values
+}
+
+// Fixture returns the recording as a fixture, with the time of each update
+// divided by the time of the last one.
+//
+// Parameters:
+//   - n: The index computed by the recorded run.
+//   - names: The names of the calculators, by index.
+//
+// Returns:
+//   - *Fixture: The fixture.
+func (r *ProgressRecorder) Fixture(n uint64, names []string) *Fixture {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f := &Fixture{Version: FixtureVersion, N: n, Calculators: slices.Clone(names)}
+	var total time.Duration
+	if len(r.times) > 0 {
+		total = r.times[len(r.times)-1]
+	}
+	// Rounded so that the files stay readable and diffable
+	round := func(x float64) float64 { return math.Round(x*1e6) / 1e6 }
+	for i, u := range r.updates {
+		at := 1.0
+		if total > 0 {
+			at = round(float64(r.times[i]) / float64(total))
+		}
+		f.Updates = append(f.Updates, FixtureUpdate{At: at, Calculator: u.CalculatorIndex, Value: round(u.Value)})
+	}
+	return f
+}
@@ -2,13 +2,72 @@ package format
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
-// FormatExecutionDuration formats a time.Duration for display.
-// It shows microseconds for durations less than a millisecond, milliseconds for
-// durations less than a second, and the default string representation otherwise.
-// This approach provides a more human-readable output for short durations.
+// DurationStyle selects how FormatExecutionDuration renders a duration.
+type DurationStyle string
+
+const (
+	// DurationAuto shows microseconds below a millisecond, milliseconds
+	// below a second, and Go's notation with full precision above
+	// ("1.234567891s").
+	DurationAuto DurationStyle = "auto"
+	// DurationCompact rounds to the precision of the smallest unit shown:
+	// "850ms", "1.23s", "2m3.46s", "1h2m3s".
+	DurationCompact DurationStyle = "compact"
+	// DurationVerbose spells the units out: "2 minutes 3.46 seconds".
+	DurationVerbose DurationStyle = "verbose"
+)
+
+// DefaultDurationPrecision is the number of decimals of the compact and
+// verbose styles.
+const DefaultDurationPrecision = 2
+
+// DurationFormat configures FormatExecutionDuration. The zero value is the
+// auto style in English.
+type DurationFormat struct {
+	// Style is the rendering; empty means DurationAuto.
+	Style DurationStyle
+	// Precision is the number of decimals of the smallest unit shown by the
+	// compact and verbose styles, from 0 to 3. Trailing zeros are dropped.
+	Precision int
+	// Locale is "en" (or empty) or "fr", which writes a decimal comma and
+	// French unit names.
+	Locale string
+}
+
+var (
+	durationMu     sync.RWMutex
+	durationFormat DurationFormat
+)
+
+// SetDurationFormat sets the process-wide format of FormatExecutionDuration.
+//
+// Parameters:
+//   - f: The format.
+func SetDurationFormat(f DurationFormat) {
+	durationMu.Lock()
+	durationFormat = f
+	durationMu.Unlock()
+}
+
+// CurrentDurationFormat returns the process-wide duration format.
+//
+// Returns:
+//   - DurationFormat: The format set by SetDurationFormat.
+func CurrentDurationFormat() DurationFormat {
+	durationMu.RLock()
+	defer durationMu.RUnlock()
+	return durationFormat
+}
+
+// FormatExecutionDuration formats a time.Duration for display in the
+// process-wide format (see SetDurationFormat). Every duration shown by the
+// CLI and the TUI goes through it, so that both read the same.
 //
 // Parameters:
 //   - d: The duration to format.
@@ -16,6 +75,87 @@ import (
 // Returns:
 //   - string: A formatted string representing the duration.
 func FormatExecutionDuration(d time.Duration) string {
+	return CurrentDurationFormat().Format(d)
+}
+
+// Format formats d in f.
+//
+// Parameters:
+//   - d: The duration to format.
+//
+// Returns:
+//   - string: A formatted string representing the duration.
+func (f DurationFormat) Format(d time.Duration) string {
+	var s string
+	switch f.Style {
+	case DurationCompact, DurationVerbose:
+		s = f.units(d)
+	default:
+		s = autoDuration(d)
+	}
+	if f.Locale == "fr" {
+		s = strings.ReplaceAll(s, ".", ",")
+	}
+	return s
+}
+
+// DurationUnit is the fixed unit of a machine-readable duration (see
+// FormatDurationIn).
+type DurationUnit string
+
+const (
+	// DurationNanoseconds writes whole nanoseconds.
+	DurationNanoseconds DurationUnit = "ns"
+	// DurationMilliseconds writes milliseconds with a fixed precision.
+	DurationMilliseconds DurationUnit = "ms"
+	// DurationSeconds writes seconds with a fixed precision.
+	DurationSeconds DurationUnit = "s"
+)
+
+// FormatDurationIn formats d as a number of unit, without the unit, for
+// parsers: unlike the display styles, the unit does not change with the
+// magnitude, the decimals are kept even when zero, and the decimal
+// separator is always a point. Nanoseconds have no decimals.
+//
+// Parameters:
+//   - d: The duration to format.
+//   - unit: The unit; anything else than ms and s means nanoseconds.
+//   - precision: The number of decimals of ms and s, from 0 to 3.
+//
+// Returns:
+//   - string: The number, such as "1234.50" for 1.2345s in ms with 2
+//     decimals.
+func FormatDurationIn(d time.Duration, unit DurationUnit, precision int) string {
+	var size time.Duration
+	switch unit {
+	case DurationMilliseconds:
+		size = time.Millisecond
+	case DurationSeconds:
+		size = time.Second
+	default:
+		return strconv.FormatInt(int64(d), 10)
+	}
+	precision = min(max(precision, 0), 3)
+	// Round in integers, so that the decimals are exact
+	step := size
+	for range precision {
+		step /= 10
+	}
+	ticks := int64(d.Round(step) / step)
+	prefix := ""
+	if ticks < 0 {
+		prefix, ticks = "-", -ticks
+	}
+	scale := int64(size / step)
+	s := prefix + strconv.FormatInt(ticks/scale, 10)
+	if precision > 0 {
+		s += fmt.Sprintf(".%0*d", precision, ticks%scale)
+	}
+	return s
+}
+
+// autoDuration renders d in the DurationAuto style.
+func autoDuration(d time.Duration) string {
 	if d < time.Millisecond {
 		return fmt.Sprintf("%d\u00b5s", d.Microseconds())
 	} else if d < time.Second {
@@ -23,3 +163,110 @@ func FormatExecutionDuration(d time.Duration) string {
 	}
 	return d.String()
 }
+
+// durationUnit is a unit of the compact and verbose styles.
+type durationUnit struct {
+	size         time.Duration
+	symbol       string
+	en, enPlural string
+	fr, frPlural string
+}
+
+var (
+	unitHour   = durationUnit{time.Hour, "h", "hour", "hours", "heure", "heures"}
+	unitMinute = durationUnit{time.Minute, "m", "minute", "minutes", "minute", "minutes"}
+	unitSecond = durationUnit{time.Second, "s", "second", "seconds", "seconde", "secondes"}
+	unitMilli  = durationUnit{time.Millisecond, "ms", "millisecond", "milliseconds", "milliseconde", "millisecondes"}
+	unitMicro  = durationUnit{time.Microsecond, "\u00b5s", "microsecond", "microseconds", "microseconde", "microsecondes"}
+	unitNano   = durationUnit{time.Nanosecond, "ns", "nanosecond", "nanoseconds", "nanoseconde", "nanosecondes"}
+)
+
+// units renders d in the compact or verbose style: whole hours and
+// minutes above a minute, then the smallest unit with f.Precision
+// decimals.
+func (f DurationFormat) units(d time.Duration) string {
+	prefix := ""
+	if d < 0 {
+		prefix, d = "-", -d
+	}
+	precision := min(max(f.Precision, 0), 3)
+
+	// Round first, so that 59.999s is written 1m0s rather than 0m60s, and
+	// again if rounding reached the next unit (999.999ms is 1s).
+	last := smallestUnit(d)
+	d = roundTo(d, last, precision)
+	if u := smallestUnit(d); u != last {
+		last = u
+		d = roundTo(d, last, precision)
+	}
+
+	var parts []string
+	if d >= time.Minute {
+		for _, u := range []durationUnit{unitHour, unitMinute} {
+			whole := d / u.size
+			d -= whole * u.size
+			// Compact keeps Go's notation (1h0m5s); verbose skips zeros.
+			if whole > 0 || (len(parts) > 0 && f.Style != DurationVerbose) {
+				parts = append(parts, f.part(float64(whole), strconv.FormatInt(int64(whole), 10), u))
+			}
+		}
+	}
+	amount := float64(d) / float64(last.size)
+	value := strconv.FormatFloat(amount, 'f', precision, 64)
+	if strings.Contains(value, ".") {
+		value = strings.TrimRight(strings.TrimRight(value, "0"), ".")
+	}
+	if d > 0 || len(parts) == 0 || f.Style != DurationVerbose {
+		parts = append(parts, f.part(amount, value, last))
+	}
+
+	sep := ""
+	if f.Style == DurationVerbose {
+		sep = " "
+	}
+	return prefix + strings.Join(parts, sep)
+}
+
+// smallestUnit returns the last unit the compact and verbose styles show
+// for d.
+func smallestUnit(d time.Duration) durationUnit {
+	switch {
+	case d < time.Microsecond:
+		return unitNano
+	case d < time.Millisecond:
+		return unitMicro
+	case d < time.Second:
+		return unitMilli
+	}
+	return unitSecond
+}
+
+// roundTo rounds d to the given decimals of unit u.
+func roundTo(d time.Duration, u durationUnit, decimals int) time.Duration {
+	step := u.size
+	for range decimals {
+		step /= 10
+	}
+	if step <= 0 {
+		return d
+	}
+	return d.Round(step)
+}
+
+// part renders an amount of unit u, written value: "3s" compact, "3
+// seconds" verbose. French uses the singular below 2, English only for 1.
+func (f DurationFormat) part(amount float64, value string, u durationUnit) string {
+	if f.Style != DurationVerbose {
+		return value + u.symbol
+	}
+	name := u.enPlural
+	switch {
+	case f.Locale == "fr" && amount < 2:
+		name = u.fr
+	case f.Locale == "fr":
+		name = u.frPlural
+	case value == "1":
+		name = u.en
+	}
+	return value + " " + name
+}
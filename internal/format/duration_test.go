@@ -0,0 +1,70 @@
+package format
+
+import (
+	"testing"
+	"time"
+)
+
+// TestDurationFormat verifies the styles, precisions and locales.
+func TestDurationFormat(t *testing.T) {
+	t.Parallel()
+	compact := DurationFormat{Style: DurationCompact, Precision: 2}
+	verbose := DurationFormat{Style: DurationVerbose, Precision: 2}
+	frVerbose := DurationFormat{Style: DurationVerbose, Precision: 1, Locale: "fr"}
+	tests := []struct {
+		f        DurationFormat
+		d        time.Duration
+		expected string
+	}{
+		{DurationFormat{}, 1234567891 * time.Nanosecond, "1.234567891s"},
+		{DurationFormat{Locale: "fr"}, 1500 * time.Millisecond, "1,5s"},
+		{compact, 500 * time.Nanosecond, "500ns"},
+		{compact, 12340 * time.Nanosecond, "12.34\u00b5s"},
+		{compact, 850 * time.Millisecond, "850ms"},
+		{compact, 1234567891 * time.Nanosecond, "1.23s"},
+		{compact, 999999 * time.Microsecond, "1s"},
+		{compact, 59999 * time.Millisecond, "1m0s"},
+		{compact, 123456 * time.Millisecond, "2m3.46s"},
+		{compact, time.Hour + 5*time.Second, "1h0m5s"},
+		{DurationFormat{Style: DurationCompact}, 1600 * time.Millisecond, "2s"},
+		{verbose, time.Second, "1 second"},
+		{verbose, 123456 * time.Millisecond, "2 minutes 3.46 seconds"},
+		{verbose, time.Hour + 5*time.Second, "1 hour 5 seconds"},
+		{verbose, 2 * time.Minute, "2 minutes"},
+		{frVerbose, 1500 * time.Millisecond, "1,5 seconde"},
+		{frVerbose, 3*time.Hour + 2*time.Minute, "3 heures 2 minutes"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.f.Format(tt.d); got != tt.expected {
+			t.Errorf("%+v.Format(%v) = %q; want %q", tt.f, tt.d, got, tt.expected)
+		}
+	}
+}
+
+// TestFormatDurationIn verifies the fixed units and precisions.
+func TestFormatDurationIn(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		d         time.Duration
+		unit      DurationUnit
+		precision int
+		expected  string
+	}{
+		{1234567891 * time.Nanosecond, DurationNanoseconds, 2, "1234567891"},
+		{1234567891 * time.Nanosecond, DurationMilliseconds, 2, "1234.57"},
+		{1234567891 * time.Nanosecond, DurationSeconds, 3, "1.235"},
+		{2 * time.Hour, DurationSeconds, 2, "7200.00"},
+		{500 * time.Microsecond, DurationSeconds, 2, "0.00"},
+		{500 * time.Microsecond, DurationMilliseconds, 0, "1"},
+		{1999 * time.Millisecond, DurationSeconds, 0, "2"},
+		{-1500 * time.Millisecond, DurationSeconds, 1, "-1.5"},
+		{42 * time.Millisecond, DurationMilliseconds, 9, "42.000"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatDurationIn(tt.d, tt.unit, tt.precision); got != tt.expected {
+			t.Errorf("FormatDurationIn(%v, %s, %d) = %q; want %q", tt.d, tt.unit, tt.precision, got, tt.expected)
+		}
+	}
+}
@@ -2,8 +2,28 @@ package format
 
 import (
 	"fmt"
+	"math"
 	"strings"
 	"time"
+
+	"github.com/agbru/fibcalc/internal/clock"
+)
+
+// ETA estimation tuning.
+const (
+	// ETAWarmupDuration is the minimum elapsed time before an ETA is reported.
+	// Early progress updates are dominated by small, fast doubling steps and
+	// produce wildly optimistic estimates, so the ETA stays hidden until the
+	// rate has had time to settle.
+	ETAWarmupDuration = time.Second
+	// ETASmoothingFactor is the weight given to each new instantaneous rate
+	// sample in the exponential moving average (0 < alpha <= 1).
+	ETASmoothingFactor = 0.3
+	// ETARangeMinSamples is the number of rate samples required before the
+	// variance is trusted enough to display an ETA range.
+	ETARangeMinSamples = 4
+	// maxETA caps estimates at a reasonable upper bound.
+	maxETA = 24 * time.Hour
 )
 
 // ProgressState encapsulates the aggregated progress of concurrent calculations.
@@ -66,10 +86,13 @@ func (ps *ProgressState) CalculateAverage() float64 {
 // based on the rate of progress.
 type ProgressWithETA struct {
 	*ProgressState
+	clock        clock.Clock
 	startTime    time.Time
 	lastUpdate   time.Time
 	lastProgress float64
 	progressRate float64 // smoothed progress rate (progress per second)
+	rateVariance float64 // exponentially weighted variance of the rate
+	rateSamples  int     // number of instantaneous rate samples observed
 }
 
 // NewProgressWithETA creates a new progress tracker with ETA calculation.
@@ -80,9 +103,24 @@ type ProgressWithETA struct {
 // Returns:
 //   - *ProgressWithETA: A new progress tracker with ETA support.
 func NewProgressWithETA(numCalculators int) *ProgressWithETA {
-	now := time.Now()
+	return NewProgressWithETAClock(numCalculators, clock.Real{})
+}
+
+// NewProgressWithETAClock creates a progress tracker that reads time from
+// clk, so that ETA estimates can be tested deterministically.
+//
+// Parameters:
+//   - numCalculators: The number of calculators being tracked.
+//   - clk: The time source (the wall clock if nil).
+//
+// Returns:
+//   - *ProgressWithETA: A new progress tracker with ETA support.
+func NewProgressWithETAClock(numCalculators int, clk clock.Clock) *ProgressWithETA {
+	clk = clock.Or(clk)
+	now := clk.Now()
 	return &ProgressWithETA{
 		ProgressState: NewProgressState(numCalculators),
+		clock:         clk,
 		startTime:     now,
 		lastUpdate:    now,
 		lastProgress:  0,
@@ -92,7 +130,8 @@ func NewProgressWithETA(numCalculators int) *ProgressWithETA {
 
 // UpdateWithETA updates progress for a specific calculator and calculates ETA.
 // It uses exponential smoothing for the progress rate to provide stable
-// estimates even with variable progress updates.
+// estimates even with variable progress updates. No ETA is reported during
+// the first ETAWarmupDuration of the run.
 //
 // Parameters:
 //   - index: The index of the calculator (0 to numCalculators-1).
@@ -105,7 +144,7 @@ func (p *ProgressWithETA) UpdateWithETA(index int, value float64) (progress floa
 	p.Update(index, value)
 	progress = p.CalculateAverage()
 
-	now := time.Now()
+	now := p.clock.Now()
 	elapsed := now.Sub(p.startTime)
 
 	// Need some elapsed time and progress to make meaningful estimates
@@ -120,56 +159,74 @@ func (p *ProgressWithETA) UpdateWithETA(index int, value float64) (progress floa
 	if timeSinceUpdate > 0.05 { // At least 50ms between updates
 		progressDelta := progress - p.lastProgress
 		if progressDelta > 0 {
-			instantRate := progressDelta / timeSinceUpdate
-
-			// Exponential smoothing: 70% old rate, 30% new rate
-			if p.progressRate > 0 {
-				p.progressRate = 0.7*p.progressRate + 0.3*instantRate
-			} else {
-				// First meaningful rate calculation - use simple estimation
-				p.progressRate = progress / elapsed.Seconds()
-			}
+			p.addRateSample(progressDelta/timeSinceUpdate, progress/elapsed.Seconds())
 		}
 
 		p.lastUpdate = now
 		p.lastProgress = progress
 	}
 
-	// Calculate ETA based on smoothed rate
-	if p.progressRate > 0 && progress < 1.0 {
-		remaining := 1.0 - progress
-		etaSeconds := remaining / p.progressRate
-		eta = time.Duration(etaSeconds * float64(time.Second))
+	return progress, p.etaAt(progress, p.progressRate)
+}
 
-		// Cap ETA at reasonable values
-		if eta > 24*time.Hour {
-			eta = 24 * time.Hour
-		}
+// addRateSample folds an instantaneous rate into the smoothed rate and its
+// exponentially weighted variance. The very first sample uses the average
+// rate since start, which is more stable than a single short interval.
+func (p *ProgressWithETA) addRateSample(instantRate, averageRate float64) {
+	p.rateSamples++
+	if p.progressRate <= 0 {
+		p.progressRate = averageRate
+		return
 	}
+	diff := instantRate - p.progressRate
+	incr := ETASmoothingFactor * diff
+	p.progressRate += incr
+	p.rateVariance = (1 - ETASmoothingFactor) * (p.rateVariance + diff*incr)
+}
 
-	return progress, eta
+// etaAt converts a progress rate into a remaining-time estimate, capped at
+// maxETA. It returns 0 when no estimate can be made, including during the
+// first ETAWarmupDuration of the run, whichever method asks.
+func (p *ProgressWithETA) etaAt(progress, rate float64) time.Duration {
+	if rate <= 0 || progress >= 1.0 || p.clock.Since(p.startTime) < ETAWarmupDuration {
+		return 0
+	}
+	remaining := 1.0 - progress
+	eta := time.Duration(remaining / rate * float64(time.Second))
+	if eta > maxETA || eta < 0 {
+		eta = maxETA
+	}
+	return eta
 }
 
 // GetETA calculates the current ETA without updating progress.
-// Useful for getting an estimate between progress updates.
+// Useful for getting an estimate between progress updates. Like
+// UpdateWithETA, it reports no ETA during the warm-up.
 //
 // Returns:
 //   - eta: The estimated time remaining based on current progress rate.
 func (p *ProgressWithETA) GetETA() time.Duration {
-	progress := p.CalculateAverage()
-	if p.progressRate <= 0 || progress >= 1.0 {
-		return 0
-	}
-
-	remaining := 1.0 - progress
-	etaSeconds := remaining / p.progressRate
-	eta := time.Duration(etaSeconds * float64(time.Second))
+	return p.etaAt(p.CalculateAverage(), p.progressRate)
+}
 
-	if eta > 24*time.Hour {
-		eta = 24 * time.Hour
+// GetETARange returns a confidence range around the current ETA, derived
+// from one standard deviation of the smoothed progress rate. Both bounds are
+// 0 until the warm-up period has elapsed and at least ETARangeMinSamples rate
+// samples have been observed.
+//
+// Returns:
+//   - low: The optimistic estimate (faster rate).
+//   - high: The pessimistic estimate (slower rate).
+func (p *ProgressWithETA) GetETARange() (low, high time.Duration) {
+	if p.rateSamples < ETARangeMinSamples || p.clock.Since(p.startTime) < ETAWarmupDuration {
+		return 0, 0
 	}
-
-	return eta
+	progress := p.CalculateAverage()
+	stddev := math.Sqrt(p.rateVariance)
+	// Never let the pessimistic rate collapse to zero, which would turn
+	// every noisy run into a 24h upper bound.
+	slowRate := math.Max(p.progressRate-stddev, p.progressRate/4)
+	return p.etaAt(progress, p.progressRate+stddev), p.etaAt(progress, slowRate)
 }
 
 // FormatETA formats a duration into a human-readable ETA string.
@@ -210,6 +267,28 @@ func FormatETA(eta time.Duration) string {
 	return fmt.Sprintf("%dh", hours)
 }
 
+// FormatETAWithRange formats an ETA, preferring a range such as "3m–5m" when
+// a confidence interval is available. It falls back to FormatETA when the
+// range is unknown or both bounds render identically.
+//
+// Parameters:
+//   - eta: The point estimate.
+//   - low: The optimistic bound (0 if unknown).
+//   - high: The pessimistic bound (0 if unknown).
+//
+// Returns:
+//   - string: A formatted string like "2m30s" or "2m–3m10s".
+func FormatETAWithRange(eta, low, high time.Duration) string {
+	if eta <= 0 || low <= 0 || high <= low {
+		return FormatETA(eta)
+	}
+	lowStr, highStr := FormatETA(low), FormatETA(high)
+	if lowStr == highStr {
+		return FormatETA(eta)
+	}
+	return lowStr + "\u2013" + highStr
+}
+
 // ProgressBar generates a string representing a textual progress bar.
 //
 // Parameters:
@@ -3,6 +3,8 @@ package format
 import (
 	"testing"
 	"time"
+
+	"github.com/agbru/fibcalc/internal/clock"
 )
 
 // TestNewProgressWithETA verifies proper initialization.
@@ -57,7 +59,8 @@ func TestGetETA(t *testing.T) {
 		t.Errorf("initial ETA = %v, want 0", eta)
 	}
 
-	// Simulate some progress
+	// Simulate some progress, after the warm-up
+	p.startTime = time.Now().Add(-10 * time.Second)
 	p.Update(0, 0.5)
 	p.progressRate = 0.1 // 10% per second
 
@@ -70,6 +73,103 @@ func TestGetETA(t *testing.T) {
 	}
 }
 
+// TestGetETARange verifies the confidence range around the ETA.
+func TestGetETARange(t *testing.T) {
+	t.Parallel()
+	p := NewProgressWithETA(1)
+
+	// Not enough samples: no range
+	if low, high := p.GetETARange(); low != 0 || high != 0 {
+		t.Errorf("initial range = (%v, %v), want (0, 0)", low, high)
+	}
+
+	// Simulate a warmed-up tracker with a noisy rate
+	p.startTime = time.Now().Add(-10 * time.Second)
+	p.Update(0, 0.5)
+	p.progressRate = 0.1
+	p.rateVariance = 0.0004 // stddev 0.02
+	p.rateSamples = ETARangeMinSamples
+
+	low, high := p.GetETARange()
+	eta := p.GetETA()
+	if !(low < eta && eta < high) {
+		t.Errorf("range (%v, %v) should bracket ETA %v", low, high, eta)
+	}
+	// 0.5 remaining at 0.12/s and 0.08/s
+	if low < 4*time.Second || low > 4500*time.Millisecond {
+		t.Errorf("low = %v, want ~4.17s", low)
+	}
+	if high < 6*time.Second || high > 6500*time.Millisecond {
+		t.Errorf("high = %v, want ~6.25s", high)
+	}
+}
+
+// TestUpdateWithETAFakeClock drives the tracker with a fake clock at a
+// steady rate and checks the exact ETA at each step.
+func TestUpdateWithETAFakeClock(t *testing.T) {
+	t.Parallel()
+	clk := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	p := NewProgressWithETAClock(1, clk)
+
+	for step := 1; step <= 5; step++ {
+		clk.Advance(time.Second)
+		progress := float64(step) / 10
+		_, eta := p.UpdateWithETA(0, progress)
+		want := time.Duration(10-step) * time.Second
+		if diff := eta - want; diff < -time.Millisecond || diff > time.Millisecond {
+			t.Errorf("step %d: ETA = %v, want %v", step, eta, want)
+		}
+	}
+
+	// A perfectly steady rate has no variance: the range collapses.
+	low, high := p.GetETARange()
+	if high-low > time.Millisecond {
+		t.Errorf("range = (%v, %v), want it collapsed around %v", low, high, p.GetETA())
+	}
+}
+
+// TestUpdateWithETAWarmup verifies that no ETA is reported during warm-up.
+func TestUpdateWithETAWarmup(t *testing.T) {
+	t.Parallel()
+	p := NewProgressWithETA(1)
+	p.startTime = time.Now().Add(-200 * time.Millisecond)
+	p.lastUpdate = p.startTime
+
+	_, eta := p.UpdateWithETA(0, 0.5)
+	if eta != 0 {
+		t.Errorf("ETA during warm-up = %v, want 0", eta)
+	}
+	if p.progressRate <= 0 {
+		t.Error("rate should still be tracked during warm-up")
+	}
+}
+
+// TestFormatETAWithRange verifies range formatting and its fallbacks.
+func TestFormatETAWithRange(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name      string
+		eta       time.Duration
+		low, high time.Duration
+		expected  string
+	}{
+		{"No ETA", 0, 0, 0, "calculating..."},
+		{"No range", 90 * time.Second, 0, 0, "1m30s"},
+		{"Range", 4 * time.Minute, 3 * time.Minute, 5 * time.Minute, "3m\u20135m"},
+		{"Identical bounds", 10 * time.Second, 10*time.Second + 100*time.Millisecond, 10*time.Second + 200*time.Millisecond, "10s"},
+		{"Inverted bounds", time.Minute, 2 * time.Minute, time.Minute, "1m"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := FormatETAWithRange(tc.eta, tc.low, tc.high); got != tc.expected {
+				t.Errorf("FormatETAWithRange(%v, %v, %v) = %q, want %q", tc.eta, tc.low, tc.high, got, tc.expected)
+			}
+		})
+	}
+}
+
 // TestFormatETA verifies ETA formatting.
 func TestFormatETA(t *testing.T) {
 	t.Parallel()
@@ -205,6 +305,7 @@ func TestProgressWithETAEdgeCases(t *testing.T) {
 func TestETACapping(t *testing.T) {
 	t.Parallel()
 	p := NewProgressWithETA(1)
+	p.startTime = time.Now().Add(-10 * time.Second)
 	p.Update(0, 0.001)         // Very small progress
 	p.progressRate = 0.0000001 // Very slow rate
 
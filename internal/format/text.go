@@ -0,0 +1,26 @@
+// Text shortening shared by the error messages that quote external input.
+
+package format
+
+// Abbreviate shortens s for a message, keeping its first head and last tail
+// runes around "...". It counts runes, not bytes, so that a multi-byte
+// character of a file or a program output is never split.
+//
+// Parameters:
+//   - s: The text to shorten.
+//   - head: The number of leading runes kept.
+//   - tail: The number of trailing runes kept; 0 keeps the start only.
+//
+// Returns:
+//   - string: s if it has at most head+tail runes, else the shortened text.
+func Abbreviate(s string, head, tail int) string {
+	// At most one rune per byte: short strings need no conversion
+	if len(s) <= head+tail {
+		return s
+	}
+	r := []rune(s)
+	if len(r) <= head+tail {
+		return s
+	}
+	return string(r[:head]) + "..." + string(r[len(r)-tail:])
+}
@@ -0,0 +1,28 @@
+package format
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestAbbreviate(t *testing.T) {
+	tests := []struct {
+		s          string
+		head, tail int
+		want       string
+	}{
+		{"12345", 3, 2, "12345"},
+		{"1234567", 3, 2, "123...67"},
+		{"1234567", 3, 0, "123..."},
+		{"éàü", 2, 1, "éàü"}, // 6 bytes but 3 runes
+		{"ééééé", 2, 1, "éé...é"},
+		{strings.Repeat("€", 50), 40, 0, strings.Repeat("€", 40) + "..."},
+	}
+	for _, tt := range tests {
+		got := Abbreviate(tt.s, tt.head, tt.tail)
+		if got != tt.want || !utf8.ValidString(got) {
+			t.Errorf("Abbreviate(%q, %d, %d) = %q, want %q", tt.s, tt.head, tt.tail, got, tt.want)
+		}
+	}
+}
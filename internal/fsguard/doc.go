@@ -0,0 +1,11 @@
+// Package fsguard is the gate through which fibcalc writes files: result
+// files, reports, logs, traces, the history and audit databases, caches,
+// the calibration profile and memory-mapped temporary buffers are all
+// created, written, renamed and removed with its functions instead of those of the
+// os package.
+//
+// --no-write closes the gate with Deny, for restricted or hermetic
+// environments. Every write then fails with ErrDenied, whichever feature
+// attempts it, so the guarantee that fibcalc touches no files does not
+// depend on each feature checking the flag. Reading stays allowed.
+package fsguard
@@ -0,0 +1,99 @@
+package fsguard
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"sync/atomic"
+)
+
+// ErrDenied is the error of the writes attempted once Deny was called.
+var ErrDenied = errors.New("writing files is disabled (--no-write)")
+
+// denied is set while writes are denied.
+var denied atomic.Bool
+
+// Deny makes every write through the package fail with ErrDenied, until
+// the returned function is called.
+//
+// Returns:
+//   - func(): Allows writes again.
+func Deny() (restore func()) {
+	denied.Store(true)
+	return func() { denied.Store(false) }
+}
+
+// Denied reports whether writes are denied.
+func Denied() bool {
+	return denied.Load()
+}
+
+// check returns a *fs.PathError wrapping ErrDenied if writes are denied.
+func check(op, path string) error {
+	if denied.Load() {
+		return &fs.PathError{Op: op, Path: path, Err: ErrDenied}
+	}
+	return nil
+}
+
+// writeFlags are the os.OpenFile flags that write to the file system.
+const writeFlags = os.O_WRONLY | os.O_RDWR | os.O_APPEND | os.O_CREATE | os.O_TRUNC
+
+// OpenFile is os.OpenFile, denied if flag opens name for writing.
+func OpenFile(name string, flag int, perm os.FileMode) (*os.File, error) {
+	if flag&writeFlags != 0 {
+		if err := check("open", name); err != nil {
+			return nil, err
+		}
+	}
+	return os.OpenFile(name, flag, perm)
+}
+
+// Create is os.Create, denied.
+func Create(name string) (*os.File, error) {
+	if err := check("open", name); err != nil {
+		return nil, err
+	}
+	return os.Create(name)
+}
+
+// CreateTemp is os.CreateTemp, denied.
+func CreateTemp(dir, pattern string) (*os.File, error) {
+	if err := check("createtemp", dir); err != nil {
+		return nil, err
+	}
+	return os.CreateTemp(dir, pattern)
+}
+
+// WriteFile is os.WriteFile, denied.
+func WriteFile(name string, data []byte, perm os.FileMode) error {
+	if err := check("open", name); err != nil {
+		return err
+	}
+	return os.WriteFile(name, data, perm)
+}
+
+// MkdirAll is os.MkdirAll, denied.
+func MkdirAll(path string, perm os.FileMode) error {
+	if err := check("mkdir", path); err != nil {
+		return err
+	}
+	return os.MkdirAll(path, perm)
+}
+
+// Remove is os.Remove, denied: deleting a file, such as the oldest of
+// rotated logs, changes the file system as writing one does.
+func Remove(name string) error {
+	if err := check("remove", name); err != nil {
+		return err
+	}
+	return os.Remove(name)
+}
+
+// Rename is os.Rename, denied.
+func Rename(oldpath, newpath string) error {
+	if err := check("rename", newpath); err != nil {
+		return err
+	}
+	return os.Rename(oldpath, newpath)
+}
@@ -0,0 +1,102 @@
+package fsguard
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// The tests deny writes for the whole process, so they do not run in
+// parallel.
+
+func TestDeny(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing.txt")
+	if err := WriteFile(existing, []byte("kept"), 0o600); err != nil {
+		t.Fatalf("WriteFile before Deny: %v", err)
+	}
+
+	restore := Deny()
+	if !Denied() {
+		t.Error("Denied() = false after Deny")
+	}
+	path := filepath.Join(dir, "new.txt")
+	writes := map[string]func() error{
+		"WriteFile": func() error { return WriteFile(path, nil, 0o600) },
+		"Create":    func() error { _, err := Create(path); return err },
+		"CreateTemp": func() error {
+			_, err := CreateTemp(dir, "x-*")
+			return err
+		},
+		"OpenFile": func() error {
+			_, err := OpenFile(existing, os.O_APPEND|os.O_WRONLY, 0o600)
+			return err
+		},
+		"MkdirAll": func() error { return MkdirAll(filepath.Join(dir, "sub"), 0o750) },
+		"Rename":   func() error { return Rename(existing, path) },
+		"Remove":   func() error { return Remove(existing) },
+	}
+	for name, write := range writes {
+		if err := write(); !errors.Is(err, ErrDenied) {
+			t.Errorf("%s with writes denied = %v, want ErrDenied", name, err)
+		}
+	}
+	f, err := OpenFile(existing, os.O_RDONLY, 0)
+	if err != nil {
+		t.Errorf("OpenFile for reading with writes denied: %v", err)
+	} else {
+		f.Close()
+	}
+	restore()
+
+	if Denied() {
+		t.Error("Denied() = true after restore")
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("directory holds %d entries, want only the existing file", len(entries))
+	}
+	if err := WriteFile(path, nil, 0o600); err != nil {
+		t.Errorf("WriteFile after restore: %v", err)
+	}
+}
+
+// directWrite matches the os functions that write or remove files, which fibcalc
+// code must call through this package.
+var directWrite = regexp.MustCompile(`\bos\.(WriteFile|Create|CreateTemp|OpenFile|MkdirAll|Rename|Remove)\(`)
+
+// TestNoDirectWrites checks that the code of the internal packages writes
+// files through fsguard only, so that --no-write cannot be bypassed.
+func TestNoDirectWrites(t *testing.T) {
+	err := filepath.WalkDir("..", func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			// fsguard wraps the os functions; fibtest only runs in tests
+			if name := d.Name(); name == "fsguard" || name == "fibtest" || name == "testutil" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for i, line := range strings.Split(string(src), "\n") {
+			if directWrite.MatchString(line) {
+				t.Errorf("%s:%d writes without fsguard: %s", path, i+1, strings.TrimSpace(line))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
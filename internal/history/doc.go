@@ -0,0 +1,7 @@
+// Package history keeps a local database of completed calculations, one
+// entry per algorithm run, so that performance can be followed over time
+// and across versions with `fibcalc history`. Like the audit log, the
+// database is an append-only JSON Lines file (by default
+// ~/.fibcalc_history.jsonl); it needs no server nor schema migration, and
+// lines from newer versions with unknown fields are still read.
+package history
@@ -0,0 +1,250 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/fsguard"
+)
+
+// DefaultFileName is the name of the history file in the home directory.
+const DefaultFileName = ".fibcalc_history.jsonl"
+
+// maxLineSize bounds the length of a history line when reading; longer
+// lines are not entries written by fibcalc and are skipped.
+const maxLineSize = 1 << 20
+
+// Entry is the summary of one algorithm's run.
+type Entry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Version   string    `json:"version"`
+	N         uint64    `json:"n"`
+	// Algorithm is the registered name of the calculator ("fast", "fft").
+	Algorithm string `json:"algorithm"`
+	// Name is the display name of the calculator.
+	Name       string `json:"name,omitempty"`
+	DurationNs int64  `json:"duration_ns"`
+	ResultBits int    `json:"result_bits,omitempty"`
+	// ErrorKind categorizes a failure; omitted on success.
+	ErrorKind apperrors.ErrorKind `json:"error_kind,omitempty"`
+}
+
+// Duration returns the duration of the run.
+func (e Entry) Duration() time.Duration {
+	return time.Duration(e.DurationNs)
+}
+
+// DefaultPath returns the history file in the user's home directory, or in
+// the working directory if the home directory is unknown.
+func DefaultPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return DefaultFileName
+	}
+	return filepath.Join(home, DefaultFileName)
+}
+
+// appendMu serializes appends within the process.
+var appendMu sync.Mutex
+
+// Append adds entries at the end of the history file, creating it and its
+// directory if needed. Each entry is one JSON line written with O_APPEND,
+// so concurrent fibcalc processes do not interleave within a line.
+//
+// Parameters:
+//   - path: The history file.
+//   - entries: The entries to add.
+//
+// Returns:
+//   - error: An error if an entry cannot be encoded or written.
+func Append(path string, entries []Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	var buf []byte
+	for _, e := range entries {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("failed to encode history entry: %w", err)
+		}
+		buf = append(append(buf, line...), '\n')
+	}
+
+	appendMu.Lock()
+	defer appendMu.Unlock()
+
+	if dir := filepath.Dir(path); dir != "" && dir != "." {
+		if err := fsguard.MkdirAll(dir, 0750); err != nil {
+			return fmt.Errorf("failed to create history directory %q: %w", dir, err)
+		}
+	}
+	file, err := fsguard.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open history %q: %w", path, err)
+	}
+	defer file.Close()
+	if _, err := file.Write(buf); err != nil {
+		return fmt.Errorf("failed to write history %q: %w", path, err)
+	}
+	return nil
+}
+
+// Load reads every entry of the history file. A missing file is an empty
+// history; lines that are not valid entries (a truncated last line after a
+// crash, for instance) are skipped.
+//
+// Parameters:
+//   - path: The history file.
+//
+// Returns:
+//   - []Entry: The entries, oldest first.
+//   - error: An error if the file exists but cannot be read.
+func Load(path string) ([]Entry, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history %q: %w", path, err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 4096), maxLineSize)
+	for scanner.Scan() {
+		var e Entry
+		if json.Unmarshal(scanner.Bytes(), &e) == nil && e.Algorithm != "" {
+			entries = append(entries, e)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history %q: %w", path, err)
+	}
+	return entries, nil
+}
+
+// Sort orders accepted by Query.
+const (
+	// SortTime lists the most recent runs first.
+	SortTime = "time"
+	// SortDuration lists the fastest runs first.
+	SortDuration = "duration"
+	// SortN lists the largest n first.
+	SortN = "n"
+)
+
+// SortOrders lists the accepted values of Query.Sort.
+var SortOrders = []string{SortTime, SortDuration, SortN}
+
+// Query selects and orders history entries. Zero fields do not filter.
+type Query struct {
+	// Since keeps the entries at most this old.
+	Since time.Duration
+	// Algorithm keeps the entries of this calculator.
+	Algorithm string
+	// N keeps the entries for this index.
+	N uint64
+	// Failed keeps the failed runs too; by default only successes are
+	// listed.
+	Failed bool
+	// Sort is one of SortOrders; empty means SortTime.
+	Sort string
+	// Limit keeps the first Limit entries after sorting.
+	Limit int
+}
+
+// Apply returns the entries matching q, in q's order.
+//
+// Parameters:
+//   - entries: The history, as returned by Load.
+//   - now: The current time, for Since.
+//
+// Returns:
+//   - []Entry: The selected entries.
+//   - error: An error if q.Sort is not one of SortOrders.
+func (q Query) Apply(entries []Entry, now time.Time) ([]Entry, error) {
+	var selected []Entry
+	for _, e := range entries {
+		switch {
+		case q.Since > 0 && now.Sub(e.Timestamp) > q.Since,
+			q.Algorithm != "" && e.Algorithm != q.Algorithm,
+			q.N != 0 && e.N != q.N,
+			!q.Failed && e.ErrorKind != apperrors.ErrorKindNone:
+			continue
+		}
+		selected = append(selected, e)
+	}
+
+	var cmp func(a, b Entry) int
+	switch q.Sort {
+	case "", SortTime:
+		cmp = func(a, b Entry) int { return b.Timestamp.Compare(a.Timestamp) }
+	case SortDuration:
+		cmp = func(a, b Entry) int { return compareInt(a.DurationNs, b.DurationNs) }
+	case SortN:
+		cmp = func(a, b Entry) int { return compareInt(b.N, a.N) }
+	default:
+		return nil, fmt.Errorf("unknown sort order %q (accepted values: %s)", q.Sort, strings.Join(SortOrders, ", "))
+	}
+	slices.SortStableFunc(selected, cmp)
+
+	if q.Limit > 0 && len(selected) > q.Limit {
+		selected = selected[:q.Limit]
+	}
+	return selected, nil
+}
+
+// compareInt orders two integers.
+func compareInt[T int64 | uint64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// ParseAge parses a --since value: a Go duration ("36h", "90m") or a
+// whole number of days or weeks ("7d", "2w").
+//
+// Parameters:
+//   - s: The age.
+//
+// Returns:
+//   - time.Duration: The parsed age.
+//   - error: An error if s is not a positive age.
+func ParseAge(s string) (time.Duration, error) {
+	var d time.Duration
+	if unit := strings.TrimLeft(s, "0123456789"); unit == "d" || unit == "w" {
+		count, err := strconv.Atoi(strings.TrimSuffix(s, unit))
+		if err != nil {
+			return 0, fmt.Errorf("invalid age %q", s)
+		}
+		d = time.Duration(count) * 24 * time.Hour
+		if unit == "w" {
+			d *= 7
+		}
+	} else {
+		parsed, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, fmt.Errorf("invalid age %q (use a duration such as 36h, or days and weeks such as 7d, 2w)", s)
+		}
+		d = parsed
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("age must be positive, got %q", s)
+	}
+	return d, nil
+}
@@ -0,0 +1,119 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+)
+
+func TestAppendLoad(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "sub", "history.jsonl")
+	if entries, err := Load(path); err != nil || entries != nil {
+		t.Fatalf("Load() of a missing file = %v, %v; want an empty history", entries, err)
+	}
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	first := []Entry{
+		{Timestamp: now, Version: "v1", N: 1000, Algorithm: "fast", DurationNs: 10},
+		{Timestamp: now, Version: "v1", N: 1000, Algorithm: "fft", DurationNs: 20, ErrorKind: apperrors.ErrorKindTimeout},
+	}
+	if err := Append(path, first); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	// A torn line, as left by a crash, is skipped
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"timestamp":"2026-`)
+	f.WriteString("\n")
+	f.Close()
+	if err := Append(path, []Entry{{Timestamp: now, N: 5, Algorithm: "matrix", DurationNs: 30}}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	entries, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	var algos []string
+	for _, e := range entries {
+		algos = append(algos, e.Algorithm)
+	}
+	if !slices.Equal(algos, []string{"fast", "fft", "matrix"}) {
+		t.Errorf("loaded algorithms = %v, want [fast fft matrix]", algos)
+	}
+	if entries[1].ErrorKind != apperrors.ErrorKindTimeout || !entries[0].Timestamp.Equal(now) {
+		t.Errorf("entries did not round-trip: %+v", entries[:2])
+	}
+}
+
+func TestQueryApply(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	entries := []Entry{
+		{Timestamp: now.Add(-10 * day), N: 100, Algorithm: "fft", DurationNs: 5},
+		{Timestamp: now.Add(-2 * day), N: 200, Algorithm: "fft", DurationNs: 9},
+		{Timestamp: now.Add(-1 * day), N: 300, Algorithm: "fft", DurationNs: 7},
+		{Timestamp: now.Add(-1 * day), N: 300, Algorithm: "fast", DurationNs: 1},
+		{Timestamp: now, N: 300, Algorithm: "fft", DurationNs: 2, ErrorKind: apperrors.ErrorKindCanceled},
+	}
+	durations := func(es []Entry) []int64 {
+		var out []int64
+		for _, e := range es {
+			out = append(out, e.DurationNs)
+		}
+		return out
+	}
+	tests := []struct {
+		name string
+		q    Query
+		want []int64
+	}{
+		{"default is newest successes first", Query{}, []int64{7, 1, 9, 5}},
+		{"since and algorithm", Query{Since: 7 * day, Algorithm: "fft"}, []int64{7, 9}},
+		{"sort by duration", Query{Algorithm: "fft", Sort: SortDuration}, []int64{5, 7, 9}},
+		{"failed included", Query{Failed: true, Limit: 2}, []int64{2, 7}},
+		{"by n, largest first", Query{Sort: SortN, Limit: 3}, []int64{7, 1, 9}},
+		{"one n", Query{N: 200}, []int64{9}},
+	}
+	for _, tt := range tests {
+		got, err := tt.q.Apply(entries, now)
+		if err != nil {
+			t.Errorf("%s: Apply() error = %v", tt.name, err)
+			continue
+		}
+		if !slices.Equal(durations(got), tt.want) {
+			t.Errorf("%s: Apply() = %v, want %v", tt.name, durations(got), tt.want)
+		}
+	}
+	if _, err := (Query{Sort: "speed"}).Apply(entries, now); err == nil {
+		t.Error("Apply() with an unknown sort order should fail")
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	t.Parallel()
+	tests := map[string]time.Duration{
+		"7d":  7 * 24 * time.Hour,
+		"2w":  14 * 24 * time.Hour,
+		"36h": 36 * time.Hour,
+		"90m": 90 * time.Minute,
+	}
+	for in, want := range tests {
+		if got, err := ParseAge(in); err != nil || got != want {
+			t.Errorf("ParseAge(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "d", "0d", "-1h", "7x", "1.5d"} {
+		if _, err := ParseAge(in); err == nil {
+			t.Errorf("ParseAge(%q) should fail", in)
+		}
+	}
+}
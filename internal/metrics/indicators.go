@@ -19,6 +19,10 @@ type Indicators struct {
 	DoublingSteps   uint64  // number of doubling iterations ≈ log₂(n)
 	StepsPerSecond  float64 // doubling steps executed per second
 
+	// Throughput history (set from a ThroughputHistory, 0 when not tracked)
+	RollingBitsPerSecond float64 // bits/s over the last ThroughputWindow
+	PeakBitsPerSecond    float64 // highest rolling bits/s of the run
+
 	// Mathematical (only available after calculation completes)
 	GoldenRatioDeviation float64 // % deviation of actual bitLen vs theoretical n·log₂(φ)
 	DigitalRoot          int     // iterative digit sum until single digit (1-9)
@@ -50,8 +54,7 @@ func ComputeLive(n uint64, progress float64, elapsed time.Duration) *Indicators
 	}
 
 	seconds := elapsed.Seconds()
-	theoreticalBits := float64(n) * log2Phi
-	estimatedBitsProduced := progress * theoreticalBits
+	estimatedBitsProduced := EstimatedBits(n, progress)
 	estimatedDigitsProduced := estimatedBitsProduced * math.Log10(2)
 	doublingSteps := uint64(bits.Len64(n))
 	completedSteps := progress * float64(doublingSteps)
@@ -87,11 +90,7 @@ func Compute(result *big.Int, n uint64, duration time.Duration) *Indicators {
 		IsEven:          n%3 == 0,
 	}
 
-	// Golden ratio deviation: compare actual bitLen to theoretical n·log₂(φ)
-	if n > 1 {
-		theoretical := float64(n) * log2Phi
-		ind.GoldenRatioDeviation = math.Abs(float64(bitLen)-theoretical) / theoretical * 100
-	}
+	ind.GoldenRatioDeviation = goldenRatioDeviation(bitLen, n)
 
 	// Digital root: 1 + ((x - 1) mod 9) for x > 0
 	ind.DigitalRoot = digitalRoot(result)
@@ -102,6 +101,16 @@ func Compute(result *big.Int, n uint64, duration time.Duration) *Indicators {
 	return ind
 }
 
+// goldenRatioDeviation returns the deviation, in percent, of the bit length
+// of F(n) from the theoretical n·log₂(φ), or 0 for n ≤ 1.
+func goldenRatioDeviation(bitLen int, n uint64) float64 {
+	if n <= 1 {
+		return 0
+	}
+	theoretical := float64(n) * log2Phi
+	return math.Abs(float64(bitLen)-theoretical) / theoretical * 100
+}
+
 // digitalRoot computes the digital root of x (repeated digit sum until single digit).
 // For positive integers: digitalRoot(x) = 1 + ((x - 1) mod 9).
 // Uses big.Int.Mod which is efficient on arbitrary-precision integers.
@@ -0,0 +1,271 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Category groups indicators so that they can be selected together.
+type Category string
+
+const (
+	// CategoryPerf is for indicators about the speed of the calculation.
+	CategoryPerf Category = "perf"
+	// CategoryMath is for indicators about the value of F(n).
+	CategoryMath Category = "math"
+)
+
+// Selection keywords accepted by Registry.Select besides indicator names.
+const (
+	// SelectAll selects every registered indicator.
+	SelectAll = "all"
+	// SelectList is not a selection: it asks for the list of indicators.
+	// It is reserved so that no indicator can take the name.
+	SelectList = "list"
+)
+
+// Indicator is one analysis of a completed calculation, shown under
+// "Indicators of interest" in the detailed output. Indicators run after the
+// calculation, so they do not affect the measured time, but they should
+// still avoid converting the whole result to decimal.
+type Indicator interface {
+	// Name identifies the indicator in --indicators.
+	Name() string
+	// Category is the group the indicator belongs to.
+	Category() Category
+	// Label is the short description shown next to the value.
+	Label() string
+	// Compute returns the value of the indicator for F(n) = result,
+	// computed in duration (positive).
+	Compute(result *big.Int, n uint64, duration time.Duration) string
+}
+
+// funcIndicator is an Indicator backed by a function.
+type funcIndicator struct {
+	name     string
+	category Category
+	label    string
+	compute  func(result *big.Int, n uint64, duration time.Duration) string
+}
+
+func (f funcIndicator) Name() string       { return f.name }
+func (f funcIndicator) Category() Category { return f.category }
+func (f funcIndicator) Label() string      { return f.label }
+
+func (f funcIndicator) Compute(result *big.Int, n uint64, duration time.Duration) string {
+	return f.compute(result, n, duration)
+}
+
+// NewIndicator returns an Indicator computed by fn.
+//
+// Parameters:
+//   - name: The identifier used in --indicators.
+//   - category: The group of the indicator.
+//   - label: The description shown next to the value.
+//   - fn: The function computing the value.
+//
+// Returns:
+//   - Indicator: The indicator, ready to be registered.
+func NewIndicator(name string, category Category, label string, fn func(result *big.Int, n uint64, duration time.Duration) string) Indicator {
+	return funcIndicator{name: name, category: category, label: label, compute: fn}
+}
+
+// RatedIndicator is an Indicator whose value is a count and its rate, such
+// as the doubling steps and the steps per second. The detailed output shows
+// the two parts apart; Compute joins them as "value  (rate)".
+type RatedIndicator interface {
+	Indicator
+	// ComputeRated returns the value and the rate for F(n) = result,
+	// computed in duration (positive).
+	ComputeRated(result *big.Int, n uint64, duration time.Duration) (value, rate string)
+}
+
+// ratedIndicator is a RatedIndicator backed by a function.
+type ratedIndicator struct {
+	funcIndicator
+	computeRated func(result *big.Int, n uint64, duration time.Duration) (value, rate string)
+}
+
+func (r ratedIndicator) Compute(result *big.Int, n uint64, duration time.Duration) string {
+	value, rate := r.computeRated(result, n, duration)
+	return value + "  (" + rate + ")"
+}
+
+func (r ratedIndicator) ComputeRated(result *big.Int, n uint64, duration time.Duration) (value, rate string) {
+	return r.computeRated(result, n, duration)
+}
+
+// NewRatedIndicator returns a RatedIndicator computed by fn.
+//
+// Parameters:
+//   - name: The identifier used in --indicators.
+//   - category: The group of the indicator.
+//   - label: The description shown next to the value.
+//   - fn: The function computing the value and its rate.
+//
+// Returns:
+//   - RatedIndicator: The indicator, ready to be registered.
+func NewRatedIndicator(name string, category Category, label string, fn func(result *big.Int, n uint64, duration time.Duration) (value, rate string)) RatedIndicator {
+	return ratedIndicator{funcIndicator: funcIndicator{name: name, category: category, label: label}, computeRated: fn}
+}
+
+// Registry holds indicators in registration order. It is safe for
+// concurrent use.
+type Registry struct {
+	mu         sync.RWMutex
+	indicators []Indicator
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds an indicator after the existing ones.
+//
+// Parameters:
+//   - ind: The indicator to add.
+//
+// Returns:
+//   - error: An error if ind is nil, its name is empty, reserved (a
+//     selection keyword or category) or already registered.
+func (r *Registry) Register(ind Indicator) error {
+	if ind == nil {
+		return errors.New("nil indicator")
+	}
+	name := ind.Name()
+	switch Category(name) {
+	case "", SelectAll, SelectList, CategoryPerf, CategoryMath:
+		return fmt.Errorf("invalid indicator name: %q", name)
+	}
+	if strings.Contains(name, ",") {
+		return fmt.Errorf("invalid indicator name: %q", name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.indicators {
+		if existing.Name() == name {
+			return fmt.Errorf("indicator %s is already registered", name)
+		}
+	}
+	r.indicators = append(r.indicators, ind)
+	return nil
+}
+
+// Indicators returns the registered indicators in registration order.
+func (r *Registry) Indicators() []Indicator {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]Indicator(nil), r.indicators...)
+}
+
+// Names returns the names of the registered indicators in registration
+// order.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, len(r.indicators))
+	for i, ind := range r.indicators {
+		names[i] = ind.Name()
+	}
+	return names
+}
+
+// Select resolves an --indicators value: "all" (or empty), a category
+// ("perf", "math") or a comma-separated list of names, which may mix
+// categories and names. The result follows registration order, without
+// duplicates.
+//
+// Parameters:
+//   - spec: The selection.
+//
+// Returns:
+//   - []Indicator: The selected indicators.
+//   - error: An error naming the first unknown entry.
+func (r *Registry) Select(spec string) ([]Indicator, error) {
+	all := r.Indicators()
+	if spec == "" || spec == SelectAll {
+		return all, nil
+	}
+	wanted := make(map[string]bool)
+	wantedCategories := make(map[Category]bool)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		switch Category(entry) {
+		case CategoryPerf, CategoryMath:
+			wantedCategories[Category(entry)] = true
+			continue
+		}
+		found := false
+		for _, ind := range all {
+			if ind.Name() == entry {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown indicator %q", entry)
+		}
+		wanted[entry] = true
+	}
+	var selected []Indicator
+	for _, ind := range all {
+		if wanted[ind.Name()] || wantedCategories[ind.Category()] {
+			selected = append(selected, ind)
+		}
+	}
+	return selected, nil
+}
+
+// DefaultRegistry holds the built-in indicators. Applications may register
+// their own with Register.
+var DefaultRegistry = NewRegistry()
+
+// Register adds an indicator to DefaultRegistry (see Registry.Register).
+func Register(ind Indicator) error {
+	return DefaultRegistry.Register(ind)
+}
+
+// builtinIndicators are the indicators of DefaultRegistry, in display order.
+var builtinIndicators = []Indicator{
+	NewIndicator("bit-throughput", CategoryPerf, "Throughput (bits)", func(result *big.Int, _ uint64, d time.Duration) string {
+		return FormatBitsPerSecond(float64(result.BitLen()) / d.Seconds())
+	}),
+	NewIndicator("digit-throughput", CategoryPerf, "Throughput (digits)", func(result *big.Int, _ uint64, d time.Duration) string {
+		return FormatDigitsPerSecond(float64(result.BitLen()) * math.Log10(2) / d.Seconds())
+	}),
+	NewRatedIndicator("doubling-steps", CategoryPerf, "Doubling steps", func(_ *big.Int, n uint64, d time.Duration) (string, string) {
+		steps := bits.Len64(n)
+		return fmt.Sprintf("%d", steps), fmt.Sprintf("%.2f steps/s", float64(steps)/d.Seconds())
+	}),
+	NewIndicator("golden-ratio", CategoryMath, "Golden ratio deviation", func(result *big.Int, n uint64, _ time.Duration) string {
+		return fmt.Sprintf("%.4f%%", goldenRatioDeviation(result.BitLen(), n))
+	}),
+	NewIndicator("digital-root", CategoryMath, "Digital root", func(result *big.Int, _ uint64, _ time.Duration) string {
+		return fmt.Sprintf("%d", digitalRoot(result))
+	}),
+	NewIndicator("last-digits", CategoryMath, "Last 20 digits", func(result *big.Int, _ uint64, _ time.Duration) string {
+		return lastNDigits(result, 20)
+	}),
+	NewIndicator("parity", CategoryMath, "Parity", func(_ *big.Int, n uint64, _ time.Duration) string {
+		if n%3 == 0 {
+			return "even"
+		}
+		return "odd"
+	}),
+}
+
+func init() {
+	for _, ind := range builtinIndicators {
+		if err := Register(ind); err != nil {
+			panic(err)
+		}
+	}
+}
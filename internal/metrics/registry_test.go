@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRegistryRegister(t *testing.T) {
+	r := NewRegistry()
+	constant := func(*big.Int, uint64, time.Duration) string { return "x" }
+	if err := r.Register(NewIndicator("custom", CategoryMath, "Custom", constant)); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	for _, name := range []string{"", "all", "list", "perf", "math", "a,b", "custom"} {
+		if err := r.Register(NewIndicator(name, CategoryMath, "Bad", constant)); err == nil {
+			t.Errorf("Register(%q) succeeded, want an error", name)
+		}
+	}
+	if err := r.Register(nil); err == nil {
+		t.Error("Register(nil) succeeded, want an error")
+	}
+	if got := r.Names(); !slices.Equal(got, []string{"custom"}) {
+		t.Errorf("Names() = %v, want [custom]", got)
+	}
+}
+
+func TestRegistrySelect(t *testing.T) {
+	names := func(inds []Indicator) []string {
+		var out []string
+		for _, ind := range inds {
+			out = append(out, ind.Name())
+		}
+		return out
+	}
+	tests := []struct {
+		spec string
+		want []string
+	}{
+		{"", DefaultRegistry.Names()},
+		{"all", DefaultRegistry.Names()},
+		{"perf", []string{"bit-throughput", "digit-throughput", "doubling-steps"}},
+		{"parity, bit-throughput", []string{"bit-throughput", "parity"}},
+		{"math,parity", []string{"golden-ratio", "digital-root", "last-digits", "parity"}},
+	}
+	for _, tt := range tests {
+		got, err := DefaultRegistry.Select(tt.spec)
+		if err != nil {
+			t.Errorf("Select(%q) error = %v", tt.spec, err)
+			continue
+		}
+		if !slices.Equal(names(got), tt.want) {
+			t.Errorf("Select(%q) = %v, want %v", tt.spec, names(got), tt.want)
+		}
+	}
+	if _, err := DefaultRegistry.Select("perf,nope"); err == nil {
+		t.Error("Select() with an unknown name succeeded, want an error")
+	}
+}
+
+func TestBuiltinIndicatorsMatchCompute(t *testing.T) {
+	result := fibSmall(100)
+	ind := Compute(result, 100, time.Second)
+	want := map[string]string{
+		"bit-throughput":   FormatBitsPerSecond(ind.BitsPerSecond),
+		"digit-throughput": FormatDigitsPerSecond(ind.DigitsPerSecond),
+		"doubling-steps":   "7  (7.00 steps/s)",
+		"golden-ratio":     fmt.Sprintf("%.4f%%", ind.GoldenRatioDeviation),
+		"digital-root":     "3",
+		"last-digits":      ind.LastDigits,
+		"parity":           "odd",
+	}
+	for _, b := range DefaultRegistry.Indicators() {
+		if got := b.Compute(result, 100, time.Second); got != want[b.Name()] {
+			t.Errorf("%s = %q, want %q", b.Name(), got, want[b.Name()])
+		}
+	}
+}
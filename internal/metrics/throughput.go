@@ -0,0 +1,104 @@
+package metrics
+
+import (
+	"math"
+	"time"
+)
+
+// Throughput history tuning.
+const (
+	// ThroughputWindow is the span of the rolling throughput.
+	ThroughputWindow = time.Minute
+	// throughputMinSpan is the shortest span a throughput is measured over;
+	// shorter spans are dominated by the timing of progress updates.
+	throughputMinSpan = time.Second
+)
+
+// throughputSample is the estimated number of result bits produced at a
+// point in time.
+type throughputSample struct {
+	at   time.Time
+	bits float64
+}
+
+// ThroughputHistory tracks the throughput of a running calculation over a
+// sliding window, and the peak of that rolling throughput. As operands
+// grow, each doubling step is slower than the last, so the rolling figure
+// falls below the peak; the gap shows the degradation.
+//
+// ThroughputHistory is not safe for concurrent use.
+type ThroughputHistory struct {
+	samples []throughputSample
+	peak    float64
+}
+
+// EstimatedBits returns the number of result bits produced at the given
+// progress of F(n), under the same model as ComputeLive.
+//
+// Parameters:
+//   - n: The Fibonacci index being computed.
+//   - progress: The normalized progress (0.0 to 1.0).
+//
+// Returns:
+//   - float64: progress · n·log₂(φ).
+func EstimatedBits(n uint64, progress float64) float64 {
+	return progress * float64(n) * log2Phi
+}
+
+// Add records the bits produced so far at time at, drops the samples that
+// left the window, and updates the peak. Samples must be added in time
+// order.
+//
+// Parameters:
+//   - at: The time of the sample.
+//   - bits: The cumulative number of bits produced.
+func (h *ThroughputHistory) Add(at time.Time, bits float64) {
+	h.samples = append(h.samples, throughputSample{at: at, bits: bits})
+	// Keep the newest sample older than the window, so the rolling rate
+	// always covers the full window once the run is that long.
+	cut := 0
+	for cut+1 < len(h.samples) && at.Sub(h.samples[cut+1].at) >= ThroughputWindow {
+		cut++
+	}
+	h.samples = h.samples[cut:]
+	if rate := h.Rolling(); rate > h.peak {
+		h.peak = rate
+	}
+}
+
+// Rolling returns the throughput in bits per second over the last
+// ThroughputWindow, or 0 until the samples span throughputMinSpan.
+func (h *ThroughputHistory) Rolling() float64 {
+	if len(h.samples) < 2 {
+		return 0
+	}
+	first, last := h.samples[0], h.samples[len(h.samples)-1]
+	span := last.at.Sub(first.at)
+	if span < throughputMinSpan {
+		return 0
+	}
+	return math.Max(0, (last.bits-first.bits)/span.Seconds())
+}
+
+// Peak returns the highest rolling throughput observed, in bits per second.
+func (h *ThroughputHistory) Peak() float64 {
+	return h.peak
+}
+
+// Annotate returns a copy of ind carrying the rolling and peak throughput.
+//
+// Parameters:
+//   - ind: The indicators to annotate (nil is returned as is).
+//
+// Returns:
+//   - *Indicators: A copy of ind with RollingBitsPerSecond and
+//     PeakBitsPerSecond set.
+func (h *ThroughputHistory) Annotate(ind *Indicators) *Indicators {
+	if ind == nil {
+		return nil
+	}
+	annotated := *ind
+	annotated.RollingBitsPerSecond = h.Rolling()
+	annotated.PeakBitsPerSecond = h.Peak()
+	return &annotated
+}
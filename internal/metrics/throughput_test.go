@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestThroughputHistoryRolling(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var h ThroughputHistory
+
+	h.Add(start, 0)
+	h.Add(start.Add(500*time.Millisecond), 500)
+	if got := h.Rolling(); got != 0 {
+		t.Errorf("Rolling() over 0.5s = %v, want 0 (span too short)", got)
+	}
+
+	// 1000 bit/s for the first minute, then 100 bit/s: the rolling rate
+	// falls while the peak keeps the early rate.
+	for s := 1; s <= 60; s++ {
+		h.Add(start.Add(time.Duration(s)*time.Second), float64(s)*1000)
+	}
+	if got := h.Rolling(); math.Abs(got-1000) > 1e-9 {
+		t.Errorf("Rolling() = %v, want 1000", got)
+	}
+	for s := 61; s <= 120; s++ {
+		h.Add(start.Add(time.Duration(s)*time.Second), 60000+float64(s-60)*100)
+	}
+	if got := h.Rolling(); math.Abs(got-100) > 1e-9 {
+		t.Errorf("Rolling() after slowdown = %v, want 100", got)
+	}
+	if got := h.Peak(); math.Abs(got-1000) > 1e-9 {
+		t.Errorf("Peak() = %v, want 1000", got)
+	}
+	if len(h.samples) > 62 {
+		t.Errorf("history kept %d samples, want the window only", len(h.samples))
+	}
+}
+
+func TestThroughputHistoryAnnotate(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var h ThroughputHistory
+	h.Add(start, 0)
+	h.Add(start.Add(2*time.Second), 4000)
+
+	ind := &Indicators{BitsPerSecond: 1}
+	got := h.Annotate(ind)
+	if got.RollingBitsPerSecond != 2000 || got.PeakBitsPerSecond != 2000 {
+		t.Errorf("Annotate() = rolling %v, peak %v; want 2000, 2000", got.RollingBitsPerSecond, got.PeakBitsPerSecond)
+	}
+	if ind.RollingBitsPerSecond != 0 {
+		t.Error("Annotate() modified its argument")
+	}
+	if got.BitsPerSecond != 1 {
+		t.Error("Annotate() dropped the other indicators")
+	}
+	if h.Annotate(nil) != nil {
+		t.Error("Annotate(nil) should return nil")
+	}
+}
+
+func TestEstimatedBits(t *testing.T) {
+	live := ComputeLive(1_000_000, 0.5, time.Second)
+	if got := EstimatedBits(1_000_000, 0.5); math.Abs(got-live.BitsPerSecond) > 1e-6 {
+		t.Errorf("EstimatedBits() = %v, want %v (ComputeLive over 1s)", got, live.BitsPerSecond)
+	}
+}
@@ -0,0 +1,6 @@
+// Package oeis cross-references results with the On-Line Encyclopedia of
+// Integer Sequences, whose entry A000045 lists the Fibonacci numbers. Its
+// b-file, the table of the terms a(0), a(1), ... maintained by the OEIS
+// editors, is downloaded on first use and cached locally (by default
+// ~/.fibcalc_b000045.txt), so that later checks work offline.
+package oeis
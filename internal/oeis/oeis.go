@@ -0,0 +1,233 @@
+package oeis
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/format"
+	"github.com/agbru/fibcalc/internal/fsguard"
+)
+
+const (
+	// SequenceID is the OEIS entry of the Fibonacci numbers.
+	SequenceID = "A000045"
+	// URL is the page of the entry.
+	URL = "https://oeis.org/" + SequenceID
+	// BFileURL is the b-file of the entry.
+	BFileURL = URL + "/b000045.txt"
+	// DefaultFileName is the name of the cached b-file in the home
+	// directory.
+	DefaultFileName = ".fibcalc_b000045.txt"
+)
+
+// downloadTimeout bounds the download of the b-file.
+const downloadTimeout = 30 * time.Second
+
+// maxBFileSize bounds the downloaded b-file, which is a few megabytes.
+const maxBFileSize = 64 << 20
+
+// BFile is the table of the terms of a b-file.
+type BFile struct {
+	terms map[uint64]string
+	// First and Last are the lowest and highest indices of the table.
+	First uint64
+	Last  uint64
+}
+
+// Term returns a(n).
+//
+// Parameters:
+//   - n: The index.
+//
+// Returns:
+//   - string: The term, in decimal.
+//   - bool: false if the table has no term of index n.
+func (b *BFile) Term(n uint64) (string, bool) {
+	term, ok := b.terms[n]
+	return term, ok
+}
+
+// Len returns the number of terms of the table.
+func (b *BFile) Len() int {
+	return len(b.terms)
+}
+
+// Parse reads a b-file: one "n a(n)" line per term, blank lines and lines
+// starting with '#' being ignored.
+//
+// Parameters:
+//   - r: The b-file.
+//
+// Returns:
+//   - *BFile: The table.
+//   - error: An error if a line is malformed or there is no term.
+func Parse(r io.Reader) (*BFile, error) {
+	b := &BFile{terms: make(map[uint64]string)}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, maxBFileSize)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("b-file line %d: expected \"n a(n)\", got %q", line, format.Abbreviate(text, 40, 0))
+		}
+		n, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("b-file line %d: invalid index %q", line, fields[0])
+		}
+		if strings.Trim(fields[1], "0123456789") != "" {
+			return nil, fmt.Errorf("b-file line %d: invalid term %q", line, format.Abbreviate(fields[1], 40, 0))
+		}
+		if len(b.terms) == 0 || n < b.First {
+			b.First = n
+		}
+		b.Last = max(b.Last, n)
+		b.terms[n] = fields[1]
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the b-file: %w", err)
+	}
+	if len(b.terms) == 0 {
+		return nil, fmt.Errorf("the b-file has no term")
+	}
+	return b, nil
+}
+
+// DefaultPath returns the cached b-file in the user's home directory, or
+// in the working directory if the home directory is unknown.
+func DefaultPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return DefaultFileName
+	}
+	return filepath.Join(home, DefaultFileName)
+}
+
+// Load reads the cached b-file.
+//
+// Parameters:
+//   - path: The cached b-file.
+//
+// Returns:
+//   - *BFile: The table.
+//   - error: An error if the file cannot be read or parsed; it wraps
+//     os.ErrNotExist if the file does not exist.
+func Load(path string) (*BFile, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the cached b-file: %w", err)
+	}
+	defer f.Close()
+	b, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return b, nil
+}
+
+// Download fetches the b-file and caches it at path, once it parses. With
+// --no-write (fsguard denying writes) the b-file is returned uncached.
+//
+// Parameters:
+//   - ctx: The context of the request.
+//   - url: The b-file URL, usually BFileURL.
+//   - path: The cached b-file.
+//
+// Returns:
+//   - *BFile: The table.
+//   - error: An error if the download fails, the b-file is malformed or the
+//     cache cannot be written.
+func Download(ctx context.Context, url, path string) (*BFile, error) {
+	ctx, cancel := context.WithTimeout(ctx, downloadTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("b-file download failed: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("b-file download failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("b-file download failed: %s answered %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBFileSize))
+	if err != nil {
+		return nil, fmt.Errorf("b-file download failed: %w", err)
+	}
+	b, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", url, err)
+	}
+
+	if err := writeCache(path, data); err != nil {
+		if errors.Is(err, fsguard.ErrDenied) {
+			return b, nil
+		}
+		return nil, err
+	}
+	return b, nil
+}
+
+// writeCache writes the downloaded b-file to the cache at path.
+//
+// Parameters:
+//   - path: The cached b-file.
+//   - data: The b-file contents.
+//
+// Returns:
+//   - error: An error if the cache cannot be written.
+func writeCache(path string, data []byte) error {
+	// Written aside then renamed, so that an interrupted write leaves no
+	// truncated cache
+	path = filepath.Clean(path)
+	if dir := filepath.Dir(path); dir != "." {
+		if err := fsguard.MkdirAll(dir, 0o750); err != nil {
+			return fmt.Errorf("failed to create the b-file cache directory: %w", err)
+		}
+	}
+	tmp := path + ".tmp"
+	if err := fsguard.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to cache the b-file: %w", err)
+	}
+	if err := fsguard.Rename(tmp, path); err != nil {
+		fsguard.Remove(tmp)
+		return fmt.Errorf("failed to cache the b-file: %w", err)
+	}
+	return nil
+}
+
+// Open returns the cached b-file, downloading it first if it is not
+// cached.
+//
+// Parameters:
+//   - ctx: The context of the download.
+//   - url: The b-file URL, usually BFileURL.
+//   - path: The cached b-file.
+//
+// Returns:
+//   - *BFile: The table.
+//   - bool: true if the b-file was downloaded.
+//   - error: An error if the b-file can be neither loaded nor downloaded.
+func Open(ctx context.Context, url, path string) (*BFile, bool, error) {
+	b, err := Load(path)
+	if !errors.Is(err, os.ErrNotExist) {
+		return b, false, err
+	}
+	b, err = Download(ctx, url, path)
+	return b, err == nil, err
+}
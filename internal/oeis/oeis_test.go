@@ -0,0 +1,109 @@
+package oeis
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/agbru/fibcalc/internal/fsguard"
+)
+
+const sample = "# A000045 (b-file synthesized from sequence entry)\n0 0\n1 1\n2 1\n3 2\n\n10 55\n"
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	b, err := Parse(strings.NewReader(sample))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if b.First != 0 || b.Last != 10 || b.Len() != 5 {
+		t.Errorf("Parse() = first %d, last %d, %d terms; want 0, 10, 5", b.First, b.Last, b.Len())
+	}
+	if term, ok := b.Term(10); !ok || term != "55" {
+		t.Errorf("Term(10) = %q, %v; want 55", term, ok)
+	}
+	if _, ok := b.Term(4); ok {
+		t.Error("Term(4) found in a table without it")
+	}
+
+	for _, in := range []string{"", "# comment only\n", "0 0 0\n", "x 1\n", "1 1.5\n"} {
+		if _, err := Parse(strings.NewReader(in)); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", in)
+		}
+	}
+
+	// A long non-ASCII line is shortened without splitting a character
+	_, err = Parse(strings.NewReader("1 " + strings.Repeat("é", 60) + "\n"))
+	if err == nil || !utf8.ValidString(err.Error()) || !strings.Contains(err.Error(), `"`+strings.Repeat("é", 40)+`..."`) {
+		t.Errorf("Parse of a non-ASCII line = %v, want a shortened, valid message", err)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	t.Parallel()
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(sample))
+	}))
+	defer srv.Close()
+	path := filepath.Join(t.TempDir(), "cache", DefaultFileName)
+
+	// Downloaded once, then read from the cache
+	for i, wantDownloaded := range []bool{true, false} {
+		b, downloaded, err := Open(context.Background(), srv.URL, path)
+		if err != nil || downloaded != wantDownloaded || b.Last != 10 {
+			t.Fatalf("Open() #%d = %+v, %v, %v; want downloaded %v", i+1, b, downloaded, err, wantDownloaded)
+		}
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("%d requests, want 1", n)
+	}
+}
+
+func TestDownloadDenied(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(sample))
+	}))
+	defer srv.Close()
+	path := filepath.Join(t.TempDir(), "cache", DefaultFileName)
+	defer fsguard.Deny()()
+
+	b, err := Download(context.Background(), srv.URL, path)
+	if err != nil || b.Last != 10 {
+		t.Fatalf("Download with writes denied = %+v, %v; want the b-file", b, err)
+	}
+	if _, err := os.Stat(filepath.Dir(path)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Download with writes denied left a cache: %v", err)
+	}
+}
+
+func TestDownloadErrors(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("<html>not a b-file</html>\n"))
+	}))
+	defer srv.Close()
+	dir := t.TempDir()
+
+	for _, url := range []string{srv.URL + "/missing", srv.URL + "/html"} {
+		path := filepath.Join(dir, "b.txt")
+		if _, err := Download(context.Background(), url, path); err == nil {
+			t.Errorf("Download(%s) succeeded, want an error", url)
+		}
+		if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("Download(%s) left a cache: %v", url, err)
+		}
+	}
+}
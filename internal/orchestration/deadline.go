@@ -0,0 +1,220 @@
+package orchestration
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/clock"
+)
+
+// DeadlineContext is a context that expires at a deadline which can be
+// pushed back while it runs, so that an interactive front end can let the
+// user extend a timeout instead of losing a long calculation. Once the
+// deadline has passed, Err returns context.DeadlineExceeded, as with
+// context.WithTimeout.
+type DeadlineContext struct {
+	parent context.Context
+	clock  clock.Clock
+
+	mu       sync.Mutex
+	deadline time.Time
+	// armed numbers the timers started by armLocked; only the latest one
+	// may expire the context.
+	armed uint64
+	done  chan struct{}
+	err   error
+	stop  func() bool
+	// pausedAt is when Pause stopped the clock, zero while it runs.
+	pausedAt time.Time
+}
+
+// deadlineKey is the context key under which a DeadlineContext finds
+// itself, through any context derived from it.
+type deadlineKey struct{}
+
+// WithExtendableTimeout returns a context that expires after timeout
+// unless Extend moves its deadline, or when parent is done.
+//
+// Parameters:
+//   - parent: The parent context.
+//   - timeout: The time until the deadline.
+//
+// Returns:
+//   - *DeadlineContext: The context.
+//   - context.CancelFunc: Releases the context's resources; it must be called.
+func WithExtendableTimeout(parent context.Context, timeout time.Duration) (*DeadlineContext, context.CancelFunc) {
+	return WithExtendableTimeoutClock(parent, timeout, clock.Real{})
+}
+
+// WithExtendableTimeoutClock is WithExtendableTimeout with the deadline
+// measured on clk. A nil clk uses the wall clock.
+//
+// Parameters:
+//   - parent: The parent context.
+//   - timeout: The time until the deadline.
+//   - clk: The time source.
+//
+// Returns:
+//   - *DeadlineContext: The context.
+//   - context.CancelFunc: Releases the context's resources; it must be called.
+func WithExtendableTimeoutClock(parent context.Context, timeout time.Duration, clk clock.Clock) (*DeadlineContext, context.CancelFunc) {
+	clk = clock.Or(clk)
+	d := &DeadlineContext{
+		parent:   parent,
+		clock:    clk,
+		deadline: clk.Now().Add(timeout),
+		done:     make(chan struct{}),
+	}
+	// Both callbacks wait for the lock until the context is set up.
+	d.mu.Lock()
+	d.armLocked()
+	d.stop = context.AfterFunc(parent, func() { d.expire(parent.Err()) })
+	d.mu.Unlock()
+	return d, func() { d.expire(context.Canceled) }
+}
+
+// DeadlineFrom returns the DeadlineContext ctx derives from, if any.
+func DeadlineFrom(ctx context.Context) (*DeadlineContext, bool) {
+	d, ok := ctx.Value(deadlineKey{}).(*DeadlineContext)
+	return d, ok
+}
+
+// armLocked starts a timer that expires the context at the deadline, and
+// disarms the previous one. It is called with d.mu held.
+func (d *DeadlineContext) armLocked() {
+	d.armed++
+	timer, fire := d.armed, d.clock.After(d.deadline.Sub(d.clock.Now()))
+	go func() {
+		select {
+		case <-fire:
+			d.timeout(timer)
+		case <-d.done:
+		}
+	}()
+}
+
+// timeout expires the context when the given timer fires, unless a later
+// one replaced it, the clock is paused or the deadline has moved.
+func (d *DeadlineContext) timeout(timer uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if timer != d.armed || !d.pausedAt.IsZero() || d.clock.Now().Before(d.deadline) {
+		return
+	}
+	d.expireLocked(context.DeadlineExceeded)
+}
+
+// expire closes the context with err, unless it is already closed.
+func (d *DeadlineContext) expire(err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.expireLocked(err)
+}
+
+// expireLocked is expire with d.mu held.
+func (d *DeadlineContext) expireLocked(err error) {
+	if d.err != nil {
+		return
+	}
+	d.err = err
+	d.stop()
+	close(d.done)
+}
+
+// Extend moves the deadline by the given duration.
+//
+// Parameters:
+//   - by: The extension, positive.
+//
+// Returns:
+//   - bool: false if the context has already expired, in which case the
+//     deadline is unchanged.
+func (d *DeadlineContext) Extend(by time.Duration) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.err != nil {
+		return false
+	}
+	if by > 0 {
+		d.deadline = d.deadline.Add(by)
+		if d.pausedAt.IsZero() {
+			d.armLocked()
+		}
+	}
+	return true
+}
+
+// Pause stops the clock of the deadline until the returned function is
+// called, which pushes the deadline back by the time paused, so that work
+// outside the timed budget (a warm-up) does not use it up. Cancellation
+// still applies while paused. Pauses do not nest.
+//
+// Returns:
+//   - func(): Resumes the clock; calls after the first do nothing.
+func (d *DeadlineContext) Pause() (resume func()) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.err != nil || !d.pausedAt.IsZero() {
+		return func() {}
+	}
+	d.pausedAt = d.clock.Now()
+	d.armed++ // disarms the timer
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			d.mu.Lock()
+			defer d.mu.Unlock()
+			d.deadline = d.deadline.Add(d.clock.Since(d.pausedAt))
+			d.pausedAt = time.Time{}
+			if d.err == nil {
+				d.armLocked()
+			}
+		})
+	}
+}
+
+// Remaining returns the time left until the deadline, zero once it has
+// passed.
+func (d *DeadlineContext) Remaining() time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.pausedAt.IsZero() {
+		return max(0, d.deadline.Sub(d.pausedAt))
+	}
+	return max(0, d.deadline.Sub(d.clock.Now()))
+}
+
+// Deadline returns the current deadline, or the parent's if it is earlier.
+func (d *DeadlineContext) Deadline() (time.Time, bool) {
+	d.mu.Lock()
+	deadline := d.deadline
+	d.mu.Unlock()
+	if parent, ok := d.parent.Deadline(); ok && parent.Before(deadline) {
+		return parent, true
+	}
+	return deadline, true
+}
+
+// Done returns a channel closed when the context expires or is canceled.
+func (d *DeadlineContext) Done() <-chan struct{} {
+	return d.done
+}
+
+// Err returns nil while the context runs, context.DeadlineExceeded once
+// the deadline has passed and context.Canceled (or the parent's error)
+// otherwise.
+func (d *DeadlineContext) Err() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.err
+}
+
+// Value returns the value of the parent for key, and the DeadlineContext
+// itself for the key used by DeadlineFrom.
+func (d *DeadlineContext) Value(key any) any {
+	if key == (deadlineKey{}) {
+		return d
+	}
+	return d.parent.Value(key)
+}
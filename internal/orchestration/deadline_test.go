@@ -0,0 +1,150 @@
+package orchestration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/clock"
+)
+
+func TestWithExtendableTimeoutExpires(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := WithExtendableTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("context did not expire")
+	}
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		t.Errorf("Err() = %v, want context.DeadlineExceeded", ctx.Err())
+	}
+	if ctx.Extend(time.Minute) {
+		t.Error("Extend succeeded on an expired context")
+	}
+	if ctx.Remaining() != 0 {
+		t.Errorf("Remaining() = %v after expiry, want 0", ctx.Remaining())
+	}
+}
+
+func TestWithExtendableTimeoutExtend(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := WithExtendableTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	before, _ := ctx.Deadline()
+
+	if !ctx.Extend(time.Hour) {
+		t.Fatal("Extend failed on a running context")
+	}
+	after, _ := ctx.Deadline()
+	if got := after.Sub(before); got != time.Hour {
+		t.Errorf("deadline moved by %v, want 1h", got)
+	}
+	if ctx.Remaining() < 59*time.Minute {
+		t.Errorf("Remaining() = %v, want about 1h", ctx.Remaining())
+	}
+
+	select {
+	case <-ctx.Done():
+		t.Fatalf("context expired despite the extension: %v", ctx.Err())
+	case <-time.After(150 * time.Millisecond):
+	}
+}
+
+func TestWithExtendableTimeoutCancel(t *testing.T) {
+	t.Parallel()
+	parent, cancelParent := context.WithCancel(context.Background())
+	ctx, cancel := WithExtendableTimeout(parent, time.Hour)
+	defer cancel()
+
+	// Derived contexts see the cancellation and can find the deadline
+	child, cancelChild := context.WithCancel(ctx)
+	defer cancelChild()
+	if d, ok := DeadlineFrom(child); !ok || d != ctx {
+		t.Fatal("DeadlineFrom did not find the deadline context")
+	}
+	if _, ok := DeadlineFrom(parent); ok {
+		t.Error("DeadlineFrom found a deadline in a plain context")
+	}
+
+	cancelParent()
+	select {
+	case <-child.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("child not canceled with the parent")
+	}
+	if !errors.Is(ctx.Err(), context.Canceled) {
+		t.Errorf("Err() = %v, want context.Canceled", ctx.Err())
+	}
+}
+
+func TestDeadlineContextPause(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := WithExtendableTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	resume := ctx.Pause()
+	remaining := ctx.Remaining()
+	select {
+	case <-ctx.Done():
+		t.Fatalf("context expired while paused: %v", ctx.Err())
+	case <-time.After(150 * time.Millisecond):
+	}
+	if got := ctx.Remaining(); got != remaining {
+		t.Errorf("Remaining() = %v after a paused wait, want %v", got, remaining)
+	}
+	resume()
+	resume()
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("context did not expire after resuming")
+	}
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		t.Errorf("Err() = %v, want context.DeadlineExceeded", ctx.Err())
+	}
+}
+
+func TestWithExtendableTimeoutClock(t *testing.T) {
+	t.Parallel()
+	clk := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	ctx, cancel := WithExtendableTimeoutClock(context.Background(), 10*time.Second, clk)
+	defer cancel()
+	expired := func() bool {
+		select {
+		case <-ctx.Done():
+			return true
+		case <-time.After(50 * time.Millisecond):
+			return false
+		}
+	}
+
+	clk.Advance(9 * time.Second)
+	if expired() {
+		t.Fatal("context expired before its deadline")
+	}
+	if got := ctx.Remaining(); got != time.Second {
+		t.Errorf("Remaining() = %v, want 1s", got)
+	}
+
+	// The extension and the pause both move the deadline on the fake clock.
+	ctx.Extend(5 * time.Second)
+	resume := ctx.Pause()
+	clk.Advance(time.Hour)
+	resume()
+	clk.Advance(5 * time.Second)
+	if expired() {
+		t.Fatal("context expired despite the extension and the pause")
+	}
+	clk.Advance(time.Second)
+	if !expired() {
+		t.Fatal("context did not expire at the deadline")
+	}
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		t.Errorf("Err() = %v, want context.DeadlineExceeded", ctx.Err())
+	}
+}
@@ -6,6 +6,10 @@ import (
 	"sync"
 	"time"
 
+	"github.com/agbru/fibcalc/internal/bigfft"
+	"github.com/agbru/fibcalc/internal/energy"
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/perfevent"
 	"github.com/agbru/fibcalc/internal/progress"
 )
 
@@ -20,6 +24,31 @@ type CalculationResult struct {
 	Duration time.Duration
 	// Err contains any error that occurred during the calculation.
 	Err error
+	// Kind categorizes the outcome: the category of Err, or
+	// apperrors.ErrorKindMismatch for a value that disagrees with the
+	// other algorithms' (Err is nil then).
+	Kind apperrors.ErrorKind
+	// Counters holds the hardware cache counters sampled during the
+	// calculation. It is nil unless counters were requested and available.
+	Counters *perfevent.Counts
+	// Energy is the energy used by the calculation, measured or
+	// estimated. It is nil unless energy reporting was requested.
+	Energy *energy.Reading
+	// CacheStats holds the statistics of the calculation's FFT transform
+	// cache. It is nil if the cache was never consulted.
+	CacheStats *bigfft.CacheStats
+	// SpillStats holds the transform buffers the calculation placed in
+	// temporary files. It is nil if none was spilled.
+	SpillStats *bigfft.SpillStats
+}
+
+// ErrorKind returns Kind, or the category of Err for a result built without
+// Kind.
+func (r CalculationResult) ErrorKind() apperrors.ErrorKind {
+	if r.Kind == apperrors.ErrorKindNone {
+		return apperrors.KindOf(r.Err)
+	}
+	return r.Kind
 }
 
 // PresentationOptions configures how results are presented to the user.
@@ -4,15 +4,22 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"math/big"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"golang.org/x/sync/errgroup"
 
+	"github.com/agbru/fibcalc/internal/clock"
+	"github.com/agbru/fibcalc/internal/energy"
 	apperrors "github.com/agbru/fibcalc/internal/errors"
 	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/fibonacci/memory"
+	"github.com/agbru/fibcalc/internal/perfevent"
 	"github.com/agbru/fibcalc/internal/progress"
+	"github.com/agbru/fibcalc/internal/sysmon"
 )
 
 // ProgressBufferMultiplier defines the buffer size multiplier for the progress
@@ -20,12 +27,131 @@ import (
 // goroutines when the UI is slow to consume updates.
 const ProgressBufferMultiplier = 5
 
+// CompareMode selects how multiple calculators are scheduled when comparing
+// algorithms.
+type CompareMode string
+
+const (
+	// CompareParallel starts all calculators at once. This is the fastest way
+	// to compare, but the algorithms compete for cores and memory bandwidth,
+	// which skews the measured durations.
+	CompareParallel CompareMode = "parallel"
+	// CompareSequential runs calculators back-to-back so each one has the
+	// machine to itself. Use this for fair benchmarking.
+	CompareSequential CompareMode = "sequential"
+	// CompareStaggered starts calculators one after another with a fixed
+	// offset (StaggerDelay), so their memory-heavy final steps are less
+	// likely to coincide while still overlapping most of the work.
+	CompareStaggered CompareMode = "staggered"
+)
+
+// StaggerDelay is the start offset between calculators in CompareStaggered mode.
+const StaggerDelay = 250 * time.Millisecond
+
+// CompareModes lists the accepted comparison modes, in display order.
+var CompareModes = []CompareMode{CompareParallel, CompareSequential, CompareStaggered}
+
+// ParseCompareMode converts a user-supplied string into a CompareMode.
+// The empty string maps to CompareParallel.
+//
+// Parameters:
+//   - s: The mode name ("parallel", "sequential", "staggered" or "").
+//
+// Returns:
+//   - CompareMode: The parsed mode.
+//   - error: An error if the mode is not recognized.
+func ParseCompareMode(s string) (CompareMode, error) {
+	if s == "" {
+		return CompareParallel, nil
+	}
+	for _, m := range CompareModes {
+		if string(m) == s {
+			return m, nil
+		}
+	}
+	names := make([]string, len(CompareModes))
+	for i, m := range CompareModes {
+		names[i] = string(m)
+	}
+	return "", fmt.Errorf("unknown compare mode %q (accepted values: %s)", s, strings.Join(names, ", "))
+}
+
+// ExecutionOptions controls how ExecuteCalculationsWithOptions schedules and
+// instruments calculators.
+type ExecutionOptions struct {
+	// Mode is the scheduling mode for multiple calculators.
+	Mode CompareMode
+	// PerfCounters enables hardware cache counters around each calculator
+	// (see package perfevent). Counters are process-wide, so when enabled
+	// multiple calculators always run sequentially to keep the per-algorithm
+	// figures meaningful.
+	PerfCounters bool
+	// Energy enables the energy indicator around each calculator (see
+	// package energy). Like the hardware counters, it measures the whole
+	// process or package, so multiple calculators then run sequentially.
+	Energy bool
+	// TDP is the processor's thermal design power in watts used when the
+	// energy has to be estimated. Zero means energy.DefaultTDP.
+	TDP float64
+	// Failure, if set, makes every calculator fail as described (see
+	// fibonacci.WithFailureInjection), to test error handling paths.
+	Failure *fibonacci.FailureInjection
+	// Warmup is the number of untimed runs of each calculator, on
+	// WarmupIndex(n), before the measured runs. They absorb first-run
+	// effects (page faults, cache population, pool and transform cache
+	// warm-up) that would otherwise penalize whichever algorithm runs first.
+	Warmup int
+	// ProgressPolicy is what happens to progress updates when the
+	// reporter falls behind and the channel is full (see progress.Policy).
+	// Empty means progress.PolicyDrop.
+	ProgressPolicy progress.Policy
+	// ProgressTimeout is how long progress.PolicyBlock waits for room;
+	// zero means progress.DefaultBlockTimeout.
+	ProgressTimeout time.Duration
+	// Clock is the time source for the stagger delay and the reported
+	// durations. Nil means the wall clock; tests use a clock.Fake.
+	Clock clock.Clock
+	// Contention, if set, samples the external CPU load while the
+	// calculators run and serves as their fibonacci.ParallelGate, so that
+	// doubling steps multiply sequentially while other processes keep the
+	// CPUs busy (see sysmon.ContentionMonitor).
+	Contention *sysmon.ContentionMonitor
+	// MemoryPressure, if set, samples the runtime's memory against its soft
+	// limit while the calculators run; when it gets close, their transform
+	// caches are dropped and disabled, and the monitor's other handlers run
+	// (see memory.PressureMonitor).
+	MemoryPressure *memory.PressureMonitor
+	// Watchdog, if set, follows the progress of the measured runs and
+	// reports, or aborts, those that stall (see Watchdog).
+	Watchdog *Watchdog
+	// RuntimeTrace, if set, captures an execution trace of the heaviest
+	// steps of the first measured run to reach them (see RuntimeTrace).
+	RuntimeTrace *RuntimeTrace
+}
+
+// WarmupMaxN caps the index of the warm-up runs: large enough to go through
+// the FFT multiplication path, small enough to take a few milliseconds.
+const WarmupMaxN uint64 = 1_000_000
+
+// WarmupIndex returns the index the warm-up runs compute for a measured
+// run of index n.
+//
+// Parameters:
+//   - n: The index of the measured run.
+//
+// Returns:
+//   - uint64: min(n, WarmupMaxN).
+func WarmupIndex(n uint64) uint64 {
+	return min(n, WarmupMaxN)
+}
+
 // ExecuteCalculations orchestrates the concurrent execution of one or more
 // Fibonacci calculations.
 //
 // It manages the lifecycle of calculation goroutines, collects their results,
 // and coordinates the display of progress updates. This function is the core of
-// the application's concurrency model.
+// the application's concurrency model. Calculators are started simultaneously;
+// use ExecuteCalculationsWithMode to choose another scheduling.
 //
 // Parameters:
 //   - ctx: The context for managing cancellation and deadlines.
@@ -38,65 +164,260 @@ const ProgressBufferMultiplier = 5
 // Returns:
 //   - []CalculationResult: A slice containing the results of each calculation.
 func ExecuteCalculations(ctx context.Context, calculators []fibonacci.Calculator, n uint64, opts fibonacci.Options, progressReporter ProgressReporter, out io.Writer) []CalculationResult {
+	return ExecuteCalculationsWithMode(ctx, calculators, n, opts, CompareParallel, progressReporter, out)
+}
+
+// ExecuteCalculationsWithMode is like ExecuteCalculations but lets the caller
+// choose how multiple calculators are scheduled (see CompareMode). With a
+// single calculator the mode has no effect.
+//
+// In parallel and staggered modes the first failure cancels the remaining
+// calculators. In sequential mode every calculator runs to completion (or
+// failure) independently, so one failing algorithm does not hide the timings
+// of the others.
+//
+// Parameters:
+//   - ctx: The context for managing cancellation and deadlines.
+//   - calculators: A slice of calculators to execute.
+//   - n: The Fibonacci index to compute.
+//   - opts: Calculation options (thresholds, etc.).
+//   - mode: The scheduling mode for multiple calculators.
+//   - progressReporter: The progress reporter for displaying updates.
+//   - out: The io.Writer for displaying progress updates.
+//
+// Returns:
+//   - []CalculationResult: A slice containing the results of each calculation.
+func ExecuteCalculationsWithMode(ctx context.Context, calculators []fibonacci.Calculator, n uint64, opts fibonacci.Options, mode CompareMode, progressReporter ProgressReporter, out io.Writer) []CalculationResult {
+	return ExecuteCalculationsWithOptions(ctx, calculators, n, opts, ExecutionOptions{Mode: mode}, progressReporter, out)
+}
+
+// ExecuteCalculationsWithOptions is like ExecuteCalculationsWithMode but also
+// accepts instrumentation settings. When exec.PerfCounters is set, each
+// successful result carries the hardware counters sampled while it ran, or
+// nil if they could not be collected. When exec.Energy is set, each
+// successful result carries the energy it used. When exec.Warmup is positive, every
+// calculator first runs that many times, untimed, before any measured run.
+// When exec.Contention is set, it samples the CPU during the measured runs
+// and gates their parallel multiplications. When exec.MemoryPressure is set,
+// it samples the memory during the measured runs and disables their
+// transform caches near the memory limit. When exec.Watchdog is set, it
+// watches the measured runs for stalls. When exec.RuntimeTrace is set, it
+// traces the heaviest steps of a measured run.
+//
+// Parameters:
+//   - ctx: The context for managing cancellation and deadlines.
+//   - calculators: A slice of calculators to execute.
+//   - n: The Fibonacci index to compute.
+//   - opts: Calculation options (thresholds, etc.).
+//   - exec: Scheduling and instrumentation options.
+//   - progressReporter: The progress reporter for displaying updates.
+//   - out: The io.Writer for displaying progress updates.
+//
+// Returns:
+//   - []CalculationResult: A slice containing the results of each calculation.
+func ExecuteCalculationsWithOptions(ctx context.Context, calculators []fibonacci.Calculator, n uint64, opts fibonacci.Options, exec ExecutionOptions, progressReporter ProgressReporter, out io.Writer) []CalculationResult {
+	mode := exec.Mode
+	if exec.PerfCounters || exec.Energy {
+		mode = CompareSequential
+	}
+	clk := clock.Or(exec.Clock)
+	warmUp(ctx, calculators, WarmupIndex(n), opts, exec.Warmup)
+
+	if exec.Contention != nil {
+		monitorCtx, stopMonitor := context.WithCancel(ctx)
+		defer stopMonitor()
+		go exec.Contention.Run(monitorCtx, sysmon.DefaultContentionInterval)
+		opts.ParallelGate = exec.Contention
+	}
+	if exec.MemoryPressure != nil {
+		monitorCtx, stopMonitor := context.WithCancel(ctx)
+		defer stopMonitor()
+		go exec.MemoryPressure.Run(monitorCtx, memory.DefaultPressureInterval)
+		opts.MemoryPressure = exec.MemoryPressure
+	}
+	if exec.Watchdog != nil {
+		monitorCtx, stopMonitor := context.WithCancel(ctx)
+		defer stopMonitor()
+		go exec.Watchdog.Run(monitorCtx, DefaultWatchdogInterval)
+	}
+
 	results := make([]CalculationResult, len(calculators))
-	progressChan := make(chan progress.ProgressUpdate, len(calculators)*ProgressBufferMultiplier)
+	channel := progress.NewChannel(len(calculators)*ProgressBufferMultiplier, exec.ProgressPolicy, exec.ProgressTimeout)
+	progressChan := channel.Send()
 
 	var displayWg sync.WaitGroup
 	displayWg.Add(1)
-	go progressReporter.DisplayProgress(&displayWg, progressChan, len(calculators), out)
+	go progressReporter.DisplayProgress(&displayWg, channel.C(), len(calculators), out)
 
-	// Fast path: single calculator doesn't need errgroup overhead
-	if len(calculators) == 1 {
-		func() {
-			defer func() {
-				if r := recover(); r != nil {
-					results[0] = CalculationResult{
-						Name: calculators[0].Name(), Err: fmt.Errorf("panic in calculator %s: %v", calculators[0].Name(), r),
-					}
-				}
-			}()
-			startTime := time.Now()
-			res, err := calculators[0].Calculate(ctx, progressChan, 0, n, opts)
-			if err != nil {
-				err = fmt.Errorf("calculator %s: %w", calculators[0].Name(), err)
-			}
-			results[0] = CalculationResult{
-				Name: calculators[0].Name(), Result: res, Duration: time.Since(startTime), Err: err,
-			}
-		}()
-	} else {
+	switch {
+	case len(calculators) == 1:
+		// Fast path: single calculator doesn't need errgroup overhead
+		results[0] = runCalculator(ctx, calculators[0], progressChan, 0, n, opts, exec)
+	case mode == CompareSequential:
+		for i, calc := range calculators {
+			results[i] = runCalculator(ctx, calc, progressChan, i, n, opts, exec)
+		}
+	default:
 		g, ctx := errgroup.WithContext(ctx)
 		for i, calc := range calculators {
 			idx, calculator := i, calc
-			g.Go(func() (err error) {
-				defer func() {
-					if r := recover(); r != nil {
-						err = fmt.Errorf("panic in calculator %s: %v", calculator.Name(), r)
-						results[idx] = CalculationResult{
-							Name: calculator.Name(), Err: err,
-						}
-					}
-				}()
-				startTime := time.Now()
-				res, calcErr := calculator.Calculate(ctx, progressChan, idx, n, opts)
-				if calcErr != nil {
-					calcErr = fmt.Errorf("calculator %s: %w", calculator.Name(), calcErr)
+			if mode == CompareStaggered && idx > 0 {
+				select {
+				case <-clk.After(StaggerDelay):
+				case <-ctx.Done():
 				}
-				results[idx] = CalculationResult{
-					Name: calculator.Name(), Result: res, Duration: time.Since(startTime), Err: calcErr,
-				}
-				return calcErr
+			}
+			g.Go(func() error {
+				results[idx] = runCalculator(ctx, calculator, progressChan, idx, n, opts, exec)
+				return results[idx].Err
 			})
 		}
 		g.Wait()
 	}
 
-	close(progressChan)
+	channel.Close()
 	displayWg.Wait()
 
 	return results
 }
 
+// warmUp runs each calculator runs times on n, one at a time and without
+// progress reporting, timing or failure injection. Results and errors are
+// discarded: a calculator that fails here fails again in the measured run,
+// where the error is reported. It stops early if ctx is canceled. The
+// deadline of a DeadlineContext is paused meanwhile, so that the warm-up
+// does not use up the timeout of the measured runs.
+func warmUp(ctx context.Context, calculators []fibonacci.Calculator, n uint64, opts fibonacci.Options, runs int) {
+	if runs <= 0 {
+		return
+	}
+	if d, ok := DeadlineFrom(ctx); ok {
+		defer d.Pause()()
+	}
+	for _, calc := range calculators {
+		wrapped := fibonacci.WrapCalculator(calc, fibonacci.WithRecovery())
+		for range runs {
+			if ctx.Err() != nil {
+				return
+			}
+			_, _ = wrapped.Calculate(ctx, nil, 0, n, opts)
+		}
+	}
+}
+
+// runCalculator executes a single calculator through the standard middleware
+// chain: panic recovery, optional hardware counters and energy, timing,
+// then optional failure injection, stall watchdog and runtime trace.
+func runCalculator(ctx context.Context, calculator fibonacci.Calculator, progressChan chan<- progress.ProgressUpdate, idx int, n uint64, opts fibonacci.Options, exec ExecutionOptions) CalculationResult {
+	result := CalculationResult{Name: calculator.Name()}
+	middlewares := []fibonacci.Middleware{fibonacci.WithRecovery()}
+	if exec.PerfCounters {
+		middlewares = append(middlewares, withPerfCounters(&result.Counters))
+	}
+	if exec.Energy {
+		middlewares = append(middlewares, withEnergy(&result.Energy, exec.TDP))
+	}
+	middlewares = append(middlewares, fibonacci.WithTimingClock(exec.Clock, func(_ string, d time.Duration, _ error) {
+		result.Duration = d
+	}))
+	if exec.Failure != nil {
+		middlewares = append(middlewares, fibonacci.WithFailureInjection(*exec.Failure))
+	}
+	if exec.Watchdog != nil {
+		middlewares = append(middlewares, exec.Watchdog.middleware())
+	}
+	if exec.RuntimeTrace != nil {
+		middlewares = append(middlewares, exec.RuntimeTrace.middleware())
+	}
+
+	// Attach the transform cache and spill here so their statistics can be
+	// reported
+	opts = opts.WithTransformCache()
+	res, err := fibonacci.WrapCalculator(calculator, middlewares...).Calculate(ctx, progressChan, idx, n, opts)
+	result.Result = res
+	if stats, ok := opts.TransformCacheStats(); ok && stats.Hits+stats.Misses > 0 {
+		result.CacheStats = &stats
+	}
+	if stats, ok := opts.SpillStats(); ok && stats.Buffers > 0 {
+		result.SpillStats = &stats
+	}
+	if err != nil {
+		result.Err = fmt.Errorf("calculator %s: %w", calculator.Name(), err)
+	}
+	result.Kind = apperrors.KindOf(err)
+	return result
+}
+
+// withPerfCounters samples hardware cache counters around a calculation and
+// stores them in *dst when it succeeds. Unavailable counters are not fatal;
+// *dst is simply left nil.
+func withPerfCounters(dst **perfevent.Counts) fibonacci.Middleware {
+	return fibonacci.NewMiddleware(func(_ string, next fibonacci.CalculateFunc) fibonacci.CalculateFunc {
+		return func(ctx context.Context, progressChan chan<- progress.ProgressUpdate, calcIndex int, n uint64, opts fibonacci.Options) (result *big.Int, err error) {
+			session, serr := perfevent.Start()
+			if serr != nil {
+				return next(ctx, progressChan, calcIndex, n, opts)
+			}
+			defer func() {
+				if c, cerr := session.Stop(); cerr == nil && err == nil {
+					*dst = &c
+				}
+			}()
+			return next(ctx, progressChan, calcIndex, n, opts)
+		}
+	})
+}
+
+// withEnergy measures the energy used by a calculation and stores it in
+// *dst when it succeeds.
+func withEnergy(dst **energy.Reading, tdp float64) fibonacci.Middleware {
+	return fibonacci.NewMiddleware(func(_ string, next fibonacci.CalculateFunc) fibonacci.CalculateFunc {
+		return func(ctx context.Context, progressChan chan<- progress.ProgressUpdate, calcIndex int, n uint64, opts fibonacci.Options) (result *big.Int, err error) {
+			session := energy.Start(tdp)
+			defer func() {
+				if r := session.Stop(); err == nil {
+					*dst = &r
+				}
+			}()
+			return next(ctx, progressChan, calcIndex, n, opts)
+		}
+	})
+}
+
+// markMismatches sets Kind to apperrors.ErrorKindMismatch on the successful
+// results whose value differs from the reference: the value returned by the
+// most algorithms, the earliest in results on a tie.
+//
+// Returns:
+//   - bool: True if any result was marked.
+func markMismatches(results []CalculationResult) bool {
+	var reference *big.Int
+	best := 0
+	for i := range results {
+		if results[i].Err != nil {
+			continue
+		}
+		count := 0
+		for j := range results {
+			if results[j].Err == nil && results[j].Result.Cmp(results[i].Result) == 0 {
+				count++
+			}
+		}
+		if count > best {
+			reference, best = results[i].Result, count
+		}
+	}
+
+	mismatch := false
+	for i := range results {
+		if results[i].Err == nil && results[i].Result.Cmp(reference) != 0 {
+			results[i].Kind = apperrors.ErrorKindMismatch
+			mismatch = true
+		}
+	}
+	return mismatch
+}
+
 // AnalyzeComparisonResults processes the results from multiple algorithms and
 // generates a summary report.
 //
@@ -138,6 +459,8 @@ func AnalyzeComparisonResults(results []CalculationResult, presOpts Presentation
 		}
 	}
 
+	mismatch := markMismatches(results)
+
 	// Present the comparison table
 	presenter.PresentComparisonTable(results, out)
 
@@ -146,16 +469,9 @@ func AnalyzeComparisonResults(results []CalculationResult, presOpts Presentation
 		return errHandler.HandleError(firstError, 0, out)
 	}
 
-	mismatch := false
-	for _, res := range results {
-		if res.Err == nil && res.Result.Cmp(firstValidResult.Result) != 0 {
-			mismatch = true
-			break
-		}
-	}
 	if mismatch {
 		fmt.Fprintf(out, "\nGlobal Status: CRITICAL ERROR! An inconsistency was detected between the results of the algorithms.")
-		return apperrors.ExitErrorMismatch
+		return apperrors.ErrorKindMismatch.ExitCode()
 	}
 
 	fmt.Fprintf(out, "\nGlobal Status: Success. All valid results are consistent.\n")
@@ -5,9 +5,15 @@ import (
 	"errors"
 	"io"
 	"math/big"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/agbru/fibcalc/internal/clock"
 	apperrors "github.com/agbru/fibcalc/internal/errors"
 	"github.com/agbru/fibcalc/internal/fibonacci"
 	"github.com/agbru/fibcalc/internal/progress"
@@ -164,9 +170,437 @@ func TestAnalyzeComparisonResults(t *testing.T) {
 	}
 }
 
+func TestMarkMismatches(t *testing.T) {
+	t.Parallel()
+	results := []CalculationResult{
+		{Name: "A", Result: big.NewInt(6)},
+		{Name: "B", Result: big.NewInt(5)},
+		{Name: "C", Err: errors.New("fail"), Kind: apperrors.ErrorKindInternal},
+		{Name: "D", Result: big.NewInt(5)},
+	}
+	if !markMismatches(results) {
+		t.Fatal("expected a mismatch")
+	}
+	want := []apperrors.ErrorKind{apperrors.ErrorKindMismatch, apperrors.ErrorKindNone, apperrors.ErrorKindInternal, apperrors.ErrorKindNone}
+	for i, res := range results {
+		if res.Kind != want[i] {
+			t.Errorf("%s: kind %v, want %v (the value shared by most algorithms is the reference)", res.Name, res.Kind, want[i])
+		}
+	}
+
+	// On a tie, the first result is the reference.
+	tie := []CalculationResult{{Name: "A", Result: big.NewInt(5)}, {Name: "B", Result: big.NewInt(6)}}
+	markMismatches(tie)
+	if tie[0].Kind != apperrors.ErrorKindNone || tie[1].Kind != apperrors.ErrorKindMismatch {
+		t.Errorf("tie: kinds %v and %v, want none and mismatch", tie[0].Kind, tie[1].Kind)
+	}
+}
+
+func TestExecuteCalculationsErrorKinds(t *testing.T) {
+	t.Parallel()
+	failing := func(name string, fail func() error) fibonacci.Calculator {
+		return &MockCalculator{
+			NameFunc: func() string { return name },
+			CalculateFunc: func(context.Context, progress.ProgressCallback, int, uint64, fibonacci.Options) (*big.Int, error) {
+				return nil, fail()
+			},
+		}
+	}
+	calculators := []fibonacci.Calculator{
+		failing("timeout", func() error { return context.DeadlineExceeded }),
+		failing("canceled", func() error { return context.Canceled }),
+		failing("oom", func() error { return apperrors.MemoryError{Requested: 1} }),
+		failing("internal", func() error { panic("boom") }),
+		failing("ok", func() error { return nil }),
+	}
+	want := map[string]apperrors.ErrorKind{
+		"timeout":  apperrors.ErrorKindTimeout,
+		"canceled": apperrors.ErrorKindCanceled,
+		"oom":      apperrors.ErrorKindOOM,
+		"internal": apperrors.ErrorKindInternal,
+		"ok":       apperrors.ErrorKindNone,
+	}
+	exec := ExecutionOptions{Mode: CompareSequential}
+	for _, res := range ExecuteCalculationsWithOptions(context.Background(), calculators, 10, fibonacci.Options{}, exec, NullProgressReporter{}, io.Discard) {
+		if res.Kind != want[res.Name] {
+			t.Errorf("%s: kind %v, want %v (err %v)", res.Name, res.Kind, want[res.Name], res.Err)
+		}
+	}
+}
+
 // DiscardWriter is a helper that implements io.Writer and discards all data.
 type DiscardWriter struct{}
 
 func (d *DiscardWriter) Write(p []byte) (n int, err error) {
 	return len(p), nil
 }
+
+// TestExecuteCalculationsWithMode verifies the scheduling of each compare mode
+// by tracking how many calculators run at the same time.
+func TestExecuteCalculationsWithMode(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		mode          CompareMode
+		maxConcurrent int32
+	}{
+		{CompareSequential, 1},
+		{CompareParallel, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			t.Parallel()
+			var running, peak atomic.Int32
+			calc := &MockCalculator{
+				CalculateFunc: func(ctx context.Context, reporter progress.ProgressCallback, index int, n uint64, opts fibonacci.Options) (*big.Int, error) {
+					cur := running.Add(1)
+					for {
+						old := peak.Load()
+						if cur <= old || peak.CompareAndSwap(old, cur) {
+							break
+						}
+					}
+					time.Sleep(50 * time.Millisecond)
+					running.Add(-1)
+					return big.NewInt(1), nil
+				},
+			}
+			calculators := []fibonacci.Calculator{calc, calc, calc}
+
+			results := ExecuteCalculationsWithMode(context.Background(), calculators, 10, fibonacci.Options{}, tt.mode, NullProgressReporter{}, io.Discard)
+			for i, r := range results {
+				if r.Err != nil || r.Result == nil {
+					t.Errorf("result %d: unexpected %+v", i, r)
+				}
+			}
+			if got := peak.Load(); got != tt.maxConcurrent {
+				t.Errorf("peak concurrency = %d, want %d", got, tt.maxConcurrent)
+			}
+		})
+	}
+}
+
+// TestExecuteCalculationsFakeClock verifies that the stagger delay and the
+// reported durations follow the injected clock.
+func TestExecuteCalculationsFakeClock(t *testing.T) {
+	t.Parallel()
+	clk := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	var started atomic.Int32
+	calc := &MockCalculator{
+		CalculateFunc: func(ctx context.Context, reporter progress.ProgressCallback, index int, n uint64, opts fibonacci.Options) (*big.Int, error) {
+			started.Add(1)
+			if index == 1 {
+				clk.Advance(3 * time.Second) // simulated work
+			}
+			return big.NewInt(1), nil
+		},
+	}
+	exec := ExecutionOptions{Mode: CompareStaggered, Clock: clk}
+
+	done := make(chan []CalculationResult, 1)
+	go func() {
+		done <- ExecuteCalculationsWithOptions(context.Background(), []fibonacci.Calculator{calc, calc}, 10, fibonacci.Options{}, exec, NullProgressReporter{}, io.Discard)
+	}()
+
+	// The second calculator waits for the stagger delay on the fake clock.
+	for clk.Pending() == 0 {
+		runtime.Gosched()
+	}
+	for started.Load() < 1 {
+		runtime.Gosched()
+	}
+	if got := started.Load(); got != 1 {
+		t.Fatalf("%d calculators started before the stagger delay, want 1", got)
+	}
+	clk.Advance(StaggerDelay)
+
+	results := <-done
+	if results[1].Duration != 3*time.Second {
+		t.Errorf("second duration = %v, want 3s", results[1].Duration)
+	}
+}
+
+// TestExecuteCalculationsPerfCountersForceSequential verifies that enabling
+// hardware counters serializes calculators regardless of the requested mode.
+func TestExecuteCalculationsPerfCountersForceSequential(t *testing.T) {
+	t.Parallel()
+	var running, peak atomic.Int32
+	calc := &MockCalculator{
+		CalculateFunc: func(ctx context.Context, reporter progress.ProgressCallback, index int, n uint64, opts fibonacci.Options) (*big.Int, error) {
+			cur := running.Add(1)
+			for {
+				old := peak.Load()
+				if cur <= old || peak.CompareAndSwap(old, cur) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			running.Add(-1)
+			return big.NewInt(1), nil
+		},
+	}
+	calculators := []fibonacci.Calculator{calc, calc}
+	exec := ExecutionOptions{Mode: CompareParallel, PerfCounters: true}
+
+	results := ExecuteCalculationsWithOptions(context.Background(), calculators, 10, fibonacci.Options{}, exec, NullProgressReporter{}, io.Discard)
+	for i, r := range results {
+		if r.Err != nil || r.Result == nil {
+			t.Errorf("result %d: unexpected %+v", i, r)
+		}
+	}
+	if got := peak.Load(); got != 1 {
+		t.Errorf("peak concurrency = %d, want 1", got)
+	}
+}
+
+// TestExecuteCalculationsEnergy verifies that the energy indicator runs
+// calculators sequentially and attaches a reading to successful results only.
+func TestExecuteCalculationsEnergy(t *testing.T) {
+	t.Parallel()
+	var running, peak atomic.Int32
+	calc := &MockCalculator{
+		CalculateFunc: func(ctx context.Context, reporter progress.ProgressCallback, index int, n uint64, opts fibonacci.Options) (*big.Int, error) {
+			cur := running.Add(1)
+			peak.Store(max(peak.Load(), cur))
+			time.Sleep(10 * time.Millisecond)
+			running.Add(-1)
+			if index == 1 {
+				return nil, errors.New("mock error")
+			}
+			return big.NewInt(1), nil
+		},
+	}
+	exec := ExecutionOptions{Mode: CompareParallel, Energy: true, TDP: 100}
+
+	results := ExecuteCalculationsWithOptions(context.Background(), []fibonacci.Calculator{calc, calc}, 10, fibonacci.Options{}, exec, NullProgressReporter{}, io.Discard)
+	if got := peak.Load(); got != 1 {
+		t.Errorf("peak concurrency = %d, want 1", got)
+	}
+	if e := results[0].Energy; e == nil || e.Joules <= 0 || e.Duration <= 0 {
+		t.Errorf("successful result energy = %+v, want a positive reading", e)
+	}
+	if results[1].Energy != nil {
+		t.Errorf("failed result energy = %+v, want nil", results[1].Energy)
+	}
+}
+
+// observerCalculator reports steps progress updates through a
+// ChannelObserver, like fibonacci.FibCalculator, then signals done.
+type observerCalculator struct {
+	steps int
+	done  *sync.WaitGroup
+}
+
+func (c *observerCalculator) Name() string { return "observer" }
+
+func (c *observerCalculator) Calculate(_ context.Context, progressChan chan<- progress.ProgressUpdate, index int, _ uint64, _ fibonacci.Options) (*big.Int, error) {
+	defer c.done.Done()
+	obs := progress.NewChannelObserver(progressChan)
+	for i := 1; i <= c.steps; i++ {
+		obs.Update(index, float64(i)/float64(c.steps))
+	}
+	return big.NewInt(1), nil
+}
+
+// TestExecuteCalculationsProgressPolicy verifies that the progress policy
+// applies to the orchestrator's channel: with coalesce, a reporter that
+// stalls until the end still receives every algorithm's final update.
+func TestExecuteCalculationsProgressPolicy(t *testing.T) {
+	t.Parallel()
+	var done sync.WaitGroup
+	done.Add(2)
+	calc := &observerCalculator{steps: 100, done: &done}
+
+	last := make(map[int]float64)
+	received := 0
+	reporter := ProgressReporterFunc(func(wg *sync.WaitGroup, ch <-chan progress.ProgressUpdate, _ int, _ io.Writer) {
+		defer wg.Done()
+		done.Wait() // stall until the calculators are finished
+		for u := range ch {
+			last[u.CalculatorIndex] = u.Value
+			received++
+		}
+	})
+	exec := ExecutionOptions{Mode: CompareParallel, ProgressPolicy: progress.PolicyCoalesce}
+	ExecuteCalculationsWithOptions(context.Background(), []fibonacci.Calculator{calc, calc}, 10, fibonacci.Options{}, exec, reporter, io.Discard)
+
+	if last[0] != 1 || last[1] != 1 {
+		t.Errorf("last updates = %v, want 1 for both calculators", last)
+	}
+	if limit := 2 * ProgressBufferMultiplier; received > limit {
+		t.Errorf("received %d updates, want at most the channel capacity %d", received, limit)
+	}
+}
+
+// TestExecuteCalculationsSequentialContinuesAfterFailure verifies that one
+// failing calculator does not cancel the others in sequential mode.
+func TestExecuteCalculationsSequentialContinuesAfterFailure(t *testing.T) {
+	t.Parallel()
+	failing := &MockCalculator{
+		CalculateFunc: func(ctx context.Context, reporter progress.ProgressCallback, index int, n uint64, opts fibonacci.Options) (*big.Int, error) {
+			return nil, errors.New("mock error")
+		},
+	}
+	ok := &MockCalculator{
+		CalculateFunc: func(ctx context.Context, reporter progress.ProgressCallback, index int, n uint64, opts fibonacci.Options) (*big.Int, error) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			return big.NewInt(1), nil
+		},
+	}
+
+	results := ExecuteCalculationsWithMode(context.Background(), []fibonacci.Calculator{failing, ok}, 10, fibonacci.Options{}, CompareSequential, NullProgressReporter{}, io.Discard)
+	if results[0].Err == nil {
+		t.Error("expected first calculator to fail")
+	}
+	if results[1].Err != nil {
+		t.Errorf("second calculator should still succeed, got %v", results[1].Err)
+	}
+}
+
+// TestParseCompareMode verifies compare mode parsing.
+func TestParseCompareMode(t *testing.T) {
+	t.Parallel()
+	for _, m := range CompareModes {
+		if got, err := ParseCompareMode(string(m)); err != nil || got != m {
+			t.Errorf("ParseCompareMode(%q) = %q, %v", m, got, err)
+		}
+	}
+	if got, err := ParseCompareMode(""); err != nil || got != CompareParallel {
+		t.Errorf("ParseCompareMode(\"\") = %q, %v; want parallel", got, err)
+	}
+	if _, err := ParseCompareMode("random"); err == nil {
+		t.Error("expected error for unknown mode")
+	}
+}
+
+// TestExecuteCalculationsRecoversPanic verifies that a panicking calculator
+// yields an error result instead of crashing the run.
+func TestExecuteCalculationsRecoversPanic(t *testing.T) {
+	t.Parallel()
+	calc := &MockCalculator{
+		NameFunc: func() string { return "panicky" },
+		CalculateFunc: func(ctx context.Context, reporter progress.ProgressCallback, index int, n uint64, opts fibonacci.Options) (*big.Int, error) {
+			panic("boom")
+		},
+	}
+
+	results := ExecuteCalculations(context.Background(), []fibonacci.Calculator{calc}, 10, fibonacci.Options{}, NullProgressReporter{}, io.Discard)
+	if len(results) != 1 || results[0].Err == nil {
+		t.Fatalf("results = %+v, want one error result", results)
+	}
+	if msg := results[0].Err.Error(); !strings.Contains(msg, "panicky") || !strings.Contains(msg, "panic: boom") {
+		t.Errorf("error = %q, want calculator name and panic value", msg)
+	}
+}
+
+// TestExecuteCalculationsReportsCacheStats verifies that a calculation that
+// goes through the cached FFT path reports its transform cache statistics,
+// and that one that never consults the cache reports none.
+func TestExecuteCalculationsReportsCacheStats(t *testing.T) {
+	t.Parallel()
+	factory := fibonacci.NewDefaultFactory()
+	calc, err := factory.Get("matrix")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	enabled := true
+	opts := fibonacci.Options{FFTThreshold: 10_000, FFTCacheEnabled: &enabled, FFTCacheMinBitLen: 1000}
+
+	results := ExecuteCalculations(context.Background(), []fibonacci.Calculator{calc}, 2_000_000, opts, NullProgressReporter{}, io.Discard)
+	if results[0].Err != nil {
+		t.Fatalf("unexpected error: %v", results[0].Err)
+	}
+	if results[0].CacheStats == nil {
+		t.Fatal("expected cache stats for a matrix calculation above the FFT threshold")
+	}
+
+	results = ExecuteCalculations(context.Background(), []fibonacci.Calculator{calc}, 50, opts, NullProgressReporter{}, io.Discard)
+	if results[0].CacheStats != nil {
+		t.Errorf("expected no cache stats for a small n, got %+v", *results[0].CacheStats)
+	}
+}
+
+func TestExecuteCalculationsInjectsFailures(t *testing.T) {
+	t.Parallel()
+	calculators := []fibonacci.Calculator{
+		&fibonacci.MockCalculator{Result: big.NewInt(55)},
+		&fibonacci.MockCalculator{Result: big.NewInt(55)},
+	}
+	exec := ExecutionOptions{
+		Mode:    CompareSequential,
+		Failure: &fibonacci.FailureInjection{Mode: fibonacci.FailMismatch},
+	}
+	results := ExecuteCalculationsWithOptions(context.Background(), calculators, 10, fibonacci.Options{}, exec, NullProgressReporter{}, io.Discard)
+
+	if results[0].Result.Cmp(results[1].Result) == 0 {
+		t.Errorf("results should disagree with an injected mismatch: %v, %v", results[0].Result, results[1].Result)
+	}
+}
+
+// TestExecuteCalculationsWarmup verifies that each calculator runs Warmup
+// times on the capped index, without progress, before the measured run.
+func TestExecuteCalculationsWarmup(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	var calls []uint64
+	calc := &MockCalculator{
+		CalculateFunc: func(ctx context.Context, reporter progress.ProgressCallback, index int, n uint64, opts fibonacci.Options) (*big.Int, error) {
+			mu.Lock()
+			calls = append(calls, n)
+			mu.Unlock()
+			reporter(1)
+			return big.NewInt(1), nil
+		},
+	}
+	const n = 10 * WarmupMaxN
+	exec := ExecutionOptions{Mode: CompareSequential, Warmup: 2}
+
+	results := ExecuteCalculationsWithOptions(context.Background(), []fibonacci.Calculator{calc, calc}, n, fibonacci.Options{}, exec, NullProgressReporter{}, io.Discard)
+	for i, r := range results {
+		if r.Err != nil {
+			t.Errorf("result %d: unexpected error %v", i, r.Err)
+		}
+	}
+	want := []uint64{WarmupMaxN, WarmupMaxN, WarmupMaxN, WarmupMaxN, n, n}
+	if !slices.Equal(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
+
+// TestExecuteCalculationsWarmupOutsideTimeout verifies that the warm-up
+// runs do not use up the timeout of the measured run.
+func TestExecuteCalculationsWarmupOutsideTimeout(t *testing.T) {
+	t.Parallel()
+	calc := &MockCalculator{
+		CalculateFunc: func(ctx context.Context, reporter progress.ProgressCallback, index int, n uint64, opts fibonacci.Options) (*big.Int, error) {
+			select {
+			case <-time.After(40 * time.Millisecond):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			return big.NewInt(1), nil
+		},
+	}
+	// Three warm-up runs take longer than the timeout, the measured run not
+	ctx, cancel := WithExtendableTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	exec := ExecutionOptions{Mode: CompareSequential, Warmup: 3}
+
+	results := ExecuteCalculationsWithOptions(ctx, []fibonacci.Calculator{calc}, 10, fibonacci.Options{}, exec, NullProgressReporter{}, io.Discard)
+	if results[0].Err != nil {
+		t.Errorf("measured run failed after the warm-up: %v", results[0].Err)
+	}
+}
+
+// TestWarmupIndex verifies that the warm-up index is capped at WarmupMaxN.
+func TestWarmupIndex(t *testing.T) {
+	t.Parallel()
+	if got := WarmupIndex(100); got != 100 {
+		t.Errorf("WarmupIndex(100) = %d, want 100", got)
+	}
+	if got := WarmupIndex(WarmupMaxN * 5); got != WarmupMaxN {
+		t.Errorf("WarmupIndex(%d) = %d, want %d", WarmupMaxN*5, got, WarmupMaxN)
+	}
+}
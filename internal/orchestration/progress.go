@@ -3,6 +3,7 @@ package orchestration
 import (
 	"time"
 
+	"github.com/agbru/fibcalc/internal/clock"
 	"github.com/agbru/fibcalc/internal/format"
 	"github.com/agbru/fibcalc/internal/progress"
 )
@@ -19,11 +20,17 @@ type ProgressAggregator struct {
 // NewProgressAggregator creates a new aggregator for the given number
 // of calculators. Returns nil if numCalculators <= 0.
 func NewProgressAggregator(numCalculators int) *ProgressAggregator {
+	return NewProgressAggregatorClock(numCalculators, clock.Real{})
+}
+
+// NewProgressAggregatorClock is NewProgressAggregator with an explicit
+// time source for the ETA (the wall clock if clk is nil).
+func NewProgressAggregatorClock(numCalculators int, clk clock.Clock) *ProgressAggregator {
 	if numCalculators <= 0 {
 		return nil
 	}
 	return &ProgressAggregator{
-		state:          format.NewProgressWithETA(numCalculators),
+		state:          format.NewProgressWithETAClock(numCalculators, clk),
 		numCalculators: numCalculators,
 	}
 }
@@ -38,16 +45,23 @@ type AggregatedProgress struct {
 	AverageProgress float64
 	// ETA is the estimated time remaining based on smoothed progress rate.
 	ETA time.Duration
+	// ETALow and ETAHigh bound the ETA once the rate variance is known.
+	// Both are 0 while the estimate is still warming up.
+	ETALow  time.Duration
+	ETAHigh time.Duration
 }
 
 // Update processes a single progress update and returns the aggregated result.
 func (a *ProgressAggregator) Update(update progress.ProgressUpdate) AggregatedProgress {
 	avgProgress, eta := a.state.UpdateWithETA(update.CalculatorIndex, update.Value)
+	low, high := a.state.GetETARange()
 	return AggregatedProgress{
 		CalculatorIndex: update.CalculatorIndex,
 		Value:           update.Value,
 		AverageProgress: avgProgress,
 		ETA:             eta,
+		ETALow:          low,
+		ETAHigh:         high,
 	}
 }
 
@@ -63,6 +77,12 @@ func (a *ProgressAggregator) GetETA() time.Duration {
 	return a.state.GetETA()
 }
 
+// GetETARange returns the current ETA confidence range without updating.
+// Both bounds are 0 until enough samples have been observed.
+func (a *ProgressAggregator) GetETARange() (low, high time.Duration) {
+	return a.state.GetETARange()
+}
+
 // NumCalculators returns the number of calculators being tracked.
 func (a *ProgressAggregator) NumCalculators() int {
 	return a.numCalculators
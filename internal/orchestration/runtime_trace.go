@@ -0,0 +1,220 @@
+package orchestration
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"runtime/trace"
+	"sync"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/clock"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/fsguard"
+	"github.com/agbru/fibcalc/internal/progress"
+)
+
+// RuntimeTraceOptions configures a RuntimeTrace.
+type RuntimeTraceOptions struct {
+	// Path is the file the execution trace is written to.
+	Path string
+	// Window bounds the length of the capture.
+	Window time.Duration
+	// Clock is the time source; nil means the wall clock.
+	Clock clock.Clock
+}
+
+// TraceCapture describes the part of a calculation a RuntimeTrace captured.
+type TraceCapture struct {
+	// Name is the calculation traced; empty if none was.
+	Name string
+	// Progress is the progress of the calculation when the capture started.
+	Progress float64
+	// Steps is the number of steps reported during the capture, FirstBit
+	// and LastBit the bits of the first and last of them, of Bits.
+	Steps             int
+	FirstBit, LastBit int
+	Bits              int
+	// Duration is the length of the capture.
+	Duration time.Duration
+}
+
+// traceState is the stage of a RuntimeTrace, which captures once.
+type traceState int
+
+const (
+	traceIdle traceState = iota
+	traceRunning
+	traceDone
+)
+
+// RuntimeTrace captures a runtime/trace execution trace of the heaviest
+// steps of a calculation, for `go tool trace`. The work of a doubling step
+// grows with each bit, so the last steps dominate: from the progress
+// reports of each calculation it watches (see fibonacci.Options.Heartbeat),
+// the RuntimeTrace estimates the time left, assuming the remaining work
+// proceeds at the average rate so far, and starts tracing once it fits in
+// the window, or would no longer fit by the next report: the intervals
+// between reports grow like the steps. The capture stops when that calculation returns or the window
+// has elapsed, whichever comes first. The execution trace is process-wide,
+// so there is a single capture, of the first calculation to reach its
+// heaviest steps. A RuntimeTrace is safe for concurrent use.
+type RuntimeTrace struct {
+	opts RuntimeTraceOptions
+	clk  clock.Clock
+
+	mu      sync.Mutex
+	state   traceState
+	traced  *tracedRun
+	file    *os.File
+	started time.Time
+	capture TraceCapture
+	err     error
+	// done is closed when the capture stops.
+	done chan struct{}
+}
+
+// tracedRun is a calculation followed by a RuntimeTrace.
+type tracedRun struct {
+	name  string
+	start time.Time
+	// lastBeat is the time of the last progress report.
+	lastBeat time.Time
+}
+
+// NewRuntimeTrace returns a runtime trace capture, which follows the
+// calculations it is given through ExecutionOptions.RuntimeTrace.
+//
+// Parameters:
+//   - opts: The configuration.
+//
+// Returns:
+//   - *RuntimeTrace: The capture, not started.
+func NewRuntimeTrace(opts RuntimeTraceOptions) *RuntimeTrace {
+	return &RuntimeTrace{opts: opts, clk: clock.Or(opts.Clock), done: make(chan struct{})}
+}
+
+// Close stops the capture if it is still running.
+//
+// Returns:
+//   - TraceCapture: The capture, whose Name is empty if no calculation
+//     reached its heaviest steps.
+//   - error: An error if the trace could not be started or written.
+func (t *RuntimeTrace) Close() (TraceCapture, error) {
+	t.stop(t.clk.Now())
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.state = traceDone
+	return t.capture, t.err
+}
+
+// heartbeat starts the capture if the calculation r, at progress p, is
+// expected to end within the window, or before the window would start
+// after its next report.
+func (t *RuntimeTrace) heartbeat(r *tracedRun, p float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.clk.Now()
+	next := stallGrowth * now.Sub(r.lastBeat)
+	r.lastBeat = now
+	if t.state != traceIdle || p <= 0 || p >= 1 {
+		return
+	}
+	// In float64: early on the estimate overflows a Duration
+	remaining := float64(now.Sub(r.start)) * (1 - p) / p
+	if remaining > float64(t.opts.Window+next) {
+		return
+	}
+
+	t.state = traceDone
+	f, err := fsguard.Create(t.opts.Path)
+	if err != nil {
+		t.err = err
+		return
+	}
+	if err := trace.Start(f); err != nil {
+		f.Close()
+		fsguard.Remove(t.opts.Path)
+		t.err = err
+		return
+	}
+	t.state, t.traced, t.file, t.started = traceRunning, r, f, now
+	t.capture = TraceCapture{Name: r.name, Progress: p}
+	deadline := t.clk.After(t.opts.Window)
+	go func() {
+		select {
+		case end := <-deadline:
+			t.stop(end)
+		case <-t.done:
+		}
+	}()
+}
+
+// step records a step of the calculation r during the capture.
+func (t *RuntimeTrace) step(r *tracedRun, e fibonacci.StepEvent) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.state != traceRunning || t.traced != r {
+		return
+	}
+	if t.capture.Steps == 0 {
+		t.capture.FirstBit = e.Bit
+	}
+	t.capture.Steps++
+	t.capture.LastBit, t.capture.Bits = e.Bit, e.Bits
+}
+
+// stop ends the capture, at end, if it is running.
+func (t *RuntimeTrace) stop(end time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.state != traceRunning {
+		return
+	}
+	trace.Stop()
+	if err := t.file.Close(); err != nil {
+		t.err = err
+	}
+	t.capture.Duration = end.Sub(t.started)
+	t.state = traceDone
+	close(t.done)
+}
+
+// middleware follows each calculation it runs, and ends the capture when
+// the traced calculation returns. While tracing, the steps are also logged
+// in the trace, under the "step" category, to locate them in the timeline.
+func (t *RuntimeTrace) middleware() fibonacci.Middleware {
+	return fibonacci.NewMiddleware(func(name string, next fibonacci.CalculateFunc) fibonacci.CalculateFunc {
+		return func(ctx context.Context, progressChan chan<- progress.ProgressUpdate, calcIndex int, n uint64, opts fibonacci.Options) (*big.Int, error) {
+			now := t.clk.Now()
+			r := &tracedRun{name: name, start: now, lastBeat: now}
+			defer func() {
+				t.mu.Lock()
+				traced := t.traced == r
+				t.mu.Unlock()
+				if traced {
+					t.stop(t.clk.Now())
+				}
+			}()
+
+			heartbeat, onStep := opts.Heartbeat, opts.OnStep
+			opts.Heartbeat = func(p float64) {
+				t.heartbeat(r, p)
+				if heartbeat != nil {
+					heartbeat(p)
+				}
+			}
+			opts.OnStep = func(e fibonacci.StepEvent) {
+				if trace.IsEnabled() {
+					t.step(r, e)
+					trace.Log(ctx, "step", fmt.Sprintf("%s: %s to k = %d at bit %d of %d", name, stepNames[e.Kind], e.K, e.Bit, e.Bits))
+				}
+				if onStep != nil {
+					onStep(e)
+				}
+			}
+			return next(ctx, progressChan, calcIndex, n, opts)
+		}
+	})
+}
@@ -0,0 +1,102 @@
+package orchestration
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/clock"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/progress"
+)
+
+// tracedCalculation runs calc under the runtime trace in a goroutine and
+// returns the channel of its error.
+func tracedCalculation(rt *RuntimeTrace, calc func(opts fibonacci.Options)) <-chan error {
+	wrapped := fibonacci.WrapCalculator(&MockCalculator{
+		CalculateFunc: func(_ context.Context, _ progress.ProgressCallback, _ int, _ uint64, opts fibonacci.Options) (*big.Int, error) {
+			calc(opts)
+			return big.NewInt(1), nil
+		},
+	}, rt.middleware())
+	errc := make(chan error, 1)
+	go func() {
+		_, err := wrapped.Calculate(context.Background(), nil, 0, 100, fibonacci.Options{})
+		errc <- err
+	}()
+	return errc
+}
+
+// Runtime traces are process-wide, so these tests do not run in parallel.
+
+func TestRuntimeTraceHeaviestSteps(t *testing.T) {
+	clk := clock.NewFake(time.Unix(0, 0))
+	path := filepath.Join(t.TempDir(), "trace.out")
+	rt := NewRuntimeTrace(RuntimeTraceOptions{Path: path, Window: 30 * time.Second, Clock: clk})
+	errc := tracedCalculation(rt, func(opts fibonacci.Options) {
+		// 10s for 10% leaves 90s: more than the window and the next
+		// report, expected 40s later
+		clk.Advance(10 * time.Second)
+		opts.Heartbeat(0.1)
+		opts.OnStep(fibonacci.StepEvent{Kind: fibonacci.StepDoubling, Bit: 2, Bits: 4})
+		// 20s for 40% leaves 30s: within the window
+		clk.Advance(10 * time.Second)
+		opts.Heartbeat(0.4)
+		opts.OnStep(fibonacci.StepEvent{Kind: fibonacci.StepDoubling, Bit: 1, Bits: 4})
+		clk.Advance(15 * time.Second)
+		opts.OnStep(fibonacci.StepEvent{Kind: fibonacci.StepDoubling, Bit: 0, Bits: 4})
+	})
+	if err := <-errc; err != nil {
+		t.Fatalf("Calculate() error = %v", err)
+	}
+
+	capture, err := rt.Close()
+	if err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	want := TraceCapture{Name: "Mock", Progress: 0.4, Steps: 2, FirstBit: 1, LastBit: 0, Bits: 4, Duration: 15 * time.Second}
+	if capture != want {
+		t.Errorf("Close() = %+v, want %+v", capture, want)
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+		t.Errorf("trace file not written: %v", err)
+	}
+}
+
+func TestRuntimeTraceWindow(t *testing.T) {
+	clk := clock.NewFake(time.Unix(0, 0))
+	path := filepath.Join(t.TempDir(), "trace.out")
+	rt := NewRuntimeTrace(RuntimeTraceOptions{Path: path, Window: 10 * time.Second, Clock: clk})
+	errc := tracedCalculation(rt, func(opts fibonacci.Options) {
+		clk.Advance(time.Second)
+		opts.Heartbeat(0.5)
+		// The estimate was short: the window ends the capture
+		clk.Advance(time.Minute)
+		<-rt.done
+	})
+	if err := <-errc; err != nil {
+		t.Fatalf("Calculate() error = %v", err)
+	}
+	capture, err := rt.Close()
+	if err != nil || capture.Duration != 10*time.Second {
+		t.Errorf("Close() = %+v, %v, want a 10s capture", capture, err)
+	}
+}
+
+func TestRuntimeTraceNoCapture(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.out")
+	rt := NewRuntimeTrace(RuntimeTraceOptions{Path: path, Window: time.Second})
+	errc := tracedCalculation(rt, func(fibonacci.Options) {})
+	if err := <-errc; err != nil {
+		t.Fatalf("Calculate() error = %v", err)
+	}
+	if capture, err := rt.Close(); err != nil || capture.Name != "" {
+		t.Errorf("Close() = %+v, %v, want no capture", capture, err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("trace file created without a capture: %v", err)
+	}
+}
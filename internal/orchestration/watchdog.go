@@ -0,0 +1,282 @@
+package orchestration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"runtime/pprof"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/clock"
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/progress"
+)
+
+// MinStallPeriod is the shortest stall period. The first steps take
+// microseconds, and below a few seconds scheduling, GC pauses or a busy
+// machine would pass for stalls.
+const MinStallPeriod = 5 * time.Second
+
+// stallGrowth is the expected ratio between an interval between progress
+// reports and the longest one before it: the work of a doubling step
+// roughly quadruples with each bit (see progress.CalcTotalWork).
+const stallGrowth = 4
+
+// StallGrace is how long a calculation aborted by the watchdog has to
+// return. One that does not is stuck where it does not check its context,
+// such as a deadlock between the goroutines of a parallel multiplication.
+const StallGrace = 10 * time.Second
+
+// DefaultWatchdogInterval is the checking period of Watchdog.Run.
+const DefaultWatchdogInterval = 500 * time.Millisecond
+
+// WatchdogOptions configures a Watchdog.
+type WatchdogOptions struct {
+	// Factor sets the stall period of a calculation: Factor times the time
+	// expected until its next progress report, estimated from the longest
+	// interval between its reports so far, and at least MinStallPeriod.
+	Factor float64
+	// Abort cancels a stalled calculation, which then fails with an
+	// apperrors.StallError; otherwise the stall is only reported.
+	Abort bool
+	// OnStuck, with Abort, is called when an aborted calculation has not
+	// returned after StallGrace.
+	OnStuck func()
+	// Out receives the diagnostic snapshots; nil discards them.
+	Out io.Writer
+	// Clock is the time source; nil means the wall clock.
+	Clock clock.Clock
+}
+
+// Watchdog detects calculations that stop making progress, such as a
+// deadlock in the parallel FFT multiplication. It follows the progress
+// reports and steps of each calculation it watches (see
+// fibonacci.Options.Heartbeat and OnStep); when one reports nothing for its
+// stall period, the watchdog writes a diagnostic snapshot, the phase of the
+// calculation and a dump of every goroutine, and with Abort cancels it. A
+// Watchdog is safe for concurrent use.
+type Watchdog struct {
+	opts WatchdogOptions
+	clk  clock.Clock
+
+	mu     sync.Mutex
+	runs   map[*watchedRun]struct{}
+	stalls int
+}
+
+// watchedRun is the state of a calculation followed by a Watchdog.
+type watchedRun struct {
+	name   string
+	cancel context.CancelCauseFunc
+	start  time.Time
+	// lastBeat is the time of the last progress report or step, and
+	// longest the longest interval between two of them.
+	lastBeat time.Time
+	longest  time.Duration
+	progress float64
+	// step is the last step, without its values, if the algorithm reports
+	// steps.
+	step    fibonacci.StepEvent
+	stepped bool
+	// stalledAt is when the current stall was reported, abortedAt when the
+	// calculation was canceled, zero otherwise.
+	stalledAt time.Time
+	abortedAt time.Time
+	stuck     bool
+}
+
+// NewWatchdog returns a watchdog; call Run to start checking.
+//
+// Parameters:
+//   - opts: The configuration.
+//
+// Returns:
+//   - *Watchdog: The watchdog.
+func NewWatchdog(opts WatchdogOptions) *Watchdog {
+	if opts.Out == nil {
+		opts.Out = io.Discard
+	}
+	return &Watchdog{opts: opts, clk: clock.Or(opts.Clock), runs: make(map[*watchedRun]struct{})}
+}
+
+// Stalls returns the number of stalls detected so far.
+func (w *Watchdog) Stalls() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.stalls
+}
+
+// Run checks the watched calculations every interval until ctx is
+// canceled.
+//
+// Parameters:
+//   - ctx: Stops the checks when canceled.
+//   - interval: The checking period; zero means DefaultWatchdogInterval.
+func (w *Watchdog) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultWatchdogInterval
+	}
+	ticker := w.clk.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C():
+			w.check(now)
+		}
+	}
+}
+
+// period returns the stall period of r. It must be called with w.mu held.
+func (w *Watchdog) period(r *watchedRun) time.Duration {
+	expected := time.Duration(float64(stallGrowth*r.longest) * w.opts.Factor)
+	return max(expected, MinStallPeriod)
+}
+
+// check reports the calculations that have been stalled for longer than
+// their stall period, aborting them with Abort, and the aborted ones that
+// have not returned after StallGrace.
+func (w *Watchdog) check(now time.Time) {
+	type stall struct {
+		run            *watchedRun
+		idle, period   time.Duration
+		phase, elapsed string
+	}
+	var stalls []stall
+	var stuck []*watchedRun
+	w.mu.Lock()
+	for r := range w.runs {
+		if !r.abortedAt.IsZero() {
+			if !r.stuck && now.Sub(r.abortedAt) >= StallGrace {
+				r.stuck = true
+				stuck = append(stuck, r)
+			}
+			continue
+		}
+		idle, period := now.Sub(r.lastBeat), w.period(r)
+		if !r.stalledAt.IsZero() || idle <= period {
+			continue
+		}
+		r.stalledAt = now
+		w.stalls++
+		stalls = append(stalls, stall{r, idle, period, r.phase(), now.Sub(r.start).Round(time.Millisecond).String()})
+		if w.opts.Abort {
+			r.abortedAt = now
+		}
+	}
+	w.mu.Unlock()
+
+	for _, s := range stalls {
+		w.snapshot(s.run.name, s.idle, s.period, s.phase, s.elapsed)
+		if w.opts.Abort {
+			s.run.cancel(apperrors.StallError{Idle: s.idle.Round(time.Millisecond), Period: s.period.Round(time.Millisecond)})
+		}
+	}
+	for _, r := range stuck {
+		fmt.Fprintf(w.opts.Out, "Watchdog: %s has not returned %s after it was aborted; it does not check its context.\n", r.name, StallGrace)
+		if w.opts.OnStuck != nil {
+			w.opts.OnStuck()
+		}
+	}
+}
+
+// snapshot writes the diagnostic snapshot of a stalled calculation.
+func (w *Watchdog) snapshot(name string, idle, period time.Duration, phase, elapsed string) {
+	action := "still waiting"
+	if w.opts.Abort {
+		action = "aborting it"
+	}
+	fmt.Fprintf(w.opts.Out, "Watchdog: %s has made no progress for %s (stall period %s); %s.\n",
+		name, idle.Round(time.Millisecond), period.Round(time.Millisecond), action)
+	fmt.Fprintf(w.opts.Out, "  Phase: %s, running for %s.\n", phase, elapsed)
+	fmt.Fprintln(w.opts.Out, "  Goroutines:")
+	_ = pprof.Lookup("goroutine").WriteTo(w.opts.Out, 2)
+}
+
+// stepNames are the names of the step kinds in the snapshots.
+var stepNames = map[fibonacci.StepKind]string{
+	fibonacci.StepDoubling:       "doubling",
+	fibonacci.StepAddition:       "addition",
+	fibonacci.StepMatrixMultiply: "matrix multiplication",
+	fibonacci.StepMatrixSquare:   "matrix squaring",
+}
+
+// phase describes where the calculation stands. It must be called with
+// w.mu held.
+func (r *watchedRun) phase() string {
+	parts := []string{fmt.Sprintf("progress %.1f%%", 100*r.progress)}
+	if r.stepped {
+		parts = append(parts, fmt.Sprintf("last step %s to k = %d at bit %d of %d",
+			stepNames[r.step.Kind], r.step.K, r.step.Bit, r.step.Bits))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// beat records a progress report or a step of r. It must be called with
+// w.mu held.
+func (w *Watchdog) beat(r *watchedRun) {
+	now := w.clk.Now()
+	idle := now.Sub(r.lastBeat)
+	r.longest = max(r.longest, idle)
+	r.lastBeat = now
+	if !r.stalledAt.IsZero() && r.abortedAt.IsZero() {
+		fmt.Fprintf(w.opts.Out, "Watchdog: %s is making progress again, after %s without.\n", r.name, idle.Round(time.Millisecond))
+		r.stalledAt = time.Time{}
+	}
+}
+
+// middleware watches each calculation it runs, from its start to its
+// return. A calculation aborted by the watchdog fails with the
+// apperrors.StallError instead of the cancellation.
+func (w *Watchdog) middleware() fibonacci.Middleware {
+	return fibonacci.NewMiddleware(func(name string, next fibonacci.CalculateFunc) fibonacci.CalculateFunc {
+		return func(ctx context.Context, progressChan chan<- progress.ProgressUpdate, calcIndex int, n uint64, opts fibonacci.Options) (*big.Int, error) {
+			ctx, cancel := context.WithCancelCause(ctx)
+			defer cancel(nil)
+			now := w.clk.Now()
+			r := &watchedRun{name: name, cancel: cancel, start: now, lastBeat: now}
+			w.mu.Lock()
+			w.runs[r] = struct{}{}
+			w.mu.Unlock()
+			defer func() {
+				w.mu.Lock()
+				delete(w.runs, r)
+				w.mu.Unlock()
+			}()
+
+			heartbeat, onStep := opts.Heartbeat, opts.OnStep
+			opts.Heartbeat = func(p float64) {
+				w.mu.Lock()
+				r.progress = p
+				w.beat(r)
+				w.mu.Unlock()
+				if heartbeat != nil {
+					heartbeat(p)
+				}
+			}
+			opts.OnStep = func(e fibonacci.StepEvent) {
+				w.mu.Lock()
+				r.step, r.stepped = e, true
+				r.step.Values = nil
+				w.beat(r)
+				w.mu.Unlock()
+				if onStep != nil {
+					onStep(e)
+				}
+			}
+
+			result, err := next(ctx, progressChan, calcIndex, n, opts)
+			var stall apperrors.StallError
+			if err != nil && errors.As(context.Cause(ctx), &stall) {
+				return nil, stall
+			}
+			return result, err
+		}
+	})
+}
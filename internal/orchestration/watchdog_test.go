@@ -0,0 +1,146 @@
+package orchestration
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/clock"
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/progress"
+)
+
+// watchedCalculation runs calc under the watchdog in a goroutine and
+// returns the channel of its error.
+func watchedCalculation(w *Watchdog, calc func(ctx context.Context, opts fibonacci.Options) error) <-chan error {
+	wrapped := fibonacci.WrapCalculator(&MockCalculator{
+		CalculateFunc: func(ctx context.Context, _ progress.ProgressCallback, _ int, _ uint64, opts fibonacci.Options) (*big.Int, error) {
+			return big.NewInt(1), calc(ctx, opts)
+		},
+	}, w.middleware())
+	errc := make(chan error, 1)
+	go func() {
+		_, err := wrapped.Calculate(context.Background(), nil, 0, 100, fibonacci.Options{})
+		errc <- err
+	}()
+	return errc
+}
+
+func TestWatchdogAbort(t *testing.T) {
+	t.Parallel()
+	clk := clock.NewFake(time.Unix(0, 0))
+	var out bytes.Buffer
+	w := NewWatchdog(WatchdogOptions{Factor: 10, Abort: true, Out: &out, Clock: clk})
+	started := make(chan struct{})
+	errc := watchedCalculation(w, func(ctx context.Context, opts fibonacci.Options) error {
+		opts.Heartbeat(0.5)
+		opts.OnStep(fibonacci.StepEvent{Kind: fibonacci.StepDoubling, Bit: 3, Bits: 20, K: 42})
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	<-started
+
+	// The stall period is MinStallPeriod until an interval is measured
+	clk.Advance(MinStallPeriod)
+	w.check(clk.Now())
+	if w.Stalls() != 0 {
+		t.Fatal("stall reported at the stall period")
+	}
+	clk.Advance(time.Second)
+	w.check(clk.Now())
+	err := <-errc
+	var stall apperrors.StallError
+	if !errors.As(err, &stall) || apperrors.KindOf(err) != apperrors.ErrorKindStalled || stall.Idle != 6*time.Second {
+		t.Fatalf("Calculate() error = %v, want a 6s stall", err)
+	}
+	for _, want := range []string{
+		"Mock has made no progress for 6s (stall period 5s); aborting it.",
+		"Phase: progress 50.0%, last step doubling to k = 42 at bit 3 of 20",
+		"goroutine ",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("snapshot lacks %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestWatchdogStuck(t *testing.T) {
+	t.Parallel()
+	clk := clock.NewFake(time.Unix(0, 0))
+	var out bytes.Buffer
+	stuck := make(chan struct{})
+	w := NewWatchdog(WatchdogOptions{Factor: 10, Abort: true, Out: &out, Clock: clk, OnStuck: func() { close(stuck) }})
+	started, release := make(chan struct{}), make(chan struct{})
+	errc := watchedCalculation(w, func(context.Context, fibonacci.Options) error {
+		close(started)
+		<-release // deaf to its context
+		return nil
+	})
+	<-started
+
+	clk.Advance(MinStallPeriod + time.Second)
+	w.check(clk.Now())
+	clk.Advance(StallGrace)
+	w.check(clk.Now())
+	select {
+	case <-stuck:
+	default:
+		t.Fatalf("OnStuck was not called:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "Mock has not returned 10s after it was aborted") {
+		t.Errorf("output lacks the stuck calculation:\n%s", out.String())
+	}
+	close(release)
+	if err := <-errc; apperrors.KindOf(err) != apperrors.ErrorKindNone {
+		t.Errorf("Calculate() error = %v, want the result it returned", err)
+	}
+}
+
+func TestWatchdogResume(t *testing.T) {
+	t.Parallel()
+	clk := clock.NewFake(time.Unix(0, 0))
+	var out bytes.Buffer
+	w := NewWatchdog(WatchdogOptions{Factor: 10, Out: &out, Clock: clk})
+	started, beat, beaten := make(chan struct{}), make(chan struct{}), make(chan struct{})
+	errc := watchedCalculation(w, func(_ context.Context, opts fibonacci.Options) error {
+		close(started)
+		for range beat {
+			opts.Heartbeat(0.5)
+			beaten <- struct{}{}
+		}
+		return nil
+	})
+	<-started
+
+	// A 2s interval sets the stall period to 10 × 4 × 2s
+	clk.Advance(2 * time.Second)
+	beat <- struct{}{}
+	<-beaten
+	clk.Advance(80 * time.Second)
+	w.check(clk.Now())
+	if w.Stalls() != 0 {
+		t.Fatalf("stall reported within the stall period:\n%s", out.String())
+	}
+	clk.Advance(time.Second)
+	w.check(clk.Now())
+	w.check(clk.Now())
+	if w.Stalls() != 1 || !strings.Contains(out.String(), "stall period 1m20s); still waiting.") {
+		t.Fatalf("Stalls() = %d with:\n%s", w.Stalls(), out.String())
+	}
+
+	beat <- struct{}{}
+	<-beaten
+	close(beat)
+	if err := <-errc; err != nil {
+		t.Fatalf("Calculate() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Mock is making progress again, after 1m21s without.") {
+		t.Errorf("output lacks the resumption:\n%s", out.String())
+	}
+}
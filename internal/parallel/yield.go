@@ -0,0 +1,91 @@
+package parallel
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// YieldSlice is the longest a compute loop runs without yielding the
+// processor when cooperative yielding is needed (see MaybeYield).
+const YieldSlice = 5 * time.Millisecond
+
+// epoch is the reference for the monotonic timestamps of lastYield.
+var epoch = time.Now()
+
+// lastYield is the time of the last yield, in nanoseconds since epoch.
+var lastYield atomic.Int64
+
+// yields counts the yields, for tests.
+var yields atomic.Uint64
+
+// singleProcessor records whether GOMAXPROCS is 1, as of the last
+// RefreshProcessors. MaybeYield cannot ask the runtime itself:
+// runtime.GOMAXPROCS takes the scheduler lock even to read the setting,
+// which would serialize the parallel workers calling it.
+var singleProcessor atomic.Bool
+
+func init() {
+	RefreshProcessors()
+}
+
+// softRealtime counts the calculations running in soft real-time mode
+// (see EnterSoftRealtime).
+var softRealtime atomic.Int32
+
+// cooperativeOS reports whether the platform has no preemption of running
+// goroutines, so that long loops must yield explicitly.
+const cooperativeOS = runtime.GOOS == "js" || runtime.GOOS == "wasip1"
+
+// NeedsYield reports whether compute loops must yield explicitly for other
+// goroutines (progress display, TUI, signal handling) to run: when there is
+// a single processor (GOMAXPROCS=1 at the last RefreshProcessors), on
+// WebAssembly, which does not preempt running goroutines, or while a
+// calculation runs in soft real-time mode.
+//
+// Returns:
+//   - bool: True if MaybeYield yields.
+func NeedsYield() bool {
+	return cooperativeOS || softRealtime.Load() > 0 || singleProcessor.Load()
+}
+
+// RefreshProcessors reads GOMAXPROCS again for NeedsYield. It is called at
+// program start, at the start of each calculation and after fibcalc
+// changes GOMAXPROCS, rather than in the compute loops.
+func RefreshProcessors() {
+	singleProcessor.Store(runtime.GOMAXPROCS(0) == 1)
+}
+
+// EnterSoftRealtime makes compute loops yield every YieldSlice whatever the
+// number of processors, until the returned function is called, so that the
+// goroutines refreshing a display wait at most about a slice for a
+// processor instead of a scheduler time slice per busy worker. Calls nest:
+// loops keep yielding while any calculation is in soft real-time mode.
+//
+// Returns:
+//   - func(): Leaves soft real-time mode; calls after the first do nothing.
+func EnterSoftRealtime() (exit func()) {
+	softRealtime.Add(1)
+	var once sync.Once
+	return func() {
+		once.Do(func() { softRealtime.Add(-1) })
+	}
+}
+
+// MaybeYield yields the processor if NeedsYield and YieldSlice has elapsed
+// since the last yield. It is cheap enough to call once per unit of work in
+// a compute loop (a pointwise product, a transform pass, a doubling step):
+// when several processors are available, it only reads two atomics.
+func MaybeYield() {
+	if !NeedsYield() {
+		return
+	}
+	now := int64(time.Since(epoch))
+	last := lastYield.Load()
+	if now-last < int64(YieldSlice) || !lastYield.CompareAndSwap(last, now) {
+		return
+	}
+	yields.Add(1)
+	runtime.Gosched()
+}
@@ -0,0 +1,99 @@
+package parallel
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+// setMaxProcs sets GOMAXPROCS and refreshes NeedsYield, restoring both at
+// the end of the test.
+func setMaxProcs(t *testing.T, n int) {
+	prev := runtime.GOMAXPROCS(n)
+	RefreshProcessors()
+	t.Cleanup(func() {
+		runtime.GOMAXPROCS(prev)
+		RefreshProcessors()
+	})
+}
+
+// TestMaybeYield verifies that MaybeYield yields once per time slice on a
+// single processor, and never with several processors.
+func TestMaybeYield(t *testing.T) {
+	setMaxProcs(t, 1)
+
+	if !NeedsYield() {
+		t.Fatal("NeedsYield() = false with GOMAXPROCS=1")
+	}
+	lastYield.Store(int64(time.Since(epoch)) - 2*int64(YieldSlice))
+	before := yields.Load()
+	MaybeYield()
+	if got := yields.Load() - before; got != 1 {
+		t.Errorf("MaybeYield after a full slice yielded %d times, want 1", got)
+	}
+	MaybeYield()
+	if got := yields.Load() - before; got != 1 {
+		t.Errorf("MaybeYield within the slice yielded again (%d yields)", got)
+	}
+
+	if cooperativeOS || runtime.NumCPU() < 2 {
+		return
+	}
+	runtime.GOMAXPROCS(2)
+	RefreshProcessors()
+	lastYield.Store(0)
+	before = yields.Load()
+	MaybeYield()
+	if got := yields.Load() - before; got != 0 {
+		t.Errorf("MaybeYield with GOMAXPROCS=2 yielded %d times, want 0", got)
+	}
+}
+
+// TestRefreshProcessors verifies that NeedsYield follows GOMAXPROCS as of
+// the last RefreshProcessors, without querying the runtime itself.
+func TestRefreshProcessors(t *testing.T) {
+	if cooperativeOS || runtime.NumCPU() < 2 {
+		t.Skip("yielding is always needed here")
+	}
+	setMaxProcs(t, 2)
+
+	runtime.GOMAXPROCS(1)
+	if NeedsYield() {
+		t.Error("NeedsYield() changed before RefreshProcessors")
+	}
+	RefreshProcessors()
+	if !NeedsYield() {
+		t.Error("NeedsYield() = false after RefreshProcessors with GOMAXPROCS=1")
+	}
+}
+
+// TestEnterSoftRealtime verifies that soft real-time mode makes compute
+// loops yield with several processors, until every caller has left it.
+func TestEnterSoftRealtime(t *testing.T) {
+	if cooperativeOS || runtime.NumCPU() < 2 {
+		t.Skip("yielding is always needed here")
+	}
+	setMaxProcs(t, 2)
+
+	exitFirst := EnterSoftRealtime()
+	exitSecond := EnterSoftRealtime()
+	if !NeedsYield() {
+		t.Fatal("NeedsYield() = false in soft real-time mode")
+	}
+	lastYield.Store(int64(time.Since(epoch)) - 2*int64(YieldSlice))
+	before := yields.Load()
+	MaybeYield()
+	if got := yields.Load() - before; got != 1 {
+		t.Errorf("MaybeYield in soft real-time mode yielded %d times, want 1", got)
+	}
+
+	exitFirst()
+	exitFirst()
+	if !NeedsYield() {
+		t.Error("leaving twice ended the other caller's soft real-time mode")
+	}
+	exitSecond()
+	if NeedsYield() {
+		t.Error("NeedsYield() = true after every caller left soft real-time mode")
+	}
+}
@@ -0,0 +1,57 @@
+// Package perfevent samples hardware performance counters (last-level cache
+// misses and references) around a calculation so that the memory behaviour of
+// different algorithms can be compared. Counters are only available on Linux
+// with perf_event_open support; other platforms report ErrUnsupported.
+package perfevent
+
+import (
+	"errors"
+	"time"
+)
+
+// CacheLineSize is the number of bytes assumed to be transferred from memory
+// for each last-level cache miss when estimating bandwidth.
+const CacheLineSize = 64
+
+// ErrUnsupported is returned by Start when hardware counters cannot be used
+// on the current platform.
+var ErrUnsupported = errors.New("perfevent: hardware counters are not supported on this platform")
+
+// Counts holds the hardware counter values collected during a session.
+type Counts struct {
+	// CacheMisses is the number of last-level cache misses.
+	CacheMisses uint64
+	// CacheReferences is the number of last-level cache accesses.
+	CacheReferences uint64
+	// Duration is the wall-clock time the counters were enabled.
+	Duration time.Duration
+}
+
+// MissRate returns the fraction of cache references that missed, or 0 when
+// no references were recorded.
+func (c Counts) MissRate() float64 {
+	if c.CacheReferences == 0 {
+		return 0
+	}
+	return float64(c.CacheMisses) / float64(c.CacheReferences)
+}
+
+// Bandwidth estimates the memory bandwidth in bytes per second, assuming
+// every cache miss transfers one CacheLineSize line from memory.
+func (c Counts) Bandwidth() float64 {
+	if c.Duration <= 0 {
+		return 0
+	}
+	return float64(c.CacheMisses) * CacheLineSize / c.Duration.Seconds()
+}
+
+// Available reports whether hardware counters can be opened in the current
+// process. It performs a short trial session.
+func Available() error {
+	s, err := Start()
+	if err != nil {
+		return err
+	}
+	_, err = s.Stop()
+	return err
+}
@@ -0,0 +1,156 @@
+//go:build linux
+
+package perfevent
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// Session is a set of enabled hardware counters. Because Go schedules
+// goroutines across several OS threads, a counter is opened for every thread
+// of the process (with inheritance for threads they spawn), and the values
+// are summed when the session stops.
+type Session struct {
+	misses []int
+	refs   []int
+	start  time.Time
+}
+
+// Start opens and enables cache counters on every thread of the current
+// process. Only user-space events are counted so that the default
+// perf_event_paranoid setting is sufficient.
+//
+// Returns:
+//   - *Session: The running session, to be stopped with Stop.
+//   - error: An error if the counters could not be opened.
+func Start() (*Session, error) {
+	tids, err := threadIDs()
+	if err != nil {
+		return nil, fmt.Errorf("perfevent: list threads: %w", err)
+	}
+	s := &Session{}
+	for _, tid := range tids {
+		missFd, err := openCounter(tid, unix.PERF_COUNT_HW_CACHE_MISSES)
+		if errors.Is(err, unix.ESRCH) {
+			continue // thread exited in the meantime
+		}
+		if err != nil {
+			s.close()
+			return nil, openError(err)
+		}
+		refFd, err := openCounter(tid, unix.PERF_COUNT_HW_CACHE_REFERENCES)
+		if err != nil {
+			unix.Close(missFd)
+			if errors.Is(err, unix.ESRCH) {
+				continue
+			}
+			s.close()
+			return nil, openError(err)
+		}
+		s.misses = append(s.misses, missFd)
+		s.refs = append(s.refs, refFd)
+	}
+	if len(s.misses) == 0 {
+		return nil, ErrUnsupported
+	}
+	s.start = time.Now()
+	for _, fd := range s.fds() {
+		if err := unix.IoctlSetInt(fd, unix.PERF_EVENT_IOC_ENABLE, 0); err != nil {
+			s.close()
+			return nil, fmt.Errorf("perfevent: enable counter: %w", err)
+		}
+	}
+	return s, nil
+}
+
+// Stop disables the counters, reads their totals and releases them.
+//
+// Returns:
+//   - Counts: The summed counter values.
+//   - error: An error if a counter could not be read.
+func (s *Session) Stop() (Counts, error) {
+	defer s.close()
+	for _, fd := range s.fds() {
+		_ = unix.IoctlSetInt(fd, unix.PERF_EVENT_IOC_DISABLE, 0)
+	}
+	c := Counts{Duration: time.Since(s.start)}
+	var err error
+	if c.CacheMisses, err = sumCounters(s.misses); err != nil {
+		return Counts{}, err
+	}
+	if c.CacheReferences, err = sumCounters(s.refs); err != nil {
+		return Counts{}, err
+	}
+	return c, nil
+}
+
+func (s *Session) fds() []int {
+	return append(append([]int(nil), s.misses...), s.refs...)
+}
+
+func (s *Session) close() {
+	for _, fd := range s.fds() {
+		unix.Close(fd)
+	}
+	s.misses, s.refs = nil, nil
+}
+
+// openCounter opens a disabled user-space hardware counter on a thread.
+func openCounter(tid int, config uint64) (int, error) {
+	attr := unix.PerfEventAttr{
+		Type:   unix.PERF_TYPE_HARDWARE,
+		Config: config,
+		Bits:   unix.PerfBitDisabled | unix.PerfBitInherit | unix.PerfBitExcludeKernel | unix.PerfBitExcludeHv,
+	}
+	attr.Size = uint32(unsafe.Sizeof(attr))
+	return unix.PerfEventOpen(&attr, tid, -1, -1, unix.PERF_FLAG_FD_CLOEXEC)
+}
+
+// openError wraps a perf_event_open failure, mapping the errors returned
+// when the kernel or CPU lacks hardware counters to ErrUnsupported.
+func openError(err error) error {
+	if errors.Is(err, unix.ENOENT) || errors.Is(err, unix.ENODEV) || errors.Is(err, unix.EOPNOTSUPP) {
+		return fmt.Errorf("%w (%v)", ErrUnsupported, err)
+	}
+	return fmt.Errorf("perfevent: perf_event_open: %w", err)
+}
+
+// sumCounters reads and adds up the 64-bit values of the given counters.
+func sumCounters(fds []int) (uint64, error) {
+	var total uint64
+	buf := make([]byte, 8)
+	for _, fd := range fds {
+		n, err := unix.Read(fd, buf)
+		if err != nil {
+			return 0, fmt.Errorf("perfevent: read counter: %w", err)
+		}
+		if n != len(buf) {
+			return 0, fmt.Errorf("perfevent: short counter read (%d bytes)", n)
+		}
+		total += binary.NativeEndian.Uint64(buf)
+	}
+	return total, nil
+}
+
+// threadIDs lists the OS thread IDs of the current process.
+func threadIDs() ([]int, error) {
+	entries, err := os.ReadDir("/proc/self/task")
+	if err != nil {
+		return nil, err
+	}
+	tids := make([]int, 0, len(entries))
+	for _, e := range entries {
+		if tid, err := strconv.Atoi(e.Name()); err == nil {
+			tids = append(tids, tid)
+		}
+	}
+	return tids, nil
+}
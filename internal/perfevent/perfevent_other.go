@@ -0,0 +1,16 @@
+//go:build !linux
+
+package perfevent
+
+// Session is a placeholder on platforms without perf_event support.
+type Session struct{}
+
+// Start always fails with ErrUnsupported on this platform.
+func Start() (*Session, error) {
+	return nil, ErrUnsupported
+}
+
+// Stop is a no-op on this platform.
+func (s *Session) Stop() (Counts, error) {
+	return Counts{}, ErrUnsupported
+}
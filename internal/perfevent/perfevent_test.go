@@ -0,0 +1,39 @@
+package perfevent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCountsDerivedMetrics(t *testing.T) {
+	c := Counts{CacheMisses: 1000, CacheReferences: 4000, Duration: time.Second}
+	if got := c.MissRate(); got != 0.25 {
+		t.Errorf("MissRate() = %v, want 0.25", got)
+	}
+	if got := c.Bandwidth(); got != 64000 {
+		t.Errorf("Bandwidth() = %v, want 64000", got)
+	}
+
+	var zero Counts
+	if zero.MissRate() != 0 || zero.Bandwidth() != 0 {
+		t.Error("zero Counts should have zero derived metrics")
+	}
+}
+
+func TestStartStop(t *testing.T) {
+	s, err := Start()
+	if err != nil {
+		t.Skipf("hardware counters unavailable: %v", err)
+	}
+	sink := make([]byte, 1<<20)
+	for i := range sink {
+		sink[i] = byte(i)
+	}
+	c, err := s.Stop()
+	if err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if c.Duration <= 0 {
+		t.Errorf("Duration = %v, want > 0", c.Duration)
+	}
+}
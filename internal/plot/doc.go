@@ -0,0 +1,6 @@
+// Package plot charts the duration of each algorithm against the index n,
+// for `fibcalc plot`. Both axes are logarithmic, so that the power laws of
+// the algorithms are straight lines and the crossover points, where one
+// algorithm overtakes another, stand out. The chart is written as an SVG
+// document or as text for the terminal.
+package plot
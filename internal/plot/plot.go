@@ -0,0 +1,357 @@
+package plot
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+// ErrNoData is returned when no series has a point that can be placed on
+// logarithmic axes, that is with a positive index and duration.
+var ErrNoData = errors.New("no timing with a positive index and duration to plot")
+
+// Point is a timing of an algorithm.
+type Point struct {
+	N        uint64
+	Duration time.Duration
+}
+
+// Series is the timings of an algorithm, by increasing N.
+type Series struct {
+	Name   string
+	Points []Point
+}
+
+// Crossover is a change of order between two algorithms: Slower is faster
+// at Low, Faster at High, the next index both were timed at.
+type Crossover struct {
+	Faster string
+	Slower string
+	Low    uint64
+	High   uint64
+	// N estimates the crossing, by interpolating the logarithm of the
+	// durations over the logarithm of n.
+	N float64
+}
+
+// Crossovers finds where the algorithms swap order, comparing each pair of
+// series at the indexes they share.
+//
+// Parameters:
+//   - series: The series, whose points are by increasing N.
+//
+// Returns:
+//   - []Crossover: The crossovers, by pair of series then by index.
+func Crossovers(series []Series) []Crossover {
+	var crossovers []Crossover
+	for i := range series {
+		for j := i + 1; j < len(series); j++ {
+			crossovers = append(crossovers, pairCrossovers(series[i], series[j])...)
+		}
+	}
+	return crossovers
+}
+
+// pairCrossovers returns the crossovers of two series.
+func pairCrossovers(a, b Series) []Crossover {
+	durations := make(map[uint64]time.Duration, len(b.Points))
+	for _, p := range b.Points {
+		durations[p.N] = p.Duration
+	}
+	// The log ratio of the durations at the shared indexes
+	type sample struct {
+		n     uint64
+		ratio float64
+	}
+	var samples []sample
+	for _, p := range a.Points {
+		// A tie is not a change of order: it is skipped
+		if d, ok := durations[p.N]; ok && p.N > 0 && p.Duration > 0 && d > 0 && d != p.Duration {
+			samples = append(samples, sample{p.N, math.Log(float64(p.Duration)) - math.Log(float64(d))})
+		}
+	}
+
+	var crossovers []Crossover
+	for k := 1; k < len(samples); k++ {
+		lo, hi := samples[k-1], samples[k]
+		if lo.ratio*hi.ratio > 0 {
+			continue
+		}
+		c := Crossover{Faster: a.Name, Slower: b.Name, Low: lo.n, High: hi.n}
+		if hi.ratio > 0 {
+			c.Faster, c.Slower = b.Name, a.Name
+		}
+		x0, x1 := math.Log(float64(lo.n)), math.Log(float64(hi.n))
+		c.N = math.Exp(x0 + (x1-x0)*lo.ratio/(lo.ratio-hi.ratio))
+		crossovers = append(crossovers, c)
+	}
+	return crossovers
+}
+
+// FormatN formats an index compactly: 1000 as "1e3", 320000 as "3.2e5".
+func FormatN(n float64) string {
+	s := fmt.Sprintf("%.3g", n)
+	s = strings.Replace(s, "e+0", "e", 1)
+	return strings.Replace(s, "e+", "e", 1)
+}
+
+// axes are the decades spanned by the chart: 10^x0 to 10^x1 for n and
+// 10^y0 to 10^y1 nanoseconds for the durations.
+type axes struct {
+	x0, x1, y0, y1 int
+}
+
+// newAxes returns the decades enclosing the points of the series.
+func newAxes(series []Series) (axes, bool) {
+	minN, maxN := math.Inf(1), math.Inf(-1)
+	minD, maxD := math.Inf(1), math.Inf(-1)
+	for _, s := range series {
+		for _, p := range s.Points {
+			if p.N == 0 || p.Duration <= 0 {
+				continue
+			}
+			minN, maxN = math.Min(minN, float64(p.N)), math.Max(maxN, float64(p.N))
+			minD, maxD = math.Min(minD, float64(p.Duration)), math.Max(maxD, float64(p.Duration))
+		}
+	}
+	if math.IsInf(minN, 1) {
+		return axes{}, false
+	}
+	a := axes{
+		x0: int(math.Floor(math.Log10(minN))), x1: int(math.Ceil(math.Log10(maxN))),
+		y0: int(math.Floor(math.Log10(minD))), y1: int(math.Ceil(math.Log10(maxD))),
+	}
+	if a.x1 == a.x0 {
+		a.x1++
+	}
+	if a.y1 == a.y0 {
+		a.y1++
+	}
+	return a, true
+}
+
+// fx returns the position of n along the x axis, from 0 to 1.
+func (a axes) fx(n float64) float64 {
+	return (math.Log10(n) - float64(a.x0)) / float64(a.x1-a.x0)
+}
+
+// fy returns the position of d along the y axis, from 0 at the bottom to
+// 1 at the top.
+func (a axes) fy(d time.Duration) float64 {
+	return (math.Log10(float64(d)) - float64(a.y0)) / float64(a.y1-a.y0)
+}
+
+// durationLabel formats the duration of 10^e nanoseconds.
+func durationLabel(e int) string {
+	return time.Duration(math.Pow10(e)).String()
+}
+
+// plotted returns the points of s that fit on logarithmic axes.
+func plotted(s Series) []Point {
+	var points []Point
+	for _, p := range s.Points {
+		if p.N > 0 && p.Duration > 0 {
+			points = append(points, p)
+		}
+	}
+	return points
+}
+
+// Layout of the SVG chart, in pixels.
+const (
+	svgWidth     = 800
+	svgHeight    = 480
+	marginLeft   = 80
+	marginRight  = 160
+	marginTop    = 40
+	marginBottom = 50
+)
+
+// palette are the colors of the series in the SVG chart, in turn.
+var palette = []string{"#1f77b4", "#d62728", "#2ca02c", "#ff7f0e", "#9467bd", "#8c564b", "#e377c2", "#17becf"}
+
+// WriteSVG writes the chart as an SVG document: a line per series, with a
+// legend, and a dashed line at each crossover.
+//
+// Parameters:
+//   - w: The destination.
+//   - series: The series, whose points are by increasing N.
+//   - title: The title of the chart.
+//
+// Returns:
+//   - error: ErrNoData if there is nothing to plot, or a write error.
+func WriteSVG(w io.Writer, series []Series, title string) error {
+	a, ok := newAxes(series)
+	if !ok {
+		return ErrNoData
+	}
+	plotW, plotH := float64(svgWidth-marginLeft-marginRight), float64(svgHeight-marginTop-marginBottom)
+	px := func(n float64) float64 { return marginLeft + a.fx(n)*plotW }
+	py := func(d time.Duration) float64 { return marginTop + (1-a.fy(d))*plotH }
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n",
+		svgWidth, svgHeight, svgWidth, svgHeight)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="white"/>`+"\n", svgWidth, svgHeight)
+	fmt.Fprintf(&b, `<text x="%d" y="24" text-anchor="middle" font-size="16">%s</text>`+"\n",
+		marginLeft+int(plotW)/2, html.EscapeString(title))
+
+	// Grid and tick labels, at each decade
+	for e := a.x0; e <= a.x1; e++ {
+		x := px(math.Pow10(e))
+		fmt.Fprintf(&b, `<line x1="%.1f" y1="%d" x2="%.1f" y2="%.1f" stroke="#ddd"/>`+"\n", x, marginTop, x, marginTop+plotH)
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="middle">%s</text>`+"\n", x, marginTop+plotH+18, FormatN(math.Pow10(e)))
+	}
+	for e := a.y0; e <= a.y1; e++ {
+		y := py(time.Duration(math.Pow10(e)))
+		fmt.Fprintf(&b, `<line x1="%d" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#ddd"/>`+"\n", marginLeft, y, marginLeft+plotW, y)
+		fmt.Fprintf(&b, `<text x="%d" y="%.1f" text-anchor="end">%s</text>`+"\n", marginLeft-6, y+4, html.EscapeString(durationLabel(e)))
+	}
+	fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%.0f" height="%.0f" fill="none" stroke="#333"/>`+"\n", marginLeft, marginTop, plotW, plotH)
+	fmt.Fprintf(&b, `<text x="%.1f" y="%d" text-anchor="middle">n</text>`+"\n", marginLeft+plotW/2, svgHeight-8)
+	fmt.Fprintf(&b, `<text x="16" y="%.1f" text-anchor="middle" transform="rotate(-90 16 %.1f)">duration</text>`+"\n",
+		marginTop+plotH/2, marginTop+plotH/2)
+
+	for _, c := range Crossovers(series) {
+		x := px(c.N)
+		fmt.Fprintf(&b, `<line x1="%.1f" y1="%d" x2="%.1f" y2="%.1f" stroke="#888" stroke-dasharray="4 3"><title>%s overtakes %s at n ≈ %s</title></line>`+"\n",
+			x, marginTop, x, marginTop+plotH, html.EscapeString(c.Faster), html.EscapeString(c.Slower), FormatN(c.N))
+		fmt.Fprintf(&b, `<text x="%.1f" y="%d" text-anchor="middle" font-size="10" fill="#555">%s</text>`+"\n", x, marginTop-4, FormatN(c.N))
+	}
+
+	for i, s := range series {
+		color := palette[i%len(palette)]
+		points := plotted(s)
+		coords := make([]string, len(points))
+		for k, p := range points {
+			coords[k] = fmt.Sprintf("%.1f,%.1f", px(float64(p.N)), py(p.Duration))
+		}
+		if len(points) > 1 {
+			fmt.Fprintf(&b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2"/>`+"\n", strings.Join(coords, " "), color)
+		}
+		for _, c := range coords {
+			x, y, _ := strings.Cut(c, ",")
+			fmt.Fprintf(&b, `<circle cx="%s" cy="%s" r="3" fill="%s"/>`+"\n", x, y, color)
+		}
+		ly := marginTop + 10 + 20*i
+		fmt.Fprintf(&b, `<line x1="%.0f" y1="%d" x2="%.0f" y2="%d" stroke="%s" stroke-width="2"/>`+"\n",
+			marginLeft+plotW+16, ly, marginLeft+plotW+36, ly, color)
+		fmt.Fprintf(&b, `<text x="%.0f" y="%d">%s</text>`+"\n", marginLeft+plotW+42, ly+4, html.EscapeString(s.Name))
+	}
+	b.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markers are the characters of the series in the text chart, in turn.
+const markers = "*+ox%@&="
+
+// overlapMarker marks the cells where points of several series fall.
+const overlapMarker = '#'
+
+// WriteASCII writes the chart as text: a plot area of width × height
+// characters, labeled with the decades of each axis, and a legend. Each
+// series is drawn with its marker at its points, joined by dots.
+//
+// Parameters:
+//   - w: The destination.
+//   - series: The series, whose points are by increasing N.
+//   - width: The width of the plot area, in characters.
+//   - height: The height of the plot area, in lines.
+//
+// Returns:
+//   - error: ErrNoData if there is nothing to plot, or a write error.
+func WriteASCII(w io.Writer, series []Series, width, height int) error {
+	a, ok := newAxes(series)
+	if !ok {
+		return ErrNoData
+	}
+	width, height = max(width, 10), max(height, 4)
+	grid := make([][]rune, height)
+	for r := range grid {
+		grid[r] = []rune(strings.Repeat(" ", width))
+	}
+	col := func(n uint64) int { return int(math.Round(a.fx(float64(n)) * float64(width-1))) }
+	row := func(d time.Duration) int { return int(math.Round((1 - a.fy(d)) * float64(height-1))) }
+
+	// The lines first, so that the markers are drawn over them
+	for _, s := range series {
+		points := plotted(s)
+		for k := 1; k < len(points); k++ {
+			c0, r0 := col(points[k-1].N), row(points[k-1].Duration)
+			c1, r1 := col(points[k].N), row(points[k].Duration)
+			steps := max(abs(c1-c0), abs(r1-r0))
+			for t := 1; t < steps; t++ {
+				c := c0 + int(math.Round(float64((c1-c0)*t)/float64(steps)))
+				r := r0 + int(math.Round(float64((r1-r0)*t)/float64(steps)))
+				if grid[r][c] == ' ' {
+					grid[r][c] = '.'
+				}
+			}
+		}
+	}
+	overlap := false
+	for i, s := range series {
+		m := rune(markers[i%len(markers)])
+		for _, p := range plotted(s) {
+			cell := &grid[row(p.Duration)][col(p.N)]
+			if *cell != ' ' && *cell != '.' && *cell != m {
+				*cell = overlapMarker
+				overlap = true
+			} else {
+				*cell = m
+			}
+		}
+	}
+
+	labels := make(map[int]string)
+	labelWidth := 0
+	for e := a.y0; e <= a.y1; e++ {
+		label := durationLabel(e)
+		labels[row(time.Duration(math.Pow10(e)))] = label
+		labelWidth = max(labelWidth, len([]rune(label)))
+	}
+	var b strings.Builder
+	for r, line := range grid {
+		fmt.Fprintf(&b, "%*s |%s\n", labelWidth, labels[r], strings.TrimRight(string(line), " "))
+	}
+	fmt.Fprintf(&b, "%s +%s\n", strings.Repeat(" ", labelWidth), strings.Repeat("-", width))
+
+	// The decades of n under the axis, skipping those that would overlap
+	axis := []rune(strings.Repeat(" ", labelWidth+2+width+4))
+	next := 0
+	for e := a.x0; e <= a.x1; e++ {
+		label := FormatN(math.Pow10(e))
+		start := max(labelWidth+2+col(uint64(math.Pow10(e)))-len(label)/2, 0)
+		if start < next || start+len(label) > len(axis) {
+			continue
+		}
+		copy(axis[start:], []rune(label))
+		next = start + len(label) + 1
+	}
+	fmt.Fprintf(&b, "%s  n\n", strings.TrimRight(string(axis), " "))
+
+	legend := make([]string, 0, len(series)+1)
+	for i, s := range series {
+		legend = append(legend, fmt.Sprintf("%c %s", markers[i%len(markers)], s.Name))
+	}
+	if overlap {
+		legend = append(legend, fmt.Sprintf("%c several", overlapMarker))
+	}
+	fmt.Fprintf(&b, "%s  %s\n", strings.Repeat(" ", labelWidth), strings.Join(legend, "   "))
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
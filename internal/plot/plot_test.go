@@ -0,0 +1,115 @@
+package plot
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testSeries has "a" in n^1 and "b" in 1000·n^0.5: b overtakes a at n = 1e6.
+func testSeries() []Series {
+	a := Series{Name: "a"}
+	b := Series{Name: "b<&>"}
+	for _, n := range []uint64{1e4, 1e5, 1e7, 1e8} {
+		a.Points = append(a.Points, Point{n, time.Duration(n)})
+		b.Points = append(b.Points, Point{n, time.Duration(1000 * sqrt(n))})
+	}
+	return []Series{a, b}
+}
+
+func sqrt(n uint64) uint64 {
+	r := uint64(1)
+	for r*r < n {
+		r++
+	}
+	return r
+}
+
+func TestCrossovers(t *testing.T) {
+	t.Parallel()
+	got := Crossovers(testSeries())
+	if len(got) != 1 {
+		t.Fatalf("Crossovers() = %+v, want one", got)
+	}
+	c := got[0]
+	if c.Faster != "b<&>" || c.Slower != "a" || c.Low != 1e5 || c.High != 1e7 {
+		t.Errorf("Crossovers() = %+v", c)
+	}
+	if c.N < 0.99e6 || c.N > 1.01e6 {
+		t.Errorf("crossover at n = %g, want 1e6", c.N)
+	}
+	// Through a tie
+	tie := testSeries()
+	tie[1].Points[1].Duration = tie[0].Points[1].Duration
+	if got := Crossovers(tie); len(got) != 1 || got[0].Low != 1e4 || got[0].High != 1e7 {
+		t.Errorf("Crossovers() through a tie = %+v", got)
+	}
+	if got := Crossovers(testSeries()[:1]); len(got) != 0 {
+		t.Errorf("Crossovers() of a single series = %+v", got)
+	}
+}
+
+func TestFormatN(t *testing.T) {
+	t.Parallel()
+	for n, want := range map[float64]string{1: "1", 100: "100", 1000: "1e3", 320000: "3.2e5", 1.2345e10: "1.23e10"} {
+		if got := FormatN(n); got != want {
+			t.Errorf("FormatN(%g) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	if err := WriteSVG(&buf, testSeries(), "duration vs n"); err != nil {
+		t.Fatal(err)
+	}
+	svg := buf.String()
+	for _, want := range []string{"<svg ", "</svg>", ">duration vs n<", ">1e4<", ">1e8<", "b&lt;&amp;&gt;", `stroke-dasharray`} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("SVG lacks %q:\n%s", want, svg)
+		}
+	}
+	if got := strings.Count(svg, "<polyline"); got != 2 {
+		t.Errorf("SVG has %d polylines, want 2", got)
+	}
+	if got := strings.Count(svg, "<circle"); got != 8 {
+		t.Errorf("SVG has %d points, want 8", got)
+	}
+}
+
+func TestWriteASCII(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	if err := WriteASCII(&buf, testSeries(), 40, 10); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	// The plot area, the axis, its labels and the legend
+	if len(lines) != 13 {
+		t.Fatalf("chart has %d lines, want 13:\n%s", len(lines), buf.String())
+	}
+	chart := buf.String()
+	// Besides the legend, and the corner of the axes for +
+	if strings.Count(chart, "*")-1 != 4 || strings.Count(chart, "+")-2 != 4 {
+		t.Errorf("chart does not have 4 points per series:\n%s", chart)
+	}
+	for _, want := range []string{"100ms |", "10µs |", "1e4", "1e8", "* a", "+ b<&>"} {
+		if !strings.Contains(chart, want) {
+			t.Errorf("chart lacks %q:\n%s", want, chart)
+		}
+	}
+}
+
+func TestNoData(t *testing.T) {
+	t.Parallel()
+	series := []Series{{Name: "a", Points: []Point{{0, time.Second}, {10, 0}}}}
+	if err := WriteSVG(&bytes.Buffer{}, series, ""); !errors.Is(err, ErrNoData) {
+		t.Errorf("WriteSVG() error = %v, want ErrNoData", err)
+	}
+	if err := WriteASCII(&bytes.Buffer{}, nil, 40, 10); !errors.Is(err, ErrNoData) {
+		t.Errorf("WriteASCII() error = %v, want ErrNoData", err)
+	}
+}
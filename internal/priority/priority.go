@@ -0,0 +1,123 @@
+// Package priority lowers the scheduling priority of the process so that a
+// long calculation leaves the workstation usable: CPU niceness on every
+// platform that has it (a priority class on Windows) and the I/O scheduling
+// class on Linux. Unsupported settings report ErrUnsupported.
+package priority
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Nice values, as for nice(1): lower runs first.
+const (
+	// MinNice is the highest priority. Going below 0 usually needs
+	// privileges.
+	MinNice = -20
+	// MaxNice is the lowest priority.
+	MaxNice = 19
+	// BackgroundNice is the niceness of background mode.
+	BackgroundNice = MaxNice
+)
+
+// MaxIOLevel is the lowest priority level within an I/O class (0 is the
+// highest).
+const MaxIOLevel = 7
+
+// ErrUnsupported is returned when a setting is not available on the
+// current platform.
+var ErrUnsupported = errors.New("priority: not supported on this platform")
+
+// IOClass is an I/O scheduling class, as for ionice(1).
+type IOClass int
+
+const (
+	// IOClassNone leaves the I/O priority unchanged.
+	IOClassNone IOClass = iota
+	// IOClassRealtime gets the disk first. It needs privileges.
+	IOClassRealtime
+	// IOClassBestEffort is the default class, ordered by level.
+	IOClassBestEffort
+	// IOClassIdle gets the disk only when no other process uses it.
+	IOClassIdle
+)
+
+// ioClassNames maps the names accepted by ParseIOPriority to classes.
+var ioClassNames = map[string]IOClass{
+	"realtime":    IOClassRealtime,
+	"best-effort": IOClassBestEffort,
+	"idle":        IOClassIdle,
+}
+
+// IOPriority is an I/O class with its level. The level only applies to
+// the realtime and best-effort classes.
+type IOPriority struct {
+	Class IOClass
+	Level int
+}
+
+// BackgroundIO is the I/O priority of background mode.
+var BackgroundIO = IOPriority{Class: IOClassIdle}
+
+// ParseIOPriority parses "CLASS" or "CLASS:LEVEL", where CLASS is
+// realtime, best-effort or idle and LEVEL is 0 (highest) to 7. The empty
+// string means IOClassNone.
+//
+// Parameters:
+//   - s: The text to parse.
+//
+// Returns:
+//   - IOPriority: The parsed priority. The level defaults to 4.
+//   - error: An error if s is not a valid priority.
+func ParseIOPriority(s string) (IOPriority, error) {
+	if s == "" {
+		return IOPriority{}, nil
+	}
+	name, levelText, hasLevel := strings.Cut(s, ":")
+	class, ok := ioClassNames[name]
+	if !ok {
+		return IOPriority{}, fmt.Errorf("unknown I/O class %q (realtime, best-effort or idle)", name)
+	}
+	p := IOPriority{Class: class, Level: 4}
+	if class == IOClassIdle {
+		p.Level = 0
+	}
+	if hasLevel {
+		if class == IOClassIdle {
+			return IOPriority{}, errors.New("the idle I/O class takes no level")
+		}
+		level, err := strconv.Atoi(levelText)
+		if err != nil || level < 0 || level > MaxIOLevel {
+			return IOPriority{}, fmt.Errorf("I/O level must be 0 to %d, got %q", MaxIOLevel, levelText)
+		}
+		p.Level = level
+	}
+	return p, nil
+}
+
+// String returns the priority in the form accepted by ParseIOPriority.
+func (p IOPriority) String() string {
+	for name, class := range ioClassNames {
+		if class == p.Class {
+			if class == IOClassIdle {
+				return name
+			}
+			return fmt.Sprintf("%s:%d", name, p.Level)
+		}
+	}
+	return ""
+}
+
+// BackgroundWorkers returns the number of processors background mode
+// leaves to the calculation: half of them, at least one.
+//
+// Parameters:
+//   - numCPU: The number of logical processors.
+//
+// Returns:
+//   - int: The worker cap.
+func BackgroundWorkers(numCPU int) int {
+	return max(1, numCPU/2)
+}
@@ -0,0 +1,77 @@
+//go:build linux
+
+package priority
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"golang.org/x/sys/unix"
+)
+
+// ioprioWhoProcess selects a thread for ioprio_set, and ioprioClassShift
+// places the class above the level in the priority value.
+const (
+	ioprioWhoProcess = 1
+	ioprioClassShift = 13
+)
+
+// SetNice sets the niceness of the process. On Linux niceness is a
+// property of each thread, so every existing thread is changed; threads
+// created later inherit it.
+//
+// Parameters:
+//   - nice: The niceness, MinNice to MaxNice.
+//
+// Returns:
+//   - error: An error if a thread cannot be changed (lowering the
+//     niceness usually needs privileges).
+func SetNice(nice int) error {
+	return forEachThread(func(tid int) error {
+		if err := unix.Setpriority(unix.PRIO_PROCESS, tid, nice); err != nil {
+			return fmt.Errorf("priority: setpriority: %w", err)
+		}
+		return nil
+	})
+}
+
+// SetIOPriority sets the I/O scheduling class of every thread of the
+// process, as SetNice does for niceness.
+//
+// Parameters:
+//   - p: The I/O priority. IOClassNone does nothing.
+//
+// Returns:
+//   - error: An error if a thread cannot be changed (the realtime class
+//     needs privileges).
+func SetIOPriority(p IOPriority) error {
+	if p.Class == IOClassNone {
+		return nil
+	}
+	value := uintptr(p.Class)<<ioprioClassShift | uintptr(p.Level)
+	return forEachThread(func(tid int) error {
+		if _, _, errno := unix.Syscall(unix.SYS_IOPRIO_SET, ioprioWhoProcess, uintptr(tid), value); errno != 0 {
+			return fmt.Errorf("priority: ioprio_set: %w", errno)
+		}
+		return nil
+	})
+}
+
+// forEachThread calls fn with the ID of every thread of the process.
+func forEachThread(fn func(tid int) error) error {
+	entries, err := os.ReadDir("/proc/self/task")
+	if err != nil {
+		return fmt.Errorf("priority: listing threads: %w", err)
+	}
+	for _, e := range entries {
+		tid, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		if err := fn(tid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
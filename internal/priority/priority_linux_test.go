@@ -0,0 +1,34 @@
+//go:build linux
+
+package priority
+
+import (
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+// Lowering the priority is always allowed, so the test can run
+// unprivileged; it only affects the test process.
+func TestSetPriorityAllThreads(t *testing.T) {
+	if err := SetNice(MaxNice); err != nil {
+		t.Fatalf("SetNice: %v", err)
+	}
+	if err := SetIOPriority(BackgroundIO); err != nil {
+		t.Fatalf("SetIOPriority: %v", err)
+	}
+	err := forEachThread(func(tid int) error {
+		// The raw syscall returns 20 - nice.
+		if prio, err := unix.Getpriority(unix.PRIO_PROCESS, tid); err != nil || 20-prio != MaxNice {
+			t.Errorf("thread %d: nice = %d (%v), want %d", tid, 20-prio, err, MaxNice)
+		}
+		value, _, errno := unix.Syscall(unix.SYS_IOPRIO_GET, ioprioWhoProcess, uintptr(tid), 0)
+		if errno != 0 || IOClass(value>>ioprioClassShift) != IOClassIdle {
+			t.Errorf("thread %d: I/O class = %d (%v), want idle", tid, value>>ioprioClassShift, errno)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !unix && !windows
+
+package priority
+
+// SetNice is not supported on this platform.
+func SetNice(nice int) error {
+	return ErrUnsupported
+}
+
+// SetIOPriority is not supported on this platform.
+func SetIOPriority(p IOPriority) error {
+	if p.Class == IOClassNone {
+		return nil
+	}
+	return ErrUnsupported
+}
@@ -0,0 +1,39 @@
+package priority
+
+import "testing"
+
+func TestParseIOPriority(t *testing.T) {
+	t.Parallel()
+
+	valid := map[string]IOPriority{
+		"":              {},
+		"idle":          {Class: IOClassIdle},
+		"best-effort":   {Class: IOClassBestEffort, Level: 4},
+		"best-effort:7": {Class: IOClassBestEffort, Level: 7},
+		"realtime:0":    {Class: IOClassRealtime, Level: 0},
+	}
+	for text, want := range valid {
+		got, err := ParseIOPriority(text)
+		if err != nil || got != want {
+			t.Errorf("ParseIOPriority(%q) = %+v, %v; want %+v", text, got, err, want)
+		}
+		if text != "" && text != "best-effort" && got.String() != text {
+			t.Errorf("%+v.String() = %q, want %q", got, got.String(), text)
+		}
+	}
+	for _, text := range []string{"low", "idle:3", "best-effort:8", "best-effort:-1", "realtime:x"} {
+		if _, err := ParseIOPriority(text); err == nil {
+			t.Errorf("ParseIOPriority(%q) should fail", text)
+		}
+	}
+}
+
+func TestBackgroundWorkers(t *testing.T) {
+	t.Parallel()
+
+	for numCPU, want := range map[int]int{1: 1, 2: 1, 3: 1, 8: 4, 64: 32} {
+		if got := BackgroundWorkers(numCPU); got != want {
+			t.Errorf("BackgroundWorkers(%d) = %d, want %d", numCPU, got, want)
+		}
+	}
+}
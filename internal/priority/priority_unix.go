@@ -0,0 +1,32 @@
+//go:build unix && !linux
+
+package priority
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// SetNice sets the niceness of the process.
+//
+// Parameters:
+//   - nice: The niceness, MinNice to MaxNice.
+//
+// Returns:
+//   - error: An error if the niceness cannot be changed (lowering it
+//     usually needs privileges).
+func SetNice(nice int) error {
+	if err := unix.Setpriority(unix.PRIO_PROCESS, 0, nice); err != nil {
+		return fmt.Errorf("priority: setpriority: %w", err)
+	}
+	return nil
+}
+
+// SetIOPriority is only supported on Linux.
+func SetIOPriority(p IOPriority) error {
+	if p.Class == IOClassNone {
+		return nil
+	}
+	return ErrUnsupported
+}
@@ -0,0 +1,45 @@
+//go:build windows
+
+package priority
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/windows"
+)
+
+// SetNice sets the priority class of the process closest to the niceness:
+// idle from 15, below normal from 1, above normal below 0 and high from -15.
+//
+// Parameters:
+//   - nice: The niceness, MinNice to MaxNice.
+//
+// Returns:
+//   - error: An error if the priority class cannot be changed.
+func SetNice(nice int) error {
+	var class uint32
+	switch {
+	case nice >= 15:
+		class = windows.IDLE_PRIORITY_CLASS
+	case nice > 0:
+		class = windows.BELOW_NORMAL_PRIORITY_CLASS
+	case nice == 0:
+		class = windows.NORMAL_PRIORITY_CLASS
+	case nice > -15:
+		class = windows.ABOVE_NORMAL_PRIORITY_CLASS
+	default:
+		class = windows.HIGH_PRIORITY_CLASS
+	}
+	if err := windows.SetPriorityClass(windows.CurrentProcess(), class); err != nil {
+		return fmt.Errorf("priority: SetPriorityClass: %w", err)
+	}
+	return nil
+}
+
+// SetIOPriority is only supported on Linux.
+func SetIOPriority(p IOPriority) error {
+	if p.Class == IOClassNone {
+		return nil
+	}
+	return ErrUnsupported
+}
@@ -0,0 +1,176 @@
+// This file contains the time-based adaptive progress reporter.
+
+package progress
+
+import (
+	"sync"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/clock"
+)
+
+// ProgressUpdateInterval is the target wall-clock interval between progress
+// updates emitted by an AdaptiveReporter (about 10 updates per second).
+const ProgressUpdateInterval = 100 * time.Millisecond
+
+// stepInterpolationCap bounds interpolated progress within a single step.
+// Under the geometric work model of CalcTotalWork, the next doubling step
+// accounts for roughly three quarters of the remaining work, so progress is
+// never extrapolated past that point before the calculator confirms it.
+const stepInterpolationCap = 0.75
+
+// AdaptiveReporter decouples the rate of progress updates from the number of
+// algorithm steps. Step reports that arrive faster than the interval are
+// coalesced (small n), and when a single step takes longer than the interval
+// (huge n, where the last few steps dominate), progress is interpolated from
+// the observed rate so that the UI keeps moving.
+//
+// Emitted values are monotonic, and the final 1.0 is always forwarded
+// immediately. AdaptiveReporter is safe for concurrent use; Stop must be
+// called once the calculation finishes.
+type AdaptiveReporter struct {
+	inner    ProgressCallback
+	interval time.Duration
+	clock    clock.Clock
+	ticker   clock.Ticker
+
+	mu         sync.Mutex
+	start      time.Time
+	lastReal   float64
+	lastRealAt time.Time
+	emitted    float64
+	lastEmit   time.Time
+	pending    bool
+	done       bool
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewAdaptiveReporter wraps a progress callback and starts the background
+// ticker that flushes coalesced updates and interpolates long steps.
+//
+// Parameters:
+//   - inner: The callback receiving the adapted updates.
+//   - interval: The target interval between updates (ProgressUpdateInterval
+//     if zero or negative).
+//
+// Returns:
+//   - *AdaptiveReporter: The running reporter.
+func NewAdaptiveReporter(inner ProgressCallback, interval time.Duration) *AdaptiveReporter {
+	return NewAdaptiveReporterClock(inner, interval, clock.Real{})
+}
+
+// NewAdaptiveReporterClock is NewAdaptiveReporter with an explicit time
+// source, so that coalescing and interpolation can be tested with a fake
+// clock. The ticker is armed before NewAdaptiveReporterClock returns.
+//
+// Parameters:
+//   - inner: The callback receiving the adapted updates.
+//   - interval: The target interval between updates (ProgressUpdateInterval
+//     if zero or negative).
+//   - clk: The time source (the wall clock if nil).
+//
+// Returns:
+//   - *AdaptiveReporter: The running reporter.
+func NewAdaptiveReporterClock(inner ProgressCallback, interval time.Duration, clk clock.Clock) *AdaptiveReporter {
+	if interval <= 0 {
+		interval = ProgressUpdateInterval
+	}
+	clk = clock.Or(clk)
+	a := &AdaptiveReporter{
+		inner:    inner,
+		interval: interval,
+		clock:    clk,
+		start:    clk.Now(),
+		// Tick at half the interval so that jitter does not halve the update rate
+		ticker: clk.NewTicker(interval / 2),
+		stop:   make(chan struct{}),
+	}
+	a.wg.Add(1)
+	go a.run()
+	return a
+}
+
+// Report records a progress value from the calculator. It matches the
+// ProgressCallback signature so that a.Report can be passed to algorithms.
+//
+// Parameters:
+//   - progress: The normalized progress value (0.0 to 1.0).
+func (a *AdaptiveReporter) Report(progress float64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.done {
+		return
+	}
+	now := a.clock.Now()
+	a.lastReal, a.lastRealAt = progress, now
+	if progress >= 1.0 {
+		a.done = true
+		a.emitLocked(progress, now)
+		return
+	}
+	if a.lastEmit.IsZero() || now.Sub(a.lastEmit) >= a.interval {
+		a.emitLocked(progress, now)
+		return
+	}
+	a.pending = true
+}
+
+// Stop halts the background ticker and waits for it to exit. No update is
+// emitted after Stop returns. It is safe to call Stop more than once.
+func (a *AdaptiveReporter) Stop() {
+	a.stopOnce.Do(func() { close(a.stop) })
+	a.wg.Wait()
+}
+
+// run flushes pending updates and interpolates progress until stopped.
+func (a *AdaptiveReporter) run() {
+	defer a.wg.Done()
+	defer a.ticker.Stop()
+	for {
+		select {
+		case <-a.stop:
+			return
+		case now := <-a.ticker.C():
+			a.tick(now)
+		}
+	}
+}
+
+// tick emits the latest coalesced value, or an interpolated one when the
+// calculator has been silent for longer than the interval.
+func (a *AdaptiveReporter) tick(now time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.done || now.Sub(a.lastEmit) < a.interval {
+		return
+	}
+	if a.pending {
+		a.emitLocked(a.lastReal, now)
+		return
+	}
+	elapsed := a.lastRealAt.Sub(a.start).Seconds()
+	if a.lastReal <= 0 || elapsed <= 0 {
+		return // no rate observed yet
+	}
+	rate := a.lastReal / elapsed
+	estimate := a.lastReal + rate*now.Sub(a.lastRealAt).Seconds()
+	estimate = min(estimate, a.lastReal+stepInterpolationCap*(1-a.lastReal))
+	if estimate > a.emitted {
+		a.emitLocked(estimate, now)
+	}
+}
+
+// emitLocked forwards a value, keeping emitted progress monotonic.
+// The caller must hold a.mu.
+func (a *AdaptiveReporter) emitLocked(progress float64, now time.Time) {
+	a.pending = false
+	a.lastEmit = now
+	if progress < a.emitted {
+		progress = a.emitted
+	}
+	a.emitted = progress
+	a.inner(progress)
+}
@@ -0,0 +1,126 @@
+package progress
+
+import (
+	"math"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/clock"
+)
+
+// recorder collects values passed to a ProgressCallback.
+type recorder struct {
+	mu     sync.Mutex
+	values []float64
+}
+
+func (r *recorder) callback(p float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.values = append(r.values, p)
+}
+
+func (r *recorder) snapshot() []float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]float64(nil), r.values...)
+}
+
+// advance moves the fake clock forward in steps of half the reporter
+// interval, waiting after each step until the reporter has consumed the tick.
+func advance(clk *clock.Fake, a *AdaptiveReporter, d time.Duration) {
+	step := a.interval / 2
+	for elapsed := time.Duration(0); elapsed < d; elapsed += step {
+		clk.Advance(step)
+		for len(a.ticker.C()) > 0 {
+			runtime.Gosched()
+		}
+	}
+}
+
+// assertUpdates compares emitted updates with the expected sequence.
+func assertUpdates(t *testing.T, got, want []float64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("updates = %v, want %v", got, want)
+	}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-9 {
+			t.Fatalf("updates = %v, want %v", got, want)
+		}
+	}
+}
+
+func newFakeClock() *clock.Fake {
+	return clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+}
+
+func TestAdaptiveReporterCoalescesFastSteps(t *testing.T) {
+	t.Parallel()
+	var rec recorder
+	a := NewAdaptiveReporter(rec.callback, time.Hour)
+	for i := 1; i < 100; i++ {
+		a.Report(float64(i) / 100)
+	}
+	a.Report(1.0)
+	a.Stop()
+
+	got := rec.snapshot()
+	if len(got) != 2 {
+		t.Fatalf("got %d updates %v, want first and final only", len(got), got)
+	}
+	if got[len(got)-1] != 1.0 {
+		t.Errorf("final update = %v, want 1.0", got[len(got)-1])
+	}
+}
+
+func TestAdaptiveReporterFlushesPending(t *testing.T) {
+	t.Parallel()
+	var rec recorder
+	clk := newFakeClock()
+	a := NewAdaptiveReporterClock(rec.callback, 20*time.Millisecond, clk)
+	a.Report(0.1)
+	a.Report(0.2) // coalesced, flushed by the ticker
+	advance(clk, a, 10*time.Millisecond)
+	assertUpdates(t, rec.snapshot(), []float64{0.1})
+	advance(clk, a, 10*time.Millisecond)
+	a.Stop()
+
+	assertUpdates(t, rec.snapshot(), []float64{0.1, 0.2})
+}
+
+func TestAdaptiveReporterInterpolatesLongSteps(t *testing.T) {
+	t.Parallel()
+	var rec recorder
+	clk := newFakeClock()
+	a := NewAdaptiveReporterClock(rec.callback, 10*time.Millisecond, clk)
+	advance(clk, a, 20*time.Millisecond)
+	a.Report(0.2) // observed rate: 10 per second
+	// The calculator is now silent, as during one long final step
+	advance(clk, a, 150*time.Millisecond)
+	a.Stop()
+
+	// One interpolated update per interval, until the step ceiling
+	// 0.2 + stepInterpolationCap*0.8 = 0.8 is reached.
+	assertUpdates(t, rec.snapshot(), []float64{0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8})
+}
+
+func TestAdaptiveReporterStop(t *testing.T) {
+	t.Parallel()
+	var rec recorder
+	clk := newFakeClock()
+	a := NewAdaptiveReporterClock(rec.callback, 5*time.Millisecond, clk)
+	a.Report(0.5)
+	a.Stop()
+	a.Stop() // idempotent
+	n := len(rec.snapshot())
+	clk.Advance(30 * time.Millisecond)
+	if clk.Pending() != 0 {
+		t.Error("ticker still armed after Stop")
+	}
+	if len(rec.snapshot()) != n {
+		t.Error("updates emitted after Stop")
+	}
+}
@@ -0,0 +1,257 @@
+// This file contains the backpressure policies of progress channels.
+
+package progress
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Policy decides what a ChannelObserver does with an update when the
+// progress channel is full, that is when the display falls behind.
+type Policy string
+
+const (
+	// PolicyDrop discards the new update (the historical behavior). The
+	// display catches up on the next update that fits.
+	PolicyDrop Policy = "drop"
+	// PolicyDropOldest discards the oldest queued update to make room for
+	// the new one, so the display always receives the most recent values.
+	PolicyDropOldest Policy = "drop-oldest"
+	// PolicyCoalesce replaces the queued updates with the latest one of
+	// each calculator: nothing that matters is lost, since progress values
+	// only grow.
+	PolicyCoalesce Policy = "coalesce"
+	// PolicyBlock waits for room up to a timeout, then discards the update.
+	// It slows the calculation down to the display's pace.
+	PolicyBlock Policy = "block"
+)
+
+// Policies lists the backpressure policies.
+var Policies = []Policy{PolicyDrop, PolicyDropOldest, PolicyCoalesce, PolicyBlock}
+
+// DefaultBlockTimeout is how long PolicyBlock waits for room by default.
+const DefaultBlockTimeout = 50 * time.Millisecond
+
+// ParsePolicy validates a policy name.
+//
+// Parameters:
+//   - s: The policy name; empty means PolicyDrop.
+//
+// Returns:
+//   - Policy: The policy.
+//   - error: An error if s is not one of Policies.
+func ParsePolicy(s string) (Policy, error) {
+	if s == "" {
+		return PolicyDrop, nil
+	}
+	if slices.Contains(Policies, Policy(s)) {
+		return Policy(s), nil
+	}
+	return "", fmt.Errorf("unknown progress policy %q (accepted values: drop, drop-oldest, coalesce, block)", s)
+}
+
+// DropStats counts what happened to progress updates sent to channels.
+type DropStats struct {
+	// Sent is the number of updates that reached a channel.
+	Sent uint64
+	// Dropped is the number of updates lost: discarded when the channel
+	// was full, evicted by PolicyDropOldest, or timed out by PolicyBlock.
+	Dropped uint64
+	// Coalesced is the number of queued updates that PolicyCoalesce
+	// replaced with a later value of the same calculator.
+	Coalesced uint64
+	// Blocked is the number of updates for which PolicyBlock had to wait.
+	Blocked uint64
+}
+
+// dropCounters holds DropStats as atomic counters.
+type dropCounters struct {
+	sent, dropped, coalesced, blocked atomic.Int64
+}
+
+// add applies signed deltas to the counters.
+func (d *dropCounters) add(sent, dropped, coalesced, blocked int64) {
+	d.sent.Add(sent)
+	d.dropped.Add(dropped)
+	d.coalesced.Add(coalesced)
+	d.blocked.Add(blocked)
+}
+
+func (d *dropCounters) snapshot() DropStats {
+	return DropStats{
+		Sent:      uint64(d.sent.Load()),
+		Dropped:   uint64(d.dropped.Load()),
+		Coalesced: uint64(d.coalesced.Load()),
+		Blocked:   uint64(d.blocked.Load()),
+	}
+}
+
+// totals accumulates the statistics of every progress channel of the
+// process, including plain channels handed to NewChannelObserver.
+var totals dropCounters
+
+// TotalStats returns the progress update statistics of the whole process
+// so far. A growing Dropped count means the display cannot keep up.
+func TotalStats() DropStats {
+	return totals.snapshot()
+}
+
+// Channel is a progress channel with a backpressure policy. Calculators
+// receive Send() like any progress channel: the ChannelObserver they create
+// for it finds the channel's policy, so Calculator implementations need no
+// change. The display reads C().
+type Channel struct {
+	ch      chan ProgressUpdate
+	policy  Policy
+	timeout time.Duration
+	stats   dropCounters
+
+	// mu serializes drop-oldest and coalesce, which take updates back out
+	// of the channel.
+	mu sync.Mutex
+}
+
+// channels maps the send side of every open Channel to it.
+var channels sync.Map // chan<- ProgressUpdate → *Channel
+
+// NewChannel creates a progress channel. It must be closed with Close.
+//
+// Parameters:
+//   - capacity: The buffer size; coalesce needs at least one slot per
+//     calculator.
+//   - policy: The backpressure policy; empty means PolicyDrop.
+//   - timeout: How long PolicyBlock waits (DefaultBlockTimeout if zero or
+//     negative).
+//
+// Returns:
+//   - *Channel: The open channel.
+func NewChannel(capacity int, policy Policy, timeout time.Duration) *Channel {
+	if policy == "" {
+		policy = PolicyDrop
+	}
+	if timeout <= 0 {
+		timeout = DefaultBlockTimeout
+	}
+	c := &Channel{ch: make(chan ProgressUpdate, capacity), policy: policy, timeout: timeout}
+	channels.Store(c.Send(), c)
+	return c
+}
+
+// C returns the channel the display reads.
+func (c *Channel) C() <-chan ProgressUpdate { return c.ch }
+
+// Send returns the channel to hand to calculators.
+func (c *Channel) Send() chan<- ProgressUpdate { return c.ch }
+
+// Policy returns the backpressure policy.
+func (c *Channel) Policy() Policy { return c.policy }
+
+// Stats returns the statistics of this channel so far.
+func (c *Channel) Stats() DropStats { return c.stats.snapshot() }
+
+// Close closes the channel once no calculator sends to it anymore.
+func (c *Channel) Close() {
+	channels.Delete(c.Send())
+	close(c.ch)
+}
+
+// lookupChannel returns the Channel whose send side is ch, if any.
+func lookupChannel(ch chan<- ProgressUpdate) *Channel {
+	if v, ok := channels.Load(ch); ok {
+		return v.(*Channel)
+	}
+	return nil
+}
+
+// record applies deltas to the statistics of the channel and of the
+// process. c may be nil for a plain channel.
+func (c *Channel) record(sent, dropped, coalesced, blocked int64) {
+	totals.add(sent, dropped, coalesced, blocked)
+	if c != nil {
+		c.stats.add(sent, dropped, coalesced, blocked)
+	}
+}
+
+// sendDrop sends update to ch without blocking, discarding it if ch is
+// full. c may be nil for a plain channel.
+func (c *Channel) sendDrop(ch chan<- ProgressUpdate, update ProgressUpdate) {
+	select {
+	case ch <- update:
+		c.record(1, 0, 0, 0)
+	default:
+		c.record(0, 1, 0, 0)
+	}
+}
+
+// send delivers update according to the policy.
+func (c *Channel) send(update ProgressUpdate) {
+	select {
+	case c.ch <- update:
+		c.record(1, 0, 0, 0)
+		return
+	default:
+	}
+
+	switch c.policy {
+	case PolicyDropOldest:
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		select {
+		case <-c.ch:
+			c.record(0, 1, 0, 0)
+		default: // drained by the display in the meantime
+		}
+		c.sendDrop(c.ch, update)
+	case PolicyCoalesce:
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.coalesce(update)
+	case PolicyBlock:
+		c.record(0, 0, 0, 1)
+		timer := time.NewTimer(c.timeout)
+		defer timer.Stop()
+		select {
+		case c.ch <- update:
+			c.record(1, 0, 0, 0)
+		case <-timer.C:
+			c.record(0, 1, 0, 0)
+		}
+	default:
+		c.record(0, 1, 0, 0)
+	}
+}
+
+// coalesce empties the channel and queues again the latest update of each
+// calculator, update included, in order of first appearance. c.mu must be
+// held.
+func (c *Channel) coalesce(update ProgressUpdate) {
+	var order []int
+	latest := make(map[int]ProgressUpdate)
+	keep := func(u ProgressUpdate) {
+		if _, seen := latest[u.CalculatorIndex]; !seen {
+			order = append(order, u.CalculatorIndex)
+		}
+		latest[u.CalculatorIndex] = u
+	}
+	queued := 0
+drain:
+	for {
+		select {
+		case u := <-c.ch:
+			keep(u)
+			queued++
+		default:
+			break drain
+		}
+	}
+	keep(update)
+	// The updates taken back out no longer count as sent
+	c.record(-int64(queued), 0, int64(queued+1-len(order)), 0)
+	for _, idx := range order {
+		c.sendDrop(c.ch, latest[idx])
+	}
+}
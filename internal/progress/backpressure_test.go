@@ -0,0 +1,147 @@
+package progress
+
+import (
+	"testing"
+	"time"
+)
+
+// drain returns the updates queued in c.
+func drain(c *Channel) []ProgressUpdate {
+	var got []ProgressUpdate
+	for {
+		select {
+		case u := <-c.ch:
+			got = append(got, u)
+		default:
+			return got
+		}
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	t.Parallel()
+	for _, p := range Policies {
+		if got, err := ParsePolicy(string(p)); err != nil || got != p {
+			t.Errorf("ParsePolicy(%q) = %q, %v", p, got, err)
+		}
+	}
+	if got, err := ParsePolicy(""); err != nil || got != PolicyDrop {
+		t.Errorf(`ParsePolicy("") = %q, %v; want drop`, got, err)
+	}
+	if _, err := ParsePolicy("latest"); err == nil {
+		t.Error(`ParsePolicy("latest") succeeded`)
+	}
+}
+
+func TestChannelPolicies(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		policy   Policy
+		capacity int
+		updates  []ProgressUpdate
+		want     []ProgressUpdate
+		stats    DropStats
+	}{
+		{
+			name: "drop", policy: PolicyDrop, capacity: 1,
+			updates: []ProgressUpdate{{0, 0.1}, {0, 0.2}},
+			want:    []ProgressUpdate{{0, 0.1}},
+			stats:   DropStats{Sent: 1, Dropped: 1},
+		},
+		{
+			name: "drop-oldest", policy: PolicyDropOldest, capacity: 2,
+			updates: []ProgressUpdate{{0, 0.1}, {1, 0.1}, {0, 0.2}},
+			want:    []ProgressUpdate{{1, 0.1}, {0, 0.2}},
+			stats:   DropStats{Sent: 3, Dropped: 1},
+		},
+		{
+			name: "coalesce", policy: PolicyCoalesce, capacity: 2,
+			updates: []ProgressUpdate{{0, 0.1}, {1, 0.1}, {0, 0.2}, {0, 0.3}},
+			want:    []ProgressUpdate{{0, 0.3}, {1, 0.1}},
+			stats:   DropStats{Sent: 2, Coalesced: 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			c := NewChannel(tt.capacity, tt.policy, 0)
+			defer c.Close()
+			obs := NewChannelObserver(c.Send())
+			for _, u := range tt.updates {
+				obs.Update(u.CalculatorIndex, u.Value)
+			}
+			got := drain(c)
+			if len(got) != len(tt.want) {
+				t.Fatalf("queued %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("queued %v, want %v", got, tt.want)
+					break
+				}
+			}
+			if s := c.Stats(); s != tt.stats {
+				t.Errorf("Stats() = %+v, want %+v", s, tt.stats)
+			}
+		})
+	}
+}
+
+func TestChannelBlock(t *testing.T) {
+	t.Parallel()
+
+	t.Run("room made in time", func(t *testing.T) {
+		t.Parallel()
+		c := NewChannel(1, PolicyBlock, time.Minute)
+		defer c.Close()
+		obs := NewChannelObserver(c.Send())
+		obs.Update(0, 0.1)
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			<-c.C()
+		}()
+		obs.Update(0, 0.2)
+		if got := <-c.C(); got.Value != 0.2 {
+			t.Errorf("received %v, want the blocked update", got)
+		}
+		if s := c.Stats(); s != (DropStats{Sent: 2, Blocked: 1}) {
+			t.Errorf("Stats() = %+v", s)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Parallel()
+		c := NewChannel(1, PolicyBlock, time.Millisecond)
+		defer c.Close()
+		obs := NewChannelObserver(c.Send())
+		obs.Update(0, 0.1)
+		obs.Update(0, 0.2)
+		if s := c.Stats(); s != (DropStats{Sent: 1, Dropped: 1, Blocked: 1}) {
+			t.Errorf("Stats() = %+v", s)
+		}
+	})
+}
+
+func TestChannelRegistry(t *testing.T) {
+	t.Parallel()
+	c := NewChannel(1, PolicyCoalesce, 0)
+	if lookupChannel(c.Send()) != c {
+		t.Fatal("an open Channel is not found from its send side")
+	}
+	c.Close()
+	if lookupChannel(c.Send()) != nil {
+		t.Error("a closed Channel is still registered")
+	}
+
+	// Plain channels keep the drop policy and count in the totals
+	plain := make(chan ProgressUpdate, 1)
+	before := TotalStats()
+	obs := NewChannelObserver(plain)
+	obs.Update(0, 0.5)
+	obs.Update(0, 0.6)
+	after := TotalStats()
+	if after.Sent < before.Sent+1 || after.Dropped < before.Dropped+1 {
+		t.Errorf("TotalStats() went from %+v to %+v, want one more sent and dropped", before, after)
+	}
+}
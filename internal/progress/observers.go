@@ -18,10 +18,14 @@ import (
 // progress updates via channels.
 type ChannelObserver struct {
 	channel chan<- ProgressUpdate
+	// policy is the Channel that ch belongs to, nil for a plain channel.
+	policy *Channel
 }
 
 // NewChannelObserver creates an observer that sends updates to a channel.
-// The channel should have sufficient buffer capacity to avoid blocking.
+// If ch is the Send side of a Channel, updates follow its backpressure
+// policy; otherwise they are dropped when the channel is full (PolicyDrop),
+// so the channel should have sufficient buffer capacity.
 //
 // Parameters:
 //   - ch: The channel to send progress updates to. If nil, updates are discarded.
@@ -29,11 +33,15 @@ type ChannelObserver struct {
 // Returns:
 //   - *ChannelObserver: A new observer that forwards to the channel.
 func NewChannelObserver(ch chan<- ProgressUpdate) *ChannelObserver {
-	return &ChannelObserver{channel: ch}
+	if ch == nil {
+		return &ChannelObserver{}
+	}
+	return &ChannelObserver{channel: ch, policy: lookupChannel(ch)}
 }
 
-// Update implements ProgressObserver by sending to the channel.
-// Uses non-blocking send to avoid deadlocks when the channel is full.
+// Update implements ProgressObserver by sending to the channel. When the
+// channel is full, the update is handled by the backpressure policy; the
+// outcome is counted in TotalStats.
 //
 // Parameters:
 //   - calcIndex: The calculator instance identifier.
@@ -49,13 +57,12 @@ func (o *ChannelObserver) Update(calcIndex int, progress float64) {
 	}
 
 	update := ProgressUpdate{CalculatorIndex: calcIndex, Value: progress}
-
-	// Non-blocking send to avoid deadlocks
-	select {
-	case o.channel <- update:
-	default:
-		// Channel full, drop update (UI will catch up on next update)
+	if o.policy != nil {
+		o.policy.send(update)
+		return
 	}
+	// Plain channel: drop the update if full (UI will catch up on next update)
+	o.policy.sendDrop(o.channel, update)
 }
 
 // ─────────────────────────────────────────────────────────────────────────────
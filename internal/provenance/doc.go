@@ -0,0 +1,11 @@
+// Package provenance signs calculation results with ed25519 so that results
+// exchanged between researchers carry verifiable provenance. A signature
+// attests a Statement (the index n, the algorithm, the fibcalc version and
+// the SHA-256 digest of the value) and embeds the signer's public key; it
+// is stored in the header of result files and in saved TUI reports, and
+// checked by `fibcalc verify-signature`.
+//
+// Keys are PEM-encoded PKCS#8 ed25519 private keys, as written by
+// `openssl genpkey -algorithm ed25519`; the matching public key, for
+// pinning the signer, is the PKIX PEM of `openssl pkey -pubout`.
+package provenance
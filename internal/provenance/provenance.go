@@ -0,0 +1,364 @@
+package provenance
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/agbru/fibcalc/internal/audit"
+)
+
+// Verification errors, wrapped with details by Verify and VerifyFile.
+var (
+	// ErrUnsigned reports a file without a signature.
+	ErrUnsigned = errors.New("no signature")
+	// ErrBadSignature reports a signature that does not match the signed
+	// statement: the file was modified after signing.
+	ErrBadSignature = errors.New("invalid signature")
+	// ErrUntrustedKey reports a valid signature by another key than the
+	// one the signer was pinned to.
+	ErrUntrustedKey = errors.New("signed by an untrusted key")
+	// ErrDigestMismatch reports a value whose digest differs from the
+	// signed one.
+	ErrDigestMismatch = errors.New("value does not match the signed digest")
+)
+
+// Header fields of a signed result file, after the fields written by every
+// result file (Algorithm, N, ...).
+const (
+	headerVersion   = "Version"
+	headerDigest    = "Result-SHA256"
+	headerPublicKey = "Public-Key"
+	headerSignature = "Signature"
+)
+
+// signatureContext prefixes the signed message so that a signature cannot
+// be replayed for another purpose.
+const signatureContext = "fibcalc result signature v1"
+
+// Statement is what a signature attests about a result.
+type Statement struct {
+	N            uint64 `json:"n"`
+	Algorithm    string `json:"algorithm"`
+	Version      string `json:"version"`
+	ResultSHA256 string `json:"result_sha256"`
+}
+
+// NewStatement returns the statement about F(n) = result.
+//
+// Parameters:
+//   - n: The index.
+//   - algorithm: The algorithm that computed the result.
+//   - version: The fibcalc version.
+//   - result: The value, hashed with audit.HashResult.
+//
+// Returns:
+//   - Statement: The statement.
+func NewStatement(n uint64, algorithm, version string, result *big.Int) Statement {
+	return Statement{N: n, Algorithm: algorithm, Version: version, ResultSHA256: audit.HashResult(result)}
+}
+
+// message returns the bytes signed for s, one field per line.
+func (s Statement) message() []byte {
+	return fmt.Appendf(nil, "%s\nn=%d\nalgorithm=%s\nversion=%s\nresult_sha256=%s\n",
+		signatureContext, s.N, s.Algorithm, s.Version, s.ResultSHA256)
+}
+
+// Signature is a signed Statement with the signer's public key. Its JSON
+// form is the "provenance" object of saved reports.
+type Signature struct {
+	Statement
+	// PublicKey is the base64 ed25519 public key of the signer.
+	PublicKey string `json:"public_key"`
+	// Signature is the base64 ed25519 signature of the statement.
+	Signature string `json:"signature"`
+}
+
+// Sign signs s with key.
+//
+// Parameters:
+//   - key: The signer's private key.
+//   - s: The statement.
+//
+// Returns:
+//   - Signature: The signature, embedding the public key.
+func Sign(key ed25519.PrivateKey, s Statement) Signature {
+	return Signature{
+		Statement: s,
+		PublicKey: base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)),
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(key, s.message())),
+	}
+}
+
+// Verify checks the signature against its statement and embedded public
+// key, and that the key is trusted when one is given.
+//
+// Parameters:
+//   - trusted: The only key accepted as signer, or nil to accept any.
+//
+// Returns:
+//   - error: nil if the signature is valid; otherwise an error wrapping
+//     ErrBadSignature or ErrUntrustedKey.
+func (s Signature) Verify(trusted ed25519.PublicKey) error {
+	pub, err := base64.StdEncoding.DecodeString(s.PublicKey)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return fmt.Errorf("%w: malformed public key", ErrBadSignature)
+	}
+	sig, err := base64.StdEncoding.DecodeString(s.Signature)
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return fmt.Errorf("%w: malformed signature", ErrBadSignature)
+	}
+	if !ed25519.Verify(pub, s.message(), sig) {
+		return ErrBadSignature
+	}
+	if trusted != nil && !trusted.Equal(ed25519.PublicKey(pub)) {
+		return fmt.Errorf("%w (%s)", ErrUntrustedKey, s.Fingerprint())
+	}
+	return nil
+}
+
+// Fingerprint identifies the signer's key in the format of OpenSSH:
+// "SHA256:" followed by the unpadded base64 digest of the key.
+func (s Signature) Fingerprint() string {
+	pub, err := base64.StdEncoding.DecodeString(s.PublicKey)
+	if err != nil {
+		return "invalid key"
+	}
+	return Fingerprint(pub)
+}
+
+// Fingerprint returns the fingerprint of a public key (see
+// Signature.Fingerprint).
+func Fingerprint(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:])
+}
+
+// WriteHeader writes the signature as "# Field: value" header lines of a
+// result file, to follow its Algorithm and N lines.
+//
+// Parameters:
+//   - w: The destination.
+//   - s: The signature.
+func WriteHeader(w io.Writer, s Signature) {
+	fmt.Fprintf(w, "# %s: %s\n", headerVersion, s.Version)
+	fmt.Fprintf(w, "# %s: %s\n", headerDigest, s.ResultSHA256)
+	fmt.Fprintf(w, "# %s: %s\n", headerPublicKey, s.PublicKey)
+	fmt.Fprintf(w, "# %s: %s\n", headerSignature, s.Signature)
+}
+
+// LoadPrivateKey reads a PEM-encoded PKCS#8 ed25519 private key.
+//
+// Parameters:
+//   - path: The key file.
+//
+// Returns:
+//   - ed25519.PrivateKey: The key.
+//   - error: An error if the file cannot be read or holds no such key.
+func LoadPrivateKey(path string) (ed25519.PrivateKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("signing key %q: %w", path, err)
+	}
+	priv, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("signing key %q is not an ed25519 key", path)
+	}
+	return priv, nil
+}
+
+// LoadPublicKey reads a PEM-encoded PKIX ed25519 public key, or derives it
+// from a private key file.
+//
+// Parameters:
+//   - path: The key file.
+//
+// Returns:
+//   - ed25519.PublicKey: The key.
+//   - error: An error if the file cannot be read or holds no such key.
+func LoadPublicKey(path string) (ed25519.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	if block.Type == "PRIVATE KEY" {
+		priv, err := LoadPrivateKey(path)
+		if err != nil {
+			return nil, err
+		}
+		return priv.Public().(ed25519.PublicKey), nil
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("public key %q: %w", path, err)
+	}
+	pub, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key %q is not an ed25519 key", path)
+	}
+	return pub, nil
+}
+
+// readPEM returns the first PEM block of a file.
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("key %q is not PEM-encoded", path)
+	}
+	return block, nil
+}
+
+// Verification is the outcome of VerifyFile.
+type Verification struct {
+	Signature
+	// ValueChecked is true if the file holds the value and its digest
+	// matched the signed one; reports only carry the digest.
+	ValueChecked bool
+}
+
+// VerifyFile checks the signature of a result file or saved report.
+// Result files are verified against the value they hold: the signature
+// must cover the header fields and the value's digest. Reports, which do
+// not hold the value, must match the signed n and algorithm.
+//
+// Parameters:
+//   - path: The result file or report.
+//   - trusted: The only key accepted as signer, or nil to accept any.
+//
+// Returns:
+//   - Verification: The verified signature.
+//   - error: An error wrapping ErrUnsigned, ErrBadSignature,
+//     ErrUntrustedKey or ErrDigestMismatch if the file does not verify.
+func VerifyFile(path string, trusted ed25519.PublicKey) (Verification, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return Verification{}, fmt.Errorf("failed to read %q: %w", path, err)
+	}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		return verifyReport(data, trusted)
+	}
+	return verifyResult(data, trusted)
+}
+
+// verifyReport verifies the provenance of a JSON report.
+func verifyReport(data []byte, trusted ed25519.PublicKey) (Verification, error) {
+	var report struct {
+		N          uint64     `json:"n"`
+		Algorithm  string     `json:"algorithm"`
+		Provenance *Signature `json:"provenance"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return Verification{}, fmt.Errorf("failed to decode report: %w", err)
+	}
+	if report.Provenance == nil {
+		return Verification{}, ErrUnsigned
+	}
+	v := Verification{Signature: *report.Provenance}
+	if err := v.Verify(trusted); err != nil {
+		return v, err
+	}
+	if report.N != v.N || report.Algorithm != v.Algorithm {
+		return v, fmt.Errorf("%w: the report is about F(%d) by %s, the signature about F(%d) by %s",
+			ErrBadSignature, report.N, report.Algorithm, v.N, v.Algorithm)
+	}
+	return v, nil
+}
+
+// verifyResult verifies a result file: "# Field: value" header lines, a
+// blank line, "F(n) =" and the decimal value, on one line or wrapped (see
+// joinValue).
+func verifyResult(data []byte, trusted ed25519.PublicKey) (Verification, error) {
+	header := make(map[string]string)
+	var body []string
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(nil, len(data)+1)
+	for sc.Scan() {
+		line := sc.Text()
+		if field, ok := strings.CutPrefix(line, "# "); ok && body == nil {
+			if name, value, ok := strings.Cut(field, ": "); ok {
+				header[name] = value
+			}
+			continue
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			body = append(body, line)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return Verification{}, fmt.Errorf("failed to read result: %w", err)
+	}
+	if header[headerSignature] == "" {
+		return Verification{}, ErrUnsigned
+	}
+
+	n, err := strconv.ParseUint(header["N"], 10, 64)
+	if err != nil {
+		return Verification{}, fmt.Errorf("%w: invalid N %q", ErrBadSignature, header["N"])
+	}
+	v := Verification{Signature: Signature{
+		Statement: Statement{
+			N:            n,
+			Algorithm:    header["Algorithm"],
+			Version:      header[headerVersion],
+			ResultSHA256: header[headerDigest],
+		},
+		PublicKey: header[headerPublicKey],
+		Signature: header[headerSignature],
+	}}
+	if err := v.Verify(trusted); err != nil {
+		return v, err
+	}
+
+	if len(body) < 2 || body[0] != fmt.Sprintf("F(%d) =", n) {
+		return v, fmt.Errorf("%w: no value of F(%d) in the file", ErrDigestMismatch, n)
+	}
+	value, ok := new(big.Int).SetString(joinValue(body[1:]), 10)
+	if !ok {
+		return v, fmt.Errorf("%w: the value is not a decimal number", ErrDigestMismatch)
+	}
+	if audit.HashResult(value) != v.ResultSHA256 {
+		return v, ErrDigestMismatch
+	}
+	v.ValueChecked = true
+	return v, nil
+}
+
+// joinValue returns the digits of a value written on one line or, with
+// --wrap, on lines of digit groups preceded by their "[position]", under a
+// ruler of "+offset" columns.
+func joinValue(lines []string) string {
+	var b strings.Builder
+	for _, line := range lines {
+		if strings.HasPrefix(line, "+") {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(line, "["); ok {
+			if _, digits, ok := strings.Cut(rest, "]"); ok {
+				line = digits
+			}
+		}
+		b.WriteString(strings.ReplaceAll(line, " ", ""))
+	}
+	return b.String()
+}
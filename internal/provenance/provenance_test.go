@@ -0,0 +1,170 @@
+package provenance
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newKey returns a fresh key, saved as PKCS#8 PEM in dir.
+func newKey(t *testing.T, dir, name string) (ed25519.PrivateKey, string) {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return priv, path
+}
+
+// writeResult writes a signed result file of F(10) = 55 in the format of
+// the CLI's --output files.
+func writeResult(t *testing.T, path string, key ed25519.PrivateKey) {
+	t.Helper()
+	var b strings.Builder
+	b.WriteString("# Fibonacci Calculation Result\n# Generated: 2026-01-01T00:00:00Z\n# Algorithm: fast\n# N: 10\n")
+	WriteHeader(&b, Sign(key, NewStatement(10, "fast", "v1.2.3", big.NewInt(55))))
+	b.WriteString("\nF(10) =\n55\n")
+	if err := os.WriteFile(path, []byte(b.String()), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	key, keyPath := newKey(t, dir, "key.pem")
+	other, _ := newKey(t, dir, "other.pem")
+
+	loaded, err := LoadPrivateKey(keyPath)
+	if err != nil || !loaded.Equal(key) {
+		t.Fatalf("LoadPrivateKey() = %v; want the saved key", err)
+	}
+	pub, err := LoadPublicKey(keyPath)
+	if err != nil || !pub.Equal(key.Public()) {
+		t.Fatalf("LoadPublicKey() of a private key = %v; want its public key", err)
+	}
+
+	sig := Sign(key, NewStatement(10, "fast", "v1.2.3", big.NewInt(55)))
+	if err := sig.Verify(nil); err != nil {
+		t.Errorf("Verify(nil) = %v", err)
+	}
+	if err := sig.Verify(pub); err != nil {
+		t.Errorf("Verify(signer) = %v", err)
+	}
+	if err := sig.Verify(other.Public().(ed25519.PublicKey)); !errors.Is(err, ErrUntrustedKey) {
+		t.Errorf("Verify(other key) = %v, want ErrUntrustedKey", err)
+	}
+	tampered := sig
+	tampered.N = 11
+	if err := tampered.Verify(nil); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("Verify() of a modified statement = %v, want ErrBadSignature", err)
+	}
+	if got, want := sig.Fingerprint(), Fingerprint(pub); got != want || !strings.HasPrefix(got, "SHA256:") {
+		t.Errorf("Fingerprint() = %q, want %q", got, want)
+	}
+}
+
+func TestVerifyFile(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	key, _ := newKey(t, dir, "key.pem")
+	path := filepath.Join(dir, "result.txt")
+	writeResult(t, path, key)
+
+	v, err := VerifyFile(path, key.Public().(ed25519.PublicKey))
+	if err != nil || !v.ValueChecked || v.N != 10 || v.Algorithm != "fast" || v.Version != "v1.2.3" {
+		t.Fatalf("VerifyFile() = %+v, %v; want a checked F(10) by fast", v, err)
+	}
+
+	// A value wrapped by --wrap
+	data, _ := os.ReadFile(path)
+	wrapped := filepath.Join(dir, "wrapped.txt")
+	ruled := strings.Replace(string(data), "\n55\n", "\n     +0\n[1]  5\n[2]  5\n", 1)
+	if err := os.WriteFile(wrapped, []byte(ruled), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := VerifyFile(wrapped, nil); err != nil || !v.ValueChecked {
+		t.Errorf("VerifyFile() of a wrapped value = %+v, %v; want it checked", v, err)
+	}
+
+	tests := []struct {
+		name, old, new string
+		want           error
+	}{
+		{"value", "\n55\n", "\n56\n", ErrDigestMismatch},
+		{"index", "# N: 10", "# N: 11", ErrBadSignature},
+		{"algorithm", "# Algorithm: fast", "# Algorithm: matrix", ErrBadSignature},
+		{"unsigned", "# Signature:", "# Comment:", ErrUnsigned},
+	}
+	for _, tt := range tests {
+		modified := filepath.Join(dir, tt.name+".txt")
+		if err := os.WriteFile(modified, []byte(strings.Replace(string(data), tt.old, tt.new, 1)), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := VerifyFile(modified, nil); !errors.Is(err, tt.want) {
+			t.Errorf("VerifyFile() with a modified %s = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestVerifyReport(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	key, _ := newKey(t, dir, "key.pem")
+	sig := Sign(key, NewStatement(10, "fast", "v1.2.3", big.NewInt(55)))
+
+	write := func(name string, n uint64, sig *Signature) string {
+		data, err := json.Marshal(map[string]any{"n": n, "algorithm": "fast", "provenance": sig})
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	v, err := VerifyFile(write("report.json", 10, &sig), nil)
+	if err != nil || v.ValueChecked || v.ResultSHA256 != sig.ResultSHA256 {
+		t.Errorf("VerifyFile() of a report = %+v, %v; want the signed digest, unchecked", v, err)
+	}
+	if _, err := VerifyFile(write("moved.json", 11, &sig), nil); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("VerifyFile() of a report about another n = %v, want ErrBadSignature", err)
+	}
+	if _, err := VerifyFile(write("unsigned.json", 10, nil), nil); !errors.Is(err, ErrUnsigned) {
+		t.Errorf("VerifyFile() of an unsigned report = %v, want ErrUnsigned", err)
+	}
+}
+
+func TestLoadKeyErrors(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "key.txt")
+	if err := os.WriteFile(notPEM, []byte("secret"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{notPEM, filepath.Join(dir, "missing.pem")} {
+		if _, err := LoadPrivateKey(path); err == nil {
+			t.Errorf("LoadPrivateKey(%q) succeeded", path)
+		}
+		if _, err := LoadPublicKey(path); err == nil {
+			t.Errorf("LoadPublicKey(%q) succeeded", path)
+		}
+	}
+}
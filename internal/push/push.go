@@ -0,0 +1,304 @@
+// Package push sends run summaries and live gauges to a metrics backend
+// (InfluxDB or an OpenTelemetry collector) over HTTP, so that calculations
+// can be followed in existing Grafana dashboards without scraping.
+//
+// Two wire formats are supported without extra dependencies: InfluxDB line
+// protocol (POSTed to a /api/v2/write URL) and OTLP/HTTP with JSON encoding
+// (POSTed to a collector's /v1/metrics URL). Every field of a Sample is a
+// gauge.
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Format is a wire format for samples.
+type Format string
+
+const (
+	// FormatInflux is the InfluxDB line protocol.
+	FormatInflux Format = "influx"
+	// FormatOTLP is OTLP/HTTP with JSON encoding.
+	FormatOTLP Format = "otlp"
+)
+
+// Formats lists the supported formats.
+var Formats = []Format{FormatInflux, FormatOTLP}
+
+// DefaultInterval is the default period of the live gauges.
+const DefaultInterval = 10 * time.Second
+
+// requestTimeout bounds each push, so that an unreachable backend never
+// holds up a calculation or the exit.
+const requestTimeout = 5 * time.Second
+
+// Sample is a set of gauges measured at one time.
+type Sample struct {
+	// Name is the measurement (InfluxDB) or the prefix of the metric names
+	// (OTLP: Name_field).
+	Name string
+	// Tags are the dimensions of the sample (attributes in OTLP).
+	Tags map[string]string
+	// Fields are the gauge values.
+	Fields map[string]float64
+	// Time is when the values were measured.
+	Time time.Time
+}
+
+// ParseFormat validates a format name.
+//
+// Parameters:
+//   - s: The format name; empty means FormatInflux.
+//
+// Returns:
+//   - Format: The format.
+//   - error: An error if s is not one of Formats.
+func ParseFormat(s string) (Format, error) {
+	if s == "" {
+		return FormatInflux, nil
+	}
+	if slices.Contains(Formats, Format(s)) {
+		return Format(s), nil
+	}
+	return "", fmt.Errorf("unknown metrics push format %q (accepted values: influx, otlp)", s)
+}
+
+// ValidateURL checks that target is an absolute http or https URL.
+func ValidateURL(target string) error {
+	u, err := url.Parse(target)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("metrics push URL must be an http or https URL, got %q", target)
+	}
+	return nil
+}
+
+// Pusher sends samples to one endpoint. Errors do not interrupt anything:
+// they are kept, the first one being reported by Err. Pusher is safe for
+// concurrent use.
+type Pusher struct {
+	url    string
+	format Format
+	token  string
+	client *http.Client
+
+	mu  sync.Mutex
+	err error
+}
+
+// New creates a pusher.
+//
+// Parameters:
+//   - target: The write URL (InfluxDB /api/v2/write with org, bucket and
+//     precision=ns, or an OTLP collector's /v1/metrics).
+//   - format: The wire format.
+//   - token: The API token sent in the Authorization header ("Token" for
+//     InfluxDB, "Bearer" for OTLP); empty sends none.
+//
+// Returns:
+//   - *Pusher: The pusher.
+func New(target string, format Format, token string) *Pusher {
+	return &Pusher{url: target, format: format, token: token, client: &http.Client{Timeout: requestTimeout}}
+}
+
+// Push encodes samples and POSTs them. The error is also recorded for Err.
+//
+// Parameters:
+//   - ctx: The context of the request.
+//   - samples: The samples to send; nothing is sent if empty.
+//
+// Returns:
+//   - error: An error if the request fails or is rejected.
+func (p *Pusher) Push(ctx context.Context, samples []Sample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+	err := p.send(ctx, samples)
+	if err != nil {
+		p.mu.Lock()
+		if p.err == nil {
+			p.err = err
+		}
+		p.mu.Unlock()
+	}
+	return err
+}
+
+// Err returns the first push error, or nil.
+func (p *Pusher) Err() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+// send performs one request.
+func (p *Pusher) send(ctx context.Context, samples []Sample) error {
+	var body []byte
+	contentType := "text/plain; charset=utf-8"
+	auth := "Token "
+	if p.format == FormatOTLP {
+		var err error
+		if body, err = EncodeOTLP(samples); err != nil {
+			return fmt.Errorf("metrics push: encode: %w", err)
+		}
+		contentType, auth = "application/json", "Bearer "
+	} else {
+		body = EncodeInflux(samples)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("metrics push: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	if p.token != "" {
+		req.Header.Set("Authorization", auth+p.token)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("metrics push: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("metrics push: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// influxEscaper escapes measurement names, tag keys and tag values.
+var influxEscaper = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+
+// EncodeInflux encodes samples in InfluxDB line protocol with nanosecond
+// timestamps. Tags and fields are sorted by key.
+//
+// Parameters:
+//   - samples: The samples.
+//
+// Returns:
+//   - []byte: One line per sample.
+func EncodeInflux(samples []Sample) []byte {
+	var b bytes.Buffer
+	for _, s := range samples {
+		b.WriteString(influxEscaper.Replace(s.Name))
+		for _, k := range sortedKeys(s.Tags) {
+			if s.Tags[k] == "" {
+				continue // empty tag values are invalid
+			}
+			fmt.Fprintf(&b, ",%s=%s", influxEscaper.Replace(k), influxEscaper.Replace(s.Tags[k]))
+		}
+		for i, k := range sortedKeys(s.Fields) {
+			sep := ","
+			if i == 0 {
+				sep = " "
+			}
+			fmt.Fprintf(&b, "%s%s=%s", sep, influxEscaper.Replace(k), strconv.FormatFloat(s.Fields[k], 'g', -1, 64))
+		}
+		fmt.Fprintf(&b, " %d\n", s.Time.UnixNano())
+	}
+	return b.Bytes()
+}
+
+// OTLP/JSON message shapes (opentelemetry-proto metrics/v1), limited to
+// gauges of doubles.
+type (
+	otlpRequest struct {
+		ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+	}
+	otlpResourceMetrics struct {
+		Resource     otlpResource       `json:"resource"`
+		ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+	}
+	otlpResource struct {
+		Attributes []otlpAttribute `json:"attributes"`
+	}
+	otlpScopeMetrics struct {
+		Scope   otlpScope    `json:"scope"`
+		Metrics []otlpMetric `json:"metrics"`
+	}
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+	otlpMetric struct {
+		Name  string    `json:"name"`
+		Gauge otlpGauge `json:"gauge"`
+	}
+	otlpGauge struct {
+		DataPoints []otlpDataPoint `json:"dataPoints"`
+	}
+	otlpDataPoint struct {
+		Attributes   []otlpAttribute `json:"attributes,omitempty"`
+		TimeUnixNano string          `json:"timeUnixNano"`
+		AsDouble     float64         `json:"asDouble"`
+	}
+	otlpAttribute struct {
+		Key   string    `json:"key"`
+		Value otlpValue `json:"value"`
+	}
+	otlpValue struct {
+		StringValue string `json:"stringValue"`
+	}
+)
+
+// EncodeOTLP encodes samples as an OTLP/JSON ExportMetricsServiceRequest:
+// one gauge per field, named Name_field, with the tags as attributes, under
+// the resource service.name=fibcalc.
+//
+// Parameters:
+//   - samples: The samples.
+//
+// Returns:
+//   - []byte: The JSON request body.
+//   - error: An error if a value cannot be encoded (NaN, ±Inf).
+func EncodeOTLP(samples []Sample) ([]byte, error) {
+	var metrics []otlpMetric
+	index := make(map[string]int)
+	for _, s := range samples {
+		var attrs []otlpAttribute
+		for _, k := range sortedKeys(s.Tags) {
+			attrs = append(attrs, otlpAttribute{Key: k, Value: otlpValue{StringValue: s.Tags[k]}})
+		}
+		ts := strconv.FormatInt(s.Time.UnixNano(), 10)
+		for _, k := range sortedKeys(s.Fields) {
+			name := s.Name + "_" + k
+			i, ok := index[name]
+			if !ok {
+				i = len(metrics)
+				index[name] = i
+				metrics = append(metrics, otlpMetric{Name: name})
+			}
+			metrics[i].Gauge.DataPoints = append(metrics[i].Gauge.DataPoints,
+				otlpDataPoint{Attributes: attrs, TimeUnixNano: ts, AsDouble: s.Fields[k]})
+		}
+	}
+	return json.Marshal(otlpRequest{ResourceMetrics: []otlpResourceMetrics{{
+		Resource: otlpResource{Attributes: []otlpAttribute{
+			{Key: "service.name", Value: otlpValue{StringValue: "fibcalc"}},
+		}},
+		ScopeMetrics: []otlpScopeMetrics{{Scope: otlpScope{Name: "fibcalc"}, Metrics: metrics}},
+	}}})
+}
+
+// sortedKeys returns the keys of m in increasing order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,217 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/clock"
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/orchestration"
+	"github.com/agbru/fibcalc/internal/progress"
+)
+
+var sampleTime = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func TestEncodeInflux(t *testing.T) {
+	t.Parallel()
+	got := string(EncodeInflux([]Sample{{
+		Name:   "fibcalc_run",
+		Tags:   map[string]string{"n": "1000", "algorithm": "fast doubling", "empty": ""},
+		Fields: map[string]float64{"result_bits": 694, "duration_seconds": 0.25},
+		Time:   sampleTime,
+	}}))
+	want := `fibcalc_run,algorithm=fast\ doubling,n=1000 duration_seconds=0.25,result_bits=694 1772366400000000000` + "\n"
+	if got != want {
+		t.Errorf("EncodeInflux() =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestEncodeOTLP(t *testing.T) {
+	t.Parallel()
+	body, err := EncodeOTLP([]Sample{
+		{Name: "fibcalc_run", Tags: map[string]string{"algorithm": "fast"}, Fields: map[string]float64{"duration_seconds": 1}, Time: sampleTime},
+		{Name: "fibcalc_run", Tags: map[string]string{"algorithm": "fft"}, Fields: map[string]float64{"duration_seconds": 2}, Time: sampleTime},
+	})
+	if err != nil {
+		t.Fatalf("EncodeOTLP() error = %v", err)
+	}
+	var req otlpRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	metrics := req.ResourceMetrics[0].ScopeMetrics[0].Metrics
+	if len(metrics) != 1 || metrics[0].Name != "fibcalc_run_duration_seconds" {
+		t.Fatalf("metrics = %+v, want one fibcalc_run_duration_seconds gauge", metrics)
+	}
+	points := metrics[0].Gauge.DataPoints
+	if len(points) != 2 || points[1].AsDouble != 2 || points[1].Attributes[0].Value.StringValue != "fft" {
+		t.Errorf("data points = %+v", points)
+	}
+	if points[0].TimeUnixNano != "1772366400000000000" {
+		t.Errorf("timeUnixNano = %s", points[0].TimeUnixNano)
+	}
+}
+
+func TestPusher(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		format      Format
+		contentType string
+		auth        string
+	}{
+		{FormatInflux, "text/plain; charset=utf-8", "Token secret"},
+		{FormatOTLP, "application/json", "Bearer secret"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			t.Parallel()
+			var gotType, gotAuth, gotBody string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				gotType, gotAuth, gotBody = r.Header.Get("Content-Type"), r.Header.Get("Authorization"), string(body)
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer srv.Close()
+
+			p := New(srv.URL, tt.format, "secret")
+			err := p.Push(context.Background(), []Sample{{Name: "m", Fields: map[string]float64{"v": 1}, Time: sampleTime}})
+			if err != nil || p.Err() != nil {
+				t.Fatalf("Push() error = %v", err)
+			}
+			if gotType != tt.contentType || gotAuth != tt.auth {
+				t.Errorf("headers = %q, %q; want %q, %q", gotType, gotAuth, tt.contentType, tt.auth)
+			}
+			if !strings.Contains(gotBody, "m") {
+				t.Errorf("body = %q", gotBody)
+			}
+		})
+	}
+}
+
+func TestPusherError(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "bucket not found", http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	p := New(srv.URL, FormatInflux, "")
+	err := p.Push(context.Background(), []Sample{{Name: "m", Fields: map[string]float64{"v": 1}, Time: sampleTime}})
+	if err == nil || !strings.Contains(err.Error(), "bucket not found") {
+		t.Fatalf("Push() error = %v, want the server's message", err)
+	}
+	if !errors.Is(p.Err(), err) {
+		t.Errorf("Err() = %v, want %v", p.Err(), err)
+	}
+}
+
+func TestParseFormatAndURL(t *testing.T) {
+	t.Parallel()
+	if f, err := ParseFormat(""); err != nil || f != FormatInflux {
+		t.Errorf(`ParseFormat("") = %q, %v`, f, err)
+	}
+	if _, err := ParseFormat("statsd"); err == nil {
+		t.Error(`ParseFormat("statsd") succeeded`)
+	}
+	for _, u := range []string{"http://localhost:8086/api/v2/write", "https://otel:4318/v1/metrics"} {
+		if err := ValidateURL(u); err != nil {
+			t.Errorf("ValidateURL(%q) = %v", u, err)
+		}
+	}
+	for _, u := range []string{"localhost:8086", "udp://host:8089", "http://"} {
+		if err := ValidateURL(u); err == nil {
+			t.Errorf("ValidateURL(%q) succeeded", u)
+		}
+	}
+}
+
+func TestReporter(t *testing.T) {
+	t.Parallel()
+	bodies := make(chan string, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies <- string(body)
+	}))
+	defer srv.Close()
+
+	clk := clock.NewFake(sampleTime)
+	var forwarded []progress.ProgressUpdate
+	inner := orchestration.ProgressReporterFunc(func(wg *sync.WaitGroup, ch <-chan progress.ProgressUpdate, _ int, _ io.Writer) {
+		defer wg.Done()
+		for u := range ch {
+			forwarded = append(forwarded, u)
+		}
+	})
+	r := &Reporter{
+		Inner:      inner,
+		Pusher:     New(srv.URL, FormatInflux, ""),
+		Interval:   time.Second,
+		Algorithms: []string{"fast", "fft"},
+		N:          1000,
+		Clock:      clk,
+	}
+
+	ch := make(chan progress.ProgressUpdate, 4)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go r.DisplayProgress(&wg, ch, 2, io.Discard)
+
+	ch <- progress.ProgressUpdate{CalculatorIndex: 1, Value: 0.5}
+	for clk.Pending() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	// Let the reporter record the update before the tick
+	for len(ch) > 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	clk.Advance(time.Second)
+	body := <-bodies
+	close(ch)
+	wg.Wait()
+
+	for _, want := range []string{
+		"fibcalc_progress,algorithm=fast,n=1000 elapsed_seconds=1,progress=0 ",
+		"fibcalc_progress,algorithm=fft,n=1000 elapsed_seconds=1,progress=0.5 ",
+		"fibcalc_process,n=1000 goroutines=",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("pushed body lacks %q:\n%s", want, body)
+		}
+	}
+	if len(forwarded) != 1 || forwarded[0].Value != 0.5 {
+		t.Errorf("forwarded = %+v, want the update passed on to Inner", forwarded)
+	}
+}
+
+func TestRunSamples(t *testing.T) {
+	t.Parallel()
+	results := []orchestration.CalculationResult{
+		{Name: "Fast Doubling", Result: big.NewInt(1 << 20), Duration: 2 * time.Second},
+		{Name: "FFT", Err: context.DeadlineExceeded, Kind: apperrors.ErrorKindTimeout, Duration: time.Second},
+	}
+	samples := RunSamples(results, []string{"fast", ""}, 100, "v1", sampleTime)
+	if len(samples) != 2 {
+		t.Fatalf("RunSamples() returned %d samples, want 2", len(samples))
+	}
+	ok := samples[0]
+	if ok.Tags["algorithm"] != "fast" || ok.Tags["status"] != "ok" || ok.Fields["result_bits"] != 21 || ok.Fields["bits_per_second"] != 10.5 {
+		t.Errorf("samples[0] = %+v", ok)
+	}
+	failed := samples[1]
+	if failed.Tags["algorithm"] != "FFT" || failed.Tags["status"] != "timeout" {
+		t.Errorf("samples[1] = %+v", failed)
+	}
+	if _, has := failed.Fields["result_bits"]; has {
+		t.Errorf("failed sample has result_bits: %+v", failed)
+	}
+}
@@ -0,0 +1,180 @@
+package push
+
+import (
+	"context"
+	"io"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/clock"
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/orchestration"
+	"github.com/agbru/fibcalc/internal/progress"
+)
+
+// Measurement names of the pushed samples.
+const (
+	// MeasurementProgress holds the live progress of each algorithm.
+	MeasurementProgress = "fibcalc_progress"
+	// MeasurementProcess holds the live memory use of the process.
+	MeasurementProcess = "fibcalc_process"
+	// MeasurementRun holds the summary of each algorithm's run.
+	MeasurementRun = "fibcalc_run"
+)
+
+// Reporter is an orchestration.ProgressReporter that pushes live gauges
+// every Interval while passing the progress updates on to Inner, which
+// keeps displaying them. Pushes run in the background, one at a time: a
+// tick arriving while the previous push is still in flight is skipped.
+type Reporter struct {
+	// Inner displays the progress; nil means orchestration.NullProgressReporter.
+	Inner orchestration.ProgressReporter
+	// Pusher sends the samples.
+	Pusher *Pusher
+	// Interval is the period of the gauges; zero means DefaultInterval.
+	Interval time.Duration
+	// Algorithms names the calculator of each progress index.
+	Algorithms []string
+	// N is the index being computed.
+	N uint64
+	// Clock is the time source; nil means the wall clock.
+	Clock clock.Clock
+}
+
+// Verify interface compliance.
+var _ orchestration.ProgressReporter = (*Reporter)(nil)
+
+// DisplayProgress forwards the updates to Inner and pushes the gauges until
+// progressChan is closed. It returns after Inner and the last push are
+// done.
+func (r *Reporter) DisplayProgress(wg *sync.WaitGroup, progressChan <-chan progress.ProgressUpdate, numCalculators int, out io.Writer) {
+	defer wg.Done()
+
+	inner := r.Inner
+	if inner == nil {
+		inner = orchestration.NullProgressReporter{}
+	}
+	forward := make(chan progress.ProgressUpdate, cap(progressChan))
+	var innerWG sync.WaitGroup
+	innerWG.Add(1)
+	go inner.DisplayProgress(&innerWG, forward, numCalculators, out)
+
+	interval := r.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	clk := clock.Or(r.Clock)
+	start := clk.Now()
+	ticker := clk.NewTicker(interval)
+	defer ticker.Stop()
+
+	values := make([]float64, numCalculators)
+	inFlight := make(chan struct{}, 1)
+loop:
+	for {
+		select {
+		case update, ok := <-progressChan:
+			if !ok {
+				break loop
+			}
+			if update.CalculatorIndex >= 0 && update.CalculatorIndex < len(values) {
+				values[update.CalculatorIndex] = update.Value
+			}
+			forward <- update
+		case now := <-ticker.C():
+			select {
+			case inFlight <- struct{}{}:
+				samples := r.samples(values, now, now.Sub(start))
+				go func() {
+					defer func() { <-inFlight }()
+					_ = r.Pusher.Push(context.Background(), samples)
+				}()
+			default:
+			}
+		}
+	}
+
+	close(forward)
+	innerWG.Wait()
+	inFlight <- struct{}{}
+}
+
+// samples builds the gauges of one tick.
+func (r *Reporter) samples(values []float64, now time.Time, elapsed time.Duration) []Sample {
+	n := strconv.FormatUint(r.N, 10)
+	samples := make([]Sample, 0, len(values)+1)
+	for i, v := range values {
+		samples = append(samples, Sample{
+			Name:   MeasurementProgress,
+			Tags:   map[string]string{"algorithm": r.algorithm(i), "n": n},
+			Fields: map[string]float64{"progress": v, "elapsed_seconds": elapsed.Seconds()},
+			Time:   now,
+		})
+	}
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	drops := progress.TotalStats()
+	samples = append(samples, Sample{
+		Name: MeasurementProcess,
+		Tags: map[string]string{"n": n},
+		Fields: map[string]float64{
+			"heap_bytes":         float64(ms.HeapAlloc),
+			"goroutines":         float64(runtime.NumGoroutine()),
+			"progress_dropped":   float64(drops.Dropped),
+			"progress_coalesced": float64(drops.Coalesced),
+		},
+		Time: now,
+	})
+	return samples
+}
+
+// algorithm returns the name of calculator i.
+func (r *Reporter) algorithm(i int) string {
+	if i < len(r.Algorithms) {
+		return r.Algorithms[i]
+	}
+	return strconv.Itoa(i)
+}
+
+// RunSamples summarizes the results of a run, one sample per algorithm with
+// its duration, the size of F(n) and the throughput in bits per second.
+//
+// Parameters:
+//   - results: The results of the run.
+//   - algorithms: The registered name of each result's algorithm, in order.
+//   - n: The index computed.
+//   - version: The version of fibcalc.
+//   - now: The time of the samples.
+//
+// Returns:
+//   - []Sample: The samples, tagged with status "ok" or the error kind.
+func RunSamples(results []orchestration.CalculationResult, algorithms []string, n uint64, version string, now time.Time) []Sample {
+	samples := make([]Sample, 0, len(results))
+	for i, res := range results {
+		name := res.Name
+		if i < len(algorithms) && algorithms[i] != "" {
+			name = algorithms[i]
+		}
+		status := "ok"
+		if kind := res.ErrorKind(); kind != apperrors.ErrorKindNone {
+			status = kind.String()
+		}
+		fields := map[string]float64{"duration_seconds": res.Duration.Seconds()}
+		if res.Result != nil {
+			bits := float64(res.Result.BitLen())
+			fields["result_bits"] = bits
+			if res.Duration > 0 {
+				fields["bits_per_second"] = bits / res.Duration.Seconds()
+			}
+		}
+		samples = append(samples, Sample{
+			Name:   MeasurementRun,
+			Tags:   map[string]string{"algorithm": name, "n": strconv.FormatUint(n, 10), "version": version, "status": status},
+			Fields: fields,
+			Time:   now,
+		})
+	}
+	return samples
+}
@@ -0,0 +1,154 @@
+package sysmon
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/cpu"
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// Contention thresholds of ContentionMonitor, as the fraction of the
+// machine's CPU time used by other processes. The gap between them keeps a
+// load hovering around one threshold from toggling the mode every sample.
+const (
+	// ContentionHigh is the external load from which multiplications run
+	// sequentially.
+	ContentionHigh = 0.7
+	// ContentionLow is the external load below which they run in parallel
+	// again.
+	ContentionLow = 0.4
+)
+
+// DefaultContentionInterval is the sampling period of ContentionMonitor.Run.
+const DefaultContentionInterval = time.Second
+
+// cpuTimes is a reading of the cumulative CPU time, in seconds summed over
+// all CPUs.
+type cpuTimes struct {
+	busy  float64 // used by every process, system included
+	total float64 // elapsed, busy or idle
+	own   float64 // used by this process
+}
+
+// ContentionMonitor samples the CPU time used by other processes and
+// decides whether parallel multiplications are worthwhile: when other
+// processes keep most CPUs busy, goroutines multiplying in parallel only
+// compete with them and with each other, and a sequential multiplication
+// finishes sooner. It implements fibonacci.ParallelGate and is safe for
+// concurrent use.
+type ContentionMonitor struct {
+	read       func() (cpuTimes, error)
+	sequential atomic.Bool
+	switches   atomic.Int64
+}
+
+// NewContentionMonitor returns a monitor of the machine's CPU that allows
+// parallel multiplications until it observes contention.
+//
+// Returns:
+//   - *ContentionMonitor: The monitor; call Run to start sampling.
+func NewContentionMonitor() *ContentionMonitor {
+	return &ContentionMonitor{read: readCPUTimes}
+}
+
+// readCPUTimes reads the cumulative CPU times of the machine and of this
+// process.
+func readCPUTimes() (cpuTimes, error) {
+	all, err := cpu.Times(false)
+	if err != nil {
+		return cpuTimes{}, err
+	}
+	if len(all) == 0 {
+		return cpuTimes{}, errors.New("no CPU times")
+	}
+	p, err := process.NewProcess(int32(os.Getpid()))
+	if err != nil {
+		return cpuTimes{}, err
+	}
+	own, err := p.Times()
+	if err != nil {
+		return cpuTimes{}, err
+	}
+	t := all[0]
+	total := t.Total()
+	return cpuTimes{
+		busy:  total - t.Idle - t.Iowait,
+		total: total,
+		own:   own.User + own.System,
+	}, nil
+}
+
+// externalLoad returns the fraction of the CPU time elapsed between two
+// readings that other processes used, in [0, 1].
+func externalLoad(prev, cur cpuTimes) float64 {
+	elapsed := cur.total - prev.total
+	if elapsed <= 0 {
+		return 0
+	}
+	load := (cur.busy - prev.busy - (cur.own - prev.own)) / elapsed
+	return min(max(load, 0), 1)
+}
+
+// Run samples the CPU every interval until ctx is canceled, switching the
+// monitor between parallel and sequential with the observed load. Samples
+// that cannot be read leave the mode unchanged.
+//
+// Parameters:
+//   - ctx: Stops the sampling when canceled.
+//   - interval: The sampling period; zero means DefaultContentionInterval.
+func (m *ContentionMonitor) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultContentionInterval
+	}
+	prev, err := m.read()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		cur, curErr := m.read()
+		if curErr == nil && err == nil {
+			m.Observe(externalLoad(prev, cur))
+		}
+		prev, err = cur, curErr
+	}
+}
+
+// Observe records an external load sample: at or above ContentionHigh the
+// monitor switches to sequential, below ContentionLow back to parallel.
+//
+// Parameters:
+//   - load: The fraction of the CPU used by other processes, in [0, 1].
+//
+// Returns:
+//   - bool: true if the sample switched the mode.
+func (m *ContentionMonitor) Observe(load float64) bool {
+	switch {
+	case load >= ContentionHigh && !m.sequential.Load():
+		m.sequential.Store(true)
+	case load < ContentionLow && m.sequential.Load():
+		m.sequential.Store(false)
+	default:
+		return false
+	}
+	m.switches.Add(1)
+	return true
+}
+
+// AllowParallel reports whether multiplications may currently run in
+// parallel.
+func (m *ContentionMonitor) AllowParallel() bool {
+	return !m.sequential.Load()
+}
+
+// Switches returns how many times the monitor changed mode.
+func (m *ContentionMonitor) Switches() int64 {
+	return m.switches.Load()
+}
@@ -0,0 +1,91 @@
+package sysmon
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestExternalLoad(t *testing.T) {
+	prev := cpuTimes{busy: 10, total: 40, own: 2}
+	tests := []struct {
+		name string
+		cur  cpuTimes
+		want float64
+	}{
+		{"idle machine", cpuTimes{busy: 10, total: 48, own: 2}, 0},
+		{"only this process", cpuTimes{busy: 16, total: 48, own: 8}, 0},
+		{"other processes", cpuTimes{busy: 18, total: 48, own: 4}, 0.75},
+		{"no time elapsed", prev, 0},
+		{"clamped", cpuTimes{busy: 30, total: 48, own: 2}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := externalLoad(prev, tt.cur); got != tt.want {
+				t.Errorf("externalLoad() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContentionMonitor_Hysteresis(t *testing.T) {
+	m := NewContentionMonitor()
+	steps := []struct {
+		load     float64
+		switched bool
+		parallel bool
+	}{
+		{0.5, false, true},
+		{0.8, true, false},
+		{0.5, false, false},
+		{0.9, false, false},
+		{0.3, true, true},
+		{0.6, false, true},
+	}
+	for i, s := range steps {
+		if got := m.Observe(s.load); got != s.switched {
+			t.Errorf("step %d: Observe(%v) = %v, want %v", i, s.load, got, s.switched)
+		}
+		if got := m.AllowParallel(); got != s.parallel {
+			t.Errorf("step %d: AllowParallel() = %v, want %v", i, got, s.parallel)
+		}
+	}
+	if got := m.Switches(); got != 2 {
+		t.Errorf("Switches() = %d, want 2", got)
+	}
+}
+
+func TestContentionMonitor_Run(t *testing.T) {
+	var calls atomic.Int64
+	m := &ContentionMonitor{read: func() (cpuTimes, error) {
+		// Each reading adds 1s of CPU time, 0.9s of it used by others
+		n := float64(calls.Add(1))
+		return cpuTimes{busy: 0.9 * n, total: n}, nil
+	}}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		m.Run(ctx, time.Millisecond)
+		close(done)
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for m.AllowParallel() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+	if m.AllowParallel() {
+		t.Error("AllowParallel() = true under a 90% external load")
+	}
+}
+
+func TestReadCPUTimes(t *testing.T) {
+	got, err := readCPUTimes()
+	if err != nil {
+		t.Skipf("CPU times unavailable: %v", err)
+	}
+	if got.total <= 0 || got.busy < 0 || got.busy > got.total || got.own < 0 {
+		t.Errorf("readCPUTimes() = %+v", got)
+	}
+}
@@ -0,0 +1,46 @@
+package sysmon
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrUsageUnsupported is returned by ReadUsage on platforms without
+// getrusage.
+var ErrUsageUnsupported = errors.New("sysmon: resource usage is not supported on this platform")
+
+// Usage is the resource usage of the process, as reported by getrusage.
+type Usage struct {
+	// UserCPU and SystemCPU are the CPU time spent in user and kernel mode.
+	UserCPU   time.Duration
+	SystemCPU time.Duration
+	// MaxRSS is the peak resident set size of the process, in bytes.
+	MaxRSS uint64
+	// VoluntaryCtxSwitches counts the times the process gave up the CPU,
+	// mostly to wait; InvoluntaryCtxSwitches the times it was preempted.
+	VoluntaryCtxSwitches   int64
+	InvoluntaryCtxSwitches int64
+}
+
+// CPU returns the total CPU time, user and system.
+func (u Usage) CPU() time.Duration {
+	return u.UserCPU + u.SystemCPU
+}
+
+// Sub returns the usage between the earlier reading prev and u. MaxRSS is
+// a peak over the life of the process, not a counter, so it is kept as is.
+//
+// Parameters:
+//   - prev: A reading taken before u.
+//
+// Returns:
+//   - Usage: The usage accumulated since prev.
+func (u Usage) Sub(prev Usage) Usage {
+	return Usage{
+		UserCPU:                u.UserCPU - prev.UserCPU,
+		SystemCPU:              u.SystemCPU - prev.SystemCPU,
+		MaxRSS:                 u.MaxRSS,
+		VoluntaryCtxSwitches:   u.VoluntaryCtxSwitches - prev.VoluntaryCtxSwitches,
+		InvoluntaryCtxSwitches: u.InvoluntaryCtxSwitches - prev.InvoluntaryCtxSwitches,
+	}
+}
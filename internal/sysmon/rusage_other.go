@@ -0,0 +1,12 @@
+//go:build !unix
+
+package sysmon
+
+// ReadUsage is not available on this platform.
+//
+// Returns:
+//   - Usage: The zero usage.
+//   - error: ErrUsageUnsupported.
+func ReadUsage() (Usage, error) {
+	return Usage{}, ErrUsageUnsupported
+}
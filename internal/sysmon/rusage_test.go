@@ -0,0 +1,54 @@
+package sysmon
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestUsageSub(t *testing.T) {
+	prev := Usage{UserCPU: time.Second, SystemCPU: 100 * time.Millisecond, MaxRSS: 10 << 20, VoluntaryCtxSwitches: 5, InvoluntaryCtxSwitches: 1}
+	cur := Usage{UserCPU: 3 * time.Second, SystemCPU: 300 * time.Millisecond, MaxRSS: 50 << 20, VoluntaryCtxSwitches: 12, InvoluntaryCtxSwitches: 4}
+
+	got := cur.Sub(prev)
+	want := Usage{UserCPU: 2 * time.Second, SystemCPU: 200 * time.Millisecond, MaxRSS: 50 << 20, VoluntaryCtxSwitches: 7, InvoluntaryCtxSwitches: 3}
+	if got != want {
+		t.Errorf("Sub() = %+v, want %+v", got, want)
+	}
+	if got.CPU() != 2200*time.Millisecond {
+		t.Errorf("CPU() = %v, want 2.2s", got.CPU())
+	}
+}
+
+func TestReadUsage(t *testing.T) {
+	before, err := ReadUsage()
+	if runtime.GOOS == "windows" || runtime.GOOS == "js" || runtime.GOOS == "wasip1" {
+		if err == nil {
+			t.Fatal("ReadUsage() succeeded on a platform without getrusage")
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("ReadUsage() error = %v", err)
+	}
+
+	// Burn some CPU so that the reading moves
+	sum := 0
+	for deadline := time.Now().Add(20 * time.Millisecond); time.Now().Before(deadline); {
+		for i := range 1000 {
+			sum += i
+		}
+	}
+	_ = sum
+	after, err := ReadUsage()
+	if err != nil {
+		t.Fatalf("ReadUsage() error = %v", err)
+	}
+	d := after.Sub(before)
+	if d.CPU() <= 0 {
+		t.Errorf("CPU time did not increase: %+v", d)
+	}
+	if after.MaxRSS < 1<<20 {
+		t.Errorf("MaxRSS = %d bytes, want at least 1 MiB", after.MaxRSS)
+	}
+}
@@ -0,0 +1,33 @@
+//go:build unix
+
+package sysmon
+
+import (
+	"runtime"
+	"syscall"
+	"time"
+)
+
+// ReadUsage reads the resource usage of the process so far.
+//
+// Returns:
+//   - Usage: The usage since the process started.
+//   - error: An error if getrusage fails.
+func ReadUsage() (Usage, error) {
+	var ru syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &ru); err != nil {
+		return Usage{}, err
+	}
+	// ru_maxrss is in bytes on Darwin and in kilobytes elsewhere
+	maxRSS := uint64(ru.Maxrss)
+	if runtime.GOOS != "darwin" && runtime.GOOS != "ios" {
+		maxRSS *= 1024
+	}
+	return Usage{
+		UserCPU:                time.Duration(ru.Utime.Nano()),
+		SystemCPU:              time.Duration(ru.Stime.Nano()),
+		MaxRSS:                 maxRSS,
+		VoluntaryCtxSwitches:   int64(ru.Nvcsw),
+		InvoluntaryCtxSwitches: int64(ru.Nivcsw),
+	}, nil
+}
@@ -1,4 +1,5 @@
-// Package sysmon provides system-wide CPU and memory usage sampling.
+// Package sysmon provides system-wide CPU and memory usage sampling, and
+// the resource usage of the process.
 package sysmon
 
 import (
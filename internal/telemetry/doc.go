@@ -0,0 +1,11 @@
+// Package telemetry submits anonymous performance records to a community
+// endpoint, so that default thresholds can be derived from many machines
+// instead of the maintainers' own.
+//
+// Telemetry is strictly opt-in: nothing is sent until the user runs fibcalc
+// with --telemetry, which records the consent and the endpoint in a state
+// file (by default ~/.fibcalc_telemetry.json); `fibcalc telemetry off`
+// withdraws it. A record holds no user data: a hash of the hardware, the
+// platform, n, the algorithm, the duration and the multiplication
+// thresholds.
+package telemetry
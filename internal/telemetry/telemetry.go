@@ -0,0 +1,199 @@
+package telemetry
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/fsguard"
+)
+
+// DefaultFileName is the name of the state file in the home directory.
+const DefaultFileName = ".fibcalc_telemetry.json"
+
+// SchemaVersion is the version of the submitted document.
+const SchemaVersion = 1
+
+// requestTimeout bounds each submission, so that an unreachable endpoint
+// never holds up the exit.
+const requestTimeout = 5 * time.Second
+
+// State is the user's telemetry consent.
+type State struct {
+	// Enabled is true once the user opted in, until they opt out.
+	Enabled bool `json:"enabled"`
+	// Endpoint is the URL the records are POSTed to.
+	Endpoint string `json:"endpoint,omitempty"`
+	// Since is when the user opted in.
+	Since time.Time `json:"since,omitzero"`
+}
+
+// Record is the anonymous summary of one successful algorithm run.
+type Record struct {
+	// Hardware is a hash identifying the machine's class, not the machine:
+	// machines of the same model share it (see Fingerprint).
+	Hardware  string `json:"hardware"`
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+	Cores     int    `json:"cores"`
+	Version   string `json:"fibcalc_version"`
+	N         uint64 `json:"n"`
+	Algorithm string `json:"algorithm"`
+	// DurationNs is the duration of the calculation in nanoseconds.
+	DurationNs int64 `json:"duration_ns"`
+	// The thresholds in effect, in bits; 0 means the built-in default.
+	ParallelThreshold int `json:"parallel_threshold"`
+	FFTThreshold      int `json:"fft_threshold"`
+	StrassenThreshold int `json:"strassen_threshold"`
+}
+
+// report is the submitted document.
+type report struct {
+	Schema  int      `json:"schema"`
+	Records []Record `json:"records"`
+}
+
+// DefaultPath returns the state file in the user's home directory, or in
+// the working directory if the home directory is unknown.
+func DefaultPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return DefaultFileName
+	}
+	return filepath.Join(home, DefaultFileName)
+}
+
+// Load reads the telemetry state.
+//
+// Parameters:
+//   - path: The state file.
+//
+// Returns:
+//   - State: The state; disabled if the file does not exist.
+//   - error: An error if the file cannot be read or parsed.
+func Load(path string) (State, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return State{}, nil
+	}
+	if err != nil {
+		return State{}, fmt.Errorf("failed to read the telemetry state: %w", err)
+	}
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return State{}, fmt.Errorf("failed to parse the telemetry state %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Save writes the telemetry state, creating its directory if needed.
+//
+// Parameters:
+//   - path: The state file.
+//   - s: The state.
+//
+// Returns:
+//   - error: An error if the file cannot be written.
+func Save(path string, s State) error {
+	path = filepath.Clean(path)
+	if dir := filepath.Dir(path); dir != "." {
+		if err := fsguard.MkdirAll(dir, 0o750); err != nil {
+			return fmt.Errorf("failed to create the telemetry state directory: %w", err)
+		}
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode the telemetry state: %w", err)
+	}
+	if err := fsguard.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write the telemetry state: %w", err)
+	}
+	return nil
+}
+
+// Fingerprint returns the hardware hash of the records: the first 16 hex
+// digits of the SHA-256 of the CPU model, the number of logical CPUs and
+// the platform. It tells machines of different kinds apart without
+// identifying one.
+func Fingerprint() string {
+	sum := sha256.Sum256(fmt.Appendf(nil, "%s|%d|%s/%s", cpuModel(), runtime.NumCPU(), runtime.GOOS, runtime.GOARCH))
+	return hex.EncodeToString(sum[:8])
+}
+
+// cpuModel returns the CPU model name from /proc/cpuinfo, or "" where it
+// is not available.
+func cpuModel() string {
+	f, err := os.Open("/proc/cpuinfo")
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		key, value, ok := strings.Cut(sc.Text(), ":")
+		if ok && strings.TrimSpace(key) == "model name" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// NewRecord returns a record of this machine, the fields of the run left
+// to the caller.
+func NewRecord() Record {
+	return Record{
+		Hardware: Fingerprint(),
+		OS:       runtime.GOOS,
+		Arch:     runtime.GOARCH,
+		Cores:    runtime.NumCPU(),
+	}
+}
+
+// Submit POSTs records to the endpoint as one JSON document.
+//
+// Parameters:
+//   - ctx: The context of the request.
+//   - endpoint: The endpoint URL.
+//   - records: The records; none sends nothing.
+//
+// Returns:
+//   - error: An error if the request fails or the endpoint does not answer
+//     with a 2xx status.
+func Submit(ctx context.Context, endpoint string, records []Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(report{Schema: SchemaVersion, Records: records})
+	if err != nil {
+		return fmt.Errorf("failed to encode the telemetry records: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("telemetry submission failed: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("telemetry submission failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("telemetry submission failed: %s answered %s", endpoint, resp.Status)
+	}
+	return nil
+}
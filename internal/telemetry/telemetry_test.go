@@ -0,0 +1,86 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestLoadSave(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "sub", DefaultFileName)
+
+	s, err := Load(path)
+	if err != nil || s.Enabled {
+		t.Fatalf("Load() of a missing file = %+v, %v; want disabled", s, err)
+	}
+
+	want := State{Enabled: true, Endpoint: "https://example.test/v1/records", Since: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}
+	if err := Save(path, want); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	t.Parallel()
+	fp := Fingerprint()
+	if len(fp) != 16 || fp != Fingerprint() {
+		t.Errorf("Fingerprint() = %q, want 16 stable hex digits", fp)
+	}
+	r := NewRecord()
+	if r.Hardware != fp || r.OS != runtime.GOOS || r.Arch != runtime.GOARCH || r.Cores != runtime.NumCPU() {
+		t.Errorf("NewRecord() = %+v", r)
+	}
+}
+
+func TestSubmit(t *testing.T) {
+	t.Parallel()
+	var got report
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("request %s with Content-Type %q", r.Method, r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("invalid body: %v", err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	rec := NewRecord()
+	rec.N, rec.Algorithm, rec.DurationNs, rec.FFTThreshold = 1000000, "fast", 42000000, 500000
+	if err := Submit(context.Background(), srv.URL, []Record{rec}); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if got.Schema != SchemaVersion || len(got.Records) != 1 || got.Records[0] != rec {
+		t.Errorf("submitted %+v, want %+v", got, rec)
+	}
+}
+
+func TestSubmitErrors(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	if err := Submit(context.Background(), srv.URL, []Record{NewRecord()}); err == nil {
+		t.Error("expected an error for a 503 answer")
+	}
+	// Nothing to send: no request, even to an unreachable endpoint
+	if err := Submit(context.Background(), "http://127.0.0.1:1", nil); err != nil {
+		t.Errorf("Submit() without records error = %v", err)
+	}
+}
@@ -0,0 +1,160 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/agbru/fibcalc/internal/capabilities"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+)
+
+// AboutMsg carries the capabilities detected for the about screen and the
+// algorithms it lists.
+type AboutMsg struct {
+	Report     capabilities.Report
+	Algorithms []fibonacci.AlgorithmInfo
+}
+
+// AboutModel is the overlay describing the binary: its version, platform
+// and optional subsystems, as reported by `fibcalc capabilities`, and its
+// algorithms, as listed by `fibcalc algos`.
+type AboutModel struct {
+	version    string
+	keys       []ModeHelp
+	report     *capabilities.Report // nil until detected
+	algorithms []fibonacci.AlgorithmInfo
+	visible    bool
+	width      int
+}
+
+// NewAboutModel creates the about screen of the given version, which also
+// lists the key bindings of each mode.
+func NewAboutModel(version string, keys []ModeHelp) AboutModel {
+	return AboutModel{version: version, keys: keys}
+}
+
+// SetWidth updates the available width.
+func (a *AboutModel) SetWidth(w int) {
+	a.width = w
+}
+
+// Open shows the overlay. The capabilities are detected once, off the UI
+// goroutine, as the probes open counters and map a file.
+//
+// Returns:
+//   - tea.Cmd: The detection, or nil once it has run.
+func (a *AboutModel) Open() tea.Cmd {
+	a.visible = true
+	if a.report != nil {
+		return nil
+	}
+	version := a.version
+	return func() tea.Msg {
+		factory := fibonacci.NewDefaultFactory()
+		return AboutMsg{
+			Report:     capabilities.Detect(version, factory),
+			Algorithms: fibonacci.Algorithms(factory),
+		}
+	}
+}
+
+// HandleReport records the detected capabilities and the algorithms.
+func (a *AboutModel) HandleReport(msg AboutMsg) {
+	a.report = &msg.Report
+	a.algorithms = msg.Algorithms
+}
+
+// Visible reports whether the overlay is shown.
+func (a AboutModel) Visible() bool {
+	return a.visible
+}
+
+// Hide closes the overlay.
+func (a *AboutModel) Hide() {
+	a.visible = false
+}
+
+// View renders the about box.
+func (a AboutModel) View() string {
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("About fibcalc " + a.version))
+	b.WriteString("\n\n")
+	if a.report == nil {
+		b.WriteString(footerDescStyle.Render("Detecting capabilities...") + "\n")
+	} else {
+		r := a.report
+		fmt.Fprintf(&b, "%s %s, %s, %d CPUs\n\n", metricLabelStyle.Render("Platform:"), r.Platform, r.GoVersion, r.NumCPU)
+		for _, c := range r.Capabilities {
+			status := footerDescStyle.Render(padCell(c.Status(), 12))
+			if c.Available {
+				status = logSuccessStyle.Render(padCell(c.Status(), 12))
+			}
+			fmt.Fprintf(&b, "%s %s %s\n", metricLabelStyle.Render(padCell(c.Name, 15)), status, footerDescStyle.Render(c.Detail))
+		}
+		if len(a.algorithms) > 0 {
+			b.WriteString("\n" + metricLabelStyle.Render("Algorithms:") + "\n")
+		}
+		for _, info := range a.algorithms {
+			parallel := ""
+			if info.Parallel {
+				parallel = "parallel"
+			}
+			fmt.Fprintf(&b, "%s %s %s %s\n", metricLabelStyle.Render(padCell(info.Name, 15)),
+				metricValueStyle.Render(padCell(info.RangeString(), 20)),
+				logSuccessStyle.Render(padCell(parallel, 9)), footerDescStyle.Render(info.Complexity))
+		}
+	}
+	if len(a.keys) > 0 {
+		b.WriteString("\n" + metricLabelStyle.Render("Keys:") + "\n")
+		a.writeKeys(&b)
+	}
+	b.WriteString("\n")
+	b.WriteString(fmt.Sprintf("%s: %s", footerKeyStyle.Render("esc"), footerDescStyle.Render("Close")))
+
+	style := panelStyle.Padding(0, 2)
+	if a.width > 0 {
+		style = style.MaxWidth(a.width)
+	}
+	return style.Render(b.String())
+}
+
+// aboutKeyLabelWidth is the width of the mode column of the key help.
+const aboutKeyLabelWidth = 16
+
+// writeKeys writes the key help: one row per mode, its bindings wrapped to
+// the width of the box.
+func (a AboutModel) writeKeys(b *strings.Builder) {
+	width := 0 // unbounded
+	if a.width > 0 {
+		// The box border and padding take 6 cells.
+		width = max(a.width-6-aboutKeyLabelWidth, 20)
+	}
+	for _, mode := range a.keys {
+		b.WriteString(metricLabelStyle.Render(padCell(mode.Mode, aboutKeyLabelWidth)))
+		used := 0
+		for _, binding := range mode.Bindings {
+			help := binding.Help()
+			entry := help.Key + " " + help.Desc
+			if used > 0 {
+				if width > 0 && used+3+cellWidth(entry) > width {
+					b.WriteString("\n" + spaces(aboutKeyLabelWidth))
+					used = 0
+				} else {
+					b.WriteString(footerDescStyle.Render(" · "))
+					used += 3
+				}
+			}
+			b.WriteString(footerKeyStyle.Render(help.Key) + " " + footerDescStyle.Render(help.Desc))
+			used += cellWidth(entry)
+		}
+		b.WriteString("\n")
+	}
+}
+
+// overlay centers the about box over a body of the given size.
+func (a AboutModel) overlay(width, height int) string {
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, a.View())
+}
@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/agbru/fibcalc/internal/testutil"
+)
+
+func TestAboutModel(t *testing.T) {
+	a := NewAboutModel("v1.2.3", DefaultKeyMap().Help())
+	cmd := a.Open()
+	if !a.Visible() || cmd == nil {
+		t.Fatal("Open() should show the overlay and detect the capabilities")
+	}
+	if view := testutil.StripAnsiCodes(a.View()); !strings.Contains(view, "Detecting capabilities") {
+		t.Errorf("view before detection: %q", view)
+	}
+
+	msg, ok := cmd().(AboutMsg)
+	if !ok {
+		t.Fatalf("detection returned %T, want AboutMsg", msg)
+	}
+	a.HandleReport(msg)
+	view := testutil.StripAnsiCodes(a.View())
+	for _, want := range []string{"About fibcalc v1.2.3", "Platform:", "simd", "gmp", "mmap", "Algorithms:", "lowmem", "n ≥ 1,000,000,000",
+		"Keys:", "Dashboard", "d Full decimal result", "Summary", "w Save summary report", "Timeout prompt", "n/esc Keep the timeout"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in the about screen:\n%s", want, view)
+		}
+	}
+
+	a.Hide()
+	if a.Open() != nil {
+		t.Error("capabilities detected twice")
+	}
+}
+
+func TestModel_AboutKey(t *testing.T) {
+	m := newTestModel(t)
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	m = updated.(Model)
+	if !m.about.Visible() || cmd == nil {
+		t.Fatal("a should open the about screen")
+	}
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if updated.(Model).about.Visible() {
+		t.Error("esc should close the about screen")
+	}
+}
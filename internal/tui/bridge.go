@@ -7,6 +7,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/agbru/fibcalc/internal/clock"
 	apperrors "github.com/agbru/fibcalc/internal/errors"
 	"github.com/agbru/fibcalc/internal/format"
 	"github.com/agbru/fibcalc/internal/progress"
@@ -41,7 +42,8 @@ func (r *programRef) Send(msg tea.Msg) {
 // TUIProgressReporter implements orchestration.ProgressReporter.
 // It drains the progress channel and forwards updates as bubbletea messages.
 type TUIProgressReporter struct {
-	ref *programRef
+	ref   *programRef
+	clock clock.Clock // time source for the ETA; nil means the wall clock
 }
 
 // Verify interface compliance.
@@ -51,7 +53,7 @@ var _ orchestration.ProgressReporter = (*TUIProgressReporter)(nil)
 func (t *TUIProgressReporter) DisplayProgress(wg *sync.WaitGroup, progressChan <-chan progress.ProgressUpdate, numCalculators int, _ io.Writer) {
 	defer wg.Done()
 
-	agg := orchestration.NewProgressAggregator(numCalculators)
+	agg := orchestration.NewProgressAggregatorClock(numCalculators, t.clock)
 	if agg == nil {
 		orchestration.DrainChannel(progressChan)
 		return
@@ -64,11 +66,42 @@ func (t *TUIProgressReporter) DisplayProgress(wg *sync.WaitGroup, progressChan <
 			Value:           ap.Value,
 			AverageProgress: ap.AverageProgress,
 			ETA:             ap.ETA,
+			ETALow:          ap.ETALow,
+			ETAHigh:         ap.ETAHigh,
 		})
 	}
 	t.ref.Send(ProgressDoneMsg{})
 }
 
+// teeProgressReporter passes the progress updates to two reporters: the
+// dashboard, and with --log-file the CLI reporter writing to the log.
+type teeProgressReporter struct {
+	first, second orchestration.ProgressReporter
+}
+
+// Verify interface compliance.
+var _ orchestration.ProgressReporter = teeProgressReporter{}
+
+// DisplayProgress forwards every update to both reporters and returns once
+// both are done.
+func (t teeProgressReporter) DisplayProgress(wg *sync.WaitGroup, progressChan <-chan progress.ProgressUpdate, numCalculators int, out io.Writer) {
+	defer wg.Done()
+
+	first := make(chan progress.ProgressUpdate, cap(progressChan))
+	second := make(chan progress.ProgressUpdate, cap(progressChan))
+	var innerWG sync.WaitGroup
+	innerWG.Add(2)
+	go t.first.DisplayProgress(&innerWG, first, numCalculators, out)
+	go t.second.DisplayProgress(&innerWG, second, numCalculators, out)
+	for update := range progressChan {
+		first <- update
+		second <- update
+	}
+	close(first)
+	close(second)
+	innerWG.Wait()
+}
+
 // TUIResultPresenter implements orchestration.ResultPresenter.
 // It sends result messages to the TUI instead of writing to stdout.
 type TUIResultPresenter struct {
@@ -5,8 +5,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/charmbracelet/lipgloss"
-
 	"github.com/agbru/fibcalc/internal/format"
 )
 
@@ -14,6 +12,8 @@ import (
 type ChartModel struct {
 	averageProgress float64
 	eta             time.Duration
+	etaLow          time.Duration
+	etaHigh         time.Duration
 	elapsed         time.Duration
 	done            bool
 	width           int
@@ -49,6 +49,13 @@ func (c *ChartModel) AddDataPoint(progress float64, avg float64, eta time.Durati
 	c.eta = eta
 }
 
+// SetETARange records the ETA confidence range shown in the chart header.
+// Zero bounds mean no range is available yet.
+func (c *ChartModel) SetETARange(low, high time.Duration) {
+	c.etaLow = low
+	c.etaHigh = high
+}
+
 // UpdateSysStats records a system metrics sample.
 func (c *ChartModel) UpdateSysStats(cpuPct, memPct float64) {
 	c.cpuHistory.Push(cpuPct)
@@ -66,6 +73,8 @@ func (c *ChartModel) SetDone(elapsed time.Duration) {
 func (c *ChartModel) Reset() {
 	c.averageProgress = 0
 	c.eta = 0
+	c.etaLow = 0
+	c.etaHigh = 0
 	c.elapsed = 0
 	c.done = false
 	c.cpuHistory.Reset()
@@ -81,11 +90,11 @@ func (c ChartModel) View() string {
 	if c.done {
 		statusStr = fmt.Sprintf("Completed in %s", format.FormatExecutionDuration(c.elapsed))
 	} else {
-		statusStr = fmt.Sprintf("ETA: %s", format.FormatETA(c.eta))
+		statusStr = fmt.Sprintf("ETA: %s", format.FormatETAWithRange(c.eta, c.etaLow, c.etaHigh))
 	}
 	titleLeft := metricLabelStyle.Render("  Progress Chart")
 	titleRight := elapsedStyle.Render(statusStr + "  ")
-	gap := c.width - 4 - lipgloss.Width(titleLeft) - lipgloss.Width(titleRight)
+	gap := c.width - 4 - cellWidth(titleLeft) - cellWidth(titleRight)
 	if gap < 1 {
 		gap = 1
 	}
@@ -110,7 +119,7 @@ func (c ChartModel) View() string {
 	return panelStyle.
 		Width(c.width - 2).
 		Height(c.height - 2).
-		Render(b.String())
+		Render(fitLines(b.String(), c.width-2))
 }
 
 func (c ChartModel) renderProgressBar() string {
@@ -1,9 +1,15 @@
 package tui
 
 import (
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/agbru/fibcalc/internal/clock"
+	"github.com/agbru/fibcalc/internal/fibtest"
+	"github.com/agbru/fibcalc/internal/orchestration"
+	"github.com/agbru/fibcalc/internal/progress"
 )
 
 func TestChartModel_AddDataPoint(t *testing.T) {
@@ -54,6 +60,23 @@ func TestChartModel_View(t *testing.T) {
 	}
 }
 
+func TestChartModel_ViewETARange(t *testing.T) {
+	chart := NewChartModel()
+	chart.SetSize(60, 10)
+	chart.AddDataPoint(0.5, 0.5, 4*time.Minute)
+	chart.SetETARange(3*time.Minute, 5*time.Minute)
+
+	view := chart.View()
+	if !strings.Contains(view, "3m\u20135m") {
+		t.Errorf("expected ETA range in header, got:\n%s", view)
+	}
+
+	chart.Reset()
+	if chart.etaLow != 0 || chart.etaHigh != 0 {
+		t.Error("expected ETA range cleared after reset")
+	}
+}
+
 func TestChartModel_RenderProgressBar(t *testing.T) {
 	chart := NewChartModel()
 	chart.SetSize(50, 10)
@@ -184,3 +207,30 @@ func TestChartModel_SetSize_ResizesBuffers(t *testing.T) {
 		t.Errorf("expected mem buffer cap %d, got %d", expectedWidth, chart.memHistory.Cap())
 	}
 }
+
+// TestChartModel_ReplayFixture replays a captured fast-doubling run through
+// the chart on a fake clock and compares the rendering at mid-run and at
+// the end with testdata/chart_replay.golden.
+func TestChartModel_ReplayFixture(t *testing.T) {
+	fixture, err := fibtest.EmbeddedFixture("fast-doubling-100m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	clk := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	agg := orchestration.NewProgressAggregatorClock(len(fixture.Calculators), clk)
+	chart := NewChartModel()
+	chart.SetSize(60, 6)
+
+	var views []string
+	fixture.Advance(clk, 5*time.Second, func(u progress.ProgressUpdate) {
+		ap := agg.Update(u)
+		chart.AddDataPoint(ap.Value, ap.AverageProgress, ap.ETA)
+		chart.SetETARange(ap.ETALow, ap.ETAHigh)
+		if len(views) == 0 && ap.AverageProgress >= 0.5 {
+			views = append(views, chart.View())
+		}
+	})
+	views = append(views, chart.View())
+
+	fibtest.AssertGolden(t, filepath.Join("testdata", "chart_replay.golden"), []byte(strings.Join(views, "\n")+"\n"))
+}
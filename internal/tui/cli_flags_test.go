@@ -12,6 +12,7 @@ import (
 	"github.com/agbru/fibcalc/internal/config"
 	apperrors "github.com/agbru/fibcalc/internal/errors"
 	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/fibtest"
 	"github.com/agbru/fibcalc/internal/orchestration"
 )
 
@@ -43,19 +44,6 @@ func (c *capturingCalculator) Name() string {
 // Verify interface compliance.
 var _ fibonacci.Calculator = (*capturingCalculator)(nil)
 
-// blockingCalculator blocks until context cancellation.
-type blockingCalculator struct{}
-
-func (b blockingCalculator) Calculate(ctx context.Context, _ chan<- fibonacci.ProgressUpdate, _ int, _ uint64, _ fibonacci.Options) (*big.Int, error) {
-	<-ctx.Done()
-	return nil, ctx.Err()
-}
-
-func (b blockingCalculator) Name() string { return "blocking" }
-
-// Verify interface compliance.
-var _ fibonacci.Calculator = blockingCalculator{}
-
 // ---------------------------------------------------------------------------
 // Group 1: Config propagation to model
 // ---------------------------------------------------------------------------
@@ -227,7 +215,7 @@ func TestNewModel_ConfigPreservedAfterRestart(t *testing.T) {
 		ShowValue:         true,
 		TUI:               true,
 	}
-	calcs := []fibonacci.Calculator{mockCalculator{name: "Fast"}}
+	calcs := []fibonacci.Calculator{fibtest.Fixed("Fast", nil)}
 	m := NewModel(context.Background(), calcs, cfg, "v1.0.0")
 	t.Cleanup(m.cancel)
 
@@ -278,15 +266,15 @@ func TestModel_CalculatorSelection(t *testing.T) {
 	}{
 		{
 			name:      "Single calculator",
-			calcs:     []fibonacci.Calculator{mockCalculator{name: "Fast Doubling"}},
+			calcs:     []fibonacci.Calculator{fibtest.Fixed("Fast Doubling", nil)},
 			wantCount: 1,
 			wantNames: []string{"Fast Doubling"},
 		},
 		{
 			name: "Two calculators",
 			calcs: []fibonacci.Calculator{
-				mockCalculator{name: "Fast Doubling"},
-				mockCalculator{name: "Matrix"},
+				fibtest.Fixed("Fast Doubling", nil),
+				fibtest.Fixed("Matrix", nil),
 			},
 			wantCount: 2,
 			wantNames: []string{"Fast Doubling", "Matrix"},
@@ -294,9 +282,9 @@ func TestModel_CalculatorSelection(t *testing.T) {
 		{
 			name: "Three calculators",
 			calcs: []fibonacci.Calculator{
-				mockCalculator{name: "Fast Doubling"},
-				mockCalculator{name: "Matrix"},
-				mockCalculator{name: "FFT"},
+				fibtest.Fixed("Fast Doubling", nil),
+				fibtest.Fixed("Matrix", nil),
+				fibtest.Fixed("FFT", nil),
 			},
 			wantCount: 3,
 			wantNames: []string{"Fast Doubling", "Matrix", "FFT"},
@@ -330,8 +318,8 @@ func TestModel_CalculatorSelection(t *testing.T) {
 func TestModel_CalculatorsPreservedAfterRestart(t *testing.T) {
 	t.Parallel()
 	calcs := []fibonacci.Calculator{
-		mockCalculator{name: "Fast Doubling"},
-		mockCalculator{name: "Matrix"},
+		fibtest.Fixed("Fast Doubling", nil),
+		fibtest.Fixed("Matrix", nil),
 	}
 	cfg := config.AppConfig{N: 1000, Timeout: time.Minute}
 	m := NewModel(context.Background(), calcs, cfg, "v1.0.0")
@@ -408,7 +396,7 @@ func TestStartCalculationCmd_ConfigPassthrough(t *testing.T) {
 				StrassenThreshold: tt.strassenThreshold,
 			}
 
-			cmd := startCalculationCmd(ref, ctx, []fibonacci.Calculator{capture}, cfg, 0)
+			cmd := startCalculationCmd(ref, ctx, []fibonacci.Calculator{capture}, cfg, 0, nil, session{})
 			msg := cmd()
 
 			complete, ok := msg.(CalculationCompleteMsg)
@@ -440,10 +428,10 @@ func TestStartCalculationCmd_ExitCodes(t *testing.T) {
 	t.Run("Successful calculation", func(t *testing.T) {
 		t.Parallel()
 		ref := &programRef{}
-		calc := mockCalculator{name: "Fast"}
+		calc := fibtest.Fixed("Fast", nil)
 		cfg := config.AppConfig{N: 10, Timeout: time.Minute}
 
-		cmd := startCalculationCmd(ref, context.Background(), []fibonacci.Calculator{calc}, cfg, 0)
+		cmd := startCalculationCmd(ref, context.Background(), []fibonacci.Calculator{calc}, cfg, 0, nil, session{})
 		msg := cmd()
 
 		complete, ok := msg.(CalculationCompleteMsg)
@@ -457,13 +445,13 @@ func TestStartCalculationCmd_ExitCodes(t *testing.T) {
 
 	t.Run("Timeout", func(t *testing.T) {
 		ref := &programRef{}
-		calc := blockingCalculator{}
+		calc := fibtest.Failing("blocking", nil, time.Hour)
 		cfg := config.AppConfig{N: 100_000_000, Timeout: time.Minute}
 
 		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Millisecond)
 		defer cancel()
 
-		cmd := startCalculationCmd(ref, ctx, []fibonacci.Calculator{calc}, cfg, 0)
+		cmd := startCalculationCmd(ref, ctx, []fibonacci.Calculator{calc}, cfg, 0, nil, session{})
 		msg := cmd()
 
 		complete, ok := msg.(CalculationCompleteMsg)
@@ -492,10 +480,10 @@ func TestStartCalculationCmd_Generation(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 			ref := &programRef{}
-			calc := mockCalculator{name: "Fast"}
+			calc := fibtest.Fixed("Fast", nil)
 			cfg := config.AppConfig{N: 10, Timeout: time.Minute}
 
-			cmd := startCalculationCmd(ref, context.Background(), []fibonacci.Calculator{calc}, cfg, tt.generation)
+			cmd := startCalculationCmd(ref, context.Background(), []fibonacci.Calculator{calc}, cfg, tt.generation, nil, session{})
 			msg := cmd()
 
 			complete, ok := msg.(CalculationCompleteMsg)
@@ -514,7 +502,7 @@ func TestStartCalculationCmd_NoCalculators(t *testing.T) {
 	ref := &programRef{}
 	cfg := config.AppConfig{N: 10, Timeout: time.Minute}
 
-	cmd := startCalculationCmd(ref, context.Background(), []fibonacci.Calculator{}, cfg, 0)
+	cmd := startCalculationCmd(ref, context.Background(), []fibonacci.Calculator{}, cfg, 0, nil, session{})
 	msg := cmd()
 
 	_, ok := msg.(CalculationCompleteMsg)
@@ -547,7 +535,7 @@ func TestStartCalculationCmd_DisplayFlagsInConfig(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 			ref := &programRef{}
-			calc := mockCalculator{name: "Fast"}
+			calc := fibtest.Fixed("Fast", nil)
 			cfg := config.AppConfig{
 				N:         10,
 				Timeout:   time.Minute,
@@ -556,7 +544,7 @@ func TestStartCalculationCmd_DisplayFlagsInConfig(t *testing.T) {
 				ShowValue: tt.showValue,
 			}
 
-			cmd := startCalculationCmd(ref, context.Background(), []fibonacci.Calculator{calc}, cfg, 0)
+			cmd := startCalculationCmd(ref, context.Background(), []fibonacci.Calculator{calc}, cfg, 0, nil, session{})
 			msg := cmd()
 
 			complete, ok := msg.(CalculationCompleteMsg)
@@ -635,13 +623,13 @@ func TestFinalResultMsg_DisplayFlags(t *testing.T) {
 
 func TestStartCalculationCmd_Timeout(t *testing.T) {
 	ref := &programRef{}
-	calc := blockingCalculator{}
+	calc := fibtest.Failing("blocking", nil, time.Hour)
 	cfg := config.AppConfig{N: 100_000_000, Timeout: time.Minute}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Millisecond)
 	defer cancel()
 
-	cmd := startCalculationCmd(ref, ctx, []fibonacci.Calculator{calc}, cfg, 0)
+	cmd := startCalculationCmd(ref, ctx, []fibonacci.Calculator{calc}, cfg, 0, nil, session{})
 	msg := cmd()
 
 	complete, ok := msg.(CalculationCompleteMsg)
@@ -705,7 +693,7 @@ func TestStartCalculationCmd_SmallN(t *testing.T) {
 			ref := &programRef{}
 			cfg := config.AppConfig{N: tt.n, Timeout: time.Minute}
 
-			cmd := startCalculationCmd(ref, context.Background(), []fibonacci.Calculator{capture}, cfg, 0)
+			cmd := startCalculationCmd(ref, context.Background(), []fibonacci.Calculator{capture}, cfg, 0, nil, session{})
 			msg := cmd()
 
 			complete, ok := msg.(CalculationCompleteMsg)
@@ -734,7 +722,7 @@ func TestStartCalculationCmd_ZeroThresholds(t *testing.T) {
 		StrassenThreshold: 0,
 	}
 
-	cmd := startCalculationCmd(ref, context.Background(), []fibonacci.Calculator{capture}, cfg, 0)
+	cmd := startCalculationCmd(ref, context.Background(), []fibonacci.Calculator{capture}, cfg, 0, nil, session{})
 	msg := cmd()
 
 	complete, ok := msg.(CalculationCompleteMsg)
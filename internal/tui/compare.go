@@ -0,0 +1,128 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/agbru/fibcalc/internal/format"
+	"github.com/agbru/fibcalc/internal/metrics"
+)
+
+// deltaNoiseFraction is the relative change below which a delta is shown
+// as neutral rather than as an improvement or a regression.
+const deltaNoiseFraction = 0.01
+
+// comparisonRow is one line of the baseline comparison.
+type comparisonRow struct {
+	label    string
+	baseline string
+	current  string
+	delta    string // rendered delta, empty when not applicable
+}
+
+// compareReports lists the metrics of a baseline and a current run side by
+// side, with deltas for duration, throughput and peak heap.
+func compareReports(base, cur RunReport) []comparisonRow {
+	bytes := func(b uint64) string { return format.FormatBytes(b) }
+	throughput := func(bps float64) string {
+		if bps <= 0 {
+			return "n/a"
+		}
+		return metrics.FormatBitsPerSecond(bps)
+	}
+	cacheHitRate := func(rate *float64) string {
+		if rate == nil {
+			return "n/a"
+		}
+		return fmt.Sprintf("%.1f%%", *rate*100)
+	}
+	threshold := func(bits int) string {
+		if bits == 0 {
+			return "auto"
+		}
+		return format.FormatNumberString(fmt.Sprintf("%d", bits))
+	}
+
+	rows := []comparisonRow{
+		{label: "N", baseline: fmt.Sprintf("%d", base.N), current: fmt.Sprintf("%d", cur.N)},
+		{label: "Algorithm", baseline: base.Algorithm, current: cur.Algorithm},
+		{
+			label:    "Duration",
+			baseline: format.FormatExecutionDuration(base.Duration),
+			current:  format.FormatExecutionDuration(cur.Duration),
+			delta:    formatDelta(float64(base.Duration), float64(cur.Duration), true),
+		},
+		{
+			label:    "Throughput",
+			baseline: throughput(base.BitsPerSecond),
+			current:  throughput(cur.BitsPerSecond),
+			delta:    formatDelta(base.BitsPerSecond, cur.BitsPerSecond, false),
+		},
+		{
+			label:    "Peak heap",
+			baseline: bytes(base.PeakHeapBytes),
+			current:  bytes(cur.PeakHeapBytes),
+			delta:    formatDelta(float64(base.PeakHeapBytes), float64(cur.PeakHeapBytes), true),
+		},
+		{label: "FFT cache hit rate", baseline: cacheHitRate(base.CacheHitRate), current: cacheHitRate(cur.CacheHitRate)},
+		{label: "Parallel threshold", baseline: threshold(base.ParallelThreshold), current: threshold(cur.ParallelThreshold)},
+		{label: "FFT threshold", baseline: threshold(base.FFTThreshold), current: threshold(cur.FFTThreshold)},
+		{label: "Strassen threshold", baseline: threshold(base.StrassenThreshold), current: threshold(cur.StrassenThreshold)},
+	}
+	for i := range rows {
+		if rows[i].delta == "" && rows[i].baseline != rows[i].current {
+			rows[i].delta = logProgressStyle.Render("changed")
+		}
+	}
+	return rows
+}
+
+// formatDelta renders the relative change from base to cur, colored as an
+// improvement or a regression. It returns "" when base is unknown.
+func formatDelta(base, cur float64, lowerIsBetter bool) string {
+	if base <= 0 || cur <= 0 {
+		return ""
+	}
+	change := (cur - base) / base
+	text := fmt.Sprintf("%+.1f%%", change*100)
+	switch {
+	case change > -deltaNoiseFraction && change < deltaNoiseFraction:
+		return metricLabelStyle.Render(text)
+	case (change < 0) == lowerIsBetter:
+		return logSuccessStyle.Render(text)
+	default:
+		return logErrorStyle.Render(text)
+	}
+}
+
+// comparisonView renders the baseline and the current run as two panels
+// side by side, the current one annotated with deltas.
+func comparisonView(base, cur RunReport, baselinePath string) string {
+	rows := compareReports(base, cur)
+
+	labelWidth, valueWidth := 0, 0
+	for _, row := range rows {
+		labelWidth = max(labelWidth, cellWidth(row.label))
+		valueWidth = max(valueWidth, cellWidth(row.baseline), cellWidth(row.current))
+	}
+
+	var left, right strings.Builder
+	left.WriteString(titleStyle.Render("Baseline: " + filepath.Base(baselinePath)))
+	right.WriteString(titleStyle.Render("Current run"))
+	for _, row := range rows {
+		label := metricLabelStyle.Render(padCell(row.label, labelWidth) + "  ")
+		left.WriteString("\n" + label + metricValueStyle.Render(row.baseline))
+		right.WriteString("\n" + label + metricValueStyle.Render(padCell(row.current, valueWidth)))
+		if row.delta != "" {
+			right.WriteString("  " + row.delta)
+		}
+	}
+	if base.N != cur.N {
+		right.WriteString("\n\n" + logProgressStyle.Render("Different n: only throughput is directly comparable."))
+	}
+
+	return lipgloss.JoinHorizontal(lipgloss.Top, left.String(), "    ", right.String())
+}
@@ -0,0 +1,157 @@
+package tui
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/agbru/fibcalc/internal/config"
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/testutil"
+)
+
+func TestFormatDelta(t *testing.T) {
+	tests := []struct {
+		name          string
+		base, cur     float64
+		lowerIsBetter bool
+		want          string
+	}{
+		{"faster", 100, 80, true, "-20.0%"},
+		{"slower", 100, 125, true, "+25.0%"},
+		{"more throughput", 100, 150, false, "+50.0%"},
+		{"noise", 100, 100.5, true, "+0.5%"},
+		{"unknown baseline", 0, 100, true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := testutil.StripAnsiCodes(formatDelta(tt.base, tt.cur, tt.lowerIsBetter))
+			if got != tt.want {
+				t.Errorf("formatDelta(%v, %v) = %q, want %q", tt.base, tt.cur, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareReports(t *testing.T) {
+	base := RunReport{N: 1000, Algorithm: "Fast", Duration: 2 * time.Second, BitsPerSecond: 1000, PeakHeapBytes: 1 << 20, FFTThreshold: 500000}
+	cur := RunReport{N: 1000, Algorithm: "Fast", Duration: time.Second, BitsPerSecond: 2000, PeakHeapBytes: 1 << 20, FFTThreshold: 250000}
+
+	rows := make(map[string]comparisonRow)
+	for _, row := range compareReports(base, cur) {
+		rows[row.label] = row
+	}
+
+	deltas := map[string]string{
+		"Duration":      "-50.0%",
+		"Throughput":    "+100.0%",
+		"Peak heap":     "+0.0%",
+		"FFT threshold": "changed",
+		"Algorithm":     "",
+	}
+	for label, want := range deltas {
+		row, ok := rows[label]
+		if !ok {
+			t.Errorf("missing row %q", label)
+			continue
+		}
+		if got := testutil.StripAnsiCodes(row.delta); got != want {
+			t.Errorf("%s delta = %q, want %q", label, got, want)
+		}
+	}
+	if rows["Parallel threshold"].current != "auto" {
+		t.Errorf("expected a zero threshold to show as auto, got %q", rows["Parallel threshold"].current)
+	}
+}
+
+func TestComparisonView(t *testing.T) {
+	base := RunReport{N: 1000, Algorithm: "Matrix", Duration: time.Second}
+	cur := RunReport{N: 2000, Algorithm: "Fast", Duration: time.Second}
+
+	view := testutil.StripAnsiCodes(comparisonView(base, cur, "/tmp/runs/base.json"))
+	for _, want := range []string{"Baseline: base.json", "Current run", "Matrix", "Fast", "Different n"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in comparison view:\n%s", want, view)
+		}
+	}
+}
+
+func TestSummaryModel_ToggleComparison(t *testing.T) {
+	s := NewSummaryModel()
+	s.ToggleComparison()
+	if s.comparing {
+		t.Error("comparison should stay off without a baseline")
+	}
+
+	s.SetBaseline("base.json", RunReport{N: 10, Algorithm: "Fast"})
+	s.SetResult(testFinalResultMsg(), config.AppConfig{})
+	s.Show(0)
+	if view := testutil.StripAnsiCodes(s.View()); !strings.Contains(view, "Baseline: base.json") {
+		t.Errorf("expected the comparison by default:\n%s", view)
+	}
+
+	s.ToggleComparison()
+	if view := testutil.StripAnsiCodes(s.View()); !strings.Contains(view, "Run summary") || !strings.Contains(view, "c: Compare") {
+		t.Errorf("expected the summary after toggling:\n%s", view)
+	}
+
+	s.Reset()
+	if s.baseline == nil || !s.comparing || s.Available() {
+		t.Error("expected Reset to keep the baseline and clear the result")
+	}
+}
+
+func TestModel_Baseline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "base.json")
+	if err := SaveReport(path, RunReport{N: 10, Algorithm: "Fast", Duration: 100 * time.Millisecond}); err != nil {
+		t.Fatalf("SaveReport: %v", err)
+	}
+
+	cfg := config.AppConfig{N: 10, Timeout: time.Minute, Baseline: path}
+	m := NewModel(context.Background(), nil, cfg, "v0.1.0")
+	t.Cleanup(m.cancel)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	m = updated.(Model)
+
+	if !strings.Contains(testutil.StripAnsiCodes(strings.Join(m.logs.entries, "\n")), "Baseline: F(10) with Fast") {
+		t.Error("expected the baseline in the logs")
+	}
+
+	updated, _ = m.Update(testFinalResultMsg())
+	m = updated.(Model)
+	updated, _ = m.Update(CalculationCompleteMsg{ExitCode: apperrors.ExitSuccess})
+	m = updated.(Model)
+	view := testutil.StripAnsiCodes(m.View())
+	if !strings.Contains(view, "Baseline: base.json") || !strings.Contains(view, "-50.0%") {
+		t.Errorf("expected the comparison with a duration delta:\n%s", view)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	m = updated.(Model)
+	if !strings.Contains(testutil.StripAnsiCodes(m.View()), "Run summary") {
+		t.Error("expected 'c' to switch to the summary")
+	}
+}
+
+func TestModel_BaselineLoadError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.json")
+	if err := os.WriteFile(path, []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.AppConfig{N: 10, Timeout: time.Minute, Baseline: path}
+	m := NewModel(context.Background(), nil, cfg, "v0.1.0")
+	t.Cleanup(m.cancel)
+
+	if m.summary.baseline != nil {
+		t.Error("expected no baseline from an invalid report")
+	}
+	if !strings.Contains(testutil.StripAnsiCodes(strings.Join(m.logs.entries, "\n")), "Baseline not loaded") {
+		t.Error("expected the load error in the logs")
+	}
+}
@@ -0,0 +1,121 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/agbru/fibcalc/internal/format"
+)
+
+// Deadline prompt tuning.
+const (
+	// deadlinePromptLead is how close to the deadline the prompt may
+	// appear: earlier, the ETA is not reliable enough to act on.
+	deadlinePromptLead = time.Minute
+	// deadlineMinExtension is the smallest extension offered.
+	deadlineMinExtension = 30 * time.Second
+)
+
+// DeadlinePromptModel is the overlay asking whether to extend the timeout
+// when the ETA shows the run will not finish before it. It is asked at
+// most once per run; declining lets the timeout fire.
+type DeadlinePromptModel struct {
+	visible   bool
+	asked     bool
+	overshoot time.Duration // by how much the ETA exceeds the deadline
+	extension time.Duration // extension offered
+	width     int
+}
+
+// NewDeadlinePromptModel creates a prompt that has not been asked.
+func NewDeadlinePromptModel() DeadlinePromptModel {
+	return DeadlinePromptModel{}
+}
+
+// Reset allows the prompt to be asked again, for a restart.
+func (d *DeadlinePromptModel) Reset() {
+	*d = DeadlinePromptModel{width: d.width}
+}
+
+// SetWidth updates the available width.
+func (d *DeadlinePromptModel) SetWidth(w int) {
+	d.width = w
+}
+
+// Check opens the prompt if the run will likely time out: the deadline is
+// close and the ETA lies beyond it.
+//
+// Parameters:
+//   - eta: The estimated time to completion, zero if unknown.
+//   - remaining: The time left before the deadline.
+//
+// Returns:
+//   - bool: true if the prompt was opened.
+func (d *DeadlinePromptModel) Check(eta, remaining time.Duration) bool {
+	if d.asked || eta <= 0 || remaining <= 0 || remaining > deadlinePromptLead || eta <= remaining {
+		return false
+	}
+	d.asked, d.visible = true, true
+	d.overshoot = eta - remaining
+	d.extension = extensionFor(d.overshoot)
+	return true
+}
+
+// extensionFor returns the extension offered for an ETA overshoot: the
+// overshoot plus a quarter for the uncertainty of the estimate, rounded up
+// to the second.
+func extensionFor(overshoot time.Duration) time.Duration {
+	ext := overshoot + overshoot/4
+	if rem := ext % time.Second; rem != 0 {
+		ext += time.Second - rem
+	}
+	return max(ext, deadlineMinExtension)
+}
+
+// Extension returns the extension offered.
+func (d DeadlinePromptModel) Extension() time.Duration {
+	return d.extension
+}
+
+// Visible reports whether the prompt is shown.
+func (d DeadlinePromptModel) Visible() bool {
+	return d.visible
+}
+
+// Hide closes the prompt.
+func (d *DeadlinePromptModel) Hide() {
+	d.visible = false
+}
+
+// approxDuration formats d for the prompt, to the minute above a minute.
+func approxDuration(d time.Duration) string {
+	if d >= time.Minute {
+		d = d.Round(time.Minute)
+	}
+	return format.FormatETA(d)
+}
+
+// View renders the prompt box.
+func (d DeadlinePromptModel) View() string {
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(fmt.Sprintf("ETA exceeds timeout by ~%s — extend?", approxDuration(d.overshoot))))
+	b.WriteString("\n\n")
+	b.WriteString(fmt.Sprintf("%s: %s   %s: %s",
+		footerKeyStyle.Render("y"), footerDescStyle.Render("Extend by "+format.FormatETA(d.extension)),
+		footerKeyStyle.Render("n/esc"), footerDescStyle.Render("Let it time out"),
+	))
+
+	style := panelStyle.Padding(0, 2)
+	if d.width > 0 {
+		style = style.MaxWidth(d.width)
+	}
+	return style.Render(b.String())
+}
+
+// overlay centers the prompt over a body of the given size.
+func (d DeadlinePromptModel) overlay(width, height int) string {
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, d.View())
+}
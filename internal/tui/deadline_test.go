@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/agbru/fibcalc/internal/config"
+	"github.com/agbru/fibcalc/internal/orchestration"
+	"github.com/agbru/fibcalc/internal/testutil"
+)
+
+func TestDeadlinePromptModel_Check(t *testing.T) {
+	tests := []struct {
+		name      string
+		eta       time.Duration
+		remaining time.Duration
+		want      bool
+	}{
+		{"unknown ETA", 0, 30 * time.Second, false},
+		{"finishes in time", 20 * time.Second, 30 * time.Second, false},
+		{"deadline far away", time.Hour, 10 * time.Minute, false},
+		{"will time out", 2*time.Minute + 30*time.Second, 30 * time.Second, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDeadlinePromptModel()
+			if got := d.Check(tt.eta, tt.remaining); got != tt.want || d.Visible() != tt.want {
+				t.Errorf("Check() = %v, Visible() = %v, want %v", got, d.Visible(), tt.want)
+			}
+		})
+	}
+
+	d := NewDeadlinePromptModel()
+	d.Check(2*time.Minute+30*time.Second, 30*time.Second)
+	view := testutil.StripAnsiCodes(d.View())
+	if !strings.Contains(view, "ETA exceeds timeout by ~2m — extend?") || !strings.Contains(view, "Extend by 2m30s") {
+		t.Errorf("unexpected prompt: %q", view)
+	}
+
+	// Asked once per run, again after a restart
+	d.Hide()
+	if d.Check(time.Hour, 30*time.Second) {
+		t.Error("prompt asked twice")
+	}
+	d.Reset()
+	if !d.Check(time.Hour, 30*time.Second) {
+		t.Error("prompt not asked after Reset")
+	}
+}
+
+func TestExtensionFor(t *testing.T) {
+	if got := extensionFor(time.Second); got != deadlineMinExtension {
+		t.Errorf("extensionFor(1s) = %v, want %v", got, deadlineMinExtension)
+	}
+	if got := extensionFor(100*time.Second + time.Millisecond); got != 126*time.Second {
+		t.Errorf("extensionFor(100.001s) = %v, want 2m6s", got)
+	}
+}
+
+func TestModel_DeadlinePrompt(t *testing.T) {
+	ctx, cancel := orchestration.WithExtendableTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	m := NewModel(ctx, nil, config.AppConfig{N: 1000, Timeout: 30 * time.Second}, "v0.1.0")
+	t.Cleanup(m.cancel)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = updated.(Model)
+
+	updated, _ = m.Update(ProgressMsg{Value: 0.1, AverageProgress: 0.1, ETA: 5 * time.Minute})
+	m = updated.(Model)
+	if !m.extend.Visible() {
+		t.Fatal("expected the timeout prompt")
+	}
+	if view := testutil.StripAnsiCodes(m.View()); !strings.Contains(view, "extend?") {
+		t.Error("prompt not rendered")
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	m = updated.(Model)
+	if m.extend.Visible() {
+		t.Error("prompt still shown after y")
+	}
+	if remaining := ctx.Remaining(); remaining < 5*time.Minute {
+		t.Errorf("Remaining() = %v after extending, want more than 5m", remaining)
+	}
+
+	// Without an extendable deadline there is no prompt
+	plain := newTestModel(t)
+	updated, _ = plain.Update(ProgressMsg{Value: 0.1, AverageProgress: 0.1, ETA: 5 * time.Minute})
+	if updated.(Model).extend.Visible() {
+		t.Error("prompt shown without an extendable deadline")
+	}
+}
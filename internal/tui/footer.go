@@ -1,22 +1,20 @@
 package tui
 
-import (
-	"fmt"
-
-	"github.com/charmbracelet/lipgloss"
-)
+import "fmt"
 
 // FooterModel renders the bottom status bar.
 type FooterModel struct {
-	paused bool
-	done   bool
-	hasErr bool
-	width  int
+	paused   bool
+	done     bool
+	hasErr   bool
+	fullLogs bool
+	follow   bool
+	width    int
 }
 
 // NewFooterModel creates a new footer.
 func NewFooterModel() FooterModel {
-	return FooterModel{}
+	return FooterModel{follow: true}
 }
 
 // SetWidth updates the available width.
@@ -34,6 +32,16 @@ func (f *FooterModel) SetDone(d bool) {
 	f.done = d
 }
 
+// SetFullLogs sets whether the logs panel records every progress update.
+func (f *FooterModel) SetFullLogs(full bool) {
+	f.fullLogs = full
+}
+
+// SetFollow sets whether the logs panel follows new entries.
+func (f *FooterModel) SetFollow(follow bool) {
+	f.follow = follow
+}
+
 // SetError sets the error state.
 func (f *FooterModel) SetError(e bool) {
 	f.hasErr = e
@@ -41,11 +49,23 @@ func (f *FooterModel) SetError(e bool) {
 
 // View renders the footer.
 func (f FooterModel) View() string {
+	logsMode := "Logs: sampled"
+	if f.fullLogs {
+		logsMode = "Logs: full"
+	}
+	followMode := "Follow: off"
+	if f.follow {
+		followMode = "Follow: on"
+	}
 	shortcuts := fmt.Sprintf(
-		"%s: %s   %s: %s   %s: %s",
+		"%s: %s   %s: %s   %s: %s   %s: %s   %s: %s   %s: %s   %s: %s",
 		footerKeyStyle.Render("q"), footerDescStyle.Render("Quit"),
 		footerKeyStyle.Render("r"), footerDescStyle.Render("Restart"),
 		footerKeyStyle.Render("space"), footerDescStyle.Render("Pause/Resume"),
+		footerKeyStyle.Render("v"), footerDescStyle.Render(logsMode),
+		footerKeyStyle.Render("f"), footerDescStyle.Render(followMode),
+		footerKeyStyle.Render("/"), footerDescStyle.Render("Search"),
+		footerKeyStyle.Render("a"), footerDescStyle.Render("About & keys"),
 	)
 
 	var status string
@@ -65,8 +85,8 @@ func (f FooterModel) View() string {
 		innerWidth = 0
 	}
 
-	shortcutsWidth := lipgloss.Width(shortcuts)
-	statusWidth := lipgloss.Width(status)
+	shortcutsWidth := cellWidth(shortcuts)
+	statusWidth := cellWidth(status)
 	gap := innerWidth - shortcutsWidth - statusWidth
 	if gap < 2 {
 		gap = 2
@@ -4,13 +4,13 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/charmbracelet/lipgloss"
-
+	"github.com/agbru/fibcalc/internal/clock"
 	"github.com/agbru/fibcalc/internal/format"
 )
 
 // HeaderModel renders the top bar: title, version, elapsed time.
 type HeaderModel struct {
+	clock     clock.Clock
 	startTime time.Time
 	endTime   time.Time
 	version   string
@@ -19,20 +19,26 @@ type HeaderModel struct {
 
 // NewHeaderModel creates a new header.
 func NewHeaderModel(version string) HeaderModel {
+	return newHeaderModel(version, clock.Real{})
+}
+
+// newHeaderModel creates a header whose elapsed time is read from clk.
+func newHeaderModel(version string, clk clock.Clock) HeaderModel {
 	return HeaderModel{
-		startTime: time.Now(),
+		clock:     clk,
+		startTime: clk.Now(),
 		version:   version,
 	}
 }
 
 // SetDone freezes the elapsed timer at the current time.
 func (h *HeaderModel) SetDone() {
-	h.endTime = time.Now()
+	h.endTime = h.clock.Now()
 }
 
 // Reset restarts the elapsed timer.
 func (h *HeaderModel) Reset() {
-	h.startTime = time.Now()
+	h.startTime = h.clock.Now()
 	h.endTime = time.Time{}
 }
 
@@ -55,12 +61,12 @@ func (h HeaderModel) View() string {
 	if !h.endTime.IsZero() {
 		duration = h.endTime.Sub(h.startTime)
 	} else {
-		duration = time.Since(h.startTime)
+		duration = h.clock.Since(h.startTime)
 	}
 	elapsed := elapsedStyle.Render(fmt.Sprintf("Elapsed: %s", format.FormatExecutionDuration(duration)))
 
 	leftPart := title + pipe + elapsed
-	leftLen := lipgloss.Width(leftPart)
+	leftLen := cellWidth(leftPart)
 
 	innerWidth := h.width - 2
 	if innerWidth < 0 {
@@ -3,6 +3,9 @@ package tui
 import (
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/clock"
 )
 
 func TestHeaderModel_View_ContainsTitle(t *testing.T) {
@@ -35,6 +38,30 @@ func TestHeaderModel_View_ContainsElapsed(t *testing.T) {
 	}
 }
 
+func TestHeaderModel_View_FakeClock(t *testing.T) {
+	clk := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	h := newHeaderModel("v1.0.0", clk)
+	h.SetWidth(80)
+
+	clk.Advance(2500 * time.Millisecond)
+	if view := h.View(); !strings.Contains(view, "Elapsed: 2.5s") {
+		t.Errorf("expected 'Elapsed: 2.5s', got %q", view)
+	}
+
+	// SetDone freezes the elapsed time.
+	h.SetDone()
+	clk.Advance(time.Minute)
+	if view := h.View(); !strings.Contains(view, "Elapsed: 2.5s") {
+		t.Errorf("expected elapsed frozen at 2.5s, got %q", view)
+	}
+
+	h.Reset()
+	clk.Advance(300 * time.Millisecond)
+	if view := h.View(); !strings.Contains(view, "Elapsed: 300ms") {
+		t.Errorf("expected 'Elapsed: 300ms' after Reset, got %q", view)
+	}
+}
+
 func TestHeaderModel_View_NarrowWidth(t *testing.T) {
 	h := NewHeaderModel("v1.0.0")
 	h.SetWidth(10)
@@ -0,0 +1,146 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/agbru/fibcalc/internal/format"
+)
+
+// idleRefresh is the refresh period of the idle screen. It only shows the
+// progress and the ETA, which do not need the dashboard's pace.
+const idleRefresh = 5 * time.Second
+
+// bigGlyphs is the block font of the idle screen's percentage, five rows
+// per character.
+var bigGlyphs = map[rune][5]string{
+	'0': {"███", "█ █", "█ █", "█ █", "███"},
+	'1': {" █ ", "██ ", " █ ", " █ ", "███"},
+	'2': {"███", "  █", "███", "█  ", "███"},
+	'3': {"███", "  █", "███", "  █", "███"},
+	'4': {"█ █", "█ █", "███", "  █", "  █"},
+	'5': {"███", "█  ", "███", "  █", "███"},
+	'6': {"███", "█  ", "███", "█ █", "███"},
+	'7': {"███", "  █", "  █", "  █", "  █"},
+	'8': {"███", "█ █", "███", "█ █", "███"},
+	'9': {"███", "█ █", "███", "  █", "███"},
+	'.': {" ", " ", " ", " ", "█"},
+	'%': {"█ █", "  █", " █ ", "█  ", "█ █"},
+}
+
+// IdleModel is the screensaver of long runs: after a while without
+// keyboard input, the dashboard gives way to a dimmed screen showing only
+// the progress in large digits and the ETA, refreshed every idleRefresh,
+// so that rendering the dashboard does not compete with the calculation.
+// Any key brings the dashboard back.
+type IdleModel struct {
+	after     time.Duration // inactivity before the idle screen; 0 disables it
+	lastInput time.Time
+	active    bool
+	width     int
+	height    int
+}
+
+// NewIdleModel creates the idle screen.
+//
+// Parameters:
+//   - after: The inactivity after which the screen turns idle; 0 never.
+//   - now: The time the dashboard starts, counted as the last input.
+//
+// Returns:
+//   - IdleModel: The idle screen, inactive.
+func NewIdleModel(after time.Duration, now time.Time) IdleModel {
+	return IdleModel{after: after, lastInput: now}
+}
+
+// SetSize updates the available size.
+func (i *IdleModel) SetSize(w, h int) {
+	i.width = w
+	i.height = h
+}
+
+// Active reports whether the idle screen is shown.
+func (i IdleModel) Active() bool {
+	return i.active
+}
+
+// Touch records keyboard input at now and leaves the idle screen.
+//
+// Returns:
+//   - bool: true if the idle screen was shown, so that the key only wakes
+//     the dashboard.
+func (i *IdleModel) Touch(now time.Time) bool {
+	i.lastInput = now
+	woke := i.active
+	i.active = false
+	return woke
+}
+
+// Wake leaves the idle screen without counting as input, for events the
+// dashboard must show.
+func (i *IdleModel) Wake() {
+	i.active = false
+}
+
+// Check shows the idle screen once the inactivity reaches the threshold.
+//
+// Returns:
+//   - bool: true if the idle screen was just shown.
+func (i *IdleModel) Check(now time.Time) bool {
+	if i.active || i.after <= 0 || now.Sub(i.lastInput) < i.after {
+		return false
+	}
+	i.active = true
+	return true
+}
+
+// View renders the idle screen.
+//
+// Parameters:
+//   - progress: The average progress, in [0, 1].
+//   - eta: The ETA line, such as "ETA: 2h13m".
+//
+// Returns:
+//   - string: The screen, centered in the available size.
+func (i IdleModel) View(progress float64, eta string) string {
+	pct := fmt.Sprintf("%.1f%%", progress*100)
+	big := bigText(pct)
+	if cellWidth(big) > i.width {
+		big = pct
+	}
+	screen := lipgloss.JoinVertical(lipgloss.Center,
+		idleStyle.Render(big),
+		"",
+		idleStyle.Render(eta),
+		"",
+		idleStyle.Render("Idle — press any key to wake"))
+	return lipgloss.Place(i.width, i.height, lipgloss.Center, lipgloss.Center, screen)
+}
+
+// bigText renders s in the block font, one space between characters.
+// Characters without a glyph are skipped.
+func bigText(s string) string {
+	var rows [5][]string
+	for _, r := range s {
+		glyph, ok := bigGlyphs[r]
+		if !ok {
+			continue
+		}
+		for row := range rows {
+			rows[row] = append(rows[row], glyph[row])
+		}
+	}
+	lines := make([]string, len(rows))
+	for row := range rows {
+		lines[row] = strings.Join(rows[row], " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// idleETA returns the ETA line of the idle screen for the chart's state.
+func idleETA(c ChartModel) string {
+	return "ETA: " + format.FormatETAWithRange(c.eta, c.etaLow, c.etaHigh)
+}
@@ -0,0 +1,121 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/agbru/fibcalc/internal/clock"
+	"github.com/agbru/fibcalc/internal/config"
+)
+
+func TestIdleModel_CheckAndTouch(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	idle := NewIdleModel(time.Minute, start)
+
+	if idle.Check(start.Add(59 * time.Second)) {
+		t.Fatal("idle before the threshold")
+	}
+	if !idle.Check(start.Add(time.Minute)) || !idle.Active() {
+		t.Fatal("expected the idle screen at the threshold")
+	}
+	if idle.Check(start.Add(2 * time.Minute)) {
+		t.Error("Check reported entering the idle screen twice")
+	}
+
+	if !idle.Touch(start.Add(3*time.Minute)) || idle.Active() {
+		t.Fatal("expected a key to wake the dashboard")
+	}
+	if idle.Touch(start.Add(4 * time.Minute)) {
+		t.Error("a key on the dashboard reported waking it")
+	}
+	// The inactivity counts from the last key
+	if idle.Check(start.Add(4*time.Minute + 59*time.Second)) {
+		t.Error("idle before the threshold after the last key")
+	}
+}
+
+func TestIdleModel_Disabled(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	idle := NewIdleModel(0, start)
+	if idle.Check(start.Add(24 * time.Hour)) {
+		t.Error("idle screen shown although disabled")
+	}
+}
+
+func TestBigText(t *testing.T) {
+	got := bigText("1.5%")
+	want := strings.Join([]string{
+		" █    ███ █ █",
+		"██    █     █",
+		" █    ███  █ ",
+		" █      █ █  ",
+		"███ █ ███ █ █",
+	}, "\n")
+	if got != want {
+		t.Errorf("bigText() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestIdleModel_View(t *testing.T) {
+	idle := NewIdleModel(time.Minute, time.Time{})
+
+	idle.SetSize(80, 24)
+	view := idle.View(0.425, "ETA: 2h")
+	if !strings.Contains(view, "███") || !strings.Contains(view, "ETA: 2h") || !strings.Contains(view, "press any key") {
+		t.Errorf("unexpected idle screen:\n%s", view)
+	}
+
+	// Too narrow for the block font
+	idle.SetSize(10, 24)
+	if view := idle.View(0.425, "ETA: 2h"); !strings.Contains(view, "42.5%") {
+		t.Errorf("expected the plain percentage on a narrow screen:\n%s", view)
+	}
+}
+
+func TestModel_IdleScreen(t *testing.T) {
+	clk := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	cfg := config.AppConfig{N: 1000, Timeout: time.Minute, TUIIdle: 10 * time.Minute}
+	m := newModel(context.Background(), nil, cfg, "v0.1.0", clk)
+	t.Cleanup(m.cancel)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = updated.(Model)
+
+	clk.Advance(10 * time.Minute)
+	updated, _ = m.Update(TickMsg{At: clk.Now()})
+	m = updated.(Model)
+	if !m.idle.Active() {
+		t.Fatal("expected the idle screen after 10 minutes without input")
+	}
+	if view := m.View(); strings.Contains(view, "Progress Chart") || !strings.Contains(view, "press any key") {
+		t.Errorf("expected the idle screen instead of the dashboard:\n%s", view)
+	}
+
+	// The waking key is not acted upon: q does not quit
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	m = updated.(Model)
+	if cmd != nil || m.idle.Active() {
+		t.Fatalf("expected q to only wake the dashboard, got cmd %v", cmd)
+	}
+	if view := m.View(); !strings.Contains(view, "Progress Chart") {
+		t.Errorf("expected the dashboard after a key:\n%s", view)
+	}
+}
+
+func TestModel_IdleScreen_WakesOnCompletion(t *testing.T) {
+	clk := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	cfg := config.AppConfig{N: 1000, Timeout: time.Minute, TUIIdle: time.Minute}
+	m := newModel(context.Background(), nil, cfg, "v0.1.0", clk)
+	t.Cleanup(m.cancel)
+
+	clk.Advance(time.Minute)
+	updated, _ := m.Update(TickMsg{At: clk.Now()})
+	m = updated.(Model)
+	updated, _ = m.Update(CalculationCompleteMsg{ExitCode: 0, Generation: m.generation})
+	if updated.(Model).idle.Active() {
+		t.Error("expected the dashboard back when the run completes")
+	}
+}
@@ -2,15 +2,40 @@ package tui
 
 import "github.com/charmbracelet/bubbles/key"
 
-// KeyMap defines keyboard bindings for the TUI.
+// KeyMap defines keyboard bindings for the TUI. A key is bound to one
+// action per mode (see Help): the dashboard, and the overlays that take the
+// keys while shown. Across modes it may be reused — n is the next search
+// match on the dashboard and lets the deadline fire on the timeout prompt,
+// esc clears the search on the dashboard and closes the overlays.
 type KeyMap struct {
-	Quit       key.Binding
-	Pause      key.Binding
-	Reset      key.Binding
-	Up         key.Binding
-	Down       key.Binding
-	PageUp     key.Binding
-	PageDown   key.Binding
+	Quit        key.Binding
+	Pause       key.Binding
+	Reset       key.Binding
+	Up          key.Binding
+	Down        key.Binding
+	PageUp      key.Binding
+	PageDown    key.Binding
+	Verbosity   key.Binding
+	Follow      key.Binding
+	Search      key.Binding
+	NextMatch   key.Binding
+	PrevMatch   key.Binding
+	ClearSearch key.Binding
+	Summary     key.Binding
+	SaveReport  key.Binding
+	Export      key.Binding
+	Close       key.Binding
+	Compare     key.Binding
+	HexValue    key.Binding
+	FullValue   key.Binding
+	DigitWindow key.Binding
+	Left        key.Binding
+	Right       key.Binding
+	Home        key.Binding
+	End         key.Binding
+	Extend      key.Binding
+	Decline     key.Binding
+	About       key.Binding
 }
 
 // DefaultKeyMap returns the default keyboard bindings.
@@ -44,5 +69,119 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("pgdown"),
 			key.WithHelp("pgdn", "Page down"),
 		),
+		Verbosity: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "Sampled/full logs"),
+		),
+		Follow: key.NewBinding(
+			key.WithKeys("f"),
+			key.WithHelp("f", "Follow new log entries"),
+		),
+		Search: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "Search logs"),
+		),
+		NextMatch: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "Next match"),
+		),
+		PrevMatch: key.NewBinding(
+			key.WithKeys("N"),
+			key.WithHelp("N", "Previous match"),
+		),
+		ClearSearch: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "Clear search"),
+		),
+		Summary: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "Show run summary"),
+		),
+		SaveReport: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "Save summary report"),
+		),
+		Export: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "Export result"),
+		),
+		Close: key.NewBinding(
+			key.WithKeys("esc", "enter"),
+			key.WithHelp("esc", "Close"),
+		),
+		Compare: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "Summary/baseline comparison"),
+		),
+		HexValue: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "Result in hex"),
+		),
+		FullValue: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "Full decimal result (once done)"),
+		),
+		DigitWindow: key.NewBinding(
+			key.WithKeys("z"),
+			key.WithHelp("z", "Result digit window"),
+		),
+		Left: key.NewBinding(
+			key.WithKeys("left", "h"),
+			key.WithHelp("left/h", "Previous digit window"),
+		),
+		Right: key.NewBinding(
+			key.WithKeys("right", "l"),
+			key.WithHelp("right/l", "Next digit window"),
+		),
+		Home: key.NewBinding(
+			key.WithKeys("home", "g"),
+			key.WithHelp("home/g", "First digits"),
+		),
+		End: key.NewBinding(
+			key.WithKeys("end", "G"),
+			key.WithHelp("end/G", "Last digits"),
+		),
+		Extend: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", "Extend the timeout"),
+		),
+		Decline: key.NewBinding(
+			key.WithKeys("n", "esc"),
+			key.WithHelp("n/esc", "Keep the timeout"),
+		),
+		About: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "About fibcalc and keys"),
+		),
+	}
+}
+
+// ModeHelp lists the bindings active in one mode of the TUI.
+type ModeHelp struct {
+	Mode     string
+	Bindings []key.Binding
+}
+
+// Help returns the bindings of each mode, in the order handleKey and the
+// overlay handlers match them. The about screen shows it as the key help.
+//
+// Returns:
+//   - []ModeHelp: The dashboard first, then each overlay.
+func (k KeyMap) Help() []ModeHelp {
+	return []ModeHelp{
+		{"Dashboard", []key.Binding{
+			k.Quit, k.Pause, k.Reset, k.FullValue, k.Verbosity, k.HexValue, k.DigitWindow,
+			k.Follow, k.Search, k.NextMatch, k.PrevMatch, k.ClearSearch, k.Summary, k.About,
+			k.Up, k.Down, k.PageUp, k.PageDown,
+		}},
+		{"Summary", []key.Binding{
+			k.SaveReport, k.Export, k.Compare, k.HexValue, k.FullValue, k.Close, k.Summary, k.Quit, k.Reset,
+		}},
+		{"Result viewer", []key.Binding{
+			k.HexValue, k.FullValue, k.DigitWindow, k.Up, k.Left, k.Down, k.Right,
+			k.PageUp, k.PageDown, k.Home, k.End, k.Close, k.Quit, k.Reset,
+		}},
+		{"Timeout prompt", []key.Binding{k.Extend, k.Decline, k.Quit, k.Reset}},
+		{"About", []key.Binding{k.Close, k.About, k.Quit, k.Reset}},
 	}
 }
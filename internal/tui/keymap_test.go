@@ -20,6 +20,27 @@ func TestDefaultKeyMap_AllBindingsDefined(t *testing.T) {
 		{"Down", km.Down},
 		{"PageUp", km.PageUp},
 		{"PageDown", km.PageDown},
+		{"Verbosity", km.Verbosity},
+		{"Follow", km.Follow},
+		{"Search", km.Search},
+		{"NextMatch", km.NextMatch},
+		{"PrevMatch", km.PrevMatch},
+		{"ClearSearch", km.ClearSearch},
+		{"Summary", km.Summary},
+		{"SaveReport", km.SaveReport},
+		{"Export", km.Export},
+		{"Close", km.Close},
+		{"Compare", km.Compare},
+		{"HexValue", km.HexValue},
+		{"FullValue", km.FullValue},
+		{"DigitWindow", km.DigitWindow},
+		{"Left", km.Left},
+		{"Right", km.Right},
+		{"Home", km.Home},
+		{"End", km.End},
+		{"Extend", km.Extend},
+		{"Decline", km.Decline},
+		{"About", km.About},
 	}
 
 	for _, b := range bindings {
@@ -35,6 +56,23 @@ func TestDefaultKeyMap_AllBindingsDefined(t *testing.T) {
 	}
 }
 
+// TestDefaultKeyMap_NoConflicts verifies that within each mode a key
+// triggers a single action.
+func TestDefaultKeyMap_NoConflicts(t *testing.T) {
+	for _, mode := range DefaultKeyMap().Help() {
+		owner := make(map[string]string)
+		for _, b := range mode.Bindings {
+			desc := b.Help().Desc
+			for _, k := range b.Keys() {
+				if other, ok := owner[k]; ok && other != desc {
+					t.Errorf("%s: %q is bound to both %q and %q", mode.Mode, k, other, desc)
+				}
+				owner[k] = desc
+			}
+		}
+	}
+}
+
 func TestDefaultKeyMap_QuitKeys(t *testing.T) {
 	km := DefaultKeyMap()
 
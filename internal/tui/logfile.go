@@ -0,0 +1,35 @@
+package tui
+
+import (
+	"io"
+	"sync"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// plainLog is the writer of --log-file. It strips the colors of the CLI
+// output, and serializes writes as a restarted calculation may start
+// logging before the canceled one has finished.
+type plainLog struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// newPlainLog returns a plain-text log writing to w, or nil if w is nil.
+func newPlainLog(w io.Writer) io.Writer {
+	if w == nil {
+		return nil
+	}
+	return &plainLog{w: w}
+}
+
+// Write writes p without its ANSI escape sequences. It reports len(p) on
+// success, as the written byte count differs from p's.
+func (l *plainLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := io.WriteString(l.w, ansi.Strip(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
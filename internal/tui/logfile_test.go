@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/config"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/fibtest"
+)
+
+func TestPlainLogStripsColors(t *testing.T) {
+	var buf bytes.Buffer
+	log := newPlainLog(&buf)
+	colored := []byte("\x1b[38;5;82mSuccess\x1b[0m\n")
+	n, err := log.Write(colored)
+	if err != nil || n != len(colored) {
+		t.Fatalf("Write = %d, %v", n, err)
+	}
+	if got := buf.String(); got != "Success\n" {
+		t.Errorf("log = %q, want %q", got, "Success\n")
+	}
+	if newPlainLog(nil) != nil {
+		t.Error("newPlainLog(nil) should return nil")
+	}
+}
+
+func TestStartCalculationCmd_LogFile(t *testing.T) {
+	var buf bytes.Buffer
+	ref := &programRef{}
+	calc := fibtest.Fixed("Fast", nil)
+	cfg := config.AppConfig{N: 10, Timeout: time.Minute}
+
+	cmd := startCalculationCmd(ref, context.Background(), []fibonacci.Calculator{calc}, cfg, 0, nil, session{logOut: newPlainLog(&buf)})
+	if _, ok := cmd().(CalculationCompleteMsg); !ok {
+		t.Fatal("expected CalculationCompleteMsg")
+	}
+
+	log := buf.String()
+	for _, want := range []string{"--- Execution Configuration ---", "--- Comparison Summary ---", "Fast"} {
+		if !strings.Contains(log, want) {
+			t.Errorf("log does not contain %q:\n%s", want, log)
+		}
+	}
+	if strings.Contains(log, "\x1b[") {
+		t.Errorf("log contains escape sequences:\n%q", log)
+	}
+}
@@ -9,41 +9,112 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/agbru/fibcalc/internal/clock"
 	"github.com/agbru/fibcalc/internal/config"
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/fibonacci/memory"
 	"github.com/agbru/fibcalc/internal/format"
 	"github.com/agbru/fibcalc/internal/orchestration"
 )
 
 const maxLogEntries = 10000
 
+// logSampleEvery is the sampling ratio for progress entries: unless full
+// verbosity is enabled, only every Nth update of a calculator is logged,
+// plus its first update, each 10% milestone and completion.
+const logSampleEvery = 10
+
+// progressSampler tracks the progress updates seen for one calculator.
+type progressSampler struct {
+	count  int
+	decile int
+}
+
 // LogsModel manages the scrollable log panel.
 type LogsModel struct {
 	viewport    viewport.Model
 	entries     []string
-	autoScroll  bool
+	follow      bool // keep the newest entry in view; toggled explicitly
 	width       int
 	height      int
 	algoNames   []string // algorithm names for mapping index -> name
+	clock       clock.Clock // source of the entry timestamps
+
+	fullVerbosity bool                    // log every progress update
+	samplers      map[int]progressSampler // per-calculator sampling state
+	sampledOut    int                     // progress updates not logged
+
+	search logSearch
 }
 
 // NewLogsModel creates a new logs panel.
 func NewLogsModel(algoNames []string) LogsModel {
+	return newLogsModel(algoNames, clock.Real{})
+}
+
+// newLogsModel creates a logs panel whose entries are timestamped with clk.
+func newLogsModel(algoNames []string, clk clock.Clock) LogsModel {
 	vp := viewport.New(40, 10)
 	return LogsModel{
 		viewport:   vp,
 		entries:    make([]string, 0, 64),
-		autoScroll: true,
+		follow:     true,
 		algoNames:  algoNames,
+		clock:      clk,
+		samplers:   make(map[int]progressSampler),
 	}
 }
 
-// Reset clears all log entries.
+// Reset clears all log entries and the active search, and resumes
+// following. The verbosity setting is kept.
 func (l *LogsModel) Reset() {
 	l.entries = l.entries[:0]
-	l.autoScroll = true
+	l.follow = true
+	l.search = logSearch{}
+	l.samplers = make(map[int]progressSampler)
+	l.sampledOut = 0
 	l.updateContent()
 }
 
+// ToggleVerbosity switches between sampled and full progress logging and
+// records the switch in the log.
+//
+// Returns:
+//   - bool: true if full verbosity is now enabled.
+func (l *LogsModel) ToggleVerbosity() bool {
+	l.fullVerbosity = !l.fullVerbosity
+	mode := "sampled"
+	if l.fullVerbosity {
+		mode = "full"
+	}
+	l.entries = append(l.entries, logTimeStyle.Render(fmt.Sprintf("--- Progress logging: %s (%d updates skipped so far) ---", mode, l.sampledOut)))
+	l.trimEntries()
+	l.updateContent()
+	return l.fullVerbosity
+}
+
+// FullVerbosity reports whether every progress update is logged.
+func (l LogsModel) FullVerbosity() bool {
+	return l.fullVerbosity
+}
+
+// shouldLogProgress applies the sampling policy to a progress update and
+// records it in the calculator's sampler.
+func (l *LogsModel) shouldLogProgress(msg ProgressMsg) bool {
+	s, seen := l.samplers[msg.CalculatorIndex]
+	decile := int(msg.Value * 10)
+	keep := l.fullVerbosity ||
+		!seen ||
+		msg.Value >= 1.0 ||
+		decile != s.decile ||
+		(s.count+1)%logSampleEvery == 0
+	s.count++
+	s.decile = decile
+	l.samplers[msg.CalculatorIndex] = s
+	return keep
+}
+
 // SetSize updates the viewport dimensions.
 func (l *LogsModel) SetSize(w, h int) {
 	l.width = w
@@ -58,7 +129,7 @@ func (l *LogsModel) AddExecutionConfig(cfg config.AppConfig) {
 	l.entries = append(l.entries, logAlgoStyle.Render("--- Execution Configuration ---"))
 	l.entries = append(l.entries, fmt.Sprintf("  Calculating %s with a timeout of %s.",
 		logAlgoStyle.Render(fmt.Sprintf("F(%d)", cfg.N)),
-		metricValueStyle.Render(cfg.Timeout.String())))
+		metricValueStyle.Render(format.FormatExecutionDuration(cfg.Timeout))))
 	l.entries = append(l.entries, fmt.Sprintf("  Environment: %s logical processors, Go %s.",
 		metricValueStyle.Render(fmt.Sprintf("%d", runtime.NumCPU())),
 		metricValueStyle.Render(runtime.Version())))
@@ -68,7 +139,14 @@ func (l *LogsModel) AddExecutionConfig(cfg config.AppConfig) {
 
 	var modeDesc string
 	if len(l.algoNames) > 1 {
-		modeDesc = "Parallel comparison of all algorithms"
+		switch orchestration.CompareMode(cfg.CompareMode) {
+		case orchestration.CompareSequential:
+			modeDesc = "Sequential comparison of all algorithms"
+		case orchestration.CompareStaggered:
+			modeDesc = "Staggered comparison of all algorithms"
+		default:
+			modeDesc = "Parallel comparison of all algorithms"
+		}
 	} else if len(l.algoNames) == 1 {
 		modeDesc = fmt.Sprintf("Single calculation with the %s algorithm", logSuccessStyle.Render(l.algoNames[0]))
 	}
@@ -77,11 +155,33 @@ func (l *LogsModel) AddExecutionConfig(cfg config.AppConfig) {
 	l.updateContent()
 }
 
-// AddProgressEntry adds a progress log line.
+// AddBaseline logs the baseline report the run will be compared against,
+// or why it could not be loaded.
+func (l *LogsModel) AddBaseline(path string, r RunReport, err error) {
+	if err != nil {
+		l.entries = append(l.entries, logErrorStyle.Render(fmt.Sprintf("  Baseline not loaded: %v", err)))
+	} else {
+		l.entries = append(l.entries, fmt.Sprintf("  Baseline: %s with %s in %s, from %s.",
+			logAlgoStyle.Render(fmt.Sprintf("F(%d)", r.N)),
+			logSuccessStyle.Render(r.Algorithm),
+			metricValueStyle.Render(format.FormatExecutionDuration(r.Duration)),
+			path))
+	}
+	l.entries = append(l.entries, "")
+	l.updateContent()
+}
+
+// AddProgressEntry adds a progress log line, subject to sampling unless
+// full verbosity is enabled.
 func (l *LogsModel) AddProgressEntry(msg ProgressMsg) {
-	ts := logTimeStyle.Render(time.Now().Format("15:04:05"))
+	if !l.shouldLogProgress(msg) {
+		l.sampledOut++
+		return
+	}
+
+	ts := logTimeStyle.Render(l.clock.Now().Format("15:04:05"))
 	name := l.algoName(msg.CalculatorIndex)
-	algoStr := logAlgoStyle.Render(fmt.Sprintf("%-16s", name))
+	algoStr := logAlgoStyle.Render(padCell(name, 16))
 
 	var progressStr string
 	if msg.Value >= 1.0 {
@@ -101,33 +201,31 @@ func (l *LogsModel) AddResults(results []orchestration.CalculationResult) {
 	l.entries = append(l.entries, "")
 	l.entries = append(l.entries, logAlgoStyle.Render("--- Comparison Summary ---"))
 
-	// Find max name and duration widths for column alignment
+	// Find max name and duration widths for column alignment, in cells:
+	// durations may hold "µs" or French unit names
 	maxNameLen := 0
 	maxDurLen := 0
 	for _, res := range results {
-		if len(res.Name) > maxNameLen {
-			maxNameLen = len(res.Name)
-		}
-		dur := format.FormatExecutionDuration(res.Duration)
-		if len(dur) > maxDurLen {
-			maxDurLen = len(dur)
-		}
+		maxNameLen = max(maxNameLen, cellWidth(res.Name))
+		maxDurLen = max(maxDurLen, cellWidth(format.FormatExecutionDuration(res.Duration)))
 	}
 
-	nameFmt := fmt.Sprintf("%%-%ds", maxNameLen)
-	durFmt := fmt.Sprintf("%%%ds", maxDurLen)
-
 	for _, res := range results {
 		var status string
-		if res.Err != nil {
-			status = logErrorStyle.Render(fmt.Sprintf("FAIL (%v)", res.Err))
-		} else {
+		switch kind := res.ErrorKind(); {
+		case kind == apperrors.ErrorKindNone:
 			status = logSuccessStyle.Render("OK")
+		case kind == apperrors.ErrorKindInternal:
+			status = logErrorStyle.Render(fmt.Sprintf("FAIL (%v)", res.Err))
+		case res.Err != nil:
+			status = logErrorStyle.Render(fmt.Sprintf("%s (%v)", strings.ToUpper(kind.String()), res.Err))
+		default:
+			status = logErrorStyle.Render(strings.ToUpper(kind.String()))
 		}
 		duration := format.FormatExecutionDuration(res.Duration)
 		entry := fmt.Sprintf("  %s  %s  %s",
-			logAlgoStyle.Render(fmt.Sprintf(nameFmt, res.Name)),
-			metricValueStyle.Render(fmt.Sprintf(durFmt, duration)),
+			logAlgoStyle.Render(padCell(res.Name, maxNameLen)),
+			metricValueStyle.Render(padCellLeft(duration, maxDurLen)),
 			status)
 		l.entries = append(l.entries, entry)
 	}
@@ -151,25 +249,73 @@ func (l *LogsModel) AddFinalResult(msg FinalResultMsg) {
 
 // AddError adds an error entry to the log.
 func (l *LogsModel) AddError(msg ErrorMsg) {
-	ts := logTimeStyle.Render(time.Now().Format("15:04:05"))
+	ts := logTimeStyle.Render(l.clock.Now().Format("15:04:05"))
 	entry := fmt.Sprintf("[%s] %s", ts, logErrorStyle.Render(fmt.Sprintf("ERROR: %v", msg.Err)))
 	l.entries = append(l.entries, entry)
 	l.trimEntries()
 	l.updateContent()
 }
 
-// Update handles viewport keyboard events.
+// AddDeadlineExtended records an extension of the timeout.
+func (l *LogsModel) AddDeadlineExtended(by, remaining time.Duration) {
+	ts := logTimeStyle.Render(l.clock.Now().Format("15:04:05"))
+	entry := fmt.Sprintf("[%s] Timeout extended by %s, %s left.", ts,
+		metricValueStyle.Render(format.FormatETA(by)), metricValueStyle.Render(format.FormatETA(remaining)))
+	l.entries = append(l.entries, entry)
+	l.trimEntries()
+	l.updateContent()
+}
+
+// AddStatePool records the calculation states kept from the previous runs
+// that the restarted calculation can reuse. Nothing is logged without any.
+func (l *LogsModel) AddStatePool(stats fibonacci.StatePoolStats) {
+	if stats.States == 0 {
+		return
+	}
+	ts := logTimeStyle.Render(l.clock.Now().Format("15:04:05"))
+	entry := fmt.Sprintf("[%s] Reusing %s of buffers in %d states kept from previous runs.", ts,
+		metricValueStyle.Render(format.FormatBytes(uint64(stats.Bytes))), stats.States)
+	l.entries = append(l.entries, entry)
+	l.trimEntries()
+	l.updateContent()
+}
+
+// AddMemoryPressure records that the memory approached the runtime's soft
+// limit and that the transform caches were dropped.
+func (l *LogsModel) AddMemoryPressure(p memory.Pressure) {
+	ts := logTimeStyle.Render(l.clock.Now().Format("15:04:05"))
+	entry := fmt.Sprintf("[%s] %s memory at %s of the %s limit; FFT transform caches dropped and disabled.", ts,
+		logWarnStyle.Render("WARNING:"), metricValueStyle.Render(format.FormatBytes(p.Used)), metricValueStyle.Render(format.FormatBytes(p.Limit)))
+	l.entries = append(l.entries, entry)
+	l.trimEntries()
+	l.updateContent()
+}
+
+// Update handles viewport keyboard events. Scrolling does not change the
+// follow mode; while following, the next entry brings the view back to
+// the bottom.
 func (l *LogsModel) Update(msg tea.Msg) {
 	var cmd tea.Cmd
 	l.viewport, cmd = l.viewport.Update(msg)
 	_ = cmd
+}
 
-	// If user scrolled up manually, disable auto-scroll
-	if l.viewport.AtBottom() {
-		l.autoScroll = true
-	} else {
-		l.autoScroll = false
+// ToggleFollow switches follow mode, jumping to the newest entry when it
+// is turned on.
+//
+// Returns:
+//   - bool: true if the panel now follows new entries.
+func (l *LogsModel) ToggleFollow() bool {
+	l.follow = !l.follow
+	if l.follow {
+		l.viewport.GotoBottom()
 	}
+	return l.follow
+}
+
+// Following reports whether the panel keeps the newest entry in view.
+func (l LogsModel) Following() bool {
+	return l.follow
 }
 
 // View renders the logs panel.
@@ -178,11 +324,18 @@ func (l LogsModel) View() string {
 }
 
 // renderToHeight renders the logs panel to the specified total height.
+// An active search replaces the last visible line with its status.
 func (l LogsModel) renderToHeight(h int) string {
+	content := l.viewport.View()
+	if status := l.searchStatus(); status != "" {
+		lines := strings.Split(content, "\n")
+		lines[len(lines)-1] = logProgressStyle.Render(status)
+		content = strings.Join(lines, "\n")
+	}
 	return panelStyle.
 		Width(l.width - 2).
 		Height(max(h-2, 0)).
-		Render(l.viewport.View())
+		Render(fitLines(content, l.width-2))
 }
 
 func (l *LogsModel) trimEntries() {
@@ -192,9 +345,9 @@ func (l *LogsModel) trimEntries() {
 }
 
 func (l *LogsModel) updateContent() {
-	content := strings.Join(l.entries, "\n")
+	content := strings.Join(l.highlightEntries(), "\n")
 	l.viewport.SetContent(content)
-	if l.autoScroll {
+	if l.follow {
 		l.viewport.GotoBottom()
 	}
 }
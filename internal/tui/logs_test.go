@@ -9,6 +9,8 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/fibonacci/memory"
 	"github.com/agbru/fibcalc/internal/orchestration"
 )
 
@@ -158,6 +160,30 @@ func TestLogsModel_AddError(t *testing.T) {
 	}
 }
 
+func TestLogsModel_AddStatePool(t *testing.T) {
+	logs := NewLogsModel([]string{})
+	logs.SetSize(60, 20)
+
+	logs.AddStatePool(fibonacci.StatePoolStats{})
+	if len(logs.entries) != 0 {
+		t.Fatalf("expected no entry for an empty pool, got %d", len(logs.entries))
+	}
+	logs.AddStatePool(fibonacci.StatePoolStats{States: 2, Bytes: 3 << 20})
+	if len(logs.entries) != 1 || !strings.Contains(logs.entries[0], "2 states") {
+		t.Errorf("expected an entry for 2 kept states, got %q", logs.entries)
+	}
+}
+
+func TestLogsModel_AddMemoryPressure(t *testing.T) {
+	logs := NewLogsModel([]string{})
+	logs.SetSize(60, 20)
+
+	logs.AddMemoryPressure(memory.Pressure{Used: 900 << 20, Limit: 1 << 30})
+	if len(logs.entries) != 1 || !strings.Contains(logs.entries[0], "900.0 MB") || !strings.Contains(logs.entries[0], "1.0 GB") {
+		t.Errorf("expected an entry for 900 MB of 1 GB, got %q", logs.entries)
+	}
+}
+
 func TestLogsModel_AlgoName_OutOfBounds(t *testing.T) {
 	logs := NewLogsModel([]string{"Fast Doubling"})
 
@@ -198,9 +224,9 @@ func TestLogsModel_AutoScroll(t *testing.T) {
 		logs.AddProgressEntry(ProgressMsg{CalculatorIndex: 0, Value: float64(i) / 50})
 	}
 
-	// autoScroll should still be true (GotoBottom called)
-	if !logs.autoScroll {
-		t.Error("expected autoScroll to be true after adding entries")
+	// follow should still be true (GotoBottom called)
+	if !logs.follow {
+		t.Error("expected follow to be true after adding entries")
 	}
 }
 
@@ -261,29 +287,64 @@ func TestLogsModel_algoName_NegativeProducesUnknown(t *testing.T) {
 	}
 }
 
-func TestLogsModel_AutoScroll_DisablesOnScrollUp(t *testing.T) {
+func TestLogsModel_ScrollUp_KeepsFollow(t *testing.T) {
 	logs := NewLogsModel([]string{"Fast"})
 	logs.SetSize(60, 5)
+	logs.ToggleVerbosity()
 
 	// Add many entries to overflow viewport
 	for i := 0; i < 100; i++ {
 		logs.AddProgressEntry(ProgressMsg{CalculatorIndex: 0, Value: float64(i) / 100})
 	}
 
-	if !logs.autoScroll {
-		t.Fatal("precondition: autoScroll should be true")
+	// Scroll up
+	logs.Update(tea.KeyMsg{Type: tea.KeyPgUp})
+	if logs.viewport.AtBottom() {
+		t.Fatal("precondition: viewport should have scrolled up")
+	}
+	if !logs.follow {
+		t.Error("scrolling should not disable follow mode")
+	}
+
+	// The next entry brings the view back to the bottom
+	logs.AddError(ErrorMsg{Err: errors.New("boom")})
+	if !logs.viewport.AtBottom() {
+		t.Error("expected a new entry to scroll back to the bottom while following")
 	}
+}
 
-	// Scroll up
+func TestLogsModel_ToggleFollow(t *testing.T) {
+	logs := NewLogsModel([]string{"Fast"})
+	logs.SetSize(60, 5)
+	logs.ToggleVerbosity()
+	for i := 0; i < 100; i++ {
+		logs.AddProgressEntry(ProgressMsg{CalculatorIndex: 0, Value: float64(i) / 100})
+	}
+
+	if logs.ToggleFollow() {
+		t.Fatal("expected follow to be off after the first toggle")
+	}
 	logs.Update(tea.KeyMsg{Type: tea.KeyPgUp})
+	offset := logs.viewport.YOffset
+	logs.AddError(ErrorMsg{Err: errors.New("boom")})
+	if logs.viewport.YOffset != offset {
+		t.Errorf("expected the view to stay at offset %d while not following, got %d", offset, logs.viewport.YOffset)
+	}
 
-	// After scrolling up and not being at bottom, autoScroll should be false
-	if logs.viewport.AtBottom() {
-		// If we're still at bottom (viewport larger than content), this test is not meaningful
-		t.Skip("viewport is larger than content, scroll test not applicable")
+	if !logs.ToggleFollow() {
+		t.Fatal("expected follow to be on after the second toggle")
 	}
-	if logs.autoScroll {
-		t.Error("expected autoScroll to be false after scrolling up")
+	if !logs.viewport.AtBottom() {
+		t.Error("expected enabling follow to jump to the bottom")
+	}
+}
+
+func TestLogsModel_Reset_ResumesFollow(t *testing.T) {
+	logs := NewLogsModel([]string{"Fast"})
+	logs.ToggleFollow()
+	logs.Reset()
+	if !logs.Following() {
+		t.Error("expected Reset to resume following")
 	}
 }
 
@@ -323,3 +384,71 @@ func TestLogsModel_SetSize(t *testing.T) {
 		t.Errorf("expected viewport height 28, got %d", logs.viewport.Height)
 	}
 }
+
+func TestLogsModel_ProgressSampling(t *testing.T) {
+	logs := NewLogsModel([]string{"Fast"})
+	logs.SetSize(60, 20)
+
+	// 1000 updates within the first 10%: only the first and every
+	// logSampleEvery-th update are kept.
+	for i := 0; i < 1000; i++ {
+		logs.AddProgressEntry(ProgressMsg{CalculatorIndex: 0, Value: float64(i) / 100000})
+	}
+	want := 1 + 1000/logSampleEvery // first update + every Nth
+	if len(logs.entries) != want {
+		t.Errorf("expected %d sampled entries, got %d", want, len(logs.entries))
+	}
+	if logs.sampledOut+len(logs.entries) != 1000 {
+		t.Errorf("sampled out %d + kept %d should equal 1000", logs.sampledOut, len(logs.entries))
+	}
+}
+
+func TestLogsModel_ProgressSampling_KeepsMilestonesAndCompletion(t *testing.T) {
+	logs := NewLogsModel([]string{"Fast", "Matrix"})
+	logs.SetSize(60, 20)
+
+	logs.AddProgressEntry(ProgressMsg{CalculatorIndex: 0, Value: 0.01}) // first for calc 0
+	logs.AddProgressEntry(ProgressMsg{CalculatorIndex: 0, Value: 0.02}) // sampled out
+	logs.AddProgressEntry(ProgressMsg{CalculatorIndex: 1, Value: 0.02}) // first for calc 1
+	logs.AddProgressEntry(ProgressMsg{CalculatorIndex: 0, Value: 0.15}) // new 10% milestone
+	logs.AddProgressEntry(ProgressMsg{CalculatorIndex: 0, Value: 0.16}) // sampled out
+	logs.AddProgressEntry(ProgressMsg{CalculatorIndex: 0, Value: 1.0})  // completion
+
+	if len(logs.entries) != 4 {
+		t.Fatalf("expected 4 entries, got %d: %v", len(logs.entries), logs.entries)
+	}
+	if !strings.Contains(logs.entries[3], "100% OK") {
+		t.Errorf("expected completion entry to be kept, got %q", logs.entries[3])
+	}
+	if logs.sampledOut != 2 {
+		t.Errorf("expected 2 sampled-out updates, got %d", logs.sampledOut)
+	}
+}
+
+func TestLogsModel_ToggleVerbosity(t *testing.T) {
+	logs := NewLogsModel([]string{"Fast"})
+	logs.SetSize(60, 20)
+
+	if logs.FullVerbosity() {
+		t.Fatal("expected sampled logging by default")
+	}
+	if !logs.ToggleVerbosity() {
+		t.Fatal("expected full verbosity after toggle")
+	}
+	marker := len(logs.entries)
+
+	for i := 0; i < 50; i++ {
+		logs.AddProgressEntry(ProgressMsg{CalculatorIndex: 0, Value: float64(i) / 100000})
+	}
+	if got := len(logs.entries) - marker; got != 50 {
+		t.Errorf("expected every update to be logged in full mode, got %d", got)
+	}
+
+	logs.Reset()
+	if !logs.FullVerbosity() {
+		t.Error("Reset should keep the verbosity setting")
+	}
+	if logs.ToggleVerbosity() {
+		t.Error("expected sampled logging after second toggle")
+	}
+}
@@ -3,8 +3,10 @@ package tui
 import (
 	"time"
 
+	"github.com/agbru/fibcalc/internal/fibonacci/memory"
 	"github.com/agbru/fibcalc/internal/metrics"
 	"github.com/agbru/fibcalc/internal/orchestration"
+	"github.com/agbru/fibcalc/internal/progress"
 )
 
 // ProgressMsg carries a progress update from a calculator to the TUI.
@@ -13,6 +15,8 @@ type ProgressMsg struct {
 	Value           float64
 	AverageProgress float64
 	ETA             time.Duration
+	ETALow          time.Duration
+	ETAHigh         time.Duration
 }
 
 // ProgressDoneMsg signals that the progress channel has been closed.
@@ -38,8 +42,19 @@ type ErrorMsg struct {
 	Duration time.Duration
 }
 
-// TickMsg triggers periodic metric sampling.
-type TickMsg time.Time
+// TickMsg is sent once per refresh period by the dashboard's scheduler
+// (see tickCmd), with the metrics sampled at that time: a single timer
+// drives the redraw and both samplers, so the dashboard wakes once per
+// period.
+type TickMsg struct {
+	At time.Time
+	// Generation is the calculation the scheduler was started for. Ticks
+	// of an earlier calculation are dropped, which stops its scheduler.
+	Generation uint64
+	// Mem and Sys are the samples, nil when the dashboard was paused.
+	Mem *MemStatsMsg
+	Sys *SysStatsMsg
+}
 
 // MemStatsMsg carries runtime memory statistics.
 type MemStatsMsg struct {
@@ -48,6 +63,8 @@ type MemStatsMsg struct {
 	NumGC        uint32
 	PauseTotalNs uint64
 	NumGoroutine int
+	// Progress counts the progress updates sent and lost so far.
+	Progress progress.DropStats
 }
 
 // CalculationCompleteMsg signals that all calculations have finished.
@@ -67,6 +84,12 @@ type IndicatorsMsg struct {
 	Indicators *metrics.Indicators
 }
 
+// MemoryPressureMsg signals that the memory approached the runtime's soft
+// limit and that the transform caches were dropped.
+type MemoryPressureMsg struct {
+	Pressure memory.Pressure
+}
+
 // ContextCancelledMsg signals that the context was cancelled.
 type ContextCancelledMsg struct {
 	Err        error
@@ -5,10 +5,11 @@ import (
 	"strings"
 	"time"
 
-	"github.com/charmbracelet/lipgloss"
-
+	"github.com/agbru/fibcalc/internal/clock"
+	"github.com/agbru/fibcalc/internal/config"
 	"github.com/agbru/fibcalc/internal/format"
 	"github.com/agbru/fibcalc/internal/metrics"
+	"github.com/agbru/fibcalc/internal/progress"
 )
 
 // EMA smoothing constants for speed calculation.
@@ -20,22 +21,34 @@ const (
 // MetricsModel displays runtime memory and performance metrics.
 type MetricsModel struct {
 	alloc        uint64
+	peakAlloc    uint64 // highest sampled alloc
 	heapSys      uint64
 	numGC        uint32
 	pauseTotalNs uint64
 	numGoroutine int
+	progress     progress.DropStats // progress updates sent and lost
 	speed        float64 // progress per second
 	lastProgress float64
 	lastUpdate   time.Time
+	clock        clock.Clock
+	throughput   metrics.ThroughputHistory
 	indicators   *metrics.Indicators
+	softRealtime bool          // --soft-realtime: show worstRefresh
+	worstRefresh time.Duration // longest delay of a refresh past its tick
 	width        int
 	height       int
 }
 
 // NewMetricsModel creates a new metrics panel.
 func NewMetricsModel() MetricsModel {
+	return newMetricsModel(clock.Real{})
+}
+
+// newMetricsModel creates a metrics panel whose speed is timed with clk.
+func newMetricsModel(clk clock.Clock) MetricsModel {
 	return MetricsModel{
-		lastUpdate: time.Now(),
+		lastUpdate: clk.Now(),
+		clock:      clk,
 	}
 }
 
@@ -48,15 +61,35 @@ func (m *MetricsModel) SetSize(w, h int) {
 // UpdateMemStats updates memory statistics.
 func (m *MetricsModel) UpdateMemStats(msg MemStatsMsg) {
 	m.alloc = msg.Alloc
+	m.peakAlloc = max(m.peakAlloc, msg.Alloc)
 	m.heapSys = msg.HeapSys
 	m.numGC = msg.NumGC
 	m.pauseTotalNs = msg.PauseTotalNs
 	m.numGoroutine = msg.NumGoroutine
+	m.progress = msg.Progress
+}
+
+// SetSoftRealtime shows, for --soft-realtime, the longest delay of a
+// refresh against config.SoftRealtimeTarget.
+func (m *MetricsModel) SetSoftRealtime(on bool) {
+	m.softRealtime = on
+}
+
+// RecordRefreshDelay records how long after its tick a refresh was
+// handled: the time the dashboard stuttered.
+func (m *MetricsModel) RecordRefreshDelay(d time.Duration) {
+	m.worstRefresh = max(m.worstRefresh, d)
+}
+
+// PeakAlloc returns the highest heap allocation sampled so far. Samples are
+// taken every tick, so short-lived peaks between ticks are missed.
+func (m MetricsModel) PeakAlloc() uint64 {
+	return m.peakAlloc
 }
 
 // UpdateProgress updates the speed metric.
 func (m *MetricsModel) UpdateProgress(progress float64) {
-	now := time.Now()
+	now := m.clock.Now()
 	dt := now.Sub(m.lastUpdate).Seconds()
 	if dt > 0.05 {
 		dp := progress - m.lastProgress
@@ -73,9 +106,22 @@ func (m *MetricsModel) UpdateProgress(progress float64) {
 	}
 }
 
-// UpdateIndicators stores the post-calculation indicators.
+// UpdateThroughput records the estimated number of result bits produced
+// so far, for the rolling and peak throughput.
+func (m *MetricsModel) UpdateThroughput(bits float64) {
+	m.throughput.Add(m.clock.Now(), bits)
+}
+
+// Throughput returns the rolling throughput over the last minute and the
+// peak rolling throughput of the run, in bits per second.
+func (m MetricsModel) Throughput() (rolling, peak float64) {
+	return m.throughput.Rolling(), m.throughput.Peak()
+}
+
+// UpdateIndicators stores the indicators, annotated with the throughput
+// history.
 func (m *MetricsModel) UpdateIndicators(ind *metrics.Indicators) {
-	m.indicators = ind
+	m.indicators = m.throughput.Annotate(ind)
 }
 
 // View renders the metrics panel.
@@ -90,6 +136,19 @@ func (m MetricsModel) View() string {
 		metricLabelStyle.Render("Heap:"), heapStr,
 		pipe,
 		metricLabelStyle.Render("GC:"), gcPauseStr)
+	if m.softRealtime {
+		style := metricValueStyle
+		if m.worstRefresh > config.SoftRealtimeTarget {
+			style = logErrorStyle
+		}
+		topLine += fmt.Sprintf("%s%s %s", pipe, metricLabelStyle.Render("Stutter:"),
+			style.Render(format.FormatExecutionDuration(m.worstRefresh)+" / "+format.FormatExecutionDuration(config.SoftRealtimeTarget)))
+	}
+	// Lost or merged progress updates mean the display cannot keep up
+	if lost := m.progress.Dropped + m.progress.Coalesced; lost > 0 {
+		topLine += fmt.Sprintf("%s%s %s", pipe, metricLabelStyle.Render("Progress:"),
+			metricValueStyle.Render(fmt.Sprintf("%d dropped, %d coalesced", m.progress.Dropped, m.progress.Coalesced)))
+	}
 	rows.WriteString(topLine)
 
 	colWidth := (m.width - 6) / 2
@@ -114,6 +173,10 @@ func (m MetricsModel) View() string {
 			formatMetricCol("Digits/s:", metrics.FormatDigitsPerSecond(m.indicators.DigitsPerSecond), colWidth),
 			formatMetricCol("Parity:", parity, colWidth),
 		)
+		if m.indicators.PeakBitsPerSecond > 0 {
+			leftCol = append(leftCol, formatMetricCol("Last 1m:", metrics.FormatBitsPerSecond(m.indicators.RollingBitsPerSecond), colWidth))
+			rightCol = append(rightCol, formatMetricCol("Peak:", metrics.FormatBitsPerSecond(m.indicators.PeakBitsPerSecond), colWidth))
+		}
 	}
 
 	for i := range leftCol {
@@ -125,18 +188,14 @@ func (m MetricsModel) View() string {
 	return panelStyle.
 		Width(m.width - 2).
 		Height(m.height - 2).
-		Render(rows.String())
+		Render(fitLines(rows.String(), m.width-2))
 }
 
 func formatMetricCol(label, value string, colWidth int) string {
 	cell := fmt.Sprintf(" %s %s",
-		metricLabelStyle.Render(fmt.Sprintf("%-12s", label)),
+		metricLabelStyle.Render(padCell(label, 12)),
 		metricValueStyle.Render(value))
-	// Pad to fixed column width using lipgloss-aware width
-	visible := lipgloss.Width(cell)
-	if visible < colWidth {
-		cell += strings.Repeat(" ", colWidth-visible)
-	}
-	return cell
+	// Fit to the fixed column width, measured in terminal cells
+	return padCell(fitLine(cell, colWidth), colWidth)
 }
 
@@ -1,11 +1,15 @@
 package tui
 
 import (
+	"math"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/agbru/fibcalc/internal/clock"
 	"github.com/agbru/fibcalc/internal/format"
+	"github.com/agbru/fibcalc/internal/metrics"
+	"github.com/agbru/fibcalc/internal/progress"
 )
 
 func TestMetricsModel_UpdateMemStats(t *testing.T) {
@@ -29,6 +33,21 @@ func TestMetricsModel_UpdateMemStats(t *testing.T) {
 	}
 }
 
+func TestMetricsModel_PeakAlloc(t *testing.T) {
+	m := NewMetricsModel()
+
+	for _, alloc := range []uint64{10, 30, 20} {
+		m.UpdateMemStats(MemStatsMsg{Alloc: alloc})
+	}
+
+	if got := m.PeakAlloc(); got != 30 {
+		t.Errorf("expected peak alloc 30, got %d", got)
+	}
+	if m.alloc != 20 {
+		t.Errorf("expected current alloc 20, got %d", m.alloc)
+	}
+}
+
 func TestMetricsModel_UpdateProgress(t *testing.T) {
 	m := NewMetricsModel()
 	// Force the lastUpdate back in time to ensure dt > 0.05
@@ -44,27 +63,22 @@ func TestMetricsModel_UpdateProgress(t *testing.T) {
 }
 
 func TestMetricsModel_UpdateProgress_Smoothing(t *testing.T) {
-	m := NewMetricsModel()
-	m.lastUpdate = time.Now().Add(-1 * time.Second)
+	clk := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	m := newMetricsModel(clk)
 
-	// First update: dp=0.3 over ~1s → speed ≈ 0.3
+	// First update: dp=0.3 over 1s → speed = 0.3
+	clk.Advance(time.Second)
 	m.UpdateProgress(0.3)
-	firstSpeed := m.speed
-
-	if firstSpeed <= 0 {
-		t.Fatal("precondition: first speed should be positive")
+	if math.Abs(m.speed-0.3) > 1e-9 {
+		t.Fatalf("first speed = %f, want 0.3", m.speed)
 	}
 
-	// Second update: dp=0.5 over ~0.5s → instant speed ≈ 1.0
-	// Smoothed: 0.7*0.3 + 0.3*1.0 = 0.51 ≠ 0.3
-	m.lastUpdate = time.Now().Add(-500 * time.Millisecond)
+	// Second update: dp=0.5 over 0.5s → instant speed = 1.0
+	// Smoothed: 0.7*0.3 + 0.3*1.0 = 0.51
+	clk.Advance(500 * time.Millisecond)
 	m.UpdateProgress(0.8)
-
-	if m.speed <= 0 {
-		t.Error("expected positive speed after second update")
-	}
-	if m.speed == firstSpeed {
-		t.Error("expected speed to change after second update with different rate")
+	if math.Abs(m.speed-0.51) > 1e-9 {
+		t.Errorf("smoothed speed = %f, want 0.51", m.speed)
 	}
 }
 
@@ -116,6 +130,62 @@ func TestMetricsModel_View(t *testing.T) {
 	}
 }
 
+func TestMetricsModel_ViewProgressDrops(t *testing.T) {
+	m := NewMetricsModel()
+	m.SetSize(120, 15)
+
+	m.UpdateMemStats(MemStatsMsg{Progress: progress.DropStats{Sent: 100}})
+	if strings.Contains(m.View(), "dropped") {
+		t.Error("expected no progress drops shown while none are lost")
+	}
+
+	m.UpdateMemStats(MemStatsMsg{Progress: progress.DropStats{Sent: 100, Dropped: 3, Coalesced: 2}})
+	if view := m.View(); !strings.Contains(view, "3 dropped, 2 coalesced") {
+		t.Errorf("expected the progress drops in the view, got:\n%s", view)
+	}
+}
+
+func TestMetricsModel_ViewStutter(t *testing.T) {
+	m := NewMetricsModel()
+	m.SetSize(120, 15)
+	m.RecordRefreshDelay(12 * time.Millisecond)
+	if strings.Contains(m.View(), "Stutter") {
+		t.Error("expected no stutter shown without --soft-realtime")
+	}
+
+	m.SetSoftRealtime(true)
+	m.RecordRefreshDelay(3 * time.Millisecond)
+	if view := m.View(); !strings.Contains(view, "Stutter:") || !strings.Contains(view, "12ms / 50ms") {
+		t.Errorf("expected the worst refresh delay against the target, got:\n%s", view)
+	}
+}
+
+func TestMetricsModel_Throughput(t *testing.T) {
+	clk := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	m := newMetricsModel(clk)
+	m.SetSize(80, 15)
+
+	// 2 Mbit/s for 10s, then 1 Mbit/s for a minute
+	m.UpdateThroughput(0)
+	clk.Advance(10 * time.Second)
+	m.UpdateThroughput(20e6)
+	clk.Advance(time.Minute)
+	m.UpdateThroughput(80e6)
+
+	rolling, peak := m.Throughput()
+	if math.Abs(rolling-1e6) > 1 || math.Abs(peak-2e6) > 1 {
+		t.Fatalf("Throughput() = (%v, %v), want (1e6, 2e6)", rolling, peak)
+	}
+
+	m.UpdateIndicators(&metrics.Indicators{BitsPerSecond: 1e6, Live: true})
+	view := m.View()
+	for _, want := range []string{"Last 1m:", "1.00 Mbit/s", "Peak:", "2.00 Mbit/s"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in metrics view:\n%s", want, view)
+		}
+	}
+}
+
 func TestFormatBytes(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -10,12 +10,17 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/agbru/fibcalc/internal/cli"
+	"github.com/agbru/fibcalc/internal/clock"
 	"github.com/agbru/fibcalc/internal/config"
 	apperrors "github.com/agbru/fibcalc/internal/errors"
 	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/fibonacci/memory"
 	"github.com/agbru/fibcalc/internal/metrics"
 	"github.com/agbru/fibcalc/internal/orchestration"
+	"github.com/agbru/fibcalc/internal/progress"
 	"github.com/agbru/fibcalc/internal/sysmon"
+	"github.com/agbru/fibcalc/internal/ui"
 )
 
 // ExecutionState holds the execution-related fields of a TUI session.
@@ -80,6 +85,11 @@ type Model struct {
 	metrics MetricsModel
 	chart   ChartModel
 	footer  FooterModel
+	summary SummaryModel
+	result  ResultViewModel
+	extend  DeadlinePromptModel
+	about   AboutModel
+	idle    IdleModel
 
 	keymap KeyMap
 
@@ -87,29 +97,59 @@ type Model struct {
 	LayoutManager
 
 	parentCtx context.Context
+	deadline  *orchestration.DeadlineContext // nil without an extendable timeout
 	config    config.AppConfig
 	ref       *programRef
+	session   session
 	paused    bool
+	clock     clock.Clock
 }
 
 // NewModel creates a new TUI model.
 func NewModel(parentCtx context.Context, calculators []fibonacci.Calculator, cfg config.AppConfig, version string) Model {
+	return newModel(parentCtx, calculators, cfg, version, clock.Real{})
+}
+
+// newModel creates a TUI model driven by clk: elapsed times, speed, log
+// timestamps, ticks and the calculation timings all read from it.
+func newModel(parentCtx context.Context, calculators []fibonacci.Calculator, cfg config.AppConfig, version string, clk clock.Clock) Model {
 	algoNames := make([]string, len(calculators))
 	for i, c := range calculators {
 		algoNames[i] = c.Name()
 	}
 
 	ctx, cancel := context.WithCancel(parentCtx)
+	deadline, _ := orchestration.DeadlineFrom(parentCtx)
 
-	logs := NewLogsModel(algoNames)
+	logs := newLogsModel(algoNames, clk)
 	logs.AddExecutionConfig(cfg)
 
+	metrics := newMetricsModel(clk)
+	metrics.SetSoftRealtime(cfg.SoftRealtime)
+
+	summary := NewSummaryModelClock(clk)
+	if cfg.SignKey != "" {
+		summary.SetSigning(cfg.SignKey, version)
+	}
+	if cfg.Baseline != "" {
+		baseline, err := LoadReport(cfg.Baseline)
+		if err == nil {
+			summary.SetBaseline(cfg.Baseline, baseline)
+		}
+		logs.AddBaseline(cfg.Baseline, baseline, err)
+	}
+
 	return Model{
-		header:  NewHeaderModel(version),
+		header:  newHeaderModel(version, clk),
 		logs:    logs,
-		metrics: NewMetricsModel(),
+		metrics: metrics,
 		chart:   NewChartModel(),
 		footer:  NewFooterModel(),
+		summary: summary,
+		result:  NewResultViewModel(),
+		extend:  NewDeadlinePromptModel(),
+		about:   NewAboutModel(version, DefaultKeyMap().Help()),
+		idle:    NewIdleModel(cfg.TUIIdle, clk.Now()),
 		keymap:  DefaultKeyMap(),
 		ExecutionState: ExecutionState{
 			ctx:         ctx,
@@ -118,16 +158,26 @@ func NewModel(parentCtx context.Context, calculators []fibonacci.Calculator, cfg
 			exitCode:    apperrors.ExitSuccess,
 		},
 		parentCtx: parentCtx,
+		deadline:  deadline,
 		config:    cfg,
 		ref:       &programRef{},
+		session:   session{statePool: fibonacci.NewStatePool(cfg.SessionPoolBytes())},
+		clock:     clk,
 	}
 }
 
+// session holds what the calculations of a TUI session share across
+// restarts.
+type session struct {
+	logOut    io.Writer            // plain-text log of --log-file, or nil
+	statePool *fibonacci.StatePool // states kept for restarts, or nil
+}
+
 // Init returns the initial commands.
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
-		tickCmd(),
-		startCalculationCmd(m.ref, m.ctx, m.calculators, m.config, m.generation),
+		m.tick(),
+		startCalculationCmd(m.ref, m.ctx, m.calculators, m.config, m.generation, m.clock, m.session),
 		watchContextCmd(m.ctx, m.generation),
 	)
 }
@@ -148,11 +198,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if !m.paused {
 			m.logs.AddProgressEntry(msg)
 			m.chart.AddDataPoint(msg.Value, msg.AverageProgress, msg.ETA)
+			m.chart.SetETARange(msg.ETALow, msg.ETAHigh)
 			m.metrics.UpdateProgress(msg.AverageProgress)
+			m.metrics.UpdateThroughput(metrics.EstimatedBits(m.config.N, msg.AverageProgress))
 			// Refresh live indicators from progress data
-			elapsed := time.Since(m.header.startTime)
+			elapsed := m.clock.Since(m.header.startTime)
 			m.metrics.UpdateIndicators(metrics.ComputeLive(m.config.N, msg.AverageProgress, elapsed))
 		}
+		// Offer to extend the timeout when the run will not make it
+		if m.deadline != nil && !m.done && m.extend.Check(msg.ETA, m.deadline.Remaining()) {
+			m.idle.Wake()
+		}
 		return m, nil
 
 	case ProgressDoneMsg:
@@ -164,6 +220,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case FinalResultMsg:
 		m.logs.AddFinalResult(msg)
+		m.summary.SetResult(msg, m.config)
+		m.result.SetValue(msg.Result.Result, msg.N)
 		// Compute indicators asynchronously to avoid blocking the UI
 		if msg.Result.Result != nil {
 			return m, computeIndicatorsCmd(msg)
@@ -172,31 +230,49 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case IndicatorsMsg:
 		m.metrics.UpdateIndicators(msg.Indicators)
+		m.summary.SetIndicators(msg.Indicators)
+		return m, nil
+
+	case SummarySavedMsg:
+		m.summary.HandleSaved(msg)
+		return m, nil
+
+	case ResultTextMsg:
+		m.result.HandleText(msg)
+		return m, nil
+
+	case AboutMsg:
+		m.about.HandleReport(msg)
 		return m, nil
 
 	case ErrorMsg:
+		m.idle.Wake()
 		m.logs.AddError(msg)
 		m.footer.SetError(true)
+		m.extend.Hide()
 		m.done = true
 		m.header.SetDone()
 		m.footer.SetDone(true)
 		return m, nil
 
 	case TickMsg:
-		if m.done {
+		if m.done || msg.Generation != m.generation {
 			return m, nil
 		}
+		m.metrics.RecordRefreshDelay(m.clock.Since(msg.At))
 		if !m.paused {
-			return m, tea.Batch(sampleMemStatsCmd(), sampleSysStatsCmd(), tickCmd())
+			if msg.Mem != nil {
+				m.metrics.UpdateMemStats(*msg.Mem)
+			}
+			if msg.Sys != nil {
+				m.chart.UpdateSysStats(msg.Sys.CPUPercent, msg.Sys.MemPercent)
+			}
 		}
-		return m, tickCmd()
-
-	case MemStatsMsg:
-		m.metrics.UpdateMemStats(msg)
-		return m, nil
+		m.idle.Check(m.clock.Now())
+		return m, m.tick()
 
-	case SysStatsMsg:
-		m.chart.UpdateSysStats(msg.CPUPercent, msg.MemPercent)
+	case MemoryPressureMsg:
+		m.logs.AddMemoryPressure(msg.Pressure)
 		return m, nil
 
 	case CalculationCompleteMsg:
@@ -205,9 +281,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		m.done = true
 		m.exitCode = msg.ExitCode
+		m.idle.Wake()
+		m.extend.Hide()
 		m.header.SetDone()
-		m.chart.SetDone(time.Since(m.header.startTime))
+		m.chart.SetDone(m.clock.Since(m.header.startTime))
 		m.footer.SetDone(true)
+		if msg.ExitCode == apperrors.ExitSuccess {
+			m.showSummary()
+		}
 		return m, nil
 
 	case ContextCancelledMsg:
@@ -224,6 +305,28 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// On the idle screen, a key only brings the dashboard back
+	if m.idle.Touch(m.clock.Now()) && msg.Type != tea.KeyCtrlC {
+		return m, nil
+	}
+	// The search prompt captures every key except ctrl+c
+	if m.logs.Searching() && msg.Type != tea.KeyCtrlC {
+		m.logs.HandleSearchKey(msg)
+		return m, nil
+	}
+	if m.extend.Visible() {
+		return m.handleDeadlineKey(msg)
+	}
+	if m.about.Visible() {
+		return m.handleAboutKey(msg)
+	}
+	if m.result.Visible() {
+		return m.handleResultKey(msg)
+	}
+	if m.summary.Visible() {
+		return m.handleSummaryKey(msg)
+	}
+
 	switch {
 	case key.Matches(msg, m.keymap.Quit):
 		if m.cancel != nil {
@@ -251,9 +354,13 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Reset all UI components
 		m.header.Reset()
 		m.logs.Reset()
+		m.logs.AddStatePool(m.session.statePool.Stats())
 		m.chart.Reset()
-		m.metrics = NewMetricsModel()
+		m.metrics = newMetricsModel(m.clock)
 		m.metrics.SetSize(m.metricsWidth(), m.metricsHeight())
+		m.summary.Reset()
+		m.result.Reset()
+		m.extend.Reset()
 		m.footer.SetDone(false)
 		m.footer.SetError(false)
 		m.footer.SetPaused(false)
@@ -263,11 +370,57 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 		// Restart calculation and watchers
 		return m, tea.Batch(
-			tickCmd(),
-			startCalculationCmd(m.ref, m.ctx, m.calculators, m.config, m.generation),
+			m.tick(),
+			startCalculationCmd(m.ref, m.ctx, m.calculators, m.config, m.generation, m.clock, m.session),
 			watchContextCmd(m.ctx, m.generation),
 		)
 
+	case key.Matches(msg, m.keymap.FullValue):
+		// The value is only complete once the run is done
+		if m.done && m.result.Available() {
+			return m, m.result.Open(resultFull)
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keymap.Verbosity):
+		m.footer.SetFullLogs(m.logs.ToggleVerbosity())
+		return m, nil
+
+	case key.Matches(msg, m.keymap.HexValue):
+		return m, m.result.Open(resultHex)
+
+	case key.Matches(msg, m.keymap.DigitWindow):
+		return m, m.result.Open(resultWindow)
+
+	case key.Matches(msg, m.keymap.Follow):
+		m.footer.SetFollow(m.logs.ToggleFollow())
+		return m, nil
+
+	case key.Matches(msg, m.keymap.Search):
+		m.logs.StartSearch()
+		return m, nil
+
+	case key.Matches(msg, m.keymap.NextMatch):
+		m.logs.NextMatch()
+		m.footer.SetFollow(m.logs.Following())
+		return m, nil
+
+	case key.Matches(msg, m.keymap.PrevMatch):
+		m.logs.PrevMatch()
+		m.footer.SetFollow(m.logs.Following())
+		return m, nil
+
+	case key.Matches(msg, m.keymap.ClearSearch):
+		m.logs.ClearSearch()
+		return m, nil
+
+	case key.Matches(msg, m.keymap.Summary):
+		m.showSummary()
+		return m, nil
+
+	case key.Matches(msg, m.keymap.About):
+		return m, m.about.Open()
+
 	case key.Matches(msg, m.keymap.Up), key.Matches(msg, m.keymap.Down),
 		key.Matches(msg, m.keymap.PageUp), key.Matches(msg, m.keymap.PageDown):
 		m.logs.Update(msg)
@@ -277,11 +430,131 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// showSummary opens the summary overlay with the peak heap and throughput
+// sampled during the run.
+func (m *Model) showSummary() {
+	rolling, peak := m.metrics.Throughput()
+	m.summary.SetThroughput(rolling, peak)
+	m.summary.Show(m.metrics.PeakAlloc())
+}
+
+// handleSummaryKey handles keys while the summary overlay is shown. Quit
+// and restart keep working; other dashboard keys are ignored.
+func (m Model) handleSummaryKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keymap.SaveReport):
+		return m, m.summary.saveReportCmd()
+
+	case key.Matches(msg, m.keymap.Export):
+		return m, m.summary.exportResultCmd()
+
+	case key.Matches(msg, m.keymap.Compare):
+		m.summary.ToggleComparison()
+		return m, nil
+
+	case key.Matches(msg, m.keymap.HexValue):
+		m.summary.Hide()
+		return m, m.result.Open(resultHex)
+
+	case key.Matches(msg, m.keymap.FullValue):
+		m.summary.Hide()
+		return m, m.result.Open(resultFull)
+
+	case key.Matches(msg, m.keymap.Close), key.Matches(msg, m.keymap.Summary):
+		m.summary.Hide()
+		return m, nil
+
+	case key.Matches(msg, m.keymap.Quit), key.Matches(msg, m.keymap.Reset):
+		m.summary.Hide()
+		return m.handleKey(msg)
+	}
+	return m, nil
+}
+
+// handleDeadlineKey handles keys while the timeout prompt is shown: y
+// extends the deadline, n lets it fire. Quit and restart keep working.
+func (m Model) handleDeadlineKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keymap.Extend):
+		m.extend.Hide()
+		if m.deadline.Extend(m.extend.Extension()) {
+			m.logs.AddDeadlineExtended(m.extend.Extension(), m.deadline.Remaining())
+		}
+
+	case key.Matches(msg, m.keymap.Decline):
+		m.extend.Hide()
+
+	case key.Matches(msg, m.keymap.Quit), key.Matches(msg, m.keymap.Reset):
+		m.extend.Hide()
+		return m.handleKey(msg)
+	}
+	return m, nil
+}
+
+// handleAboutKey handles keys while the about screen is shown: a, esc and
+// enter close it. Quit and restart keep working.
+func (m Model) handleAboutKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keymap.Close), key.Matches(msg, m.keymap.About):
+		m.about.Hide()
+
+	case key.Matches(msg, m.keymap.Quit), key.Matches(msg, m.keymap.Reset):
+		m.about.Hide()
+		return m.handleKey(msg)
+	}
+	return m, nil
+}
+
+// handleResultKey handles keys while the result viewer is shown: x, d and
+// z switch the display, the navigation keys move through the value. Quit
+// and restart keep working.
+func (m Model) handleResultKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keymap.HexValue):
+		return m, m.result.Open(resultHex)
+
+	case key.Matches(msg, m.keymap.FullValue):
+		return m, m.result.Open(resultFull)
+
+	case key.Matches(msg, m.keymap.DigitWindow):
+		return m, m.result.Open(resultWindow)
+
+	case key.Matches(msg, m.keymap.Up), key.Matches(msg, m.keymap.Left):
+		m.result.Scroll(-1)
+
+	case key.Matches(msg, m.keymap.Down), key.Matches(msg, m.keymap.Right):
+		m.result.Scroll(1)
+
+	case key.Matches(msg, m.keymap.PageUp):
+		m.result.Scroll(-m.result.PageSize())
+
+	case key.Matches(msg, m.keymap.PageDown):
+		m.result.Scroll(m.result.PageSize())
+
+	case key.Matches(msg, m.keymap.Home):
+		m.result.ScrollHome()
+
+	case key.Matches(msg, m.keymap.End):
+		m.result.ScrollEnd()
+
+	case key.Matches(msg, m.keymap.Close):
+		m.result.Hide()
+
+	case key.Matches(msg, m.keymap.Quit), key.Matches(msg, m.keymap.Reset):
+		m.result.Hide()
+		return m.handleKey(msg)
+	}
+	return m, nil
+}
+
 // View renders the entire dashboard.
 func (m Model) View() string {
 	if m.width == 0 || m.height == 0 {
 		return "Initializing..."
 	}
+	if m.idle.Active() {
+		return ui.SafeText(m.idle.View(m.chart.averageProgress, idleETA(m.chart)))
+	}
 
 	header := m.header.View()
 	footer := m.footer.View()
@@ -297,9 +570,22 @@ func (m Model) View() string {
 
 	// Main body: logs on left, right column on right
 	body := lipgloss.JoinHorizontal(lipgloss.Top, logs, rightCol)
+	if m.summary.Visible() {
+		body = m.summary.overlay(m.width, lipgloss.Height(body))
+	}
+	if m.result.Visible() {
+		body = m.result.overlay(m.width, lipgloss.Height(body))
+	}
+	if m.about.Visible() {
+		body = m.about.overlay(m.width, lipgloss.Height(body))
+	}
+	if m.extend.Visible() {
+		body = m.extend.overlay(m.width, lipgloss.Height(body))
+	}
 
-	// Full layout: header + body + footer
-	return lipgloss.JoinVertical(lipgloss.Left, header, body, footer)
+	// Full layout: header + body + footer, transliterated to ASCII on
+	// consoles that cannot display block and box-drawing characters.
+	return ui.SafeText(lipgloss.JoinVertical(lipgloss.Left, header, body, footer))
 }
 
 // Layout constants for the TUI dashboard.
@@ -314,6 +600,11 @@ const (
 func (m *Model) layoutPanels() {
 	m.header.SetWidth(m.width)
 	m.footer.SetWidth(m.width)
+	m.summary.SetWidth(m.width)
+	m.result.SetSize(m.width, m.bodyHeight())
+	m.extend.SetWidth(m.width)
+	m.about.SetWidth(m.width)
+	m.idle.SetSize(m.width, m.height)
 	m.logs.SetSize(m.logsWidth(), m.bodyHeight())
 	m.metrics.SetSize(m.rightWidth(), m.metricsHeight())
 	m.chart.SetSize(m.rightWidth(), m.chartHeight())
@@ -321,11 +612,14 @@ func (m *Model) layoutPanels() {
 
 // Run is the public entry point for the TUI mode.
 // It creates the bubbletea program, runs it, and returns the exit code.
-func Run(ctx context.Context, calculators []fibonacci.Calculator, cfg config.AppConfig, version string) int {
-	// Rebuild styles from the current ui theme (set by app.Run via InitTheme).
-	initTUIStyles()
+// If log is not nil, the output the CLI would have printed is also written
+// to it, as plain text.
+func Run(ctx context.Context, calculators []fibonacci.Calculator, cfg config.AppConfig, rt config.RuntimeConfig, version string, log io.Writer) int {
+	// Rebuild styles from the theme of this run.
+	initTUIStyles(rt.TUITheme())
 
 	model := NewModel(ctx, calculators, cfg, version)
+	model.session.logOut = newPlainLog(log)
 	defer model.cancel()
 
 	p := tea.NewProgram(model, tea.WithAltScreen())
@@ -345,17 +639,57 @@ func Run(ctx context.Context, calculators []fibonacci.Calculator, cfg config.App
 }
 
 // startCalculationCmd returns a tea.Cmd that launches the orchestration.
-func startCalculationCmd(ref *programRef, ctx context.Context, calculators []fibonacci.Calculator, cfg config.AppConfig, gen uint64) tea.Cmd {
+// A nil clk means the wall clock. If sess has a log, the configuration,
+// progress and results are also written to it as the CLI prints them.
+func startCalculationCmd(ref *programRef, ctx context.Context, calculators []fibonacci.Calculator, cfg config.AppConfig, gen uint64, clk clock.Clock, sess session) tea.Cmd {
 	return func() tea.Msg {
-		progressReporter := &TUIProgressReporter{ref: ref}
+		logOut := sess.logOut
+		var progressReporter orchestration.ProgressReporter = &TUIProgressReporter{ref: ref, clock: clk}
 		presenter := &TUIResultPresenter{ref: ref}
+		progressOut := io.Discard
 
 		opts := fibonacci.Options{
-			ParallelThreshold: cfg.Threshold,
-			FFTThreshold:      cfg.FFTThreshold,
-			StrassenThreshold: cfg.StrassenThreshold,
+			ParallelThreshold:   cfg.Threshold,
+			FFTThreshold:        cfg.FFTThreshold,
+			StrassenThreshold:   cfg.StrassenThreshold,
+			GCMode:              cfg.GCControl,
+			SoftRealtime:        cfg.SoftRealtime,
+			DisableTables:       cfg.NoTable,
+			SpillThresholdBytes: cfg.SpillThresholdBytes(),
+			SpillDir:            cfg.SpillDir,
+			StatePool:           sess.statePool,
+		}
+		mode, err := orchestration.ParseCompareMode(cfg.CompareMode)
+		if err != nil {
+			mode = orchestration.CompareParallel
+		}
+		execOpts := orchestration.ExecutionOptions{
+			Mode:            mode,
+			Failure:         fibonacci.NewFailureInjection(cfg.FailMode, cfg.FailAfter),
+			Warmup:          cfg.Warmup,
+			Clock:           clk,
+			ProgressPolicy:  progress.Policy(cfg.ProgressPolicy),
+			ProgressTimeout: cfg.ProgressTimeout,
+		}
+		if cfg.AdaptiveParallelism {
+			execOpts.Contention = sysmon.NewContentionMonitor()
 		}
-		results := orchestration.ExecuteCalculations(ctx, calculators, cfg.N, opts, progressReporter, io.Discard)
+		if cfg.MemoryPressure > 0 {
+			execOpts.MemoryPressure = memory.NewPressureMonitor(cfg.MemoryPressure)
+			execOpts.MemoryPressure.OnPressure(func(p memory.Pressure) {
+				ref.Send(MemoryPressureMsg{Pressure: p})
+				if logOut != nil {
+					cli.PrintMemoryPressure(p, logOut)
+				}
+			})
+		}
+		if logOut != nil {
+			cli.PrintExecutionConfig(cfg, logOut)
+			cli.PrintExecutionMode(calculators, mode, logOut)
+			progressReporter = teeProgressReporter{first: progressReporter, second: cli.CLIProgressReporter{RefreshRate: cfg.ProgressRefresh}}
+			progressOut = logOut
+		}
+		results := orchestration.ExecuteCalculationsWithOptions(ctx, calculators, cfg.N, opts, execOpts, progressReporter, progressOut)
 		presOpts := orchestration.PresentationOptions{
 			N:         cfg.N,
 			Verbose:   cfg.Verbose,
@@ -363,41 +697,63 @@ func startCalculationCmd(ref *programRef, ctx context.Context, calculators []fib
 			ShowValue: cfg.ShowValue,
 		}
 		exitCode := orchestration.AnalyzeComparisonResults(results, presOpts, presenter, presenter, io.Discard)
+		if logOut != nil {
+			logPresenter := cli.CLIResultPresenter{Truncation: cli.TruncationFromConfig(cfg)}
+			orchestration.AnalyzeComparisonResults(results, presOpts, logPresenter, logPresenter, logOut)
+		}
 
 		return CalculationCompleteMsg{ExitCode: exitCode, Generation: gen}
 	}
 }
 
-// tickCmd returns a command that sends a TickMsg after 500ms.
-func tickCmd() tea.Cmd {
-	return tea.Tick(500*time.Millisecond, func(t time.Time) tea.Msg {
-		return TickMsg(t)
-	})
+// tick schedules the next TickMsg of the current calculation, one refresh
+// period from now (at least idleRefresh on the idle screen), sampling the
+// metrics unless the dashboard is paused.
+func (m Model) tick() tea.Cmd {
+	interval := m.config.TUIRefresh
+	if interval <= 0 {
+		interval = config.DefaultTUIRefresh
+	}
+	if m.idle.Active() {
+		interval = max(interval, idleRefresh)
+	}
+	return tickCmd(m.clock, interval, m.generation, !m.paused)
 }
 
-// sampleMemStatsCmd reads runtime memory stats and returns a MemStatsMsg.
-func sampleMemStatsCmd() tea.Cmd {
+// tickCmd returns a command that sends a TickMsg once interval has elapsed
+// on clk, with the memory and system metrics sampled then if sample is set.
+// It is the only timer of the dashboard: each TickMsg schedules the next.
+func tickCmd(clk clock.Clock, interval time.Duration, gen uint64, sample bool) tea.Cmd {
 	return func() tea.Msg {
-		var ms runtime.MemStats
-		runtime.ReadMemStats(&ms)
-		return MemStatsMsg{
-			Alloc:        ms.Alloc,
-			HeapSys:      ms.HeapSys,
-			NumGC:        ms.NumGC,
-			PauseTotalNs: ms.PauseTotalNs,
-			NumGoroutine: runtime.NumGoroutine(),
+		msg := TickMsg{At: <-clk.After(interval), Generation: gen}
+		if sample {
+			mem, sys := sampleMemStats(), sampleSysStats()
+			msg.Mem, msg.Sys = &mem, &sys
 		}
+		return msg
 	}
 }
 
-// sampleSysStatsCmd reads system-wide CPU and memory stats and returns a SysStatsMsg.
-func sampleSysStatsCmd() tea.Cmd {
-	return func() tea.Msg {
-		s := sysmon.Sample()
-		return SysStatsMsg{
-			CPUPercent: s.CPUPercent,
-			MemPercent: s.MemPercent,
-		}
+// sampleMemStats reads runtime memory stats.
+func sampleMemStats() MemStatsMsg {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return MemStatsMsg{
+		Alloc:        ms.Alloc,
+		HeapSys:      ms.HeapSys,
+		NumGC:        ms.NumGC,
+		PauseTotalNs: ms.PauseTotalNs,
+		NumGoroutine: runtime.NumGoroutine(),
+		Progress:     progress.TotalStats(),
+	}
+}
+
+// sampleSysStats reads system-wide CPU and memory stats.
+func sampleSysStats() SysStatsMsg {
+	s := sysmon.Sample()
+	return SysStatsMsg{
+		CPUPercent: s.CPUPercent,
+		MemPercent: s.MemPercent,
 	}
 }
 
@@ -2,33 +2,23 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"math/big"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/agbru/fibcalc/internal/clock"
 	"github.com/agbru/fibcalc/internal/config"
 	apperrors "github.com/agbru/fibcalc/internal/errors"
 	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/fibtest"
 	"github.com/agbru/fibcalc/internal/orchestration"
 )
 
-// mockCalculator implements fibonacci.Calculator for testing.
-type mockCalculator struct {
-	name string
-}
-
-func (m mockCalculator) Calculate(_ context.Context, _ chan<- fibonacci.ProgressUpdate, _ int, _ uint64, _ fibonacci.Options) (*big.Int, error) {
-	return big.NewInt(0), nil
-}
-
-func (m mockCalculator) Name() string { return m.name }
-
-// Verify interface compliance at compile time.
-var _ fibonacci.Calculator = mockCalculator{}
-
 func newTestModel(t *testing.T) Model {
 	t.Helper()
 	ctx := context.Background()
@@ -45,6 +35,16 @@ func newTestModelWithSize(t *testing.T, w, h int) Model {
 	return updated.(Model)
 }
 
+// TestModelRecordsRefreshDelay verifies that a tick handled late counts as
+// a stutter of the dashboard.
+func TestModelRecordsRefreshDelay(t *testing.T) {
+	m := newTestModel(t)
+	updated, _ := m.Update(TickMsg{At: time.Now().Add(-80 * time.Millisecond), Generation: m.generation})
+	if got := updated.(Model).metrics.worstRefresh; got < 80*time.Millisecond {
+		t.Errorf("worst refresh delay = %v, want at least 80ms", got)
+	}
+}
+
 func TestNewModel(t *testing.T) {
 	model := newTestModel(t)
 
@@ -61,8 +61,8 @@ func TestNewModel(t *testing.T) {
 
 func TestNewModel_WithCalculators(t *testing.T) {
 	calcs := []fibonacci.Calculator{
-		mockCalculator{name: "Fast Doubling"},
-		mockCalculator{name: "Matrix"},
+		fibtest.Fixed("Fast Doubling", nil),
+		fibtest.Fixed("Matrix", nil),
 	}
 	cfg := config.AppConfig{N: 1000, Timeout: time.Minute}
 	model := NewModel(context.Background(), calcs, cfg, "v1.0.0")
@@ -363,45 +363,60 @@ func TestModel_Update_CalculationComplete_NonZeroExitCode(t *testing.T) {
 	}
 }
 
-func TestModel_Update_MemStatsMsg(t *testing.T) {
+func TestModel_Update_TickMsg_MemStats(t *testing.T) {
 	m := newTestModel(t)
 
-	msg := MemStatsMsg{
+	mem := MemStatsMsg{
 		Alloc:        1024 * 1024 * 10,
 		NumGC:        5,
 		NumGoroutine: 12,
 	}
-	updated, _ := m.Update(msg)
+	updated, _ := m.Update(TickMsg{At: time.Now(), Mem: &mem})
 	result := updated.(Model)
 
-	if result.metrics.alloc != msg.Alloc {
-		t.Errorf("expected alloc %d, got %d", msg.Alloc, result.metrics.alloc)
+	if result.metrics.alloc != mem.Alloc {
+		t.Errorf("expected alloc %d, got %d", mem.Alloc, result.metrics.alloc)
 	}
 }
 
 func TestModel_Update_TickMsg_NotPaused(t *testing.T) {
 	m := newTestModel(t)
 
-	updated, cmd := m.Update(TickMsg(time.Now()))
+	updated, cmd := m.Update(TickMsg{At: time.Now()})
 	_ = updated
 
-	// When not paused, should return a batch of sampleMemStatsCmd + tickCmd
+	// When not paused, should schedule the next tick
 	if cmd == nil {
-		t.Error("expected commands from tick when not paused")
+		t.Error("expected the next tick when not paused")
 	}
 }
 
 func TestModel_Update_TickMsg_Paused(t *testing.T) {
-	m := newTestModel(t)
+	m := newTestModelWithSize(t, 80, 24)
 	m.paused = true
 
-	updated, cmd := m.Update(TickMsg(time.Now()))
-	_ = updated
+	sys := SysStatsMsg{CPUPercent: 25.5, MemPercent: 60.0}
+	updated, cmd := m.Update(TickMsg{At: time.Now(), Sys: &sys})
+	result := updated.(Model)
 
-	// When paused, should return only tickCmd (no mem stats sampling)
+	// When paused, should keep ticking without recording samples
 	if cmd == nil {
 		t.Error("expected tick command even when paused")
 	}
+	if result.chart.cpuHistory.Len() != 0 {
+		t.Errorf("expected no cpu sample while paused, got %d", result.chart.cpuHistory.Len())
+	}
+}
+
+func TestModel_Update_TickMsg_StaleGeneration(t *testing.T) {
+	m := newTestModel(t)
+	m.generation = 2
+
+	// A tick of the calculation before a restart ends its scheduler
+	_, cmd := m.Update(TickMsg{At: time.Now(), Generation: 1})
+	if cmd != nil {
+		t.Error("expected no command from a tick of an earlier calculation")
+	}
 }
 
 func TestModel_HandleKey_Quit_Q(t *testing.T) {
@@ -447,6 +462,72 @@ func TestModel_HandleKey_ScrollKeys(t *testing.T) {
 	}
 }
 
+func TestModel_HandleKey_ToggleVerbosity(t *testing.T) {
+	m := newTestModelWithSize(t, 120, 40)
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'v'}})
+	m = updated.(Model)
+	if cmd != nil {
+		t.Error("expected no command from verbosity toggle")
+	}
+	if !m.logs.FullVerbosity() {
+		t.Error("expected full log verbosity after pressing 'v'")
+	}
+	if !strings.Contains(m.footer.View(), "Logs: full") {
+		t.Error("expected footer to show full logging mode")
+	}
+}
+
+func TestModel_HandleKey_ToggleFollow(t *testing.T) {
+	m := newTestModelWithSize(t, 120, 40)
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	m = updated.(Model)
+	if m.logs.Following() {
+		t.Error("expected follow to be off after pressing 'f'")
+	}
+	if !strings.Contains(m.footer.View(), "Follow: off") {
+		t.Error("expected footer to show follow mode off")
+	}
+}
+
+func TestModel_HandleKey_Search(t *testing.T) {
+	m := newTestModelWithSize(t, 120, 40)
+	m.logs.AddError(ErrorMsg{Err: errors.New("overflow detected")})
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	m = updated.(Model)
+	if !m.logs.Searching() {
+		t.Fatal("expected '/' to open the search prompt")
+	}
+
+	// Keys bound to actions are typed into the prompt while searching
+	for _, r := range "q overflow" {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updated.(Model)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	m = updated.(Model)
+	if m.paused {
+		t.Error("keys typed into the search prompt should not trigger actions")
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.logs.Searching() {
+		t.Error("expected Enter to close the search prompt")
+	}
+	if m.logs.search.query != "q overflo" {
+		t.Errorf("query = %q, want %q", m.logs.search.query, "q overflo")
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.logs.search.query != "" {
+		t.Error("expected Esc to clear the search")
+	}
+}
+
 func TestModel_HandleKey_Unknown(t *testing.T) {
 	m := newTestModel(t)
 
@@ -529,14 +610,9 @@ func TestModel_Init_ReturnsCommands(t *testing.T) {
 	}
 }
 
-func TestSampleMemStatsCmd_ReturnsMemStatsMsg(t *testing.T) {
-	cmd := sampleMemStatsCmd()
-	if cmd == nil {
-		t.Fatal("expected non-nil command from sampleMemStatsCmd")
-	}
-	msg := cmd()
-	if _, ok := msg.(MemStatsMsg); !ok {
-		t.Errorf("expected MemStatsMsg, got %T", msg)
+func TestSampleMemStats(t *testing.T) {
+	if msg := sampleMemStats(); msg.HeapSys == 0 || msg.NumGoroutine == 0 {
+		t.Errorf("expected runtime memory stats, got %+v", msg)
 	}
 }
 
@@ -658,19 +734,87 @@ func TestModel_metricsHeight_SmallTerminal(t *testing.T) {
 }
 
 func TestTickCmd_ReturnsCmd(t *testing.T) {
-	cmd := tickCmd()
+	cmd := tickCmd(clock.Real{}, config.DefaultTUIRefresh, 0, true)
 	if cmd == nil {
 		t.Error("expected non-nil command from tickCmd")
 	}
 }
 
+func TestTickCmd_FakeClock(t *testing.T) {
+	const interval = 250 * time.Millisecond
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clk := clock.NewFake(start)
+	msgs := make(chan tea.Msg, 1)
+	go func() { msgs <- tickCmd(clk, interval, 3, true)() }()
+
+	for clk.Pending() == 0 {
+		runtime.Gosched()
+	}
+	clk.Advance(interval - time.Millisecond)
+	select {
+	case msg := <-msgs:
+		t.Fatalf("tick delivered early: %v", msg)
+	default:
+	}
+
+	clk.Advance(time.Millisecond)
+	msg := <-msgs
+	tick, ok := msg.(TickMsg)
+	if !ok {
+		t.Fatalf("expected TickMsg, got %T", msg)
+	}
+	if want := start.Add(interval); !tick.At.Equal(want) {
+		t.Errorf("tick at %v, want %v", tick.At, want)
+	}
+	// One wakeup carries both samples
+	if tick.Generation != 3 || tick.Mem == nil || tick.Sys == nil {
+		t.Errorf("tick = %+v, want generation 3 with both samples", tick)
+	}
+}
+
+func TestTickCmd_Paused(t *testing.T) {
+	clk := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	msgs := make(chan tea.Msg, 1)
+	go func() { msgs <- tickCmd(clk, time.Second, 0, false)() }()
+
+	for clk.Pending() == 0 {
+		runtime.Gosched()
+	}
+	clk.Advance(time.Second)
+	if tick := (<-msgs).(TickMsg); tick.Mem != nil || tick.Sys != nil {
+		t.Errorf("tick = %+v, want no samples while paused", tick)
+	}
+}
+
+func TestModel_Tick_UsesConfiguredRefresh(t *testing.T) {
+	clk := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	cfg := config.AppConfig{N: 1000, Timeout: time.Minute, TUIRefresh: 2 * time.Second}
+	m := newModel(context.Background(), nil, cfg, "v1.0.0", clk)
+	msgs := make(chan tea.Msg, 1)
+	go func() { msgs <- m.tick()() }()
+
+	for clk.Pending() == 0 {
+		runtime.Gosched()
+	}
+	clk.Advance(config.DefaultTUIRefresh)
+	select {
+	case msg := <-msgs:
+		t.Fatalf("tick delivered at the default period: %v", msg)
+	default:
+	}
+	clk.Advance(2*time.Second - config.DefaultTUIRefresh)
+	if _, ok := (<-msgs).(TickMsg); !ok {
+		t.Fatal("expected a TickMsg after the configured period")
+	}
+}
+
 func TestStartCalculationCmd_ReturnsCompleteMsg(t *testing.T) {
 	ref := &programRef{}
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	calcs := []fibonacci.Calculator{mockCalculator{name: "Fast"}}
+	calcs := []fibonacci.Calculator{fibtest.Fixed("Fast", nil)}
 	cfg := config.AppConfig{N: 10, Timeout: 10 * time.Second}
-	cmd := startCalculationCmd(ref, ctx, calcs, cfg, 0)
+	cmd := startCalculationCmd(ref, ctx, calcs, cfg, 0, nil, session{})
 	if cmd == nil {
 		t.Fatal("expected non-nil command from startCalculationCmd")
 	}
@@ -681,16 +825,13 @@ func TestStartCalculationCmd_ReturnsCompleteMsg(t *testing.T) {
 	}
 }
 
-func TestModel_Update_SysStatsMsg(t *testing.T) {
+func TestModel_Update_TickMsg_SysStats(t *testing.T) {
 	m := newTestModelWithSize(t, 80, 24)
 
-	msg := SysStatsMsg{CPUPercent: 25.5, MemPercent: 60.0}
-	updated, cmd := m.Update(msg)
+	sys := SysStatsMsg{CPUPercent: 25.5, MemPercent: 60.0}
+	updated, _ := m.Update(TickMsg{At: time.Now(), Sys: &sys})
 	result := updated.(Model)
 
-	if cmd != nil {
-		t.Error("expected no command from SysStatsMsg")
-	}
 	if result.chart.cpuHistory.Len() != 1 {
 		t.Errorf("expected 1 cpu sample, got %d", result.chart.cpuHistory.Len())
 	}
@@ -702,14 +843,9 @@ func TestModel_Update_SysStatsMsg(t *testing.T) {
 	}
 }
 
-func TestSampleSysStatsCmd_ReturnsSysStatsMsg(t *testing.T) {
-	cmd := sampleSysStatsCmd()
-	if cmd == nil {
-		t.Fatal("expected non-nil command from sampleSysStatsCmd")
-	}
-	msg := cmd()
-	if _, ok := msg.(SysStatsMsg); !ok {
-		t.Errorf("expected SysStatsMsg, got %T", msg)
+func TestSampleSysStats(t *testing.T) {
+	if msg := sampleSysStats(); msg.CPUPercent < 0 || msg.CPUPercent > 100 || msg.MemPercent < 0 || msg.MemPercent > 100 {
+		t.Errorf("expected percentages, got %+v", msg)
 	}
 }
 
@@ -0,0 +1,192 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/audit"
+	"github.com/agbru/fibcalc/internal/bigfft"
+	"github.com/agbru/fibcalc/internal/config"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/fsguard"
+	"github.com/agbru/fibcalc/internal/provenance"
+)
+
+// exactDigitsMaxBits is the result size up to which the digit count is
+// taken from the decimal string rather than the closed form (see
+// fibonacci.DecimalDigits).
+const exactDigitsMaxBits = 1 << 16
+
+// RunReport summarizes a completed run. It is shown on the summary screen
+// and saved from there as JSON, so runs can be compared later.
+type RunReport struct {
+	N         uint64        `json:"n"`
+	Algorithm string        `json:"algorithm"`
+	Duration  time.Duration `json:"duration_ns"`
+	Bits      int           `json:"bits"`
+	Digits    uint64        `json:"digits"`
+	// ResultSHA256 is the digest of the result (see audit.HashResult), to
+	// compare results across machines without transferring them.
+	ResultSHA256 string `json:"result_sha256,omitempty"`
+	// BitsPerSecond is the throughput in bits of result per second.
+	BitsPerSecond float64 `json:"bits_per_second"`
+	// RollingBitsPerSecond is the throughput over the last minute of the
+	// run, and PeakBitsPerSecond the highest such rolling throughput. Both
+	// are estimated from progress, and 0 for runs too short to measure.
+	RollingBitsPerSecond float64 `json:"rolling_bits_per_second,omitempty"`
+	PeakBitsPerSecond    float64 `json:"peak_bits_per_second,omitempty"`
+	// GoldenRatioDeviation is the % deviation of the result's bit length
+	// from the theoretical n·log₂(φ).
+	GoldenRatioDeviation float64 `json:"golden_ratio_deviation_pct"`
+	// CacheHitRate is the FFT transform cache hit rate (0..1), or nil if
+	// the calculation never consulted the cache.
+	CacheHitRate *float64 `json:"cache_hit_rate,omitempty"`
+	// PeakHeapBytes is the highest heap allocation sampled during the run.
+	PeakHeapBytes uint64 `json:"peak_heap_bytes"`
+	// Spill is what --spill-threshold placed in temporary files, or nil if
+	// nothing was spilled.
+	Spill             *bigfft.SpillStats `json:"spill,omitempty"`
+	ParallelThreshold int                `json:"parallel_threshold"`
+	FFTThreshold      int                `json:"fft_threshold"`
+	StrassenThreshold int                `json:"strassen_threshold"`
+	CompletedAt       time.Time          `json:"completed_at"`
+	// Provenance is the signature of the result when --sign-key is set.
+	Provenance *provenance.Signature `json:"provenance,omitempty"`
+}
+
+// newRunReport builds the report of a final result, completed at the given
+// time. Throughput, the golden ratio deviation and the peak heap are filled
+// in later, as they arrive.
+func newRunReport(msg FinalResultMsg, cfg config.AppConfig, completedAt time.Time) RunReport {
+	r := RunReport{
+		N:                 msg.N,
+		Algorithm:         msg.Result.Name,
+		Duration:          msg.Result.Duration,
+		ParallelThreshold: cfg.Threshold,
+		FFTThreshold:      cfg.FFTThreshold,
+		StrassenThreshold: cfg.StrassenThreshold,
+		CompletedAt:       completedAt,
+	}
+	if msg.Result.Result != nil {
+		r.Bits = msg.Result.Result.BitLen()
+		r.Digits = decimalDigits(msg.N, msg.Result.Result)
+		r.ResultSHA256 = audit.HashResult(msg.Result.Result)
+	}
+	if stats := msg.Result.CacheStats; stats != nil {
+		rate := stats.HitRate
+		r.CacheHitRate = &rate
+	}
+	r.Spill = msg.Result.SpillStats
+	return r
+}
+
+// decimalDigits returns the number of decimal digits of F(n). Large results
+// use the closed form to avoid a full decimal conversion.
+func decimalDigits(n uint64, result *big.Int) uint64 {
+	if result.BitLen() <= exactDigitsMaxBits {
+		return uint64(len(result.String()))
+	}
+	return fibonacci.DecimalDigits(n)
+}
+
+// reportFileName returns the default file name for a run artifact.
+func reportFileName(n uint64, at time.Time, suffix string) string {
+	return fmt.Sprintf("fibcalc-F%d-%s%s", n, at.Format("20060102-150405"), suffix)
+}
+
+// SaveReport writes r to path as indented JSON.
+//
+// Parameters:
+//   - path: The destination file, created with 0600 permissions.
+//   - r: The report to save.
+//
+// Returns:
+//   - error: An error if the file cannot be written.
+func SaveReport(path string, r RunReport) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	return writeFile(path, append(data, '\n'))
+}
+
+// LoadReport reads a report written by SaveReport.
+//
+// Parameters:
+//   - path: The report file.
+//
+// Returns:
+//   - RunReport: The decoded report.
+//   - error: An error if the file cannot be read or is not a run report.
+func LoadReport(path string) (RunReport, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return RunReport{}, fmt.Errorf("failed to read report: %w", err)
+	}
+	var r RunReport
+	if err := json.Unmarshal(data, &r); err != nil {
+		return RunReport{}, fmt.Errorf("failed to decode report %q: %w", path, err)
+	}
+	if r.N == 0 || r.Algorithm == "" {
+		return RunReport{}, fmt.Errorf("%q is not a run report", path)
+	}
+	return r, nil
+}
+
+// signReport sets the provenance of r, the report of value, when keyPath
+// names a signing key.
+func signReport(r *RunReport, value *big.Int, keyPath, version string) error {
+	if keyPath == "" || value == nil {
+		return nil
+	}
+	key, err := provenance.LoadPrivateKey(keyPath)
+	if err != nil {
+		return err
+	}
+	sig := provenance.Sign(key, provenance.NewStatement(r.N, r.Algorithm, version, value))
+	r.Provenance = &sig
+	return nil
+}
+
+// exportResult writes the value of F(n) to path, preceded by a header in
+// the format of the CLI's --output files dated generated, signed if r has
+// a provenance.
+func exportResult(path string, r RunReport, value *big.Int, generated time.Time) error {
+	if value == nil {
+		return fmt.Errorf("no result to export")
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Fibonacci Calculation Result\n"+
+		"# Generated: %s\n"+
+		"# Algorithm: %s\n"+
+		"# Duration: %s\n"+
+		"# N: %d\n"+
+		"# Bits: %d\n"+
+		"# Digits: %d\n",
+		generated.Format(time.RFC3339), r.Algorithm, r.Duration, r.N, r.Bits, r.Digits)
+	if r.Provenance != nil {
+		provenance.WriteHeader(&b, *r.Provenance)
+	}
+	fmt.Fprintf(&b, "\nF(%d) =\n%s\n", r.N, value.String())
+	return writeFile(path, []byte(b.String()))
+}
+
+// writeFile writes data to path with restrictive permissions, creating the
+// parent directory if needed.
+func writeFile(path string, data []byte) error {
+	path = filepath.Clean(path)
+	if dir := filepath.Dir(path); dir != "." {
+		if err := fsguard.MkdirAll(dir, 0750); err != nil {
+			return fmt.Errorf("failed to create directory %q: %w", dir, err)
+		}
+	}
+	if err := fsguard.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %q: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package tui
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/audit"
+	"github.com/agbru/fibcalc/internal/bigfft"
+	"github.com/agbru/fibcalc/internal/config"
+	"github.com/agbru/fibcalc/internal/orchestration"
+	"github.com/agbru/fibcalc/internal/provenance"
+)
+
+// fib returns F(n) computed iteratively.
+func fib(n uint64) *big.Int {
+	a, b := big.NewInt(0), big.NewInt(1)
+	for i := uint64(0); i < n; i++ {
+		a.Add(a, b)
+		a, b = b, a
+	}
+	return a
+}
+
+func TestDecimalDigits(t *testing.T) {
+	// Below and above the switch to the closed form
+	for _, n := range []uint64{2, 10, 93, 1000, 200_000} {
+		value := fib(n)
+		want := uint64(len(value.String()))
+		if got := decimalDigits(n, value); got != want {
+			t.Errorf("decimalDigits(%d) = %d, want %d", n, got, want)
+		}
+	}
+}
+
+func TestNewRunReport(t *testing.T) {
+	msg := FinalResultMsg{
+		Result: orchestration.CalculationResult{
+			Name:       "Fast",
+			Result:     big.NewInt(55),
+			Duration:   50 * time.Millisecond,
+			CacheStats: &bigfft.CacheStats{Hits: 3, Misses: 1, HitRate: 0.75},
+			SpillStats: &bigfft.SpillStats{Buffers: 2, Bytes: 1 << 20},
+		},
+		N: 10,
+	}
+	cfg := config.AppConfig{Threshold: 4096, FFTThreshold: 500000, StrassenThreshold: 3072}
+
+	completed := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	r := newRunReport(msg, cfg, completed)
+	if r.N != 10 || r.Algorithm != "Fast" || r.Duration != 50*time.Millisecond || !r.CompletedAt.Equal(completed) {
+		t.Errorf("unexpected identity fields: %+v", r)
+	}
+	if r.Bits != 6 || r.Digits != 2 {
+		t.Errorf("expected 6 bits and 2 digits, got %d and %d", r.Bits, r.Digits)
+	}
+	if r.ResultSHA256 != audit.HashResult(big.NewInt(55)) {
+		t.Errorf("expected the digest of 55, got %q", r.ResultSHA256)
+	}
+	if r.CacheHitRate == nil || *r.CacheHitRate != 0.75 {
+		t.Errorf("expected cache hit rate 0.75, got %v", r.CacheHitRate)
+	}
+	if r.Spill == nil || r.Spill.Buffers != 2 {
+		t.Errorf("expected 2 spilled buffers, got %v", r.Spill)
+	}
+	if r.ParallelThreshold != 4096 || r.FFTThreshold != 500000 || r.StrassenThreshold != 3072 {
+		t.Errorf("thresholds not recorded: %+v", r)
+	}
+}
+
+func TestSaveReport(t *testing.T) {
+	rate := 0.5
+	want := RunReport{
+		N:            1000,
+		Algorithm:    "Fast",
+		Duration:     time.Second,
+		Bits:         694,
+		Digits:       209,
+		CacheHitRate: &rate,
+		CompletedAt:  time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	path := filepath.Join(t.TempDir(), "reports", "run.json")
+
+	if err := SaveReport(path, want); err != nil {
+		t.Fatalf("SaveReport: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	var got RunReport
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if got.N != want.N || got.Duration != want.Duration || *got.CacheHitRate != rate || !got.CompletedAt.Equal(want.CompletedAt) {
+		t.Errorf("round trip mismatch: got %+v, want %+v", got, want)
+	}
+	if !strings.Contains(string(data), `"duration_ns": 1000000000`) {
+		t.Errorf("expected duration in nanoseconds, got:\n%s", data)
+	}
+}
+
+func TestLoadReport(t *testing.T) {
+	dir := t.TempDir()
+	want := RunReport{N: 1000, Algorithm: "Fast", Duration: time.Second, PeakHeapBytes: 4096}
+	path := filepath.Join(dir, "run.json")
+	if err := SaveReport(path, want); err != nil {
+		t.Fatalf("SaveReport: %v", err)
+	}
+
+	got, err := LoadReport(path)
+	if err != nil {
+		t.Fatalf("LoadReport: %v", err)
+	}
+	if got.N != want.N || got.Algorithm != want.Algorithm || got.Duration != want.Duration || got.PeakHeapBytes != want.PeakHeapBytes {
+		t.Errorf("LoadReport = %+v, want %+v", got, want)
+	}
+
+	invalid := map[string]string{
+		"not json":     "{",
+		"not a report": `{"foo": 1}`,
+		"missing algo": `{"n": 5}`,
+	}
+	for name, content := range invalid {
+		p := filepath.Join(dir, strings.ReplaceAll(name, " ", "-")+".json")
+		if err := os.WriteFile(p, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadReport(p); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if _, err := LoadReport(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestExportResult(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "result.txt")
+	r := RunReport{N: 10, Algorithm: "Fast", Bits: 6, Digits: 2}
+
+	if err := exportResult(path, r, big.NewInt(55), time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)); err != nil {
+		t.Fatalf("exportResult: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	for _, want := range []string{"# Generated: 2026-01-02T03:04:05Z", "# Algorithm: Fast", "# Digits: 2", "F(10) =\n55\n"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %q in export, got:\n%s", want, data)
+		}
+	}
+
+	if err := exportResult(path, r, nil, time.Now()); err == nil {
+		t.Error("expected an error without a result")
+	}
+}
+
+func TestSignedExport(t *testing.T) {
+	dir := t.TempDir()
+	_, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPath := filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	r := RunReport{N: 10, Algorithm: "Fast", Bits: 6, Digits: 2}
+	if err := signReport(&r, big.NewInt(55), keyPath, "v1.2.3"); err != nil || r.Provenance == nil {
+		t.Fatalf("signReport() = %v, provenance %v", err, r.Provenance)
+	}
+	resultPath, reportPath := filepath.Join(dir, "result.txt"), filepath.Join(dir, "report.json")
+	if err := exportResult(resultPath, r, big.NewInt(55), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveReport(reportPath, r); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{resultPath, reportPath} {
+		if v, err := provenance.VerifyFile(path, key.Public().(ed25519.PublicKey)); err != nil || v.Version != "v1.2.3" {
+			t.Errorf("VerifyFile(%s) = %+v, %v", filepath.Base(path), v, err)
+		}
+	}
+
+	if err := signReport(&r, big.NewInt(55), filepath.Join(dir, "missing.pem"), "v1.2.3"); err == nil {
+		t.Error("expected an error for a missing key")
+	}
+}
+
+func TestReportFileName(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if got := reportFileName(1000, at, ".txt"); got != "fibcalc-F1000-20260102-030405.txt" {
+		t.Errorf("unexpected file name %q", got)
+	}
+}
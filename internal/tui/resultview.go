@@ -0,0 +1,290 @@
+package tui
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/agbru/fibcalc/internal/format"
+)
+
+// resultMode selects how the result viewer shows the value.
+type resultMode int
+
+const (
+	// resultHex pages through the hexadecimal value.
+	resultHex resultMode = iota
+	// resultFull pages through the full decimal value.
+	resultFull
+	// resultWindow shows a fixed-size window of decimal digits.
+	resultWindow
+)
+
+// Result viewer layout.
+const (
+	// resultGroup is the number of digits per space-separated group.
+	resultGroup = 10
+	// resultWindowDigits is the size of the digit window.
+	resultWindowDigits = 50
+	// resultChrome is the number of rows taken by the viewer's borders,
+	// title, position line and key hints.
+	resultChrome = 7
+)
+
+// ResultTextMsg carries the text of the result in one base. The conversion
+// of a huge value takes a while, so it runs off the UI goroutine.
+type ResultTextMsg struct {
+	Value *big.Int
+	Base  int
+	Text  string
+}
+
+// ResultViewModel is the overlay showing the computed value: in hex or in
+// full decimal through a pager, or as a window of decimal digits that can
+// be moved along the number.
+type ResultViewModel struct {
+	value   *big.Int
+	n       uint64
+	mode    resultMode
+	visible bool
+
+	decimal, hex     string // converted text, empty until ready
+	decPend, hexPend bool   // conversion in flight
+
+	top    int // first line shown by the pager
+	window int // index of the first digit of the window
+	width  int
+	height int
+}
+
+// NewResultViewModel creates an empty result viewer.
+func NewResultViewModel() ResultViewModel {
+	return ResultViewModel{}
+}
+
+// Reset forgets the result for a restart, keeping the size.
+func (r *ResultViewModel) Reset() {
+	*r = ResultViewModel{width: r.width, height: r.height}
+}
+
+// SetSize updates the size of the area the overlay is placed in.
+func (r *ResultViewModel) SetSize(w, h int) {
+	r.width = w
+	r.height = h
+}
+
+// SetValue records the final value of F(n). Text conversions happen on
+// demand, the first time a mode needs them.
+func (r *ResultViewModel) SetValue(value *big.Int, n uint64) {
+	if value == nil {
+		return
+	}
+	r.Reset()
+	r.value = value
+	r.n = n
+}
+
+// Available reports whether a value has been recorded.
+func (r ResultViewModel) Available() bool {
+	return r.value != nil
+}
+
+// Visible reports whether the overlay is shown.
+func (r ResultViewModel) Visible() bool {
+	return r.visible
+}
+
+// Hide closes the overlay.
+func (r *ResultViewModel) Hide() {
+	r.visible = false
+}
+
+// Open shows the overlay in the given mode, returning the command that
+// converts the value if that mode's text is not ready. It does nothing
+// without a value.
+func (r *ResultViewModel) Open(mode resultMode) tea.Cmd {
+	if r.value == nil {
+		return nil
+	}
+	if mode != r.mode {
+		r.top = 0
+	}
+	r.mode, r.visible = mode, true
+	if mode == resultHex {
+		if r.hex == "" && !r.hexPend {
+			r.hexPend = true
+			return resultTextCmd(r.value, 16)
+		}
+		return nil
+	}
+	if r.decimal == "" && !r.decPend {
+		r.decPend = true
+		return resultTextCmd(r.value, 10)
+	}
+	return nil
+}
+
+// HandleText stores a finished conversion. Conversions of a previous
+// value, from before a restart, are dropped.
+func (r *ResultViewModel) HandleText(msg ResultTextMsg) {
+	if msg.Value != r.value {
+		return
+	}
+	if msg.Base == 16 {
+		r.hex, r.hexPend = msg.Text, false
+	} else {
+		r.decimal, r.decPend = msg.Text, false
+	}
+}
+
+// resultTextCmd converts value to the given base off the UI goroutine.
+func resultTextCmd(value *big.Int, base int) tea.Cmd {
+	return func() tea.Msg {
+		return ResultTextMsg{Value: value, Base: base, Text: value.Text(base)}
+	}
+}
+
+// Scroll moves the pager by delta lines, or the digit window by delta
+// windows, clamped to the value.
+func (r *ResultViewModel) Scroll(delta int) {
+	if r.mode == resultWindow {
+		last := max(0, len(r.decimal)-resultWindowDigits)
+		r.window = min(max(0, r.window+delta*resultWindowDigits), last)
+		return
+	}
+	r.top = min(max(0, r.top+delta), r.maxTop())
+}
+
+// PageSize returns the number of lines of a pager page.
+func (r ResultViewModel) PageSize() int {
+	return max(1, r.height-resultChrome)
+}
+
+// ScrollHome moves to the most significant digits.
+func (r *ResultViewModel) ScrollHome() {
+	r.top, r.window = 0, 0
+}
+
+// ScrollEnd moves to the least significant digits.
+func (r *ResultViewModel) ScrollEnd() {
+	r.top = r.maxTop()
+	r.window = max(0, len(r.decimal)-resultWindowDigits)
+}
+
+// text returns the text the current mode pages through.
+func (r ResultViewModel) text() string {
+	if r.mode == resultHex {
+		return r.hex
+	}
+	return r.decimal
+}
+
+// lineDigits returns the number of digits per pager line: whole groups
+// fitting next to the position label.
+func (r ResultViewModel) lineDigits() int {
+	label := len(format.FormatNumberString(fmt.Sprintf("%d", len(r.text())))) + 2
+	groups := (r.width - 8 - label) / (resultGroup + 1)
+	return max(1, groups) * resultGroup
+}
+
+// maxTop returns the first line of the last pager page.
+func (r ResultViewModel) maxTop() int {
+	per := r.lineDigits()
+	lines := (len(r.text()) + per - 1) / per
+	return max(0, lines-r.PageSize())
+}
+
+// group splits digits into space-separated groups of resultGroup.
+func group(digits string) string {
+	var b strings.Builder
+	for i := 0; i < len(digits); i += resultGroup {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(digits[i:min(i+resultGroup, len(digits))])
+	}
+	return b.String()
+}
+
+// View renders the viewer box.
+func (r ResultViewModel) View() string {
+	var b strings.Builder
+	titles := map[resultMode]string{
+		resultHex:    "hexadecimal",
+		resultFull:   "decimal",
+		resultWindow: "digit window",
+	}
+	b.WriteString(titleStyle.Render(fmt.Sprintf("F(%d), %s", r.n, titles[r.mode])))
+	b.WriteString("\n")
+
+	text := r.text()
+	switch {
+	case text == "":
+		b.WriteString(logTimeStyle.Render("Converting..."))
+	case r.mode == resultWindow:
+		r.writeWindow(&b, text)
+	default:
+		r.writePage(&b, text)
+	}
+
+	b.WriteString("\n\n")
+	b.WriteString(fmt.Sprintf("%s: %s   %s: %s   %s: %s   %s: %s   %s: %s",
+		footerKeyStyle.Render("x"), footerDescStyle.Render("Hex"),
+		footerKeyStyle.Render("d"), footerDescStyle.Render("Full value"),
+		footerKeyStyle.Render("z"), footerDescStyle.Render("Digit window"),
+		footerKeyStyle.Render(r.scrollKeys()), footerDescStyle.Render("Move"),
+		footerKeyStyle.Render("esc"), footerDescStyle.Render("Close"),
+	))
+
+	style := panelStyle.Padding(0, 2)
+	if r.width > 0 {
+		style = style.MaxWidth(r.width)
+	}
+	return style.Render(b.String())
+}
+
+// scrollKeys returns the key hint for moving in the current mode.
+func (r ResultViewModel) scrollKeys() string {
+	if r.mode == resultWindow {
+		return "left/right home/end"
+	}
+	return "up/down pgup/pgdn"
+}
+
+// writePage renders one pager page, each line labeled with the position of
+// its first digit (1 is the most significant).
+func (r ResultViewModel) writePage(b *strings.Builder, text string) {
+	per := r.lineDigits()
+	total := format.FormatNumberString(fmt.Sprintf("%d", len(text)))
+	first := r.top * per
+	last := min(len(text), first+r.PageSize()*per)
+	b.WriteString(metricLabelStyle.Render(fmt.Sprintf("Digits %s–%s of %s",
+		format.FormatNumberString(fmt.Sprintf("%d", first+1)),
+		format.FormatNumberString(fmt.Sprintf("%d", last)), total)))
+	for start := first; start < last; start += per {
+		label := format.FormatNumberString(fmt.Sprintf("%d", start+1))
+		b.WriteString("\n")
+		b.WriteString(logTimeStyle.Render(fmt.Sprintf("%*s  ", len(total), label)))
+		b.WriteString(metricValueStyle.Render(group(text[start:min(start+per, last)])))
+	}
+}
+
+// writeWindow renders the digit window with its position counted from
+// both ends of the number.
+func (r ResultViewModel) writeWindow(b *strings.Builder, text string) {
+	first := min(r.window, max(0, len(text)-resultWindowDigits))
+	last := min(len(text), first+resultWindowDigits)
+	num := func(i int) string { return format.FormatNumberString(fmt.Sprintf("%d", i)) }
+	b.WriteString(metricLabelStyle.Render(fmt.Sprintf("Digits %s–%s of %s (%s–%s from the right)",
+		num(first+1), num(last), num(len(text)), num(len(text)-last+1), num(len(text)-first))))
+	b.WriteString("\n")
+	b.WriteString(metricValueStyle.Render(group(text[first:last])))
+}
+
+// overlay centers the viewer over a body of the given size.
+func (r ResultViewModel) overlay(width, height int) string {
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, r.View())
+}
@@ -0,0 +1,180 @@
+package tui
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/orchestration"
+	"github.com/agbru/fibcalc/internal/testutil"
+)
+
+// openResult opens the viewer in mode and runs the conversion it requests.
+func openResult(t *testing.T, r *ResultViewModel, mode resultMode) {
+	t.Helper()
+	if cmd := r.Open(mode); cmd != nil {
+		r.HandleText(cmd().(ResultTextMsg))
+	}
+}
+
+func TestResultViewModel_OpenRequiresValue(t *testing.T) {
+	r := NewResultViewModel()
+	if cmd := r.Open(resultHex); cmd != nil || r.Visible() {
+		t.Error("expected Open to do nothing without a value")
+	}
+}
+
+func TestResultViewModel_Modes(t *testing.T) {
+	r := NewResultViewModel()
+	r.SetSize(120, 30)
+	r.SetValue(big.NewInt(12586269025), 50)
+
+	openResult(t, &r, resultHex)
+	view := testutil.StripAnsiCodes(r.View())
+	if !strings.Contains(view, "F(50), hexadecimal") || !strings.Contains(view, "2ee333961") {
+		t.Errorf("hex view missing value: %q", view)
+	}
+
+	openResult(t, &r, resultFull)
+	view = testutil.StripAnsiCodes(r.View())
+	if !strings.Contains(view, "1258626902 5") || !strings.Contains(view, "Digits 1–11 of 11") {
+		t.Errorf("decimal view missing grouped value: %q", view)
+	}
+
+	// The decimal text is reused: no second conversion.
+	if cmd := r.Open(resultWindow); cmd != nil {
+		t.Error("expected the window to reuse the decimal text")
+	}
+	view = testutil.StripAnsiCodes(r.View())
+	if !strings.Contains(view, "digit window") || !strings.Contains(view, "1–11 from the right") {
+		t.Errorf("window view: %q", view)
+	}
+}
+
+func TestResultViewModel_ConvertingAndStaleText(t *testing.T) {
+	r := NewResultViewModel()
+	r.SetValue(big.NewInt(55), 10)
+	cmd := r.Open(resultFull)
+	if cmd == nil {
+		t.Fatal("expected a conversion command")
+	}
+	if !strings.Contains(testutil.StripAnsiCodes(r.View()), "Converting...") {
+		t.Error("expected a placeholder while converting")
+	}
+	msg := cmd().(ResultTextMsg)
+
+	// A restart records a new value; the old conversion is dropped.
+	r.SetValue(big.NewInt(89), 11)
+	r.HandleText(msg)
+	if r.decimal != "" {
+		t.Errorf("stale conversion stored: %q", r.decimal)
+	}
+}
+
+func TestResultViewModel_WindowScroll(t *testing.T) {
+	r := NewResultViewModel()
+	r.SetSize(120, 30)
+	value, _ := new(big.Int).SetString(strings.Repeat("1234567890", 12), 10) // 120 digits
+	r.SetValue(value, 0)
+	openResult(t, &r, resultWindow)
+
+	r.Scroll(1)
+	if r.window != resultWindowDigits {
+		t.Errorf("window = %d after one step, want %d", r.window, resultWindowDigits)
+	}
+	r.Scroll(5)
+	if r.window != 120-resultWindowDigits {
+		t.Errorf("window = %d, want it clamped to %d", r.window, 120-resultWindowDigits)
+	}
+	if view := testutil.StripAnsiCodes(r.View()); !strings.Contains(view, "Digits 71–120 of 120 (1–50 from the right)") {
+		t.Errorf("window view: %q", view)
+	}
+	r.ScrollHome()
+	if r.window != 0 {
+		t.Errorf("window = %d after home, want 0", r.window)
+	}
+}
+
+func TestResultViewModel_PagerScroll(t *testing.T) {
+	r := NewResultViewModel()
+	r.SetSize(60, 10) // 3 page lines of 40 digits
+	value, _ := new(big.Int).SetString("1"+strings.Repeat("0", 399), 10)
+	r.SetValue(value, 0)
+	openResult(t, &r, resultFull)
+
+	if per := r.lineDigits(); per != 40 {
+		t.Fatalf("lineDigits() = %d, want 40", per)
+	}
+	r.ScrollEnd()
+	if want := 10 - r.PageSize(); r.top != want {
+		t.Errorf("top = %d after end, want %d", r.top, want)
+	}
+	r.Scroll(-100)
+	if r.top != 0 {
+		t.Errorf("top = %d, want it clamped to 0", r.top)
+	}
+}
+
+func TestModel_ResultKeys(t *testing.T) {
+	m := newTestModelWithSize(t, 120, 40)
+	key := func(m Model, r rune) (Model, tea.Cmd) {
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		return updated.(Model), cmd
+	}
+
+	// While running, d has no value to show and v toggles log sampling.
+	m, _ = key(m, 'd')
+	if m.result.Visible() {
+		t.Fatal("expected 'd' to do nothing while running")
+	}
+	m, _ = key(m, 'v')
+	if !m.logs.FullVerbosity() || m.result.Visible() {
+		t.Fatal("expected 'v' to toggle verbosity")
+	}
+
+	updated, _ := m.Update(FinalResultMsg{
+		Result: orchestration.CalculationResult{Name: "Fast", Result: big.NewInt(2880067194370816120)},
+		N:      90,
+	})
+	m = updated.(Model)
+	updated, _ = m.Update(CalculationCompleteMsg{ExitCode: apperrors.ExitSuccess})
+	m = updated.(Model)
+
+	// From the summary, x opens the hex view.
+	m, cmd := key(m, 'x')
+	if !m.result.Visible() || m.summary.Visible() {
+		t.Fatal("expected 'x' to replace the summary with the result viewer")
+	}
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+	if view := testutil.StripAnsiCodes(m.View()); !strings.Contains(view, "F(90), hexadecimal") {
+		t.Errorf("expected the hex view in the dashboard: %q", view)
+	}
+
+	// z switches to the digit window, esc closes, d reopens once done.
+	m, cmd = key(m, 'z')
+	if m.result.mode != resultWindow || cmd == nil {
+		t.Error("expected 'z' to switch to the digit window and convert")
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.result.Visible() {
+		t.Fatal("expected Esc to close the result viewer")
+	}
+	m, _ = key(m, 'd')
+	if !m.result.Visible() || m.result.mode != resultFull {
+		t.Error("expected 'd' to show the full value once done")
+	}
+	if !m.logs.FullVerbosity() {
+		t.Error("expected 'd' not to toggle verbosity")
+	}
+
+	// Restart clears the value.
+	m, _ = key(m, 'r')
+	if m.result.Visible() || m.result.Available() {
+		t.Error("expected restart to clear the result viewer")
+	}
+}
@@ -0,0 +1,166 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// logSearch holds the search state of the logs panel.
+type logSearch struct {
+	editing bool   // the query is being typed
+	input   string // query being typed
+	query   string // committed query, matched case-insensitively
+	matches []int  // indices of matching entries
+	current int    // position in matches of the selected match
+}
+
+// StartSearch opens the search prompt.
+func (l *LogsModel) StartSearch() {
+	l.search.editing = true
+	l.search.input = ""
+}
+
+// Searching reports whether the search prompt is capturing keystrokes.
+func (l LogsModel) Searching() bool {
+	return l.search.editing
+}
+
+// HandleSearchKey edits the search prompt. Enter runs the search and jumps
+// to the first match at or below the current scroll position; Esc closes
+// the prompt without changing the active search.
+func (l *LogsModel) HandleSearchKey(msg tea.KeyMsg) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		l.search.editing = false
+		l.search.query = l.search.input
+		l.search.matches = nil
+		l.updateContent()
+		if len(l.search.matches) > 0 {
+			l.search.current = 0
+			for i, idx := range l.search.matches {
+				if idx >= l.viewport.YOffset {
+					l.search.current = i
+					break
+				}
+			}
+			l.jumpToMatch()
+		}
+	case tea.KeyEsc:
+		l.search.editing = false
+	case tea.KeyBackspace:
+		if r := []rune(l.search.input); len(r) > 0 {
+			l.search.input = string(r[:len(r)-1])
+		}
+	case tea.KeySpace:
+		l.search.input += " "
+	case tea.KeyRunes:
+		l.search.input += string(msg.Runes)
+	}
+}
+
+// ClearSearch removes the active search and its highlights.
+func (l *LogsModel) ClearSearch() {
+	l.search = logSearch{}
+	l.updateContent()
+}
+
+// NextMatch moves to the next match, wrapping around at the end.
+func (l *LogsModel) NextMatch() {
+	if len(l.search.matches) == 0 {
+		return
+	}
+	l.search.current = (l.search.current + 1) % len(l.search.matches)
+	l.jumpToMatch()
+}
+
+// PrevMatch moves to the previous match, wrapping around at the start.
+func (l *LogsModel) PrevMatch() {
+	if len(l.search.matches) == 0 {
+		return
+	}
+	l.search.current = (l.search.current - 1 + len(l.search.matches)) % len(l.search.matches)
+	l.jumpToMatch()
+}
+
+// jumpToMatch centers the selected match in the viewport. Following is
+// turned off, otherwise the next entry would scroll the match away.
+func (l *LogsModel) jumpToMatch() {
+	l.follow = false
+	l.updateContent()
+	line := l.search.matches[l.search.current]
+	l.viewport.SetYOffset(line - l.viewport.Height/2)
+}
+
+// searchStatus returns the status line shown at the bottom of the logs
+// panel, or "" when no search is active.
+func (l LogsModel) searchStatus() string {
+	switch {
+	case l.search.editing:
+		return "/" + l.search.input + "█"
+	case l.search.query == "":
+		return ""
+	case len(l.search.matches) == 0:
+		return fmt.Sprintf("/%s: no matches", l.search.query)
+	default:
+		return fmt.Sprintf("/%s: match %d/%d (n/N, esc to clear)",
+			l.search.query, l.search.current+1, len(l.search.matches))
+	}
+}
+
+// highlightEntries returns the entries with occurrences of the active query
+// highlighted, recording the indices of matching entries. Matching lines
+// lose their original colors so the highlight stands out.
+func (l *LogsModel) highlightEntries() []string {
+	if l.search.query == "" {
+		return l.entries
+	}
+	query := strings.ToLower(l.search.query)
+	selected := -1
+	if l.search.current < len(l.search.matches) {
+		selected = l.search.matches[l.search.current]
+	}
+
+	l.search.matches = l.search.matches[:0]
+	lines := make([]string, len(l.entries))
+	for i, entry := range l.entries {
+		plain := ansi.Strip(entry)
+		lower := strings.ToLower(plain)
+		if !strings.Contains(lower, query) {
+			lines[i] = entry
+			continue
+		}
+		l.search.matches = append(l.search.matches, i)
+		style := logMatchStyle
+		if i == selected {
+			style = logCurrentMatchStyle
+		}
+		lines[i] = highlightOccurrences(plain, lower, query, style.Render)
+	}
+	if l.search.current >= len(l.search.matches) {
+		l.search.current = max(len(l.search.matches)-1, 0)
+	}
+	return lines
+}
+
+// highlightOccurrences wraps every occurrence of query in plain using
+// render. lower must be the lowercased plain; if lowercasing changed byte
+// offsets, the whole line is highlighted instead.
+func highlightOccurrences(plain, lower, query string, render func(...string) string) string {
+	if len(lower) != len(plain) {
+		return render(plain)
+	}
+	var b strings.Builder
+	for {
+		i := strings.Index(lower, query)
+		if i < 0 {
+			b.WriteString(plain)
+			return b.String()
+		}
+		b.WriteString(plain[:i])
+		b.WriteString(render(plain[i : i+len(query)]))
+		plain, lower = plain[i+len(query):], lower[i+len(query):]
+	}
+}
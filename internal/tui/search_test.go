@@ -0,0 +1,168 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/agbru/fibcalc/internal/testutil"
+)
+
+// searchLogs types query into the search prompt and submits it.
+func searchLogs(l *LogsModel, query string) {
+	l.StartSearch()
+	for _, r := range query {
+		if r == ' ' {
+			l.HandleSearchKey(tea.KeyMsg{Type: tea.KeySpace})
+			continue
+		}
+		l.HandleSearchKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	l.HandleSearchKey(tea.KeyMsg{Type: tea.KeyEnter})
+}
+
+func newSearchTestLogs() LogsModel {
+	logs := NewLogsModel([]string{"Fast"})
+	logs.SetSize(80, 8)
+	logs.ToggleVerbosity()
+	for i := 0; i < 40; i++ {
+		if i%10 == 5 {
+			logs.AddError(ErrorMsg{Err: errors.New("Overflow in step")})
+			continue
+		}
+		logs.AddProgressEntry(ProgressMsg{CalculatorIndex: 0, Value: float64(i) / 40})
+	}
+	return logs
+}
+
+func TestLogsModel_Search_FindsMatchesCaseInsensitive(t *testing.T) {
+	logs := newSearchTestLogs()
+	searchLogs(&logs, "overflow")
+
+	if logs.Searching() {
+		t.Error("expected the prompt to close after Enter")
+	}
+	if got := len(logs.search.matches); got != 4 {
+		t.Fatalf("expected 4 matches, got %d", got)
+	}
+	for _, idx := range logs.search.matches {
+		if !strings.Contains(testutil.StripAnsiCodes(logs.entries[idx]), "Overflow") {
+			t.Errorf("entry %d does not contain the query: %q", idx, logs.entries[idx])
+		}
+	}
+	if logs.Following() {
+		t.Error("expected jumping to a match to turn follow off")
+	}
+	// The search starts from the top of the view, which is at the bottom
+	// while following
+	if !strings.Contains(logs.searchStatus(), "match 4/4") {
+		t.Errorf("unexpected status %q", logs.searchStatus())
+	}
+}
+
+func TestLogsModel_Search_NextPrevWrap(t *testing.T) {
+	logs := newSearchTestLogs()
+	searchLogs(&logs, "overflow")
+
+	logs.NextMatch()
+	if logs.search.current != 0 {
+		t.Errorf("expected NextMatch to wrap to the first match, got %d", logs.search.current)
+	}
+	logs.PrevMatch()
+	if logs.search.current != 3 {
+		t.Errorf("expected PrevMatch to wrap to the last match, got %d", logs.search.current)
+	}
+	logs.PrevMatch()
+	line := logs.search.matches[logs.search.current]
+	if line < logs.viewport.YOffset || line >= logs.viewport.YOffset+logs.viewport.Height {
+		t.Errorf("match on line %d not visible at offset %d", line, logs.viewport.YOffset)
+	}
+}
+
+func TestLogsModel_Search_NoMatches(t *testing.T) {
+	logs := newSearchTestLogs()
+	searchLogs(&logs, "no such text")
+
+	if len(logs.search.matches) != 0 {
+		t.Errorf("expected no matches, got %d", len(logs.search.matches))
+	}
+	if !strings.Contains(logs.searchStatus(), "no matches") {
+		t.Errorf("unexpected status %q", logs.searchStatus())
+	}
+	// Navigation without matches is a no-op
+	logs.NextMatch()
+	logs.PrevMatch()
+	if !logs.Following() {
+		t.Error("a search without matches should not change follow mode")
+	}
+}
+
+func TestLogsModel_Search_EscKeepsActiveSearch(t *testing.T) {
+	logs := newSearchTestLogs()
+	searchLogs(&logs, "overflow")
+
+	logs.StartSearch()
+	logs.HandleSearchKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("xyz")})
+	logs.HandleSearchKey(tea.KeyMsg{Type: tea.KeyEsc})
+
+	if logs.Searching() {
+		t.Error("expected Esc to close the prompt")
+	}
+	if logs.search.query != "overflow" {
+		t.Errorf("expected the previous query to stay active, got %q", logs.search.query)
+	}
+}
+
+func TestLogsModel_Search_NewEntriesAreMatched(t *testing.T) {
+	logs := newSearchTestLogs()
+	searchLogs(&logs, "overflow")
+
+	logs.AddError(ErrorMsg{Err: errors.New("another overflow")})
+	if got := len(logs.search.matches); got != 5 {
+		t.Errorf("expected 5 matches after a new matching entry, got %d", got)
+	}
+}
+
+func TestLogsModel_ClearSearch(t *testing.T) {
+	logs := newSearchTestLogs()
+	searchLogs(&logs, "overflow")
+	logs.ClearSearch()
+
+	if logs.searchStatus() != "" {
+		t.Errorf("expected no status after clearing, got %q", logs.searchStatus())
+	}
+	if len(logs.search.matches) != 0 {
+		t.Error("expected matches to be cleared")
+	}
+}
+
+func TestLogsModel_RenderToHeight_ShowsSearchPrompt(t *testing.T) {
+	logs := newSearchTestLogs()
+	logs.StartSearch()
+	logs.HandleSearchKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("ove")})
+
+	view := testutil.StripAnsiCodes(logs.renderToHeight(10))
+	if !strings.Contains(view, "/ove") {
+		t.Errorf("expected the search prompt in the panel, got:\n%s", view)
+	}
+}
+
+func TestHighlightOccurrences(t *testing.T) {
+	mark := func(s ...string) string { return "[" + strings.Join(s, "") + "]" }
+
+	tests := []struct {
+		plain, query, want string
+	}{
+		{"Overflow at overflow", "overflow", "[Overflow] at [overflow]"},
+		{"no match", "xyz", "no match"},
+		{"aaa", "a", "[a][a][a]"},
+	}
+	for _, tt := range tests {
+		got := highlightOccurrences(tt.plain, strings.ToLower(tt.plain), tt.query, mark)
+		if got != tt.want {
+			t.Errorf("highlightOccurrences(%q, %q) = %q, want %q", tt.plain, tt.query, got, tt.want)
+		}
+	}
+}
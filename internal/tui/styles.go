@@ -19,6 +19,9 @@ var (
 	logProgressStyle  lipgloss.Style
 	logSuccessStyle   lipgloss.Style
 	logErrorStyle     lipgloss.Style
+	logWarnStyle      lipgloss.Style
+	logMatchStyle        lipgloss.Style
+	logCurrentMatchStyle lipgloss.Style
 	metricLabelStyle  lipgloss.Style
 	metricValueStyle  lipgloss.Style
 	chartBarStyle     lipgloss.Style
@@ -31,16 +34,16 @@ var (
 	statusErrorStyle   lipgloss.Style
 	cpuSparklineStyle  lipgloss.Style
 	memSparklineStyle  lipgloss.Style
+	idleStyle          lipgloss.Style
 )
 
 func init() {
-	initTUIStyles()
+	initTUIStyles(ui.GetCurrentTUITheme())
 }
 
-// initTUIStyles rebuilds all TUI styles from the current ui theme.
-// Called at package init and again from Run() after InitTheme has been invoked.
-func initTUIStyles() {
-	t := ui.GetCurrentTUITheme()
+// initTUIStyles rebuilds all TUI styles from the palette t.
+// Called at package init and again from Run() with the theme of the run.
+func initTUIStyles(t ui.TUITheme) {
 
 	panelStyle = lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
@@ -79,6 +82,18 @@ func initTUIStyles() {
 	logErrorStyle = lipgloss.NewStyle().
 		Foreground(t.Error)
 
+	logWarnStyle = lipgloss.NewStyle().
+		Foreground(t.Warning)
+
+	logMatchStyle = lipgloss.NewStyle().
+		Foreground(t.Bg).
+		Background(t.Warning)
+
+	logCurrentMatchStyle = lipgloss.NewStyle().
+		Foreground(t.Bg).
+		Background(t.Accent).
+		Bold(true)
+
 	metricLabelStyle = lipgloss.NewStyle().
 		Foreground(t.Dim)
 
@@ -120,4 +135,7 @@ func initTUIStyles() {
 
 	memSparklineStyle = lipgloss.NewStyle().
 		Foreground(t.Warning)
+
+	idleStyle = lipgloss.NewStyle().
+		Foreground(t.Dim)
 }
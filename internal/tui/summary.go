@@ -0,0 +1,296 @@
+package tui
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/agbru/fibcalc/internal/clock"
+	"github.com/agbru/fibcalc/internal/config"
+	"github.com/agbru/fibcalc/internal/format"
+	"github.com/agbru/fibcalc/internal/metrics"
+)
+
+// SummaryModel is the overlay shown when a run completes. It presents the
+// key metrics of the final result and can save them as a JSON report or
+// export the computed value. With a baseline report loaded, it shows the
+// baseline and the current run side by side instead.
+type SummaryModel struct {
+	report       RunReport
+	value        *big.Int
+	outputFile   string // export destination from --output, if any
+	available    bool
+	visible      bool
+	status       string // outcome of the last save or export
+	baseline     *RunReport
+	baselinePath string
+	comparing    bool   // show the baseline comparison rather than the summary
+	signKey      string // --sign-key: signs saved reports and exports
+	version      string // fibcalc version recorded in signatures
+	width        int
+	clock        clock.Clock // dates the report and the export
+}
+
+// NewSummaryModel creates an empty summary.
+func NewSummaryModel() SummaryModel {
+	return NewSummaryModelClock(clock.Real{})
+}
+
+// NewSummaryModelClock creates an empty summary that dates its report and
+// exports with clk.
+//
+// Parameters:
+//   - clk: The time source.
+//
+// Returns:
+//   - SummaryModel: The summary.
+func NewSummaryModelClock(clk clock.Clock) SummaryModel {
+	return SummaryModel{clock: clk}
+}
+
+// Reset clears the run's result for a restart. The baseline is kept.
+func (s *SummaryModel) Reset() {
+	*s = SummaryModel{
+		baseline:     s.baseline,
+		baselinePath: s.baselinePath,
+		comparing:    s.baseline != nil,
+		signKey:      s.signKey,
+		version:      s.version,
+		width:        s.width,
+		clock:        s.clock,
+	}
+}
+
+// SetWidth updates the available width.
+func (s *SummaryModel) SetWidth(w int) {
+	s.width = w
+}
+
+// SetBaseline sets the report the current run is compared against. The
+// comparison is shown by default once the run completes.
+func (s *SummaryModel) SetBaseline(path string, r RunReport) {
+	s.baseline = &r
+	s.baselinePath = path
+	s.comparing = true
+}
+
+// SetSigning makes saved reports and exported results carry a signature
+// by the key at keyPath (see the provenance package).
+//
+// Parameters:
+//   - keyPath: The PEM ed25519 private key.
+//   - version: The fibcalc version recorded in signatures.
+func (s *SummaryModel) SetSigning(keyPath, version string) {
+	s.signKey, s.version = keyPath, version
+}
+
+// ToggleComparison switches between the summary and the baseline
+// comparison. It does nothing without a baseline.
+func (s *SummaryModel) ToggleComparison() {
+	s.comparing = s.baseline != nil && !s.comparing
+}
+
+// SetResult records the final result of the run.
+func (s *SummaryModel) SetResult(msg FinalResultMsg, cfg config.AppConfig) {
+	if msg.Result.Result == nil {
+		return
+	}
+	s.report = newRunReport(msg, cfg, clock.Or(s.clock).Now())
+	s.value = msg.Result.Result
+	s.outputFile = cfg.OutputFile
+	s.available = true
+}
+
+// SetIndicators fills in the throughput and golden ratio deviation from
+// the final indicators.
+func (s *SummaryModel) SetIndicators(ind *metrics.Indicators) {
+	if ind == nil || ind.Live {
+		return
+	}
+	s.report.BitsPerSecond = ind.BitsPerSecond
+	s.report.GoldenRatioDeviation = ind.GoldenRatioDeviation
+}
+
+// SetThroughput records the rolling (last minute) and peak throughput of
+// the run, in bits per second.
+func (s *SummaryModel) SetThroughput(rolling, peak float64) {
+	s.report.RollingBitsPerSecond = rolling
+	s.report.PeakBitsPerSecond = peak
+}
+
+// Show opens the overlay if a result is available, recording the peak heap
+// sampled during the run.
+//
+// Returns:
+//   - bool: true if the overlay is now visible.
+func (s *SummaryModel) Show(peakHeap uint64) bool {
+	if !s.available {
+		return false
+	}
+	s.report.PeakHeapBytes = max(s.report.PeakHeapBytes, peakHeap)
+	s.visible = true
+	return true
+}
+
+// Hide closes the overlay.
+func (s *SummaryModel) Hide() {
+	s.visible = false
+}
+
+// Visible reports whether the overlay is shown.
+func (s SummaryModel) Visible() bool {
+	return s.visible
+}
+
+// Available reports whether a result has been recorded.
+func (s SummaryModel) Available() bool {
+	return s.available
+}
+
+// Report returns the report of the run.
+func (s SummaryModel) Report() RunReport {
+	return s.report
+}
+
+// SummarySavedMsg reports the outcome of saving the report or exporting
+// the result from the summary screen.
+type SummarySavedMsg struct {
+	What string // "Report" or "Result"
+	Path string
+	Err  error
+}
+
+// HandleSaved records the outcome of a save or export.
+func (s *SummaryModel) HandleSaved(msg SummarySavedMsg) {
+	if msg.Err != nil {
+		s.status = logErrorStyle.Render(fmt.Sprintf("%s not saved: %v", msg.What, msg.Err))
+		return
+	}
+	s.status = logSuccessStyle.Render(fmt.Sprintf("%s saved to %s", msg.What, msg.Path))
+}
+
+// saveReportCmd writes the report to a timestamped JSON file in the current
+// directory.
+func (s SummaryModel) saveReportCmd() tea.Cmd {
+	r, value, keyPath, version := s.report, s.value, s.signKey, s.version
+	return func() tea.Msg {
+		path := reportFileName(r.N, r.CompletedAt, "-summary.json")
+		if err := signReport(&r, value, keyPath, version); err != nil {
+			return SummarySavedMsg{What: "Report", Path: path, Err: err}
+		}
+		return SummarySavedMsg{What: "Report", Path: path, Err: SaveReport(path, r)}
+	}
+}
+
+// exportResultCmd writes the computed value to the --output file, or to a
+// timestamped text file in the current directory. The decimal conversion
+// can take a while for huge results, so it runs off the UI goroutine.
+func (s SummaryModel) exportResultCmd() tea.Cmd {
+	r, value, path, keyPath, version, clk := s.report, s.value, s.outputFile, s.signKey, s.version, clock.Or(s.clock)
+	if path == "" {
+		path = reportFileName(r.N, r.CompletedAt, ".txt")
+	}
+	return func() tea.Msg {
+		if err := signReport(&r, value, keyPath, version); err != nil {
+			return SummarySavedMsg{What: "Result", Path: path, Err: err}
+		}
+		return SummarySavedMsg{What: "Result", Path: path, Err: exportResult(path, r, value, clk.Now())}
+	}
+}
+
+// View renders the summary box, or the baseline comparison.
+func (s SummaryModel) View() string {
+	var b strings.Builder
+	compareKey := ""
+	if s.baseline != nil && s.comparing {
+		b.WriteString(comparisonView(*s.baseline, s.report, s.baselinePath))
+		compareKey = "Summary"
+	} else {
+		b.WriteString(s.summaryBody())
+		if s.baseline != nil {
+			compareKey = "Compare"
+		}
+	}
+	b.WriteString("\n\n")
+	if s.status != "" {
+		b.WriteString(s.status)
+		b.WriteString("\n")
+	}
+	b.WriteString(fmt.Sprintf("%s: %s   %s: %s   %s: %s   %s: %s",
+		footerKeyStyle.Render("w"), footerDescStyle.Render("Save report"),
+		footerKeyStyle.Render("e"), footerDescStyle.Render("Export result"),
+		footerKeyStyle.Render("x/d"), footerDescStyle.Render("View result"),
+		footerKeyStyle.Render("esc"), footerDescStyle.Render("Close"),
+	))
+	if compareKey != "" {
+		b.WriteString(fmt.Sprintf("   %s: %s", footerKeyStyle.Render("c"), footerDescStyle.Render(compareKey)))
+	}
+
+	style := panelStyle.Padding(0, 2)
+	if s.width > 0 {
+		style = style.MaxWidth(s.width)
+	}
+	return style.Render(b.String())
+}
+
+// summaryBody renders the key metrics of the current run.
+func (s SummaryModel) summaryBody() string {
+	r := s.report
+	cacheHitRate := "n/a (cache unused)"
+	if r.CacheHitRate != nil {
+		cacheHitRate = fmt.Sprintf("%.1f%%", *r.CacheHitRate*100)
+	}
+	throughput := "computing..."
+	if r.BitsPerSecond > 0 {
+		throughput = metrics.FormatBitsPerSecond(r.BitsPerSecond)
+	}
+	deviation := "computing..."
+	if r.BitsPerSecond > 0 {
+		deviation = fmt.Sprintf("%.6f%%", r.GoldenRatioDeviation)
+	}
+
+	rows := [][2]string{
+		{"Algorithm", r.Algorithm},
+		{"Duration", format.FormatExecutionDuration(r.Duration)},
+		{"Bits", format.FormatNumberString(fmt.Sprintf("%d", r.Bits))},
+		{"Digits", format.FormatNumberString(fmt.Sprintf("%d", r.Digits))},
+		{"Throughput", throughput},
+		{"Golden ratio dev.", deviation},
+		{"FFT cache hit rate", cacheHitRate},
+		{"Peak heap", format.FormatBytes(r.PeakHeapBytes)},
+	}
+	if r.Spill != nil {
+		rows = append(rows, [2]string{"Spilled to disk",
+			fmt.Sprintf("%s in %d buffers", format.FormatBytes(uint64(r.Spill.Bytes)), r.Spill.Buffers)})
+	}
+	if r.ResultSHA256 != "" {
+		// The whole digest is in the saved report
+		rows = append(rows, [2]string{"SHA-256", r.ResultSHA256[:16] + "…"})
+	}
+	if r.PeakBitsPerSecond > 0 {
+		// After the overall throughput
+		rows = slices.Insert(rows, 5,
+			[2]string{"Peak throughput", metrics.FormatBitsPerSecond(r.PeakBitsPerSecond)},
+			[2]string{"Last-min throughput", metrics.FormatBitsPerSecond(r.RollingBitsPerSecond)},
+		)
+	}
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(fmt.Sprintf("Run summary: F(%d)", r.N)))
+	b.WriteString("\n")
+	for _, row := range rows {
+		b.WriteString("\n")
+		b.WriteString(metricLabelStyle.Render(padCell(row[0], 20)))
+		b.WriteString(metricValueStyle.Render(row[1]))
+	}
+	return b.String()
+}
+
+// overlay centers the summary over a body of the given size.
+func (s SummaryModel) overlay(width, height int) string {
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, s.View())
+}
@@ -0,0 +1,216 @@
+package tui
+
+import (
+	"errors"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/agbru/fibcalc/internal/clock"
+	"github.com/agbru/fibcalc/internal/config"
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/metrics"
+	"github.com/agbru/fibcalc/internal/orchestration"
+	"github.com/agbru/fibcalc/internal/testutil"
+)
+
+func testFinalResultMsg() FinalResultMsg {
+	return FinalResultMsg{
+		Result: orchestration.CalculationResult{
+			Name:     "Fast",
+			Result:   big.NewInt(55),
+			Duration: 50 * time.Millisecond,
+		},
+		N: 10,
+	}
+}
+
+func TestSummaryModel_ShowRequiresResult(t *testing.T) {
+	s := NewSummaryModel()
+	if s.Show(0) {
+		t.Error("expected Show to fail without a result")
+	}
+
+	s.SetResult(FinalResultMsg{N: 10}, config.AppConfig{})
+	if s.Available() {
+		t.Error("a result without a value should not make the summary available")
+	}
+
+	s.SetResult(testFinalResultMsg(), config.AppConfig{})
+	if !s.Show(1024) || !s.Visible() {
+		t.Fatal("expected the summary to be visible")
+	}
+	if s.Report().PeakHeapBytes != 1024 {
+		t.Errorf("expected peak heap 1024, got %d", s.Report().PeakHeapBytes)
+	}
+
+	s.Hide()
+	if s.Visible() {
+		t.Error("expected Hide to close the summary")
+	}
+}
+
+func TestSummaryModel_Clock(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	s := NewSummaryModelClock(clock.NewFake(at))
+	s.SetResult(testFinalResultMsg(), config.AppConfig{})
+	s.Reset()
+	s.SetResult(testFinalResultMsg(), config.AppConfig{})
+	if got := s.Report().CompletedAt; !got.Equal(at) {
+		t.Errorf("report completed at %v, want the fake clock's %v", got, at)
+	}
+}
+
+func TestSummaryModel_SetIndicators(t *testing.T) {
+	s := NewSummaryModel()
+	s.SetResult(testFinalResultMsg(), config.AppConfig{})
+
+	s.SetIndicators(&metrics.Indicators{BitsPerSecond: 1, Live: true})
+	if s.Report().BitsPerSecond != 0 {
+		t.Error("live indicators should be ignored")
+	}
+
+	s.SetIndicators(&metrics.Indicators{BitsPerSecond: 120, GoldenRatioDeviation: 0.5})
+	if r := s.Report(); r.BitsPerSecond != 120 || r.GoldenRatioDeviation != 0.5 {
+		t.Errorf("final indicators not recorded: %+v", r)
+	}
+}
+
+func TestSummaryModel_View(t *testing.T) {
+	s := NewSummaryModel()
+	s.SetResult(testFinalResultMsg(), config.AppConfig{})
+	s.Show(2048)
+
+	view := testutil.StripAnsiCodes(s.View())
+	for _, want := range []string{"Run summary: F(10)", "Fast", "Digits", "computing...", "n/a (cache unused)", "2.0 KB", "Save report"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in summary view:\n%s", want, view)
+		}
+	}
+
+	s.HandleSaved(SummarySavedMsg{What: "Report", Path: "out.json"})
+	if view := testutil.StripAnsiCodes(s.View()); !strings.Contains(view, "Report saved to out.json") {
+		t.Errorf("expected save status in view:\n%s", view)
+	}
+	s.HandleSaved(SummarySavedMsg{What: "Result", Err: errors.New("disk full")})
+	if view := testutil.StripAnsiCodes(s.View()); !strings.Contains(view, "Result not saved: disk full") {
+		t.Errorf("expected error status in view:\n%s", view)
+	}
+}
+
+func TestSummaryModel_Throughput(t *testing.T) {
+	s := NewSummaryModel()
+	s.SetResult(testFinalResultMsg(), config.AppConfig{})
+	s.Show(0)
+	if view := testutil.StripAnsiCodes(s.View()); strings.Contains(view, "Peak throughput") {
+		t.Error("expected no throughput history for an unmeasured run")
+	}
+
+	s.SetThroughput(1e6, 3e6)
+	if r := s.Report(); r.RollingBitsPerSecond != 1e6 || r.PeakBitsPerSecond != 3e6 {
+		t.Errorf("throughput not recorded: %+v", r)
+	}
+	view := testutil.StripAnsiCodes(s.View())
+	for _, want := range []string{"Peak throughput", "3.00 Mbit/s", "Last-min throughput", "1.00 Mbit/s"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in summary view:\n%s", want, view)
+		}
+	}
+}
+
+func TestModel_SummaryShownOnSuccess(t *testing.T) {
+	m := newTestModelWithSize(t, 120, 40)
+
+	updated, _ := m.Update(testFinalResultMsg())
+	m = updated.(Model)
+	updated, _ = m.Update(CalculationCompleteMsg{ExitCode: apperrors.ExitSuccess})
+	m = updated.(Model)
+
+	if !m.summary.Visible() {
+		t.Fatal("expected the summary to open on completion")
+	}
+	if !strings.Contains(testutil.StripAnsiCodes(m.View()), "Run summary") {
+		t.Error("expected the summary in the dashboard view")
+	}
+
+	// Dashboard keys are ignored while the summary is shown
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	m = updated.(Model)
+	if !m.logs.Following() {
+		t.Error("expected 'f' to be ignored while the summary is shown")
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.summary.Visible() {
+		t.Error("expected Esc to close the summary")
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	m = updated.(Model)
+	if !m.summary.Visible() {
+		t.Error("expected 's' to reopen the summary")
+	}
+}
+
+func TestModel_SummaryNotShownOnFailure(t *testing.T) {
+	m := newTestModelWithSize(t, 120, 40)
+
+	updated, _ := m.Update(testFinalResultMsg())
+	m = updated.(Model)
+	updated, _ = m.Update(CalculationCompleteMsg{ExitCode: apperrors.ExitErrorMismatch})
+	m = updated.(Model)
+
+	if m.summary.Visible() {
+		t.Error("expected no summary after a failed run")
+	}
+}
+
+func TestModel_SummarySaveAndExport(t *testing.T) {
+	t.Chdir(t.TempDir())
+	m := newTestModelWithSize(t, 120, 40)
+	updated, _ := m.Update(testFinalResultMsg())
+	m = updated.(Model)
+	updated, _ = m.Update(CalculationCompleteMsg{ExitCode: apperrors.ExitSuccess})
+	m = updated.(Model)
+
+	for _, r := range []rune{'w', 'e'} {
+		_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		if cmd == nil {
+			t.Fatalf("expected a command from %q", r)
+		}
+		saved, ok := cmd().(SummarySavedMsg)
+		if !ok {
+			t.Fatalf("expected SummarySavedMsg from %q", r)
+		}
+		if saved.Err != nil {
+			t.Fatalf("%s: unexpected error %v", saved.What, saved.Err)
+		}
+		if _, err := os.Stat(saved.Path); err != nil {
+			t.Errorf("%s: expected file %s: %v", saved.What, saved.Path, err)
+		}
+		updated, _ = m.Update(saved)
+		m = updated.(Model)
+	}
+	if !strings.Contains(testutil.StripAnsiCodes(m.summary.View()), "Result saved to") {
+		t.Error("expected the export status in the summary")
+	}
+}
+
+func TestModel_Restart_ClearsSummary(t *testing.T) {
+	m := newTestModelWithSize(t, 120, 40)
+	updated, _ := m.Update(testFinalResultMsg())
+	m = updated.(Model)
+	updated, _ = m.Update(CalculationCompleteMsg{ExitCode: apperrors.ExitSuccess})
+	m = updated.(Model)
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	m = updated.(Model)
+	if m.summary.Visible() || m.summary.Available() {
+		t.Error("expected restart to clear the summary")
+	}
+}
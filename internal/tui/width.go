@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/mattn/go-runewidth"
+)
+
+// widthCondition measures text in terminal cells the way the terminal
+// displays it: by grapheme cluster, so that combining marks take no cell
+// and wide (CJK) characters two, and with the characters of ambiguous
+// width (…, °, box drawing, block elements) taking two cells in East
+// Asian locales, as detected from LC_ALL, LC_CTYPE or LANG, or forced
+// with RUNEWIDTH_EASTASIAN=1. lipgloss counts those as one cell whatever
+// the locale, so the panel layout measures with cellWidth instead.
+var widthCondition = runewidth.NewCondition()
+
+// cellWidth returns the number of terminal cells of the widest line of s,
+// ignoring its ANSI escape sequences.
+//
+// Parameters:
+//   - s: The text, possibly styled and on several lines.
+//
+// Returns:
+//   - int: The width in cells.
+func cellWidth(s string) int {
+	width := 0
+	for line := range strings.SplitSeq(ansi.Strip(s), "\n") {
+		width = max(width, widthCondition.StringWidth(line))
+	}
+	return width
+}
+
+// padCell pads s with spaces on the right to w cells. Unlike the padding
+// of fmt ("%-12s"), which counts runes, it counts cells, so that columns
+// stay aligned with wide and combining characters.
+//
+// Parameters:
+//   - s: The text, possibly styled.
+//   - w: The width in cells.
+//
+// Returns:
+//   - string: s, padded if narrower than w.
+func padCell(s string, w int) string {
+	return s + spaces(w-cellWidth(s))
+}
+
+// padCellLeft pads s with spaces on the left to w cells, to right-align it.
+//
+// Parameters:
+//   - s: The text, possibly styled.
+//   - w: The width in cells.
+//
+// Returns:
+//   - string: s, padded if narrower than w.
+func padCellLeft(s string, w int) string {
+	return spaces(w-cellWidth(s)) + s
+}
+
+// fitLines truncates the lines of s wider than w cells, ending them with an
+// ellipsis, so that a panel of inner width w does not wrap them onto extra
+// lines and overflow its height. Styles are kept.
+//
+// Parameters:
+//   - s: The panel content.
+//   - w: The inner width of the panel, in cells.
+//
+// Returns:
+//   - string: The content, each line at most w cells wide.
+func fitLines(s string, w int) string {
+	if cellWidth(s) <= w {
+		return s
+	}
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = fitLine(line, w)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// fitLine truncates line to w cells. ansi.Truncate keeps the styles but
+// measures like lipgloss, so the excess is cut until the line fits.
+func fitLine(line string, w int) string {
+	if w <= 0 {
+		return ""
+	}
+	for excess := cellWidth(line) - w; excess > 0; excess = cellWidth(line) - w {
+		keep := ansi.StringWidth(line) - excess
+		if keep <= 0 {
+			return ""
+		}
+		line = ansi.Truncate(line, keep, "…")
+	}
+	return line
+}
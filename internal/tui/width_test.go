@@ -0,0 +1,125 @@
+package tui
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/mattn/go-runewidth"
+
+	"github.com/agbru/fibcalc/internal/orchestration"
+)
+
+func TestCellWidth(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"Matrix", 6},
+		{"行列計算", 8},                // CJK: two cells each
+		{"re\u0301sultat", 8},      // combining acute accent: no cell
+		{"1\u202f234\u202f567", 9}, // French narrow no-break space separators
+		{metricValueStyle.Render("計算"), 4},
+		{"ab\n行列計算\nc", 8},
+	}
+	for _, tt := range tests {
+		if got := cellWidth(tt.s); got != tt.want {
+			t.Errorf("cellWidth(%q) = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestPadCell(t *testing.T) {
+	t.Parallel()
+	if got := padCell("計算", 6); got != "計算  " {
+		t.Errorf("padCell = %q, want %q", got, "計算  ")
+	}
+	if got := padCellLeft("é", 3); got != "  é" {
+		t.Errorf("padCellLeft = %q, want %q", got, "  é")
+	}
+	if got := padCell("Matrix", 3); got != "Matrix" {
+		t.Errorf("padCell of a wider text = %q, want it unchanged", got)
+	}
+}
+
+func TestFitLines(t *testing.T) {
+	t.Parallel()
+	styled := logErrorStyle.Render("エラー: 計算がタイムアウトしました")
+	got := fitLines("short\n"+styled, 12)
+	lines := strings.Split(got, "\n")
+	if lines[0] != "short" {
+		t.Errorf("a fitting line was changed: %q", lines[0])
+	}
+	if w := cellWidth(lines[1]); w > 12 {
+		t.Errorf("truncated line is %d cells wide, want at most 12: %q", w, lines[1])
+	}
+	if !strings.HasSuffix(ansi.Strip(lines[1]), "…") {
+		t.Errorf("truncated line should end with an ellipsis: %q", ansi.Strip(lines[1]))
+	}
+	if got := fitLines(styled, 0); got != "" {
+		t.Errorf("fitLines to 0 cells = %q, want empty", got)
+	}
+}
+
+// TestCellWidthEastAsian swaps the width condition, so it does not run in
+// parallel.
+func TestCellWidthEastAsian(t *testing.T) {
+	saved := widthCondition
+	widthCondition = &runewidth.Condition{EastAsianWidth: true}
+	defer func() { widthCondition = saved }()
+
+	// The ellipsis is of ambiguous width: two cells in East Asian
+	// locales, where lipgloss still counts one
+	if got := cellWidth("Loading…"); got != 9 {
+		t.Errorf("cellWidth(Loading…) = %d, want 9", got)
+	}
+	if got := fitLine("▁▂▃▄▅▆", 5); cellWidth(got) > 5 {
+		t.Errorf("fitLine(▁▂▃▄▅▆, 5) = %q, %d cells wide", got, cellWidth(got))
+	}
+}
+
+// TestLogsResultsAlignWideCharacters checks that the comparison summary
+// aligns its columns in cells when names and durations are not ASCII.
+func TestLogsResultsAlignWideCharacters(t *testing.T) {
+	t.Parallel()
+	logs := NewLogsModel([]string{"行列", "Fast"})
+	logs.SetSize(60, 20)
+	logs.AddResults([]orchestration.CalculationResult{
+		{Name: "行列", Result: big.NewInt(55), Duration: 850 * time.Microsecond},
+		{Name: "Fast", Result: big.NewInt(55), Duration: 1500 * time.Millisecond},
+	})
+
+	entries := logs.entries[len(logs.entries)-2:]
+	var columns []int
+	for _, entry := range entries {
+		plain := ansi.Strip(entry)
+		columns = append(columns, cellWidth(plain[:strings.Index(plain, "OK")]))
+	}
+	if columns[0] != columns[1] {
+		t.Errorf("status columns at cells %v, want aligned:\n%s", columns, ansi.Strip(strings.Join(entries, "\n")))
+	}
+}
+
+// TestPanelsDoNotOverflowWideCharacters checks that panels keep their size
+// when their content holds CJK text wider than the panel.
+func TestPanelsDoNotOverflowWideCharacters(t *testing.T) {
+	t.Parallel()
+	logs := NewLogsModel([]string{"行列"})
+	logs.SetSize(30, 10)
+	logs.AddError(ErrorMsg{Err: errors.New("計算がタイムアウトしました、メモリが不足しています"), Duration: time.Second})
+	if view := logs.View(); lipgloss.Height(view) != 10 || cellWidth(view) > 30 {
+		t.Errorf("logs panel is %dx%d cells, want at most 30x10:\n%s", cellWidth(view), lipgloss.Height(view), view)
+	}
+
+	metrics := NewMetricsModel()
+	metrics.SetSize(30, 6)
+	metrics.numGoroutine = 12345678
+	if view := metrics.View(); lipgloss.Height(view) != 6 || cellWidth(view) > 30 {
+		t.Errorf("metrics panel is %dx%d cells, want at most 30x6:\n%s", cellWidth(view), lipgloss.Height(view), view)
+	}
+}
@@ -0,0 +1,165 @@
+package ui
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ConsoleSupport describes what the attached console can display.
+type ConsoleSupport struct {
+	// VT reports whether the console interprets ANSI escape sequences
+	// (colors, cursor movement).
+	VT bool
+	// Unicode reports whether non-ASCII characters such as block elements
+	// render correctly. Legacy Windows consoles with an OEM code page
+	// show them as garbage.
+	Unicode bool
+}
+
+var (
+	console      = ConsoleSupport{VT: true, Unicode: true}
+	consoleMutex sync.RWMutex
+)
+
+// InitConsole prepares the console for output and records its
+// capabilities. On Windows it enables virtual terminal processing, and
+// detects consoles that cannot show ANSI codes or non-ASCII characters.
+// Other platforms are assumed to support both.
+//
+// Parameters:
+//   - forceASCII: If true, output is restricted to ASCII even when the
+//     console supports Unicode.
+//
+// Returns:
+//   - ConsoleSupport: The capabilities now in effect.
+func InitConsole(forceASCII bool) ConsoleSupport {
+	cs := detectConsole()
+	if forceASCII {
+		cs.Unicode = false
+	}
+	SetConsole(cs)
+	return cs
+}
+
+// Console returns the capabilities recorded by InitConsole.
+func Console() ConsoleSupport {
+	consoleMutex.RLock()
+	defer consoleMutex.RUnlock()
+	return console
+}
+
+// SetConsole overrides the recorded console capabilities.
+// This is primarily used for testing purposes to restore state.
+func SetConsole(cs ConsoleSupport) {
+	consoleMutex.Lock()
+	defer consoleMutex.Unlock()
+	console = cs
+}
+
+// asciiReplacements maps the non-ASCII characters used in the output to
+// ASCII stand-ins.
+var asciiReplacements = map[rune]string{
+	'█': "#", '░': "-", '▓': "#", '▒': "=",
+	'▁': "_", '▂': ".", '▃': ".", '▄': ":", '▅': "=", '▆': "+", '▇': "*",
+	'─': "-", '━': "-", '│': "|", '┃': "|",
+	'╭': "+", '╮': "+", '╰': "+", '╯': "+",
+	'┌': "+", '┐': "+", '└': "+", '┘': "+", '├': "+", '┤': "+", '┬': "+", '┴': "+", '┼': "+",
+	'µ': "u", 'μ': "u", '–': "-", '—': "-", '…': "...", '·': ".", '•': "*",
+	'✓': "OK", '✗': "X", '→': "->", '←': "<-", '≈': "~", '×': "x", '≥': ">=", '≤': "<=",
+	'φ': "phi", '₂': "2", '₁': "1", '₀': "0", '²': "^2",
+}
+
+// SafeText returns s unchanged on a Unicode console. Otherwise, known
+// non-ASCII characters are replaced with ASCII stand-ins, braille cells
+// with '.', and anything else with '?'. ANSI escape sequences are ASCII
+// and pass through.
+func SafeText(s string) string {
+	if Console().Unicode {
+		return s
+	}
+	return toASCII(s)
+}
+
+// toASCII replaces every non-ASCII character of s.
+func toASCII(s string) string {
+	i := 0
+	for i < len(s) && s[i] < utf8.RuneSelf {
+		i++
+	}
+	if i == len(s) {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	b.WriteString(s[:i])
+	for _, r := range s[i:] {
+		switch {
+		case r < utf8.RuneSelf:
+			b.WriteRune(r)
+		case asciiReplacements[r] != "":
+			b.WriteString(asciiReplacements[r])
+		case r == 0x2800:
+			b.WriteByte(' ')
+		case r > 0x2800 && r <= 0x28FF:
+			b.WriteByte('.')
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// safeWriter transliterates the output to ASCII. A multi-byte character
+// split across writes is held back until it is complete.
+type safeWriter struct {
+	w       io.Writer
+	pending []byte
+}
+
+// NewSafeWriter returns w itself on a Unicode console, and otherwise a
+// writer that passes the output through SafeText.
+//
+// Parameters:
+//   - w: The destination writer.
+//
+// Returns:
+//   - io.Writer: A writer that is safe for the console's code page.
+func NewSafeWriter(w io.Writer) io.Writer {
+	if Console().Unicode {
+		return w
+	}
+	return &safeWriter{w: w}
+}
+
+// Write transliterates p and writes it to the underlying writer. It
+// reports len(p) on success, as the written byte count differs from p's.
+func (s *safeWriter) Write(p []byte) (int, error) {
+	data := append(s.pending, p...)
+	cut := len(data)
+	// Hold back an incomplete trailing character.
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				cut = i
+			}
+			break
+		}
+	}
+	s.pending = append([]byte(nil), data[cut:]...)
+	if _, err := io.WriteString(s.w, toASCII(string(data[:cut]))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Fd returns the file descriptor of the underlying writer so that terminal
+// detection still works through the wrapper. It returns an invalid
+// descriptor if the writer is not a file.
+func (s *safeWriter) Fd() uintptr {
+	if f, ok := s.w.(interface{ Fd() uintptr }); ok {
+		return f.Fd()
+	}
+	return ^uintptr(0)
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+
+package ui
+
+// detectConsole assumes that terminals on this platform interpret ANSI
+// escape sequences and display UTF-8.
+func detectConsole() ConsoleSupport {
+	return ConsoleSupport{VT: true, Unicode: true}
+}
@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestInitConsoleForceASCII verifies that forcing ASCII disables Unicode.
+func TestInitConsoleForceASCII(t *testing.T) {
+	original := Console()
+	defer SetConsole(original)
+
+	if cs := InitConsole(true); cs.Unicode {
+		t.Error("InitConsole(true) should disable Unicode")
+	}
+	if Console().Unicode {
+		t.Error("Console() should report the forced ASCII mode")
+	}
+}
+
+// TestSafeText verifies transliteration on consoles without Unicode.
+func TestSafeText(t *testing.T) {
+	original := Console()
+	defer SetConsole(original)
+
+	SetConsole(ConsoleSupport{VT: true, Unicode: true})
+	if got := SafeText("██░ 3µs"); got != "██░ 3µs" {
+		t.Errorf("SafeText on a Unicode console = %q, want input unchanged", got)
+	}
+
+	SetConsole(ConsoleSupport{VT: true, Unicode: false})
+	tests := []struct {
+		in, want string
+	}{
+		{"plain text", "plain text"},
+		{"[██░░] 50%", "[##--] 50%"},
+		{"< 1µs", "< 1us"},
+		{"3m–5m", "3m-5m"},
+		{"╭──╮\n│ok│\n╰──╯", "+--+\n|ok|\n+--+"},
+		{"\x1b[1m✓ saved\x1b[0m", "\x1b[1mOK saved\x1b[0m"},
+		{"⠀⣿", " ."},
+		{"日本", "??"},
+	}
+	for _, tt := range tests {
+		if got := SafeText(tt.in); got != tt.want {
+			t.Errorf("SafeText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// TestNewSafeWriter verifies that the writer transliterates its output and
+// holds back characters split across writes.
+func TestNewSafeWriter(t *testing.T) {
+	original := Console()
+	defer SetConsole(original)
+
+	var buf bytes.Buffer
+	SetConsole(ConsoleSupport{VT: true, Unicode: true})
+	if w := NewSafeWriter(&buf); w != &buf {
+		t.Error("NewSafeWriter should return the writer itself on a Unicode console")
+	}
+
+	SetConsole(ConsoleSupport{VT: true, Unicode: false})
+	w := NewSafeWriter(&buf)
+	block := []byte("a█b")
+	// Split the 3-byte block character across two writes.
+	for _, part := range [][]byte{block[:2], block[2:]} {
+		n, err := w.Write(part)
+		if err != nil || n != len(part) {
+			t.Fatalf("Write(%q) = %d, %v; want %d, nil", part, n, err, len(part))
+		}
+	}
+	if got := buf.String(); got != "a#b" {
+		t.Errorf("output = %q, want %q", got, "a#b")
+	}
+}
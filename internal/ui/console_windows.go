@@ -0,0 +1,35 @@
+//go:build windows
+
+package ui
+
+import "golang.org/x/sys/windows"
+
+// utf8CodePage is the Windows code page identifier of UTF-8.
+const utf8CodePage = 65001
+
+// detectConsole enables virtual terminal processing on the standard output
+// console and checks whether it can display Unicode. Output that is redirected to a
+// file or a pipe is assumed to be read by a capable program.
+func detectConsole() ConsoleSupport {
+	h, err := windows.GetStdHandle(windows.STD_OUTPUT_HANDLE)
+	if err != nil {
+		return ConsoleSupport{VT: true, Unicode: true}
+	}
+	var mode uint32
+	if err := windows.GetConsoleMode(h, &mode); err != nil {
+		// Not a console.
+		return ConsoleSupport{VT: true, Unicode: true}
+	}
+
+	cs := ConsoleSupport{VT: true}
+	if mode&windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING == 0 {
+		// Legacy consoles (before Windows 10 1511) reject the flag.
+		cs.VT = windows.SetConsoleMode(h, mode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING) == nil
+	}
+	// Consoles with VT support use fonts that cover block elements. On
+	// legacy consoles, only a UTF-8 code page signals that the user has set
+	// the console up for Unicode.
+	cp, err := windows.GetConsoleOutputCP()
+	cs.Unicode = cs.VT || (err == nil && cp == utf8CodePage)
+	return cs
+}
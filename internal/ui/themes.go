@@ -146,10 +146,16 @@ var (
 // GetCurrentTUITheme returns the TUI theme matching the currently active theme.
 // When NoColorTheme is active, returns NoColorTUITheme; otherwise DarkTUITheme.
 func GetCurrentTUITheme() TUITheme {
-	themeMutex.RLock()
-	defer themeMutex.RUnlock()
+	return GetCurrentTheme().TUI()
+}
 
-	if currentTheme.Name == "none" {
+// TUI returns the TUI palette matching t: NoColorTUITheme for the "none"
+// theme, DarkTUITheme otherwise.
+//
+// Returns:
+//   - TUITheme: The lipgloss colors for t.
+func (t Theme) TUI() TUITheme {
+	if t.Name == "none" {
 		return NoColorTUITheme
 	}
 	return DarkTUITheme
@@ -201,22 +207,30 @@ func SetTheme(name string) {
 // Parameters:
 //   - noColor: If true, disables all color output regardless of environment.
 func InitTheme(noColor bool) {
-	themeMutex.Lock()
-	defer themeMutex.Unlock()
+	SetCurrentTheme(ResolveTheme(noColor))
+}
 
+// ResolveTheme returns the theme InitTheme would activate, without changing
+// the current theme, so that callers can hold their own copy.
+//
+// Parameters:
+//   - noColor: If true, disables all color output regardless of environment.
+//
+// Returns:
+//   - Theme: NoColorTheme if noColor is true or NO_COLOR is set, DarkTheme
+//     otherwise.
+func ResolveTheme(noColor bool) Theme {
 	// Check --no-color flag first
 	if noColor {
-		currentTheme = NoColorTheme
-		return
+		return NoColorTheme
 	}
 
 	// Check NO_COLOR environment variable
 	// Any non-empty value disables colors (per no-color.org spec)
 	if _, exists := os.LookupEnv("NO_COLOR"); exists {
-		currentTheme = NoColorTheme
-		return
+		return NoColorTheme
 	}
 
 	// Default to dark theme
-	currentTheme = DarkTheme
+	return DarkTheme
 }
@@ -230,3 +230,39 @@ func TestColorFunctions(t *testing.T) {
 		}
 	})
 }
+
+// TestResolveTheme verifies that ResolveTheme picks the theme without
+// changing the current one.
+func TestResolveTheme(t *testing.T) {
+	originalTheme := GetCurrentTheme()
+	defer func() { SetCurrentTheme(originalTheme) }()
+	t.Setenv("NO_COLOR", "")
+	os.Unsetenv("NO_COLOR")
+
+	SetCurrentTheme(LightTheme)
+	if got := ResolveTheme(true); got.Name != "none" {
+		t.Errorf("ResolveTheme(true): got theme %q, want %q", got.Name, "none")
+	}
+	if got := ResolveTheme(false); got.Name != "dark" {
+		t.Errorf("ResolveTheme(false): got theme %q, want %q", got.Name, "dark")
+	}
+	if current := GetCurrentTheme(); current.Name != "light" {
+		t.Errorf("ResolveTheme changed the current theme to %q", current.Name)
+	}
+
+	os.Setenv("NO_COLOR", "1")
+	if got := ResolveTheme(false); got.Name != "none" {
+		t.Errorf("ResolveTheme(false) with NO_COLOR: got theme %q, want %q", got.Name, "none")
+	}
+}
+
+// TestThemeTUI verifies the TUI palette derived from a theme.
+func TestThemeTUI(t *testing.T) {
+	t.Parallel()
+	if got := NoColorTheme.TUI(); got != NoColorTUITheme {
+		t.Error("NoColorTheme.TUI() should be NoColorTUITheme")
+	}
+	if got := DarkTheme.TUI(); got != DarkTUITheme {
+		t.Error("DarkTheme.TUI() should be DarkTUITheme")
+	}
+}
@@ -10,6 +10,13 @@ import (
 	"testing"
 )
 
+// TestMain keeps the binary under test from recording its runs in the
+// user's history database.
+func TestMain(m *testing.M) {
+	os.Setenv("FIBCALC_HISTORY", "false")
+	os.Exit(m.Run())
+}
+
 // buildOnce ensures the binary is built only once across all tests.
 var buildOnce sync.Once
 
@@ -114,6 +121,18 @@ func TestCLI_E2E(t *testing.T) {
 			wantOut:  "", // may produce error output on stderr
 			wantCode: 2, // non-zero exit code expected (timeout error)
 		},
+		{
+			name:     "Injected Timeout",
+			args:     []string{"-n", "1000", "--fail-mode", "timeout", "--fail-after", "10ms"},
+			wantOut:  "",
+			wantCode: 2,
+		},
+		{
+			name:     "Injected Mismatch",
+			args:     []string{"-n", "1000", "--algo", "all", "--fail-mode", "mismatch"},
+			wantOut:  "",
+			wantCode: 3,
+		},
 		{
 			name:     "Invalid N Zero",
 			args:     []string{"-n", "0", "-c"},